@@ -113,6 +113,7 @@ func main() {
 		version,
 		server.WithToolCapabilities(true),
 		server.WithResourceCapabilities(true, true),
+		server.WithPromptCapabilities(true),
 	)
 
 	for _, tool := range tools.Tools {
@@ -151,6 +152,43 @@ func main() {
 		})
 	}
 
+	templates, err := mcpClient.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+	if err != nil {
+		log.Fatalf("failed to list resource templates: %v", err)
+	}
+
+	for _, template := range templates.ResourceTemplates {
+		rt := template
+		stdioServer.AddResourceTemplate(rt, func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			result, readErr := mcpClient.ReadResource(ctx, mcp.ReadResourceRequest{
+				Params: mcp.ReadResourceParams{
+					URI: req.Params.URI,
+				},
+			})
+			if readErr != nil {
+				return nil, readErr
+			}
+			return result.Contents, nil
+		})
+	}
+
+	prompts, err := mcpClient.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		log.Fatalf("failed to list prompts: %v", err)
+	}
+
+	for _, prompt := range prompts.Prompts {
+		p := prompt
+		stdioServer.AddPrompt(p, func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcpClient.GetPrompt(ctx, mcp.GetPromptRequest{
+				Params: mcp.GetPromptParams{
+					Name:      req.Params.Name,
+					Arguments: req.Params.Arguments,
+				},
+			})
+		})
+	}
+
 	if err = server.ServeStdio(stdioServer); err != nil {
 		log.Fatalf("stdio server error: %v", err)
 	}
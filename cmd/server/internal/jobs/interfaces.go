@@ -1,6 +1,9 @@
 package jobs
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 //go:generate mockgen -destination interfaces_mocks_test.go -package jobs_test -source=interfaces.go
 
@@ -16,3 +19,17 @@ type ExchangeRatesUpdateSvc interface {
 		remoteURL string,
 	) error
 }
+
+type LoanSvc interface {
+	AccrueInterest(
+		ctx context.Context,
+		now time.Time,
+	) error
+}
+
+type InvestmentSvc interface {
+	SyncPrices(
+		ctx context.Context,
+		date time.Time,
+	) error
+}
@@ -1,58 +1,213 @@
 package jobs
 
 import (
+	"context"
+	"os"
+	"slices"
+	"time"
+
 	"github.com/cockroachdb/errors"
 	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/timezone"
 	"github.com/go-co-op/gocron/v2"
+	"github.com/samber/lo"
+)
+
+const (
+	JobUpdateExchangeRates = "update_exchange_rates"
+	JobFixDailyGap         = "fix_daily_gap"
+	JobAccrueLoanInterest  = "accrue_loan_interest"
+	JobSyncSecurityPrices  = "sync_security_prices"
 )
 
 type Config struct {
 	Configuration          configuration.Configuration
 	ExchangeRatesUpdateSvc ExchangeRatesUpdateSvc
 	MaintenanceSvc         MaintenanceSvc
+	LoanSvc                LoanSvc
+	InvestmentSvc          InvestmentSvc
+	Elector                gocron.Elector // when set, scheduled runs happen only on the elected replica
 	Opts                   []gocron.SchedulerOption
 }
 
+type registeredJob struct {
+	name     string
+	schedule string
+	run      func(ctx context.Context) error
+	job      gocron.Job
+}
+
 type JobScheduler struct {
 	scheduler gocron.Scheduler
 	cfg       *Config
+	jobs      []*registeredJob
+	instance  string
+	location  *time.Location
 }
 
 func NewJobScheduler(cfg *Config) (*JobScheduler, error) {
-	scheduler, err := gocron.NewScheduler(cfg.Opts...)
+	location, err := timezone.Load(cfg.Configuration.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := append(slices.Clone(cfg.Opts), gocron.WithLocation(location)) // schedules follow the household day
+	if cfg.Elector != nil {
+		opts = append(opts, gocron.WithDistributedElector(cfg.Elector))
+	}
+
+	scheduler, err := gocron.NewScheduler(opts...)
 	if err != nil {
 		return nil, err
 	}
 
+	instance, _ := os.Hostname()
+
 	j := &JobScheduler{
 		scheduler: scheduler,
 		cfg:       cfg,
+		instance:  instance,
+		location:  location,
 	}
 
-	if _, err = scheduler.NewJob(
-		gocron.CronJob("10 12 * * *", false), // should be in sync with sync-exchange-rates service
-		gocron.NewTask(j.UpdateCurrencyRates),
-	); err != nil {
-		return nil, errors.Wrap(err, "failed to create currency rate updater job")
-	}
+	jobsCfg := cfg.Configuration.Jobs
+
+	for _, job := range []*registeredJob{
+		{
+			name:     JobUpdateExchangeRates,
+			schedule: lo.CoalesceOrEmpty(jobsCfg.UpdateExchangeRatesCron, "10 12 * * *"), // should be in sync with sync-exchange-rates service
+			run:      j.UpdateCurrencyRates,
+		},
+		{
+			name:     JobFixDailyGap,
+			schedule: lo.CoalesceOrEmpty(jobsCfg.FixDailyGapCron, "1 0 * * *"), // run on day start, so it will generate daily stats for current day
+			run:      j.FixDailyGap,
+		},
+		{
+			name:     JobAccrueLoanInterest,
+			schedule: lo.CoalesceOrEmpty(jobsCfg.AccrueLoanInterestCron, "5 0 * * *"), // after daily gap fix, posts interest for payment dates reached
+			run:      j.AccrueLoanInterest,
+		},
+		{
+			name:     JobSyncSecurityPrices,
+			schedule: lo.CoalesceOrEmpty(jobsCfg.SyncSecurityPricesCron, "10 0 * * *"), // after daily gap fix, values holdings for the new day
+			run:      j.SyncSecurityPrices,
+		},
+	} {
+		jobRef := job
 
-	if _, err = scheduler.NewJob(
-		gocron.CronJob("1 0 * * *", false), // run on day start, so it will generate daily stats for current day
-		gocron.NewTask(j.FixDailyGap),
-	); err != nil {
-		return nil, errors.Wrap(err, "failed to create currency rate updater job")
+		jobRef.job, err = scheduler.NewJob(
+			gocron.CronJob(jobRef.schedule, false),
+			gocron.NewTask(func(ctx context.Context) error {
+				_, runErr := j.execute(ctx, jobRef, database.ScheduleRunTriggerCron)
+
+				return runErr
+			}),
+			gocron.WithName(jobRef.name),
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s job", jobRef.name)
+		}
+
+		j.jobs = append(j.jobs, jobRef)
 	}
 
 	return j, nil
 }
 
+// ListJobs returns the registered jobs with their schedule, next fire time and latest recorded run.
+func (j *JobScheduler) ListJobs(ctx context.Context) ([]*database.JobStatus, error) {
+	var runs []*database.JobRun
+
+	if err := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeMaster)).
+		Raw(`select distinct on (job) * from job_runs where job in ? order by job, id desc`,
+			lo.Map(j.jobs, func(job *registeredJob, _ int) string {
+				return job.name
+			})).
+		Scan(&runs).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get job runs")
+	}
+
+	lastRuns := lo.KeyBy(runs, func(run *database.JobRun) string {
+		return run.Job
+	})
+
+	var statuses []*database.JobStatus
+	for _, job := range j.jobs {
+		status := &database.JobStatus{
+			Name:     job.name,
+			Schedule: job.schedule,
+			LastRun:  lastRuns[job.name],
+		}
+
+		if nextRun, err := job.job.NextRun(); err == nil && !nextRun.IsZero() {
+			status.NextRunAt = lo.ToPtr(nextRun.UTC())
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// RunJob executes a job on demand on the current replica, regardless of leader election.
+// The run is returned together with the job error, if any.
+func (j *JobScheduler) RunJob(ctx context.Context, name string) (*database.JobRun, error) {
+	job, ok := lo.Find(j.jobs, func(job *registeredJob) bool {
+		return job.name == name
+	})
+	if !ok {
+		return nil, errors.Newf("unknown job: %s", name)
+	}
+
+	return j.execute(ctx, job, database.ScheduleRunTriggerManual)
+}
+
+// execute runs the job and records the outcome in job_runs.
+func (j *JobScheduler) execute(
+	ctx context.Context,
+	job *registeredJob,
+	trigger database.ScheduleRunTrigger,
+) (*database.JobRun, error) {
+	db := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeMaster))
+
+	run := &database.JobRun{
+		Job:       job.name,
+		Trigger:   trigger,
+		Status:    database.ScheduleRunStatusRunning,
+		Instance:  j.instance,
+		StartedAt: time.Now().UTC(),
+	}
+
+	if err := db.Create(run).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to create job run")
+	}
+
+	runErr := job.run(ctx)
+
+	run.FinishedAt = lo.ToPtr(time.Now().UTC())
+	run.Status = database.ScheduleRunStatusSuccess
+
+	if runErr != nil {
+		run.Status = database.ScheduleRunStatusFailed
+		run.Error = lo.ToPtr(runErr.Error())
+	}
+
+	if err := db.Save(run).Error; err != nil {
+		return run, errors.CombineErrors(runErr, errors.Wrap(err, "failed to update job run"))
+	}
+
+	return run, runErr
+}
+
 func (j *JobScheduler) GetScheduler() gocron.Scheduler {
 	return j.scheduler
 }
 
 func (j *JobScheduler) StartAsync() error {
 	j.scheduler.Start()
-	
+
 	return nil
 }
 
@@ -1,13 +1,29 @@
 package jobs_test
 
 import (
+	"context"
+	"errors"
+	"testing"
+
 	"github.com/ft-t/go-money/cmd/server/internal/jobs"
 	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
 	"github.com/go-co-op/gocron/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
+type staticElector struct {
+	err error
+}
+
+func (e staticElector) IsLeader(context.Context) error {
+	return e.err
+}
+
 func TestJobScheduler(t *testing.T) {
 	t.Run("succces", func(t *testing.T) {
 		jobScheduler, err := jobs.NewJobScheduler(&jobs.Config{
@@ -33,6 +49,127 @@ func TestJobScheduler(t *testing.T) {
 		assert.ErrorContains(t, err, "locker must not be nil")
 		assert.Nil(t, jobScheduler)
 	})
+
+	t.Run("schedules from configuration", func(t *testing.T) {
+		jobScheduler, err := jobs.NewJobScheduler(&jobs.Config{
+			Configuration: configuration.Configuration{
+				Jobs: configuration.JobsConfig{
+					FixDailyGapCron: "0 3 * * *",
+				},
+			},
+			Elector: staticElector{err: errors.New("not a leader")},
+		})
+		require.NoError(t, err)
+
+		names := lo.Map(jobScheduler.GetScheduler().Jobs(), func(job gocron.Job, _ int) string {
+			return job.Name()
+		})
+		assert.ElementsMatch(t, []string{
+			jobs.JobUpdateExchangeRates,
+			jobs.JobFixDailyGap,
+			jobs.JobAccrueLoanInterest,
+			jobs.JobSyncSecurityPrices,
+		}, names)
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		jobScheduler, err := jobs.NewJobScheduler(&jobs.Config{
+			Configuration: configuration.Configuration{
+				Jobs: configuration.JobsConfig{
+					AccrueLoanInterestCron: "*/x * * * *",
+				},
+			},
+		})
+
+		assert.ErrorContains(t, err, "failed to create accrue_loan_interest job")
+		assert.Nil(t, jobScheduler)
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		jobScheduler, err := jobs.NewJobScheduler(&jobs.Config{
+			Configuration: configuration.Configuration{
+				Timezone: "Mars/Olympus",
+			},
+		})
+
+		assert.ErrorContains(t, err, "invalid timezone: Mars/Olympus")
+		assert.Nil(t, jobScheduler)
+	})
+}
+
+func TestJobScheduler_RunJob(t *testing.T) {
+	cfg := configuration.GetConfiguration()
+
+	t.Run("success", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		maintenanceSvc := NewMockMaintenanceSvc(gomock.NewController(t))
+
+		jobScheduler, err := jobs.NewJobScheduler(&jobs.Config{
+			MaintenanceSvc: maintenanceSvc,
+		})
+		require.NoError(t, err)
+
+		maintenanceSvc.EXPECT().FixDailyGaps(gomock.Any()).Return(nil)
+
+		run, err := jobScheduler.RunJob(context.TODO(), jobs.JobFixDailyGap)
+		assert.NoError(t, err)
+		assert.NotZero(t, run.ID)
+		assert.Equal(t, database.ScheduleRunStatusSuccess, run.Status)
+		assert.Equal(t, database.ScheduleRunTriggerManual, run.Trigger)
+		assert.NotNil(t, run.FinishedAt)
+		assert.Nil(t, run.Error)
+
+		statuses, err := jobScheduler.ListJobs(context.TODO())
+		assert.NoError(t, err)
+		assert.Len(t, statuses, 4)
+
+		for _, status := range statuses {
+			assert.NotEmpty(t, status.Schedule)
+
+			if status.Name != jobs.JobFixDailyGap {
+				assert.Nil(t, status.LastRun)
+				continue
+			}
+
+			assert.Equal(t, "1 0 * * *", status.Schedule)
+			require.NotNil(t, status.LastRun)
+			assert.Equal(t, run.ID, status.LastRun.ID)
+		}
+	})
+
+	t.Run("job failure is recorded", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		loanSvc := NewMockLoanSvc(gomock.NewController(t))
+
+		jobScheduler, err := jobs.NewJobScheduler(&jobs.Config{
+			LoanSvc: loanSvc,
+		})
+		require.NoError(t, err)
+
+		loanSvc.EXPECT().AccrueInterest(gomock.Any(), gomock.Any()).Return(errors.New("accrual failed"))
+
+		run, err := jobScheduler.RunJob(context.TODO(), jobs.JobAccrueLoanInterest)
+		assert.ErrorContains(t, err, "accrual failed")
+		require.NotNil(t, run)
+		assert.Equal(t, database.ScheduleRunStatusFailed, run.Status)
+		assert.Equal(t, "accrual failed", *run.Error)
+
+		var stored database.JobRun
+		assert.NoError(t, database.GetDb(database.DbTypeMaster).First(&stored, run.ID).Error)
+		assert.Equal(t, database.ScheduleRunStatusFailed, stored.Status)
+		assert.Equal(t, jobs.JobAccrueLoanInterest, stored.Job)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		jobScheduler, err := jobs.NewJobScheduler(&jobs.Config{})
+		require.NoError(t, err)
+
+		run, err := jobScheduler.RunJob(context.TODO(), "missing")
+		assert.ErrorContains(t, err, "unknown job: missing")
+		assert.Nil(t, run)
+	})
 }
 
 //func TestJobScheduler_CurrencyRateUpdater(t *testing.T) {
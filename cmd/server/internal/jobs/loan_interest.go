@@ -0,0 +1,15 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func (j *JobScheduler) AccrueLoanInterest(ctx context.Context) error {
+	ctx = zerolog.Ctx(ctx).With().Str("job", "accrue_loan_interest").Logger().WithContext(ctx)
+	zerolog.Ctx(ctx).Info().Msg("Starting loan interest accrual job")
+
+	return j.cfg.LoanSvc.AccrueInterest(ctx, time.Now().In(j.location))
+}
@@ -0,0 +1,25 @@
+package jobs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ft-t/go-money/cmd/server/internal/jobs"
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccrueLoanInterest(t *testing.T) {
+	loanSvc := NewMockLoanSvc(gomock.NewController(t))
+
+	scheduler, err := jobs.NewJobScheduler(&jobs.Config{
+		LoanSvc:       loanSvc,
+		Configuration: configuration.Configuration{},
+	})
+	assert.NoError(t, err)
+
+	loanSvc.EXPECT().AccrueInterest(gomock.Any(), gomock.Any()).Return(nil)
+
+	assert.NoError(t, scheduler.AccrueLoanInterest(context.TODO()))
+}
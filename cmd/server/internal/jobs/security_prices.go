@@ -0,0 +1,15 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func (j *JobScheduler) SyncSecurityPrices(ctx context.Context) error {
+	ctx = zerolog.Ctx(ctx).With().Str("job", "sync_security_prices").Logger().WithContext(ctx)
+	zerolog.Ctx(ctx).Info().Msg("Starting security prices sync job")
+
+	return j.cfg.InvestmentSvc.SyncPrices(ctx, time.Now().In(j.location)) // local date of the household
+}
@@ -0,0 +1,25 @@
+package jobs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ft-t/go-money/cmd/server/internal/jobs"
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncSecurityPrices(t *testing.T) {
+	investmentSvc := NewMockInvestmentSvc(gomock.NewController(t))
+
+	scheduler, err := jobs.NewJobScheduler(&jobs.Config{
+		InvestmentSvc: investmentSvc,
+		Configuration: configuration.Configuration{},
+	})
+	assert.NoError(t, err)
+
+	investmentSvc.EXPECT().SyncPrices(gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+	assert.ErrorIs(t, scheduler.SyncSecurityPrices(context.TODO()), assert.AnError)
+}
@@ -22,10 +22,14 @@ import (
 	"github.com/ft-t/go-money/pkg/currency"
 	"github.com/ft-t/go-money/pkg/database"
 	"github.com/ft-t/go-money/pkg/importers"
+	"github.com/ft-t/go-money/pkg/investments"
 	"github.com/ft-t/go-money/pkg/maintenance"
 	"github.com/ft-t/go-money/pkg/mappers"
 	gomoneyMcp "github.com/ft-t/go-money/pkg/mcp"
+	mcpaudit "github.com/ft-t/go-money/pkg/mcp/audit"
+	"github.com/ft-t/go-money/pkg/suggestions"
 	"github.com/ft-t/go-money/pkg/tags"
+	"github.com/ft-t/go-money/pkg/timezone"
 	"github.com/ft-t/go-money/pkg/transactions"
 	"github.com/ft-t/go-money/pkg/transactions/applicable_accounts"
 	"github.com/ft-t/go-money/pkg/transactions/double_entry"
@@ -33,6 +37,8 @@ import (
 	"github.com/ft-t/go-money/pkg/transactions/rules"
 	"github.com/ft-t/go-money/pkg/transactions/validation"
 	"github.com/ft-t/go-money/pkg/users"
+	"github.com/ft-t/go-money/pkg/webhooks"
+	"github.com/go-co-op/gocron/v2"
 	"github.com/rs/zerolog/log"
 )
 
@@ -41,7 +47,7 @@ func main() {
 	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
 
 	config := configuration.GetConfiguration()
-	_, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
 
 	boilerplate.SetupZeroLog()
 
@@ -146,11 +152,25 @@ func main() {
 		log.Logger.Fatal().Err(err).Msg("failed to create accounts handler")
 	}
 
+	suggestionSvc := suggestions.NewService(&suggestions.ServiceConfig{
+		Lookback:   config.Suggestions.Lookback,
+		MaxSamples: config.Suggestions.MaxSamples,
+		CacheTTL:   config.Suggestions.CacheTTL,
+	})
+	tagSvc := tags.NewService(mapper, suggestionSvc)
+	categoriesSvc := categories.NewService(mapper, suggestionSvc)
+
 	baseAmountSvc := transactions.NewBaseAmountService(config.CurrencyConfig.BaseCurrency)
+	ruleModulesSvc := rules.NewModuleService()
+
 	ruleInterpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
 		AccountsSvc:          accountSvc,
 		CurrencyConverterSvc: currencyConverter,
 		DecimalSvc:           decimalSvc,
+		TagsSvc:              tagSvc,
+		CategoriesSvc:        categoriesSvc,
+		ModulesSvc:           ruleModulesSvc,
+		SuggestionsSvc:       suggestionSvc,
 	})
 
 	ruleEngine := rules.NewExecutor(ruleInterpreter)
@@ -163,8 +183,29 @@ func main() {
 		BaseCurrency: config.CurrencyConfig.BaseCurrency,
 	})
 
-	statsSvc := transactions.NewStatService()
+	location, err := timezone.Load(config.Timezone)
+	if err != nil {
+		log.Logger.Fatal().Err(err).Msg("failed to load household timezone")
+	}
+
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{
+		Location: location,
+	})
 	historySvc := history.NewService()
+	eventFeed := history.NewFeed(&history.FeedConfig{
+		PollInterval:    config.Events.PollInterval,
+		SequenceLockKey: config.Events.SequenceLockKey,
+	})
+	webhookSvc := webhooks.NewService(&webhooks.ServiceConfig{
+		FeedSvc:        eventFeed,
+		HTTPClient:     http.DefaultClient,
+		BatchSize:      config.Webhooks.BatchSize,
+		MaxAttempts:    config.Webhooks.MaxAttempts,
+		BackoffBase:    config.Webhooks.BackoffBase,
+		BackoffMax:     config.Webhooks.BackoffMax,
+		RequestTimeout: config.Webhooks.RequestTimeout,
+		PollInterval:   config.Webhooks.PollInterval,
+	})
 	transactionSvc := transactions.NewService(&transactions.ServiceConfig{
 		StatsSvc:             statsSvc,
 		MapperSvc:            mapper,
@@ -175,22 +216,73 @@ func main() {
 		DoubleEntry:          doubleEntry,
 		AccountSvc:           accountSvc,
 		HistorySvc:           historySvc,
+		SuggestionsSvc:       suggestionSvc,
+		Location:             location,
+	})
+
+	loanSvc := accounts.NewLoanService(&accounts.LoanServiceConfig{
+		AccountSvc:           accountSvc,
+		TransactionSvc:       transactionSvc,
+		DecimalSvc:           decimalSvc,
+		CurrencyConverterSvc: currencyConverter,
+	})
+
+	priceProvider, err := investments.NewPriceProvider(http.DefaultClient, config.Investments)
+	if err != nil {
+		log.Logger.Fatal().Err(err).Msg("failed to create price provider")
+	}
+
+	investmentSvc := investments.NewService(&investments.ServiceConfig{
+		AccountSvc:           accountSvc,
+		TransactionSvc:       transactionSvc,
+		DecimalSvc:           decimalSvc,
+		CurrencyConverterSvc: currencyConverter,
+		PriceProvider:        priceProvider,
+	})
+
+	analyticsSvc := analytics.NewService(&analytics.ServiceConfig{
+		DecimalSvc:   decimalSvc,
+		BaseCurrency: config.CurrencyConfig.BaseCurrency,
+		Location:     location,
 	})
 
+	var elector gocron.Elector // nil runs jobs and schedule rules on every replica
+	if config.Jobs.LeaderElection {
+		leaderElector := database.NewLeaderElector(database.GetDb(database.DbTypeMaster), config.Jobs.LeaderLockKey)
+		defer func() {
+			_ = leaderElector.Close()
+		}()
+
+		elector = leaderElector
+	}
+
+	isLeader := func() bool {
+		return elector == nil || elector.IsLeader(context.TODO()) == nil
+	}
+
 	ruleScheduler := rules.NewScheduler(&rules.SchedulerConfig{
 		RuleInterpreter: ruleInterpreter,
 		TransactionSvc:  transactionSvc,
+		CatchUpPolicy:   rules.CatchUpPolicy(config.Scheduler.CatchUpPolicy),
+		MaxCatchUpRuns:  config.Scheduler.MaxCatchUpRuns,
+		Elector:         elector,
+		Location:        location,
+		ReloadInterval:  config.Scheduler.ReloadInterval,
 	})
 
+	if err = ruleScheduler.CatchUp(context.TODO()); err != nil {
+		log.Logger.Fatal().Err(err).Msg("failed to catch up schedule rules")
+	}
+
 	if err = ruleScheduler.Reinit(context.TODO()); err != nil {
 		log.Logger.Fatal().Err(err).Msg("failed to reinitialize rule scheduler")
 	}
 
-	rulesSvc := rules.NewService(mapper)
+	rulesSvc := rules.NewService(mapper, rules.NewTestCaseRunner(&rules.TestCaseRunnerConfig{
+		Executor:       ruleEngine,
+		TransactionSvc: transactionSvc,
+	}))
 	rulesScheduleSvc := rules.NewScheduleService(mapper, ruleScheduler)
-	tagSvc := tags.NewService(mapper)
-	categoriesSvc := categories.NewService(mapper)
-
 	maintenanceSvc := maintenance.NewService(&maintenance.Config{
 		StatsSvc: statsSvc,
 	})
@@ -208,6 +300,42 @@ func main() {
 		AccountSvc:     accountSvc,
 	})
 
+	exchangeRateUpdater := currency.NewSyncer(http.DefaultClient, baseAmountSvc, config.CurrencyConfig)
+
+	jobScheduler, err := jobs.NewJobScheduler(&jobs.Config{
+		Configuration:          *config,
+		ExchangeRatesUpdateSvc: exchangeRateUpdater,
+		MaintenanceSvc:         maintenanceSvc,
+		LoanSvc:                loanSvc,
+		InvestmentSvc:          investmentSvc,
+		Elector:                elector,
+	})
+	if err != nil {
+		log.Logger.Fatal().Err(err).Msg("failed to create job scheduler")
+	}
+
+	baseParser := importers.NewBaseParser(currencyConverter, transactionSvc, mapper)
+
+	importSvc := importers.NewImporter(
+		&importers.ImporterConfig{
+			AccountSvc:     accountSvc,
+			TagSvc:         tagSvc,
+			CategoriesSvc:  categoriesSvc,
+			TransactionSvc: transactionSvc,
+			MapperSvc:      mapper,
+		},
+		importers.NewFireflyImporter(
+			transactionSvc,
+			currencyConverter,
+			baseParser,
+		),
+		importers.NewPrivat24(baseParser),
+		importers.NewMono(baseParser),
+		importers.NewParibas(baseParser),
+		importers.NewRevolut(baseParser),
+		importers.NewMbank(baseParser),
+	)
+
 	if !config.MCP.Disable {
 		logger.Info().Str("path", config.MCP.DocsDir).Msg("Reading mcp docs")
 		mcpDocs, mcpErr := gomoneyMcp.ReadDocsFromPath(config.MCP.DocsDir)
@@ -219,16 +347,54 @@ func main() {
 			logger.Fatal().Msg("mcp docs are empty")
 		}
 
-		mcpServer := gomoneyMcp.NewServer(&gomoneyMcp.ServerConfig{
+		mcpCfg := &gomoneyMcp.ServerConfig{
 			DB:             database.GetDb(database.DbTypeMaster),
 			Docs:           mcpDocs,
 			CategorySvc:    categoriesSvc,
 			RulesSvc:       rulesSvc,
+			RuleModulesSvc: ruleModulesSvc,
+			ScheduleSvc:    rulesScheduleSvc,
+			JobsSvc:        jobScheduler,
+			AccountSvc:     accountSvc,
+			LoanSvc:        loanSvc,
+			InvestmentSvc:  investmentSvc,
+			AnalyticsSvc:   analyticsSvc,
 			DryRunSvc:      dryRunSvc,
 			TagsSvc:        tagSvc,
 			TransactionSvc: transactionSvc,
+			HistorySvc:     historySvc,
+			ImportSvc:      importSvc,
 			CurrencySvc:    currencyConverter,
-		})
+			SuggestionSvc:  suggestionSvc,
+			EventFeedSvc:   eventFeed,
+			WebhookSvc:     webhookSvc,
+			CatalogSvc: currency.NewCatalogService(&currency.CatalogServiceConfig{
+				BaseAmountSvc: baseAmountSvc,
+				BaseCurrency:  config.CurrencyConfig.BaseCurrency,
+			}),
+			RateOverrideSvc: currency.NewRateOverrideService(&currency.RateOverrideServiceConfig{
+				BaseAmountSvc: baseAmountSvc,
+				BaseCurrency:  config.CurrencyConfig.BaseCurrency,
+			}),
+			Location:              location,
+			QueryRole:             config.MCP.QueryRole,
+			QueryStatementTimeout: config.MCP.QueryStatementTimeout,
+			QueryExtraTables:      config.MCP.QueryExtraTables,
+			QueryExtraFunctions:   config.MCP.QueryExtraFunctions,
+			QueryMaxResponseBytes: config.MCP.QueryMaxResponseBytes,
+			ClaimsFromContext:     middlewares.FromContext,
+			RateLimit: &gomoneyMcp.RateLimiterConfig{
+				Window:   config.MCP.RateLimitWindow,
+				MaxCalls: config.MCP.RateLimitCalls,
+				MaxRows:  config.MCP.RateLimitRows,
+			},
+		}
+
+		if !config.MCP.DisableAuditLog {
+			mcpCfg.AuditSvc = mcpaudit.NewService()
+		}
+
+		mcpServer := gomoneyMcp.NewServer(mcpCfg)
 
 		grpcServer.GetMux().Handle("/mcp", middlewares.HTTPAuthMiddleware(jwtService, mcpServer.Handler()))
 		logger.Info().Msg("MCP server enabled at /mcp")
@@ -249,72 +415,38 @@ func main() {
 		SchedulerSvc:     ruleScheduler,
 	})
 
-	analyticsSvc := analytics.NewService(&analytics.ServiceConfig{
-		DecimalSvc:   decimalSvc,
-		BaseCurrency: config.CurrencyConfig.BaseCurrency,
-	})
-
 	_ = handlers.NewCategoriesApi(grpcServer, categoriesSvc)
 	_ = handlers.NewMaintenanceApi(grpcServer, recalculateSvc)
 	_ = handlers.NewAnalyticsApi(grpcServer, analyticsSvc)
 
-	baseParser := importers.NewBaseParser(currencyConverter, transactionSvc, mapper)
-
-	importSvc := importers.NewImporter(
-		&importers.ImporterConfig{
-			AccountSvc:     accountSvc,
-			TagSvc:         tagSvc,
-			CategoriesSvc:  categoriesSvc,
-			TransactionSvc: transactionSvc,
-			MapperSvc:      mapper,
-		},
-		importers.NewFireflyImporter(
-			transactionSvc,
-			currencyConverter,
-			baseParser,
-		),
-		importers.NewPrivat24(baseParser),
-		importers.NewMono(baseParser),
-		importers.NewParibas(baseParser),
-		importers.NewRevolut(baseParser),
-		importers.NewMbank(baseParser),
-	)
-
 	_, err = handlers.NewImportApi(grpcServer, importSvc)
 	if err != nil {
 		log.Logger.Fatal().Err(err).Msg("failed to create import handler")
 	}
 
-	exchangeRateUpdater := currency.NewSyncer(http.DefaultClient, baseAmountSvc, config.CurrencyConfig)
-
 	if err = accountSvc.EnsureDefaultAccountsExist(context.TODO()); err != nil {
 		log.Logger.Fatal().Err(err).Msg("failed to ensure default accounts exist")
 	}
 
-	go func() {
-		if len(config.ExchangeRatesUrl) > 0 {
-			if currencyErr := exchangeRateUpdater.Sync(context.TODO(), config.ExchangeRatesUrl); currencyErr != nil {
-				logger.Err(err).Msg("cannot update exchange rates")
+	if isLeader() {
+		go func() {
+			if len(config.ExchangeRatesUrl) > 0 {
+				if currencyErr := exchangeRateUpdater.Sync(context.TODO(), config.ExchangeRatesUrl); currencyErr != nil {
+					logger.Err(err).Msg("cannot update exchange rates")
+				}
 			}
-		}
-	}()
+		}()
 
-	go func() {
-		if jobErr := maintenanceSvc.FixDailyGaps(context.TODO()); jobErr != nil {
-			logger.Err(jobErr).Msg("cannot fix daily gaps")
-			return
-		}
-
-		logger.Info().Msg("daily gaps fixed successfully")
-	}()
+		go func() {
+			if jobErr := maintenanceSvc.FixDailyGaps(context.TODO()); jobErr != nil {
+				logger.Err(jobErr).Msg("cannot fix daily gaps")
+				return
+			}
 
-	jobScheduler, err := jobs.NewJobScheduler(&jobs.Config{
-		Configuration:          *config,
-		ExchangeRatesUpdateSvc: exchangeRateUpdater,
-		MaintenanceSvc:         maintenanceSvc,
-	})
-	if err != nil {
-		log.Logger.Fatal().Err(err).Msg("failed to create job scheduler")
+			logger.Info().Msg("daily gaps fixed successfully")
+		}()
+	} else {
+		logger.Info().Msg("not a leader, skipping startup exchange rate sync and daily gap fix")
 	}
 
 	if err = jobScheduler.StartAsync(); err != nil {
@@ -323,6 +455,14 @@ func main() {
 
 	logger.Info().Msg("job scheduler started")
 
+	go ruleScheduler.Watch(ctx) // rule RPCs only reload the replica serving them
+
+	if !config.Webhooks.Disable {
+		go webhookSvc.Run(ctx) // safe on every replica, deliveries are claimed with skip locked
+
+		logger.Info().Msg("webhook dispatcher started")
+	}
+
 	go func() {
 		grpcServer.ServeAsync(config.GrpcPort)
 
@@ -6,13 +6,21 @@ import (
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	fetcher2 "github.com/ft-t/go-money/cmd/sync-exchange-rates/internal/fetcher"
 	uploader2 "github.com/ft-t/go-money/cmd/sync-exchange-rates/internal/uploader"
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/sethvargo/go-envconfig"
 	"net/http"
-	"os"
 	"time"
 )
 
+type Config struct {
+	BucketName   string                            `env:"EXCHANGE_RATES_BUCKET_NAME"`
+	APIURL       string                            `env:"EXCHANGE_RATES_API_URL"` // exchangerate-api.com endpoint
+	BaseCurrency string                            `env:"EXCHANGE_RATES_BASE_CURRENCY, default=USD"`
+	Providers    configuration.RateProvidersConfig `env:", prefix=EXCHANGE_RATES_PROVIDERS_"`
+}
+
 func main() {
 	ctx := context.Background()
 	sdkConfig, err := config.LoadDefaultConfig(ctx)
@@ -20,25 +28,41 @@ func main() {
 		panic(err)
 	}
 
-	s3Client := s3.NewFromConfig(sdkConfig)
-	uploader := uploader2.NewS3(s3Client, os.Getenv("EXCHANGE_RATES_BUCKET_NAME"))
+	var cfg Config
+	if err = envconfig.Process(ctx, &cfg); err != nil {
+		panic(err)
+	}
 
-	apiURL := os.Getenv("EXCHANGE_RATES_API_URL")
+	if cfg.Providers.ExchangeRateURL == "" {
+		cfg.Providers.ExchangeRateURL = cfg.APIURL
+	}
 
-	fetcher := fetcher2.NewFetcher(http.DefaultClient, apiURL)
+	s3Client := s3.NewFromConfig(sdkConfig)
+	uploader := uploader2.NewS3(s3Client, cfg.BucketName)
+
+	chain, err := currency.NewRateChainFromConfig(
+		http.DefaultClient,
+		cfg.Providers,
+		cfg.BaseCurrency,
+		"",
+		currency.ProviderExchangeRate,
+	)
+	if err != nil {
+		panic(err)
+	}
 
 	lambda.Start(func(ctx context.Context, raw json.RawMessage) error {
-		return Handler(ctx, raw, fetcher, uploader)
+		return Handler(ctx, raw, chain, uploader)
 	})
 }
 
 func Handler(
 	ctx context.Context,
 	_ json.RawMessage,
-	fetcher *fetcher2.Fetcher,
+	source currency.RateProvider,
 	uploader *uploader2.S3,
 ) error {
-	baseRates, err := fetcher.Fetch(context.Background())
+	baseRates, err := source.Fetch(ctx)
 	if err != nil {
 		return err
 	}
@@ -1,9 +1,11 @@
 package accounts
 
 import (
+	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
 	v1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
 	"context"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
 )
 
 //go:generate mockgen -destination interfaces_mocks_test.go -package accounts_test -source=interfaces.go
@@ -11,3 +13,29 @@ import (
 type MapperSvc interface {
 	MapAccount(ctx context.Context, acc *database.Account) *v1.Account
 }
+
+type DecimalSvc interface {
+	GetCurrencyDecimals(ctx context.Context, currency string) int32
+}
+
+type CurrencyConverterSvc interface {
+	Convert(
+		ctx context.Context,
+		fromCurrency string,
+		toCurrency string,
+		amount decimal.Decimal,
+	) (decimal.Decimal, error)
+}
+
+type TransactionSvc interface {
+	UpsertRawTransactions(
+		ctx context.Context,
+		created []*database.Transaction,
+		updated []*database.Transaction,
+	) ([]*transactionsv1.CreateTransactionResponse, error)
+}
+
+type LoanAccountSvc interface {
+	GetAccountByID(ctx context.Context, id int32) (*database.Account, error)
+	GetDefaultAccount(ctx context.Context, accountType v1.AccountType) (*database.Account, error)
+}
@@ -0,0 +1,156 @@
+package accounts
+
+import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	maxAmortizationPayments = 1200
+	ratePrecision           = 24 // decimal places of rates and growth factors, keeps products small and exact to far below a cent
+)
+
+var (
+	monthsInYear = decimal.NewFromInt(12)
+	daysInYear   = decimal.NewFromInt(365)
+	hundred      = decimal.NewFromInt(100)
+)
+
+// LoanDueDate returns the date of the n-th payment, n = 0 is the loan start date.
+func LoanDueDate(loan *database.Loan, n int32) time.Time {
+	if n == 0 {
+		return loan.StartDate
+	}
+
+	start := loan.StartDate
+
+	return time.Date(start.Year(), start.Month()+time.Month(n), int(loan.PaymentDay), 0, 0, 0, 0, time.UTC)
+}
+
+// periodRate returns the interest rate between two dates according to the compounding method.
+func periodRate(loan *database.Loan, from time.Time, to time.Time) decimal.Decimal {
+	annual := loan.AnnualRate.Div(hundred)
+
+	if loan.Compounding == database.LoanCompoundingDaily {
+		days := int64(to.Sub(from).Hours() / 24)
+		if days <= 0 {
+			return decimal.Zero
+		}
+
+		return decimal.NewFromInt(1).Add(annual.Div(daysInYear)).Pow(decimal.NewFromInt(days)).Sub(decimal.NewFromInt(1))
+	}
+
+	return annual.Div(monthsInYear)
+}
+
+// annuityPayment returns the fixed payment that repays principal in the given number of periods:
+// principal * r * (1+r)^n / ((1+r)^n - 1) with r the monthly rate.
+func annuityPayment(loan *database.Loan, principal decimal.Decimal, periods int32) (decimal.Decimal, error) {
+	if periods <= 0 {
+		return principal, nil
+	}
+
+	monthly, err := monthlyRate(loan)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	if monthly.IsZero() {
+		return principal.DivRound(decimal.NewFromInt32(periods), ratePrecision), nil
+	}
+
+	growth := compound(monthly, periods)
+
+	return principal.Mul(monthly).Mul(growth).DivRound(growth.Sub(decimal.NewFromInt(1)), ratePrecision), nil
+}
+
+// monthlyRate returns the rate of an average month. With daily compounding that is
+// (1 + annual/365)^(365/12) - 1, computed as exp(365/12 * ln(1 + annual/365)).
+func monthlyRate(loan *database.Loan) (decimal.Decimal, error) {
+	annual := loan.AnnualRate.Div(hundred)
+
+	if loan.Compounding != database.LoanCompoundingDaily {
+		return annual.DivRound(monthsInYear, ratePrecision), nil
+	}
+
+	ln, err := decimal.NewFromInt(1).Add(annual.DivRound(daysInYear, ratePrecision)).Ln(ratePrecision)
+	if err != nil {
+		return decimal.Zero, errors.Wrap(err, "invalid annual rate")
+	}
+
+	growth, err := ln.Mul(daysInYear).DivRound(monthsInYear, ratePrecision).ExpTaylor(ratePrecision)
+	if err != nil {
+		return decimal.Zero, errors.Wrap(err, "invalid annual rate")
+	}
+
+	return growth.Sub(decimal.NewFromInt(1)), nil
+}
+
+// compound returns (1 + rate)^periods by squaring, rounded to ratePrecision at every step so
+// the digits do not grow with the number of periods.
+func compound(rate decimal.Decimal, periods int32) decimal.Decimal {
+	base, result := decimal.NewFromInt(1).Add(rate), decimal.NewFromInt(1)
+
+	for ; periods > 0; periods /= 2 {
+		if periods%2 == 1 {
+			result = result.Mul(base).Round(ratePrecision)
+		}
+
+		base = base.Mul(base).Round(ratePrecision)
+	}
+
+	return result
+}
+
+// amortize splits fixed payments into interest and principal starting from payment firstNumber.
+// The payment with number lastNumber repays everything left, zero means no fixed term.
+func amortize(
+	loan *database.Loan,
+	principal decimal.Decimal,
+	payment decimal.Decimal,
+	firstNumber int32,
+	lastNumber int32,
+	decimals int32,
+	carryInterest decimal.Decimal,
+) ([]*AmortizationEntry, error) {
+	var entries []*AmortizationEntry
+
+	payment = payment.Round(decimals)
+	remaining := principal
+
+	for n := firstNumber; remaining.IsPositive(); n++ {
+		if len(entries) >= maxAmortizationPayments {
+			return nil, errors.Newf("loan is not repaid within %d payments", maxAmortizationPayments)
+		}
+
+		interest := remaining.Mul(periodRate(loan, LoanDueDate(loan, n-1), LoanDueDate(loan, n))).Round(decimals)
+		if n == firstNumber {
+			interest = interest.Add(carryInterest)
+		}
+
+		principalPart := payment.Sub(interest)
+		if n == lastNumber || principalPart.GreaterThan(remaining) {
+			principalPart = remaining
+		}
+
+		if !principalPart.IsPositive() {
+			return nil, errors.Newf("payment %s does not cover interest %s", payment, interest)
+		}
+
+		remaining = remaining.Sub(principalPart)
+
+		entries = append(entries, &AmortizationEntry{
+			Number:             n,
+			DueDate:            LoanDueDate(loan, n),
+			Payment:            principalPart.Add(interest),
+			Interest:           interest,
+			Principal:          principalPart,
+			RemainingPrincipal: remaining,
+		})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,426 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const loanInterestJobName = "loan_interest"
+
+type LoanService struct {
+	cfg *LoanServiceConfig
+}
+
+type LoanServiceConfig struct {
+	AccountSvc           LoanAccountSvc
+	TransactionSvc       TransactionSvc
+	DecimalSvc           DecimalSvc
+	CurrencyConverterSvc CurrencyConverterSvc
+}
+
+func NewLoanService(cfg *LoanServiceConfig) *LoanService {
+	return &LoanService{
+		cfg: cfg,
+	}
+}
+
+// SetLoan creates or replaces repayment terms of a liability account.
+func (s *LoanService) SetLoan(ctx context.Context, req *SetLoanRequest) (*database.Loan, error) {
+	if err := s.validate(req); err != nil {
+		return nil, err
+	}
+
+	account, err := s.cfg.AccountSvc.GetAccountByID(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Type != gomoneypbv1.AccountType_ACCOUNT_TYPE_LIABILITY {
+		return nil, errors.Newf("account %d is not a liability account", account.ID)
+	}
+
+	tx := database.GetDbWithContext(ctx, database.DbTypeMaster).Begin()
+	defer tx.Rollback()
+
+	var loan database.Loan
+	if err = tx.Where("account_id = ?", req.AccountID).Limit(1).Find(&loan).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get loan")
+	}
+
+	loan.AccountID = req.AccountID
+	loan.Kind = req.Kind
+	loan.Principal = req.Principal
+	loan.AnnualRate = req.AnnualRate
+	loan.TermMonths = req.TermMonths
+	loan.PaymentDay = req.PaymentDay
+	loan.Compounding = req.Compounding
+	loan.StartDate = req.StartDate.UTC()
+	loan.InterestAccountID = req.InterestAccountID
+	loan.InterestCategoryID = req.InterestCategoryID
+
+	if err = tx.Save(&loan).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to save loan")
+	}
+
+	if err = tx.Model(&database.Account{}).Where("id = ?", account.ID).
+		UpdateColumn("liability_percent", req.AnnualRate).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to update liability percent")
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &loan, nil
+}
+
+func (s *LoanService) validate(req *SetLoanRequest) error {
+	if req.Kind != database.LoanKindLoan && req.Kind != database.LoanKindCreditLine {
+		return errors.Newf("unsupported loan kind: %d", req.Kind)
+	}
+
+	if req.Compounding != database.LoanCompoundingMonthly && req.Compounding != database.LoanCompoundingDaily {
+		return errors.Newf("unsupported compounding: %d", req.Compounding)
+	}
+
+	if !req.Principal.IsPositive() {
+		return errors.New("principal must be positive")
+	}
+
+	if req.AnnualRate.IsNegative() {
+		return errors.New("annual rate must not be negative")
+	}
+
+	if req.TermMonths <= 0 || req.TermMonths > maxAmortizationPayments {
+		return errors.Newf("term must be between 1 and %d months", maxAmortizationPayments)
+	}
+
+	if req.PaymentDay < 1 || req.PaymentDay > 28 {
+		return errors.New("payment day must be between 1 and 28")
+	}
+
+	if req.StartDate.IsZero() {
+		return errors.New("start date is required")
+	}
+
+	return nil
+}
+
+func (s *LoanService) GetLoan(ctx context.Context, accountID int32) (*database.Loan, error) {
+	var loan database.Loan
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).
+		Where("account_id = ?", accountID).First(&loan).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to get loan for account %d", accountID)
+	}
+
+	return &loan, nil
+}
+
+// GetAmortizationSchedule returns the original annuity schedule of the loan.
+func (s *LoanService) GetAmortizationSchedule(ctx context.Context, accountID int32) ([]*AmortizationEntry, error) {
+	loan, err := s.GetLoan(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.cfg.AccountSvc.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := annuityPayment(loan, loan.Principal, loan.TermMonths)
+	if err != nil {
+		return nil, err
+	}
+
+	return amortize(
+		loan,
+		loan.Principal,
+		payment,
+		1,
+		loan.TermMonths,
+		s.cfg.DecimalSvc.GetCurrencyDecimals(ctx, account.Currency),
+		decimal.Zero,
+	)
+}
+
+// GetLoanStatus returns remaining principal, repayments split into interest and principal
+// and a payoff projection. Zero monthlyPayment projects with the annuity payment for the remaining term.
+func (s *LoanService) GetLoanStatus(
+	ctx context.Context,
+	accountID int32,
+	monthlyPayment decimal.Decimal,
+) (*LoanStatus, error) {
+	loan, err := s.GetLoan(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.cfg.AccountSvc.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, err := s.getAccountTransactions(database.GetDbWithContext(ctx, database.DbTypeReadonly), accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals := s.cfg.DecimalSvc.GetCurrencyDecimals(ctx, account.Currency)
+	state := replayLoan(loan, txs, time.Now().UTC())
+
+	status := &LoanStatus{
+		Loan:               loan,
+		Currency:           account.Currency,
+		RemainingPrincipal: state.principal,
+		UnpaidInterest:     state.unpaidInterest,
+		AccruedInterest:    state.accruedInterest,
+		Repayments:         state.repayments,
+	}
+
+	if !state.principal.IsPositive() {
+		return status, nil
+	}
+
+	nextNumber := int32(1)
+	for !LoanDueDate(loan, nextNumber).After(time.Now().UTC()) {
+		nextNumber++
+	}
+
+	lastNumber := int32(0)
+	if monthlyPayment.IsZero() {
+		lastNumber = max(loan.TermMonths, nextNumber)
+		if monthlyPayment, err = annuityPayment(loan, state.principal, lastNumber-nextNumber+1); err != nil {
+			return nil, err
+		}
+	}
+
+	schedule, err := amortize(loan, state.principal, monthlyPayment, nextNumber, lastNumber, decimals, state.unpaidInterest)
+	if err != nil {
+		return nil, err
+	}
+
+	status.Projection = &PayoffProjection{
+		MonthlyPayment: monthlyPayment.Round(decimals),
+		PaymentsLeft:   int32(len(schedule)),
+		PayoffDate:     schedule[len(schedule)-1].DueDate,
+		TotalInterest: lo.Reduce(schedule, func(agg decimal.Decimal, item *AmortizationEntry, _ int) decimal.Decimal {
+			return agg.Add(item.Interest)
+		}, decimal.Zero),
+		Schedule: schedule,
+	}
+
+	return status, nil
+}
+
+// AccrueInterest posts interest for every payment date reached since the last accrual
+// as an expense from the liability account, so the liability balance includes it.
+func (s *LoanService) AccrueInterest(ctx context.Context, now time.Time) error {
+	var loans []*database.Loan
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).Find(&loans).Error; err != nil {
+		return errors.Wrap(err, "failed to get loans")
+	}
+
+	var finalErr error
+	for _, loan := range loans {
+		if err := s.accrueLoan(ctx, loan, now); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Int32("loan_id", loan.ID).Msg("failed to accrue loan interest")
+			finalErr = errors.CombineErrors(finalErr, err)
+		}
+	}
+
+	return finalErr
+}
+
+func (s *LoanService) accrueLoan(ctx context.Context, loan *database.Loan, now time.Time) error {
+	account, err := s.cfg.AccountSvc.GetAccountByID(ctx, loan.AccountID)
+	if err != nil {
+		return err
+	}
+
+	interestAccount, err := s.getInterestAccount(ctx, loan)
+	if err != nil {
+		return err
+	}
+
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	txs, err := s.getAccountTransactions(db, loan.AccountID)
+	if err != nil {
+		return err
+	}
+
+	decimals := s.cfg.DecimalSvc.GetCurrencyDecimals(ctx, account.Currency)
+	lastAccrued := lo.FromPtrOr(loan.LastAccruedAt, loan.StartDate)
+	ctx = history.WithActor(ctx, history.JobActor(loanInterestJobName))
+
+	for n := int32(1); !LoanDueDate(loan, n).After(now); n++ {
+		dueDate := LoanDueDate(loan, n)
+		if !dueDate.After(lastAccrued) {
+			continue
+		}
+
+		// interest is charged on the principal outstanding at the start of the period
+		periodStart := LoanDueDate(loan, n-1)
+		state := replayLoan(loan, txs, periodStart)
+		interest := state.principal.Mul(periodRate(loan, periodStart, dueDate)).Round(decimals)
+
+		if interest.IsPositive() && !hasAccrual(loan, txs, dueDate) {
+			accrual, accrualErr := s.newAccrual(ctx, loan, account, interestAccount, interest, dueDate)
+			if accrualErr != nil {
+				return accrualErr
+			}
+
+			if _, err = s.cfg.TransactionSvc.UpsertRawTransactions(ctx, []*database.Transaction{accrual}, nil); err != nil {
+				return errors.Wrapf(err, "failed to post interest for %s", dueDate.Format(time.DateOnly))
+			}
+
+			txs = append(txs, accrual)
+		}
+
+		if err = db.Model(loan).UpdateColumn("last_accrued_at", dueDate).Error; err != nil {
+			return errors.Wrap(err, "failed to update last_accrued_at")
+		}
+	}
+
+	return nil
+}
+
+// hasAccrual reports whether interest for dueDate is already posted. Posting and moving
+// last_accrued_at are separate writes, so a run that failed in between must not post it twice;
+// loan_id and loan_accrual identify the accrual of a period.
+func hasAccrual(loan *database.Loan, txs []*database.Transaction, dueDate time.Time) bool {
+	loanID := strconv.Itoa(int(loan.ID))
+	period := dueDate.Format(time.DateOnly)
+
+	return lo.ContainsBy(txs, func(tx *database.Transaction) bool {
+		return tx.Extra[loanExtraID] == loanID && tx.Extra[loanExtraAccrual] == period
+	})
+}
+
+func (s *LoanService) newAccrual(
+	ctx context.Context,
+	loan *database.Loan,
+	account *database.Account,
+	interestAccount *database.Account,
+	interest decimal.Decimal,
+	dueDate time.Time,
+) (*database.Transaction, error) {
+	destinationAmount := interest
+	if interestAccount.Currency != account.Currency {
+		converted, err := s.cfg.CurrencyConverterSvc.Convert(ctx, account.Currency, interestAccount.Currency, interest)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert interest")
+		}
+
+		destinationAmount = converted.Round(s.cfg.DecimalSvc.GetCurrencyDecimals(ctx, interestAccount.Currency))
+	}
+
+	return &database.Transaction{
+		TransactionType:      gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+		SourceAccountID:      account.ID,
+		SourceCurrency:       account.Currency,
+		SourceAmount:         decimal.NewNullDecimal(interest.Neg()),
+		DestinationAccountID: interestAccount.ID,
+		DestinationCurrency:  interestAccount.Currency,
+		DestinationAmount:    decimal.NewNullDecimal(destinationAmount),
+		CategoryID:           loan.InterestCategoryID,
+		Title:                fmt.Sprintf("Interest %s", account.Name),
+		TransactionDateTime:  dueDate,
+		TransactionDateOnly:  dueDate,
+		Extra: map[string]string{
+			loanExtraID:      strconv.Itoa(int(loan.ID)),
+			loanExtraAccrual: dueDate.Format(time.DateOnly),
+		},
+	}, nil
+}
+
+func (s *LoanService) getInterestAccount(ctx context.Context, loan *database.Loan) (*database.Account, error) {
+	if loan.InterestAccountID != nil {
+		return s.cfg.AccountSvc.GetAccountByID(ctx, *loan.InterestAccountID)
+	}
+
+	account, err := s.cfg.AccountSvc.GetDefaultAccount(ctx, gomoneypbv1.AccountType_ACCOUNT_TYPE_EXPENSE)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get default expense account")
+	}
+
+	return account, nil
+}
+
+func (s *LoanService) getAccountTransactions(db *gorm.DB, accountID int32) ([]*database.Transaction, error) {
+	var txs []*database.Transaction
+
+	if err := db.Where("deleted_at IS NULL AND (source_account_id = ? OR destination_account_id = ?)", accountID, accountID).
+		Order("transaction_date_time, id").Find(&txs).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get loan transactions")
+	}
+
+	return txs, nil
+}
+
+type loanState struct {
+	principal       decimal.Decimal
+	unpaidInterest  decimal.Decimal
+	accruedInterest decimal.Decimal
+	repayments      []*LoanRepayment
+}
+
+// replayLoan walks account transactions up to the given date. Accruals add unpaid interest,
+// repayments cover unpaid interest first and the rest goes to principal. Credit lines
+// draw principal from outgoing transactions, loans start with the full principal.
+func replayLoan(loan *database.Loan, txs []*database.Transaction, until time.Time) *loanState {
+	state := &loanState{}
+	if loan.Kind == database.LoanKindLoan {
+		state.principal = loan.Principal
+	}
+
+	loanID := strconv.Itoa(int(loan.ID))
+
+	for _, tx := range txs {
+		if tx.TransactionDateTime.After(until) ||
+			tx.TransactionType == gomoneypbv1.TransactionType_TRANSACTION_TYPE_ADJUSTMENT {
+			continue
+		}
+
+		switch {
+		case tx.Extra[loanExtraID] == loanID && tx.Extra[loanExtraAccrual] != "":
+			state.unpaidInterest = state.unpaidInterest.Add(tx.SourceAmount.Decimal.Abs())
+			state.accruedInterest = state.accruedInterest.Add(tx.SourceAmount.Decimal.Abs())
+		case tx.DestinationAccountID == loan.AccountID:
+			amount := tx.DestinationAmount.Decimal.Abs()
+
+			interest := decimal.Min(amount, state.unpaidInterest)
+			principal := decimal.Min(amount.Sub(interest), state.principal)
+
+			state.unpaidInterest = state.unpaidInterest.Sub(interest)
+			state.principal = state.principal.Sub(principal)
+
+			state.repayments = append(state.repayments, &LoanRepayment{
+				TransactionID:      tx.ID,
+				Date:               tx.TransactionDateTime,
+				Amount:             amount,
+				Interest:           interest,
+				Principal:          principal,
+				RemainingPrincipal: state.principal,
+			})
+		case tx.SourceAccountID == loan.AccountID && loan.Kind == database.LoanKindCreditLine:
+			state.principal = state.principal.Add(tx.SourceAmount.Decimal.Abs())
+		}
+	}
+
+	return state
+}
@@ -0,0 +1,352 @@
+package accounts_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
+	v1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/ft-t/go-money/pkg/accounts"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoanDueDate(t *testing.T) {
+	loan := &database.Loan{
+		StartDate:  time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC),
+		PaymentDay: 5,
+	}
+
+	assert.Equal(t, loan.StartDate, accounts.LoanDueDate(loan, 0))
+	assert.Equal(t, time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC), accounts.LoanDueDate(loan, 1))
+	assert.Equal(t, time.Date(2027, 1, 5, 0, 0, 0, 0, time.UTC), accounts.LoanDueDate(loan, 12))
+}
+
+func newLoanTestService(t *testing.T, txSvc *MockTransactionSvc) *accounts.LoanService {
+	decimalSvc := NewMockDecimalSvc(gomock.NewController(t))
+	decimalSvc.EXPECT().GetCurrencyDecimals(gomock.Any(), gomock.Any()).Return(int32(2)).AnyTimes()
+
+	return accounts.NewLoanService(&accounts.LoanServiceConfig{
+		AccountSvc:     accounts.NewService(&accounts.ServiceConfig{}),
+		TransactionSvc: txSvc,
+		DecimalSvc:     decimalSvc,
+	})
+}
+
+func createLoanAccounts(t *testing.T) (*database.Account, *database.Account) {
+	liability := &database.Account{
+		Name:     "Car loan",
+		Currency: "USD",
+		Type:     v1.AccountType_ACCOUNT_TYPE_LIABILITY,
+		Extra:    map[string]string{},
+	}
+	expense := &database.Account{
+		Name:     "Interest",
+		Currency: "USD",
+		Type:     v1.AccountType_ACCOUNT_TYPE_EXPENSE,
+		Extra:    map[string]string{},
+	}
+	require.NoError(t, gormDB.Create(liability).Error)
+	require.NoError(t, gormDB.Create(expense).Error)
+
+	return liability, expense
+}
+
+func TestLoanService_SetLoan(t *testing.T) {
+	startDate := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+		liability, _ := createLoanAccounts(t)
+
+		svc := newLoanTestService(t, nil)
+
+		req := &accounts.SetLoanRequest{
+			AccountID:   liability.ID,
+			Kind:        database.LoanKindLoan,
+			Principal:   decimal.NewFromInt(1200),
+			AnnualRate:  decimal.NewFromInt(12),
+			TermMonths:  12,
+			PaymentDay:  15,
+			Compounding: database.LoanCompoundingMonthly,
+			StartDate:   startDate,
+		}
+
+		loan, err := svc.SetLoan(context.TODO(), req)
+		require.NoError(t, err)
+
+		req.TermMonths = 24
+		updated, err := svc.SetLoan(context.TODO(), req)
+		require.NoError(t, err)
+		assert.Equal(t, loan.ID, updated.ID)
+		assert.EqualValues(t, 24, updated.TermMonths)
+
+		var acc database.Account
+		require.NoError(t, gormDB.First(&acc, liability.ID).Error)
+		assert.Equal(t, "12", acc.LiabilityPercent.Decimal.String())
+	})
+
+	t.Run("not a liability", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+		_, expense := createLoanAccounts(t)
+
+		_, err := newLoanTestService(t, nil).SetLoan(context.TODO(), &accounts.SetLoanRequest{
+			AccountID:   expense.ID,
+			Kind:        database.LoanKindLoan,
+			Principal:   decimal.NewFromInt(100),
+			TermMonths:  1,
+			PaymentDay:  1,
+			Compounding: database.LoanCompoundingMonthly,
+			StartDate:   startDate,
+		})
+		assert.ErrorContains(t, err, "is not a liability account")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		svc := newLoanTestService(t, nil)
+		valid := accounts.SetLoanRequest{
+			Kind:        database.LoanKindLoan,
+			Principal:   decimal.NewFromInt(100),
+			TermMonths:  1,
+			PaymentDay:  1,
+			Compounding: database.LoanCompoundingMonthly,
+			StartDate:   startDate,
+		}
+
+		cases := map[string]func(r *accounts.SetLoanRequest){
+			"unsupported loan kind":       func(r *accounts.SetLoanRequest) { r.Kind = 0 },
+			"unsupported compounding":     func(r *accounts.SetLoanRequest) { r.Compounding = 9 },
+			"principal must be positive":  func(r *accounts.SetLoanRequest) { r.Principal = decimal.Zero },
+			"annual rate must not be":     func(r *accounts.SetLoanRequest) { r.AnnualRate = decimal.NewFromInt(-1) },
+			"term must be between":        func(r *accounts.SetLoanRequest) { r.TermMonths = 0 },
+			"payment day must be between": func(r *accounts.SetLoanRequest) { r.PaymentDay = 31 },
+			"start date is required":      func(r *accounts.SetLoanRequest) { r.StartDate = time.Time{} },
+		}
+
+		for msg, mutate := range cases {
+			req := valid
+			mutate(&req)
+
+			_, err := svc.SetLoan(context.TODO(), &req)
+			assert.ErrorContains(t, err, msg)
+		}
+	})
+}
+
+func TestLoanService_GetAmortizationSchedule(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+	liability, _ := createLoanAccounts(t)
+
+	require.NoError(t, gormDB.Create(&database.Loan{
+		AccountID:   liability.ID,
+		Kind:        database.LoanKindLoan,
+		Principal:   decimal.NewFromInt(1200),
+		AnnualRate:  decimal.NewFromInt(12),
+		TermMonths:  12,
+		PaymentDay:  15,
+		Compounding: database.LoanCompoundingMonthly,
+		StartDate:   time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+	}).Error)
+
+	schedule, err := newLoanTestService(t, nil).GetAmortizationSchedule(context.TODO(), liability.ID)
+	require.NoError(t, err)
+	require.Len(t, schedule, 12)
+
+	assert.Equal(t, "106.62", schedule[0].Payment.String())
+	assert.Equal(t, "12", schedule[0].Interest.String())
+	assert.Equal(t, "94.62", schedule[0].Principal.String())
+	assert.Equal(t, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), schedule[0].DueDate)
+	assert.True(t, schedule[11].RemainingPrincipal.IsZero())
+
+	total := decimal.Zero
+	for _, entry := range schedule {
+		total = total.Add(entry.Principal)
+	}
+	assert.Equal(t, "1200", total.String())
+}
+
+func TestLoanService_GetAmortizationSchedule_LargePrincipal(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+	liability, _ := createLoanAccounts(t)
+
+	require.NoError(t, gormDB.Create(&database.Loan{
+		AccountID:   liability.ID,
+		Kind:        database.LoanKindLoan,
+		Principal:   decimal.RequireFromString("987654321098765.43"),
+		AnnualRate:  decimal.RequireFromString("7.35"),
+		TermMonths:  360,
+		PaymentDay:  15,
+		Compounding: database.LoanCompoundingMonthly,
+		StartDate:   time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+	}).Error)
+
+	schedule, err := newLoanTestService(t, nil).GetAmortizationSchedule(context.TODO(), liability.ID)
+	require.NoError(t, err)
+	require.Len(t, schedule, 360)
+
+	// float64 math gave 6804662863675.04
+	assert.Equal(t, "6804662863675.02", schedule[0].Payment.String())
+	assert.Equal(t, "6049382716729.94", schedule[0].Interest.String())
+	assert.Equal(t, "755280146945.08", schedule[0].Principal.String())
+	assert.True(t, schedule[359].RemainingPrincipal.IsZero())
+}
+
+func TestLoanService_GetLoanStatus(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+	liability, expense := createLoanAccounts(t)
+
+	loan := &database.Loan{
+		AccountID:   liability.ID,
+		Kind:        database.LoanKindLoan,
+		Principal:   decimal.NewFromInt(1000),
+		AnnualRate:  decimal.NewFromInt(12),
+		TermMonths:  12,
+		PaymentDay:  15,
+		Compounding: database.LoanCompoundingMonthly,
+		StartDate:   time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, gormDB.Create(loan).Error)
+
+	require.NoError(t, gormDB.Create(&[]*database.Transaction{
+		{
+			TransactionType:      v1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+			SourceAccountID:      liability.ID,
+			SourceAmount:         decimal.NewNullDecimal(decimal.NewFromInt(-10)),
+			DestinationAccountID: expense.ID,
+			DestinationAmount:    decimal.NewNullDecimal(decimal.NewFromInt(10)),
+			TransactionDateTime:  time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC),
+			Extra:                map[string]string{"loan_id": strconv.Itoa(int(loan.ID)), "loan_accrual": "2026-02-15"},
+		},
+		{
+			TransactionType:      v1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+			DestinationAccountID: liability.ID,
+			DestinationAmount:    decimal.NewNullDecimal(decimal.NewFromInt(110)),
+			TransactionDateTime:  time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC),
+			Extra:                map[string]string{},
+		},
+	}).Error)
+
+	t.Run("annuity projection", func(t *testing.T) {
+		status, err := newLoanTestService(t, nil).GetLoanStatus(context.TODO(), liability.ID, decimal.Zero)
+		require.NoError(t, err)
+
+		assert.Equal(t, "900", status.RemainingPrincipal.String())
+		assert.True(t, status.UnpaidInterest.IsZero())
+		assert.Equal(t, "10", status.AccruedInterest.String())
+		require.Len(t, status.Repayments, 1)
+		assert.Equal(t, "10", status.Repayments[0].Interest.String())
+		assert.Equal(t, "100", status.Repayments[0].Principal.String())
+
+		require.NotNil(t, status.Projection)
+		assert.True(t, status.Projection.Schedule[len(status.Projection.Schedule)-1].RemainingPrincipal.IsZero())
+	})
+
+	t.Run("custom payment", func(t *testing.T) {
+		status, err := newLoanTestService(t, nil).GetLoanStatus(context.TODO(), liability.ID, decimal.NewFromInt(500))
+		require.NoError(t, err)
+
+		require.NotNil(t, status.Projection)
+		assert.EqualValues(t, 2, status.Projection.PaymentsLeft)
+	})
+
+	t.Run("payment does not cover interest", func(t *testing.T) {
+		_, err := newLoanTestService(t, nil).GetLoanStatus(context.TODO(), liability.ID, decimal.NewFromInt(1))
+		assert.ErrorContains(t, err, "does not cover interest")
+	})
+
+	t.Run("loan not found", func(t *testing.T) {
+		_, err := newLoanTestService(t, nil).GetLoanStatus(context.TODO(), expense.ID, decimal.Zero)
+		assert.ErrorContains(t, err, "failed to get loan for account")
+	})
+}
+
+func TestLoanService_AccrueInterest(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+	liability, expense := createLoanAccounts(t)
+
+	loan := &database.Loan{
+		AccountID:         liability.ID,
+		Kind:              database.LoanKindLoan,
+		Principal:         decimal.NewFromInt(1000),
+		AnnualRate:        decimal.NewFromInt(12),
+		TermMonths:        12,
+		PaymentDay:        15,
+		Compounding:       database.LoanCompoundingMonthly,
+		StartDate:         time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		InterestAccountID: &expense.ID,
+	}
+	require.NoError(t, gormDB.Create(loan).Error)
+
+	txSvc := NewMockTransactionSvc(gomock.NewController(t))
+	txSvc.EXPECT().UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Nil()).
+		DoAndReturn(func(_ context.Context, created []*database.Transaction, _ []*database.Transaction) ([]*transactionsv1.CreateTransactionResponse, error) {
+			require.Len(t, created, 1)
+			assert.Equal(t, "-10", created[0].SourceAmount.Decimal.String())
+			assert.Equal(t, expense.ID, created[0].DestinationAccountID)
+			assert.Equal(t, strconv.Itoa(int(loan.ID)), created[0].Extra["loan_id"])
+
+			return nil, nil
+		}).Times(2)
+
+	svc := newLoanTestService(t, txSvc)
+	now := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, svc.AccrueInterest(context.TODO(), now))
+	require.NoError(t, svc.AccrueInterest(context.TODO(), now)) // already accrued
+
+	var stored database.Loan
+	require.NoError(t, gormDB.First(&stored, loan.ID).Error)
+	require.NotNil(t, stored.LastAccruedAt)
+	assert.Equal(t, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), stored.LastAccruedAt.UTC())
+}
+
+func TestLoanService_AccrueInterest_AlreadyPosted(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+	liability, expense := createLoanAccounts(t)
+
+	loan := &database.Loan{
+		AccountID:         liability.ID,
+		Kind:              database.LoanKindLoan,
+		Principal:         decimal.NewFromInt(1000),
+		AnnualRate:        decimal.NewFromInt(12),
+		TermMonths:        12,
+		PaymentDay:        15,
+		Compounding:       database.LoanCompoundingMonthly,
+		StartDate:         time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		InterestAccountID: &expense.ID,
+	}
+	require.NoError(t, gormDB.Create(loan).Error)
+
+	// posted by a run that failed before it moved last_accrued_at
+	require.NoError(t, gormDB.Create(&database.Transaction{
+		TransactionType:      v1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+		SourceAccountID:      liability.ID,
+		SourceAmount:         decimal.NewNullDecimal(decimal.NewFromInt(-10)),
+		DestinationAccountID: expense.ID,
+		DestinationAmount:    decimal.NewNullDecimal(decimal.NewFromInt(10)),
+		TransactionDateTime:  time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC),
+		Extra:                map[string]string{"loan_id": strconv.Itoa(int(loan.ID)), "loan_accrual": "2026-02-15"},
+	}).Error)
+
+	txSvc := NewMockTransactionSvc(gomock.NewController(t))
+	txSvc.EXPECT().UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Nil()).
+		DoAndReturn(func(_ context.Context, created []*database.Transaction, _ []*database.Transaction) ([]*transactionsv1.CreateTransactionResponse, error) {
+			require.Len(t, created, 1)
+			assert.Equal(t, "2026-03-15", created[0].Extra["loan_accrual"])
+
+			return nil, nil
+		})
+
+	require.NoError(t, newLoanTestService(t, txSvc).AccrueInterest(context.TODO(), time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)))
+
+	var stored database.Loan
+	require.NoError(t, gormDB.First(&stored, loan.ID).Error)
+	require.NotNil(t, stored.LastAccruedAt)
+	assert.Equal(t, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), stored.LastAccruedAt.UTC())
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/ft-t/go-money/pkg/boilerplate"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/timezone"
 	"github.com/lib/pq"
+	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -343,6 +345,33 @@ func (s *Service) Update(
 	}, nil
 }
 
+// SetTimezone sets the IANA timezone transaction dates of the account are resolved in, an empty
+// name falls back to the household timezone. Dates of existing transactions are not changed.
+func (s *Service) SetTimezone(
+	ctx context.Context,
+	accountID int32,
+	name string,
+) (*database.Account, error) {
+	if _, err := timezone.Load(name); err != nil {
+		return nil, err
+	}
+
+	db := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeMaster))
+
+	var account database.Account
+	if err := db.Where("id = ?", accountID).First(&account).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get account")
+	}
+
+	account.Timezone = lo.EmptyableToPtr(name)
+
+	if err := db.Model(&account).UpdateColumn("timezone", account.Timezone).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to update account timezone")
+	}
+
+	return &account, nil
+}
+
 func (s *Service) EnsureDefaultAccountsExist(
 	ctx context.Context,
 ) error {
@@ -1317,3 +1317,41 @@ func TestEnsureAccountExists(t *testing.T) {
 		assert.ErrorContains(t, err, "failed to create account")
 	})
 }
+
+func TestService_SetTimezone(t *testing.T) {
+	t.Run("set and clear", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		acc := &database.Account{Name: "card", Currency: "USD", Type: v1.AccountType_ACCOUNT_TYPE_ASSET, Extra: map[string]string{}}
+		assert.NoError(t, gormDB.Create(acc).Error)
+
+		srv := accounts.NewService(&accounts.ServiceConfig{})
+
+		updated, err := srv.SetTimezone(context.TODO(), acc.ID, "Europe/Kyiv")
+		assert.NoError(t, err)
+		assert.Equal(t, "Europe/Kyiv", *updated.Timezone)
+
+		var stored database.Account
+		assert.NoError(t, gormDB.First(&stored, acc.ID).Error)
+		assert.Equal(t, "Europe/Kyiv", *stored.Timezone)
+
+		updated, err = srv.SetTimezone(context.TODO(), acc.ID, "")
+		assert.NoError(t, err)
+		assert.Nil(t, updated.Timezone)
+
+		assert.NoError(t, gormDB.First(&stored, acc.ID).Error)
+		assert.Nil(t, stored.Timezone)
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		_, err := accounts.NewService(&accounts.ServiceConfig{}).SetTimezone(context.TODO(), 1, "Mars/Olympus")
+		assert.ErrorContains(t, err, "invalid timezone")
+	})
+
+	t.Run("account not found", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		_, err := accounts.NewService(&accounts.ServiceConfig{}).SetTimezone(context.TODO(), 999, "UTC")
+		assert.ErrorContains(t, err, "failed to get account")
+	})
+}
@@ -1 +1,64 @@
 package accounts
+
+import (
+	"time"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	loanExtraID      = "loan_id"
+	loanExtraAccrual = "loan_accrual"
+)
+
+type SetLoanRequest struct {
+	AccountID          int32
+	Kind               database.LoanKind
+	Principal          decimal.Decimal
+	AnnualRate         decimal.Decimal
+	TermMonths         int32
+	PaymentDay         int32
+	Compounding        database.LoanCompounding
+	StartDate          time.Time
+	InterestAccountID  *int32
+	InterestCategoryID *int32
+}
+
+// AmortizationEntry is a single scheduled payment.
+type AmortizationEntry struct {
+	Number             int32
+	DueDate            time.Time
+	Payment            decimal.Decimal
+	Interest           decimal.Decimal
+	Principal          decimal.Decimal
+	RemainingPrincipal decimal.Decimal
+}
+
+// LoanRepayment is a transfer into the liability account split into interest and principal parts.
+type LoanRepayment struct {
+	TransactionID      int64
+	Date               time.Time
+	Amount             decimal.Decimal
+	Interest           decimal.Decimal
+	Principal          decimal.Decimal
+	RemainingPrincipal decimal.Decimal
+}
+
+type PayoffProjection struct {
+	MonthlyPayment decimal.Decimal
+	PaymentsLeft   int32
+	PayoffDate     time.Time
+	TotalInterest  decimal.Decimal
+	Schedule       []*AmortizationEntry
+}
+
+type LoanStatus struct {
+	Loan               *database.Loan
+	Currency           string
+	RemainingPrincipal decimal.Decimal
+	UnpaidInterest     decimal.Decimal
+	AccruedInterest    decimal.Decimal
+	Repayments         []*LoanRepayment
+	Projection         *PayoffProjection
+}
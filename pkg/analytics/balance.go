@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/samber/lo"
+)
+
+// GetBalanceHistory returns end of day balances of an account from daily_stat within the household
+// days of [From, To]. Week and month intervals keep the last recorded day of every period; daily_stat
+// dates are household days, so periods are household weeks and months.
+func (s *Service) GetBalanceHistory(ctx context.Context, req *BalanceHistoryRequest) ([]*BalancePoint, error) {
+	interval := lo.CoalesceOrEmpty(req.Interval, BalanceIntervalDay)
+	if !lo.Contains([]BalanceInterval{BalanceIntervalDay, BalanceIntervalWeek, BalanceIntervalMonth}, interval) {
+		return nil, errors.Newf("unsupported interval: %s", interval)
+	}
+
+	from := s.day(req.From)
+	to := s.day(req.To)
+
+	if from.After(to) {
+		return nil, errors.New("from cannot be after to")
+	}
+
+	db := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeReadonly))
+
+	var points []*BalancePoint
+	// date::timestamp truncates the calendar date, a bare date would be cast in the session timezone
+	if err := db.Raw(fmt.Sprintf(`select distinct on (date_trunc('%[1]s', date::timestamp)) date, amount as balance, holdings_value
+from daily_stat
+where account_id = ?
+  and date between ? and ?
+order by date_trunc('%[1]s', date::timestamp), date desc`, interval), // interval is validated above
+		req.AccountID,
+		from.Format(time.DateOnly),
+		to.Format(time.DateOnly),
+	).Scan(&points).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get balance history")
+	}
+
+	return points, nil
+}
@@ -0,0 +1,107 @@
+package analytics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/ft-t/go-money/pkg/analytics"
+	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_GetBalanceHistory(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	assert.NoError(t, gormDB.Create(&database.Account{
+		ID: 1, Name: "Bank", Currency: "USD", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET, Extra: map[string]string{},
+	}).Error)
+
+	day := time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, gormDB.Create([]*database.DailyStat{
+		{AccountID: 1, Date: day, Amount: decimal.NewFromInt(100)},
+		{AccountID: 1, Date: day.AddDate(0, 0, 1), Amount: decimal.NewFromInt(110)},
+		{AccountID: 1, Date: day.AddDate(0, 0, 2), Amount: decimal.NewFromInt(120)},
+		{AccountID: 1, Date: day.AddDate(0, 0, 3), Amount: decimal.NewFromInt(130)},
+	}).Error)
+
+	service := analytics.NewService(&analytics.ServiceConfig{
+		DecimalSvc:   currency.NewDecimalService(),
+		BaseCurrency: "USD",
+	})
+
+	t.Run("daily", func(t *testing.T) {
+		points, err := service.GetBalanceHistory(context.Background(), &analytics.BalanceHistoryRequest{
+			AccountID: 1,
+			From:      day.AddDate(0, 0, 1),
+			To:        day.AddDate(0, 0, 10),
+		})
+		assert.NoError(t, err)
+
+		assert.Len(t, points, 3)
+		assert.Equal(t, "110", points[0].Balance.String())
+		assert.Equal(t, "130", points[2].Balance.String())
+	})
+
+	t.Run("monthly keeps last day of period", func(t *testing.T) {
+		points, err := service.GetBalanceHistory(context.Background(), &analytics.BalanceHistoryRequest{
+			AccountID: 1,
+			From:      day,
+			To:        day.AddDate(0, 0, 10),
+			Interval:  analytics.BalanceIntervalMonth,
+		})
+		assert.NoError(t, err)
+
+		assert.Len(t, points, 2)
+		assert.Equal(t, "110", points[0].Balance.String())
+		assert.Equal(t, day.AddDate(0, 0, 1), points[0].Date.UTC())
+		assert.Equal(t, "130", points[1].Balance.String())
+	})
+
+	t.Run("days of the household timezone", func(t *testing.T) {
+		tokyo, err := time.LoadLocation("Asia/Tokyo")
+		assert.NoError(t, err)
+
+		tokyoSvc := analytics.NewService(&analytics.ServiceConfig{
+			DecimalSvc:   currency.NewDecimalService(),
+			BaseCurrency: "USD",
+			Location:     tokyo,
+		})
+
+		// still january 30 and february 1 in UTC
+		points, err := tokyoSvc.GetBalanceHistory(context.Background(), &analytics.BalanceHistoryRequest{
+			AccountID: 1,
+			From:      time.Date(2026, 1, 31, 0, 0, 0, 0, tokyo),
+			To:        time.Date(2026, 2, 1, 23, 59, 59, 0, tokyo),
+		})
+		assert.NoError(t, err)
+
+		assert.Len(t, points, 2)
+		assert.Equal(t, "110", points[0].Balance.String())
+		assert.Equal(t, "120", points[1].Balance.String())
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		_, err := service.GetBalanceHistory(context.Background(), &analytics.BalanceHistoryRequest{
+			AccountID: 1,
+			From:      day,
+			To:        day,
+			Interval:  "year",
+		})
+		assert.ErrorContains(t, err, "unsupported interval")
+	})
+
+	t.Run("from after to", func(t *testing.T) {
+		_, err := service.GetBalanceHistory(context.Background(), &analytics.BalanceHistoryRequest{
+			AccountID: 1,
+			From:      day.AddDate(0, 0, 1),
+			To:        day,
+		})
+		assert.ErrorContains(t, err, "from cannot be after to")
+	})
+}
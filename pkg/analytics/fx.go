@@ -0,0 +1,394 @@
+package analytics
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetFxGainLoss splits the effect of currency movements over [From, To] into unrealized revaluation
+// of foreign currency asset and liability accounts and realized gains of currency exchanges
+// (transfers between currencies and expenses paid in another currency). Balances and flows are
+// valued at historical rates: a rate override covering the day, else the latest synced rate on or
+// before the day, else the current rate. From and To are resolved to household days.
+func (s *Service) GetFxGainLoss(ctx context.Context, req *FxGainLossRequest) (*FxGainLoss, error) {
+	if req.From.IsZero() || req.To.IsZero() {
+		return nil, errors.New("from and to are required")
+	}
+
+	from := s.day(req.From)
+	to := s.day(req.To)
+
+	if from.After(to) {
+		return nil, errors.New("from cannot be after to")
+	}
+
+	db := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeReadonly))
+
+	accounts, err := s.getFxAccounts(db, req.AccountIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	exchanges, err := s.getFxExchanges(db, from, to, req.AccountIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := lo.Map(accounts, func(a *database.Account, _ int) string { return a.Currency })
+	for _, tx := range exchanges {
+		currencies = append(currencies, tx.SourceCurrency, tx.DestinationCurrency, tx.FxSourceCurrency)
+	}
+
+	rates, err := s.loadRateBook(db, lo.Uniq(lo.Compact(currencies)), to)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals := s.cfg.DecimalSvc.GetCurrencyDecimals(ctx, s.cfg.BaseCurrency)
+	result := &FxGainLoss{
+		From:         from,
+		To:           to,
+		BaseCurrency: s.cfg.BaseCurrency,
+	}
+
+	revaluations, err := s.revalueAccounts(db, rates, accounts, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range revaluations {
+		r.OpeningValue = r.OpeningValue.Round(decimals)
+		r.ClosingValue = r.ClosingValue.Round(decimals)
+		r.Flows = r.Flows.Round(decimals)
+		r.Unrealized = r.Unrealized.Round(decimals)
+
+		result.Unrealized = result.Unrealized.Add(r.Unrealized)
+	}
+
+	result.Accounts = revaluations
+
+	for _, tx := range exchanges {
+		gain, gainErr := realizedGain(rates, tx)
+		if gainErr != nil {
+			return nil, gainErr
+		}
+
+		gain.GivenValue = gain.GivenValue.Round(decimals)
+		gain.ReceivedValue = gain.ReceivedValue.Round(decimals)
+		gain.Gain = gain.ReceivedValue.Sub(gain.GivenValue)
+
+		result.RealizedGain = result.RealizedGain.Add(gain.Gain)
+		result.Realized = append(result.Realized, gain)
+	}
+
+	result.Total = result.Unrealized.Add(result.RealizedGain)
+
+	if req.PostRevaluations {
+		if err = s.postRevaluations(ctx, from, to, revaluations); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (s *Service) getFxAccounts(db *gorm.DB, accountIDs []int32) ([]*database.Account, error) {
+	query := db.Where("type in ? and currency <> ?", []gomoneypbv1.AccountType{
+		gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET,
+		gomoneypbv1.AccountType_ACCOUNT_TYPE_LIABILITY,
+	}, s.cfg.BaseCurrency)
+
+	if len(accountIDs) > 0 {
+		query = query.Where("id in ?", accountIDs)
+	}
+
+	var accounts []*database.Account
+	if err := query.Order("id").Find(&accounts).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get accounts")
+	}
+
+	return accounts, nil
+}
+
+func (s *Service) getFxExchanges(db *gorm.DB, from, to time.Time, accountIDs []int32) ([]*database.Transaction, error) {
+	query := db.Where("transaction_date_only >= ? and transaction_date_only <= ?", from, to).
+		Where(`(transaction_type = ? and source_currency <> destination_currency)
+or (transaction_type = ? and fx_source_amount is not null and fx_source_currency <> '' and fx_source_currency <> source_currency)`,
+			gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+			gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+		)
+
+	if len(accountIDs) > 0 {
+		query = query.Where("source_account_id in ?", accountIDs)
+	}
+
+	var txs []*database.Transaction
+	if err := query.Order("transaction_date_only, id").Find(&txs).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get exchange transactions")
+	}
+
+	return txs, nil
+}
+
+func (s *Service) revalueAccounts(
+	db *gorm.DB,
+	rates *rateBook,
+	accounts []*database.Account,
+	from, to time.Time,
+) ([]*FxAccountRevaluation, error) {
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+
+	accountIDs := lo.Map(accounts, func(a *database.Account, _ int) int32 { return a.ID })
+	openingDay := from.AddDate(0, 0, -1)
+
+	var opening []*database.DailyStat
+	if err := db.Raw(`select distinct on (account_id) account_id, date, amount
+from daily_stat
+where account_id in ? and date <= ?
+order by account_id, date desc`, accountIDs, openingDay).Scan(&opening).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get opening balances")
+	}
+
+	var stats []*database.DailyStat
+	if err := db.Where("account_id in ? and date >= ? and date <= ?", accountIDs, from, to).
+		Order("account_id, date").Find(&stats).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get daily stats")
+	}
+
+	openingByAccount := lo.SliceToMap(opening, func(st *database.DailyStat) (int32, decimal.Decimal) {
+		return st.AccountID, st.Amount
+	})
+	statsByAccount := lo.GroupBy(stats, func(st *database.DailyStat) int32 { return st.AccountID })
+
+	var revaluations []*FxAccountRevaluation
+	for _, acc := range accounts {
+		if openingByAccount[acc.ID].IsZero() && len(statsByAccount[acc.ID]) == 0 {
+			continue
+		}
+
+		openingRate, err := rates.rate(acc.Currency, openingDay)
+		if err != nil {
+			return nil, err
+		}
+
+		closingRate, err := rates.rate(acc.Currency, to)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &FxAccountRevaluation{
+			AccountID:      acc.ID,
+			Currency:       acc.Currency,
+			OpeningBalance: openingByAccount[acc.ID],
+			OpeningRate:    openingRate,
+			ClosingRate:    closingRate,
+		}
+
+		balance := r.OpeningBalance
+		for _, st := range statsByAccount[acc.ID] {
+			flow := st.Amount.Sub(balance)
+			balance = st.Amount
+
+			if flow.IsZero() {
+				continue
+			}
+
+			rate, rateErr := rates.rate(acc.Currency, st.Date)
+			if rateErr != nil {
+				return nil, rateErr
+			}
+
+			r.Flows = r.Flows.Add(flow.Div(rate))
+		}
+
+		r.ClosingBalance = balance
+		r.OpeningValue = r.OpeningBalance.Div(openingRate)
+		r.ClosingValue = r.ClosingBalance.Div(closingRate)
+		r.Unrealized = r.ClosingValue.Sub(r.OpeningValue).Sub(r.Flows)
+
+		if acc.Type == gomoneypbv1.AccountType_ACCOUNT_TYPE_LIABILITY { // a higher value owed is a loss
+			r.Unrealized = r.Unrealized.Neg()
+		}
+
+		revaluations = append(revaluations, r)
+	}
+
+	return revaluations, nil
+}
+
+func realizedGain(rates *rateBook, tx *database.Transaction) (*FxRealizedGain, error) {
+	gain := &FxRealizedGain{
+		TransactionID:                tx.ID,
+		AccountID:                    tx.SourceAccountID,
+		Date:                         tx.TransactionDateOnly,
+		GivenCurrency:                tx.SourceCurrency,
+		GivenAmount:                  tx.SourceAmount.Decimal.Abs(),
+		ReceivedCurrency:             tx.DestinationCurrency,
+		ReceivedAmount:               tx.DestinationAmount.Decimal.Abs(),
+		RecordedAmountInBaseCurrency: tx.DestinationAmountInBaseCurrency.Decimal.Abs(),
+	}
+
+	if tx.TransactionType == gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE {
+		gain.ReceivedCurrency = tx.FxSourceCurrency
+		gain.ReceivedAmount = tx.FxSourceAmount.Decimal.Abs()
+	}
+
+	givenRate, err := rates.rate(gain.GivenCurrency, gain.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	receivedRate, err := rates.rate(gain.ReceivedCurrency, gain.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	gain.GivenValue = gain.GivenAmount.Div(givenRate)
+	gain.ReceivedValue = gain.ReceivedAmount.Div(receivedRate)
+
+	return gain, nil
+}
+
+func (s *Service) postRevaluations(
+	ctx context.Context,
+	from, to time.Time,
+	revaluations []*FxAccountRevaluation,
+) error {
+	if len(revaluations) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	records := lo.Map(revaluations, func(r *FxAccountRevaluation, _ int) *database.FxRevaluation {
+		return &database.FxRevaluation{
+			AccountID:   r.AccountID,
+			Currency:    r.Currency,
+			PeriodFrom:  from,
+			PeriodTo:    to,
+			OpeningRate: r.OpeningRate,
+			ClosingRate: r.ClosingRate,
+			Amount:      r.Unrealized,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	})
+
+	db := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeMaster))
+
+	if err := db.Clauses(clause.OnConflict{
+		OnConstraint: "fx_revaluations_account_period",
+		DoUpdates: clause.AssignmentColumns([]string{
+			"currency", "period_from", "opening_rate", "closing_rate", "amount", "updated_at",
+		}),
+	}).Create(&records).Error; err != nil {
+		return errors.Wrap(err, "failed to post fx revaluations")
+	}
+
+	return nil
+}
+
+type datedRate struct {
+	Date time.Time
+	Rate decimal.Decimal
+}
+
+// rateBook resolves the rate vs base currency of a currency on a day.
+type rateBook struct {
+	baseCurrency string
+	current      map[string]decimal.Decimal
+	history      map[string][]datedRate // ascending by date
+	overrides    map[string][]*database.CurrencyRateOverride
+}
+
+func (s *Service) loadRateBook(db *gorm.DB, currencies []string, to time.Time) (*rateBook, error) {
+	book := &rateBook{
+		baseCurrency: s.cfg.BaseCurrency,
+		current:      map[string]decimal.Decimal{},
+		history:      map[string][]datedRate{},
+		overrides:    map[string][]*database.CurrencyRateOverride{},
+	}
+
+	if len(currencies) == 0 {
+		return book, nil
+	}
+
+	var current []*database.Currency
+	if err := db.Where("id in ?", currencies).Find(&current).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get currencies")
+	}
+
+	for _, c := range current {
+		book.current[c.ID] = c.Rate
+	}
+
+	var history []*database.CurrencyRateHistory
+	if err := db.Where("currency_id in ? and date <= ?", currencies, to).
+		Order("currency_id, date").Find(&history).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get rate history")
+	}
+
+	for _, h := range history {
+		book.history[h.CurrencyID] = append(book.history[h.CurrencyID], datedRate{Date: h.Date, Rate: h.Rate})
+	}
+
+	var overrides []*database.CurrencyRateOverride
+	if err := db.Where("currency_id in ?", currencies).
+		Order("valid_from desc, id desc").Find(&overrides).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get rate overrides")
+	}
+
+	for _, o := range overrides {
+		book.overrides[o.CurrencyID] = append(book.overrides[o.CurrencyID], o)
+	}
+
+	return book, nil
+}
+
+func (b *rateBook) rate(currency string, day time.Time) (decimal.Decimal, error) {
+	if currency == b.baseCurrency {
+		return decimal.NewFromInt(1), nil
+	}
+
+	rate, ok := b.lookup(currency, truncateDay(day))
+	if !ok || rate.IsZero() {
+		return decimal.Zero, errors.Newf("no exchange rate for %s on %s", currency, day.Format(time.DateOnly))
+	}
+
+	return rate, nil
+}
+
+func (b *rateBook) lookup(currency string, day time.Time) (decimal.Decimal, bool) {
+	for _, o := range b.overrides[currency] {
+		if !o.ValidFrom.After(day) && (o.ValidTo == nil || !o.ValidTo.Before(day)) {
+			return o.Rate, true
+		}
+	}
+
+	history := b.history[currency]
+	idx := sort.Search(len(history), func(i int) bool {
+		return history[i].Date.After(day)
+	})
+
+	if idx > 0 {
+		return history[idx-1].Rate, true
+	}
+
+	rate, ok := b.current[currency]
+
+	return rate, ok
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
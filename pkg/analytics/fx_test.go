@@ -0,0 +1,184 @@
+package analytics_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/ft-t/go-money/pkg/analytics"
+	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func seedFxData(t *testing.T) {
+	require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	require.NoError(t, gormDB.Create([]*database.Currency{
+		{ID: "USD", Rate: decimal.NewFromInt(1), DecimalPlaces: 2},
+		{ID: "EUR", Rate: decimal.RequireFromString("0.95"), DecimalPlaces: 2},
+		{ID: "PLN", Rate: decimal.RequireFromString("3.9"), DecimalPlaces: 2},
+	}).Error)
+
+	require.NoError(t, gormDB.Create([]*database.CurrencyRateHistory{
+		{CurrencyID: "EUR", Date: date(2026, 1, 31), Rate: decimal.RequireFromString("0.8")},
+		{CurrencyID: "EUR", Date: date(2026, 2, 10), Rate: decimal.RequireFromString("0.85")},
+		{CurrencyID: "EUR", Date: date(2026, 2, 28), Rate: decimal.RequireFromString("0.9")},
+		{CurrencyID: "PLN", Date: date(2026, 2, 1), Rate: decimal.RequireFromString("3.7")},
+	}).Error)
+
+	require.NoError(t, gormDB.Create(&database.CurrencyRateOverride{
+		CurrencyID: "PLN",
+		Rate:       decimal.NewFromInt(4),
+		ValidFrom:  date(2026, 2, 15),
+	}).Error)
+
+	require.NoError(t, gormDB.Create([]*database.Account{
+		{ID: 1, Name: "EUR savings", Currency: "EUR", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET, Extra: map[string]string{}},
+		{ID: 2, Name: "USD checking", Currency: "USD", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET, Extra: map[string]string{}},
+		{ID: 3, Name: "EUR card", Currency: "EUR", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_LIABILITY, Extra: map[string]string{}},
+		{ID: 4, Name: "PLN cash", Currency: "PLN", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET, Extra: map[string]string{}},
+	}).Error)
+
+	require.NoError(t, gormDB.Create([]*database.DailyStat{
+		{AccountID: 1, Date: date(2026, 1, 20), Amount: decimal.NewFromInt(800)},
+		{AccountID: 1, Date: date(2026, 2, 10), Amount: decimal.NewFromInt(1145)},
+		{AccountID: 2, Date: date(2026, 1, 20), Amount: decimal.NewFromInt(5000)},
+		{AccountID: 2, Date: date(2026, 2, 10), Amount: decimal.NewFromInt(4600)},
+		{AccountID: 3, Date: date(2026, 1, 1), Amount: decimal.NewFromInt(80)},
+	}).Error)
+
+	require.NoError(t, gormDB.Create([]*database.Transaction{
+		{
+			TransactionType:                 gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+			SourceAccountID:                 2,
+			SourceCurrency:                  "USD",
+			SourceAmount:                    decimal.NewNullDecimal(decimal.NewFromInt(-400)),
+			DestinationAccountID:            1,
+			DestinationCurrency:             "EUR",
+			DestinationAmount:               decimal.NewNullDecimal(decimal.NewFromInt(345)),
+			DestinationAmountInBaseCurrency: decimal.NewNullDecimal(decimal.NewFromInt(400)),
+			TransactionDateTime:             date(2026, 2, 10),
+			TransactionDateOnly:             date(2026, 2, 10),
+			Extra:                           map[string]string{},
+		},
+		{
+			TransactionType:     gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+			SourceAccountID:     1,
+			SourceCurrency:      "EUR",
+			SourceAmount:        decimal.NewNullDecimal(decimal.NewFromInt(-10)),
+			FxSourceCurrency:    "PLN",
+			FxSourceAmount:      decimal.NewNullDecimal(decimal.NewFromInt(-47)),
+			DestinationCurrency: "EUR",
+			DestinationAmount:   decimal.NewNullDecimal(decimal.NewFromInt(10)),
+			TransactionDateTime: date(2026, 2, 20),
+			TransactionDateOnly: date(2026, 2, 20),
+			Extra:               map[string]string{},
+		},
+		{
+			TransactionType:      gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+			SourceAccountID:      2,
+			SourceCurrency:       "USD",
+			SourceAmount:         decimal.NewNullDecimal(decimal.NewFromInt(-5)),
+			DestinationAccountID: 2,
+			DestinationCurrency:  "USD",
+			DestinationAmount:    decimal.NewNullDecimal(decimal.NewFromInt(5)),
+			TransactionDateTime:  date(2026, 2, 11),
+			TransactionDateOnly:  date(2026, 2, 11),
+			Extra:                map[string]string{},
+		},
+	}).Error)
+}
+
+func TestService_GetFxGainLoss(t *testing.T) {
+	service := analytics.NewService(&analytics.ServiceConfig{
+		DecimalSvc:   currency.NewDecimalService(),
+		BaseCurrency: "USD",
+	})
+
+	t.Run("unrealized and realized", func(t *testing.T) {
+		seedFxData(t)
+
+		report, err := service.GetFxGainLoss(context.TODO(), &analytics.FxGainLossRequest{
+			From: date(2026, 2, 1),
+			To:   date(2026, 2, 28),
+		})
+		require.NoError(t, err)
+
+		require.Len(t, report.Accounts, 2) // USD and empty PLN accounts are skipped
+
+		savings := report.Accounts[0]
+		assert.EqualValues(t, 1, savings.AccountID)
+		assert.Equal(t, "800", savings.OpeningBalance.String())
+		assert.Equal(t, "1145", savings.ClosingBalance.String())
+		assert.Equal(t, "0.8", savings.OpeningRate.String())
+		assert.Equal(t, "0.9", savings.ClosingRate.String())
+		assert.Equal(t, "1000", savings.OpeningValue.String())
+		assert.Equal(t, "1272.22", savings.ClosingValue.String())
+		assert.Equal(t, "405.88", savings.Flows.String())
+		assert.Equal(t, "-133.66", savings.Unrealized.String())
+
+		card := report.Accounts[1]
+		assert.EqualValues(t, 3, card.AccountID)
+		assert.Equal(t, "11.11", card.Unrealized.String()) // owing less in base currency is a gain
+
+		require.Len(t, report.Realized, 2)
+		assert.Equal(t, "400", report.Realized[0].GivenValue.String())
+		assert.Equal(t, "405.88", report.Realized[0].ReceivedValue.String())
+		assert.Equal(t, "5.88", report.Realized[0].Gain.String())
+		assert.Equal(t, "400", report.Realized[0].RecordedAmountInBaseCurrency.String())
+
+		assert.EqualValues(t, 1, report.Realized[1].AccountID)
+		assert.Equal(t, "PLN", report.Realized[1].ReceivedCurrency)
+		assert.Equal(t, "11.76", report.Realized[1].GivenValue.String())
+		assert.Equal(t, "11.75", report.Realized[1].ReceivedValue.String()) // override beats history
+
+		assert.Equal(t, "-122.55", report.Unrealized.String())
+		assert.Equal(t, "5.87", report.RealizedGain.String())
+		assert.Equal(t, "-116.68", report.Total.String())
+
+		var posted int64
+		require.NoError(t, gormDB.Model(&database.FxRevaluation{}).Count(&posted).Error)
+		assert.EqualValues(t, 0, posted)
+	})
+
+	t.Run("filtered and posted", func(t *testing.T) {
+		seedFxData(t)
+
+		for i := 0; i < 2; i++ {
+			report, err := service.GetFxGainLoss(context.TODO(), &analytics.FxGainLossRequest{
+				From:             date(2026, 2, 1),
+				To:               date(2026, 2, 28),
+				AccountIDs:       []int32{3},
+				PostRevaluations: true,
+			})
+			require.NoError(t, err)
+
+			require.Len(t, report.Accounts, 1)
+			assert.Empty(t, report.Realized)
+		}
+
+		var posted []*database.FxRevaluation
+		require.NoError(t, gormDB.Find(&posted).Error)
+		require.Len(t, posted, 1)
+		assert.EqualValues(t, 3, posted[0].AccountID)
+		assert.Equal(t, "11.11", posted[0].Amount.String())
+		assert.Equal(t, "0.8", posted[0].OpeningRate.String())
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		_, err := service.GetFxGainLoss(context.TODO(), &analytics.FxGainLossRequest{
+			From: date(2026, 3, 1),
+			To:   date(2026, 2, 1),
+		})
+		assert.ErrorContains(t, err, "from cannot be after to")
+	})
+}
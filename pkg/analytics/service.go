@@ -5,8 +5,10 @@ import (
 	"time"
 
 	analyticsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/analytics/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
 	"github.com/cockroachdb/errors"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/timezone"
 	"github.com/shopspring/decimal"
 )
 
@@ -17,6 +19,7 @@ type Service struct {
 type ServiceConfig struct {
 	DecimalSvc   DecimalSvc
 	BaseCurrency string
+	Location     *time.Location // household timezone report days are resolved in, UTC when nil
 }
 
 func NewService(cfg *ServiceConfig) *Service {
@@ -122,3 +125,92 @@ func (s *Service) calculateAccountSummary(
 
 	return summaries, nil
 }
+
+// GetNetWorth sums asset and liability accounts in base currency at the end of the household day
+// of date from daily_stat, using holdings value for investment accounts.
+func (s *Service) GetNetWorth(ctx context.Context, date time.Time) (*NetWorth, error) {
+	db := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeReadonly))
+	date = s.day(date)
+
+	type result struct {
+		AccountID     int32                   `gorm:"column:account_id"`
+		Type          gomoneypbv1.AccountType `gorm:"column:type"`
+		Currency      string                  `gorm:"column:currency"`
+		Balance       decimal.Decimal         `gorm:"column:balance"`
+		HoldingsValue decimal.NullDecimal     `gorm:"column:holdings_value"`
+		Rate          decimal.NullDecimal     `gorm:"column:rate"`
+	}
+
+	var results []result
+	if err := db.Raw(`select a.id as account_id, a.type, a.currency,
+       coalesce(st.amount, 0) as balance, st.holdings_value, c.rate
+from accounts a
+         left join lateral (select ds.amount, ds.holdings_value
+                            from daily_stat ds
+                            where ds.account_id = a.id
+                              and ds.date <= ?
+                            order by ds.date desc
+                            limit 1) st on true
+         left join currencies c on c.id = a.currency
+where a.type in ? and a.deleted_at is null
+order by a.id`,
+		date.Format(time.DateOnly),
+		[]gomoneypbv1.AccountType{
+			gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET,
+			gomoneypbv1.AccountType_ACCOUNT_TYPE_LIABILITY,
+		},
+	).Scan(&results).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	decimals := s.cfg.DecimalSvc.GetCurrencyDecimals(ctx, s.cfg.BaseCurrency)
+	netWorth := &NetWorth{
+		Date: date,
+	}
+
+	for _, r := range results {
+		valuation := &AccountValuation{
+			AccountID:     r.AccountID,
+			Currency:      r.Currency,
+			Balance:       r.Balance,
+			HoldingsValue: r.HoldingsValue,
+			Value:         r.Balance,
+		}
+
+		if r.HoldingsValue.Valid {
+			valuation.Value = r.HoldingsValue.Decimal
+		}
+
+		valuation.ValueInBaseCurrency = valuation.Value
+		if r.Currency != s.cfg.BaseCurrency {
+			if !r.Rate.Valid || r.Rate.Decimal.IsZero() {
+				return nil, errors.Newf("no exchange rate for %s", r.Currency)
+			}
+
+			valuation.ValueInBaseCurrency = valuation.Value.Div(r.Rate.Decimal)
+		}
+
+		valuation.ValueInBaseCurrency = valuation.ValueInBaseCurrency.Round(decimals)
+
+		if r.Type == gomoneypbv1.AccountType_ACCOUNT_TYPE_LIABILITY {
+			netWorth.Liabilities = netWorth.Liabilities.Add(valuation.ValueInBaseCurrency)
+		} else {
+			netWorth.Assets = netWorth.Assets.Add(valuation.ValueInBaseCurrency)
+		}
+
+		if r.HoldingsValue.Valid {
+			netWorth.Holdings = netWorth.Holdings.Add(valuation.ValueInBaseCurrency)
+		}
+
+		netWorth.Accounts = append(netWorth.Accounts, valuation)
+	}
+
+	netWorth.NetWorth = netWorth.Assets.Sub(netWorth.Liabilities)
+
+	return netWorth, nil
+}
+
+// day returns the household calendar day of t in the midnight UTC form of date columns.
+func (s *Service) day(t time.Time) time.Time {
+	return timezone.DateOnly(t, s.cfg.Location)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	analyticsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/analytics/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
 	"github.com/cockroachdb/errors"
 	"github.com/ft-t/go-money/pkg/analytics"
 	"github.com/ft-t/go-money/pkg/configuration"
@@ -255,3 +256,50 @@ func TestService_GetDebitsAndCreditsSummary(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to calculate account summary")
 	})
 }
+
+func TestService_GetNetWorth(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	assert.NoError(t, gormDB.Create([]*database.Currency{
+		{ID: "USD", Rate: decimal.NewFromInt(1), DecimalPlaces: 2},
+		{ID: "EUR", Rate: decimal.RequireFromString("0.5"), DecimalPlaces: 2},
+	}).Error)
+
+	accounts := []*database.Account{
+		{ID: 1, Name: "Bank", Currency: "USD", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET, Extra: map[string]string{}},
+		{ID: 2, Name: "Broker", Currency: "EUR", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET, Extra: map[string]string{}},
+		{ID: 3, Name: "Card", Currency: "USD", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_LIABILITY, Extra: map[string]string{}},
+		{ID: 4, Name: "Food", Currency: "USD", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_EXPENSE, Extra: map[string]string{}},
+	}
+	assert.NoError(t, gormDB.Create(accounts).Error)
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, gormDB.Create([]*database.DailyStat{
+		{AccountID: 1, Date: day, Amount: decimal.NewFromInt(1000)},
+		{AccountID: 1, Date: day.AddDate(0, 0, 5), Amount: decimal.NewFromInt(1200)},
+		{AccountID: 2, Date: day, Amount: decimal.NewFromInt(400),
+			HoldingsValue: decimal.NewNullDecimal(decimal.NewFromInt(450))},
+		{AccountID: 3, Date: day, Amount: decimal.NewFromInt(300)},
+		{AccountID: 4, Date: day, Amount: decimal.NewFromInt(50)},
+	}).Error)
+
+	service := analytics.NewService(&analytics.ServiceConfig{
+		DecimalSvc:   currency.NewDecimalService(),
+		BaseCurrency: "USD",
+	})
+
+	netWorth, err := service.GetNetWorth(context.Background(), day.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+
+	assert.Len(t, netWorth.Accounts, 3)
+	assert.Equal(t, "1900", netWorth.Assets.String())
+	assert.Equal(t, "300", netWorth.Liabilities.String())
+	assert.Equal(t, "900", netWorth.Holdings.String())
+	assert.Equal(t, "1600", netWorth.NetWorth.String())
+	assert.Equal(t, "450", netWorth.Accounts[1].Value.String())
+
+	netWorth, err = service.GetNetWorth(context.Background(), day.AddDate(0, 0, 5))
+	assert.NoError(t, err)
+	assert.Equal(t, "1800", netWorth.NetWorth.String())
+}
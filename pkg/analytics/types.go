@@ -2,6 +2,8 @@ package analytics
 
 import (
 	"context"
+	"time"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -12,6 +14,100 @@ type AccountSummary struct {
 	CreditsCount int32
 }
 
+// AccountValuation is the end of day value of an asset or liability account. Investment accounts
+// are valued at the market value of their holdings instead of the balance.
+type AccountValuation struct {
+	AccountID           int32
+	Currency            string
+	Balance             decimal.Decimal
+	HoldingsValue       decimal.NullDecimal
+	Value               decimal.Decimal
+	ValueInBaseCurrency decimal.Decimal
+}
+
+type NetWorth struct {
+	Date        time.Time
+	Assets      decimal.Decimal
+	Liabilities decimal.Decimal
+	Holdings    decimal.Decimal // part of assets held in securities
+	NetWorth    decimal.Decimal
+	Accounts    []*AccountValuation
+}
+
 type DecimalSvc interface {
 	ToString(ctx context.Context, amount decimal.Decimal, currency string) string
+	GetCurrencyDecimals(ctx context.Context, currency string) int32
+}
+
+type FxGainLossRequest struct {
+	From       time.Time
+	To         time.Time
+	AccountIDs []int32 // all asset and liability accounts not in base currency when empty
+	// PostRevaluations stores the unrealized result of every account in fx_revaluations.
+	PostRevaluations bool
+}
+
+// FxAccountRevaluation is the unrealized fx gain or loss of a foreign currency account:
+// the change of its base currency value not explained by flows valued at the rate of their day.
+type FxAccountRevaluation struct {
+	AccountID      int32
+	Currency       string
+	OpeningBalance decimal.Decimal // end of the day before From, account currency
+	ClosingBalance decimal.Decimal // end of To, account currency
+	OpeningRate    decimal.Decimal
+	ClosingRate    decimal.Decimal
+	OpeningValue   decimal.Decimal // base currency
+	ClosingValue   decimal.Decimal // base currency
+	Flows          decimal.Decimal // base currency
+	Unrealized     decimal.Decimal // base currency, positive is a gain
+}
+
+// FxRealizedGain compares what was received and what was given by a currency exchange,
+// both valued at the rates of the transaction day.
+type FxRealizedGain struct {
+	TransactionID                int64
+	AccountID                    int32 // account the given currency left
+	Date                         time.Time
+	GivenCurrency                string
+	GivenAmount                  decimal.Decimal
+	ReceivedCurrency             string
+	ReceivedAmount               decimal.Decimal
+	RecordedAmountInBaseCurrency decimal.Decimal
+	GivenValue                   decimal.Decimal // base currency
+	ReceivedValue                decimal.Decimal // base currency
+	Gain                         decimal.Decimal // base currency, positive is a gain
+}
+
+type FxGainLoss struct {
+	From         time.Time
+	To           time.Time
+	BaseCurrency string
+	Accounts     []*FxAccountRevaluation
+	Realized     []*FxRealizedGain
+	Unrealized   decimal.Decimal
+	RealizedGain decimal.Decimal
+	Total        decimal.Decimal
+}
+
+type BalanceInterval string
+
+const (
+	BalanceIntervalDay   BalanceInterval = "day"
+	BalanceIntervalWeek  BalanceInterval = "week"
+	BalanceIntervalMonth BalanceInterval = "month"
+)
+
+type BalanceHistoryRequest struct {
+	AccountID int32
+	From      time.Time
+	To        time.Time
+	Interval  BalanceInterval // day when empty
+}
+
+// BalancePoint is the balance of an account at the end of a day, for week and month intervals
+// the last recorded day of the period.
+type BalancePoint struct {
+	Date          time.Time
+	Balance       decimal.Decimal
+	HoldingsValue decimal.NullDecimal
 }
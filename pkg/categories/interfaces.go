@@ -7,6 +7,10 @@ import (
 )
 
 //go:generate mockgen -destination interfaces_mocks_test.go -package categories_test -source=interfaces.go
+type SuggestionsSvc interface {
+	Invalidate()
+}
+
 type Mapper interface {
 	MapCategory(ctx context.Context, category *database.Category) *gomoneypbv1.Category
 }
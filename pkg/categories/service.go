@@ -9,14 +9,19 @@ import (
 )
 
 type Service struct {
-	mapper Mapper
+	mapper         Mapper
+	suggestionsSvc SuggestionsSvc
 }
 
+// NewService creates the service, suggestionsSvc is optional and drops the suggestion model when
+// a category is deleted, so the deleted id is not suggested until the model expires.
 func NewService(
 	mapper Mapper,
+	suggestionsSvc SuggestionsSvc,
 ) *Service {
 	return &Service{
-		mapper: mapper,
+		mapper:         mapper,
+		suggestionsSvc: suggestionsSvc,
 	}
 }
 
@@ -78,6 +83,8 @@ func (s *Service) DeleteCategory(
 		return nil, err
 	}
 
+	s.invalidateSuggestions()
+
 	return &categoriesv1.DeleteCategoryResponse{
 		Category: s.mapper.MapCategory(ctx, &category),
 	}, nil
@@ -146,3 +153,11 @@ func (s *Service) UpdateCategory(
 		Category: s.mapper.MapCategory(ctx, &category),
 	}, nil
 }
+
+func (s *Service) invalidateSuggestions() {
+	if s.suggestionsSvc == nil {
+		return
+	}
+
+	s.suggestionsSvc.Invalidate()
+}
@@ -33,7 +33,7 @@ func TestCreateCategory(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		mapper.EXPECT().MapCategory(gomock.Any(), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, category *database.Category) *gomoneypbv1.Category {
@@ -56,7 +56,7 @@ func TestCreateCategory(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		category := &database.Category{
 			Name: "Duplicate Category",
@@ -78,7 +78,7 @@ func TestCreateCategory(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		category := &database.Category{
 			Name: "Duplicate Category",
@@ -102,7 +102,7 @@ func TestCreateCategory(t *testing.T) {
 		mockGorm, _, sql := testingutils.GormMock()
 
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		ctx := database.WithContext(context.TODO(), mockGorm)
 
@@ -124,7 +124,7 @@ func TestUpdateCategory(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		category := &database.Category{
 			Name: "Initial Category",
@@ -152,7 +152,7 @@ func TestUpdateCategory(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		resp, err := srv.UpdateCategory(context.TODO(), &categoriesv1.UpdateCategoryRequest{
 			Category: &gomoneypbv1.Category{
@@ -170,7 +170,7 @@ func TestUpdateCategory(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		category1 := &database.Category{
 			Name: "Category One",
@@ -198,7 +198,7 @@ func TestUpdateCategory(t *testing.T) {
 		mockGorm, _, sql := testingutils.GormMock()
 
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		ctx := database.WithContext(context.TODO(), mockGorm)
 
@@ -226,9 +226,12 @@ func TestDelete(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
-		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		ctrl := gomock.NewController(t)
+		mapper := NewMockMapper(ctrl)
+		suggestionsSvc := NewMockSuggestionsSvc(ctrl)
+		srv := categories.NewService(mapper, suggestionsSvc)
 
+		suggestionsSvc.EXPECT().Invalidate()
 		mapper.EXPECT().MapCategory(gomock.Any(), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, category *database.Category) *gomoneypbv1.Category {
 				assert.True(t, category.DeletedAt.Valid)
@@ -258,7 +261,7 @@ func TestDelete(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		resp, err := srv.DeleteCategory(context.TODO(), &categoriesv1.DeleteCategoryRequest{
 			Id: 9999,
@@ -272,8 +275,8 @@ func TestDelete(t *testing.T) {
 	t.Run("db error", func(t *testing.T) {
 		mockGorm, _, sql := testingutils.GormMock()
 
-		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		ctrl := gomock.NewController(t)
+		srv := categories.NewService(NewMockMapper(ctrl), NewMockSuggestionsSvc(ctrl)) // not invalidated
 
 		ctx := database.WithContext(context.TODO(), mockGorm)
 
@@ -312,7 +315,7 @@ func TestListing(t *testing.T) {
 
 	t.Run("include deleted", func(t *testing.T) {
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		mapper.EXPECT().MapCategory(gomock.Any(), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, category *database.Category) *gomoneypbv1.Category {
@@ -331,7 +334,7 @@ func TestListing(t *testing.T) {
 
 	t.Run("with ids", func(t *testing.T) {
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		mapper.EXPECT().MapCategory(gomock.Any(), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, category *database.Category) *gomoneypbv1.Category {
@@ -353,7 +356,7 @@ func TestListing(t *testing.T) {
 		mockGorm, _, sql := testingutils.GormMock()
 
 		mapper := NewMockMapper(gomock.NewController(t))
-		srv := categories.NewService(mapper)
+		srv := categories.NewService(mapper, nil)
 
 		ctx := database.WithContext(context.TODO(), mockGorm)
 
@@ -382,7 +385,7 @@ func TestGetAllCategories(t *testing.T) {
 		}
 		assert.NoError(t, gormDB.Create(&cats).Error)
 
-		srv := categories.NewService(NewMockMapper(gomock.NewController(t)))
+		srv := categories.NewService(NewMockMapper(gomock.NewController(t)), nil)
 
 		result, err := srv.GetAllCategories(context.TODO())
 
@@ -393,7 +396,7 @@ func TestGetAllCategories(t *testing.T) {
 	t.Run("db error", func(t *testing.T) {
 		mockGorm, _, sql := testingutils.GormMock()
 
-		srv := categories.NewService(NewMockMapper(gomock.NewController(t)))
+		srv := categories.NewService(NewMockMapper(gomock.NewController(t)), nil)
 
 		ctx := database.WithContext(context.TODO(), mockGorm)
 
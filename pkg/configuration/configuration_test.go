@@ -4,6 +4,7 @@ import (
 	"github.com/ft-t/go-money/pkg/configuration"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestGetConfig(t *testing.T) {
@@ -13,6 +14,18 @@ func TestGetConfig(t *testing.T) {
 		cfg := configuration.GetConfiguration()
 		assert.Contains(t, cfg.Db.Db, "ci_")
 		assert.Contains(t, cfg.ReadOnlyDb.Db, "ci_")
+		assert.Equal(t, "1 0 * * *", cfg.Jobs.FixDailyGapCron)
+		assert.True(t, cfg.Jobs.LeaderElection)
+		assert.Equal(t, "UTC", cfg.Timezone)
+		assert.Equal(t, 30*time.Second, cfg.MCP.QueryStatementTimeout)
+		assert.Equal(t, 262144, cfg.MCP.QueryMaxResponseBytes)
+		assert.Equal(t, time.Minute, cfg.MCP.RateLimitWindow)
+		assert.Equal(t, 120, cfg.MCP.RateLimitCalls)
+		assert.Equal(t, 2*365*24*time.Hour, cfg.Suggestions.Lookback)
+		assert.Equal(t, 10*time.Minute, cfg.Suggestions.CacheTTL)
+		assert.EqualValues(t, 7460514094, cfg.Events.SequenceLockKey)
+		assert.Equal(t, 8, cfg.Webhooks.MaxAttempts)
+		assert.Equal(t, 30*time.Second, cfg.Webhooks.BackoffBase)
 
 		cfg2 := configuration.GetConfiguration() // from var
 		assert.Equal(t, cfg, cfg2)
@@ -1,6 +1,10 @@
 package configuration
 
-import "github.com/ft-t/go-money/pkg/boilerplate"
+import (
+	"time"
+
+	"github.com/ft-t/go-money/pkg/boilerplate"
+)
 
 type Configuration struct {
 	Db                   boilerplate.DbConfig `env:", prefix=DB_"`
@@ -10,19 +14,86 @@ type Configuration struct {
 	JwtPrivateKey        string               `env:"JWT_PRIVATE_KEY"`
 	ExchangeRatesUrl     string               `env:"EXCHANGE_RATES_URL, default=http://go-money-exchange-rates.s3-website.eu-north-1.amazonaws.com/latest.json"`
 	StaticFilesDirectory string               `env:"STATIC_FILES_DIRECTORY"`
+	Timezone             string               `env:"TIMEZONE, default=UTC"` // IANA name of the household timezone, drives dates, daily stats and schedules
 	CurrencyConfig       CurrencyConfig       `env:", prefix=CURRENCY_CONFIG_"`
 	GrafanaConfig        GrafanaConfig        `env:", prefix=GRAFANA_CONFIG_"`
 	MCP                  MCPConfig            `env:", prefix=MCP_"`
+	Scheduler            SchedulerConfig      `env:", prefix=SCHEDULER_"`
+	Investments          InvestmentsConfig    `env:", prefix=INVESTMENTS_"`
+	Jobs                 JobsConfig           `env:", prefix=JOBS_"`
+	Suggestions          SuggestionsConfig    `env:", prefix=SUGGESTIONS_"`
+	Events               EventsConfig         `env:", prefix=EVENTS_"`
+	Webhooks             WebhooksConfig       `env:", prefix=WEBHOOKS_"`
+}
+
+type EventsConfig struct {
+	PollInterval    time.Duration `env:"POLL_INTERVAL, default=1s"`
+	SequenceLockKey int64         `env:"SEQUENCE_LOCK_KEY, default=7460514094"` // advisory lock held while committed events get their feed_id
+}
+
+type WebhooksConfig struct {
+	Disable        bool          `env:"DISABLE, default=false"`
+	PollInterval   time.Duration `env:"POLL_INTERVAL, default=5s"`
+	BatchSize      int           `env:"BATCH_SIZE, default=100"`
+	MaxAttempts    int           `env:"MAX_ATTEMPTS, default=8"` // delivery is marked failed after this many attempts
+	BackoffBase    time.Duration `env:"BACKOFF_BASE, default=30s"`
+	BackoffMax     time.Duration `env:"BACKOFF_MAX, default=1h"`
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT, default=10s"`
+}
+
+type SuggestionsConfig struct {
+	Lookback   time.Duration `env:"LOOKBACK, default=17520h"` // only transactions this recent are learned from
+	MaxSamples int           `env:"MAX_SAMPLES, default=50000"`
+	CacheTTL   time.Duration `env:"CACHE_TTL, default=10m"` // how long a learned model is reused before it is rebuilt
+}
+
+type JobsConfig struct {
+	UpdateExchangeRatesCron string `env:"UPDATE_EXCHANGE_RATES_CRON, default=10 12 * * *"` // should be in sync with sync-exchange-rates service
+	FixDailyGapCron         string `env:"FIX_DAILY_GAP_CRON, default=1 0 * * *"`
+	AccrueLoanInterestCron  string `env:"ACCRUE_LOAN_INTEREST_CRON, default=5 0 * * *"`
+	SyncSecurityPricesCron  string `env:"SYNC_SECURITY_PRICES_CRON, default=10 0 * * *"`
+	LeaderElection          bool   `env:"LEADER_ELECTION, default=true"` // only the replica holding the advisory lock runs jobs and schedule rules
+	LeaderLockKey           int64  `env:"LEADER_LOCK_KEY, default=7460514093"`
+}
+
+type InvestmentsConfig struct {
+	PriceProvider string `env:"PRICE_PROVIDER, default=manual"` // manual or csv
+	PricesCSVURL  string `env:"PRICES_CSV_URL"`                 // http(s) url or file path with symbol,date,price rows
+}
+
+type SchedulerConfig struct {
+	CatchUpPolicy  string        `env:"CATCH_UP_POLICY, default=all"` // none, latest or all runs missed since last_run_at
+	MaxCatchUpRuns int           `env:"MAX_CATCH_UP_RUNS, default=31"`
+	ReloadInterval time.Duration `env:"RELOAD_INTERVAL, default=30s"` // how often replicas pick up rules changed through another replica
 }
 
 type MCPConfig struct {
-	Disable bool   `env:"DISABLE, default=false"`
-	DocsDir string `env:"DOCS_DIR, default=./mcp"`
+	Disable               bool          `env:"DISABLE, default=false"`
+	DocsDir               string        `env:"DOCS_DIR, default=./mcp"`
+	QueryRole             string        `env:"QUERY_ROLE"`                               // optional restricted role the query tool switches to, must be granted to the app user
+	QueryStatementTimeout time.Duration `env:"QUERY_STATEMENT_TIMEOUT, default=30s"`     // postgres statement_timeout of query tool transactions
+	QueryExtraTables      []string      `env:"QUERY_EXTRA_TABLES"`                       // added to the query tool table allowlist
+	QueryExtraFunctions   []string      `env:"QUERY_EXTRA_FUNCTIONS"`                    // added to the query tool function allowlist
+	QueryMaxResponseBytes int           `env:"QUERY_MAX_RESPONSE_BYTES, default=262144"` // byte budget of one query tool page
+	DisableAuditLog       bool          `env:"DISABLE_AUDIT_LOG, default=false"`         // stop recording tool calls in mcp_tool_calls
+	RateLimitWindow       time.Duration `env:"RATE_LIMIT_WINDOW, default=1m"`
+	RateLimitCalls        int           `env:"RATE_LIMIT_CALLS, default=120"`   // tool calls per token and window, 0 disables
+	RateLimitRows         int           `env:"RATE_LIMIT_ROWS, default=100000"` // rows returned per token and window, 0 disables
 }
 
 type CurrencyConfig struct {
-	UpdateTransactionAmountInBaseCurrency bool   `env:"UPDATE_TRANSACTION_AMOUNT_IN_BASE_CURRENCY, default=false"`
-	BaseCurrency                          string `env:"BASE_CURRENCY, default=USD"`
+	UpdateTransactionAmountInBaseCurrency bool                `env:"UPDATE_TRANSACTION_AMOUNT_IN_BASE_CURRENCY, default=false"`
+	BaseCurrency                          string              `env:"BASE_CURRENCY, default=USD"`
+	RateProviders                         RateProvidersConfig `env:", prefix=RATE_PROVIDERS_"`
+}
+
+type RateProvidersConfig struct {
+	Default         []string          `env:"DEFAULT"`    // ordered provider names, e.g. remote,ecb
+	Currencies      map[string]string `env:"CURRENCIES"` // per-currency chains, e.g. PLN:nbp|ecb,UAH:nbu|remote
+	ECBURL          string            `env:"ECB_URL, default=https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"`
+	NBPURL          string            `env:"NBP_URL, default=https://api.nbp.pl/api/exchangerates/tables/A/?format=json"`
+	NBUURL          string            `env:"NBU_URL, default=https://bank.gov.ua/NBUStatService/v1/statdirectory/exchange?json"`
+	ExchangeRateURL string            `env:"EXCHANGE_RATE_URL"` // exchangerate-api.com latest endpoint, includes the API key
 }
 
 type GrafanaConfig struct {
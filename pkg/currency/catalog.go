@@ -0,0 +1,261 @@
+package currency
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var currencyCodeRegex = regexp.MustCompile(`^[A-Z0-9_]{2,10}$`)
+
+// CatalogService manages currency metadata and user defined pseudo currencies
+// (loyalty points, miles, gift cards) whose rates are maintained manually.
+type CatalogService struct {
+	cfg *CatalogServiceConfig
+}
+
+type CatalogServiceConfig struct {
+	BaseAmountSvc BaseAmountSvc
+	BaseCurrency  string
+}
+
+func NewCatalogService(cfg *CatalogServiceConfig) *CatalogService {
+	return &CatalogService{
+		cfg: cfg,
+	}
+}
+
+func (s *CatalogService) ListCurrencies(
+	ctx context.Context,
+	includeInactive bool,
+) ([]*database.Currency, error) {
+	query := database.GetDbWithContext(ctx, database.DbTypeReadonly).Order("id")
+	if !includeInactive {
+		query = query.Where("is_active = true")
+	}
+
+	var currencies []*database.Currency
+	if err := query.Find(&currencies).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to list currencies")
+	}
+
+	return currencies, nil
+}
+
+// UpsertCurrency creates a currency or updates its metadata. Missing metadata of ISO 4217
+// currencies is filled from the built-in table. Rates of existing currencies are not
+// changed here, see SetCustomRate.
+func (s *CatalogService) UpsertCurrency(
+	ctx context.Context,
+	req *UpsertCurrencyRequest,
+) (*database.Currency, error) {
+	id := strings.ToUpper(strings.TrimSpace(req.ID))
+	if !currencyCodeRegex.MatchString(id) {
+		return nil, errors.Newf("invalid currency code: %s", req.ID)
+	}
+
+	iso, isIso := LookupIso(id)
+
+	if req.IsCustom {
+		if isIso {
+			return nil, errors.Newf("%s is an ISO 4217 code, pseudo currencies need a distinct code", id)
+		}
+
+		if req.NumericCode != 0 {
+			return nil, errors.New("pseudo currencies can not have a numeric code")
+		}
+
+		if id == s.cfg.BaseCurrency {
+			return nil, errors.New("base currency can not be a pseudo currency")
+		}
+	}
+
+	if req.DecimalPlaces != nil && (*req.DecimalPlaces < 0 || *req.DecimalPlaces > 8) {
+		return nil, errors.New("decimal_places must be between 0 and 8")
+	}
+
+	tx := database.GetDbWithContext(ctx, database.DbTypeMaster).Begin()
+	defer tx.Rollback()
+
+	var record database.Currency
+	err := tx.Unscoped().Where("id = ?", id).First(&record).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.Wrap(err, "failed to get currency")
+	}
+
+	isNew := errors.Is(err, gorm.ErrRecordNotFound)
+
+	if isNew {
+		record = database.Currency{
+			ID:             id,
+			IsCustom:       req.IsCustom,
+			IsActive:       true,
+			Name:           iso.Name,
+			Symbol:         iso.Symbol,
+			SymbolPosition: iso.SymbolPosition,
+			NumericCode:    iso.NumericCode,
+			DecimalPlaces:  iso.DecimalPlaces,
+		}
+
+		if !isIso {
+			record.DecimalPlaces = configuration.DefaultDecimalPlaces
+		}
+
+		switch {
+		case id == s.cfg.BaseCurrency:
+			record.Rate = decimal.NewFromInt(1)
+		case req.Rate != nil:
+			if !req.Rate.IsPositive() {
+				return nil, errors.New("rate must be positive")
+			}
+
+			record.Rate = *req.Rate
+		case req.IsCustom:
+			return nil, errors.New("rate is required for pseudo currencies")
+		}
+	}
+
+	if req.Name != "" {
+		record.Name = req.Name
+	}
+
+	if req.Symbol != nil {
+		record.Symbol = *req.Symbol
+	}
+
+	if req.SymbolPosition != nil {
+		record.SymbolPosition = *req.SymbolPosition
+	}
+
+	if req.NumericCode != 0 {
+		record.NumericCode = req.NumericCode
+	}
+
+	if req.DecimalPlaces != nil {
+		record.DecimalPlaces = *req.DecimalPlaces
+	}
+
+	if req.IsActive != nil {
+		record.IsActive = *req.IsActive
+	}
+
+	record.UpdatedAt = time.Now().UTC()
+	record.DeletedAt = gorm.DeletedAt{}
+
+	if err = tx.Save(&record).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to save currency")
+	}
+
+	if isNew && record.IsCustom {
+		if err = s.recordCustomRate(tx, id, record.Rate, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &record, nil
+}
+
+// SetCustomRate records the rate of a pseudo currency for a day. currencies.rate follows the
+// latest recorded day; when it changes, base amounts of unlocked transactions are recalculated.
+func (s *CatalogService) SetCustomRate(
+	ctx context.Context,
+	req *SetCustomRateRequest,
+) (*database.Currency, error) {
+	id := strings.ToUpper(strings.TrimSpace(req.CurrencyID))
+
+	if !req.Rate.IsPositive() {
+		return nil, errors.New("rate must be positive")
+	}
+
+	date := req.Date
+	if date.IsZero() {
+		date = time.Now().UTC()
+	}
+
+	tx := database.GetDbWithContext(ctx, database.DbTypeMaster).Begin()
+	defer tx.Rollback()
+
+	var record database.Currency
+	if err := tx.Where("id = ?", id).First(&record).Error; err != nil {
+		return nil, errors.Wrapf(err, "currency %s not found", id)
+	}
+
+	if !record.IsCustom {
+		return nil, errors.Newf("rates of %s are synced, use a rate override instead", id)
+	}
+
+	if err := s.recordCustomRate(tx, id, req.Rate, date); err != nil {
+		return nil, err
+	}
+
+	var latest database.CurrencyRateHistory
+	if err := tx.Where("currency_id = ?", id).Order("date desc").First(&latest).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get latest rate")
+	}
+
+	if latest.Rate.Equal(record.Rate) {
+		if err := tx.Commit().Error; err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return &record, nil
+	}
+
+	record.Rate = latest.Rate
+	record.UpdatedAt = time.Now().UTC()
+
+	if err := tx.Save(&record).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to update currency rate")
+	}
+
+	var affected []*database.Transaction
+	if err := tx.Where("source_currency = ? AND NOT exchange_rate_locked", id).
+		Select("id").Find(&affected).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find transactions in currency")
+	}
+
+	if len(affected) > 0 {
+		if err := s.cfg.BaseAmountSvc.RecalculateAmountInBaseCurrency(ctx, tx, affected); err != nil {
+			return nil, errors.Wrap(err, "failed to recalculate amounts in base currency")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &record, nil
+}
+
+func (s *CatalogService) recordCustomRate(
+	tx *gorm.DB,
+	currencyID string,
+	rate decimal.Decimal,
+	date time.Time,
+) error {
+	if err := tx.Clauses(clause.OnConflict{
+		OnConstraint: "currency_rate_history_pk",
+		DoUpdates:    clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(&database.CurrencyRateHistory{
+		CurrencyID: currencyID,
+		Date:       truncateDay(date),
+		Rate:       rate,
+		UpdatedAt:  time.Now().UTC(),
+	}).Error; err != nil {
+		return errors.Wrap(err, "failed to record rate history")
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package currency_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/golang/mock/gomock"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCatalogService_UpsertCurrency(t *testing.T) {
+	svc := currency.NewCatalogService(&currency.CatalogServiceConfig{BaseCurrency: "USD"})
+
+	t.Run("iso currency filled from table", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		record, err := svc.UpsertCurrency(context.TODO(), &currency.UpsertCurrencyRequest{
+			ID:   "jpy",
+			Rate: lo.ToPtr(decimal.NewFromInt(150)),
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "JPY", record.ID)
+		assert.Equal(t, "Yen", record.Name)
+		assert.Equal(t, "¥", record.Symbol)
+		assert.EqualValues(t, 392, record.NumericCode)
+		assert.EqualValues(t, 0, record.DecimalPlaces)
+		assert.True(t, record.IsActive)
+		assert.False(t, record.IsCustom)
+	})
+
+	t.Run("update keeps rate", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+		assert.NoError(t, gormDB.Create(&database.Currency{ID: "PLN", Rate: decimal.NewFromInt(4), DecimalPlaces: 2}).Error)
+
+		record, err := svc.UpsertCurrency(context.TODO(), &currency.UpsertCurrencyRequest{
+			ID:             "PLN",
+			Name:           "Polish Zloty",
+			Symbol:         lo.ToPtr("zł"),
+			SymbolPosition: lo.ToPtr(database.CurrencySymbolPositionAfter),
+			Rate:           lo.ToPtr(decimal.NewFromInt(10)),
+		})
+		assert.NoError(t, err)
+
+		var stored database.Currency
+		assert.NoError(t, gormDB.Where("id = ?", "PLN").First(&stored).Error)
+
+		assert.Equal(t, "Polish Zloty", stored.Name)
+		assert.Equal(t, "zł", stored.Symbol)
+		assert.Equal(t, database.CurrencySymbolPositionAfter, stored.SymbolPosition)
+		assert.EqualValues(t, "4", stored.Rate.String())
+		assert.EqualValues(t, "4", record.Rate.String())
+	})
+
+	t.Run("pseudo currency records rate history", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		record, err := svc.UpsertCurrency(context.TODO(), &currency.UpsertCurrencyRequest{
+			ID:            "MILES",
+			Name:          "Airline miles",
+			DecimalPlaces: lo.ToPtr(int32(0)),
+			IsCustom:      true,
+			Rate:          lo.ToPtr(decimal.NewFromInt(80)),
+		})
+		assert.NoError(t, err)
+		assert.True(t, record.IsCustom)
+		assert.EqualValues(t, 0, record.NumericCode)
+
+		var history []*database.CurrencyRateHistory
+		assert.NoError(t, gormDB.Find(&history).Error)
+		assert.Len(t, history, 1)
+		assert.Equal(t, "MILES", history[0].CurrencyID)
+		assert.EqualValues(t, "80", history[0].Rate.String())
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		_, err := svc.UpsertCurrency(context.TODO(), &currency.UpsertCurrencyRequest{ID: "a b"})
+		assert.ErrorContains(t, err, "invalid currency code")
+
+		_, err = svc.UpsertCurrency(context.TODO(), &currency.UpsertCurrencyRequest{ID: "EUR", IsCustom: true, Rate: lo.ToPtr(decimal.NewFromInt(1))})
+		assert.ErrorContains(t, err, "is an ISO 4217 code")
+
+		_, err = svc.UpsertCurrency(context.TODO(), &currency.UpsertCurrencyRequest{ID: "PTS", IsCustom: true})
+		assert.ErrorContains(t, err, "rate is required for pseudo currencies")
+
+		_, err = svc.UpsertCurrency(context.TODO(), &currency.UpsertCurrencyRequest{ID: "PTS", IsCustom: true, NumericCode: 999})
+		assert.ErrorContains(t, err, "can not have a numeric code")
+
+		_, err = svc.UpsertCurrency(context.TODO(), &currency.UpsertCurrencyRequest{ID: "PTS", DecimalPlaces: lo.ToPtr(int32(12))})
+		assert.ErrorContains(t, err, "decimal_places must be between 0 and 8")
+	})
+}
+
+func TestCatalogService_SetCustomRate(t *testing.T) {
+	today := time.Now().UTC()
+
+	seed := func(t *testing.T) []*database.Transaction {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+		assert.NoError(t, gormDB.Create(&database.Currency{ID: "PTS", Rate: decimal.NewFromInt(100), IsCustom: true}).Error)
+		assert.NoError(t, gormDB.Create(&database.CurrencyRateHistory{
+			CurrencyID: "PTS",
+			Date:       today.AddDate(0, 0, -5),
+			Rate:       decimal.NewFromInt(100),
+			UpdatedAt:  today,
+		}).Error)
+
+		txs := []*database.Transaction{
+			{SourceCurrency: "PTS", TransactionDateOnly: today, Extra: map[string]string{}},
+			{SourceCurrency: "PTS", TransactionDateOnly: today, ExchangeRateLocked: true, Extra: map[string]string{}},
+			{SourceCurrency: "EUR", TransactionDateOnly: today, Extra: map[string]string{}},
+		}
+		assert.NoError(t, gormDB.Create(&txs).Error)
+
+		return txs
+	}
+
+	t.Run("latest rate recalculates transactions", func(t *testing.T) {
+		txs := seed(t)
+
+		baseSvc := NewMockBaseAmountSvc(gomock.NewController(t))
+		baseSvc.EXPECT().RecalculateAmountInBaseCurrency(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *gorm.DB, affected []*database.Transaction) error {
+				assert.Len(t, affected, 1)
+				assert.Equal(t, txs[0].ID, affected[0].ID)
+				return nil
+			})
+
+		record, err := currency.NewCatalogService(&currency.CatalogServiceConfig{BaseAmountSvc: baseSvc}).
+			SetCustomRate(context.TODO(), &currency.SetCustomRateRequest{
+				CurrencyID: "pts",
+				Rate:       decimal.NewFromInt(120),
+			})
+		assert.NoError(t, err)
+		assert.EqualValues(t, "120", record.Rate.String())
+
+		var history []*database.CurrencyRateHistory
+		assert.NoError(t, gormDB.Order("date").Find(&history).Error)
+		assert.Len(t, history, 2)
+	})
+
+	t.Run("older rate only records history", func(t *testing.T) {
+		seed(t)
+
+		record, err := currency.NewCatalogService(&currency.CatalogServiceConfig{
+			BaseAmountSvc: NewMockBaseAmountSvc(gomock.NewController(t)),
+		}).SetCustomRate(context.TODO(), &currency.SetCustomRateRequest{
+			CurrencyID: "PTS",
+			Rate:       decimal.NewFromInt(90),
+			Date:       today.AddDate(0, 0, -10),
+		})
+		assert.NoError(t, err)
+		assert.EqualValues(t, "100", record.Rate.String())
+
+		var count int64
+		assert.NoError(t, gormDB.Model(&database.CurrencyRateHistory{}).Count(&count).Error)
+		assert.EqualValues(t, 2, count)
+	})
+
+	t.Run("synced currency", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+		assert.NoError(t, gormDB.Create(&database.Currency{ID: "EUR", Rate: decimal.RequireFromString("0.9")}).Error)
+
+		_, err := currency.NewCatalogService(&currency.CatalogServiceConfig{}).SetCustomRate(context.TODO(), &currency.SetCustomRateRequest{
+			CurrencyID: "EUR",
+			Rate:       decimal.NewFromInt(1),
+		})
+		assert.ErrorContains(t, err, "rates of EUR are synced")
+	})
+
+	t.Run("rate must be positive", func(t *testing.T) {
+		_, err := currency.NewCatalogService(&currency.CatalogServiceConfig{}).SetCustomRate(context.TODO(), &currency.SetCustomRateRequest{
+			CurrencyID: "PTS",
+		})
+		assert.ErrorContains(t, err, "rate must be positive")
+	})
+}
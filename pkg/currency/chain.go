@@ -0,0 +1,259 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type RateChainConfig struct {
+	BaseCurrency string
+	Providers    []RateProvider
+	Default      []string            // ordered provider names used for every currency
+	Currencies   map[string][]string // ordered provider names per currency, tried before Default
+}
+
+// RateChain merges rates from several providers. Every provider is fetched at most once
+// per run and its rates are rebased to BaseCurrency. A currency takes its rate from the
+// first provider in its own chain that returns it, then from the first default provider
+// that returns it.
+type RateChain struct {
+	cfg       RateChainConfig
+	providers map[string]RateProvider
+}
+
+func NewRateChain(cfg RateChainConfig) (*RateChain, error) {
+	providers := map[string]RateProvider{}
+	for _, p := range cfg.Providers {
+		providers[p.Name()] = p
+	}
+
+	if len(cfg.Default) == 0 && len(cfg.Currencies) == 0 {
+		return nil, errors.New("no exchange rate providers configured")
+	}
+
+	check := func(names []string) error {
+		for _, name := range names {
+			if _, ok := providers[name]; !ok {
+				return errors.Errorf("unknown or unconfigured exchange rate provider %q", name)
+			}
+		}
+
+		return nil
+	}
+
+	if err := check(cfg.Default); err != nil {
+		return nil, err
+	}
+
+	for _, names := range cfg.Currencies {
+		if err := check(names); err != nil {
+			return nil, err
+		}
+	}
+
+	return &RateChain{
+		cfg:       cfg,
+		providers: providers,
+	}, nil
+}
+
+// NewRateChainFromConfig builds a chain with all built-in providers. The remote provider is
+// only available when remoteURL is set, the exchangerate provider when its URL is set.
+// When no default chain is configured defaultProviders is used.
+func NewRateChainFromConfig(
+	cl httpClient,
+	cfg configuration.RateProvidersConfig,
+	baseCurrency string,
+	remoteURL string,
+	defaultProviders ...string,
+) (*RateChain, error) {
+	providers := []RateProvider{
+		NewECBProvider(cl, cfg.ECBURL),
+		NewNBPProvider(cl, cfg.NBPURL),
+		NewNBUProvider(cl, cfg.NBUURL),
+	}
+
+	if remoteURL != "" {
+		providers = append(providers, NewRemoteProvider(cl, remoteURL))
+	}
+
+	if cfg.ExchangeRateURL != "" {
+		providers = append(providers, NewExchangeRateProvider(cl, cfg.ExchangeRateURL))
+	}
+
+	defaults := cfg.Default
+	if len(defaults) == 0 {
+		defaults = defaultProviders
+	}
+
+	currencies, err := ParseCurrencyProviders(cfg.Currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRateChain(RateChainConfig{
+		BaseCurrency: baseCurrency,
+		Providers:    providers,
+		Default:      defaults,
+		Currencies:   currencies,
+	})
+}
+
+// ParseCurrencyProviders parses per-currency chains written as "nbp|ecb".
+func ParseCurrencyProviders(raw map[string]string) (map[string][]string, error) {
+	result := make(map[string][]string, len(raw))
+
+	for code, chain := range raw {
+		var names []string
+
+		for _, name := range strings.Split(chain, "|") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			names = append(names, name)
+		}
+
+		if len(names) == 0 {
+			return nil, errors.Errorf("empty provider chain for %s", code)
+		}
+
+		result[strings.ToUpper(strings.TrimSpace(code))] = names
+	}
+
+	return result, nil
+}
+
+type chainRun struct {
+	chain   *RateChain
+	fetched map[string]*RemoteRates
+	errs    map[string]error
+}
+
+func (r *chainRun) get(ctx context.Context, name string) *RemoteRates {
+	if rates, ok := r.fetched[name]; ok {
+		return rates
+	}
+
+	if _, failed := r.errs[name]; failed {
+		return nil
+	}
+
+	rates, err := r.chain.providers[name].Fetch(ctx)
+	if err == nil && rates.Base != r.chain.cfg.BaseCurrency {
+		rates, err = Rebase(rates, r.chain.cfg.BaseCurrency)
+	}
+
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Str("provider", name).Msg("exchange rate provider failed")
+		r.errs[name] = err
+
+		return nil
+	}
+
+	r.fetched[name] = rates
+
+	return rates
+}
+
+func (r *chainRun) error() error {
+	names := make([]string, 0, len(r.errs))
+	for name := range r.errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %v", name, r.errs[name]))
+	}
+
+	return errors.Newf("no exchange rates resolved (%s)", strings.Join(parts, "; "))
+}
+
+func (c *RateChain) Name() string {
+	return "chain"
+}
+
+func (c *RateChain) Fetch(ctx context.Context) (*RemoteRates, error) {
+	run := &chainRun{
+		chain:   c,
+		fetched: map[string]*RemoteRates{},
+		errs:    map[string]error{},
+	}
+
+	result := &RemoteRates{
+		Base:    c.cfg.BaseCurrency,
+		Rates:   map[string]decimal.Decimal{},
+		Sources: map[string]string{},
+	}
+
+	use := func(code string, name string, rates *RemoteRates) {
+		result.Rates[code] = rates.Rates[code]
+		result.Sources[code] = name
+
+		if rates.UpdatedAt.After(result.UpdatedAt) {
+			result.UpdatedAt = rates.UpdatedAt
+		}
+	}
+
+	for _, name := range c.cfg.Default {
+		rates := run.get(ctx, name)
+		if rates == nil {
+			continue
+		}
+
+		for code := range rates.Rates {
+			if _, ok := result.Rates[code]; !ok {
+				use(code, name, rates)
+			}
+		}
+	}
+
+	codes := make([]string, 0, len(c.cfg.Currencies))
+	for code := range c.cfg.Currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		resolved := false
+
+		for _, name := range c.cfg.Currencies[code] {
+			rates := run.get(ctx, name)
+			if rates == nil {
+				continue
+			}
+
+			if _, ok := rates.Rates[code]; ok {
+				use(code, name, rates)
+				resolved = true
+
+				break
+			}
+		}
+
+		if !resolved {
+			zerolog.Ctx(ctx).Warn().Str("currency", code).
+				Str("fallback", result.Sources[code]).
+				Msg("no configured provider returned a rate for currency")
+		}
+	}
+
+	if len(result.Rates) == 0 {
+		return nil, run.error()
+	}
+
+	result.Rates[c.cfg.BaseCurrency] = decimal.NewFromInt(1)
+	delete(result.Sources, c.cfg.BaseCurrency)
+
+	return result, nil
+}
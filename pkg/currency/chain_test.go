@@ -0,0 +1,261 @@
+package currency_test
+
+import (
+	"context"
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newMockProvider(t *testing.T, name string) *MockRateProvider {
+	p := NewMockRateProvider(gomock.NewController(t))
+	p.EXPECT().Name().Return(name).AnyTimes()
+
+	return p
+}
+
+func rates(base string, values map[string]string) *currency.RemoteRates {
+	r := &currency.RemoteRates{
+		Base:  base,
+		Rates: map[string]decimal.Decimal{},
+	}
+
+	for code, v := range values {
+		r.Rates[code] = decimal.RequireFromString(v)
+	}
+
+	return r
+}
+
+func TestRateChain(t *testing.T) {
+	t.Run("default chain falls back when provider fails", func(t *testing.T) {
+		remote := newMockProvider(t, "remote")
+		ecb := newMockProvider(t, "ecb")
+
+		remote.EXPECT().Fetch(gomock.Any()).Return(nil, assert.AnError)
+		ecb.EXPECT().Fetch(gomock.Any()).Return(rates("EUR", map[string]string{
+			"EUR": "1",
+			"USD": "1.25",
+			"PLN": "4.25",
+		}), nil)
+
+		chain, err := currency.NewRateChain(currency.RateChainConfig{
+			BaseCurrency: "USD",
+			Providers:    []currency.RateProvider{remote, ecb},
+			Default:      []string{"remote", "ecb"},
+		})
+		assert.NoError(t, err)
+
+		resp, err := chain.Fetch(context.TODO())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "USD", resp.Base)
+		assert.Len(t, resp.Rates, 3)
+		assert.EqualValues(t, "1", resp.Rates["USD"].String())
+		assert.EqualValues(t, "0.8", resp.Rates["EUR"].String())
+		assert.EqualValues(t, "3.4", resp.Rates["PLN"].String())
+		assert.Equal(t, "ecb", resp.Sources["PLN"])
+		assert.NotContains(t, resp.Sources, "USD")
+	})
+
+	t.Run("later default provider fills missing currencies", func(t *testing.T) {
+		remote := newMockProvider(t, "remote")
+		nbu := newMockProvider(t, "nbu")
+
+		remote.EXPECT().Fetch(gomock.Any()).Return(rates("USD", map[string]string{
+			"USD": "1",
+			"EUR": "0.85",
+		}), nil)
+		nbu.EXPECT().Fetch(gomock.Any()).Return(rates("UAH", map[string]string{
+			"UAH": "1",
+			"USD": "0.025",
+			"EUR": "0.02",
+			"MDL": "0.4",
+		}), nil)
+
+		chain, err := currency.NewRateChain(currency.RateChainConfig{
+			BaseCurrency: "USD",
+			Providers:    []currency.RateProvider{remote, nbu},
+			Default:      []string{"remote", "nbu"},
+		})
+		assert.NoError(t, err)
+
+		resp, err := chain.Fetch(context.TODO())
+		assert.NoError(t, err)
+
+		assert.EqualValues(t, "0.85", resp.Rates["EUR"].String())
+		assert.Equal(t, "remote", resp.Sources["EUR"])
+		assert.EqualValues(t, "40", resp.Rates["UAH"].String())
+		assert.EqualValues(t, "16", resp.Rates["MDL"].String())
+		assert.Equal(t, "nbu", resp.Sources["MDL"])
+	})
+
+	t.Run("per currency chain overrides default", func(t *testing.T) {
+		remote := newMockProvider(t, "remote")
+		nbp := newMockProvider(t, "nbp")
+		ecb := newMockProvider(t, "ecb")
+
+		updatedAt := time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)
+
+		remote.EXPECT().Fetch(gomock.Any()).Return(rates("USD", map[string]string{
+			"USD": "1",
+			"EUR": "0.85",
+			"PLN": "3.8",
+		}), nil)
+		nbp.EXPECT().Fetch(gomock.Any()).Return(nil, assert.AnError)
+
+		ecbRates := rates("EUR", map[string]string{
+			"EUR": "1",
+			"USD": "1.25",
+			"PLN": "4.5",
+		})
+		ecbRates.UpdatedAt = updatedAt
+		ecb.EXPECT().Fetch(gomock.Any()).Return(ecbRates, nil) // fetched once for both currencies
+
+		chain, err := currency.NewRateChain(currency.RateChainConfig{
+			BaseCurrency: "USD",
+			Providers:    []currency.RateProvider{remote, nbp, ecb},
+			Default:      []string{"remote"},
+			Currencies: map[string][]string{
+				"PLN": {"nbp", "ecb"},
+				"EUR": {"ecb"},
+			},
+		})
+		assert.NoError(t, err)
+
+		resp, err := chain.Fetch(context.TODO())
+		assert.NoError(t, err)
+
+		assert.EqualValues(t, "3.6", resp.Rates["PLN"].String())
+		assert.Equal(t, "ecb", resp.Sources["PLN"])
+		assert.EqualValues(t, "0.8", resp.Rates["EUR"].String())
+		assert.Equal(t, updatedAt, resp.UpdatedAt)
+	})
+
+	t.Run("per currency chain without the currency keeps default", func(t *testing.T) {
+		remote := newMockProvider(t, "remote")
+		nbu := newMockProvider(t, "nbu")
+
+		remote.EXPECT().Fetch(gomock.Any()).Return(rates("USD", map[string]string{
+			"USD": "1",
+			"PLN": "3.8",
+		}), nil)
+		nbu.EXPECT().Fetch(gomock.Any()).Return(rates("UAH", map[string]string{
+			"UAH": "1",
+			"USD": "0.025",
+		}), nil)
+
+		chain, err := currency.NewRateChain(currency.RateChainConfig{
+			BaseCurrency: "USD",
+			Providers:    []currency.RateProvider{remote, nbu},
+			Default:      []string{"remote"},
+			Currencies: map[string][]string{
+				"PLN": {"nbu"},
+			},
+		})
+		assert.NoError(t, err)
+
+		resp, err := chain.Fetch(context.TODO())
+		assert.NoError(t, err)
+
+		assert.EqualValues(t, "3.8", resp.Rates["PLN"].String())
+		assert.Equal(t, "remote", resp.Sources["PLN"])
+	})
+
+	t.Run("provider without base currency is skipped", func(t *testing.T) {
+		nbu := newMockProvider(t, "nbu")
+
+		nbu.EXPECT().Fetch(gomock.Any()).Return(rates("UAH", map[string]string{
+			"UAH": "1",
+			"EUR": "0.02",
+		}), nil)
+
+		chain, err := currency.NewRateChain(currency.RateChainConfig{
+			BaseCurrency: "USD",
+			Providers:    []currency.RateProvider{nbu},
+			Default:      []string{"nbu"},
+		})
+		assert.NoError(t, err)
+
+		resp, err := chain.Fetch(context.TODO())
+		assert.ErrorContains(t, err, "nbu: missing rate for new base USD")
+		assert.Nil(t, resp)
+	})
+
+	t.Run("all providers fail", func(t *testing.T) {
+		remote := newMockProvider(t, "remote")
+		ecb := newMockProvider(t, "ecb")
+
+		remote.EXPECT().Fetch(gomock.Any()).Return(nil, assert.AnError)
+		ecb.EXPECT().Fetch(gomock.Any()).Return(nil, assert.AnError)
+
+		chain, err := currency.NewRateChain(currency.RateChainConfig{
+			BaseCurrency: "USD",
+			Providers:    []currency.RateProvider{remote, ecb},
+			Default:      []string{"remote", "ecb"},
+		})
+		assert.NoError(t, err)
+
+		resp, err := chain.Fetch(context.TODO())
+		assert.ErrorContains(t, err, "no exchange rates resolved (ecb:")
+		assert.Nil(t, resp)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		chain, err := currency.NewRateChain(currency.RateChainConfig{
+			BaseCurrency: "USD",
+			Default:      []string{"remote"},
+		})
+		assert.ErrorContains(t, err, `unknown or unconfigured exchange rate provider "remote"`)
+		assert.Nil(t, chain)
+	})
+
+	t.Run("no providers", func(t *testing.T) {
+		chain, err := currency.NewRateChain(currency.RateChainConfig{BaseCurrency: "USD"})
+		assert.ErrorContains(t, err, "no exchange rate providers configured")
+		assert.Nil(t, chain)
+	})
+}
+
+func TestNewRateChainFromConfig(t *testing.T) {
+	t.Run("defaults to fallback providers", func(t *testing.T) {
+		chain, err := currency.NewRateChainFromConfig(http.DefaultClient, configuration.RateProvidersConfig{},
+			"USD", "https://localhost/rates.json", currency.ProviderRemote)
+		assert.NoError(t, err)
+		assert.NotNil(t, chain)
+	})
+
+	t.Run("exchangerate requires url", func(t *testing.T) {
+		chain, err := currency.NewRateChainFromConfig(http.DefaultClient, configuration.RateProvidersConfig{
+			Default: []string{currency.ProviderExchangeRate},
+		}, "USD", "")
+		assert.ErrorContains(t, err, "exchangerate")
+		assert.Nil(t, chain)
+	})
+
+	t.Run("invalid currency chain", func(t *testing.T) {
+		chain, err := currency.NewRateChainFromConfig(http.DefaultClient, configuration.RateProvidersConfig{
+			Currencies: map[string]string{"PLN": " | "},
+		}, "USD", "", currency.ProviderECB)
+		assert.ErrorContains(t, err, "empty provider chain for PLN")
+		assert.Nil(t, chain)
+	})
+}
+
+func TestParseCurrencyProviders(t *testing.T) {
+	parsed, err := currency.ParseCurrencyProviders(map[string]string{
+		"pln": "NBP| ecb",
+		"UAH": "nbu",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"PLN": {"nbp", "ecb"},
+		"UAH": {"nbu"},
+	}, parsed)
+}
@@ -9,33 +9,74 @@ import (
 )
 
 type DecimalService struct {
-	decimalCountCache *expirable.LRU[string, int32]
+	currencyCache *expirable.LRU[string, *database.Currency]
 }
 
 func NewDecimalService() *DecimalService {
 	return &DecimalService{
-		decimalCountCache: expirable.NewLRU[string, int32](100, nil, configuration.DefaultCacheTTL),
+		currencyCache: expirable.NewLRU[string, *database.Currency](100, nil, configuration.DefaultCacheTTL),
 	}
 }
 
-func (s *DecimalService) GetCurrencyDecimals(ctx context.Context, currency string) int32 {
-	if cached, ok := s.decimalCountCache.Get(currency); ok {
-		return cached
+func (s *DecimalService) getCurrency(ctx context.Context, currency string) (*database.Currency, error) {
+	if cached, ok := s.currencyCache.Get(currency); ok {
+		return cached, nil
 	}
 
 	db := database.GetDbWithContext(ctx, database.DbTypeReadonly)
 
-	var currencyDecimal int32
-	if err := db.Model(&database.Currency{}).Where("id = ?", currency).
-		Select("decimal_places").Find(&currencyDecimal).Error; err != nil {
-		return configuration.DefaultDecimalPlaces
+	var record database.Currency
+	if err := db.Where("id = ?", currency).
+		Select("id", "decimal_places", "symbol", "symbol_position").Find(&record).Error; err != nil {
+		return nil, err
 	}
 
-	s.decimalCountCache.Add(currency, currencyDecimal)
+	s.currencyCache.Add(currency, &record)
 
-	return currencyDecimal
+	return &record, nil
 }
 
+func (s *DecimalService) GetCurrencyDecimals(ctx context.Context, currency string) int32 {
+	record, err := s.getCurrency(ctx, currency)
+	if err != nil {
+		return configuration.DefaultDecimalPlaces
+	}
+
+	return record.DecimalPlaces
+}
+
+// ToString returns a plain decimal string, as expected by API clients.
 func (s *DecimalService) ToString(ctx context.Context, amount decimal.Decimal, currency string) string {
 	return amount.StringFixed(s.GetCurrencyDecimals(ctx, currency))
 }
+
+// Format returns a human readable amount with the currency symbol, e.g. -$10.00 or 10.00 zł.
+// The currency code is used when no symbol is set.
+func (s *DecimalService) Format(ctx context.Context, amount decimal.Decimal, currency string) string {
+	record, err := s.getCurrency(ctx, currency)
+	if err != nil {
+		return amount.StringFixed(configuration.DefaultDecimalPlaces) + " " + currency
+	}
+
+	return FormatAmount(amount, record, currency)
+}
+
+// FormatAmount formats amount using symbol, symbol position and decimal places of record.
+func FormatAmount(amount decimal.Decimal, record *database.Currency, code string) string {
+	value := amount.Abs().StringFixed(record.DecimalPlaces)
+
+	sign := ""
+	if amount.IsNegative() && value != decimal.Zero.StringFixed(record.DecimalPlaces) {
+		sign = "-"
+	}
+
+	if record.Symbol == "" {
+		return sign + value + " " + code
+	}
+
+	if record.SymbolPosition == database.CurrencySymbolPositionAfter {
+		return sign + value + " " + record.Symbol
+	}
+
+	return sign + record.Symbol + value
+}
@@ -42,3 +42,22 @@ func TestToString(t *testing.T) {
 		assert.EqualValues(t, "200.000", res)
 	})
 }
+
+func TestFormat(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		assert.NoError(t, gormDB.Create(&database.Currency{
+			ID:             "PLN",
+			DecimalPlaces:  2,
+			Symbol:         "zł",
+			SymbolPosition: database.CurrencySymbolPositionAfter,
+		}).Error)
+
+		dec := currency.NewDecimalService()
+
+		assert.EqualValues(t, "-12.50 zł", dec.Format(context.TODO(), decimal.RequireFromString("-12.5"), "PLN"))
+		assert.EqualValues(t, "12.50 zł", dec.Format(context.TODO(), decimal.RequireFromString("12.5"), "PLN"))
+		assert.EqualValues(t, "2.00", dec.ToString(context.TODO(), decimal.NewFromInt(2), "PLN")) // same cache entry
+	})
+}
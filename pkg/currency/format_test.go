@@ -0,0 +1,22 @@
+package currency_test
+
+import (
+	"testing"
+
+	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAmount(t *testing.T) {
+	usd := &database.Currency{Symbol: "$", DecimalPlaces: 2}
+	pln := &database.Currency{Symbol: "zł", SymbolPosition: database.CurrencySymbolPositionAfter, DecimalPlaces: 2}
+	points := &database.Currency{DecimalPlaces: 0}
+
+	assert.Equal(t, "$10.50", currency.FormatAmount(decimal.RequireFromString("10.5"), usd, "USD"))
+	assert.Equal(t, "-$10.50", currency.FormatAmount(decimal.RequireFromString("-10.5"), usd, "USD"))
+	assert.Equal(t, "$0.00", currency.FormatAmount(decimal.RequireFromString("-0.001"), usd, "USD"))
+	assert.Equal(t, "-3.80 zł", currency.FormatAmount(decimal.RequireFromString("-3.8"), pln, "PLN"))
+	assert.Equal(t, "1500 PTS", currency.FormatAmount(decimal.NewFromInt(1500), points, "PTS"))
+}
@@ -2,6 +2,7 @@ package currency
 
 import (
 	"context"
+	"github.com/ft-t/go-money/pkg/database"
 	"gorm.io/gorm"
 	"net/http"
 )
@@ -17,4 +18,14 @@ type BaseAmountSvc interface {
 		ctx context.Context,
 		tx *gorm.DB,
 	) error
+	RecalculateAmountInBaseCurrency(
+		ctx context.Context,
+		tx *gorm.DB,
+		specificTxIDs []*database.Transaction,
+	) error
+}
+
+type RateProvider interface {
+	Name() string
+	Fetch(ctx context.Context) (*RemoteRates, error)
 }
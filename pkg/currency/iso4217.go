@@ -0,0 +1,80 @@
+package currency
+
+import (
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+)
+
+// IsoCurrency is ISO 4217 metadata used to fill currencies created by sync.
+type IsoCurrency struct {
+	Name           string
+	Symbol         string
+	SymbolPosition database.CurrencySymbolPosition
+	NumericCode    int32
+	DecimalPlaces  int32
+}
+
+var isoCurrencies = map[string]IsoCurrency{
+	"AED": {Name: "UAE Dirham", Symbol: "د.إ", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 784, DecimalPlaces: 2},
+	"AUD": {Name: "Australian Dollar", Symbol: "A$", NumericCode: 36, DecimalPlaces: 2},
+	"BGN": {Name: "Bulgarian Lev", Symbol: "лв", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 975, DecimalPlaces: 2},
+	"BHD": {Name: "Bahraini Dinar", Symbol: "BD", NumericCode: 48, DecimalPlaces: 3},
+	"BRL": {Name: "Brazilian Real", Symbol: "R$", NumericCode: 986, DecimalPlaces: 2},
+	"CAD": {Name: "Canadian Dollar", Symbol: "C$", NumericCode: 124, DecimalPlaces: 2},
+	"CHF": {Name: "Swiss Franc", Symbol: "CHF", NumericCode: 756, DecimalPlaces: 2},
+	"CNY": {Name: "Yuan Renminbi", Symbol: "¥", NumericCode: 156, DecimalPlaces: 2},
+	"CZK": {Name: "Czech Koruna", Symbol: "Kč", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 203, DecimalPlaces: 2},
+	"DKK": {Name: "Danish Krone", Symbol: "kr", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 208, DecimalPlaces: 2},
+	"EUR": {Name: "Euro", Symbol: "€", NumericCode: 978, DecimalPlaces: 2},
+	"GBP": {Name: "Pound Sterling", Symbol: "£", NumericCode: 826, DecimalPlaces: 2},
+	"GEL": {Name: "Lari", Symbol: "₾", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 981, DecimalPlaces: 2},
+	"HKD": {Name: "Hong Kong Dollar", Symbol: "HK$", NumericCode: 344, DecimalPlaces: 2},
+	"HUF": {Name: "Forint", Symbol: "Ft", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 348, DecimalPlaces: 2},
+	"ILS": {Name: "New Israeli Sheqel", Symbol: "₪", NumericCode: 376, DecimalPlaces: 2},
+	"INR": {Name: "Indian Rupee", Symbol: "₹", NumericCode: 356, DecimalPlaces: 2},
+	"ISK": {Name: "Iceland Krona", Symbol: "kr", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 352, DecimalPlaces: 0},
+	"JPY": {Name: "Yen", Symbol: "¥", NumericCode: 392, DecimalPlaces: 0},
+	"KRW": {Name: "Won", Symbol: "₩", NumericCode: 410, DecimalPlaces: 0},
+	"KWD": {Name: "Kuwaiti Dinar", Symbol: "KD", NumericCode: 414, DecimalPlaces: 3},
+	"KZT": {Name: "Tenge", Symbol: "₸", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 398, DecimalPlaces: 2},
+	"MXN": {Name: "Mexican Peso", Symbol: "MX$", NumericCode: 484, DecimalPlaces: 2},
+	"NOK": {Name: "Norwegian Krone", Symbol: "kr", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 578, DecimalPlaces: 2},
+	"NZD": {Name: "New Zealand Dollar", Symbol: "NZ$", NumericCode: 554, DecimalPlaces: 2},
+	"PLN": {Name: "Zloty", Symbol: "zł", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 985, DecimalPlaces: 2},
+	"RON": {Name: "Romanian Leu", Symbol: "lei", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 946, DecimalPlaces: 2},
+	"RSD": {Name: "Serbian Dinar", Symbol: "дин.", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 941, DecimalPlaces: 2},
+	"SEK": {Name: "Swedish Krona", Symbol: "kr", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 752, DecimalPlaces: 2},
+	"SGD": {Name: "Singapore Dollar", Symbol: "S$", NumericCode: 702, DecimalPlaces: 2},
+	"THB": {Name: "Baht", Symbol: "฿", NumericCode: 764, DecimalPlaces: 2},
+	"TRY": {Name: "Turkish Lira", Symbol: "₺", NumericCode: 949, DecimalPlaces: 2},
+	"UAH": {Name: "Hryvnia", Symbol: "₴", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 980, DecimalPlaces: 2},
+	"USD": {Name: "US Dollar", Symbol: "$", NumericCode: 840, DecimalPlaces: 2},
+	"ZAR": {Name: "Rand", Symbol: "R", NumericCode: 710, DecimalPlaces: 2},
+}
+
+// LookupIso returns ISO 4217 metadata of a well known currency code.
+func LookupIso(code string) (IsoCurrency, bool) {
+	iso, ok := isoCurrencies[code]
+
+	return iso, ok
+}
+
+// newSyncedCurrency builds a currency row for a code returned by a rate provider.
+func newSyncedCurrency(code string) *database.Currency {
+	iso, ok := LookupIso(code)
+	if !ok {
+		return &database.Currency{
+			ID:            code,
+			DecimalPlaces: configuration.DefaultDecimalPlaces,
+		}
+	}
+
+	return &database.Currency{
+		ID:             code,
+		Name:           iso.Name,
+		Symbol:         iso.Symbol,
+		SymbolPosition: iso.SymbolPosition,
+		NumericCode:    iso.NumericCode,
+		DecimalPlaces:  iso.DecimalPlaces,
+	}
+}
@@ -0,0 +1,184 @@
+package currency
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"gorm.io/gorm"
+)
+
+type RateOverrideService struct {
+	cfg *RateOverrideServiceConfig
+}
+
+type RateOverrideServiceConfig struct {
+	BaseAmountSvc BaseAmountSvc
+	BaseCurrency  string
+}
+
+func NewRateOverrideService(cfg *RateOverrideServiceConfig) *RateOverrideService {
+	return &RateOverrideService{
+		cfg: cfg,
+	}
+}
+
+func (s *RateOverrideService) ListOverrides(
+	ctx context.Context,
+	currencyID string,
+) ([]*database.CurrencyRateOverride, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeReadonly)
+
+	query := db.Order("currency_id, valid_from desc, id desc")
+	if currencyID != "" {
+		query = query.Where("currency_id = ?", strings.ToUpper(currencyID))
+	}
+
+	var overrides []*database.CurrencyRateOverride
+	if err := query.Find(&overrides).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to list rate overrides")
+	}
+
+	return overrides, nil
+}
+
+// CreateOverride stores an override and recalculates base amounts of unlocked transactions it covers.
+func (s *RateOverrideService) CreateOverride(
+	ctx context.Context,
+	req *CreateRateOverrideRequest,
+) (*database.CurrencyRateOverride, error) {
+	currencyID := strings.ToUpper(strings.TrimSpace(req.CurrencyID))
+	if currencyID == "" {
+		return nil, errors.New("currency is required")
+	}
+
+	if currencyID == s.cfg.BaseCurrency {
+		return nil, errors.New("base currency rate can not be overridden")
+	}
+
+	if !req.Rate.IsPositive() {
+		return nil, errors.New("rate must be positive")
+	}
+
+	validFrom := truncateDay(req.ValidFrom)
+
+	var validTo *time.Time
+	if req.ValidTo != nil {
+		to := truncateDay(*req.ValidTo)
+		if to.Before(validFrom) {
+			return nil, errors.New("valid_to must not be before valid_from")
+		}
+
+		validTo = &to
+	}
+
+	tx := database.GetDbWithContext(ctx, database.DbTypeMaster).Begin()
+	defer tx.Rollback()
+
+	var cur database.Currency
+	if err := tx.Where("id = ?", currencyID).First(&cur).Error; err != nil {
+		return nil, errors.Wrapf(err, "currency %s not found", currencyID)
+	}
+
+	overlap := tx.Model(&database.CurrencyRateOverride{}).
+		Where("currency_id = ?", currencyID).
+		Where("valid_to IS NULL OR valid_to >= ?", validFrom)
+	if validTo != nil {
+		overlap = overlap.Where("valid_from <= ?", *validTo)
+	}
+
+	var overlapping int64
+	if err := overlap.Count(&overlapping).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to check overlapping overrides")
+	}
+
+	if overlapping > 0 {
+		return nil, errors.Newf("rate override for %s overlaps an existing one", currencyID)
+	}
+
+	now := time.Now().UTC()
+	override := &database.CurrencyRateOverride{
+		CurrencyID: currencyID,
+		Rate:       req.Rate,
+		ValidFrom:  validFrom,
+		ValidTo:    validTo,
+		Note:       req.Note,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := tx.Create(override).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to create rate override")
+	}
+
+	if err := s.recalculate(ctx, tx, override); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return override, nil
+}
+
+// DeleteOverride removes an override and recalculates the transactions it covered.
+func (s *RateOverrideService) DeleteOverride(
+	ctx context.Context,
+	id int32,
+) (*database.CurrencyRateOverride, error) {
+	tx := database.GetDbWithContext(ctx, database.DbTypeMaster).Begin()
+	defer tx.Rollback()
+
+	var override database.CurrencyRateOverride
+	if err := tx.Where("id = ?", id).First(&override).Error; err != nil {
+		return nil, errors.Wrapf(err, "rate override %d not found", id)
+	}
+
+	if err := tx.Delete(&override).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to delete rate override")
+	}
+
+	if err := s.recalculate(ctx, tx, &override); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &override, nil
+}
+
+func (s *RateOverrideService) recalculate(
+	ctx context.Context,
+	tx *gorm.DB,
+	override *database.CurrencyRateOverride,
+) error {
+	query := tx.Where("source_currency = ? AND transaction_date_only >= ? AND NOT exchange_rate_locked",
+		override.CurrencyID, override.ValidFrom)
+	if override.ValidTo != nil {
+		query = query.Where("transaction_date_only <= ?", *override.ValidTo)
+	}
+
+	var affected []*database.Transaction
+	if err := query.Select("id").Find(&affected).Error; err != nil {
+		return errors.Wrap(err, "failed to find transactions covered by rate override")
+	}
+
+	if len(affected) == 0 {
+		return nil
+	}
+
+	if err := s.cfg.BaseAmountSvc.RecalculateAmountInBaseCurrency(ctx, tx, affected); err != nil {
+		return errors.Wrap(err, "failed to recalculate amounts in base currency")
+	}
+
+	return nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,150 @@
+package currency_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/golang/mock/gomock"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRateOverrides(t *testing.T) {
+	july := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	endOfJuly := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+
+	seed := func(t *testing.T) []*database.Transaction {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+		assert.NoError(t, gormDB.Create(&database.Currency{
+			ID:   "PLN",
+			Rate: decimal.NewFromInt(4),
+		}).Error)
+
+		txs := []*database.Transaction{
+			{SourceCurrency: "PLN", TransactionDateOnly: july.AddDate(0, 0, 10), Extra: map[string]string{}},
+			{SourceCurrency: "PLN", TransactionDateOnly: july.AddDate(0, 1, 10), Extra: map[string]string{}},
+			{SourceCurrency: "PLN", TransactionDateOnly: july.AddDate(0, 0, 11), ExchangeRateLocked: true, Extra: map[string]string{}},
+			{SourceCurrency: "EUR", TransactionDateOnly: july.AddDate(0, 0, 12), Extra: map[string]string{}},
+		}
+		assert.NoError(t, gormDB.Create(&txs).Error)
+
+		return txs
+	}
+
+	t.Run("create recalculates covered transactions", func(t *testing.T) {
+		txs := seed(t)
+
+		baseSvc := NewMockBaseAmountSvc(gomock.NewController(t))
+		baseSvc.EXPECT().RecalculateAmountInBaseCurrency(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *gorm.DB, affected []*database.Transaction) error {
+				assert.Len(t, affected, 1)
+				assert.Equal(t, txs[0].ID, affected[0].ID)
+				return nil
+			})
+
+		svc := currency.NewRateOverrideService(&currency.RateOverrideServiceConfig{
+			BaseAmountSvc: baseSvc,
+			BaseCurrency:  "USD",
+		})
+
+		override, err := svc.CreateOverride(context.TODO(), &currency.CreateRateOverrideRequest{
+			CurrencyID: "pln",
+			Rate:       decimal.NewFromInt(5),
+			ValidFrom:  july.Add(15 * time.Hour),
+			ValidTo:    &endOfJuly,
+			Note:       "bank rate",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "PLN", override.CurrencyID)
+		assert.Equal(t, july, override.ValidFrom)
+
+		list, err := svc.ListOverrides(context.TODO(), "PLN")
+		assert.NoError(t, err)
+		assert.Len(t, list, 1)
+		assert.Equal(t, "bank rate", list[0].Note)
+	})
+
+	t.Run("overlapping override", func(t *testing.T) {
+		seed(t)
+
+		assert.NoError(t, gormDB.Create(&database.CurrencyRateOverride{
+			CurrencyID: "PLN",
+			Rate:       decimal.NewFromInt(5),
+			ValidFrom:  july,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}).Error)
+
+		svc := currency.NewRateOverrideService(&currency.RateOverrideServiceConfig{BaseCurrency: "USD"})
+
+		override, err := svc.CreateOverride(context.TODO(), &currency.CreateRateOverrideRequest{
+			CurrencyID: "PLN",
+			Rate:       decimal.NewFromInt(6),
+			ValidFrom:  july.AddDate(1, 0, 0),
+		})
+		assert.ErrorContains(t, err, "overlaps an existing one")
+		assert.Nil(t, override)
+	})
+
+	t.Run("delete recalculates covered transactions", func(t *testing.T) {
+		seed(t)
+
+		existing := &database.CurrencyRateOverride{
+			CurrencyID: "PLN",
+			Rate:       decimal.NewFromInt(5),
+			ValidFrom:  july,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+		assert.NoError(t, gormDB.Create(existing).Error)
+
+		baseSvc := NewMockBaseAmountSvc(gomock.NewController(t))
+		baseSvc.EXPECT().RecalculateAmountInBaseCurrency(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *gorm.DB, affected []*database.Transaction) error {
+				assert.Len(t, affected, 2) // open ended, locked one is skipped
+				return nil
+			})
+
+		svc := currency.NewRateOverrideService(&currency.RateOverrideServiceConfig{
+			BaseAmountSvc: baseSvc,
+			BaseCurrency:  "USD",
+		})
+
+		deleted, err := svc.DeleteOverride(context.TODO(), existing.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, existing.ID, deleted.ID)
+
+		list, err := svc.ListOverrides(context.TODO(), "")
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		svc := currency.NewRateOverrideService(&currency.RateOverrideServiceConfig{BaseCurrency: "USD"})
+
+		_, err := svc.CreateOverride(context.TODO(), &currency.CreateRateOverrideRequest{
+			CurrencyID: "USD",
+			Rate:       decimal.NewFromInt(1),
+		})
+		assert.ErrorContains(t, err, "base currency rate can not be overridden")
+
+		_, err = svc.CreateOverride(context.TODO(), &currency.CreateRateOverrideRequest{
+			CurrencyID: "PLN",
+			Rate:       decimal.Zero,
+		})
+		assert.ErrorContains(t, err, "rate must be positive")
+
+		_, err = svc.CreateOverride(context.TODO(), &currency.CreateRateOverrideRequest{
+			CurrencyID: "PLN",
+			Rate:       decimal.NewFromInt(5),
+			ValidFrom:  endOfJuly,
+			ValidTo:    &july,
+		})
+		assert.ErrorContains(t, err, "valid_to must not be before valid_from")
+	})
+}
@@ -0,0 +1,326 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ProviderRemote       = "remote"
+	ProviderExchangeRate = "exchangerate"
+	ProviderECB          = "ecb"
+	ProviderNBP          = "nbp"
+	ProviderNBU          = "nbu"
+)
+
+// fetchURL performs a GET request and returns the body of a successful response.
+func fetchURL(ctx context.Context, cl httpClient, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed building request")
+	}
+
+	resp, err := cl.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch exchange rates")
+	}
+
+	if resp.Body == nil {
+		return nil, errors.New("empty response body")
+	}
+
+	if resp.StatusCode != 0 && resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+
+		return nil, errors.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+// invertRates converts quotes given as "base units per 1 unit of currency" (how
+// central banks publish them) into "currency units per 1 base unit".
+func invertRates(base string, quotes map[string]decimal.Decimal) map[string]decimal.Decimal {
+	rates := make(map[string]decimal.Decimal, len(quotes)+1)
+
+	for code, quote := range quotes {
+		if quote.IsZero() {
+			continue
+		}
+
+		rates[code] = decimal.NewFromInt(1).Div(quote)
+	}
+
+	rates[base] = decimal.NewFromInt(1)
+
+	return rates
+}
+
+// RemoteProvider reads rates already published in the go-money format (see RemoteRates),
+// e.g. the output of cmd/sync-exchange-rates.
+type RemoteProvider struct {
+	cl  httpClient
+	url string
+}
+
+func NewRemoteProvider(cl httpClient, url string) *RemoteProvider {
+	return &RemoteProvider{cl: cl, url: url}
+}
+
+func (p *RemoteProvider) Name() string {
+	return ProviderRemote
+}
+
+func (p *RemoteProvider) Fetch(ctx context.Context) (*RemoteRates, error) {
+	body, err := fetchURL(ctx, p.cl, p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var parsed *RemoteRates
+	if err = json.NewDecoder(body).Decode(&parsed); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	if parsed == nil || parsed.Base == "" {
+		return nil, errors.New("response has no base currency")
+	}
+
+	return parsed, nil
+}
+
+type exchangeRateResponse struct {
+	BaseCode        string                     `json:"base_code"`
+	ConversionRates map[string]decimal.Decimal `json:"conversion_rates"`
+}
+
+// ExchangeRateProvider reads the exchangerate-api.com v6 "latest" response.
+type ExchangeRateProvider struct {
+	cl  httpClient
+	url string
+}
+
+func NewExchangeRateProvider(cl httpClient, url string) *ExchangeRateProvider {
+	return &ExchangeRateProvider{cl: cl, url: url}
+}
+
+func (p *ExchangeRateProvider) Name() string {
+	return ProviderExchangeRate
+}
+
+func (p *ExchangeRateProvider) Fetch(ctx context.Context) (*RemoteRates, error) {
+	body, err := fetchURL(ctx, p.cl, p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var parsed exchangeRateResponse
+	if err = json.NewDecoder(body).Decode(&parsed); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	return &RemoteRates{
+		Base:  parsed.BaseCode,
+		Rates: parsed.ConversionRates,
+	}, nil
+}
+
+type ecbEnvelope struct {
+	Cube struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// ECBProvider reads the European Central Bank euro foreign exchange reference rates.
+// Rates are quoted per 1 EUR.
+type ECBProvider struct {
+	cl  httpClient
+	url string
+}
+
+func NewECBProvider(cl httpClient, url string) *ECBProvider {
+	return &ECBProvider{cl: cl, url: url}
+}
+
+func (p *ECBProvider) Name() string {
+	return ProviderECB
+}
+
+func (p *ECBProvider) Fetch(ctx context.Context) (*RemoteRates, error) {
+	body, err := fetchURL(ctx, p.cl, p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var parsed ecbEnvelope
+	if err = xml.NewDecoder(body).Decode(&parsed); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	if len(parsed.Cube.Days) == 0 {
+		return nil, errors.New("response has no rates")
+	}
+
+	day := parsed.Cube.Days[0]
+
+	rates := map[string]decimal.Decimal{
+		"EUR": decimal.NewFromInt(1),
+	}
+
+	for _, r := range day.Rates {
+		rate, rateErr := decimal.NewFromString(r.Rate)
+		if rateErr != nil {
+			return nil, errors.Wrapf(rateErr, "invalid rate for %s", r.Currency)
+		}
+
+		rates[r.Currency] = rate
+	}
+
+	result := &RemoteRates{
+		Base:  "EUR",
+		Rates: rates,
+	}
+
+	if at, parseErr := time.Parse(time.DateOnly, day.Time); parseErr == nil {
+		result.UpdatedAt = at
+	}
+
+	return result, nil
+}
+
+type nbpTable struct {
+	EffectiveDate string `json:"effectiveDate"`
+	Rates         []struct {
+		Code string          `json:"code"`
+		Mid  decimal.Decimal `json:"mid"`
+	} `json:"rates"`
+}
+
+// NBPProvider reads the National Bank of Poland average rates (table A).
+// NBP quotes PLN per 1 unit of currency, so quotes are inverted to a PLN base.
+type NBPProvider struct {
+	cl  httpClient
+	url string
+}
+
+func NewNBPProvider(cl httpClient, url string) *NBPProvider {
+	return &NBPProvider{cl: cl, url: url}
+}
+
+func (p *NBPProvider) Name() string {
+	return ProviderNBP
+}
+
+func (p *NBPProvider) Fetch(ctx context.Context) (*RemoteRates, error) {
+	body, err := fetchURL(ctx, p.cl, p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var parsed []nbpTable
+	if err = json.NewDecoder(body).Decode(&parsed); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	if len(parsed) == 0 {
+		return nil, errors.New("response has no rates")
+	}
+
+	quotes := make(map[string]decimal.Decimal, len(parsed[0].Rates))
+	for _, r := range parsed[0].Rates {
+		quotes[r.Code] = r.Mid
+	}
+
+	result := &RemoteRates{
+		Base:  "PLN",
+		Rates: invertRates("PLN", quotes),
+	}
+
+	if at, parseErr := time.Parse(time.DateOnly, parsed[0].EffectiveDate); parseErr == nil {
+		result.UpdatedAt = at
+	}
+
+	return result, nil
+}
+
+type nbuRate struct {
+	Code         string          `json:"cc"`
+	Rate         decimal.Decimal `json:"rate"`
+	ExchangeDate string          `json:"exchangedate"`
+}
+
+// NBUProvider reads the National Bank of Ukraine official rates.
+// NBU quotes UAH per 1 unit of currency, so quotes are inverted to a UAH base.
+type NBUProvider struct {
+	cl  httpClient
+	url string
+}
+
+func NewNBUProvider(cl httpClient, url string) *NBUProvider {
+	return &NBUProvider{cl: cl, url: url}
+}
+
+func (p *NBUProvider) Name() string {
+	return ProviderNBU
+}
+
+func (p *NBUProvider) Fetch(ctx context.Context) (*RemoteRates, error) {
+	body, err := fetchURL(ctx, p.cl, p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	var parsed []nbuRate
+	if err = json.NewDecoder(body).Decode(&parsed); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+
+	if len(parsed) == 0 {
+		return nil, errors.New("response has no rates")
+	}
+
+	quotes := make(map[string]decimal.Decimal, len(parsed))
+	for _, r := range parsed {
+		quotes[strings.ToUpper(r.Code)] = r.Rate
+	}
+
+	result := &RemoteRates{
+		Base:  "UAH",
+		Rates: invertRates("UAH", quotes),
+	}
+
+	if at, parseErr := time.Parse("02.01.2006", parsed[0].ExchangeDate); parseErr == nil {
+		result.UpdatedAt = at
+	}
+
+	return result, nil
+}
@@ -0,0 +1,234 @@
+package currency_test
+
+import (
+	"context"
+	_ "embed"
+	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+//go:embed testdata/exchangerate.json
+var exchangeRateFixture []byte
+
+//go:embed testdata/ecb.xml
+var ecbFixture []byte
+
+//go:embed testdata/nbp.json
+var nbpFixture []byte
+
+//go:embed testdata/nbu.json
+var nbuFixture []byte
+
+const providerURL = "https://some-api.com/rates"
+
+func TestExchangeRateProvider(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewBytesResponder(200, exchangeRateFixture))
+
+		resp, err := currency.NewExchangeRateProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.NoError(t, err)
+
+		assert.EqualValues(t, "USD", resp.Base)
+		assert.Len(t, resp.Rates, 163)
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewErrorResponder(assert.AnError))
+
+		resp, err := currency.NewExchangeRateProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewStringResponder(200, "invalid json"))
+
+		resp, err := currency.NewExchangeRateProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("invalid  status code", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewStringResponder(500, "Internal Server Error"))
+
+		resp, err := currency.NewExchangeRateProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.ErrorContains(t, err, "unexpected status code: 500")
+		assert.Nil(t, resp)
+	})
+}
+
+func TestRemoteProvider(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewBytesResponder(200, mockResponse))
+
+		resp, err := currency.NewRemoteProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.NoError(t, err)
+
+		assert.EqualValues(t, "USD", resp.Base)
+		assert.Len(t, resp.Rates, 3)
+		assert.EqualValues(t, "3.8", resp.Rates["PLN"].String())
+	})
+
+	t.Run("missing base", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewStringResponder(200, `{"r":{"USD":"1"}}`))
+
+		resp, err := currency.NewRemoteProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.ErrorContains(t, err, "no base currency")
+		assert.Nil(t, resp)
+	})
+}
+
+func TestECBProvider(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewBytesResponder(200, ecbFixture))
+
+		p := currency.NewECBProvider(http.DefaultClient, providerURL)
+		assert.Equal(t, currency.ProviderECB, p.Name())
+
+		resp, err := p.Fetch(context.TODO())
+		assert.NoError(t, err)
+
+		assert.EqualValues(t, "EUR", resp.Base)
+		assert.Len(t, resp.Rates, 31)
+		assert.EqualValues(t, "1", resp.Rates["EUR"].String())
+		assert.EqualValues(t, "1.1674", resp.Rates["USD"].String())
+		assert.EqualValues(t, "4.259", resp.Rates["PLN"].String())
+		assert.Equal(t, "2025-07-15", resp.UpdatedAt.Format("2006-01-02"))
+	})
+
+	t.Run("no rates", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewStringResponder(200, `<Envelope><Cube></Cube></Envelope>`))
+
+		resp, err := currency.NewECBProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.ErrorContains(t, err, "no rates")
+		assert.Nil(t, resp)
+	})
+
+	t.Run("invalid rate", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewStringResponder(200, `<Envelope><Cube><Cube time="2025-07-15"><Cube currency="USD" rate="abc"/></Cube></Cube></Envelope>`))
+
+		resp, err := currency.NewECBProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.ErrorContains(t, err, "invalid rate for USD")
+		assert.Nil(t, resp)
+	})
+}
+
+func TestNBPProvider(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewBytesResponder(200, nbpFixture))
+
+		p := currency.NewNBPProvider(http.DefaultClient, providerURL)
+		assert.Equal(t, currency.ProviderNBP, p.Name())
+
+		resp, err := p.Fetch(context.TODO())
+		assert.NoError(t, err)
+
+		assert.EqualValues(t, "PLN", resp.Base)
+		assert.Len(t, resp.Rates, 34)
+		assert.EqualValues(t, "1", resp.Rates["PLN"].String())
+		assert.EqualValues(t, "0.2741", resp.Rates["USD"].StringFixed(4)) // 1 / 3.6480
+		assert.EqualValues(t, "0.2348", resp.Rates["EUR"].StringFixed(4)) // 1 / 4.2590
+		assert.Equal(t, "2025-07-15", resp.UpdatedAt.Format("2006-01-02"))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewStringResponder(200, `[]`))
+
+		resp, err := currency.NewNBPProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.ErrorContains(t, err, "no rates")
+		assert.Nil(t, resp)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewStringResponder(404, "404 NotFound"))
+
+		resp, err := currency.NewNBPProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.ErrorContains(t, err, "unexpected status code: 404")
+		assert.Nil(t, resp)
+	})
+}
+
+func TestNBUProvider(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewBytesResponder(200, nbuFixture))
+
+		p := currency.NewNBUProvider(http.DefaultClient, providerURL)
+		assert.Equal(t, currency.ProviderNBU, p.Name())
+
+		resp, err := p.Fetch(context.TODO())
+		assert.NoError(t, err)
+
+		assert.EqualValues(t, "UAH", resp.Base)
+		assert.Len(t, resp.Rates, 21)
+		assert.EqualValues(t, "1", resp.Rates["UAH"].String())
+		assert.EqualValues(t, "0.02394", resp.Rates["USD"].StringFixed(5)) // 1 / 41.7765
+		assert.Equal(t, "2025-07-15", resp.UpdatedAt.Format("2006-01-02"))
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		httpmock.Activate(t)
+		defer httpmock.Deactivate()
+
+		httpmock.RegisterResponder("GET", providerURL,
+			httpmock.NewStringResponder(200, `{"error":"x"}`))
+
+		resp, err := currency.NewNBUProvider(http.DefaultClient, providerURL).Fetch(context.TODO())
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+}
@@ -88,6 +88,13 @@ func (s *Service) CreateCurrency(
 		UpdatedAt:     time.Now().UTC(),
 	}
 
+	if iso, ok := LookupIso(currency.ID); ok { // proto has no metadata fields yet
+		currency.Name = iso.Name
+		currency.Symbol = iso.Symbol
+		currency.SymbolPosition = iso.SymbolPosition
+		currency.NumericCode = iso.NumericCode
+	}
+
 	rate, err := decimal.NewFromString(req.Currency.Rate)
 	if err != nil {
 		return nil, err
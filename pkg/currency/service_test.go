@@ -187,6 +187,9 @@ func TestCreateCurrency(t *testing.T) {
 		assert.EqualValues(t, "5.21", cur.Rate.String())
 		assert.True(t, cur.IsActive)
 		assert.EqualValues(t, 2, cur.DecimalPlaces)
+		assert.Equal(t, "US Dollar", cur.Name)
+		assert.Equal(t, "$", cur.Symbol)
+		assert.EqualValues(t, 840, cur.NumericCode)
 	})
 
 	t.Run("fail duplicate", func(t *testing.T) {
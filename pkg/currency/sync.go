@@ -3,15 +3,14 @@ package currency
 import (
 	"context"
 	_ "embed"
-	"encoding/json"
 	"fmt"
 	"github.com/cockroachdb/errors"
 	"github.com/ft-t/go-money/pkg/configuration"
 	"github.com/ft-t/go-money/pkg/database"
 	"github.com/rs/zerolog"
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
-	"net/http"
 	"time"
 )
 
@@ -37,8 +36,14 @@ func (s *Syncer) RebaseRates(
 	_ context.Context,
 	currentRates *RemoteRates,
 ) (*RemoteRates, error) {
-	newBase := s.cfg.BaseCurrency
+	return Rebase(currentRates, s.cfg.BaseCurrency)
+}
 
+// Rebase converts rates to be quoted against newBase.
+func Rebase(
+	currentRates *RemoteRates,
+	newBase string,
+) (*RemoteRates, error) {
 	newBaseRate, ok := currentRates.Rates[newBase]
 	if !ok {
 		return nil, fmt.Errorf("missing rate for new base %v", newBase)
@@ -68,31 +73,31 @@ func (s *Syncer) RebaseRates(
 		Base:      newBase,
 		Rates:     rebased,
 		UpdatedAt: currentRates.UpdatedAt,
+		Sources:   currentRates.Sources,
 	}, nil
 }
 
+// Sync fetches rates through the configured provider chain and stores them. remoteURL
+// backs the "remote" provider, which is the default chain when none is configured.
 func (s *Syncer) Sync(
 	ctx context.Context,
 	remoteURL string,
 ) error {
-	httpReq, httpReqErr := http.NewRequestWithContext(ctx, "GET", remoteURL, nil)
-	if httpReqErr != nil {
-		return httpReqErr
-	}
-
-	resp, err := s.cl.Do(httpReq)
+	chain, err := NewRateChainFromConfig(s.cl, s.cfg.RateProviders, s.cfg.BaseCurrency, remoteURL, ProviderRemote)
 	if err != nil {
-		return err
+		return errors.Wrap(err, "failed to build exchange rate provider chain")
 	}
 
-	if resp.Body != nil {
-		defer func() {
-			_ = resp.Body.Close()
-		}()
-	}
+	return s.SyncFrom(ctx, chain)
+}
 
-	var parsed *RemoteRates
-	if err = json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+// SyncFrom stores rates returned by source, rebasing them to the base currency if needed.
+func (s *Syncer) SyncFrom(
+	ctx context.Context,
+	source RateProvider,
+) error {
+	parsed, err := source.Fetch(ctx)
+	if err != nil {
 		return err
 	}
 
@@ -113,7 +118,21 @@ func (s *Syncer) Sync(
 
 	parsed.Rates[s.cfg.BaseCurrency] = decimal.NewFromInt(1)
 
+	var customCurrencies []string
+	if err = tx.Model(&database.Currency{}).Unscoped().Where("is_custom").
+		Pluck("id", &customCurrencies).Error; err != nil {
+		return errors.Wrap(err, "failed to get custom currencies")
+	}
+
+	for _, custom := range customCurrencies {
+		delete(parsed.Rates, custom) // pseudo currency rates are maintained manually
+	}
+
 	for currency, rate := range parsed.Rates {
+		record := newSyncedCurrency(currency)
+		record.Rate = rate
+		record.UpdatedAt = time.Now().UTC()
+
 		if err = tx.Clauses(clause.OnConflict{
 			OnConstraint: "currencies_pk",
 			DoUpdates: clause.Set{
@@ -123,17 +142,34 @@ func (s *Syncer) Sync(
 					},
 					Value: rate,
 				},
+				{
+					Column: clause.Column{
+						Name: "name",
+					},
+					Value: clause.Expr{SQL: "COALESCE(NULLIF(currencies.name, ''), excluded.name)"},
+				},
+				{
+					Column: clause.Column{
+						Name: "symbol",
+					},
+					Value: clause.Expr{SQL: "COALESCE(NULLIF(currencies.symbol, ''), excluded.symbol)"},
+				},
+				{
+					Column: clause.Column{
+						Name: "numeric_code",
+					},
+					Value: clause.Expr{SQL: "COALESCE(NULLIF(currencies.numeric_code, 0), excluded.numeric_code)"},
+				},
 			},
-		}).Create(&database.Currency{
-			ID:            currency,
-			Rate:          rate,
-			DecimalPlaces: configuration.DefaultDecimalPlaces,
-			UpdatedAt:     time.Now().UTC(),
-		}).Error; err != nil {
+		}).Create(record).Error; err != nil {
 			return err
 		}
 	}
 
+	if err = s.recordHistory(tx, parsed); err != nil {
+		return err
+	}
+
 	if s.cfg.UpdateTransactionAmountInBaseCurrency {
 		if err = s.baseAmountSvc.RecalculateAmountInBaseCurrencyForAll(ctx, tx); err != nil {
 			return errors.Wrap(err, "failed to recalculate")
@@ -142,3 +178,34 @@ func (s *Syncer) Sync(
 
 	return tx.Commit().Error
 }
+
+// recordHistory keeps the synced rates for the day they were published, so reports can
+// value balances at historical rates.
+func (s *Syncer) recordHistory(tx *gorm.DB, rates *RemoteRates) error {
+	day := rates.UpdatedAt
+	if day.IsZero() {
+		day = time.Now()
+	}
+
+	day = truncateDay(day.UTC())
+	now := time.Now().UTC()
+
+	history := make([]*database.CurrencyRateHistory, 0, len(rates.Rates))
+	for currency, rate := range rates.Rates {
+		history = append(history, &database.CurrencyRateHistory{
+			CurrencyID: currency,
+			Date:       day,
+			Rate:       rate,
+			UpdatedAt:  now,
+		})
+	}
+
+	if err := tx.Clauses(clause.OnConflict{
+		OnConstraint: "currency_rate_history_pk",
+		DoUpdates:    clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(&history).Error; err != nil {
+		return errors.Wrap(err, "failed to record rate history")
+	}
+
+	return nil
+}
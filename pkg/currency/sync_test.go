@@ -15,6 +15,7 @@ import (
 	"io"
 	"net/http"
 	"testing"
+	"time"
 )
 
 //go:embed testdata/rates.json
@@ -62,6 +63,14 @@ func TestSync(t *testing.T) {
 		assert.Equal(t, "USD", currencies[2].ID)
 		assert.EqualValues(t, "1", currencies[2].Rate.String())
 		assert.EqualValues(t, 2, currencies[2].DecimalPlaces)
+
+		var history []*database.CurrencyRateHistory
+		assert.NoError(t, gormDB.Order("currency_id asc").Find(&history).Error)
+
+		assert.Len(t, history, 3)
+		assert.Equal(t, "EUR", history[0].CurrencyID)
+		assert.EqualValues(t, "0.85", history[0].Rate.String())
+		assert.Equal(t, time.Now().UTC().Format(time.DateOnly), history[0].Date.Format(time.DateOnly))
 	})
 
 	t.Run("success with rebase", func(t *testing.T) {
@@ -234,6 +243,56 @@ func TestSync(t *testing.T) {
 		assert.ErrorContains(t, err, "missing rate for new base")
 	})
 
+	t.Run("pseudo currencies keep rate and iso metadata is filled", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+		assert.NoError(t, gormDB.Create(&database.Currency{
+			ID:       "PTS",
+			Rate:     decimal.NewFromInt(100),
+			IsCustom: true,
+		}).Error)
+		assert.NoError(t, gormDB.Create(&database.Currency{
+			ID:     "EUR",
+			Rate:   decimal.NewFromInt(1),
+			Symbol: "EUR ",
+		}).Error)
+
+		provider := NewMockRateProvider(gomock.NewController(t))
+		provider.EXPECT().Fetch(gomock.Any()).Return(&currency.RemoteRates{
+			Base: "USD",
+			Rates: map[string]decimal.Decimal{
+				"EUR": decimal.RequireFromString("0.85"),
+				"JPY": decimal.NewFromInt(150),
+				"PTS": decimal.NewFromInt(1),
+			},
+		}, nil)
+
+		syn := currency.NewSyncer(nil, nil, configuration.CurrencyConfig{
+			BaseCurrency: "USD",
+		})
+
+		assert.NoError(t, syn.SyncFrom(context.TODO(), provider))
+
+		var currencies []*database.Currency
+		assert.NoError(t, gormDB.Order("id asc").Find(&currencies).Error)
+		assert.Len(t, currencies, 4)
+
+		assert.Equal(t, "EUR", currencies[0].ID)
+		assert.Equal(t, "Euro", currencies[0].Name)
+		assert.Equal(t, "EUR ", currencies[0].Symbol) // user set symbol is kept
+		assert.EqualValues(t, 978, currencies[0].NumericCode)
+
+		assert.Equal(t, "JPY", currencies[1].ID)
+		assert.Equal(t, "¥", currencies[1].Symbol)
+		assert.EqualValues(t, 0, currencies[1].DecimalPlaces)
+
+		assert.Equal(t, "PTS", currencies[2].ID)
+		assert.EqualValues(t, "100", currencies[2].Rate.String())
+
+		var history []*database.CurrencyRateHistory
+		assert.NoError(t, gormDB.Where("currency_id = ?", "PTS").Find(&history).Error)
+		assert.Empty(t, history)
+	})
+
 	t.Run("fail request", func(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 		remoteURL := "https://localhost/rates.json"
@@ -1,6 +1,7 @@
 package currency
 
 import (
+	"github.com/ft-t/go-money/pkg/database"
 	"github.com/shopspring/decimal"
 	"time"
 )
@@ -9,6 +10,7 @@ type RemoteRates struct {
 	Base      string                     `json:"b"`
 	Rates     map[string]decimal.Decimal `json:"r"`
 	UpdatedAt time.Time                  `json:"u"`
+	Sources   map[string]string          `json:"s,omitempty"` // provider each rate came from
 }
 
 type Quote struct {
@@ -20,3 +22,29 @@ type Quote struct {
 	ToRate       decimal.Decimal // rate of To vs BaseCurrency
 	BaseCurrency string
 }
+
+type CreateRateOverrideRequest struct {
+	CurrencyID string
+	Rate       decimal.Decimal // units of currency per 1 base currency unit
+	ValidFrom  time.Time
+	ValidTo    *time.Time // nil means open ended
+	Note       string
+}
+
+type UpsertCurrencyRequest struct {
+	ID             string
+	Name           string
+	Symbol         *string
+	SymbolPosition *database.CurrencySymbolPosition
+	NumericCode    int32
+	DecimalPlaces  *int32
+	IsActive       *bool
+	IsCustom       bool             // pseudo currency, only honored on create
+	Rate           *decimal.Decimal // initial rate vs base currency, only used on create
+}
+
+type SetCustomRateRequest struct {
+	CurrencyID string
+	Rate       decimal.Decimal // units of currency per 1 base currency unit
+	Date       time.Time       // day the rate applies from, today when zero
+}
@@ -38,6 +38,7 @@ type Account struct {
 	DisplayOrder     *int32
 
 	FirstTransactionAt *time.Time
+	Timezone           *string // IANA name, household timezone when empty
 }
 
 func (a *Account) IsDefault() bool {
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type SecurityKind int16
+
+const (
+	SecurityKindStock     SecurityKind = 1
+	SecurityKindETF       SecurityKind = 2
+	SecurityKindFund      SecurityKind = 3
+	SecurityKindBond      SecurityKind = 4
+	SecurityKindCrypto    SecurityKind = 5
+	SecurityKindCommodity SecurityKind = 6
+)
+
+type CostMethod int16
+
+const (
+	CostMethodFIFO    CostMethod = 1
+	CostMethodAverage CostMethod = 2
+)
+
+type TradeKind int16
+
+const (
+	TradeKindBuy      TradeKind = 1
+	TradeKindSell     TradeKind = 2
+	TradeKindDividend TradeKind = 3
+	TradeKindSplit    TradeKind = 4
+)
+
+// Security is a stock, fund, crypto asset or commodity priced in Currency.
+type Security struct {
+	ID       int32
+	Symbol   string
+	Name     string
+	Kind     SecurityKind `gorm:"type:smallint"`
+	Currency string
+
+	QuantityDecimals int32
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+// SecurityPrice is the closing price of a security for a day.
+type SecurityPrice struct {
+	SecurityID int32     `gorm:"primaryKey"`
+	Date       time.Time `gorm:"primaryKey;type:date"`
+	Price      decimal.Decimal
+	Source     string
+	UpdatedAt  time.Time
+}
+
+// InvestmentAccount marks an asset account as holding securities.
+type InvestmentAccount struct {
+	ID         int32
+	AccountID  int32
+	CostMethod CostMethod `gorm:"type:smallint"`
+
+	// CashAccountID is the account trades are settled with, trades do not post transactions when nil.
+	CashAccountID *int32
+	// DividendAccountID is the income account dividends are posted from, default income account when nil.
+	DividendAccountID *int32
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+type InvestmentTrade struct {
+	ID         int64
+	AccountID  int32
+	SecurityID int32
+	Kind       TradeKind `gorm:"type:smallint"`
+	TradeDate  time.Time `gorm:"type:date"`
+
+	Quantity decimal.Decimal // split ratio for splits
+	Price    decimal.Decimal // per unit in security currency
+	Fee      decimal.Decimal // in account currency
+
+	// Amount is the cash settled in account currency: paid for buys, received for sells and dividends.
+	Amount        decimal.Decimal
+	TransactionID *int64
+	Note          string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
@@ -0,0 +1,23 @@
+package database
+
+import "time"
+
+// JobRun is a single execution of a built-in background job, such as exchange rate sync.
+type JobRun struct {
+	ID         int64
+	Job        string
+	Trigger    ScheduleRunTrigger
+	Status     ScheduleRunStatus
+	Instance   string // hostname of the replica which executed the run
+	StartedAt  time.Time
+	FinishedAt *time.Time
+	Error      *string
+}
+
+// JobStatus describes a registered job together with its latest run. It is not stored.
+type JobStatus struct {
+	Name      string
+	Schedule  string
+	NextRunAt *time.Time
+	LastRun   *JobRun
+}
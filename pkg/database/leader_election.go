@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"gorm.io/gorm"
+)
+
+var ErrNotLeader = errors.New("replica is not the leader")
+
+// LeaderElector elects a single replica using a session level postgres advisory lock.
+// The lock is held on a dedicated connection, so it is released as soon as the leader
+// process dies or loses its connection. It implements gocron.Elector.
+type LeaderElector struct {
+	db      *gorm.DB
+	lockKey int64
+	mut     sync.Mutex
+	conn    *sql.Conn
+}
+
+func NewLeaderElector(db *gorm.DB, lockKey int64) *LeaderElector {
+	return &LeaderElector{
+		db:      db,
+		lockKey: lockKey,
+	}
+}
+
+// IsLeader returns nil when this replica holds the advisory lock, trying to acquire it otherwise.
+func (e *LeaderElector) IsLeader(ctx context.Context) error {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	if e.conn != nil {
+		if err := e.conn.PingContext(ctx); err == nil {
+			return nil
+		}
+
+		_ = e.conn.Close() // session is gone, so is the lock
+		e.conn = nil
+	}
+
+	rawDb, err := e.db.DB()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	conn, err := rawDb.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection for leader election")
+	}
+
+	var acquired bool
+	if err = conn.QueryRowContext(ctx, "select pg_try_advisory_lock($1)", e.lockKey).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return errors.Wrap(err, "failed to acquire advisory lock")
+	}
+
+	if !acquired {
+		_ = conn.Close()
+		return ErrNotLeader
+	}
+
+	e.conn = conn
+
+	return nil
+}
+
+// Close releases the advisory lock, letting another replica take over.
+func (e *LeaderElector) Close() error {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+
+	_, err := e.conn.ExecContext(context.Background(), "select pg_advisory_unlock($1)", e.lockKey)
+	err = errors.CombineErrors(err, e.conn.Close())
+	e.conn = nil
+
+	return err
+}
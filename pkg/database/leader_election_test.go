@@ -0,0 +1,47 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/database"
+)
+
+func TestLeaderElector(t *testing.T) {
+	t.Run("single leader", func(t *testing.T) {
+		first := database.NewLeaderElector(gormDB, 9001)
+		second := database.NewLeaderElector(gormDB, 9001)
+
+		defer func() {
+			_ = first.Close()
+			_ = second.Close()
+		}()
+
+		assert.NoError(t, first.IsLeader(context.TODO()))
+		assert.NoError(t, first.IsLeader(context.TODO()))
+		assert.ErrorIs(t, second.IsLeader(context.TODO()), database.ErrNotLeader)
+
+		assert.NoError(t, first.Close())
+		assert.NoError(t, second.IsLeader(context.TODO()))
+		assert.ErrorIs(t, first.IsLeader(context.TODO()), database.ErrNotLeader)
+	})
+
+	t.Run("different keys", func(t *testing.T) {
+		first := database.NewLeaderElector(gormDB, 9002)
+		second := database.NewLeaderElector(gormDB, 9003)
+
+		defer func() {
+			_ = first.Close()
+			_ = second.Close()
+		}()
+
+		assert.NoError(t, first.IsLeader(context.TODO()))
+		assert.NoError(t, second.IsLeader(context.TODO()))
+	})
+
+	t.Run("close without lock", func(t *testing.T) {
+		assert.NoError(t, database.NewLeaderElector(gormDB, 9004).Close())
+	})
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type LoanKind int16
+
+const (
+	LoanKindLoan       LoanKind = 1
+	LoanKindCreditLine LoanKind = 2
+)
+
+type LoanCompounding int16
+
+const (
+	LoanCompoundingMonthly LoanCompounding = 1
+	LoanCompoundingDaily   LoanCompounding = 2
+)
+
+// Loan holds repayment terms of a liability account.
+type Loan struct {
+	ID        int32
+	AccountID int32
+	Kind      LoanKind `gorm:"type:smallint"`
+
+	// Principal is the borrowed amount for loans and the limit for credit lines.
+	Principal   decimal.Decimal
+	AnnualRate  decimal.Decimal // percent, mirrored to accounts.liability_percent
+	TermMonths  int32
+	PaymentDay  int32
+	Compounding LoanCompounding `gorm:"type:smallint"`
+	StartDate   time.Time
+
+	InterestAccountID  *int32 // expense account for accruals, default expense account when nil
+	InterestCategoryID *int32
+	LastAccruedAt      *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
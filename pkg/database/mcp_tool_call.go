@@ -0,0 +1,27 @@
+package database
+
+import "time"
+
+type McpToolCallStatus int16
+
+const (
+	McpToolCallStatusSuccess  McpToolCallStatus = 1
+	McpToolCallStatusError    McpToolCallStatus = 2
+	McpToolCallStatusRejected McpToolCallStatus = 3 // refused by the per-token rate limiter, the tool did not run
+)
+
+// McpToolCall is an audit log entry of a single MCP tool call.
+type McpToolCall struct {
+	ID         int64
+	TokenID    string // jti of the token used for the call
+	UserID     int32
+	Tool       string
+	Arguments  map[string]any    `gorm:"type:jsonb;serializer:json"`
+	Status     McpToolCallStatus `gorm:"type:smallint"`
+	Error      *string
+	Rows       int32 // rows returned to the client, counted towards the row limit
+	DurationMs int64
+	CreatedAt  time.Time
+}
+
+func (McpToolCall) TableName() string { return "mcp_tool_calls" }
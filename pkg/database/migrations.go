@@ -492,5 +492,395 @@ create unique index if not exists ix_uniq_record on double_entries (transaction_
 				)
 			},
 		},
+		{
+			ID: "2026-05-03-AddRuleTestCases",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE TABLE IF NOT EXISTS rule_test_cases (
+						id             SERIAL PRIMARY KEY,
+						rule_id        INT       NOT NULL,
+						name           TEXT      NOT NULL,
+						transaction_id BIGINT,
+						input          JSONB,
+						expected       JSONB     NOT NULL,
+						created_at     TIMESTAMP NOT NULL,
+						updated_at     TIMESTAMP NOT NULL,
+						deleted_at     TIMESTAMP
+					);`,
+					`CREATE INDEX IF NOT EXISTS ix_rule_test_cases_rule_id ON rule_test_cases(rule_id) WHERE deleted_at IS NULL;`,
+				)
+			},
+		},
+		{
+			ID: "2026-05-10-AddRuleTriggers",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`alter table rules add column if not exists trigger_events integer[];`,
+					`alter table rules add column if not exists import_sources integer[];`,
+				)
+			},
+		},
+		{
+			ID: "2026-05-17-AddRuleRevisions",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE TABLE IF NOT EXISTS rule_revisions (
+						id               BIGSERIAL PRIMARY KEY,
+						rule_id          INT       NOT NULL,
+						title            TEXT,
+						script           TEXT      NOT NULL,
+						interpreter_type INT       NOT NULL,
+						sort_order       INT       NOT NULL,
+						enabled          BOOLEAN   NOT NULL,
+						is_final_rule    BOOLEAN   NOT NULL,
+						group_name       TEXT      NOT NULL,
+						trigger_events   INTEGER[],
+						import_sources   INTEGER[],
+						author_type      SMALLINT,
+						author_user_id   INT,
+						author_detail    TEXT,
+						created_at       TIMESTAMP NOT NULL
+					);`,
+					`CREATE INDEX IF NOT EXISTS ix_rule_revisions_rule_id ON rule_revisions(rule_id, id);`,
+					`alter table rules add column if not exists revision_id bigint;`,
+					`alter table transaction_history add column if not exists actor_rule_revision_id bigint;`,
+					`INSERT INTO rule_revisions (rule_id, title, script, interpreter_type, sort_order, enabled,
+						is_final_rule, group_name, trigger_events, import_sources, author_detail, created_at)
+					SELECT id, title, script, interpreter_type, sort_order, enabled,
+						is_final_rule, group_name, trigger_events, import_sources, 'migration', updated_at
+					FROM rules WHERE revision_id IS NULL;`,
+					`UPDATE rules SET revision_id = rr.id
+					FROM rule_revisions rr
+					WHERE rr.rule_id = rules.id AND rules.revision_id IS NULL;`,
+				)
+			},
+		},
+		{
+			ID: "2026-05-24-AddLuaModules",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE TABLE IF NOT EXISTS lua_modules (
+						id          SERIAL PRIMARY KEY,
+						name        TEXT      NOT NULL,
+						description TEXT      NOT NULL DEFAULT '',
+						script      TEXT      NOT NULL,
+						created_at  TIMESTAMP NOT NULL,
+						updated_at  TIMESTAMP NOT NULL,
+						deleted_at  TIMESTAMP
+					);`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS ix_uniq_lua_modules_name ON lua_modules(name) WHERE deleted_at IS NULL;`,
+				)
+			},
+		},
+		{
+			ID: "2026-05-31-AddScheduleRuleRuns",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE TABLE IF NOT EXISTS schedule_rule_runs (
+						id               BIGSERIAL PRIMARY KEY,
+						schedule_rule_id INT       NOT NULL,
+						trigger          SMALLINT  NOT NULL,
+						status           SMALLINT  NOT NULL,
+						effective_date   TIMESTAMP NOT NULL,
+						started_at       TIMESTAMP NOT NULL,
+						finished_at      TIMESTAMP,
+						error            TEXT,
+						transaction_ids  BIGINT[]
+					);`,
+					`CREATE INDEX IF NOT EXISTS ix_schedule_rule_runs_rule_id ON schedule_rule_runs(schedule_rule_id, id);`,
+				)
+			},
+		},
+		{
+			ID: "2026-06-07-AddLoans",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE TABLE IF NOT EXISTS loans (
+						id                   SERIAL PRIMARY KEY,
+						account_id           INT       NOT NULL,
+						kind                 SMALLINT  NOT NULL,
+						principal            DECIMAL   NOT NULL,
+						annual_rate          DECIMAL   NOT NULL,
+						term_months          INT       NOT NULL,
+						payment_day          INT       NOT NULL,
+						compounding          SMALLINT  NOT NULL,
+						start_date           TIMESTAMP NOT NULL,
+						interest_account_id  INT,
+						interest_category_id INT,
+						last_accrued_at      TIMESTAMP,
+						created_at           TIMESTAMP NOT NULL,
+						updated_at           TIMESTAMP NOT NULL,
+						deleted_at           TIMESTAMP
+					);`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS ix_uniq_loans_account_id ON loans(account_id) WHERE deleted_at IS NULL;`,
+				)
+			},
+		},
+		{
+			ID: "2026-06-14-AddExchangeRateOverrides",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC;`,
+					`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS exchange_rate_source SMALLINT NOT NULL DEFAULT 0;`,
+					`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS exchange_rate_locked BOOLEAN NOT NULL DEFAULT FALSE;`,
+					`CREATE TABLE IF NOT EXISTS currency_rate_overrides (
+						id          SERIAL PRIMARY KEY,
+						currency_id TEXT      NOT NULL,
+						rate        NUMERIC   NOT NULL,
+						valid_from  DATE      NOT NULL,
+						valid_to    DATE,
+						note        TEXT      NOT NULL DEFAULT '',
+						created_at  TIMESTAMP NOT NULL,
+						updated_at  TIMESTAMP NOT NULL,
+						deleted_at  TIMESTAMP
+					);`,
+					`CREATE INDEX IF NOT EXISTS ix_currency_rate_overrides_currency ON currency_rate_overrides(currency_id, valid_from) WHERE deleted_at IS NULL;`,
+				)
+			},
+		},
+		{
+			ID: "2026-06-21-AddInvestments",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE TABLE IF NOT EXISTS securities (
+						id                SERIAL PRIMARY KEY,
+						symbol            TEXT      NOT NULL,
+						name              TEXT      NOT NULL DEFAULT '',
+						kind              SMALLINT  NOT NULL,
+						currency          TEXT      NOT NULL,
+						quantity_decimals INT       NOT NULL DEFAULT 0,
+						created_at        TIMESTAMP NOT NULL,
+						updated_at        TIMESTAMP NOT NULL,
+						deleted_at        TIMESTAMP
+					);`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS ix_uniq_securities_symbol ON securities(symbol) WHERE deleted_at IS NULL;`,
+					`CREATE TABLE IF NOT EXISTS security_prices (
+						security_id INT       NOT NULL,
+						date        DATE      NOT NULL,
+						price       NUMERIC   NOT NULL,
+						source      TEXT      NOT NULL DEFAULT '',
+						updated_at  TIMESTAMP NOT NULL,
+						CONSTRAINT security_prices_pk PRIMARY KEY (security_id, date)
+					);`,
+					`CREATE TABLE IF NOT EXISTS investment_accounts (
+						id                  SERIAL PRIMARY KEY,
+						account_id          INT       NOT NULL,
+						cost_method         SMALLINT  NOT NULL,
+						cash_account_id     INT,
+						dividend_account_id INT,
+						created_at          TIMESTAMP NOT NULL,
+						updated_at          TIMESTAMP NOT NULL,
+						deleted_at          TIMESTAMP
+					);`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS ix_uniq_investment_accounts_account_id ON investment_accounts(account_id) WHERE deleted_at IS NULL;`,
+					`CREATE TABLE IF NOT EXISTS investment_trades (
+						id             BIGSERIAL PRIMARY KEY,
+						account_id     INT       NOT NULL,
+						security_id    INT       NOT NULL,
+						kind           SMALLINT  NOT NULL,
+						trade_date     DATE      NOT NULL,
+						quantity       NUMERIC   NOT NULL,
+						price          NUMERIC   NOT NULL,
+						fee            NUMERIC   NOT NULL,
+						amount         NUMERIC   NOT NULL,
+						transaction_id BIGINT,
+						note           TEXT      NOT NULL DEFAULT '',
+						created_at     TIMESTAMP NOT NULL,
+						updated_at     TIMESTAMP NOT NULL,
+						deleted_at     TIMESTAMP
+					);`,
+					`CREATE INDEX IF NOT EXISTS ix_investment_trades_account ON investment_trades(account_id, trade_date, id) WHERE deleted_at IS NULL;`,
+					`ALTER TABLE daily_stat ADD COLUMN IF NOT EXISTS holdings_value NUMERIC;`,
+				)
+			},
+		},
+		{
+			ID: "2026-06-28-AddFxRevaluations",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE TABLE IF NOT EXISTS currency_rate_history (
+						currency_id TEXT      NOT NULL,
+						date        DATE      NOT NULL,
+						rate        NUMERIC   NOT NULL,
+						updated_at  TIMESTAMP NOT NULL,
+						CONSTRAINT currency_rate_history_pk PRIMARY KEY (currency_id, date)
+					);`,
+					`INSERT INTO currency_rate_history(currency_id, date, rate, updated_at)
+						SELECT id, current_date, rate, now() FROM currencies WHERE deleted_at IS NULL
+						ON CONFLICT DO NOTHING;`,
+					`CREATE TABLE IF NOT EXISTS fx_revaluations (
+						id           BIGSERIAL PRIMARY KEY,
+						account_id   INT       NOT NULL,
+						currency     TEXT      NOT NULL,
+						period_from  DATE      NOT NULL,
+						period_to    DATE      NOT NULL,
+						opening_rate NUMERIC   NOT NULL,
+						closing_rate NUMERIC   NOT NULL,
+						amount       NUMERIC   NOT NULL,
+						created_at   TIMESTAMP NOT NULL,
+						updated_at   TIMESTAMP NOT NULL,
+						CONSTRAINT fx_revaluations_account_period UNIQUE (account_id, period_to)
+					);`,
+				)
+			},
+		},
+		{
+			ID: "2026-07-05-AddJobRuns",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE TABLE IF NOT EXISTS job_runs (
+						id          BIGSERIAL PRIMARY KEY,
+						job         TEXT      NOT NULL,
+						trigger     SMALLINT  NOT NULL,
+						status      SMALLINT  NOT NULL,
+						instance    TEXT      NOT NULL,
+						started_at  TIMESTAMP NOT NULL,
+						finished_at TIMESTAMP,
+						error       TEXT
+					);`,
+					`CREATE INDEX IF NOT EXISTS ix_job_runs_job ON job_runs(job, id);`,
+				)
+			},
+		},
+		{
+			ID: "2026-07-12-AddAccountTimezone",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`ALTER TABLE accounts ADD COLUMN IF NOT EXISTS timezone TEXT;`,
+				)
+			},
+		},
+		{
+			ID: "2026-07-19-AddCurrencyMetadata",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`ALTER TABLE currencies ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '';`,
+					`ALTER TABLE currencies ADD COLUMN IF NOT EXISTS symbol TEXT NOT NULL DEFAULT '';`,
+					`ALTER TABLE currencies ADD COLUMN IF NOT EXISTS symbol_position SMALLINT NOT NULL DEFAULT 0;`,
+					`ALTER TABLE currencies ADD COLUMN IF NOT EXISTS numeric_code INTEGER NOT NULL DEFAULT 0;`,
+					`ALTER TABLE currencies ADD COLUMN IF NOT EXISTS is_custom BOOLEAN NOT NULL DEFAULT FALSE;`,
+				)
+			},
+		},
+		{
+			ID: "2026-07-26-AddMcpToolCalls",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE TABLE IF NOT EXISTS mcp_tool_calls (
+						id          BIGSERIAL PRIMARY KEY,
+						token_id    TEXT      NOT NULL,
+						user_id     INTEGER   NOT NULL,
+						tool        TEXT      NOT NULL,
+						arguments   JSONB,
+						status      SMALLINT  NOT NULL,
+						error       TEXT,
+						rows        INTEGER   NOT NULL DEFAULT 0,
+						duration_ms BIGINT    NOT NULL DEFAULT 0,
+						created_at  TIMESTAMP NOT NULL
+					);`,
+					`CREATE INDEX IF NOT EXISTS ix_mcp_tool_calls_created_at ON mcp_tool_calls(created_at);`,
+					`CREATE INDEX IF NOT EXISTS ix_mcp_tool_calls_token ON mcp_tool_calls(token_id, created_at);`,
+				)
+			},
+		},
+		{
+			ID: "2026-08-02-AddWebhooks",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE TABLE IF NOT EXISTS webhooks (
+						id              SERIAL PRIMARY KEY,
+						name            TEXT      NOT NULL,
+						url             TEXT      NOT NULL,
+						secret          TEXT      NOT NULL,
+						event_types     INTEGER[] NOT NULL DEFAULT '{}',
+						enabled         BOOLEAN   NOT NULL DEFAULT TRUE,
+						last_history_id BIGINT    NOT NULL DEFAULT 0,
+						created_at      TIMESTAMP NOT NULL,
+						updated_at      TIMESTAMP NOT NULL,
+						deleted_at      TIMESTAMP
+					);`,
+					`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+						id              BIGSERIAL PRIMARY KEY,
+						webhook_id      INTEGER   NOT NULL,
+						history_id      BIGINT    NOT NULL,
+						event_type      SMALLINT  NOT NULL,
+						payload         JSONB     NOT NULL,
+						status          SMALLINT  NOT NULL,
+						attempts        INTEGER   NOT NULL DEFAULT 0,
+						next_attempt_at TIMESTAMP NOT NULL,
+						last_attempt_at TIMESTAMP,
+						response_code   INTEGER,
+						error           TEXT,
+						duration_ms     BIGINT    NOT NULL DEFAULT 0,
+						created_at      TIMESTAMP NOT NULL,
+						delivered_at    TIMESTAMP
+					);`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS ux_webhook_deliveries_event
+						ON webhook_deliveries(webhook_id, history_id) WHERE history_id > 0;`,
+					`CREATE INDEX IF NOT EXISTS ix_webhook_deliveries_pending
+						ON webhook_deliveries(next_attempt_at) WHERE status = 1;`,
+					`CREATE INDEX IF NOT EXISTS ix_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, id);`,
+				)
+			},
+		},
+		{
+			ID: "2026-08-09-AddTransactionKeysetIndexes",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE INDEX IF NOT EXISTS ix_transactions_active_date_id ON transactions (transaction_date_time, id)
+						WHERE deleted_at IS NULL;`,
+					`CREATE INDEX IF NOT EXISTS ix_transactions_active_created_id ON transactions (created_at, id)
+						WHERE deleted_at IS NULL;`,
+				)
+			},
+		},
+		{
+			ID: "2026-08-16-AddTransactionSearch",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`CREATE EXTENSION IF NOT EXISTS pg_trgm;`,
+					`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS search_vector tsvector;`,
+					`CREATE OR REPLACE FUNCTION transaction_search_document(
+						title TEXT, notes TEXT, reference_number TEXT, internal_reference_numbers TEXT[], extra JSONB
+					) RETURNS tsvector LANGUAGE sql STABLE AS $$
+						SELECT setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+							setweight(to_tsvector('simple', coalesce(reference_number, '') || ' ' ||
+								coalesce(array_to_string(internal_reference_numbers, ' '), '')), 'B') ||
+							setweight(to_tsvector('simple', coalesce(notes, '')), 'C') ||
+							setweight(jsonb_to_tsvector('simple', coalesce(extra, '{}'::jsonb), '["string"]'), 'D')
+					$$;`,
+					`CREATE OR REPLACE FUNCTION transactions_search_vector_trigger() RETURNS trigger LANGUAGE plpgsql AS $$
+					BEGIN
+						NEW.search_vector := transaction_search_document(
+							NEW.title, NEW.notes, NEW.reference_number, NEW.internal_reference_numbers, NEW.extra);
+						RETURN NEW;
+					END
+					$$;`,
+					`DROP TRIGGER IF EXISTS transactions_search_vector ON transactions;`,
+					`CREATE TRIGGER transactions_search_vector
+						BEFORE INSERT OR UPDATE OF title, notes, reference_number, internal_reference_numbers, extra
+						ON transactions FOR EACH ROW EXECUTE FUNCTION transactions_search_vector_trigger();`,
+					`UPDATE transactions SET search_vector = transaction_search_document(
+						title, notes, reference_number, internal_reference_numbers, extra);`,
+					`CREATE INDEX IF NOT EXISTS ix_transactions_search_vector ON transactions USING GIN (search_vector)
+						WHERE deleted_at IS NULL;`,
+					`CREATE INDEX IF NOT EXISTS ix_transactions_title_trgm ON transactions USING GIN (title gin_trgm_ops)
+						WHERE deleted_at IS NULL;`,
+				)
+			},
+		},
+		{
+			ID: "2026-08-23-AddTransactionHistoryFeedID",
+			Migrate: func(db *gorm.DB) error {
+				return boilerplate.ExecuteSql(db,
+					`ALTER TABLE transaction_history ADD COLUMN IF NOT EXISTS feed_id BIGINT;`,
+					`UPDATE transaction_history SET feed_id = id WHERE feed_id IS NULL;`,
+					`CREATE UNIQUE INDEX IF NOT EXISTS ux_transaction_history_feed_id ON transaction_history(feed_id);`,
+					`CREATE INDEX IF NOT EXISTS ix_transaction_history_unsequenced ON transaction_history(id)
+						WHERE feed_id IS NULL;`,
+					`ALTER TABLE webhooks RENAME COLUMN last_history_id TO last_feed_id;`,
+				)
+			},
+		},
 	}
 }
@@ -2,10 +2,20 @@ package database
 
 import (
 	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 	"time"
 )
 
+type RuleTriggerEvent int32
+
+const (
+	RuleTriggerEventCreated  RuleTriggerEvent = 1
+	RuleTriggerEventUpdated  RuleTriggerEvent = 2
+	RuleTriggerEventImported RuleTriggerEvent = 3
+	RuleTriggerEventManual   RuleTriggerEvent = 4
+)
+
 type Rule struct {
 	ID              int32
 	Title           string
@@ -18,6 +28,28 @@ type Rule struct {
 	IsFinalRule     bool
 	DeletedAt       gorm.DeletedAt
 	GroupName       string
+	TriggerEvents   pq.Int32Array `gorm:"type:integer[]"` // RuleTriggerEvent values, empty = created, updated and imported
+	ImportSources   pq.Int32Array `gorm:"type:integer[]"` // importv1.ImportSource values, empty = any source
+	RevisionID      *int64        // current rule_revisions.id
+}
+
+// RuleRevision is an immutable copy of a rule, created on every change.
+type RuleRevision struct {
+	ID              int64
+	RuleID          int32
+	Title           string
+	Script          string
+	InterpreterType gomoneypbv1.RuleInterpreterType
+	SortOrder       int32
+	Enabled         bool
+	IsFinalRule     bool
+	GroupName       string
+	TriggerEvents   pq.Int32Array                `gorm:"type:integer[]"`
+	ImportSources   pq.Int32Array                `gorm:"type:integer[]"`
+	AuthorType      *TransactionHistoryActorType `gorm:"type:smallint"`
+	AuthorUserID    *int32
+	AuthorDetail    *string
+	CreatedAt       time.Time
 }
 
 type ScheduleRule struct {
@@ -33,3 +65,55 @@ type ScheduleRule struct {
 	LastRunAt       *time.Time
 	GroupName       string
 }
+
+type ScheduleRunTrigger int16
+
+const (
+	ScheduleRunTriggerCron    ScheduleRunTrigger = 1
+	ScheduleRunTriggerCatchUp ScheduleRunTrigger = 2
+	ScheduleRunTriggerManual  ScheduleRunTrigger = 3
+)
+
+type ScheduleRunStatus int16
+
+const (
+	ScheduleRunStatusRunning ScheduleRunStatus = 1
+	ScheduleRunStatusSuccess ScheduleRunStatus = 2
+	ScheduleRunStatusFailed  ScheduleRunStatus = 3
+)
+
+// ScheduleRuleRun is a single execution of a schedule rule.
+type ScheduleRuleRun struct {
+	ID             int64
+	ScheduleRuleID int32
+	Trigger        ScheduleRunTrigger
+	Status         ScheduleRunStatus
+	EffectiveDate  time.Time // date the run acts for, used as transaction date
+	StartedAt      time.Time
+	FinishedAt     *time.Time
+	Error          *string
+	TransactionIDs pq.Int64Array `gorm:"type:bigint[]"`
+}
+
+type RuleTestCase struct {
+	ID            int32
+	RuleID        int32
+	Name          string
+	TransactionID *int64         // when set, input is loaded from the existing transaction
+	Input         map[string]any `gorm:"serializer:json"` // transaction snapshot, same keys as transaction_history.snapshot
+	Expected      map[string]any `gorm:"serializer:json"` // subset of snapshot keys expected after rule execution
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt
+}
+
+// LuaModule is a named Lua library shared between rule scripts via require("name").
+type LuaModule struct {
+	ID          int32
+	Name        string
+	Description string
+	Script      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt
+}
@@ -11,6 +11,8 @@ type DailyStat struct {
 	Date      time.Time `gorm:"primaryKey"`
 
 	Amount decimal.Decimal
+	// HoldingsValue is the market value of securities at the end of the day, investment accounts only.
+	HoldingsValue decimal.NullDecimal
 }
 
 func (*DailyStat) TableName() string {
@@ -23,3 +25,22 @@ type MonthlyStat struct {
 
 	Balance decimal.Decimal
 }
+
+// FxRevaluation records the unrealized fx gain or loss of a foreign currency account over a period,
+// in base currency. It does not change the account balance, which is kept in account currency.
+type FxRevaluation struct {
+	ID          int64
+	AccountID   int32
+	Currency    string
+	PeriodFrom  time.Time `gorm:"type:date"`
+	PeriodTo    time.Time `gorm:"type:date"`
+	OpeningRate decimal.Decimal
+	ClosingRate decimal.Decimal
+	Amount      decimal.Decimal // in base currency, positive is a gain
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (*FxRevaluation) TableName() string {
+	return "fx_revaluations"
+}
@@ -12,11 +12,49 @@ type Currency struct {
 
 	IsActive bool
 
-	DecimalPlaces int32
-	UpdatedAt     time.Time
-	DeletedAt     gorm.DeletedAt
+	DecimalPlaces  int32
+	Name           string
+	Symbol         string
+	SymbolPosition CurrencySymbolPosition
+	NumericCode    int32 // ISO 4217 numeric code, 0 for pseudo currencies
+	IsCustom       bool  // user defined pseudo currency, rates are maintained manually and never synced
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt
 }
 
+type CurrencySymbolPosition int16
+
+const (
+	CurrencySymbolPositionBefore CurrencySymbolPosition = 0 // $10.00
+	CurrencySymbolPositionAfter  CurrencySymbolPosition = 1 // 10.00 zł
+)
+
 func (c *Currency) TableName() string {
 	return "currencies"
 }
+
+// CurrencyRateOverride is a user defined rate vs base currency that takes precedence
+// over the synced currencies.rate for transactions dated within [ValidFrom, ValidTo].
+type CurrencyRateOverride struct {
+	ID         int32
+	CurrencyID string
+	Rate       decimal.Decimal
+	ValidFrom  time.Time  `gorm:"type:date"`
+	ValidTo    *time.Time `gorm:"type:date"` // nil means open ended
+	Note       string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt
+}
+
+// CurrencyRateHistory is the synced rate vs base currency of a currency on a day.
+type CurrencyRateHistory struct {
+	CurrencyID string    `gorm:"primaryKey"`
+	Date       time.Time `gorm:"primaryKey;type:date"`
+	Rate       decimal.Decimal
+	UpdatedAt  time.Time
+}
+
+func (*CurrencyRateHistory) TableName() string {
+	return "currency_rate_history"
+}
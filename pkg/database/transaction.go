@@ -23,6 +23,11 @@ type Transaction struct {
 	DestinationCurrency             string
 	DestinationAmountInBaseCurrency decimal.NullDecimal
 
+	// ExchangeRate is the source currency rate vs base currency used for the base amounts.
+	ExchangeRate       decimal.NullDecimal
+	ExchangeRateSource ExchangeRateSource `gorm:"type:smallint"`
+	ExchangeRateLocked bool               // locked rates are kept on recalculation
+
 	SourceAccountID      int32
 	DestinationAccountID int32
 
@@ -53,9 +58,20 @@ type Transaction struct {
 }
 
 type RuleAppliedEvent struct {
-	RuleID int32
-	Before *Transaction
-	After  *Transaction
+	RuleID         int32
+	RuleRevisionID *int64
+	Before         *Transaction
+	After          *Transaction
 }
 
 type TransactionFlags int64
+
+type ExchangeRateSource int16
+
+const (
+	ExchangeRateSourceUnspecified ExchangeRateSource = 0
+	ExchangeRateSourceSynced      ExchangeRateSource = 1 // currencies.rate
+	ExchangeRateSourceOverride    ExchangeRateSource = 2 // currency_rate_overrides
+	ExchangeRateSourceDerived     ExchangeRateSource = 3 // actual amounts, one side is in base currency
+	ExchangeRateSourceManual      ExchangeRateSource = 4 // set by user
+)
@@ -19,6 +19,7 @@ const (
 	TransactionHistoryActorTypeScheduler TransactionHistoryActorType = 3
 	TransactionHistoryActorTypeImporter  TransactionHistoryActorType = 4
 	TransactionHistoryActorTypeBulk      TransactionHistoryActorType = 5
+	TransactionHistoryActorTypeMcp       TransactionHistoryActorType = 6
 )
 
 type TransactionHistory struct {
@@ -29,9 +30,13 @@ type TransactionHistory struct {
 	ActorUserID   *int32
 	ActorRuleID   *int32
 	ActorExtra    *string
-	Snapshot      map[string]any `gorm:"type:jsonb;serializer:json"`
-	Diff          map[string]any `gorm:"type:jsonb;serializer:json"`
-	OccurredAt    time.Time
+	// ActorRuleRevisionID points to rule_revisions.id for rule-applied events.
+	ActorRuleRevisionID *int64
+	Snapshot            map[string]any `gorm:"type:jsonb;serializer:json"`
+	Diff                map[string]any `gorm:"type:jsonb;serializer:json"`
+	OccurredAt          time.Time
+	// FeedID is the position in the change feed, given out after the transaction committed.
+	FeedID *int64
 }
 
 func (TransactionHistory) TableName() string { return "transaction_history" }
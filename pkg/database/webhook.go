@@ -0,0 +1,55 @@
+package database
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type WebhookDeliveryStatus int16
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = 1 // waiting for the first attempt or a retry
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = 2
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = 3 // all attempts failed
+)
+
+// Webhook receives transaction history events as signed HTTP POST requests.
+type Webhook struct {
+	ID         int32
+	Name       string
+	URL        string
+	Secret     string        // HMAC-SHA256 key of the signature header
+	EventTypes pq.Int32Array `gorm:"type:integer[]"` // TransactionHistoryEventType values, empty = all
+	Enabled    bool
+
+	// LastFeedID is the transaction_history.feed_id up to which deliveries were created.
+	LastFeedID int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+func (Webhook) TableName() string { return "webhooks" }
+
+// WebhookDelivery is a single event sent to a webhook, together with the outcome of its latest attempt.
+type WebhookDelivery struct {
+	ID            int64
+	WebhookID     int32
+	HistoryID     int64                       // transaction_history.id, 0 for pings
+	EventType     TransactionHistoryEventType `gorm:"type:smallint"`
+	Payload       map[string]any              `gorm:"type:jsonb;serializer:json"` // request body, identical for every attempt
+	Status        WebhookDeliveryStatus       `gorm:"type:smallint"`
+	Attempts      int32
+	NextAttemptAt time.Time
+	LastAttemptAt *time.Time
+	ResponseCode  *int32
+	Error         *string
+	DurationMs    int64
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+}
+
+func (WebhookDelivery) TableName() string { return "webhook_deliveries" }
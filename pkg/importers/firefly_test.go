@@ -438,7 +438,7 @@ func TestFireflyIntegration(t *testing.T) {
 	ruleSvc := rules.NewExecutor(nil)
 
 	txSvc := transactions.NewService(&transactions.ServiceConfig{
-		StatsSvc:             transactions.NewStatService(),
+		StatsSvc:             transactions.NewStatService(&transactions.StatServiceConfig{}),
 		MapperSvc:            m,
 		CurrencyConverterSvc: converter,
 		BaseAmountService:    baseAmountSvc,
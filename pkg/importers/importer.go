@@ -14,6 +14,7 @@ import (
 	"github.com/ft-t/go-money/pkg/database"
 	"github.com/ft-t/go-money/pkg/transactions"
 	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
@@ -155,6 +156,7 @@ func (i *Importer) Import(
 	}
 
 	ctx = history.WithActor(ctx, history.ImporterActor(importerSourceName(req.Source)))
+	ctx = rules.WithTrigger(ctx, rules.ImportTrigger(req.Source))
 
 	tx := database.FromContext(ctx, database.GetDb(database.DbTypeMaster)).Begin()
 	defer tx.Rollback()
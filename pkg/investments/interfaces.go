@@ -0,0 +1,54 @@
+package investments
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
+	v1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
+)
+
+//go:generate mockgen -destination interfaces_mocks_test.go -package investments_test -source=interfaces.go
+
+type AccountSvc interface {
+	GetAccountByID(ctx context.Context, id int32) (*database.Account, error)
+	GetDefaultAccount(ctx context.Context, accountType v1.AccountType) (*database.Account, error)
+}
+
+type TransactionSvc interface {
+	UpsertRawTransactions(
+		ctx context.Context,
+		created []*database.Transaction,
+		updated []*database.Transaction,
+	) ([]*transactionsv1.CreateTransactionResponse, error)
+	DeleteTransaction(
+		ctx context.Context,
+		req *transactionsv1.DeleteTransactionsRequest,
+	) (*transactionsv1.DeleteTransactionsResponse, error)
+}
+
+type DecimalSvc interface {
+	GetCurrencyDecimals(ctx context.Context, currency string) int32
+}
+
+type CurrencyConverterSvc interface {
+	Convert(
+		ctx context.Context,
+		fromCurrency string,
+		toCurrency string,
+		amount decimal.Decimal,
+	) (decimal.Decimal, error)
+}
+
+// PriceProvider returns prices of the given securities, at least for the requested date when available.
+type PriceProvider interface {
+	Name() string
+	Fetch(ctx context.Context, securities []*database.Security, date time.Time) ([]*database.SecurityPrice, error)
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
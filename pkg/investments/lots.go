@@ -0,0 +1,170 @@
+package investments
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
+)
+
+type position struct {
+	lots         []*Lot
+	realizedGain decimal.Decimal
+	dividends    decimal.Decimal
+}
+
+func (p *position) quantity() decimal.Decimal {
+	total := decimal.Zero
+	for _, lot := range p.lots {
+		total = total.Add(lot.Quantity)
+	}
+
+	return total
+}
+
+func (p *position) cost() decimal.Decimal {
+	total := decimal.Zero
+	for _, lot := range p.lots {
+		total = total.Add(lot.Cost)
+	}
+
+	return total
+}
+
+// sortTrades orders trades by date and id, trades not stored yet go last within their day.
+func sortTrades(trades []*database.InvestmentTrade) {
+	sort.SliceStable(trades, func(i, j int) bool {
+		if !trades[i].TradeDate.Equal(trades[j].TradeDate) {
+			return trades[i].TradeDate.Before(trades[j].TradeDate)
+		}
+
+		if trades[i].ID == 0 || trades[j].ID == 0 {
+			return trades[j].ID == 0 && trades[i].ID != 0
+		}
+
+		return trades[i].ID < trades[j].ID
+	})
+}
+
+// replayTrades builds open lots per security. FIFO sells consume the oldest lots first,
+// average cost accounts pool every buy into a single lot. Splits scale quantities and keep cost.
+func replayTrades(method database.CostMethod, trades []*database.InvestmentTrade) (map[int32]*position, error) {
+	sortTrades(trades)
+
+	positions := map[int32]*position{}
+
+	for _, trade := range trades {
+		pos, ok := positions[trade.SecurityID]
+		if !ok {
+			pos = &position{}
+			positions[trade.SecurityID] = pos
+		}
+
+		switch trade.Kind {
+		case database.TradeKindBuy:
+			if method == database.CostMethodAverage && len(pos.lots) > 0 {
+				pos.lots[0].Quantity = pos.lots[0].Quantity.Add(trade.Quantity)
+				pos.lots[0].Cost = pos.lots[0].Cost.Add(trade.Amount)
+
+				continue
+			}
+
+			pos.lots = append(pos.lots, &Lot{
+				TradeID:  trade.ID,
+				Date:     trade.TradeDate,
+				Quantity: trade.Quantity,
+				Cost:     trade.Amount,
+			})
+		case database.TradeKindSell:
+			if held := pos.quantity(); held.LessThan(trade.Quantity) {
+				return nil, errors.Newf("trade on %s sells %s units of security %d, only %s held",
+					trade.TradeDate.Format(time.DateOnly), trade.Quantity, trade.SecurityID, held)
+			}
+
+			remaining := trade.Quantity
+			consumedCost := decimal.Zero
+
+			for remaining.IsPositive() {
+				lot := pos.lots[0]
+
+				taken := decimal.Min(lot.Quantity, remaining)
+				cost := lot.Cost
+				if taken.LessThan(lot.Quantity) {
+					cost = lot.Cost.Mul(taken).Div(lot.Quantity)
+				}
+
+				lot.Quantity = lot.Quantity.Sub(taken)
+				lot.Cost = lot.Cost.Sub(cost)
+				remaining = remaining.Sub(taken)
+				consumedCost = consumedCost.Add(cost)
+
+				if lot.Quantity.IsZero() {
+					pos.lots = pos.lots[1:]
+				}
+			}
+
+			pos.realizedGain = pos.realizedGain.Add(trade.Amount.Sub(consumedCost))
+		case database.TradeKindDividend:
+			pos.dividends = pos.dividends.Add(trade.Amount)
+		case database.TradeKindSplit:
+			for _, lot := range pos.lots {
+				lot.Quantity = lot.Quantity.Mul(trade.Quantity)
+			}
+		default:
+			return nil, errors.Newf("unsupported trade kind: %d", trade.Kind)
+		}
+	}
+
+	return positions, nil
+}
+
+// walkDays replays trades and prices day by day from the first trade and calls fn with quantities held
+// and latest known prices per security at the end of every day in [from, to]. Buys and sells update the
+// price, splits adjust it by the ratio and price rows of the day take precedence. Trades must be sorted.
+func walkDays(
+	trades []*database.InvestmentTrade,
+	prices []*database.SecurityPrice,
+	from time.Time,
+	to time.Time,
+	fn func(day time.Time, quantities map[int32]decimal.Decimal, prices map[int32]decimal.Decimal),
+) {
+	if len(trades) == 0 {
+		return
+	}
+
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].Date.Before(prices[j].Date)
+	})
+
+	quantities := map[int32]decimal.Decimal{}
+	latest := map[int32]decimal.Decimal{}
+	tradeIdx, priceIdx := 0, 0
+
+	for day := trades[0].TradeDate; !day.After(to); day = day.AddDate(0, 0, 1) {
+		for ; tradeIdx < len(trades) && !trades[tradeIdx].TradeDate.After(day); tradeIdx++ {
+			trade := trades[tradeIdx]
+
+			switch trade.Kind {
+			case database.TradeKindBuy:
+				quantities[trade.SecurityID] = quantities[trade.SecurityID].Add(trade.Quantity)
+				latest[trade.SecurityID] = trade.Price
+			case database.TradeKindSell:
+				quantities[trade.SecurityID] = quantities[trade.SecurityID].Sub(trade.Quantity)
+				latest[trade.SecurityID] = trade.Price
+			case database.TradeKindSplit:
+				quantities[trade.SecurityID] = quantities[trade.SecurityID].Mul(trade.Quantity)
+				latest[trade.SecurityID] = latest[trade.SecurityID].Div(trade.Quantity)
+			}
+		}
+
+		for ; priceIdx < len(prices) && !prices[priceIdx].Date.After(day); priceIdx++ {
+			latest[prices[priceIdx].SecurityID] = prices[priceIdx].Price
+		}
+
+		if !day.Before(from) {
+			fn(day, quantities, latest)
+		}
+	}
+}
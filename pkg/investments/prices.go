@@ -0,0 +1,183 @@
+package investments
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
+)
+
+// NewPriceProvider builds the provider selected in configuration.
+func NewPriceProvider(cl httpClient, cfg configuration.InvestmentsConfig) (PriceProvider, error) {
+	switch strings.ToLower(cfg.PriceProvider) {
+	case "", PriceProviderManual:
+		return NewManualPriceProvider(), nil
+	case PriceProviderCSV:
+		if cfg.PricesCSVURL == "" {
+			return nil, errors.New("prices csv url is required for csv price provider")
+		}
+
+		return NewCSVPriceProvider(cl, cfg.PricesCSVURL), nil
+	default:
+		return nil, errors.Newf("unknown price provider %q", cfg.PriceProvider)
+	}
+}
+
+// ManualPriceProvider is a local stand-in that fetches nothing. Holdings are valued
+// with trade prices and prices imported manually.
+type ManualPriceProvider struct {
+}
+
+func NewManualPriceProvider() *ManualPriceProvider {
+	return &ManualPriceProvider{}
+}
+
+func (p *ManualPriceProvider) Name() string {
+	return PriceProviderManual
+}
+
+func (p *ManualPriceProvider) Fetch(_ context.Context, _ []*database.Security, _ time.Time) ([]*database.SecurityPrice, error) {
+	return nil, nil
+}
+
+// CSVPriceProvider reads symbol,date,price rows from an http(s) url or a local file.
+// Every row of a known security is returned, so the file can also backfill history.
+type CSVPriceProvider struct {
+	cl  httpClient
+	url string
+}
+
+func NewCSVPriceProvider(cl httpClient, url string) *CSVPriceProvider {
+	return &CSVPriceProvider{cl: cl, url: url}
+}
+
+func (p *CSVPriceProvider) Name() string {
+	return PriceProviderCSV
+}
+
+func (p *CSVPriceProvider) Fetch(
+	ctx context.Context,
+	securities []*database.Security,
+	_ time.Time,
+) ([]*database.SecurityPrice, error) {
+	body, err := p.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	quotes, err := ParsePricesCSV(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveQuotes(securities, quotes, p.Name()), nil
+}
+
+func (p *CSVPriceProvider) open(ctx context.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(p.url, "http://") && !strings.HasPrefix(p.url, "https://") {
+		f, err := os.Open(p.url)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open prices file")
+		}
+
+		return f, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed building request")
+	}
+
+	resp, err := p.cl.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch prices")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+
+		return nil, errors.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+// ParsePricesCSV reads symbol,date,price rows, date in YYYY-MM-DD format. A header row is skipped.
+func ParsePricesCSV(r io.Reader) ([]*PriceQuote, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read csv")
+	}
+
+	var quotes []*PriceQuote
+	for i, row := range rows {
+		if len(row) == 0 || (len(row) == 1 && strings.TrimSpace(row[0]) == "") {
+			continue
+		}
+
+		if i == 0 && strings.EqualFold(strings.TrimSpace(row[0]), "symbol") {
+			continue
+		}
+
+		if len(row) < 3 {
+			return nil, errors.Newf("line %d: expected symbol,date,price", i+1)
+		}
+
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(row[1]))
+		if err != nil {
+			return nil, errors.Newf("line %d: invalid date %q", i+1, row[1])
+		}
+
+		price, err := decimal.NewFromString(strings.TrimSpace(row[2]))
+		if err != nil || price.IsNegative() {
+			return nil, errors.Newf("line %d: invalid price %q", i+1, row[2])
+		}
+
+		quotes = append(quotes, &PriceQuote{
+			Symbol: strings.ToUpper(strings.TrimSpace(row[0])),
+			Date:   date,
+			Price:  price,
+		})
+	}
+
+	return quotes, nil
+}
+
+func resolveQuotes(securities []*database.Security, quotes []*PriceQuote, source string) []*database.SecurityPrice {
+	bySymbol := make(map[string]*database.Security, len(securities))
+	for _, sec := range securities {
+		bySymbol[strings.ToUpper(sec.Symbol)] = sec
+	}
+
+	var prices []*database.SecurityPrice
+	for _, q := range quotes {
+		sec, ok := bySymbol[q.Symbol]
+		if !ok {
+			continue
+		}
+
+		prices = append(prices, &database.SecurityPrice{
+			SecurityID: sec.ID,
+			Date:       q.Date,
+			Price:      q.Price,
+			Source:     source,
+		})
+	}
+
+	return prices
+}
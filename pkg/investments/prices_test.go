@@ -0,0 +1,114 @@
+package investments_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/investments"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePricesCSV(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		quotes, err := investments.ParsePricesCSV(strings.NewReader("symbol,date,price\naapl, 2026-01-05, 180.25\n\nBTC,2026-01-05,42000\n"))
+		require.NoError(t, err)
+
+		require.Len(t, quotes, 2)
+		assert.Equal(t, "AAPL", quotes[0].Symbol)
+		assert.Equal(t, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), quotes[0].Date)
+		assert.Equal(t, "180.25", quotes[0].Price.String())
+		assert.Equal(t, "BTC", quotes[1].Symbol)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		_, err := investments.ParsePricesCSV(strings.NewReader("AAPL,05.01.2026,180"))
+		assert.ErrorContains(t, err, "line 1: invalid date")
+	})
+
+	t.Run("invalid price", func(t *testing.T) {
+		_, err := investments.ParsePricesCSV(strings.NewReader("symbol,date,price\nAAPL,2026-01-05,-1"))
+		assert.ErrorContains(t, err, "line 2: invalid price")
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		_, err := investments.ParsePricesCSV(strings.NewReader("AAPL,2026-01-05"))
+		assert.ErrorContains(t, err, "expected symbol,date,price")
+	})
+}
+
+func TestCSVPriceProvider(t *testing.T) {
+	securities := []*database.Security{
+		{ID: 1, Symbol: "AAPL"},
+		{ID: 2, Symbol: "VWCE"},
+	}
+
+	t.Run("http", func(t *testing.T) {
+		cl := &http.Client{}
+		httpmock.ActivateNonDefault(cl)
+		t.Cleanup(httpmock.DeactivateAndReset)
+
+		httpmock.RegisterResponder(http.MethodGet, "https://localhost/prices.csv",
+			httpmock.NewStringResponder(http.StatusOK, "AAPL,2026-01-05,180\nMSFT,2026-01-05,400\nVWCE,2026-01-06,120.5\n"))
+
+		provider := investments.NewCSVPriceProvider(cl, "https://localhost/prices.csv")
+		assert.Equal(t, investments.PriceProviderCSV, provider.Name())
+
+		prices, err := provider.Fetch(context.TODO(), securities, time.Now())
+		require.NoError(t, err)
+
+		require.Len(t, prices, 2)
+		assert.EqualValues(t, 1, prices[0].SecurityID)
+		assert.Equal(t, "180", prices[0].Price.String())
+		assert.EqualValues(t, 2, prices[1].SecurityID)
+		assert.Equal(t, investments.PriceProviderCSV, prices[1].Source)
+	})
+
+	t.Run("http error", func(t *testing.T) {
+		cl := &http.Client{}
+		httpmock.ActivateNonDefault(cl)
+		t.Cleanup(httpmock.DeactivateAndReset)
+
+		httpmock.RegisterResponder(http.MethodGet, "https://localhost/prices.csv",
+			httpmock.NewStringResponder(http.StatusNotFound, ""))
+
+		prices, err := investments.NewCSVPriceProvider(cl, "https://localhost/prices.csv").
+			Fetch(context.TODO(), securities, time.Now())
+		assert.ErrorContains(t, err, "unexpected status code: 404")
+		assert.Nil(t, prices)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "prices.csv")
+		require.NoError(t, os.WriteFile(path, []byte("VWCE,2026-01-06,120.5\n"), 0o600))
+
+		prices, err := investments.NewCSVPriceProvider(nil, path).Fetch(context.TODO(), securities, time.Now())
+		require.NoError(t, err)
+		require.Len(t, prices, 1)
+		assert.EqualValues(t, 2, prices[0].SecurityID)
+	})
+}
+
+func TestNewPriceProvider(t *testing.T) {
+	provider, err := investments.NewPriceProvider(http.DefaultClient, configuration.InvestmentsConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, investments.PriceProviderManual, provider.Name())
+
+	prices, err := provider.Fetch(context.TODO(), nil, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, prices)
+
+	_, err = investments.NewPriceProvider(http.DefaultClient, configuration.InvestmentsConfig{PriceProvider: "csv"})
+	assert.ErrorContains(t, err, "prices csv url is required")
+
+	_, err = investments.NewPriceProvider(http.DefaultClient, configuration.InvestmentsConfig{PriceProvider: "yahoo"})
+	assert.ErrorContains(t, err, `unknown price provider "yahoo"`)
+}
@@ -0,0 +1,783 @@
+package investments
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const importPriceSource = "import"
+
+// upsertHoldingsValue writes holdings_value and keeps amount of existing rows. Days without
+// a row are before the first transaction of the account, so their balance is zero.
+const upsertHoldingsValue = `insert into daily_stat(account_id, date, amount, holdings_value)
+select ?, v.date, 0, v.value
+from unnest((?)::date[], (?)::numeric[]) as v(date, value)
+on conflict on constraint daily_stat_pk do update set holdings_value = excluded.holdings_value`
+
+type Service struct {
+	cfg *ServiceConfig
+}
+
+type ServiceConfig struct {
+	AccountSvc           AccountSvc
+	TransactionSvc       TransactionSvc
+	DecimalSvc           DecimalSvc
+	CurrencyConverterSvc CurrencyConverterSvc
+	PriceProvider        PriceProvider
+}
+
+func NewService(cfg *ServiceConfig) *Service {
+	return &Service{
+		cfg: cfg,
+	}
+}
+
+func (s *Service) CreateSecurity(ctx context.Context, req *CreateSecurityRequest) (*database.Security, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(req.Symbol))
+	if symbol == "" {
+		return nil, errors.New("symbol is required")
+	}
+
+	if req.Kind < database.SecurityKindStock || req.Kind > database.SecurityKindCommodity {
+		return nil, errors.Newf("unsupported security kind: %d", req.Kind)
+	}
+
+	if req.QuantityDecimals < 0 || req.QuantityDecimals > 18 {
+		return nil, errors.New("quantity decimals must be between 0 and 18")
+	}
+
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var cur database.Currency
+	if err := db.Where("id = ?", strings.ToUpper(req.Currency)).Limit(1).Find(&cur).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get currency")
+	}
+
+	if cur.ID == "" {
+		return nil, errors.Newf("currency %s not found", req.Currency)
+	}
+
+	var existing int64
+	if err := db.Model(&database.Security{}).Where("symbol = ?", symbol).Count(&existing).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to check existing securities")
+	}
+
+	if existing > 0 {
+		return nil, errors.Newf("security %s already exists", symbol)
+	}
+
+	security := &database.Security{
+		Symbol:           symbol,
+		Name:             req.Name,
+		Kind:             req.Kind,
+		Currency:         cur.ID,
+		QuantityDecimals: req.QuantityDecimals,
+	}
+
+	if err := db.Create(security).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to create security")
+	}
+
+	return security, nil
+}
+
+func (s *Service) ListSecurities(ctx context.Context) ([]*database.Security, error) {
+	var securities []*database.Security
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).
+		Order("symbol").Find(&securities).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to list securities")
+	}
+
+	return securities, nil
+}
+
+// SetInvestmentAccount marks an asset account as holding securities or updates its settings.
+func (s *Service) SetInvestmentAccount(
+	ctx context.Context,
+	req *SetInvestmentAccountRequest,
+) (*database.InvestmentAccount, error) {
+	if req.CostMethod != database.CostMethodFIFO && req.CostMethod != database.CostMethodAverage {
+		return nil, errors.Newf("unsupported cost method: %d", req.CostMethod)
+	}
+
+	account, err := s.cfg.AccountSvc.GetAccountByID(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Type != gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET {
+		return nil, errors.Newf("account %d is not an asset account", account.ID)
+	}
+
+	if req.CashAccountID != nil {
+		if *req.CashAccountID == account.ID {
+			return nil, errors.New("cash account must differ from the investment account")
+		}
+
+		cashAccount, cashErr := s.cfg.AccountSvc.GetAccountByID(ctx, *req.CashAccountID)
+		if cashErr != nil {
+			return nil, cashErr
+		}
+
+		if cashAccount.Type != gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET {
+			return nil, errors.Newf("cash account %d is not an asset account", cashAccount.ID)
+		}
+
+		if cashAccount.Currency != account.Currency {
+			return nil, errors.Newf("cash account currency %s does not match account currency %s",
+				cashAccount.Currency, account.Currency)
+		}
+	}
+
+	if req.DividendAccountID != nil {
+		dividendAccount, dividendErr := s.cfg.AccountSvc.GetAccountByID(ctx, *req.DividendAccountID)
+		if dividendErr != nil {
+			return nil, dividendErr
+		}
+
+		if dividendAccount.Type != gomoneypbv1.AccountType_ACCOUNT_TYPE_INCOME {
+			return nil, errors.Newf("dividend account %d is not an income account", dividendAccount.ID)
+		}
+	}
+
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var investmentAccount database.InvestmentAccount
+	if err = db.Where("account_id = ?", req.AccountID).Limit(1).Find(&investmentAccount).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get investment account")
+	}
+
+	investmentAccount.AccountID = req.AccountID
+	investmentAccount.CostMethod = req.CostMethod
+	investmentAccount.CashAccountID = req.CashAccountID
+	investmentAccount.DividendAccountID = req.DividendAccountID
+
+	if err = db.Save(&investmentAccount).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to save investment account")
+	}
+
+	return &investmentAccount, nil
+}
+
+func (s *Service) GetInvestmentAccount(ctx context.Context, accountID int32) (*database.InvestmentAccount, error) {
+	var investmentAccount database.InvestmentAccount
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).
+		Where("account_id = ?", accountID).First(&investmentAccount).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to get investment account %d", accountID)
+	}
+
+	return &investmentAccount, nil
+}
+
+// RecordTrade stores a trade, settles it with the cash account and revalues holdings from the trade date.
+// Buys transfer cash into the investment account, sells transfer proceeds out and dividends are posted
+// as income of the cash account, so the investment account balance is the net amount invested.
+func (s *Service) RecordTrade(ctx context.Context, req *RecordTradeRequest) (*database.InvestmentTrade, error) {
+	if err := s.validateTrade(req); err != nil {
+		return nil, err
+	}
+
+	investmentAccount, err := s.GetInvestmentAccount(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.cfg.AccountSvc.GetAccountByID(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	security, err := s.getSecurity(ctx, req.SecurityID)
+	if err != nil {
+		return nil, err
+	}
+
+	trade := &database.InvestmentTrade{
+		AccountID:  req.AccountID,
+		SecurityID: req.SecurityID,
+		Kind:       req.Kind,
+		TradeDate:  truncateDay(req.TradeDate),
+		Quantity:   req.Quantity,
+		Price:      req.Price,
+		Fee:        req.Fee,
+		Amount:     req.Amount,
+		Note:       req.Note,
+	}
+
+	if trade.Amount, err = s.tradeAmount(ctx, account, security, req); err != nil {
+		return nil, err
+	}
+
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	existing, err := s.getTrades(db, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = replayTrades(investmentAccount.CostMethod, append(existing, trade)); err != nil {
+		return nil, err
+	}
+
+	if investmentAccount.CashAccountID != nil && trade.Kind != database.TradeKindSplit {
+		cashTx, txErr := s.newCashTransaction(ctx, investmentAccount, account, security, trade)
+		if txErr != nil {
+			return nil, txErr
+		}
+
+		if _, err = s.cfg.TransactionSvc.UpsertRawTransactions(ctx, []*database.Transaction{cashTx}, nil); err != nil {
+			return nil, errors.Wrap(err, "failed to post trade transaction")
+		}
+
+		trade.TransactionID = &cashTx.ID
+	}
+
+	if err = db.Create(trade).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to create trade")
+	}
+
+	if err = s.RevalueAccount(ctx, db, req.AccountID, trade.TradeDate); err != nil {
+		return nil, err
+	}
+
+	return trade, nil
+}
+
+// DeleteTrade removes a trade together with its cash transaction.
+func (s *Service) DeleteTrade(ctx context.Context, id int64) (*database.InvestmentTrade, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var trade database.InvestmentTrade
+	if err := db.Where("id = ?", id).First(&trade).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to get trade %d", id)
+	}
+
+	investmentAccount, err := s.GetInvestmentAccount(ctx, trade.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.getTrades(db, trade.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := lo.Filter(existing, func(t *database.InvestmentTrade, _ int) bool {
+		return t.ID != trade.ID
+	})
+
+	if _, err = replayTrades(investmentAccount.CostMethod, remaining); err != nil {
+		return nil, errors.Wrap(err, "trade can not be deleted")
+	}
+
+	if trade.TransactionID != nil {
+		if _, err = s.cfg.TransactionSvc.DeleteTransaction(ctx, &transactionsv1.DeleteTransactionsRequest{
+			Ids: []int64{*trade.TransactionID},
+		}); err != nil {
+			return nil, errors.Wrap(err, "failed to delete trade transaction")
+		}
+	}
+
+	if err = db.Delete(&trade).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to delete trade")
+	}
+
+	if err = s.RevalueAccount(ctx, db, trade.AccountID, trade.TradeDate); err != nil {
+		return nil, err
+	}
+
+	return &trade, nil
+}
+
+// GetHoldings returns open positions with cost basis, latest prices and gains in account currency.
+func (s *Service) GetHoldings(ctx context.Context, accountID int32) (*Portfolio, error) {
+	investmentAccount, err := s.GetInvestmentAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.cfg.AccountSvc.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.GetDbWithContext(ctx, database.DbTypeReadonly)
+
+	trades, err := s.getTrades(db, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := replayTrades(investmentAccount.CostMethod, trades)
+	if err != nil {
+		return nil, err
+	}
+
+	securityIDs := lo.Keys(positions)
+	today := truncateDay(time.Now().UTC())
+
+	prices, err := s.getPrices(db, securityIDs, today)
+	if err != nil {
+		return nil, err
+	}
+
+	latestPrices := map[int32]decimal.Decimal{}
+	walkDays(trades, prices, today, today, func(_ time.Time, _ map[int32]decimal.Decimal, p map[int32]decimal.Decimal) {
+		latestPrices = p
+	})
+
+	var securities []*database.Security
+	if err = db.Unscoped().Where("id IN ?", securityIDs).Find(&securities).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get securities")
+	}
+
+	decimals := s.cfg.DecimalSvc.GetCurrencyDecimals(ctx, account.Currency)
+	portfolio := &Portfolio{
+		Account:  investmentAccount,
+		Currency: account.Currency,
+	}
+
+	for _, security := range securities {
+		pos := positions[security.ID]
+
+		factor, factorErr := s.conversionFactor(ctx, security.Currency, account.Currency)
+		if factorErr != nil {
+			return nil, factorErr
+		}
+
+		holding := &Holding{
+			Security:     security,
+			Quantity:     pos.quantity(),
+			CostBasis:    pos.cost().Round(decimals),
+			Price:        latestPrices[security.ID],
+			RealizedGain: pos.realizedGain.Round(decimals),
+			Dividends:    pos.dividends.Round(decimals),
+			Lots:         pos.lots,
+		}
+		holding.MarketValue = holding.Quantity.Mul(holding.Price).Mul(factor).Round(decimals)
+		holding.UnrealizedGain = holding.MarketValue.Sub(holding.CostBasis)
+
+		portfolio.Holdings = append(portfolio.Holdings, holding)
+		portfolio.CostBasis = portfolio.CostBasis.Add(holding.CostBasis)
+		portfolio.MarketValue = portfolio.MarketValue.Add(holding.MarketValue)
+		portfolio.UnrealizedGain = portfolio.UnrealizedGain.Add(holding.UnrealizedGain)
+		portfolio.RealizedGain = portfolio.RealizedGain.Add(holding.RealizedGain)
+		portfolio.Dividends = portfolio.Dividends.Add(holding.Dividends)
+	}
+
+	sort.Slice(portfolio.Holdings, func(i, j int) bool {
+		return portfolio.Holdings[i].Security.Symbol < portfolio.Holdings[j].Security.Symbol
+	})
+
+	return portfolio, nil
+}
+
+// ImportPrices stores prices by symbol and revalues accounts holding the affected securities.
+func (s *Service) ImportPrices(ctx context.Context, quotes []*PriceQuote) (int, error) {
+	if len(quotes) == 0 {
+		return 0, nil
+	}
+
+	securities, err := s.ListSecurities(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	known := lo.SliceToMap(securities, func(sec *database.Security) (string, bool) {
+		return sec.Symbol, true
+	})
+
+	for _, q := range quotes {
+		if !known[q.Symbol] {
+			return 0, errors.Newf("unknown security %s", q.Symbol)
+		}
+	}
+
+	prices := resolveQuotes(securities, quotes, importPriceSource)
+	if err = s.storePrices(ctx, prices); err != nil {
+		return 0, err
+	}
+
+	return len(prices), nil
+}
+
+// SyncPrices fetches prices from the configured provider and revalues every investment account.
+func (s *Service) SyncPrices(ctx context.Context, date time.Time) error {
+	securities, err := s.ListSecurities(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(securities) > 0 {
+		prices, fetchErr := s.cfg.PriceProvider.Fetch(ctx, securities, truncateDay(date))
+		if fetchErr != nil {
+			// stale prices still give a valuation, so keep going
+			zerolog.Ctx(ctx).Error().Err(fetchErr).Str("provider", s.cfg.PriceProvider.Name()).
+				Msg("failed to fetch security prices")
+		}
+
+		if err = s.savePrices(ctx, prices); err != nil {
+			return err
+		}
+	}
+
+	return s.RevalueAll(ctx)
+}
+
+func (s *Service) RevalueAll(ctx context.Context) error {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var investmentAccounts []*database.InvestmentAccount
+	if err := db.Find(&investmentAccounts).Error; err != nil {
+		return errors.Wrap(err, "failed to get investment accounts")
+	}
+
+	var finalErr error
+	for _, investmentAccount := range investmentAccounts {
+		if err := s.RevalueAccount(ctx, db, investmentAccount.AccountID, time.Time{}); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Int32("account_id", investmentAccount.AccountID).
+				Msg("failed to revalue investment account")
+			finalErr = errors.CombineErrors(finalErr, err)
+		}
+	}
+
+	return finalErr
+}
+
+// RevalueAccount writes daily_stat.holdings_value of the account from the given date till tomorrow,
+// matching the range daily stats are generated for.
+func (s *Service) RevalueAccount(ctx context.Context, db *gorm.DB, accountID int32, from time.Time) error {
+	account, err := s.cfg.AccountSvc.GetAccountByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	trades, err := s.getTrades(db, accountID)
+	if err != nil {
+		return err
+	}
+
+	from = truncateDay(from)
+
+	if len(trades) == 0 {
+		if err = db.Model(&database.DailyStat{}).Where("account_id = ? AND date >= ?", accountID, from).
+			UpdateColumn("holdings_value", nil).Error; err != nil {
+			return errors.Wrap(err, "failed to reset holdings value")
+		}
+
+		return nil
+	}
+
+	sortTrades(trades)
+
+	if from.Before(trades[0].TradeDate) {
+		from = trades[0].TradeDate
+	}
+
+	to := truncateDay(time.Now().UTC()).AddDate(0, 0, 1)
+
+	securityIDs := lo.Uniq(lo.Map(trades, func(t *database.InvestmentTrade, _ int) int32 {
+		return t.SecurityID
+	}))
+
+	prices, err := s.getPrices(db, securityIDs, to)
+	if err != nil {
+		return err
+	}
+
+	var securities []*database.Security
+	if err = db.Unscoped().Where("id IN ?", securityIDs).Find(&securities).Error; err != nil {
+		return errors.Wrap(err, "failed to get securities")
+	}
+
+	factors := map[int32]decimal.Decimal{}
+	for _, security := range securities {
+		if factors[security.ID], err = s.conversionFactor(ctx, security.Currency, account.Currency); err != nil {
+			return err
+		}
+	}
+
+	decimals := s.cfg.DecimalSvc.GetCurrencyDecimals(ctx, account.Currency)
+
+	var dates, values []string
+	walkDays(trades, prices, from, to, func(day time.Time, quantities, latest map[int32]decimal.Decimal) {
+		total := decimal.Zero
+		for securityID, quantity := range quantities {
+			total = total.Add(quantity.Mul(latest[securityID]).Mul(factors[securityID]))
+		}
+
+		dates = append(dates, day.Format(time.DateOnly))
+		values = append(values, total.Round(decimals).String())
+	})
+
+	if err = db.Exec(upsertHoldingsValue, accountID, pq.Array(dates), pq.Array(values)).Error; err != nil {
+		return errors.Wrap(err, "failed to store holdings value")
+	}
+
+	return nil
+}
+
+func (s *Service) validateTrade(req *RecordTradeRequest) error {
+	if req.TradeDate.IsZero() {
+		return errors.New("trade date is required")
+	}
+
+	if req.Fee.IsNegative() {
+		return errors.New("fee must not be negative")
+	}
+
+	switch req.Kind {
+	case database.TradeKindBuy, database.TradeKindSell:
+		if !req.Quantity.IsPositive() {
+			return errors.New("quantity must be positive")
+		}
+
+		if req.Price.IsNegative() {
+			return errors.New("price must not be negative")
+		}
+
+		if req.Amount.IsNegative() {
+			return errors.New("amount must not be negative")
+		}
+	case database.TradeKindDividend:
+		if !req.Amount.IsPositive() {
+			return errors.New("dividend amount must be positive")
+		}
+	case database.TradeKindSplit:
+		if !req.Quantity.IsPositive() {
+			return errors.New("split ratio must be positive")
+		}
+	default:
+		return errors.Newf("unsupported trade kind: %d", req.Kind)
+	}
+
+	return nil
+}
+
+// tradeAmount returns cash settled by the trade, calculated from quantity, price and fee unless given.
+func (s *Service) tradeAmount(
+	ctx context.Context,
+	account *database.Account,
+	security *database.Security,
+	req *RecordTradeRequest,
+) (decimal.Decimal, error) {
+	if req.Kind == database.TradeKindSplit {
+		return decimal.Zero, nil
+	}
+
+	if req.Kind == database.TradeKindDividend || !req.Amount.IsZero() {
+		return req.Amount, nil
+	}
+
+	factor, err := s.conversionFactor(ctx, security.Currency, account.Currency)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	gross := req.Quantity.Mul(req.Price).Mul(factor).
+		Round(s.cfg.DecimalSvc.GetCurrencyDecimals(ctx, account.Currency))
+
+	if req.Kind == database.TradeKindBuy {
+		return gross.Add(req.Fee), nil
+	}
+
+	amount := gross.Sub(req.Fee)
+	if amount.IsNegative() {
+		return decimal.Zero, errors.New("fee exceeds sell proceeds")
+	}
+
+	return amount, nil
+}
+
+func (s *Service) newCashTransaction(
+	ctx context.Context,
+	investmentAccount *database.InvestmentAccount,
+	account *database.Account,
+	security *database.Security,
+	trade *database.InvestmentTrade,
+) (*database.Transaction, error) {
+	cashAccountID := *investmentAccount.CashAccountID
+
+	tx := &database.Transaction{
+		TransactionType:      gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+		SourceAccountID:      cashAccountID,
+		SourceCurrency:       account.Currency,
+		SourceAmount:         decimal.NewNullDecimal(trade.Amount.Neg()),
+		DestinationAccountID: account.ID,
+		DestinationCurrency:  account.Currency,
+		DestinationAmount:    decimal.NewNullDecimal(trade.Amount),
+		Title:                fmt.Sprintf("Buy %s %s", trade.Quantity, security.Symbol),
+		TransactionDateTime:  trade.TradeDate,
+		TransactionDateOnly:  trade.TradeDate,
+		Extra: map[string]string{
+			"security": security.Symbol,
+		},
+	}
+
+	switch trade.Kind {
+	case database.TradeKindSell:
+		tx.SourceAccountID, tx.DestinationAccountID = account.ID, cashAccountID
+		tx.Title = fmt.Sprintf("Sell %s %s", trade.Quantity, security.Symbol)
+	case database.TradeKindDividend:
+		dividendAccount, err := s.getDividendAccount(ctx, investmentAccount)
+		if err != nil {
+			return nil, err
+		}
+
+		sourceAmount := trade.Amount
+		if dividendAccount.Currency != account.Currency {
+			converted, convErr := s.cfg.CurrencyConverterSvc.Convert(ctx, account.Currency, dividendAccount.Currency, trade.Amount)
+			if convErr != nil {
+				return nil, errors.Wrap(convErr, "failed to convert dividend")
+			}
+
+			sourceAmount = converted.Round(s.cfg.DecimalSvc.GetCurrencyDecimals(ctx, dividendAccount.Currency))
+		}
+
+		tx.TransactionType = gomoneypbv1.TransactionType_TRANSACTION_TYPE_INCOME
+		tx.SourceAccountID = dividendAccount.ID
+		tx.SourceCurrency = dividendAccount.Currency
+		tx.SourceAmount = decimal.NewNullDecimal(sourceAmount.Neg())
+		tx.DestinationAccountID = cashAccountID
+		tx.Title = fmt.Sprintf("Dividend %s", security.Symbol)
+	}
+
+	return tx, nil
+}
+
+func (s *Service) getDividendAccount(
+	ctx context.Context,
+	investmentAccount *database.InvestmentAccount,
+) (*database.Account, error) {
+	if investmentAccount.DividendAccountID != nil {
+		return s.cfg.AccountSvc.GetAccountByID(ctx, *investmentAccount.DividendAccountID)
+	}
+
+	account, err := s.cfg.AccountSvc.GetDefaultAccount(ctx, gomoneypbv1.AccountType_ACCOUNT_TYPE_INCOME)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get default income account")
+	}
+
+	return account, nil
+}
+
+// conversionFactor returns the amount of toCurrency per 1 unit of fromCurrency.
+func (s *Service) conversionFactor(ctx context.Context, fromCurrency, toCurrency string) (decimal.Decimal, error) {
+	if fromCurrency == toCurrency {
+		return decimal.NewFromInt(1), nil
+	}
+
+	factor, err := s.cfg.CurrencyConverterSvc.Convert(ctx, fromCurrency, toCurrency, decimal.NewFromInt(1))
+	if err != nil {
+		return decimal.Zero, errors.Wrapf(err, "failed to convert %s to %s", fromCurrency, toCurrency)
+	}
+
+	return factor, nil
+}
+
+func (s *Service) getSecurity(ctx context.Context, id int32) (*database.Security, error) {
+	var security database.Security
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).
+		Where("id = ?", id).First(&security).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to get security %d", id)
+	}
+
+	return &security, nil
+}
+
+func (s *Service) getTrades(db *gorm.DB, accountID int32) ([]*database.InvestmentTrade, error) {
+	var trades []*database.InvestmentTrade
+
+	if err := db.Where("account_id = ?", accountID).
+		Order("trade_date, id").Find(&trades).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get trades")
+	}
+
+	return trades, nil
+}
+
+func (s *Service) getPrices(db *gorm.DB, securityIDs []int32, until time.Time) ([]*database.SecurityPrice, error) {
+	var prices []*database.SecurityPrice
+
+	if len(securityIDs) == 0 {
+		return nil, nil
+	}
+
+	if err := db.Where("security_id IN ? AND date <= ?", securityIDs, until).
+		Order("date").Find(&prices).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get security prices")
+	}
+
+	return prices, nil
+}
+
+func (s *Service) savePrices(ctx context.Context, prices []*database.SecurityPrice) error {
+	if len(prices) == 0 {
+		return nil
+	}
+
+	for _, price := range prices {
+		price.UpdatedAt = time.Now().UTC()
+	}
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).Clauses(clause.OnConflict{
+		OnConstraint: "security_prices_pk",
+		DoUpdates:    clause.AssignmentColumns([]string{"price", "source", "updated_at"}),
+	}).Create(&prices).Error; err != nil {
+		return errors.Wrap(err, "failed to store security prices")
+	}
+
+	return nil
+}
+
+// storePrices saves prices and revalues accounts holding the affected securities from the earliest price date.
+func (s *Service) storePrices(ctx context.Context, prices []*database.SecurityPrice) error {
+	if err := s.savePrices(ctx, prices); err != nil || len(prices) == 0 {
+		return err
+	}
+
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	affected := lo.Uniq(lo.Map(prices, func(p *database.SecurityPrice, _ int) int32 {
+		return p.SecurityID
+	}))
+
+	from := lo.MinBy(prices, func(a, b *database.SecurityPrice) bool {
+		return a.Date.Before(b.Date)
+	}).Date
+
+	var accountIDs []int32
+	if err := db.Model(&database.InvestmentTrade{}).Where("security_id IN ?", affected).
+		Distinct("account_id").Pluck("account_id", &accountIDs).Error; err != nil {
+		return errors.Wrap(err, "failed to get accounts holding securities")
+	}
+
+	for _, accountID := range accountIDs {
+		if err := s.RevalueAccount(ctx, db, accountID, from); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,365 @@
+package investments_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
+	v1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/ft-t/go-money/pkg/accounts"
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/investments"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/golang/mock/gomock"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var gormDB *gorm.DB
+var cfg *configuration.Configuration
+
+func TestMain(m *testing.M) {
+	cfg = configuration.GetConfiguration()
+	gormDB = database.GetDb(database.DbTypeMaster)
+
+	os.Exit(m.Run())
+}
+
+type investmentFixture struct {
+	investment *database.Account
+	cash       *database.Account
+	income     *database.Account
+	security   *database.Security
+}
+
+func newInvestmentTestService(t *testing.T, txSvc *MockTransactionSvc) *investments.Service {
+	decimalSvc := NewMockDecimalSvc(gomock.NewController(t))
+	decimalSvc.EXPECT().GetCurrencyDecimals(gomock.Any(), gomock.Any()).Return(int32(2)).AnyTimes()
+
+	return investments.NewService(&investments.ServiceConfig{
+		AccountSvc:     accounts.NewService(&accounts.ServiceConfig{}),
+		TransactionSvc: txSvc,
+		DecimalSvc:     decimalSvc,
+		PriceProvider:  investments.NewManualPriceProvider(),
+	})
+}
+
+func createInvestmentFixture(t *testing.T, svc *investments.Service, method database.CostMethod) *investmentFixture {
+	require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+	require.NoError(t, gormDB.Create(&database.Currency{ID: "USD", Rate: decimal.NewFromInt(1), DecimalPlaces: 2}).Error)
+
+	f := &investmentFixture{
+		investment: &database.Account{Name: "Broker", Currency: "USD", Type: v1.AccountType_ACCOUNT_TYPE_ASSET, Extra: map[string]string{}},
+		cash:       &database.Account{Name: "Broker cash", Currency: "USD", Type: v1.AccountType_ACCOUNT_TYPE_ASSET, Extra: map[string]string{}},
+		income:     &database.Account{Name: "Dividends", Currency: "USD", Type: v1.AccountType_ACCOUNT_TYPE_INCOME, Extra: map[string]string{}},
+	}
+	require.NoError(t, gormDB.Create(f.investment).Error)
+	require.NoError(t, gormDB.Create(f.cash).Error)
+	require.NoError(t, gormDB.Create(f.income).Error)
+
+	var err error
+	f.security, err = svc.CreateSecurity(context.TODO(), &investments.CreateSecurityRequest{
+		Symbol:   "aapl",
+		Name:     "Apple",
+		Kind:     database.SecurityKindStock,
+		Currency: "USD",
+	})
+	require.NoError(t, err)
+
+	_, err = svc.SetInvestmentAccount(context.TODO(), &investments.SetInvestmentAccountRequest{
+		AccountID:         f.investment.ID,
+		CostMethod:        method,
+		CashAccountID:     &f.cash.ID,
+		DividendAccountID: &f.income.ID,
+	})
+	require.NoError(t, err)
+
+	return f
+}
+
+func expectCashTransactions(t *testing.T, txSvc *MockTransactionSvc, posted *[]*database.Transaction) {
+	txSvc.EXPECT().UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Nil()).
+		DoAndReturn(func(_ context.Context, created []*database.Transaction, _ []*database.Transaction) ([]*transactionsv1.CreateTransactionResponse, error) {
+			for _, tx := range created {
+				tx.ID = int64(len(*posted) + 1)
+				*posted = append(*posted, tx)
+			}
+
+			return nil, nil
+		}).AnyTimes()
+}
+
+func recordTrades(t *testing.T, svc *investments.Service, f *investmentFixture, day time.Time) {
+	trades := []*investments.RecordTradeRequest{
+		{Kind: database.TradeKindBuy, TradeDate: day, Quantity: decimal.NewFromInt(10), Price: decimal.NewFromInt(100), Fee: decimal.NewFromInt(1)},
+		{Kind: database.TradeKindBuy, TradeDate: day.AddDate(0, 0, 1), Quantity: decimal.NewFromInt(10), Price: decimal.NewFromInt(120)},
+		{Kind: database.TradeKindSell, TradeDate: day.AddDate(0, 0, 2), Quantity: decimal.NewFromInt(15), Price: decimal.NewFromInt(130)},
+		{Kind: database.TradeKindDividend, TradeDate: day.AddDate(0, 0, 3), Amount: decimal.NewFromInt(7)},
+	}
+
+	for _, req := range trades {
+		req.AccountID = f.investment.ID
+		req.SecurityID = f.security.ID
+
+		_, err := svc.RecordTrade(context.TODO(), req)
+		require.NoError(t, err)
+	}
+}
+
+func TestService_RecordTrade(t *testing.T) {
+	day := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	t.Run("fifo", func(t *testing.T) {
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+		svc := newInvestmentTestService(t, txSvc)
+		f := createInvestmentFixture(t, svc, database.CostMethodFIFO)
+
+		var posted []*database.Transaction
+		expectCashTransactions(t, txSvc, &posted)
+
+		recordTrades(t, svc, f, day)
+
+		require.Len(t, posted, 3)
+		assert.Equal(t, v1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS, posted[0].TransactionType)
+		assert.Equal(t, f.cash.ID, posted[0].SourceAccountID)
+		assert.Equal(t, f.investment.ID, posted[0].DestinationAccountID)
+		assert.Equal(t, "1001", posted[0].DestinationAmount.Decimal.String())
+		assert.Equal(t, f.investment.ID, posted[1].SourceAccountID)
+		assert.Equal(t, "1950", posted[1].DestinationAmount.Decimal.String()) // sell
+		assert.Equal(t, v1.TransactionType_TRANSACTION_TYPE_INCOME, posted[2].TransactionType)
+		assert.Equal(t, f.income.ID, posted[2].SourceAccountID)
+		assert.Equal(t, f.cash.ID, posted[2].DestinationAccountID)
+
+		portfolio, err := svc.GetHoldings(context.TODO(), f.investment.ID)
+		require.NoError(t, err)
+
+		require.Len(t, portfolio.Holdings, 1)
+		holding := portfolio.Holdings[0]
+		assert.Equal(t, "AAPL", holding.Security.Symbol)
+		assert.Equal(t, "5", holding.Quantity.String())
+		assert.Equal(t, "600", holding.CostBasis.String())
+		assert.Equal(t, "130", holding.Price.String())
+		assert.Equal(t, "650", holding.MarketValue.String())
+		assert.Equal(t, "50", holding.UnrealizedGain.String())
+		assert.Equal(t, "349", holding.RealizedGain.String())
+		assert.Equal(t, "7", holding.Dividends.String())
+		require.Len(t, holding.Lots, 1)
+		assert.Equal(t, day.AddDate(0, 0, 1), holding.Lots[0].Date)
+
+		var stats []*database.DailyStat
+		require.NoError(t, gormDB.Where("account_id = ? AND date <= ?", f.investment.ID, day.AddDate(0, 0, 2)).
+			Order("date").Find(&stats).Error)
+		require.Len(t, stats, 3)
+		assert.Equal(t, "1000", stats[0].HoldingsValue.Decimal.String())
+		assert.Equal(t, "2400", stats[1].HoldingsValue.Decimal.String())
+		assert.Equal(t, "650", stats[2].HoldingsValue.Decimal.String())
+	})
+
+	t.Run("average cost", func(t *testing.T) {
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+		svc := newInvestmentTestService(t, txSvc)
+		f := createInvestmentFixture(t, svc, database.CostMethodAverage)
+
+		var posted []*database.Transaction
+		expectCashTransactions(t, txSvc, &posted)
+
+		recordTrades(t, svc, f, day)
+
+		portfolio, err := svc.GetHoldings(context.TODO(), f.investment.ID)
+		require.NoError(t, err)
+
+		holding := portfolio.Holdings[0]
+		assert.Equal(t, "5", holding.Quantity.String())
+		assert.Equal(t, "550.25", holding.CostBasis.String())
+		assert.Equal(t, "299.25", holding.RealizedGain.String())
+		assert.Equal(t, "99.75", portfolio.UnrealizedGain.String())
+	})
+
+	t.Run("split and imported prices", func(t *testing.T) {
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+		svc := newInvestmentTestService(t, txSvc)
+		f := createInvestmentFixture(t, svc, database.CostMethodFIFO)
+
+		var posted []*database.Transaction
+		expectCashTransactions(t, txSvc, &posted)
+
+		for _, req := range []*investments.RecordTradeRequest{
+			{Kind: database.TradeKindBuy, TradeDate: day, Quantity: decimal.NewFromInt(10), Price: decimal.NewFromInt(100)},
+			{Kind: database.TradeKindSplit, TradeDate: day.AddDate(0, 0, 1), Quantity: decimal.NewFromInt(4)},
+		} {
+			req.AccountID = f.investment.ID
+			req.SecurityID = f.security.ID
+
+			_, err := svc.RecordTrade(context.TODO(), req)
+			require.NoError(t, err)
+		}
+
+		assert.Len(t, posted, 1) // splits are not settled
+
+		portfolio, err := svc.GetHoldings(context.TODO(), f.investment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "40", portfolio.Holdings[0].Quantity.String())
+		assert.Equal(t, "25", portfolio.Holdings[0].Price.String())
+		assert.Equal(t, "1000", portfolio.MarketValue.String())
+
+		imported, err := svc.ImportPrices(context.TODO(), []*investments.PriceQuote{
+			{Symbol: "AAPL", Date: day.AddDate(0, 0, 2), Price: decimal.NewFromInt(30)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, imported)
+
+		portfolio, err = svc.GetHoldings(context.TODO(), f.investment.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "1200", portfolio.MarketValue.String())
+		assert.Equal(t, "200", portfolio.UnrealizedGain.String())
+
+		var stat database.DailyStat
+		require.NoError(t, gormDB.Where("account_id = ? AND date = ?", f.investment.ID, day.AddDate(0, 0, 1)).
+			First(&stat).Error)
+		assert.Equal(t, "1000", stat.HoldingsValue.Decimal.String())
+
+		_, err = svc.ImportPrices(context.TODO(), []*investments.PriceQuote{
+			{Symbol: "MSFT", Date: day, Price: decimal.NewFromInt(1)},
+		})
+		assert.ErrorContains(t, err, "unknown security MSFT")
+	})
+
+	t.Run("sell more than held", func(t *testing.T) {
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+		svc := newInvestmentTestService(t, txSvc)
+		f := createInvestmentFixture(t, svc, database.CostMethodFIFO)
+
+		_, err := svc.RecordTrade(context.TODO(), &investments.RecordTradeRequest{
+			AccountID:  f.investment.ID,
+			SecurityID: f.security.ID,
+			Kind:       database.TradeKindSell,
+			TradeDate:  day,
+			Quantity:   decimal.NewFromInt(1),
+			Price:      decimal.NewFromInt(100),
+		})
+		assert.ErrorContains(t, err, "only 0 held")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		svc := newInvestmentTestService(t, nil)
+		valid := investments.RecordTradeRequest{
+			Kind:      database.TradeKindBuy,
+			TradeDate: day,
+			Quantity:  decimal.NewFromInt(1),
+			Price:     decimal.NewFromInt(1),
+		}
+
+		cases := map[string]func(r *investments.RecordTradeRequest){
+			"trade date is required":           func(r *investments.RecordTradeRequest) { r.TradeDate = time.Time{} },
+			"fee must not be negative":         func(r *investments.RecordTradeRequest) { r.Fee = decimal.NewFromInt(-1) },
+			"quantity must be positive":        func(r *investments.RecordTradeRequest) { r.Quantity = decimal.Zero },
+			"price must not be negative":       func(r *investments.RecordTradeRequest) { r.Price = decimal.NewFromInt(-1) },
+			"dividend amount must be positive": func(r *investments.RecordTradeRequest) { r.Kind = database.TradeKindDividend },
+			"split ratio must be positive": func(r *investments.RecordTradeRequest) {
+				r.Kind = database.TradeKindSplit
+				r.Quantity = decimal.Zero
+			},
+			"unsupported trade kind": func(r *investments.RecordTradeRequest) { r.Kind = 9 },
+		}
+
+		for msg, mutate := range cases {
+			req := valid
+			mutate(&req)
+
+			_, err := svc.RecordTrade(context.TODO(), &req)
+			assert.ErrorContains(t, err, msg)
+		}
+	})
+}
+
+func TestService_DeleteTrade(t *testing.T) {
+	day := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	txSvc := NewMockTransactionSvc(gomock.NewController(t))
+	svc := newInvestmentTestService(t, txSvc)
+	f := createInvestmentFixture(t, svc, database.CostMethodFIFO)
+
+	var posted []*database.Transaction
+	expectCashTransactions(t, txSvc, &posted)
+
+	buy, err := svc.RecordTrade(context.TODO(), &investments.RecordTradeRequest{
+		AccountID:  f.investment.ID,
+		SecurityID: f.security.ID,
+		Kind:       database.TradeKindBuy,
+		TradeDate:  day,
+		Quantity:   decimal.NewFromInt(2),
+		Price:      decimal.NewFromInt(10),
+	})
+	require.NoError(t, err)
+
+	sell, err := svc.RecordTrade(context.TODO(), &investments.RecordTradeRequest{
+		AccountID:  f.investment.ID,
+		SecurityID: f.security.ID,
+		Kind:       database.TradeKindSell,
+		TradeDate:  day.AddDate(0, 0, 1),
+		Quantity:   decimal.NewFromInt(1),
+		Price:      decimal.NewFromInt(12),
+	})
+	require.NoError(t, err)
+
+	_, err = svc.DeleteTrade(context.TODO(), buy.ID)
+	assert.ErrorContains(t, err, "trade can not be deleted")
+
+	txSvc.EXPECT().DeleteTransaction(gomock.Any(), &transactionsv1.DeleteTransactionsRequest{
+		Ids: []int64{*sell.TransactionID},
+	}).Return(&transactionsv1.DeleteTransactionsResponse{DeletedCount: 1}, nil)
+
+	deleted, err := svc.DeleteTrade(context.TODO(), sell.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sell.ID, deleted.ID)
+
+	portfolio, err := svc.GetHoldings(context.TODO(), f.investment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "2", portfolio.Holdings[0].Quantity.String())
+}
+
+func TestService_SetInvestmentAccount(t *testing.T) {
+	svc := newInvestmentTestService(t, nil)
+	f := createInvestmentFixture(t, svc, database.CostMethodFIFO)
+
+	updated, err := svc.SetInvestmentAccount(context.TODO(), &investments.SetInvestmentAccountRequest{
+		AccountID:  f.investment.ID,
+		CostMethod: database.CostMethodAverage,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, database.CostMethodAverage, updated.CostMethod)
+	assert.Nil(t, updated.CashAccountID)
+
+	_, err = svc.SetInvestmentAccount(context.TODO(), &investments.SetInvestmentAccountRequest{
+		AccountID:  f.income.ID,
+		CostMethod: database.CostMethodFIFO,
+	})
+	assert.ErrorContains(t, err, "is not an asset account")
+
+	_, err = svc.SetInvestmentAccount(context.TODO(), &investments.SetInvestmentAccountRequest{
+		AccountID:     f.investment.ID,
+		CostMethod:    database.CostMethodFIFO,
+		CashAccountID: lo.ToPtr(f.investment.ID),
+	})
+	assert.ErrorContains(t, err, "cash account must differ")
+
+	_, err = svc.SetInvestmentAccount(context.TODO(), &investments.SetInvestmentAccountRequest{
+		AccountID:         f.investment.ID,
+		CostMethod:        database.CostMethodFIFO,
+		DividendAccountID: &f.cash.ID,
+	})
+	assert.ErrorContains(t, err, "is not an income account")
+
+	_, err = svc.CreateSecurity(context.TODO(), &investments.CreateSecurityRequest{
+		Symbol:   "AAPL",
+		Kind:     database.SecurityKindStock,
+		Currency: "USD",
+	})
+	assert.ErrorContains(t, err, "security AAPL already exists")
+}
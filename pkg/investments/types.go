@@ -0,0 +1,79 @@
+package investments
+
+import (
+	"time"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PriceProviderManual = "manual"
+	PriceProviderCSV    = "csv"
+)
+
+type CreateSecurityRequest struct {
+	Symbol           string
+	Name             string
+	Kind             database.SecurityKind
+	Currency         string
+	QuantityDecimals int32
+}
+
+type SetInvestmentAccountRequest struct {
+	AccountID         int32
+	CostMethod        database.CostMethod
+	CashAccountID     *int32
+	DividendAccountID *int32
+}
+
+type RecordTradeRequest struct {
+	AccountID  int32
+	SecurityID int32
+	Kind       database.TradeKind
+	TradeDate  time.Time
+	Quantity   decimal.Decimal // split ratio for splits, e.g. 2 for a 2-for-1 split
+	Price      decimal.Decimal
+	Fee        decimal.Decimal
+	Amount     decimal.Decimal // optional for buys and sells, calculated from quantity, price and fee when zero
+	Note       string
+}
+
+// Lot is an open purchase of a security. Average cost accounts keep a single pooled lot per security.
+type Lot struct {
+	TradeID  int64
+	Date     time.Time
+	Quantity decimal.Decimal
+	Cost     decimal.Decimal
+}
+
+// Holding is a position in a single security replayed from trades, amounts are in account currency.
+type Holding struct {
+	Security       *database.Security
+	Quantity       decimal.Decimal
+	CostBasis      decimal.Decimal
+	Price          decimal.Decimal // latest known price in security currency
+	MarketValue    decimal.Decimal
+	UnrealizedGain decimal.Decimal
+	RealizedGain   decimal.Decimal
+	Dividends      decimal.Decimal
+	Lots           []*Lot
+}
+
+type Portfolio struct {
+	Account        *database.InvestmentAccount
+	Currency       string
+	Holdings       []*Holding
+	CostBasis      decimal.Decimal
+	MarketValue    decimal.Decimal
+	UnrealizedGain decimal.Decimal
+	RealizedGain   decimal.Decimal
+	Dividends      decimal.Decimal
+}
+
+// PriceQuote is a price identified by symbol, as read from CSV files.
+type PriceQuote struct {
+	Symbol string
+	Date   time.Time
+	Price  decimal.Decimal
+}
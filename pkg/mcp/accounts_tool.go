@@ -0,0 +1,206 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/ft-t/go-money/pkg/analytics"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/timezone"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+)
+
+var accountTypes = map[string]gomoneypbv1.AccountType{
+	"asset":      gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET,
+	"liability":  gomoneypbv1.AccountType_ACCOUNT_TYPE_LIABILITY,
+	"expense":    gomoneypbv1.AccountType_ACCOUNT_TYPE_EXPENSE,
+	"income":     gomoneypbv1.AccountType_ACCOUNT_TYPE_INCOME,
+	"adjustment": gomoneypbv1.AccountType_ACCOUNT_TYPE_ADJUSTMENT,
+}
+
+var accountTypeNames = lo.Invert(accountTypes)
+
+const defaultBalanceHistoryDays = 30
+
+type accountOutput struct {
+	ID             int32   `json:"id"`
+	Name           string  `json:"name"`
+	Type           string  `json:"type"`
+	Currency       string  `json:"currency"`
+	CurrentBalance string  `json:"current_balance"`
+	IsDefault      bool    `json:"is_default,omitempty"`
+	TagIDs         []int32 `json:"tag_ids,omitempty"`
+	Timezone       string  `json:"timezone,omitempty"`
+	Note           string  `json:"note,omitempty"`
+	LastUpdatedAt  string  `json:"last_updated_at"`
+}
+
+func mapAccount(account *database.Account) *accountOutput {
+	return &accountOutput{
+		ID:             account.ID,
+		Name:           account.Name,
+		Type:           accountTypeNames[account.Type],
+		Currency:       account.Currency,
+		CurrentBalance: account.CurrentBalance.String(),
+		IsDefault:      account.IsDefault(),
+		TagIDs:         account.TagIDs,
+		Timezone:       lo.FromPtr(account.Timezone),
+		Note:           account.Note,
+		LastUpdatedAt:  account.LastUpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+type balancePointOutput struct {
+	Date          string `json:"date"`
+	Balance       string `json:"balance"`
+	HoldingsValue string `json:"holdings_value,omitempty"`
+}
+
+type balanceHistoryOutput struct {
+	AccountID int32                 `json:"account_id"`
+	From      string                `json:"from"`
+	To        string                `json:"to"`
+	Interval  string                `json:"interval"`
+	Points    []*balancePointOutput `json:"points"`
+}
+
+type accountTimezoneOutput struct {
+	ID       int32  `json:"id"`
+	Name     string `json:"name"`
+	Timezone string `json:"timezone"`
+}
+
+func (s *Server) handleSetAccountTimezone(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	accountID, ok := args["account_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("account_id parameter is required"), nil
+	}
+
+	name, _ := args["timezone"].(string)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	account, err := s.cfg.AccountSvc.SetTimezone(queryCtx, int32(accountID), name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to set account timezone: %v", err)), nil
+	}
+
+	return toolJSONResult(&accountTimezoneOutput{
+		ID:       account.ID,
+		Name:     account.Name,
+		Timezone: lo.FromPtr(account.Timezone),
+	})
+}
+
+func (s *Server) handleListAccounts(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	var types []gomoneypbv1.AccountType
+	if raw, ok := args["types"].([]any); ok {
+		for _, v := range raw {
+			name, _ := v.(string)
+
+			accountType, ok := accountTypes[strings.ToLower(name)]
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("unsupported account type: %v", v)), nil
+			}
+
+			types = append(types, accountType)
+		}
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	accounts, err := s.cfg.AccountSvc.GetAllAccounts(queryCtx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list accounts: %v", err)), nil
+	}
+
+	if len(types) > 0 {
+		accounts = lo.Filter(accounts, func(account *database.Account, _ int) bool {
+			return lo.Contains(types, account.Type)
+		})
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID < accounts[j].ID
+	})
+
+	return toolJSONResult(lo.Map(accounts, func(account *database.Account, _ int) *accountOutput {
+		return mapAccount(account)
+	}))
+}
+
+func (s *Server) handleGetAccountBalanceHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	accountID, ok := args["account_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("account_id parameter is required"), nil
+	}
+
+	to, err := parseDateArg(args, "to")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if to == nil {
+		to = lo.ToPtr(timezone.Today(s.cfg.Location))
+	}
+
+	from, err := parseDateArg(args, "from")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if from == nil {
+		from = lo.ToPtr(to.AddDate(0, 0, -defaultBalanceHistoryDays))
+	}
+
+	interval, _ := args["interval"].(string)
+	start, end := dayRange(*from, *to, s.cfg.Location)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	points, err := s.cfg.AnalyticsSvc.GetBalanceHistory(queryCtx, &analytics.BalanceHistoryRequest{
+		AccountID: int32(accountID),
+		From:      start,
+		To:        end,
+		Interval:  analytics.BalanceInterval(strings.ToLower(interval)),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get balance history: %v", err)), nil
+	}
+
+	reportRows(ctx, len(points))
+
+	return toolJSONResult(&balanceHistoryOutput{
+		AccountID: int32(accountID),
+		From:      from.Format(time.DateOnly),
+		To:        to.Format(time.DateOnly),
+		Interval:  lo.CoalesceOrEmpty(strings.ToLower(interval), string(analytics.BalanceIntervalDay)),
+		Points: lo.Map(points, func(p *analytics.BalancePoint, _ int) *balancePointOutput {
+			return &balancePointOutput{
+				Date:          p.Date.Format(time.DateOnly),
+				Balance:       p.Balance.String(),
+				HoldingsValue: nullDecimalString(p.HoldingsValue),
+			}
+		}),
+	})
+}
@@ -0,0 +1,227 @@
+package mcp_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/analytics"
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+func newAccountsTestServer(t *testing.T, accountSvc *MockAccountsService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:         gormDB,
+		Docs:       "test docs",
+		AccountSvc: accountSvc,
+	})
+}
+
+func TestServer_HandleSetAccountTimezone(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		accountSvc := NewMockAccountsService(gomock.NewController(t))
+		accountSvc.EXPECT().SetTimezone(gomock.Any(), int32(5), "America/New_York").Return(&database.Account{
+			ID:       5,
+			Name:     "checking",
+			Timezone: lo.ToPtr("America/New_York"),
+		}, nil)
+
+		result := callTool(t, newAccountsTestServer(t, accountSvc), "set_account_timezone", map[string]any{
+			"account_id": float64(5),
+			"timezone":   "America/New_York",
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"timezone": "America/New_York"`)
+		assert.Contains(t, text, `"name": "checking"`)
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		accountSvc := NewMockAccountsService(gomock.NewController(t))
+		accountSvc.EXPECT().SetTimezone(gomock.Any(), int32(5), "").Return(&database.Account{ID: 5}, nil)
+
+		result := callTool(t, newAccountsTestServer(t, accountSvc), "set_account_timezone", map[string]any{
+			"account_id": float64(5),
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"timezone": ""`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		accountSvc := NewMockAccountsService(gomock.NewController(t))
+		accountSvc.EXPECT().SetTimezone(gomock.Any(), int32(5), "Mars/Olympus").Return(nil, assert.AnError)
+
+		result := callTool(t, newAccountsTestServer(t, accountSvc), "set_account_timezone", map[string]any{
+			"account_id": float64(5),
+			"timezone":   "Mars/Olympus",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to set account timezone")
+	})
+
+	t.Run("account_id required", func(t *testing.T) {
+		result := callTool(t, newAccountsTestServer(t, NewMockAccountsService(gomock.NewController(t))), "set_account_timezone", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "account_id parameter is required")
+	})
+}
+
+func TestServer_HandleListAccounts(t *testing.T) {
+	accounts := []*database.Account{
+		{ID: 2, Name: "Food", Currency: "USD", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_EXPENSE},
+		{ID: 1, Name: "Checking", Currency: "EUR", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET,
+			CurrentBalance: decimal.RequireFromString("1250.50")},
+	}
+
+	t.Run("all", func(t *testing.T) {
+		accountSvc := NewMockAccountsService(gomock.NewController(t))
+		accountSvc.EXPECT().GetAllAccounts(gomock.Any()).Return(accounts, nil)
+
+		result := callTool(t, newAccountsTestServer(t, accountSvc), "list_accounts", map[string]any{})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"current_balance": "1250.5"`)
+		assert.Contains(t, text, `"type": "asset"`)
+		assert.Less(t, strings.Index(text, "Checking"), strings.Index(text, "Food"))
+	})
+
+	t.Run("filter by type", func(t *testing.T) {
+		accountSvc := NewMockAccountsService(gomock.NewController(t))
+		accountSvc.EXPECT().GetAllAccounts(gomock.Any()).Return(accounts, nil)
+
+		result := callTool(t, newAccountsTestServer(t, accountSvc), "list_accounts", map[string]any{
+			"types": []any{"expense"},
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, "Food")
+		assert.NotContains(t, text, "Checking")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		result := callTool(t, newAccountsTestServer(t, NewMockAccountsService(gomock.NewController(t))), "list_accounts", map[string]any{
+			"types": []any{"savings"},
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "unsupported account type: savings")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		accountSvc := NewMockAccountsService(gomock.NewController(t))
+		accountSvc.EXPECT().GetAllAccounts(gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newAccountsTestServer(t, accountSvc), "list_accounts", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to list accounts")
+	})
+}
+
+func TestServer_HandleGetAccountBalanceHistory(t *testing.T) {
+	newServer := func(t *testing.T, analyticsSvc *MockAnalyticsService) *gomcp.Server {
+		gormDB, mockDB, _ := testingutils.GormMock()
+		t.Cleanup(func() { _ = mockDB.Close() })
+
+		return gomcp.NewServer(&gomcp.ServerConfig{
+			DB:           gormDB,
+			Docs:         "test docs",
+			AnalyticsSvc: analyticsSvc,
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		analyticsSvc := NewMockAnalyticsService(gomock.NewController(t))
+		analyticsSvc.EXPECT().GetBalanceHistory(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *analytics.BalanceHistoryRequest) ([]*analytics.BalancePoint, error) {
+				assert.EqualValues(t, 3, req.AccountID)
+				assert.Equal(t, "2026-01-01", req.From.Format(time.DateOnly))
+				assert.Equal(t, "2026-03-31", req.To.Format(time.DateOnly))
+				assert.Equal(t, analytics.BalanceIntervalMonth, req.Interval)
+
+				return []*analytics.BalancePoint{
+					{Date: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), Balance: decimal.NewFromInt(100)},
+					{Date: time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), Balance: decimal.NewFromInt(150),
+						HoldingsValue: decimal.NewNullDecimal(decimal.NewFromInt(170))},
+				}, nil
+			})
+
+		result := callTool(t, newServer(t, analyticsSvc), "get_account_balance_history", map[string]any{
+			"account_id": float64(3),
+			"from":       "2026-01-01",
+			"to":         "2026-03-31",
+			"interval":   "Month",
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"date": "2026-02-28"`)
+		assert.Contains(t, text, `"holdings_value": "170"`)
+		assert.Contains(t, text, `"interval": "month"`)
+	})
+
+	t.Run("defaults to last 30 days", func(t *testing.T) {
+		analyticsSvc := NewMockAnalyticsService(gomock.NewController(t))
+		analyticsSvc.EXPECT().GetBalanceHistory(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *analytics.BalanceHistoryRequest) ([]*analytics.BalancePoint, error) {
+				assert.Equal(t, req.To.AddDate(0, 0, -30).Format(time.DateOnly), req.From.Format(time.DateOnly))
+
+				return nil, nil
+			})
+
+		result := callTool(t, newServer(t, analyticsSvc), "get_account_balance_history", map[string]any{
+			"account_id": float64(3),
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"interval": "day"`)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		result := callTool(t, newServer(t, NewMockAnalyticsService(gomock.NewController(t))), "get_account_balance_history", map[string]any{
+			"account_id": float64(3),
+			"from":       "01/02/2026",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid from")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		analyticsSvc := NewMockAnalyticsService(gomock.NewController(t))
+		analyticsSvc.EXPECT().GetBalanceHistory(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newServer(t, analyticsSvc), "get_account_balance_history", map[string]any{
+			"account_id": float64(3),
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to get balance history")
+	})
+
+	t.Run("account_id required", func(t *testing.T) {
+		result := callTool(t, newServer(t, NewMockAnalyticsService(gomock.NewController(t))), "get_account_balance_history", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "account_id parameter is required")
+	})
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	analyticsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/analytics/v1"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type debitsCreditsItemOutput struct {
+	AccountID     int32  `json:"account_id"`
+	DebitsCount   int32  `json:"debits_count"`
+	DebitsAmount  string `json:"debits_amount"`
+	CreditsCount  int32  `json:"credits_count"`
+	CreditsAmount string `json:"credits_amount"`
+}
+
+type debitsCreditsSummaryOutput struct {
+	From     string                     `json:"from"`
+	To       string                     `json:"to"`
+	Accounts []*debitsCreditsItemOutput `json:"accounts"`
+}
+
+func (s *Server) handleDebitsCreditsSummary(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	accountIDs, err := parseInt32SliceArg(args, "account_ids")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if len(accountIDs) == 0 {
+		return mcp.NewToolResultError("account_ids parameter is required"), nil
+	}
+
+	from, err := parseDateArg(args, "from")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	to, err := parseDateArg(args, "to")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if from == nil || to == nil {
+		return mcp.NewToolResultError("from and to parameters are required"), nil
+	}
+
+	start, end := dayRange(*from, *to, s.cfg.Location)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	resp, err := s.cfg.AnalyticsSvc.GetDebitsAndCreditsSummary(queryCtx, &analyticsv1.GetDebitsAndCreditsSummaryRequest{
+		AccountIds: accountIDs,
+		StartAt:    timestamppb.New(start),
+		EndAt:      timestamppb.New(end),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get debits and credits summary: %v", err)), nil
+	}
+
+	output := &debitsCreditsSummaryOutput{
+		From: from.Format(time.DateOnly),
+		To:   to.Format(time.DateOnly),
+	}
+
+	for accountID, item := range resp.Items {
+		output.Accounts = append(output.Accounts, &debitsCreditsItemOutput{
+			AccountID:     accountID,
+			DebitsCount:   item.TotalDebitsCount,
+			DebitsAmount:  item.TotalDebitsAmount,
+			CreditsCount:  item.TotalCreditsCount,
+			CreditsAmount: item.TotalCreditsAmount,
+		})
+	}
+
+	sort.Slice(output.Accounts, func(i, j int) bool {
+		return output.Accounts[i].AccountID < output.Accounts[j].AccountID
+	})
+
+	return toolJSONResult(output)
+}
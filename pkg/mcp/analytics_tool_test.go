@@ -0,0 +1,92 @@
+package mcp_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	analyticsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/analytics/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+func TestServer_HandleDebitsCreditsSummary(t *testing.T) {
+	newServer := func(t *testing.T, analyticsSvc *MockAnalyticsService) *gomcp.Server {
+		gormDB, mockDB, _ := testingutils.GormMock()
+		t.Cleanup(func() { _ = mockDB.Close() })
+
+		return gomcp.NewServer(&gomcp.ServerConfig{
+			DB:           gormDB,
+			Docs:         "test docs",
+			AnalyticsSvc: analyticsSvc,
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		analyticsSvc := NewMockAnalyticsService(gomock.NewController(t))
+		analyticsSvc.EXPECT().GetDebitsAndCreditsSummary(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ context.Context,
+				req *analyticsv1.GetDebitsAndCreditsSummaryRequest,
+			) (*analyticsv1.GetDebitsAndCreditsSummaryResponse, error) {
+				assert.Equal(t, []int32{2, 1}, req.AccountIds)
+				assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), req.StartAt.AsTime())
+				assert.Equal(t, time.Date(2026, 2, 28, 23, 59, 59, 999999999, time.UTC), req.EndAt.AsTime())
+
+				return &analyticsv1.GetDebitsAndCreditsSummaryResponse{
+					Items: map[int32]*analyticsv1.GetDebitsAndCreditsSummaryResponse_SummaryItem{
+						2: {TotalDebitsCount: 1, TotalDebitsAmount: "10.00", TotalCreditsAmount: "0.00"},
+						1: {TotalCreditsCount: 3, TotalCreditsAmount: "250.00", TotalDebitsAmount: "0.00"},
+					},
+				}, nil
+			})
+
+		result := callTool(t, newServer(t, analyticsSvc), "debits_credits_summary", map[string]any{
+			"account_ids": []any{float64(2), float64(1)},
+			"from":        "2026-02-01",
+			"to":          "2026-02-28",
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"credits_amount": "250.00"`)
+		assert.Less(t, strings.Index(text, `"account_id": 1`), strings.Index(text, `"account_id": 2`))
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			args     map[string]any
+			expected string
+		}{
+			"no accounts": {args: map[string]any{"from": "2026-02-01", "to": "2026-02-28"}, expected: "account_ids parameter is required"},
+			"no dates":    {args: map[string]any{"account_ids": []any{float64(1)}}, expected: "from and to parameters are required"},
+			"bad date":    {args: map[string]any{"account_ids": []any{float64(1)}, "from": "x", "to": "2026-02-28"}, expected: "invalid from"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				result := callTool(t, newServer(t, NewMockAnalyticsService(gomock.NewController(t))), "debits_credits_summary", c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		analyticsSvc := NewMockAnalyticsService(gomock.NewController(t))
+		analyticsSvc.EXPECT().GetDebitsAndCreditsSummary(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newServer(t, analyticsSvc), "debits_credits_summary", map[string]any{
+			"account_ids": []any{float64(1)},
+			"from":        "2026-02-01",
+			"to":          "2026-02-28",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to get debits and credits summary")
+	})
+}
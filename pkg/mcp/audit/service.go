@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+)
+
+// Service stores and reads the MCP tool-call audit log.
+type Service struct{}
+
+func NewService() *Service { return &Service{} }
+
+func (s *Service) Record(ctx context.Context, call *database.McpToolCall) error {
+	db := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeMaster))
+
+	return errors.WithStack(db.Create(call).Error)
+}
+
+// List returns matching calls, newest first.
+func (s *Service) List(ctx context.Context, req *ListRequest) ([]*database.McpToolCall, error) {
+	db := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeReadonly))
+
+	query := db.Model(&database.McpToolCall{})
+
+	if req.TokenID != "" {
+		query = query.Where("token_id = ?", req.TokenID)
+	}
+
+	if req.Tool != "" {
+		query = query.Where("tool = ?", req.Tool)
+	}
+
+	if len(req.Statuses) > 0 {
+		query = query.Where("status IN ?", req.Statuses)
+	}
+
+	if req.From != nil {
+		query = query.Where("created_at >= ?", req.From.UTC())
+	}
+
+	if req.To != nil {
+		query = query.Where("created_at <= ?", req.To.UTC())
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	var calls []*database.McpToolCall
+	if err := query.Order("id DESC").Limit(limit).Find(&calls).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return calls, nil
+}
@@ -0,0 +1,99 @@
+package audit_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/mcp/audit"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var (
+	cfg    *configuration.Configuration
+	gormDB *gorm.DB
+)
+
+func TestMain(m *testing.M) {
+	cfg = configuration.GetConfiguration()
+	gormDB = database.GetDb(database.DbTypeMaster)
+	os.Exit(m.Run())
+}
+
+func TestService_Record(t *testing.T) {
+	require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+	svc := audit.NewService()
+
+	require.NoError(t, svc.Record(context.Background(), &database.McpToolCall{
+		TokenID:    "jti-1",
+		UserID:     7,
+		Tool:       "query",
+		Arguments:  map[string]any{"sql": "SELECT 1"},
+		Status:     database.McpToolCallStatusError,
+		Error:      lo.ToPtr("query error"),
+		Rows:       0,
+		DurationMs: 12,
+		CreatedAt:  time.Now().UTC(),
+	}))
+
+	var stored database.McpToolCall
+	require.NoError(t, gormDB.First(&stored).Error)
+	assert.Equal(t, "jti-1", stored.TokenID)
+	assert.Equal(t, "SELECT 1", stored.Arguments["sql"])
+	assert.Equal(t, database.McpToolCallStatusError, stored.Status)
+	assert.Equal(t, "query error", *stored.Error)
+}
+
+func TestService_List(t *testing.T) {
+	require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+	svc := audit.NewService()
+
+	start := time.Date(2026, 7, 1, 10, 0, 0, 0, time.UTC)
+	calls := []*database.McpToolCall{
+		{TokenID: "a", UserID: 1, Tool: "query", Status: database.McpToolCallStatusSuccess, CreatedAt: start},
+		{TokenID: "a", UserID: 1, Tool: "search_transactions", Status: database.McpToolCallStatusError, CreatedAt: start.Add(time.Hour)},
+		{TokenID: "b", UserID: 1, Tool: "query", Status: database.McpToolCallStatusRejected, CreatedAt: start.Add(2 * time.Hour)},
+	}
+	require.NoError(t, gormDB.Create(&calls).Error)
+
+	t.Run("newest first", func(t *testing.T) {
+		rows, err := svc.List(context.Background(), &audit.ListRequest{})
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, calls[2].ID, rows[0].ID)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		rows, err := svc.List(context.Background(), &audit.ListRequest{
+			TokenID:  "a",
+			Statuses: []database.McpToolCallStatus{database.McpToolCallStatusError, database.McpToolCallStatusRejected},
+		})
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, "search_transactions", rows[0].Tool)
+	})
+
+	t.Run("time range and limit", func(t *testing.T) {
+		rows, err := svc.List(context.Background(), &audit.ListRequest{
+			From:  lo.ToPtr(start.Add(30 * time.Minute)),
+			To:    lo.ToPtr(start.Add(3 * time.Hour)),
+			Limit: 1,
+		})
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, calls[2].ID, rows[0].ID)
+	})
+
+	t.Run("tool", func(t *testing.T) {
+		rows, err := svc.List(context.Background(), &audit.ListRequest{Tool: "query"})
+		require.NoError(t, err)
+		assert.Len(t, rows, 2)
+	})
+}
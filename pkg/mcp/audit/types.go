@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/ft-t/go-money/pkg/database"
+)
+
+const defaultListLimit = 50
+
+type ListRequest struct {
+	TokenID  string
+	Tool     string
+	Statuses []database.McpToolCallStatus
+	From     *time.Time
+	To       *time.Time
+	Limit    int // defaults to 50
+}
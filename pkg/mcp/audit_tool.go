@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/mcp/audit"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+)
+
+var toolCallStatuses = map[string]database.McpToolCallStatus{
+	"success":  database.McpToolCallStatusSuccess,
+	"error":    database.McpToolCallStatusError,
+	"rejected": database.McpToolCallStatusRejected,
+}
+
+var toolCallStatusNames = lo.Invert(toolCallStatuses)
+
+type toolCallOutput struct {
+	ID         int64          `json:"id"`
+	TokenID    string         `json:"token_id"`
+	UserID     int32          `json:"user_id"`
+	Tool       string         `json:"tool"`
+	Status     string         `json:"status"`
+	Error      *string        `json:"error,omitempty"`
+	Rows       int32          `json:"rows"`
+	DurationMs int64          `json:"duration_ms"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	CreatedAt  string         `json:"created_at"`
+}
+
+func (s *Server) handleListMcpAuditLog(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.cfg.AuditSvc == nil {
+		return mcp.NewToolResultError("mcp audit log is disabled"), nil
+	}
+
+	args := request.GetArguments()
+
+	req := &audit.ListRequest{Limit: defaultSearchLimit}
+	req.TokenID, _ = args["token_id"].(string)
+	req.Tool, _ = args["tool"].(string)
+
+	if limit, ok := args["limit"].(float64); ok {
+		if limit < 1 || limit > maxSearchLimit {
+			return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit)), nil
+		}
+
+		req.Limit = int(limit)
+	}
+
+	if raw, ok := args["statuses"].([]any); ok {
+		for _, v := range raw {
+			name, _ := v.(string)
+
+			status, found := toolCallStatuses[strings.ToLower(name)]
+			if !found {
+				return mcp.NewToolResultError(fmt.Sprintf("unsupported status: %v", v)), nil
+			}
+
+			req.Statuses = append(req.Statuses, status)
+		}
+	}
+
+	from, err := parseDateArg(args, "from")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	to, err := parseDateArg(args, "to")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if from != nil {
+		start, _ := dayRange(*from, *from, s.cfg.Location)
+		req.From = &start
+	}
+
+	if to != nil {
+		_, end := dayRange(*to, *to, s.cfg.Location)
+		req.To = &end
+	}
+
+	includeArguments, _ := args["include_arguments"].(bool)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	calls, err := s.cfg.AuditSvc.List(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list mcp audit log: %v", err)), nil
+	}
+
+	reportRows(ctx, len(calls))
+
+	return toolJSONResult(lo.Map(calls, func(call *database.McpToolCall, _ int) *toolCallOutput {
+		out := &toolCallOutput{
+			ID:         call.ID,
+			TokenID:    call.TokenID,
+			UserID:     call.UserID,
+			Tool:       call.Tool,
+			Status:     toolCallStatusNames[call.Status],
+			Error:      call.Error,
+			Rows:       call.Rows,
+			DurationMs: call.DurationMs,
+			CreatedAt:  call.CreatedAt.UTC().Format(time.RFC3339),
+		}
+
+		if includeArguments {
+			out.Arguments = call.Arguments
+		}
+
+		return out
+	}))
+}
@@ -0,0 +1,129 @@
+package mcp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/mcp/audit"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+func TestServer_HandleListMcpAuditLog(t *testing.T) {
+	newServer := func(t *testing.T, auditSvc gomcp.AuditService) *gomcp.Server {
+		gormDB, mockDB, _ := testingutils.GormMock()
+		t.Cleanup(func() { _ = mockDB.Close() })
+
+		return gomcp.NewServer(&gomcp.ServerConfig{
+			DB:       gormDB,
+			Docs:     "test docs",
+			AuditSvc: auditSvc,
+		})
+	}
+
+	calls := []*database.McpToolCall{
+		{
+			ID:         5,
+			TokenID:    "token-jti",
+			UserID:     7,
+			Tool:       "query",
+			Arguments:  map[string]any{"sql": "SELECT 1"},
+			Status:     database.McpToolCallStatusRejected,
+			Error:      lo.ToPtr("rate limit exceeded"),
+			DurationMs: 3,
+			CreatedAt:  time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	t.Run("maps filters", func(t *testing.T) {
+		auditSvc := NewMockAuditService(gomock.NewController(t))
+		auditSvc.EXPECT().List(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *audit.ListRequest) ([]*database.McpToolCall, error) {
+				assert.Equal(t, "token-jti", req.TokenID)
+				assert.Equal(t, "query", req.Tool)
+				assert.Equal(t, []database.McpToolCallStatus{database.McpToolCallStatusRejected}, req.Statuses)
+				assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *req.From)
+				assert.Equal(t, time.Date(2026, 3, 2, 23, 59, 59, 999999999, time.UTC), *req.To)
+				assert.Equal(t, 10, req.Limit)
+
+				return calls, nil
+			})
+
+		result := callTool(t, newServer(t, auditSvc), "list_mcp_audit_log", map[string]any{
+			"token_id": "token-jti",
+			"tool":     "query",
+			"statuses": []any{"rejected"},
+			"from":     "2026-03-01",
+			"to":       "2026-03-02",
+			"limit":    float64(10),
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"status": "rejected"`)
+		assert.Contains(t, text, `"error": "rate limit exceeded"`)
+		assert.Contains(t, text, `"created_at": "2026-03-01T08:00:00Z"`)
+		assert.NotContains(t, text, "arguments")
+	})
+
+	t.Run("include arguments", func(t *testing.T) {
+		auditSvc := NewMockAuditService(gomock.NewController(t))
+		auditSvc.EXPECT().List(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *audit.ListRequest) ([]*database.McpToolCall, error) {
+				assert.Equal(t, 50, req.Limit)
+				assert.Nil(t, req.From)
+
+				return calls, nil
+			})
+
+		result := callTool(t, newServer(t, auditSvc), "list_mcp_audit_log", map[string]any{
+			"include_arguments": true,
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"sql": "SELECT 1"`)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			args     map[string]any
+			expected string
+		}{
+			"status": {args: map[string]any{"statuses": []any{"pending"}}, expected: "unsupported status: pending"},
+			"limit":  {args: map[string]any{"limit": float64(0)}, expected: "limit must be between 1 and 500"},
+			"date":   {args: map[string]any{"to": "yesterday"}, expected: "invalid to"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				result := callTool(t, newServer(t, NewMockAuditService(gomock.NewController(t))), "list_mcp_audit_log", c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		auditSvc := NewMockAuditService(gomock.NewController(t))
+		auditSvc.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newServer(t, auditSvc), "list_mcp_audit_log", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to list mcp audit log")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		result := callTool(t, newServer(t, nil), "list_mcp_audit_log", map[string]any{})
+
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "mcp audit log is disabled")
+	})
+}
@@ -3,13 +3,43 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	categoriesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/categories/v1"
 	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
 	"github.com/ft-t/go-money/pkg/database"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
 )
 
+type categoryOutput struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+func (s *Server) handleListCategories(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	categories, err := s.cfg.CategorySvc.GetAllCategories(queryCtx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list categories: %v", err)), nil
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].ID < categories[j].ID
+	})
+
+	return toolJSONResult(lo.Map(categories, func(category *database.Category, _ int) *categoryOutput {
+		return &categoryOutput{
+			ID:   category.ID,
+			Name: category.Name,
+		}
+	}))
+}
+
 func (s *Server) handleCreateCategory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
@@ -2,6 +2,7 @@ package mcp_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	categoriesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/categories/v1"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ft-t/go-money/pkg/database"
 	gomcp "github.com/ft-t/go-money/pkg/mcp"
 	"github.com/ft-t/go-money/pkg/testingutils"
 )
@@ -294,3 +296,41 @@ func TestServer_HandleUpdateCategory_Failure(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_HandleListCategories(t *testing.T) {
+	newServer := func(t *testing.T, catSvc *MockCategoryService) *gomcp.Server {
+		gormDB, mockDB, _ := testingutils.GormMock()
+		t.Cleanup(func() { _ = mockDB.Close() })
+
+		return gomcp.NewServer(&gomcp.ServerConfig{
+			DB:          gormDB,
+			Docs:        "test docs",
+			CategorySvc: catSvc,
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		catSvc := NewMockCategoryService(gomock.NewController(t))
+		catSvc.EXPECT().GetAllCategories(gomock.Any()).Return([]*database.Category{
+			{ID: 2, Name: "Groceries"},
+			{ID: 1, Name: "Rent"},
+		}, nil)
+
+		result := callTool(t, newServer(t, catSvc), "list_categories", map[string]any{})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"name": "Groceries"`)
+		assert.Less(t, strings.Index(text, "Rent"), strings.Index(text, "Groceries"))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		catSvc := NewMockCategoryService(gomock.NewController(t))
+		catSvc.EXPECT().GetAllCategories(gomock.Any()).Return(nil, errors.New("db down"))
+
+		result := callTool(t, newServer(t, catSvc), "list_categories", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to list categories: db down")
+	})
+}
@@ -4,12 +4,51 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/ft-t/go-money/pkg/currency"
 	"github.com/ft-t/go-money/pkg/database"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
 )
 
+var symbolPositionNames = map[database.CurrencySymbolPosition]string{
+	database.CurrencySymbolPositionBefore: "before",
+	database.CurrencySymbolPositionAfter:  "after",
+}
+
+var symbolPositions = lo.Invert(symbolPositionNames)
+
+type currencyOutput struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Symbol         string `json:"symbol"`
+	SymbolPosition string `json:"symbol_position"`
+	NumericCode    int32  `json:"numeric_code,omitempty"`
+	DecimalPlaces  int32  `json:"decimal_places"`
+	Rate           string `json:"rate"`
+	IsActive       bool   `json:"is_active"`
+	IsCustom       bool   `json:"is_custom"`
+	Example        string `json:"example"`
+}
+
+func mapCurrency(record *database.Currency) *currencyOutput {
+	return &currencyOutput{
+		ID:             record.ID,
+		Name:           record.Name,
+		Symbol:         record.Symbol,
+		SymbolPosition: symbolPositionNames[record.SymbolPosition],
+		NumericCode:    record.NumericCode,
+		DecimalPlaces:  record.DecimalPlaces,
+		Rate:           record.Rate.String(),
+		IsActive:       record.IsActive,
+		IsCustom:       record.IsCustom,
+		Example:        currency.FormatAmount(decimal.RequireFromString("-1234.5"), record, record.ID),
+	}
+}
+
 func (s *Server) handleConvertCurrency(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
 
@@ -50,3 +89,122 @@ func (s *Server) handleConvertCurrency(ctx context.Context, request mcp.CallTool
 	}
 	return mcp.NewToolResultText(string(result)), nil
 }
+
+func (s *Server) handleListCurrencies(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	includeInactive, _ := request.GetArguments()["include_inactive"].(bool)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	currencies, err := s.cfg.CatalogSvc.ListCurrencies(queryCtx, includeInactive)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list currencies: %v", err)), nil
+	}
+
+	return toolJSONResult(lo.Map(currencies, func(record *database.Currency, _ int) *currencyOutput {
+		return mapCurrency(record)
+	}))
+}
+
+func (s *Server) handleUpsertCurrency(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	id, _ := args["id"].(string)
+	if id == "" {
+		return mcp.NewToolResultError("id parameter is required"), nil
+	}
+
+	req := &currency.UpsertCurrencyRequest{
+		ID: id,
+	}
+
+	req.Name, _ = args["name"].(string)
+	req.IsCustom, _ = args["is_custom"].(bool)
+
+	if val, ok := args["symbol"].(string); ok {
+		req.Symbol = &val
+	}
+
+	if val, _ := args["symbol_position"].(string); val != "" {
+		position, ok := symbolPositions[strings.ToLower(val)]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid symbol_position: %s", val)), nil
+		}
+
+		req.SymbolPosition = &position
+	}
+
+	if val, ok := args["numeric_code"].(float64); ok {
+		req.NumericCode = int32(val)
+	}
+
+	if val, ok := args["decimal_places"].(float64); ok {
+		req.DecimalPlaces = lo.ToPtr(int32(val))
+	}
+
+	if val, ok := args["is_active"].(bool); ok {
+		req.IsActive = &val
+	}
+
+	if val, _ := args["rate"].(string); val != "" {
+		rate, err := decimal.NewFromString(val)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid rate: %v", err)), nil
+		}
+
+		req.Rate = &rate
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	record, err := s.cfg.CatalogSvc.UpsertCurrency(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to upsert currency: %v", err)), nil
+	}
+
+	return toolJSONResult(mapCurrency(record))
+}
+
+func (s *Server) handleSetCurrencyRate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	currencyID, _ := args["currency"].(string)
+	rateStr, _ := args["rate"].(string)
+	if currencyID == "" || rateStr == "" {
+		return mcp.NewToolResultError("currency and rate are required"), nil
+	}
+
+	rate, err := decimal.NewFromString(rateStr)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid rate: %v", err)), nil
+	}
+
+	req := &currency.SetCustomRateRequest{
+		CurrencyID: currencyID,
+		Rate:       rate,
+	}
+
+	if val, _ := args["date"].(string); val != "" {
+		req.Date, err = time.Parse(time.DateOnly, val)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid date: %v", err)), nil
+		}
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	record, err := s.cfg.CatalogSvc.SetCustomRate(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to set currency rate: %v", err)), nil
+	}
+
+	return toolJSONResult(mapCurrency(record))
+}
@@ -3,6 +3,7 @@ package mcp_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/golang/mock/gomock"
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/ft-t/go-money/pkg/database"
 	gomcp "github.com/ft-t/go-money/pkg/mcp"
 	"github.com/ft-t/go-money/pkg/testingutils"
 )
@@ -266,3 +268,151 @@ func TestServer_HandleConvertCurrency_Failure(t *testing.T) {
 		})
 	}
 }
+
+func newCatalogTestServer(t *testing.T, catalogSvc *MockCurrencyCatalogService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:         gormDB,
+		Docs:       "test docs",
+		CatalogSvc: catalogSvc,
+	})
+}
+
+func TestServer_HandleListCurrencies(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		catalogSvc := NewMockCurrencyCatalogService(gomock.NewController(t))
+		catalogSvc.EXPECT().ListCurrencies(gomock.Any(), true).Return([]*database.Currency{
+			{ID: "PLN", Name: "Zloty", Symbol: "zł", SymbolPosition: database.CurrencySymbolPositionAfter, NumericCode: 985, DecimalPlaces: 2, Rate: decimal.RequireFromString("3.8"), IsActive: true},
+			{ID: "MILES", Name: "Airline miles", Rate: decimal.NewFromInt(80), IsCustom: true},
+		}, nil)
+
+		result := callTool(t, newCatalogTestServer(t, catalogSvc), "list_currencies", map[string]any{"include_inactive": true})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"symbol_position": "after"`)
+		assert.Contains(t, text, `"numeric_code": 985`)
+		assert.Contains(t, text, `"example": "-1234.50 zł"`)
+		assert.Contains(t, text, `"example": "-1235 MILES"`)
+		assert.Contains(t, text, `"is_custom": true`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		catalogSvc := NewMockCurrencyCatalogService(gomock.NewController(t))
+		catalogSvc.EXPECT().ListCurrencies(gomock.Any(), false).Return(nil, assert.AnError)
+
+		result := callTool(t, newCatalogTestServer(t, catalogSvc), "list_currencies", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to list currencies")
+	})
+}
+
+func TestServer_HandleUpsertCurrency(t *testing.T) {
+	t.Run("pseudo currency", func(t *testing.T) {
+		catalogSvc := NewMockCurrencyCatalogService(gomock.NewController(t))
+		catalogSvc.EXPECT().UpsertCurrency(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *currency.UpsertCurrencyRequest) (*database.Currency, error) {
+				assert.Equal(t, "PTS", req.ID)
+				assert.Equal(t, "Store points", req.Name)
+				assert.Equal(t, "pts", *req.Symbol)
+				assert.Equal(t, database.CurrencySymbolPositionAfter, *req.SymbolPosition)
+				assert.EqualValues(t, 0, *req.DecimalPlaces)
+				assert.True(t, req.IsCustom)
+				assert.EqualValues(t, "100", req.Rate.String())
+				assert.Nil(t, req.IsActive)
+
+				return &database.Currency{ID: "PTS", Symbol: "pts", SymbolPosition: database.CurrencySymbolPositionAfter, Rate: *req.Rate, IsCustom: true}, nil
+			})
+
+		result := callTool(t, newCatalogTestServer(t, catalogSvc), "upsert_currency", map[string]any{
+			"id":              "PTS",
+			"name":            "Store points",
+			"symbol":          "pts",
+			"symbol_position": "After",
+			"decimal_places":  float64(0),
+			"is_custom":       true,
+			"rate":            "100",
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"example": "-1235 pts"`)
+	})
+
+	t.Run("invalid symbol position", func(t *testing.T) {
+		result := callTool(t, newCatalogTestServer(t, NewMockCurrencyCatalogService(gomock.NewController(t))), "upsert_currency", map[string]any{
+			"id":              "PTS",
+			"symbol_position": "middle",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid symbol_position")
+	})
+
+	t.Run("id required", func(t *testing.T) {
+		result := callTool(t, newCatalogTestServer(t, NewMockCurrencyCatalogService(gomock.NewController(t))), "upsert_currency", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "id parameter is required")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		catalogSvc := NewMockCurrencyCatalogService(gomock.NewController(t))
+		catalogSvc.EXPECT().UpsertCurrency(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newCatalogTestServer(t, catalogSvc), "upsert_currency", map[string]any{"id": "EUR"})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to upsert currency")
+	})
+}
+
+func TestServer_HandleSetCurrencyRate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		catalogSvc := NewMockCurrencyCatalogService(gomock.NewController(t))
+		catalogSvc.EXPECT().SetCustomRate(gomock.Any(), &currency.SetCustomRateRequest{
+			CurrencyID: "MILES",
+			Rate:       decimal.NewFromInt(75),
+			Date:       time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		}).Return(&database.Currency{ID: "MILES", Rate: decimal.NewFromInt(75), IsCustom: true}, nil)
+
+		result := callTool(t, newCatalogTestServer(t, catalogSvc), "set_currency_rate", map[string]any{
+			"currency": "MILES",
+			"rate":     "75",
+			"date":     "2026-07-01",
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"rate": "75"`)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		result := callTool(t, newCatalogTestServer(t, NewMockCurrencyCatalogService(gomock.NewController(t))), "set_currency_rate", map[string]any{
+			"currency": "MILES",
+			"rate":     "75",
+			"date":     "July",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid date")
+	})
+
+	t.Run("required", func(t *testing.T) {
+		result := callTool(t, newCatalogTestServer(t, NewMockCurrencyCatalogService(gomock.NewController(t))), "set_currency_rate", map[string]any{"currency": "MILES"})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "currency and rate are required")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		catalogSvc := NewMockCurrencyCatalogService(gomock.NewController(t))
+		catalogSvc.EXPECT().SetCustomRate(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newCatalogTestServer(t, catalogSvc), "set_currency_rate", map[string]any{"currency": "EUR", "rate": "1"})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to set currency rate")
+	})
+}
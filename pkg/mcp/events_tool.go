@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+)
+
+const maxEventsWait = 25 * time.Second // below queryTimeout
+
+var historyEventTypes = lo.Invert(historyEventTypeNames)
+
+type transactionEventsOutput struct {
+	Events     []*transactionHistoryOutput `json:"events"`
+	NextCursor int64                       `json:"next_cursor"`
+}
+
+// parseEventTypesArg reads event type names, ok is false when the argument is absent.
+func parseEventTypesArg(args map[string]any, key string) ([]database.TransactionHistoryEventType, bool, error) {
+	raw, exists := args[key]
+	if !exists || raw == nil {
+		return nil, false, nil
+	}
+
+	names, ok := raw.([]any)
+	if !ok {
+		return nil, false, errors.Newf("%s must be an array", key)
+	}
+
+	result := make([]database.TransactionHistoryEventType, 0, len(names))
+	for i, v := range names {
+		name, _ := v.(string)
+
+		eventType, found := historyEventTypes[name]
+		if !found {
+			return nil, false, errors.Newf("%s[%d] must be one of created, updated, deleted, rule_applied", key, i)
+		}
+
+		result = append(result, eventType)
+	}
+
+	return result, true, nil
+}
+
+func (s *Server) handleListTransactionEvents(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.cfg.EventFeedSvc == nil {
+		return mcp.NewToolResultError("event feed is disabled"), nil
+	}
+
+	args := request.GetArguments()
+
+	req := &history.FeedRequest{Limit: defaultSearchLimit}
+
+	if v, ok := args["after_id"].(float64); ok {
+		if v < 0 {
+			return mcp.NewToolResultError("after_id must not be negative"), nil
+		}
+
+		req.AfterID = int64(v)
+	}
+
+	if v, ok := args["limit"].(float64); ok {
+		req.Limit = int(v)
+		if req.Limit < 1 || req.Limit > maxSearchLimit {
+			return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit)), nil
+		}
+	}
+
+	var wait time.Duration
+	if v, ok := args["wait_seconds"].(float64); ok {
+		wait = time.Duration(v * float64(time.Second))
+		if wait < 0 || wait > maxEventsWait {
+			return mcp.NewToolResultError(fmt.Sprintf("wait_seconds must be between 0 and %d", int(maxEventsWait.Seconds()))), nil
+		}
+	}
+
+	eventTypes, _, err := parseEventTypesArg(args, "event_types")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	req.EventTypes = eventTypes
+	includeSnapshot, _ := args["include_snapshot"].(bool)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	events, err := s.cfg.EventFeedSvc.Wait(queryCtx, req, wait)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list transaction events: %v", err)), nil
+	}
+
+	reportRows(ctx, len(events))
+
+	out := &transactionEventsOutput{
+		Events:     make([]*transactionHistoryOutput, 0, len(events)),
+		NextCursor: req.AfterID,
+	}
+
+	for _, event := range events {
+		mapped := mapHistoryEvent(event, includeSnapshot)
+		mapped.TransactionID = event.TransactionID
+
+		out.Events = append(out.Events, mapped)
+		out.NextCursor = lo.FromPtr(event.FeedID)
+	}
+
+	return toolJSONResult(out)
+}
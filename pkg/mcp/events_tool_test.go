@@ -0,0 +1,116 @@
+package mcp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+)
+
+func newEventsTestServer(t *testing.T, feedSvc gomcp.EventFeedService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:           gormDB,
+		Docs:         "test docs",
+		EventFeedSvc: feedSvc,
+	})
+}
+
+func TestServer_HandleListTransactionEvents(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		feedSvc := NewMockEventFeedService(gomock.NewController(t))
+		feedSvc.EXPECT().Wait(gomock.Any(), gomock.Any(), 5*time.Second).
+			DoAndReturn(func(_ context.Context, req *history.FeedRequest, _ time.Duration) ([]*database.TransactionHistory, error) {
+				assert.EqualValues(t, 40, req.AfterID)
+				assert.Equal(t, 10, req.Limit)
+				assert.Equal(t, []database.TransactionHistoryEventType{database.TransactionHistoryEventTypeDeleted}, req.EventTypes)
+
+				return []*database.TransactionHistory{
+					{
+						ID:            41,
+						FeedID:        lo.ToPtr(int64(43)),
+						TransactionID: 9,
+						EventType:     database.TransactionHistoryEventTypeDeleted,
+						ActorType:     database.TransactionHistoryActorTypeUser,
+						Snapshot:      map[string]any{"title": "Coffee"},
+						OccurredAt:    time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+					},
+				}, nil
+			})
+
+		result := callTool(t, newEventsTestServer(t, feedSvc), "list_transaction_events", map[string]any{
+			"after_id":     float64(40),
+			"limit":        float64(10),
+			"wait_seconds": float64(5),
+			"event_types":  []any{"deleted"},
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"transaction_id": 9`)
+		assert.Contains(t, text, `"event_type": "deleted"`)
+		assert.Contains(t, text, `"next_cursor": 43`)
+		assert.NotContains(t, text, "Coffee")
+	})
+
+	t.Run("empty keeps cursor", func(t *testing.T) {
+		feedSvc := NewMockEventFeedService(gomock.NewController(t))
+		feedSvc.EXPECT().Wait(gomock.Any(), gomock.Any(), time.Duration(0)).Return(nil, nil)
+
+		result := callTool(t, newEventsTestServer(t, feedSvc), "list_transaction_events", map[string]any{
+			"after_id": float64(40),
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"events": []`)
+		assert.Contains(t, text, `"next_cursor": 40`)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			args     map[string]any
+			expected string
+		}{
+			"after id": {args: map[string]any{"after_id": float64(-1)}, expected: "after_id must not be negative"},
+			"limit":    {args: map[string]any{"limit": float64(501)}, expected: "limit must be between 1 and 500"},
+			"wait":     {args: map[string]any{"wait_seconds": float64(26)}, expected: "wait_seconds must be between 0 and 25"},
+			"type":     {args: map[string]any{"event_types": []any{"moved"}}, expected: "event_types[0] must be one of"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				result := callTool(t, newEventsTestServer(t, NewMockEventFeedService(gomock.NewController(t))), "list_transaction_events", c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		feedSvc := NewMockEventFeedService(gomock.NewController(t))
+		feedSvc.EXPECT().Wait(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newEventsTestServer(t, feedSvc), "list_transaction_events", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to list transaction events")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		result := callTool(t, newEventsTestServer(t, nil), "list_transaction_events", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "event feed is disabled")
+	})
+}
@@ -0,0 +1,249 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+var exchangeRateSources = map[database.ExchangeRateSource]string{
+	database.ExchangeRateSourceUnspecified: "",
+	database.ExchangeRateSourceSynced:      "synced",
+	database.ExchangeRateSourceOverride:    "override",
+	database.ExchangeRateSourceDerived:     "derived",
+	database.ExchangeRateSourceManual:      "manual",
+}
+
+type transactionExchangeRateOutput struct {
+	TransactionID                   int64  `json:"transaction_id"`
+	SourceCurrency                  string `json:"source_currency"`
+	ExchangeRate                    string `json:"exchange_rate,omitempty"`
+	ExchangeRateSource              string `json:"exchange_rate_source,omitempty"`
+	ExchangeRateLocked              bool   `json:"exchange_rate_locked"`
+	SourceAmountInBaseCurrency      string `json:"source_amount_in_base_currency,omitempty"`
+	DestinationAmountInBaseCurrency string `json:"destination_amount_in_base_currency,omitempty"`
+}
+
+type rateOverrideOutput struct {
+	ID         int32      `json:"id"`
+	CurrencyID string     `json:"currency_id"`
+	Rate       string     `json:"rate"`
+	ValidFrom  string     `json:"valid_from"`
+	ValidTo    *string    `json:"valid_to,omitempty"`
+	Note       string     `json:"note,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+}
+
+func nullDecimalString(d decimal.NullDecimal) string {
+	if !d.Valid {
+		return ""
+	}
+
+	return d.Decimal.String()
+}
+
+func mapRateOverride(o *database.CurrencyRateOverride) *rateOverrideOutput {
+	out := &rateOverrideOutput{
+		ID:         o.ID,
+		CurrencyID: o.CurrencyID,
+		Rate:       o.Rate.String(),
+		ValidFrom:  o.ValidFrom.Format(time.DateOnly),
+		Note:       o.Note,
+		CreatedAt:  o.CreatedAt,
+	}
+
+	if o.ValidTo != nil {
+		out.ValidTo = lo.ToPtr(o.ValidTo.Format(time.DateOnly))
+	}
+
+	if o.DeletedAt.Valid {
+		out.DeletedAt = &o.DeletedAt.Time
+	}
+
+	return out
+}
+
+func (s *Server) handleSetTransactionExchangeRate(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	assignmentsRaw, ok := args["assignments"].([]any)
+	if !ok || len(assignmentsRaw) == 0 {
+		return mcp.NewToolResultError("assignments parameter is required and must be a non-empty array"), nil
+	}
+
+	assignments := make([]transactions.ExchangeRateAssignment, 0, len(assignmentsRaw))
+	for i, item := range assignmentsRaw {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("assignment[%d] must be an object", i)), nil
+		}
+
+		txID, ok := itemMap["transaction_id"].(float64)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("assignment[%d].transaction_id is required and must be a number", i)), nil
+		}
+
+		assignment := transactions.ExchangeRateAssignment{
+			TransactionID: int64(txID),
+			Locked:        true,
+		}
+
+		if locked, exists := itemMap["locked"]; exists {
+			lockedBool, ok := locked.(bool)
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("assignment[%d].locked must be a boolean", i)), nil
+			}
+
+			assignment.Locked = lockedBool
+		}
+
+		if rateRaw, exists := itemMap["rate"]; exists && rateRaw != nil {
+			rateStr, ok := rateRaw.(string)
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("assignment[%d].rate must be a decimal string", i)), nil
+			}
+
+			rate, err := decimal.NewFromString(rateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("assignment[%d].rate is invalid: %v", i, err)), nil
+			}
+
+			assignment.Rate = &rate
+		}
+
+		assignments = append(assignments, assignment)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	updated, err := s.cfg.TransactionSvc.SetExchangeRates(queryCtx, assignments)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to set exchange rates: %v", err)), nil
+	}
+
+	out := lo.Map(updated, func(t *database.Transaction, _ int) *transactionExchangeRateOutput {
+		return &transactionExchangeRateOutput{
+			TransactionID:                   t.ID,
+			SourceCurrency:                  t.SourceCurrency,
+			ExchangeRate:                    nullDecimalString(t.ExchangeRate),
+			ExchangeRateSource:              exchangeRateSources[t.ExchangeRateSource],
+			ExchangeRateLocked:              t.ExchangeRateLocked,
+			SourceAmountInBaseCurrency:      nullDecimalString(t.SourceAmountInBaseCurrency),
+			DestinationAmountInBaseCurrency: nullDecimalString(t.DestinationAmountInBaseCurrency),
+		}
+	})
+
+	result, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleListRateOverrides(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	currencyID, _ := request.GetArguments()["currency"].(string)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	overrides, err := s.cfg.RateOverrideSvc.ListOverrides(queryCtx, currencyID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list rate overrides: %v", err)), nil
+	}
+
+	result, err := json.MarshalIndent(lo.Map(overrides, func(o *database.CurrencyRateOverride, _ int) *rateOverrideOutput {
+		return mapRateOverride(o)
+	}), "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleCreateRateOverride(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	currencyID, _ := args["currency"].(string)
+	if currencyID == "" {
+		return mcp.NewToolResultError("currency parameter is required"), nil
+	}
+
+	req := &currency.CreateRateOverrideRequest{
+		CurrencyID: currencyID,
+	}
+
+	var err error
+	rate, _ := args["rate"].(string)
+	if req.Rate, err = decimal.NewFromString(rate); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid rate: %v", err)), nil
+	}
+
+	validFrom, _ := args["valid_from"].(string)
+	if req.ValidFrom, err = time.Parse(time.DateOnly, validFrom); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid valid_from: %v", err)), nil
+	}
+
+	if validTo, _ := args["valid_to"].(string); validTo != "" {
+		parsed, parseErr := time.Parse(time.DateOnly, validTo)
+		if parseErr != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid valid_to: %v", parseErr)), nil
+		}
+
+		req.ValidTo = &parsed
+	}
+
+	req.Note, _ = args["note"].(string)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	override, err := s.cfg.RateOverrideSvc.CreateOverride(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create rate override: %v", err)), nil
+	}
+
+	result, err := json.MarshalIndent(mapRateOverride(override), "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleDeleteRateOverride(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	override, err := s.cfg.RateOverrideSvc.DeleteOverride(queryCtx, int32(id))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to delete rate override: %v", err)), nil
+	}
+
+	result, err := json.MarshalIndent(mapRateOverride(override), "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
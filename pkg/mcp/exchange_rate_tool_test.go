@@ -0,0 +1,204 @@
+package mcp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions"
+)
+
+func newExchangeRateTestServer(
+	t *testing.T,
+	txSvc *MockTransactionService,
+	overrideSvc *MockRateOverridesService,
+) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:              gormDB,
+		Docs:            "test docs",
+		TransactionSvc:  txSvc,
+		RateOverrideSvc: overrideSvc,
+	})
+}
+
+func TestServer_HandleSetTransactionExchangeRate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		txSvc := NewMockTransactionService(gomock.NewController(t))
+
+		rate := decimal.RequireFromString("4.25")
+		txSvc.EXPECT().SetExchangeRates(gomock.Any(), []transactions.ExchangeRateAssignment{
+			{TransactionID: 10, Rate: &rate, Locked: true},
+			{TransactionID: 11, Locked: false},
+		}).Return([]*database.Transaction{
+			{
+				ID:                         10,
+				SourceCurrency:             "PLN",
+				ExchangeRate:               decimal.NewNullDecimal(rate),
+				ExchangeRateSource:         database.ExchangeRateSourceManual,
+				ExchangeRateLocked:         true,
+				SourceAmountInBaseCurrency: decimal.NewNullDecimal(decimal.NewFromInt(-20)),
+			},
+			{
+				ID:                 11,
+				SourceCurrency:     "PLN",
+				ExchangeRate:       decimal.NewNullDecimal(decimal.NewFromInt(4)),
+				ExchangeRateSource: database.ExchangeRateSourceSynced,
+			},
+		}, nil)
+
+		result := callTool(t, newExchangeRateTestServer(t, txSvc, nil), "set_transaction_exchange_rate", map[string]any{
+			"assignments": []any{
+				map[string]any{"transaction_id": float64(10), "rate": "4.25"},
+				map[string]any{"transaction_id": float64(11), "locked": false},
+			},
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"exchange_rate": "4.25"`)
+		assert.Contains(t, text, `"exchange_rate_source": "manual"`)
+		assert.Contains(t, text, `"exchange_rate_source": "synced"`)
+		assert.Contains(t, text, `"source_amount_in_base_currency": "-20"`)
+	})
+
+	t.Run("missing assignments", func(t *testing.T) {
+		result := callTool(t, newExchangeRateTestServer(t, NewMockTransactionService(gomock.NewController(t)), nil),
+			"set_transaction_exchange_rate", map[string]any{})
+
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("invalid rate", func(t *testing.T) {
+		result := callTool(t, newExchangeRateTestServer(t, NewMockTransactionService(gomock.NewController(t)), nil),
+			"set_transaction_exchange_rate", map[string]any{
+				"assignments": []any{
+					map[string]any{"transaction_id": float64(10), "rate": "abc"},
+				},
+			})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "assignment[0].rate is invalid")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		txSvc := NewMockTransactionService(gomock.NewController(t))
+		txSvc.EXPECT().SetExchangeRates(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newExchangeRateTestServer(t, txSvc, nil), "set_transaction_exchange_rate", map[string]any{
+			"assignments": []any{
+				map[string]any{"transaction_id": float64(10)},
+			},
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to set exchange rates")
+	})
+}
+
+func TestServer_HandleRateOverrides(t *testing.T) {
+	validFrom := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	validTo := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("create", func(t *testing.T) {
+		overrideSvc := NewMockRateOverridesService(gomock.NewController(t))
+		overrideSvc.EXPECT().CreateOverride(gomock.Any(), &currency.CreateRateOverrideRequest{
+			CurrencyID: "PLN",
+			Rate:       decimal.RequireFromString("4.1"),
+			ValidFrom:  validFrom,
+			ValidTo:    &validTo,
+			Note:       "bank",
+		}).Return(&database.CurrencyRateOverride{
+			ID:         1,
+			CurrencyID: "PLN",
+			Rate:       decimal.RequireFromString("4.1"),
+			ValidFrom:  validFrom,
+			ValidTo:    &validTo,
+		}, nil)
+
+		result := callTool(t, newExchangeRateTestServer(t, nil, overrideSvc), "create_rate_override", map[string]any{
+			"currency":   "PLN",
+			"rate":       "4.1",
+			"valid_from": "2025-07-01",
+			"valid_to":   "2025-07-31",
+			"note":       "bank",
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"valid_from": "2025-07-01"`)
+		assert.Contains(t, text, `"valid_to": "2025-07-31"`)
+	})
+
+	t.Run("create invalid date", func(t *testing.T) {
+		result := callTool(t, newExchangeRateTestServer(t, nil, NewMockRateOverridesService(gomock.NewController(t))),
+			"create_rate_override", map[string]any{
+				"currency":   "PLN",
+				"rate":       "4.1",
+				"valid_from": "July",
+			})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid valid_from")
+	})
+
+	t.Run("create service error", func(t *testing.T) {
+		overrideSvc := NewMockRateOverridesService(gomock.NewController(t))
+		overrideSvc.EXPECT().CreateOverride(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newExchangeRateTestServer(t, nil, overrideSvc), "create_rate_override", map[string]any{
+			"currency":   "PLN",
+			"rate":       "4.1",
+			"valid_from": "2025-07-01",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to create rate override")
+	})
+
+	t.Run("list", func(t *testing.T) {
+		overrideSvc := NewMockRateOverridesService(gomock.NewController(t))
+		overrideSvc.EXPECT().ListOverrides(gomock.Any(), "PLN").Return([]*database.CurrencyRateOverride{
+			{ID: 1, CurrencyID: "PLN", Rate: decimal.NewFromInt(4), ValidFrom: validFrom},
+		}, nil)
+
+		result := callTool(t, newExchangeRateTestServer(t, nil, overrideSvc), "list_rate_overrides", map[string]any{
+			"currency": "PLN",
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"currency_id": "PLN"`)
+		assert.NotContains(t, text, "valid_to")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		overrideSvc := NewMockRateOverridesService(gomock.NewController(t))
+		overrideSvc.EXPECT().DeleteOverride(gomock.Any(), int32(3)).
+			Return(&database.CurrencyRateOverride{ID: 3, CurrencyID: "PLN", ValidFrom: validFrom}, nil)
+
+		result := callTool(t, newExchangeRateTestServer(t, nil, overrideSvc), "delete_rate_override", map[string]any{
+			"id": float64(3),
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"id": 3`)
+	})
+
+	t.Run("delete missing id", func(t *testing.T) {
+		result := callTool(t, newExchangeRateTestServer(t, nil, NewMockRateOverridesService(gomock.NewController(t))),
+			"delete_rate_override", map[string]any{})
+
+		assert.True(t, result.IsError)
+	})
+}
@@ -0,0 +1,9 @@
+package mcp
+
+// TrackedTokens returns the number of tokens the limiter keeps usage of.
+func (l *RateLimiter) TrackedTokens() int {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	return len(l.usage)
+}
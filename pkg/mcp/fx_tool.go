@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/analytics"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+)
+
+type fxAccountRevaluationOutput struct {
+	AccountID      int32  `json:"account_id"`
+	Currency       string `json:"currency"`
+	OpeningBalance string `json:"opening_balance"`
+	ClosingBalance string `json:"closing_balance"`
+	OpeningRate    string `json:"opening_rate"`
+	ClosingRate    string `json:"closing_rate"`
+	OpeningValue   string `json:"opening_value"`
+	ClosingValue   string `json:"closing_value"`
+	Flows          string `json:"flows"`
+	Unrealized     string `json:"unrealized"`
+}
+
+type fxRealizedGainOutput struct {
+	TransactionID                int64  `json:"transaction_id"`
+	AccountID                    int32  `json:"account_id"`
+	Date                         string `json:"date"`
+	GivenCurrency                string `json:"given_currency"`
+	GivenAmount                  string `json:"given_amount"`
+	ReceivedCurrency             string `json:"received_currency"`
+	ReceivedAmount               string `json:"received_amount"`
+	RecordedAmountInBaseCurrency string `json:"recorded_amount_in_base_currency"`
+	GivenValue                   string `json:"given_value"`
+	ReceivedValue                string `json:"received_value"`
+	Gain                         string `json:"gain"`
+}
+
+type fxGainLossOutput struct {
+	From         string                        `json:"from"`
+	To           string                        `json:"to"`
+	BaseCurrency string                        `json:"base_currency"`
+	Unrealized   string                        `json:"unrealized"`
+	Realized     string                        `json:"realized"`
+	Total        string                        `json:"total"`
+	Accounts     []*fxAccountRevaluationOutput `json:"accounts"`
+	Exchanges    []*fxRealizedGainOutput       `json:"exchanges"`
+}
+
+func (s *Server) handleGetFxGainLoss(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+	req := &analytics.FxGainLossRequest{}
+
+	for name, target := range map[string]*time.Time{
+		"from": &req.From,
+		"to":   &req.To,
+	} {
+		val, _ := args[name].(string)
+
+		parsed, err := time.Parse(time.DateOnly, val)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid %s: %v", name, err)), nil
+		}
+
+		*target = parsed
+	}
+
+	accountIDs, err := parseInt32SliceArg(args, "account_ids")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	req.From, req.To = dayRange(req.From, req.To, s.cfg.Location)
+	req.AccountIDs = accountIDs
+	req.PostRevaluations, _ = args["post_revaluations"].(bool)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	report, err := s.cfg.AnalyticsSvc.GetFxGainLoss(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get fx gain/loss: %v", err)), nil
+	}
+
+	return toolJSONResult(&fxGainLossOutput{
+		From:         report.From.Format(time.DateOnly),
+		To:           report.To.Format(time.DateOnly),
+		BaseCurrency: report.BaseCurrency,
+		Unrealized:   report.Unrealized.String(),
+		Realized:     report.RealizedGain.String(),
+		Total:        report.Total.String(),
+		Accounts: lo.Map(report.Accounts, func(a *analytics.FxAccountRevaluation, _ int) *fxAccountRevaluationOutput {
+			return &fxAccountRevaluationOutput{
+				AccountID:      a.AccountID,
+				Currency:       a.Currency,
+				OpeningBalance: a.OpeningBalance.String(),
+				ClosingBalance: a.ClosingBalance.String(),
+				OpeningRate:    a.OpeningRate.String(),
+				ClosingRate:    a.ClosingRate.String(),
+				OpeningValue:   a.OpeningValue.String(),
+				ClosingValue:   a.ClosingValue.String(),
+				Flows:          a.Flows.String(),
+				Unrealized:     a.Unrealized.String(),
+			}
+		}),
+		Exchanges: lo.Map(report.Realized, func(g *analytics.FxRealizedGain, _ int) *fxRealizedGainOutput {
+			return &fxRealizedGainOutput{
+				TransactionID:                g.TransactionID,
+				AccountID:                    g.AccountID,
+				Date:                         g.Date.Format(time.DateOnly),
+				GivenCurrency:                g.GivenCurrency,
+				GivenAmount:                  g.GivenAmount.String(),
+				ReceivedCurrency:             g.ReceivedCurrency,
+				ReceivedAmount:               g.ReceivedAmount.String(),
+				RecordedAmountInBaseCurrency: g.RecordedAmountInBaseCurrency.String(),
+				GivenValue:                   g.GivenValue.String(),
+				ReceivedValue:                g.ReceivedValue.String(),
+				Gain:                         g.Gain.String(),
+			}
+		}),
+	})
+}
@@ -0,0 +1,79 @@
+package mcp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/analytics"
+)
+
+func TestServer_HandleGetFxGainLoss(t *testing.T) {
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		analyticsSvc := NewMockAnalyticsService(gomock.NewController(t))
+		analyticsSvc.EXPECT().GetFxGainLoss(gomock.Any(), &analytics.FxGainLossRequest{
+			From:             from,
+			To:               to.AddDate(0, 0, 1).Add(-time.Nanosecond), // end of the day
+			AccountIDs:       []int32{1, 3},
+			PostRevaluations: true,
+		}).Return(&analytics.FxGainLoss{
+			From:         from,
+			To:           to,
+			BaseCurrency: "USD",
+			Accounts: []*analytics.FxAccountRevaluation{
+				{AccountID: 1, Currency: "EUR", Unrealized: decimal.RequireFromString("-133.66")},
+			},
+			Realized: []*analytics.FxRealizedGain{
+				{TransactionID: 5, Date: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), Gain: decimal.RequireFromString("5.88")},
+			},
+			Unrealized:   decimal.RequireFromString("-133.66"),
+			RealizedGain: decimal.RequireFromString("5.88"),
+			Total:        decimal.RequireFromString("-127.78"),
+		}, nil)
+
+		result := callTool(t, newInvestmentsTestServer(t, nil, analyticsSvc), "get_fx_gain_loss", map[string]any{
+			"from":              "2026-02-01",
+			"to":                "2026-02-28",
+			"account_ids":       []any{float64(1), float64(3)},
+			"post_revaluations": true,
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"unrealized": "-133.66"`)
+		assert.Contains(t, text, `"total": "-127.78"`)
+		assert.Contains(t, text, `"date": "2026-02-10"`)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		result := callTool(t, newInvestmentsTestServer(t, nil, NewMockAnalyticsService(gomock.NewController(t))),
+			"get_fx_gain_loss", map[string]any{
+				"from": "2026-02-01",
+			})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid to")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		analyticsSvc := NewMockAnalyticsService(gomock.NewController(t))
+		analyticsSvc.EXPECT().GetFxGainLoss(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("no exchange rate for UAH on 2026-02-01"))
+
+		result := callTool(t, newInvestmentsTestServer(t, nil, analyticsSvc), "get_fx_gain_loss", map[string]any{
+			"from": "2026-02-01",
+			"to":   "2026-02-28",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to get fx gain/loss: no exchange rate for UAH")
+	})
+}
@@ -0,0 +1,216 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	importv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/import/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/suggestions"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+)
+
+const importTimeout = 5 * time.Minute // large statements run rules for every row
+
+type importRowOutput struct {
+	*transactionOutput
+	DuplicateTransactionID *int64             `json:"duplicate_transaction_id,omitempty"`
+	SuggestedCategories    []*candidateOutput `json:"suggested_categories,omitempty"`
+	SuggestedTags          []*candidateOutput `json:"suggested_tags,omitempty"`
+}
+
+type parseImportOutput struct {
+	Total        int                `json:"total"`
+	Duplicates   int                `json:"duplicates"`
+	Unparsed     int                `json:"unparsed"`
+	Transactions []*importRowOutput `json:"transactions"`
+}
+
+type commitImportOutput struct {
+	ImportedCount  int32 `json:"imported_count"`
+	DuplicateCount int32 `json:"duplicate_count"`
+	SkippedCount   int32 `json:"skipped_count"`
+}
+
+// parseImportArgs reads the source and the files shared by parse_import and commit_import.
+func parseImportArgs(args map[string]any) (importv1.ImportSource, []string, error) {
+	name, _ := args["source"].(string)
+
+	source, found := importv1.ImportSource_value[name]
+	if !found || source == 0 {
+		return 0, nil, errors.New("source must be an ImportSource name like IMPORT_SOURCE_REVOLUT")
+	}
+
+	rawFiles, _ := args["files"].([]any)
+	if len(rawFiles) == 0 {
+		return 0, nil, errors.New("files parameter is required")
+	}
+
+	encoding, _ := args["encoding"].(string)
+	if encoding == "" {
+		encoding = "base64"
+	}
+
+	if encoding != "base64" && encoding != "text" {
+		return 0, nil, errors.New("encoding must be base64 or text")
+	}
+
+	files := make([]string, 0, len(rawFiles))
+	for i, raw := range rawFiles {
+		content, ok := raw.(string)
+		if !ok || content == "" {
+			return 0, nil, errors.Newf("files[%d] must be a non empty string", i)
+		}
+
+		if encoding == "text" {
+			content = base64.StdEncoding.EncodeToString([]byte(content))
+		} else if _, err := base64.StdEncoding.DecodeString(content); err != nil {
+			return 0, nil, errors.Wrapf(err, "files[%d] is not valid base64", i)
+		}
+
+		files = append(files, content)
+	}
+
+	return importv1.ImportSource(source), files, nil
+}
+
+func (s *Server) handleParseImport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	source, files, err := parseImportArgs(args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	treatDatesAsUtc, _ := args["treat_dates_as_utc"].(bool)
+	skipDuplicateReferenceCheck, _ := args["skip_duplicate_reference_check"].(bool)
+
+	importCtx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+
+	importCtx = database.WithContext(importCtx, s.db)
+
+	resp, err := s.cfg.ImportSvc.Parse(importCtx, &importv1.ParseTransactionsRequest{
+		Content:                     files,
+		Source:                      source,
+		TreatDatesAsUtc:             treatDatesAsUtc,
+		SkipDuplicateReferenceCheck: skipDuplicateReferenceCheck,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to parse import: %v", err)), nil
+	}
+
+	reportRows(ctx, len(resp.Transactions))
+
+	out := &parseImportOutput{
+		Total: len(resp.Transactions),
+		Transactions: lo.Map(resp.Transactions, func(row *importv1.ParseTransactionsResponse_ParsedTransaction, _ int) *importRowOutput {
+			mapped := &importRowOutput{
+				transactionOutput:      mapTransactionOutput(row.Transaction),
+				DuplicateTransactionID: row.DuplicateTransactionId,
+			}
+
+			if row.Transaction.Type == gomoneypbv1.TransactionType_TRANSACTION_TYPE_UNSPECIFIED {
+				mapped.Type = "unparsed" // the importer could not map the row, title and notes hold the raw data
+			}
+
+			return mapped
+		}),
+	}
+
+	if err = s.suggestImportRows(importCtx, resp.Transactions, out.Transactions); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to suggest categories: %v", err)), nil
+	}
+
+	for _, row := range out.Transactions {
+		if row.DuplicateTransactionID != nil {
+			out.Duplicates++
+		}
+
+		if row.Type == "unparsed" {
+			out.Unparsed++
+		}
+	}
+
+	return toolJSONResult(out)
+}
+
+// suggestImportRows fills suggestions for new parsed rows that have no category yet.
+func (s *Server) suggestImportRows(
+	ctx context.Context,
+	parsed []*importv1.ParseTransactionsResponse_ParsedTransaction,
+	rows []*importRowOutput,
+) error {
+	if s.cfg.SuggestionSvc == nil {
+		return nil
+	}
+
+	var inputs []*suggestions.Input
+	var targets []*importRowOutput
+
+	for i, row := range parsed {
+		if row.DuplicateTransactionId != nil || row.Transaction.CategoryId != nil ||
+			row.Transaction.Type == gomoneypbv1.TransactionType_TRANSACTION_TYPE_UNSPECIFIED {
+			continue
+		}
+
+		inputs = append(inputs, suggestions.InputFromProto(row.Transaction))
+		targets = append(targets, rows[i])
+	}
+
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	result, err := s.cfg.SuggestionSvc.Suggest(ctx, inputs)
+	if err != nil {
+		return err
+	}
+
+	for i, suggestion := range result {
+		targets[i].SuggestedCategories = mapCandidates(suggestion.Categories)
+		targets[i].SuggestedTags = mapCandidates(suggestion.Tags)
+	}
+
+	return nil
+}
+
+func (s *Server) handleCommitImport(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	source, files, err := parseImportArgs(args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	treatDatesAsUtc, _ := args["treat_dates_as_utc"].(bool)
+	skipDuplicateReferenceCheck, _ := args["skip_duplicate_reference_check"].(bool)
+	skipValidationErrors, _ := args["skip_validation_errors"].(bool)
+
+	importCtx, cancel := context.WithTimeout(ctx, importTimeout)
+	defer cancel()
+
+	importCtx = database.WithContext(importCtx, s.db)
+
+	resp, err := s.cfg.ImportSvc.Import(importCtx, &importv1.ImportTransactionsRequest{
+		Content:                     files,
+		Source:                      source,
+		TreatDatesAsUtc:             treatDatesAsUtc,
+		SkipDuplicateReferenceCheck: skipDuplicateReferenceCheck,
+		SkipValidationErrors:        skipValidationErrors,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to import: %v", err)), nil
+	}
+
+	return toolJSONResult(&commitImportOutput{
+		ImportedCount:  resp.ImportedCount,
+		DuplicateCount: resp.DuplicateCount,
+		SkippedCount:   resp.SkippedCount,
+	})
+}
@@ -0,0 +1,231 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	importv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/import/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/suggestions"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+func newImportTestServer(t *testing.T, importSvc *MockImportService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:        gormDB,
+		Docs:      "test docs",
+		ImportSvc: importSvc,
+	})
+}
+
+func TestServer_HandleParseImport(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		importSvc := NewMockImportService(gomock.NewController(t))
+		importSvc.EXPECT().Parse(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *importv1.ParseTransactionsRequest) (*importv1.ParseTransactionsResponse, error) {
+				assert.Equal(t, importv1.ImportSource_IMPORT_SOURCE_REVOLUT, req.Source)
+				assert.Equal(t, []string{base64.StdEncoding.EncodeToString([]byte("Type,Product\n"))}, req.Content)
+				assert.True(t, req.SkipDuplicateReferenceCheck)
+
+				return &importv1.ParseTransactionsResponse{
+					Transactions: []*importv1.ParseTransactionsResponse_ParsedTransaction{
+						{
+							Transaction: &gomoneypbv1.Transaction{
+								Type:         gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+								Title:        "Coffee",
+								SourceAmount: "-3.50",
+							},
+						},
+						{
+							DuplicateTransactionId: lo.ToPtr(int64(41)),
+							Transaction: &gomoneypbv1.Transaction{
+								Type:  gomoneypbv1.TransactionType_TRANSACTION_TYPE_INCOME,
+								Title: "Salary",
+							},
+						},
+						{
+							Transaction: &gomoneypbv1.Transaction{Title: "raw row", Notes: "TOPUP;;"},
+						},
+					},
+				}, nil
+			})
+
+		result := callTool(t, newImportTestServer(t, importSvc), "parse_import", map[string]any{
+			"source":                         "IMPORT_SOURCE_REVOLUT",
+			"files":                          []any{"Type,Product\n"},
+			"encoding":                       "text",
+			"skip_duplicate_reference_check": true,
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"total": 3`)
+		assert.Contains(t, text, `"duplicates": 1`)
+		assert.Contains(t, text, `"unparsed": 1`)
+		assert.Contains(t, text, `"duplicate_transaction_id": 41`)
+		assert.Contains(t, text, `"type": "unparsed"`)
+		assert.Contains(t, text, `"source_amount": "-3.50"`)
+	})
+
+	t.Run("suggestions for new rows", func(t *testing.T) {
+		importSvc := NewMockImportService(gomock.NewController(t))
+		importSvc.EXPECT().Parse(gomock.Any(), gomock.Any()).
+			Return(&importv1.ParseTransactionsResponse{
+				Transactions: []*importv1.ParseTransactionsResponse_ParsedTransaction{
+					{
+						Transaction: &gomoneypbv1.Transaction{
+							Type:                 gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+							Title:                "LIDL Warszawa",
+							DestinationAccountId: 50,
+							DestinationAmount:    "42.10",
+						},
+					},
+					{
+						Transaction: &gomoneypbv1.Transaction{
+							Type:       gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+							Title:      "already categorized",
+							CategoryId: lo.ToPtr(int32(1)),
+						},
+					},
+					{
+						DuplicateTransactionId: lo.ToPtr(int64(41)),
+						Transaction:            &gomoneypbv1.Transaction{Type: gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE},
+					},
+					{
+						Transaction: &gomoneypbv1.Transaction{Title: "raw row"},
+					},
+				},
+			}, nil)
+
+		suggestionSvc := NewMockSuggestionsService(gomock.NewController(t))
+		suggestionSvc.EXPECT().Suggest(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, inputs []*suggestions.Input) ([]*suggestions.Suggestion, error) {
+				assert.Len(t, inputs, 1)
+				assert.Equal(t, "LIDL Warszawa", inputs[0].Title)
+				assert.EqualValues(t, 50, inputs[0].DestinationAccountID)
+				assert.Equal(t, "42.1", inputs[0].Amount.String())
+
+				return []*suggestions.Suggestion{{
+					Categories: []*suggestions.Candidate{{ID: 3, Confidence: 0.91}},
+					Tags:       []*suggestions.Candidate{{ID: 7, Confidence: 0.4}},
+				}}, nil
+			})
+
+		result := callTool(t, newSuggestionsTestServer(t, suggestionSvc, importSvc), "parse_import", map[string]any{
+			"source": "IMPORT_SOURCE_MBANK",
+			"files":  []any{"eA=="},
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"suggested_categories": [`)
+		assert.Contains(t, text, `"confidence": 0.91`)
+		assert.Equal(t, 1, strings.Count(text, "suggested_categories"))
+		assert.Equal(t, 1, strings.Count(text, "suggested_tags"))
+	})
+
+	t.Run("suggestions error", func(t *testing.T) {
+		importSvc := NewMockImportService(gomock.NewController(t))
+		importSvc.EXPECT().Parse(gomock.Any(), gomock.Any()).
+			Return(&importv1.ParseTransactionsResponse{
+				Transactions: []*importv1.ParseTransactionsResponse_ParsedTransaction{
+					{Transaction: &gomoneypbv1.Transaction{Type: gomoneypbv1.TransactionType_TRANSACTION_TYPE_INCOME}},
+				},
+			}, nil)
+
+		suggestionSvc := NewMockSuggestionsService(gomock.NewController(t))
+		suggestionSvc.EXPECT().Suggest(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newSuggestionsTestServer(t, suggestionSvc, importSvc), "parse_import", map[string]any{
+			"source": "IMPORT_SOURCE_MBANK",
+			"files":  []any{"eA=="},
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to suggest categories")
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			args     map[string]any
+			expected string
+		}{
+			"source":   {args: map[string]any{"source": "revolut", "files": []any{"eA=="}}, expected: "source must be an ImportSource name"},
+			"files":    {args: map[string]any{"source": "IMPORT_SOURCE_REVOLUT"}, expected: "files parameter is required"},
+			"empty":    {args: map[string]any{"source": "IMPORT_SOURCE_REVOLUT", "files": []any{""}}, expected: "files[0] must be a non empty string"},
+			"base64":   {args: map[string]any{"source": "IMPORT_SOURCE_REVOLUT", "files": []any{"not base64!"}}, expected: "files[0] is not valid base64"},
+			"encoding": {args: map[string]any{"source": "IMPORT_SOURCE_REVOLUT", "files": []any{"eA=="}, "encoding": "hex"}, expected: "encoding must be base64 or text"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				result := callTool(t, newImportTestServer(t, NewMockImportService(gomock.NewController(t))), "parse_import", c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		importSvc := NewMockImportService(gomock.NewController(t))
+		importSvc.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newImportTestServer(t, importSvc), "parse_import", map[string]any{
+			"source": "IMPORT_SOURCE_MBANK",
+			"files":  []any{"eA=="},
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to parse import")
+	})
+}
+
+func TestServer_HandleCommitImport(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		importSvc := NewMockImportService(gomock.NewController(t))
+		importSvc.EXPECT().Import(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *importv1.ImportTransactionsRequest) (*importv1.ImportTransactionsResponse, error) {
+				assert.Equal(t, importv1.ImportSource_IMPORT_SOURCE_BNP_PARIBAS_POLSKA, req.Source)
+				assert.Equal(t, []string{"eA=="}, req.Content)
+				assert.True(t, req.SkipValidationErrors)
+				assert.False(t, req.TreatDatesAsUtc)
+
+				return &importv1.ImportTransactionsResponse{ImportedCount: 10, DuplicateCount: 2, SkippedCount: 1}, nil
+			})
+
+		result := callTool(t, newImportTestServer(t, importSvc), "commit_import", map[string]any{
+			"source":                 "IMPORT_SOURCE_BNP_PARIBAS_POLSKA",
+			"files":                  []any{"eA=="},
+			"skip_validation_errors": true,
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"imported_count": 10`)
+		assert.Contains(t, text, `"duplicate_count": 2`)
+		assert.Contains(t, text, `"skipped_count": 1`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		importSvc := NewMockImportService(gomock.NewController(t))
+		importSvc.EXPECT().Import(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newImportTestServer(t, importSvc), "commit_import", map[string]any{
+			"source": "IMPORT_SOURCE_FIREFLY",
+			"files":  []any{"eA=="},
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to import")
+	})
+}
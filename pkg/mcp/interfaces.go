@@ -2,19 +2,32 @@ package mcp
 
 import (
 	"context"
+	"time"
 
+	analyticsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/analytics/v1"
 	categoriesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/categories/v1"
+	importv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/import/v1"
 	rulesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/rules/v1"
 	tagsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/tags/v1"
 	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
+	"github.com/ft-t/go-money/pkg/accounts"
+	"github.com/ft-t/go-money/pkg/analytics"
 	"github.com/ft-t/go-money/pkg/currency"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/investments"
+	"github.com/ft-t/go-money/pkg/mcp/audit"
+	"github.com/ft-t/go-money/pkg/suggestions"
 	"github.com/ft-t/go-money/pkg/transactions"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
+	"github.com/ft-t/go-money/pkg/webhooks"
 	"github.com/shopspring/decimal"
 )
 
 //go:generate mockgen -destination interfaces_mocks_test.go -package mcp_test -source=interfaces.go
 
 type CategoryService interface {
+	GetAllCategories(ctx context.Context) ([]*database.Category, error)
 	CreateCategory(ctx context.Context, req *categoriesv1.CreateCategoryRequest) (*categoriesv1.CreateCategoryResponse, error)
 	UpdateCategory(ctx context.Context, req *categoriesv1.UpdateCategoryRequest) (*categoriesv1.UpdateCategoryResponse, error)
 }
@@ -23,6 +36,57 @@ type RulesService interface {
 	ListRules(ctx context.Context, req *rulesv1.ListRulesRequest) (*rulesv1.ListRulesResponse, error)
 	CreateRule(ctx context.Context, req *rulesv1.CreateRuleRequest) (*rulesv1.CreateRuleResponse, error)
 	UpdateRule(ctx context.Context, req *rulesv1.UpdateRuleRequest) (*rulesv1.UpdateRuleResponse, error)
+	ListTestCases(ctx context.Context, ruleID int32) ([]*database.RuleTestCase, error)
+	SetTestCases(ctx context.Context, ruleID int32, testCases []*database.RuleTestCase) ([]*rules.TestCaseResult, error)
+	RunTests(ctx context.Context, ruleIDs []int32) ([]*rules.RuleTestReport, error)
+	SetTriggers(ctx context.Context, ruleID int32, events []database.RuleTriggerEvent, importSources []importv1.ImportSource) (*database.Rule, error)
+	ListRevisions(ctx context.Context, ruleID int32) ([]*database.RuleRevision, error)
+	DiffRevisions(ctx context.Context, fromID int64, toID int64) (*rules.RevisionDiff, error)
+	RestoreRevision(ctx context.Context, revisionID int64) (*database.Rule, error)
+}
+
+type RuleModulesService interface {
+	ListModules(ctx context.Context) ([]*database.LuaModule, error)
+	CreateModule(ctx context.Context, module *database.LuaModule) (*database.LuaModule, error)
+	UpdateModule(ctx context.Context, module *database.LuaModule) (*database.LuaModule, error)
+	DeleteModule(ctx context.Context, id int32) (*database.LuaModule, error)
+}
+
+type ScheduleRulesService interface {
+	RunNow(ctx context.Context, ruleID int32, effectiveDate time.Time) (*database.ScheduleRuleRun, error)
+	ListRuns(ctx context.Context, ruleID int32, limit int) ([]*database.ScheduleRuleRun, error)
+}
+
+type JobsService interface {
+	ListJobs(ctx context.Context) ([]*database.JobStatus, error)
+	RunJob(ctx context.Context, name string) (*database.JobRun, error)
+}
+
+type AccountsService interface {
+	GetAllAccounts(ctx context.Context) ([]*database.Account, error)
+	SetTimezone(ctx context.Context, accountID int32, timezone string) (*database.Account, error)
+}
+
+type LoansService interface {
+	SetLoan(ctx context.Context, req *accounts.SetLoanRequest) (*database.Loan, error)
+	GetAmortizationSchedule(ctx context.Context, accountID int32) ([]*accounts.AmortizationEntry, error)
+	GetLoanStatus(ctx context.Context, accountID int32, monthlyPayment decimal.Decimal) (*accounts.LoanStatus, error)
+}
+
+type InvestmentsService interface {
+	CreateSecurity(ctx context.Context, req *investments.CreateSecurityRequest) (*database.Security, error)
+	SetInvestmentAccount(ctx context.Context, req *investments.SetInvestmentAccountRequest) (*database.InvestmentAccount, error)
+	RecordTrade(ctx context.Context, req *investments.RecordTradeRequest) (*database.InvestmentTrade, error)
+	DeleteTrade(ctx context.Context, id int64) (*database.InvestmentTrade, error)
+	GetHoldings(ctx context.Context, accountID int32) (*investments.Portfolio, error)
+	ImportPrices(ctx context.Context, quotes []*investments.PriceQuote) (int, error)
+}
+
+type AnalyticsService interface {
+	GetNetWorth(ctx context.Context, date time.Time) (*analytics.NetWorth, error)
+	GetFxGainLoss(ctx context.Context, req *analytics.FxGainLossRequest) (*analytics.FxGainLoss, error)
+	GetBalanceHistory(ctx context.Context, req *analytics.BalanceHistoryRequest) ([]*analytics.BalancePoint, error)
+	GetDebitsAndCreditsSummary(ctx context.Context, req *analyticsv1.GetDebitsAndCreditsSummaryRequest) (*analyticsv1.GetDebitsAndCreditsSummaryResponse, error)
 }
 
 type DryRunService interface {
@@ -37,10 +101,57 @@ type TagsService interface {
 }
 
 type TransactionService interface {
+	ListPage(ctx context.Context, req *transactions.ListRequest) (*transactions.ListResponse, error)
 	Create(ctx context.Context, req *transactionsv1.CreateTransactionRequest) (*transactionsv1.CreateTransactionResponse, error)
 	Update(ctx context.Context, req *transactionsv1.UpdateTransactionRequest) (*transactionsv1.UpdateTransactionResponse, error)
 	BulkSetCategory(ctx context.Context, assignments []transactions.CategoryAssignment) error
 	BulkSetTags(ctx context.Context, assignments []transactions.TagsAssignment) error
+	SetExchangeRates(ctx context.Context, assignments []transactions.ExchangeRateAssignment) ([]*database.Transaction, error)
+}
+
+type ImportService interface {
+	Parse(ctx context.Context, req *importv1.ParseTransactionsRequest) (*importv1.ParseTransactionsResponse, error)
+	Import(ctx context.Context, req *importv1.ImportTransactionsRequest) (*importv1.ImportTransactionsResponse, error)
+}
+
+type TransactionHistoryService interface {
+	List(ctx context.Context, transactionID int64) ([]*database.TransactionHistory, error)
+}
+
+type RateOverridesService interface {
+	ListOverrides(ctx context.Context, currencyID string) ([]*database.CurrencyRateOverride, error)
+	CreateOverride(ctx context.Context, req *currency.CreateRateOverrideRequest) (*database.CurrencyRateOverride, error)
+	DeleteOverride(ctx context.Context, id int32) (*database.CurrencyRateOverride, error)
+}
+
+type CurrencyCatalogService interface {
+	ListCurrencies(ctx context.Context, includeInactive bool) ([]*database.Currency, error)
+	UpsertCurrency(ctx context.Context, req *currency.UpsertCurrencyRequest) (*database.Currency, error)
+	SetCustomRate(ctx context.Context, req *currency.SetCustomRateRequest) (*database.Currency, error)
+}
+
+type AuditService interface {
+	Record(ctx context.Context, call *database.McpToolCall) error
+	List(ctx context.Context, req *audit.ListRequest) ([]*database.McpToolCall, error)
+}
+
+type SuggestionsService interface {
+	Suggest(ctx context.Context, inputs []*suggestions.Input) ([]*suggestions.Suggestion, error)
+	SuggestForTransactions(ctx context.Context, ids []int64) ([]*suggestions.TransactionSuggestion, error)
+}
+
+type EventFeedService interface {
+	Wait(ctx context.Context, req *history.FeedRequest, timeout time.Duration) ([]*database.TransactionHistory, error)
+}
+
+type WebhooksService interface {
+	ListWebhooks(ctx context.Context) ([]*database.Webhook, error)
+	CreateWebhook(ctx context.Context, req *webhooks.CreateWebhookRequest) (*database.Webhook, error)
+	UpdateWebhook(ctx context.Context, req *webhooks.UpdateWebhookRequest) (*database.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int32) (*database.Webhook, error)
+	Ping(ctx context.Context, id int32) (*database.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, req *webhooks.ListDeliveriesRequest) ([]*database.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, id int64) (*database.WebhookDelivery, error)
 }
 
 type CurrencyConverterService interface {
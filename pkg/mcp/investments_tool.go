@@ -0,0 +1,438 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/analytics"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/investments"
+	"github.com/ft-t/go-money/pkg/timezone"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+var securityKinds = map[string]database.SecurityKind{
+	"stock":     database.SecurityKindStock,
+	"etf":       database.SecurityKindETF,
+	"fund":      database.SecurityKindFund,
+	"bond":      database.SecurityKindBond,
+	"crypto":    database.SecurityKindCrypto,
+	"commodity": database.SecurityKindCommodity,
+}
+
+var costMethods = map[string]database.CostMethod{
+	"fifo":    database.CostMethodFIFO,
+	"average": database.CostMethodAverage,
+}
+
+var tradeKinds = map[string]database.TradeKind{
+	"buy":      database.TradeKindBuy,
+	"sell":     database.TradeKindSell,
+	"dividend": database.TradeKindDividend,
+	"split":    database.TradeKindSplit,
+}
+
+type securityOutput struct {
+	ID               int32  `json:"id"`
+	Symbol           string `json:"symbol"`
+	Name             string `json:"name,omitempty"`
+	Kind             string `json:"kind"`
+	Currency         string `json:"currency"`
+	QuantityDecimals int32  `json:"quantity_decimals"`
+}
+
+type investmentAccountOutput struct {
+	AccountID         int32  `json:"account_id"`
+	CostMethod        string `json:"cost_method"`
+	CashAccountID     *int32 `json:"cash_account_id,omitempty"`
+	DividendAccountID *int32 `json:"dividend_account_id,omitempty"`
+}
+
+type tradeOutput struct {
+	ID            int64  `json:"id"`
+	AccountID     int32  `json:"account_id"`
+	SecurityID    int32  `json:"security_id"`
+	Kind          string `json:"kind"`
+	TradeDate     string `json:"trade_date"`
+	Quantity      string `json:"quantity"`
+	Price         string `json:"price"`
+	Fee           string `json:"fee"`
+	Amount        string `json:"amount"`
+	TransactionID *int64 `json:"transaction_id,omitempty"`
+	Note          string `json:"note,omitempty"`
+}
+
+type lotOutput struct {
+	TradeID  int64  `json:"trade_id"`
+	Date     string `json:"date"`
+	Quantity string `json:"quantity"`
+	Cost     string `json:"cost"`
+}
+
+type holdingOutput struct {
+	Security       *securityOutput `json:"security"`
+	Quantity       string          `json:"quantity"`
+	CostBasis      string          `json:"cost_basis"`
+	Price          string          `json:"price"`
+	MarketValue    string          `json:"market_value"`
+	UnrealizedGain string          `json:"unrealized_gain"`
+	RealizedGain   string          `json:"realized_gain"`
+	Dividends      string          `json:"dividends"`
+	Lots           []*lotOutput    `json:"lots"`
+}
+
+type portfolioOutput struct {
+	Account        *investmentAccountOutput `json:"account"`
+	Currency       string                   `json:"currency"`
+	Holdings       []*holdingOutput         `json:"holdings"`
+	CostBasis      string                   `json:"cost_basis"`
+	MarketValue    string                   `json:"market_value"`
+	UnrealizedGain string                   `json:"unrealized_gain"`
+	RealizedGain   string                   `json:"realized_gain"`
+	Dividends      string                   `json:"dividends"`
+}
+
+type accountValuationOutput struct {
+	AccountID           int32  `json:"account_id"`
+	Currency            string `json:"currency"`
+	Balance             string `json:"balance"`
+	HoldingsValue       string `json:"holdings_value,omitempty"`
+	Value               string `json:"value"`
+	ValueInBaseCurrency string `json:"value_in_base_currency"`
+}
+
+type netWorthOutput struct {
+	Date        string                    `json:"date"`
+	Assets      string                    `json:"assets"`
+	Liabilities string                    `json:"liabilities"`
+	Holdings    string                    `json:"holdings"`
+	NetWorth    string                    `json:"net_worth"`
+	Accounts    []*accountValuationOutput `json:"accounts"`
+}
+
+func mapSecurity(sec *database.Security) *securityOutput {
+	return &securityOutput{
+		ID:               sec.ID,
+		Symbol:           sec.Symbol,
+		Name:             sec.Name,
+		Kind:             lo.Invert(securityKinds)[sec.Kind],
+		Currency:         sec.Currency,
+		QuantityDecimals: sec.QuantityDecimals,
+	}
+}
+
+func mapInvestmentAccount(acc *database.InvestmentAccount) *investmentAccountOutput {
+	return &investmentAccountOutput{
+		AccountID:         acc.AccountID,
+		CostMethod:        lo.Invert(costMethods)[acc.CostMethod],
+		CashAccountID:     acc.CashAccountID,
+		DividendAccountID: acc.DividendAccountID,
+	}
+}
+
+func mapTrade(trade *database.InvestmentTrade) *tradeOutput {
+	return &tradeOutput{
+		ID:            trade.ID,
+		AccountID:     trade.AccountID,
+		SecurityID:    trade.SecurityID,
+		Kind:          lo.Invert(tradeKinds)[trade.Kind],
+		TradeDate:     trade.TradeDate.Format(time.DateOnly),
+		Quantity:      trade.Quantity.String(),
+		Price:         trade.Price.String(),
+		Fee:           trade.Fee.String(),
+		Amount:        trade.Amount.String(),
+		TransactionID: trade.TransactionID,
+		Note:          trade.Note,
+	}
+}
+
+func parseOptionalDecimal(args map[string]any, name string) (decimal.Decimal, error) {
+	val, _ := args[name].(string)
+	if val == "" {
+		return decimal.Zero, nil
+	}
+
+	parsed, err := decimal.NewFromString(val)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return parsed, nil
+}
+
+func toolJSONResult(v any) (*mcp.CallToolResult, error) {
+	result, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleCreateSecurity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	kind, _ := args["kind"].(string)
+	securityKind, ok := securityKinds[strings.ToLower(kind)]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("unsupported kind: %s", kind)), nil
+	}
+
+	req := &investments.CreateSecurityRequest{
+		Kind: securityKind,
+	}
+	req.Symbol, _ = args["symbol"].(string)
+	req.Name, _ = args["name"].(string)
+	req.Currency, _ = args["currency"].(string)
+
+	if val, isNum := args["quantity_decimals"].(float64); isNum {
+		req.QuantityDecimals = int32(val)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	security, err := s.cfg.InvestmentSvc.CreateSecurity(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create security: %v", err)), nil
+	}
+
+	return toolJSONResult(mapSecurity(security))
+}
+
+func (s *Server) handleSetInvestmentAccount(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	accountID, ok := args["account_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("account_id parameter is required"), nil
+	}
+
+	req := &investments.SetInvestmentAccountRequest{
+		AccountID:  int32(accountID),
+		CostMethod: database.CostMethodFIFO,
+	}
+
+	if method, _ := args["cost_method"].(string); method != "" {
+		if req.CostMethod, ok = costMethods[method]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported cost_method: %s", method)), nil
+		}
+	}
+
+	if val, isNum := args["cash_account_id"].(float64); isNum {
+		req.CashAccountID = lo.ToPtr(int32(val))
+	}
+
+	if val, isNum := args["dividend_account_id"].(float64); isNum {
+		req.DividendAccountID = lo.ToPtr(int32(val))
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	account, err := s.cfg.InvestmentSvc.SetInvestmentAccount(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to set investment account: %v", err)), nil
+	}
+
+	return toolJSONResult(mapInvestmentAccount(account))
+}
+
+func (s *Server) handleRecordTrade(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	accountID, ok := args["account_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("account_id parameter is required"), nil
+	}
+
+	securityID, ok := args["security_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("security_id parameter is required"), nil
+	}
+
+	kind, _ := args["kind"].(string)
+	tradeKind, ok := tradeKinds[strings.ToLower(kind)]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("unsupported kind: %s", kind)), nil
+	}
+
+	req := &investments.RecordTradeRequest{
+		AccountID:  int32(accountID),
+		SecurityID: int32(securityID),
+		Kind:       tradeKind,
+	}
+
+	var err error
+	tradeDate, _ := args["trade_date"].(string)
+	if req.TradeDate, err = time.Parse(time.DateOnly, tradeDate); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid trade_date: %v", err)), nil
+	}
+
+	for name, target := range map[string]*decimal.Decimal{
+		"quantity": &req.Quantity,
+		"price":    &req.Price,
+		"fee":      &req.Fee,
+		"amount":   &req.Amount,
+	} {
+		if *target, err = parseOptionalDecimal(args, name); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	req.Note, _ = args["note"].(string)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	trade, err := s.cfg.InvestmentSvc.RecordTrade(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to record trade: %v", err)), nil
+	}
+
+	return toolJSONResult(mapTrade(trade))
+}
+
+func (s *Server) handleDeleteTrade(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	trade, err := s.cfg.InvestmentSvc.DeleteTrade(queryCtx, int64(id))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to delete trade: %v", err)), nil
+	}
+
+	return toolJSONResult(mapTrade(trade))
+}
+
+func (s *Server) handleGetHoldings(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	accountID, ok := request.GetArguments()["account_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("account_id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	portfolio, err := s.cfg.InvestmentSvc.GetHoldings(queryCtx, int32(accountID))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get holdings: %v", err)), nil
+	}
+
+	return toolJSONResult(&portfolioOutput{
+		Account:  mapInvestmentAccount(portfolio.Account),
+		Currency: portfolio.Currency,
+		Holdings: lo.Map(portfolio.Holdings, func(h *investments.Holding, _ int) *holdingOutput {
+			return &holdingOutput{
+				Security:       mapSecurity(h.Security),
+				Quantity:       h.Quantity.String(),
+				CostBasis:      h.CostBasis.String(),
+				Price:          h.Price.String(),
+				MarketValue:    h.MarketValue.String(),
+				UnrealizedGain: h.UnrealizedGain.String(),
+				RealizedGain:   h.RealizedGain.String(),
+				Dividends:      h.Dividends.String(),
+				Lots: lo.Map(h.Lots, func(l *investments.Lot, _ int) *lotOutput {
+					return &lotOutput{
+						TradeID:  l.TradeID,
+						Date:     l.Date.Format(time.DateOnly),
+						Quantity: l.Quantity.String(),
+						Cost:     l.Cost.String(),
+					}
+				}),
+			}
+		}),
+		CostBasis:      portfolio.CostBasis.String(),
+		MarketValue:    portfolio.MarketValue.String(),
+		UnrealizedGain: portfolio.UnrealizedGain.String(),
+		RealizedGain:   portfolio.RealizedGain.String(),
+		Dividends:      portfolio.Dividends.String(),
+	})
+}
+
+func (s *Server) handleImportSecurityPrices(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	content, _ := request.GetArguments()["csv"].(string)
+	if strings.TrimSpace(content) == "" {
+		return mcp.NewToolResultError("csv parameter is required"), nil
+	}
+
+	quotes, err := investments.ParsePricesCSV(strings.NewReader(content))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid csv: %v", err)), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	imported, err := s.cfg.InvestmentSvc.ImportPrices(queryCtx, quotes)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to import prices: %v", err)), nil
+	}
+
+	return toolJSONResult(map[string]int{"imported": imported})
+}
+
+func (s *Server) handleGetNetWorth(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	date := timezone.Today(s.cfg.Location)
+
+	if val, _ := request.GetArguments()["date"].(string); val != "" {
+		parsed, err := time.Parse(time.DateOnly, val)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid date: %v", err)), nil
+		}
+
+		date = parsed
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	_, endOfDay := dayRange(date, date, s.cfg.Location)
+
+	netWorth, err := s.cfg.AnalyticsSvc.GetNetWorth(queryCtx, endOfDay)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get net worth: %v", err)), nil
+	}
+
+	return toolJSONResult(&netWorthOutput{
+		Date:        netWorth.Date.Format(time.DateOnly),
+		Assets:      netWorth.Assets.String(),
+		Liabilities: netWorth.Liabilities.String(),
+		Holdings:    netWorth.Holdings.String(),
+		NetWorth:    netWorth.NetWorth.String(),
+		Accounts: lo.Map(netWorth.Accounts, func(a *analytics.AccountValuation, _ int) *accountValuationOutput {
+			return &accountValuationOutput{
+				AccountID:           a.AccountID,
+				Currency:            a.Currency,
+				Balance:             a.Balance.String(),
+				HoldingsValue:       nullDecimalString(a.HoldingsValue),
+				Value:               a.Value.String(),
+				ValueInBaseCurrency: a.ValueInBaseCurrency.String(),
+			}
+		}),
+	})
+}
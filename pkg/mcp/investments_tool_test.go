@@ -0,0 +1,295 @@
+package mcp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/analytics"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/investments"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+func newInvestmentsTestServer(
+	t *testing.T,
+	investmentSvc *MockInvestmentsService,
+	analyticsSvc *MockAnalyticsService,
+) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:            gormDB,
+		Docs:          "test docs",
+		InvestmentSvc: investmentSvc,
+		AnalyticsSvc:  analyticsSvc,
+	})
+}
+
+func TestServer_HandleCreateSecurity(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		investmentSvc := NewMockInvestmentsService(gomock.NewController(t))
+		investmentSvc.EXPECT().CreateSecurity(gomock.Any(), &investments.CreateSecurityRequest{
+			Symbol:           "BTC",
+			Name:             "Bitcoin",
+			Kind:             database.SecurityKindCrypto,
+			Currency:         "USD",
+			QuantityDecimals: 8,
+		}).Return(&database.Security{
+			ID:               1,
+			Symbol:           "BTC",
+			Kind:             database.SecurityKindCrypto,
+			Currency:         "USD",
+			QuantityDecimals: 8,
+		}, nil)
+
+		result := callTool(t, newInvestmentsTestServer(t, investmentSvc, nil), "create_security", map[string]any{
+			"symbol":            "BTC",
+			"name":              "Bitcoin",
+			"kind":              "crypto",
+			"currency":          "USD",
+			"quantity_decimals": float64(8),
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"kind": "crypto"`)
+	})
+
+	t.Run("invalid kind", func(t *testing.T) {
+		result := callTool(t, newInvestmentsTestServer(t, NewMockInvestmentsService(gomock.NewController(t)), nil),
+			"create_security", map[string]any{
+				"symbol": "X",
+				"kind":   "option",
+			})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "unsupported kind")
+	})
+}
+
+func TestServer_HandleSetInvestmentAccount(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cashID := int32(4)
+
+		investmentSvc := NewMockInvestmentsService(gomock.NewController(t))
+		investmentSvc.EXPECT().SetInvestmentAccount(gomock.Any(), &investments.SetInvestmentAccountRequest{
+			AccountID:     3,
+			CostMethod:    database.CostMethodAverage,
+			CashAccountID: &cashID,
+		}).Return(&database.InvestmentAccount{
+			AccountID:     3,
+			CostMethod:    database.CostMethodAverage,
+			CashAccountID: &cashID,
+		}, nil)
+
+		result := callTool(t, newInvestmentsTestServer(t, investmentSvc, nil), "set_investment_account", map[string]any{
+			"account_id":      float64(3),
+			"cost_method":     "average",
+			"cash_account_id": float64(4),
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"cost_method": "average"`)
+		assert.Contains(t, text, `"cash_account_id": 4`)
+	})
+
+	t.Run("invalid cost method", func(t *testing.T) {
+		result := callTool(t, newInvestmentsTestServer(t, NewMockInvestmentsService(gomock.NewController(t)), nil),
+			"set_investment_account", map[string]any{
+				"account_id":  float64(3),
+				"cost_method": "lifo",
+			})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "unsupported cost_method")
+	})
+}
+
+func TestServer_HandleRecordTrade(t *testing.T) {
+	tradeDate := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		investmentSvc := NewMockInvestmentsService(gomock.NewController(t))
+		investmentSvc.EXPECT().RecordTrade(gomock.Any(), &investments.RecordTradeRequest{
+			AccountID:  3,
+			SecurityID: 1,
+			Kind:       database.TradeKindBuy,
+			TradeDate:  tradeDate,
+			Quantity:   decimal.RequireFromString("10"),
+			Price:      decimal.RequireFromString("100.5"),
+			Fee:        decimal.RequireFromString("1"),
+			Amount:     decimal.Zero,
+		}).Return(&database.InvestmentTrade{
+			ID:         7,
+			AccountID:  3,
+			SecurityID: 1,
+			Kind:       database.TradeKindBuy,
+			TradeDate:  tradeDate,
+			Quantity:   decimal.RequireFromString("10"),
+			Price:      decimal.RequireFromString("100.5"),
+			Fee:        decimal.RequireFromString("1"),
+			Amount:     decimal.RequireFromString("1006"),
+		}, nil)
+
+		result := callTool(t, newInvestmentsTestServer(t, investmentSvc, nil), "record_trade", map[string]any{
+			"account_id":  float64(3),
+			"security_id": float64(1),
+			"kind":        "buy",
+			"trade_date":  "2026-01-05",
+			"quantity":    "10",
+			"price":       "100.5",
+			"fee":         "1",
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"amount": "1006"`)
+		assert.Contains(t, text, `"trade_date": "2026-01-05"`)
+	})
+
+	t.Run("invalid quantity", func(t *testing.T) {
+		result := callTool(t, newInvestmentsTestServer(t, NewMockInvestmentsService(gomock.NewController(t)), nil),
+			"record_trade", map[string]any{
+				"account_id":  float64(3),
+				"security_id": float64(1),
+				"kind":        "buy",
+				"trade_date":  "2026-01-05",
+				"quantity":    "ten",
+			})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid quantity")
+	})
+
+	t.Run("invalid trade date", func(t *testing.T) {
+		result := callTool(t, newInvestmentsTestServer(t, NewMockInvestmentsService(gomock.NewController(t)), nil),
+			"record_trade", map[string]any{
+				"account_id":  float64(3),
+				"security_id": float64(1),
+				"kind":        "sell",
+				"trade_date":  "05.01.2026",
+			})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid trade_date")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		investmentSvc := NewMockInvestmentsService(gomock.NewController(t))
+		investmentSvc.EXPECT().RecordTrade(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("only 0 held"))
+
+		result := callTool(t, newInvestmentsTestServer(t, investmentSvc, nil), "record_trade", map[string]any{
+			"account_id":  float64(3),
+			"security_id": float64(1),
+			"kind":        "sell",
+			"trade_date":  "2026-01-05",
+			"quantity":    "1",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to record trade: only 0 held")
+	})
+}
+
+func TestServer_HandleGetHoldings(t *testing.T) {
+	investmentSvc := NewMockInvestmentsService(gomock.NewController(t))
+	investmentSvc.EXPECT().GetHoldings(gomock.Any(), int32(3)).Return(&investments.Portfolio{
+		Account:  &database.InvestmentAccount{AccountID: 3, CostMethod: database.CostMethodFIFO},
+		Currency: "USD",
+		Holdings: []*investments.Holding{
+			{
+				Security:    &database.Security{ID: 1, Symbol: "AAPL", Kind: database.SecurityKindStock},
+				Quantity:    decimal.RequireFromString("5"),
+				CostBasis:   decimal.RequireFromString("600"),
+				MarketValue: decimal.RequireFromString("650"),
+				Lots: []*investments.Lot{
+					{TradeID: 2, Date: time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC), Quantity: decimal.RequireFromString("5")},
+				},
+			},
+		},
+		MarketValue: decimal.RequireFromString("650"),
+	}, nil)
+
+	result := callTool(t, newInvestmentsTestServer(t, investmentSvc, nil), "get_holdings", map[string]any{
+		"account_id": float64(3),
+	})
+
+	assert.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"symbol": "AAPL"`)
+	assert.Contains(t, text, `"market_value": "650"`)
+	assert.Contains(t, text, `"date": "2026-01-06"`)
+}
+
+func TestServer_HandleImportSecurityPrices(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		investmentSvc := NewMockInvestmentsService(gomock.NewController(t))
+		investmentSvc.EXPECT().ImportPrices(gomock.Any(), []*investments.PriceQuote{
+			{Symbol: "AAPL", Date: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), Price: decimal.RequireFromString("180.25")},
+		}).Return(1, nil)
+
+		result := callTool(t, newInvestmentsTestServer(t, investmentSvc, nil), "import_security_prices", map[string]any{
+			"csv": "symbol,date,price\nAAPL,2026-01-05,180.25\n",
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"imported": 1`)
+	})
+
+	t.Run("invalid csv", func(t *testing.T) {
+		result := callTool(t, newInvestmentsTestServer(t, NewMockInvestmentsService(gomock.NewController(t)), nil),
+			"import_security_prices", map[string]any{
+				"csv": "AAPL,yesterday,1",
+			})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid csv")
+	})
+}
+
+func TestServer_HandleGetNetWorth(t *testing.T) {
+	date := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	analyticsSvc := NewMockAnalyticsService(gomock.NewController(t))
+	analyticsSvc.EXPECT().GetNetWorth(gomock.Any(), date.AddDate(0, 0, 1).Add(-time.Nanosecond)).Return(&analytics.NetWorth{
+		Date:        date,
+		Assets:      decimal.RequireFromString("1650"),
+		Liabilities: decimal.RequireFromString("200"),
+		Holdings:    decimal.RequireFromString("650"),
+		NetWorth:    decimal.RequireFromString("1450"),
+		Accounts: []*analytics.AccountValuation{
+			{
+				AccountID:           3,
+				Currency:            "USD",
+				Balance:             decimal.RequireFromString("551"),
+				HoldingsValue:       decimal.NewNullDecimal(decimal.RequireFromString("650")),
+				Value:               decimal.RequireFromString("650"),
+				ValueInBaseCurrency: decimal.RequireFromString("650"),
+			},
+		},
+	}, nil)
+
+	result := callTool(t, newInvestmentsTestServer(t, nil, analyticsSvc), "get_net_worth", map[string]any{
+		"date": "2026-03-31",
+	})
+
+	assert.False(t, result.IsError)
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, `"net_worth": "1450"`)
+	assert.Contains(t, text, `"holdings_value": "650"`)
+
+	result = callTool(t, newInvestmentsTestServer(t, nil, analyticsSvc), "get_net_worth", map[string]any{
+		"date": "march",
+	})
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid date")
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+)
+
+const jobRunTimeout = 5 * time.Minute // exchange rate sync and gap fix can outlive a query
+
+type jobRunOutput struct {
+	ID         int64      `json:"id"`
+	Job        string     `json:"job"`
+	Trigger    string     `json:"trigger"`
+	Status     string     `json:"status"`
+	Instance   string     `json:"instance"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      *string    `json:"error,omitempty"`
+}
+
+type jobStatusOutput struct {
+	Name      string        `json:"name"`
+	Schedule  string        `json:"schedule"`
+	NextRunAt *time.Time    `json:"next_run_at,omitempty"`
+	LastRun   *jobRunOutput `json:"last_run,omitempty"`
+}
+
+func mapJobRun(run *database.JobRun) *jobRunOutput {
+	if run == nil {
+		return nil
+	}
+
+	return &jobRunOutput{
+		ID:         run.ID,
+		Job:        run.Job,
+		Trigger:    scheduleRunTriggerNames[run.Trigger],
+		Status:     scheduleRunStatusNames[run.Status],
+		Instance:   run.Instance,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+		Error:      run.Error,
+	}
+}
+
+func (s *Server) handleListJobs(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	statuses, err := s.cfg.JobsSvc.ListJobs(queryCtx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list jobs: %v", err)), nil
+	}
+
+	return toolJSONResult(lo.Map(statuses, func(status *database.JobStatus, _ int) *jobStatusOutput {
+		return &jobStatusOutput{
+			Name:      status.Name,
+			Schedule:  status.Schedule,
+			NextRunAt: status.NextRunAt,
+			LastRun:   mapJobRun(status.LastRun),
+		}
+	}))
+}
+
+func (s *Server) handleRunJob(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, _ := request.GetArguments()["name"].(string)
+	if name == "" {
+		return mcp.NewToolResultError("name parameter is required"), nil
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, jobRunTimeout)
+	defer cancel()
+
+	runCtx = database.WithContext(runCtx, s.db)
+
+	run, err := s.cfg.JobsSvc.RunJob(runCtx, name)
+	if run == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to run job: %v", err)), nil
+	}
+
+	result, fmtErr := json.MarshalIndent(mapJobRun(run), "", "  ")
+	if fmtErr != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", fmtErr)), nil
+	}
+
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("job run failed: %v\n%s", err, result)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
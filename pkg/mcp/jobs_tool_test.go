@@ -0,0 +1,126 @@
+package mcp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+func newJobsTestServer(t *testing.T, jobsSvc *MockJobsService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:      gormDB,
+		Docs:    "test docs",
+		JobsSvc: jobsSvc,
+	})
+}
+
+func TestServer_HandleListJobs(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		nextRun := time.Date(2026, 7, 6, 0, 1, 0, 0, time.UTC)
+
+		jobsSvc := NewMockJobsService(gomock.NewController(t))
+		jobsSvc.EXPECT().ListJobs(gomock.Any()).Return([]*database.JobStatus{
+			{
+				Name:      "fix_daily_gap",
+				Schedule:  "1 0 * * *",
+				NextRunAt: &nextRun,
+				LastRun: &database.JobRun{
+					ID:       3,
+					Job:      "fix_daily_gap",
+					Trigger:  database.ScheduleRunTriggerCron,
+					Status:   database.ScheduleRunStatusFailed,
+					Instance: "go-money-0",
+					Error:    lo.ToPtr("boom"),
+				},
+			},
+			{
+				Name:     "update_exchange_rates",
+				Schedule: "10 12 * * *",
+			},
+		}, nil)
+
+		result := callTool(t, newJobsTestServer(t, jobsSvc), "list_jobs", map[string]any{})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"schedule": "1 0 * * *"`)
+		assert.Contains(t, text, `"next_run_at": "2026-07-06T00:01:00Z"`)
+		assert.Contains(t, text, `"trigger": "cron"`)
+		assert.Contains(t, text, `"status": "failed"`)
+		assert.Contains(t, text, `"instance": "go-money-0"`)
+		assert.Contains(t, text, `"name": "update_exchange_rates"`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		jobsSvc := NewMockJobsService(gomock.NewController(t))
+		jobsSvc.EXPECT().ListJobs(gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newJobsTestServer(t, jobsSvc), "list_jobs", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to list jobs")
+	})
+}
+
+func TestServer_HandleRunJob(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		jobsSvc := NewMockJobsService(gomock.NewController(t))
+		jobsSvc.EXPECT().RunJob(gomock.Any(), "update_exchange_rates").Return(&database.JobRun{
+			ID:      1,
+			Job:     "update_exchange_rates",
+			Trigger: database.ScheduleRunTriggerManual,
+			Status:  database.ScheduleRunStatusSuccess,
+		}, nil)
+
+		result := callTool(t, newJobsTestServer(t, jobsSvc), "run_job", map[string]any{"name": "update_exchange_rates"})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"trigger": "manual"`)
+		assert.Contains(t, text, `"status": "success"`)
+	})
+
+	t.Run("job failure returns recorded run", func(t *testing.T) {
+		jobsSvc := NewMockJobsService(gomock.NewController(t))
+		jobsSvc.EXPECT().RunJob(gomock.Any(), "fix_daily_gap").Return(&database.JobRun{
+			ID:     2,
+			Status: database.ScheduleRunStatusFailed,
+			Error:  lo.ToPtr("boom"),
+		}, assert.AnError)
+
+		result := callTool(t, newJobsTestServer(t, jobsSvc), "run_job", map[string]any{"name": "fix_daily_gap"})
+
+		assert.True(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, "job run failed")
+		assert.Contains(t, text, `"status": "failed"`)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		jobsSvc := NewMockJobsService(gomock.NewController(t))
+		jobsSvc.EXPECT().RunJob(gomock.Any(), "missing").Return(nil, assert.AnError)
+
+		result := callTool(t, newJobsTestServer(t, jobsSvc), "run_job", map[string]any{"name": "missing"})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to run job")
+	})
+
+	t.Run("name required", func(t *testing.T) {
+		result := callTool(t, newJobsTestServer(t, NewMockJobsService(gomock.NewController(t))), "run_job", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "name parameter is required")
+	})
+}
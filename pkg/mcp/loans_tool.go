@@ -0,0 +1,268 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/accounts"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+)
+
+var loanKinds = map[string]database.LoanKind{
+	"loan":        database.LoanKindLoan,
+	"credit_line": database.LoanKindCreditLine,
+}
+
+var loanCompoundings = map[string]database.LoanCompounding{
+	"monthly": database.LoanCompoundingMonthly,
+	"daily":   database.LoanCompoundingDaily,
+}
+
+type loanOutput struct {
+	ID                 int32      `json:"id"`
+	AccountID          int32      `json:"account_id"`
+	Kind               string     `json:"kind"`
+	Principal          string     `json:"principal"`
+	AnnualRate         string     `json:"annual_rate"`
+	TermMonths         int32      `json:"term_months"`
+	PaymentDay         int32      `json:"payment_day"`
+	Compounding        string     `json:"compounding"`
+	StartDate          time.Time  `json:"start_date"`
+	InterestAccountID  *int32     `json:"interest_account_id,omitempty"`
+	InterestCategoryID *int32     `json:"interest_category_id,omitempty"`
+	LastAccruedAt      *time.Time `json:"last_accrued_at,omitempty"`
+}
+
+type amortizationEntryOutput struct {
+	Number             int32     `json:"number"`
+	DueDate            time.Time `json:"due_date"`
+	Payment            string    `json:"payment"`
+	Interest           string    `json:"interest"`
+	Principal          string    `json:"principal"`
+	RemainingPrincipal string    `json:"remaining_principal"`
+}
+
+type loanRepaymentOutput struct {
+	TransactionID      int64     `json:"transaction_id"`
+	Date               time.Time `json:"date"`
+	Amount             string    `json:"amount"`
+	Interest           string    `json:"interest"`
+	Principal          string    `json:"principal"`
+	RemainingPrincipal string    `json:"remaining_principal"`
+}
+
+type payoffProjectionOutput struct {
+	MonthlyPayment string                     `json:"monthly_payment"`
+	PaymentsLeft   int32                      `json:"payments_left"`
+	PayoffDate     time.Time                  `json:"payoff_date"`
+	TotalInterest  string                     `json:"total_interest"`
+	Schedule       []*amortizationEntryOutput `json:"schedule"`
+}
+
+type loanStatusOutput struct {
+	Loan               *loanOutput             `json:"loan"`
+	Currency           string                  `json:"currency"`
+	RemainingPrincipal string                  `json:"remaining_principal"`
+	UnpaidInterest     string                  `json:"unpaid_interest"`
+	AccruedInterest    string                  `json:"accrued_interest"`
+	Repayments         []*loanRepaymentOutput  `json:"repayments"`
+	Projection         *payoffProjectionOutput `json:"projection,omitempty"`
+}
+
+func mapLoan(loan *database.Loan) *loanOutput {
+	return &loanOutput{
+		ID:                 loan.ID,
+		AccountID:          loan.AccountID,
+		Kind:               lo.Invert(loanKinds)[loan.Kind],
+		Principal:          loan.Principal.String(),
+		AnnualRate:         loan.AnnualRate.String(),
+		TermMonths:         loan.TermMonths,
+		PaymentDay:         loan.PaymentDay,
+		Compounding:        lo.Invert(loanCompoundings)[loan.Compounding],
+		StartDate:          loan.StartDate,
+		InterestAccountID:  loan.InterestAccountID,
+		InterestCategoryID: loan.InterestCategoryID,
+		LastAccruedAt:      loan.LastAccruedAt,
+	}
+}
+
+func mapAmortizationEntries(entries []*accounts.AmortizationEntry) []*amortizationEntryOutput {
+	return lo.Map(entries, func(entry *accounts.AmortizationEntry, _ int) *amortizationEntryOutput {
+		return &amortizationEntryOutput{
+			Number:             entry.Number,
+			DueDate:            entry.DueDate,
+			Payment:            entry.Payment.String(),
+			Interest:           entry.Interest.String(),
+			Principal:          entry.Principal.String(),
+			RemainingPrincipal: entry.RemainingPrincipal.String(),
+		}
+	})
+}
+
+func (s *Server) handleSetLoan(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	accountID, ok := args["account_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("account_id parameter is required"), nil
+	}
+
+	req := &accounts.SetLoanRequest{
+		AccountID:   int32(accountID),
+		Kind:        database.LoanKindLoan,
+		Compounding: database.LoanCompoundingMonthly,
+	}
+
+	if kind, _ := args["kind"].(string); kind != "" {
+		if req.Kind, ok = loanKinds[kind]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported kind: %s", kind)), nil
+		}
+	}
+
+	if compounding, _ := args["compounding"].(string); compounding != "" {
+		if req.Compounding, ok = loanCompoundings[compounding]; !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported compounding: %s", compounding)), nil
+		}
+	}
+
+	var err error
+	principal, _ := args["principal"].(string)
+	if req.Principal, err = decimal.NewFromString(principal); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid principal: %v", err)), nil
+	}
+
+	annualRate, _ := args["annual_rate"].(string)
+	if req.AnnualRate, err = decimal.NewFromString(annualRate); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid annual_rate: %v", err)), nil
+	}
+
+	startDate, _ := args["start_date"].(string)
+	if req.StartDate, err = time.Parse(time.RFC3339, startDate); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid start_date: %v", err)), nil
+	}
+
+	termMonths, _ := args["term_months"].(float64)
+	paymentDay, _ := args["payment_day"].(float64)
+	req.TermMonths = int32(termMonths)
+	req.PaymentDay = int32(paymentDay)
+
+	if val, isNum := args["interest_account_id"].(float64); isNum {
+		req.InterestAccountID = lo.ToPtr(int32(val))
+	}
+
+	if val, isNum := args["interest_category_id"].(float64); isNum {
+		req.InterestCategoryID = lo.ToPtr(int32(val))
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	loan, err := s.cfg.LoanSvc.SetLoan(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to set loan: %v", err)), nil
+	}
+
+	result, err := json.MarshalIndent(mapLoan(loan), "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleGetLoanStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	accountID, ok := args["account_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("account_id parameter is required"), nil
+	}
+
+	monthlyPayment := decimal.Zero
+	if val, _ := args["monthly_payment"].(string); val != "" {
+		parsed, err := decimal.NewFromString(val)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid monthly_payment: %v", err)), nil
+		}
+
+		monthlyPayment = parsed
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	status, err := s.cfg.LoanSvc.GetLoanStatus(queryCtx, int32(accountID), monthlyPayment)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get loan status: %v", err)), nil
+	}
+
+	output := &loanStatusOutput{
+		Loan:               mapLoan(status.Loan),
+		Currency:           status.Currency,
+		RemainingPrincipal: status.RemainingPrincipal.String(),
+		UnpaidInterest:     status.UnpaidInterest.String(),
+		AccruedInterest:    status.AccruedInterest.String(),
+		Repayments: lo.Map(status.Repayments, func(r *accounts.LoanRepayment, _ int) *loanRepaymentOutput {
+			return &loanRepaymentOutput{
+				TransactionID:      r.TransactionID,
+				Date:               r.Date,
+				Amount:             r.Amount.String(),
+				Interest:           r.Interest.String(),
+				Principal:          r.Principal.String(),
+				RemainingPrincipal: r.RemainingPrincipal.String(),
+			}
+		}),
+	}
+
+	if status.Projection != nil {
+		output.Projection = &payoffProjectionOutput{
+			MonthlyPayment: status.Projection.MonthlyPayment.String(),
+			PaymentsLeft:   status.Projection.PaymentsLeft,
+			PayoffDate:     status.Projection.PayoffDate,
+			TotalInterest:  status.Projection.TotalInterest.String(),
+			Schedule:       mapAmortizationEntries(status.Projection.Schedule),
+		}
+	}
+
+	result, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleGetLoanSchedule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	accountID, ok := args["account_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("account_id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	entries, err := s.cfg.LoanSvc.GetAmortizationSchedule(queryCtx, int32(accountID))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get loan schedule: %v", err)), nil
+	}
+
+	result, err := json.MarshalIndent(mapAmortizationEntries(entries), "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
@@ -0,0 +1,170 @@
+package mcp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/accounts"
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+func newLoansTestServer(t *testing.T, loanSvc *MockLoansService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:      gormDB,
+		Docs:    "test docs",
+		LoanSvc: loanSvc,
+	})
+}
+
+func TestServer_HandleSetLoan(t *testing.T) {
+	startDate := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		loanSvc := NewMockLoansService(gomock.NewController(t))
+		loanSvc.EXPECT().SetLoan(gomock.Any(), &accounts.SetLoanRequest{
+			AccountID:   3,
+			Kind:        database.LoanKindCreditLine,
+			Principal:   decimal.RequireFromString("1000"),
+			AnnualRate:  decimal.RequireFromString("12.5"),
+			TermMonths:  12,
+			PaymentDay:  10,
+			Compounding: database.LoanCompoundingDaily,
+			StartDate:   startDate,
+		}).Return(&database.Loan{
+			ID:          1,
+			AccountID:   3,
+			Kind:        database.LoanKindCreditLine,
+			Compounding: database.LoanCompoundingDaily,
+		}, nil)
+
+		result := callTool(t, newLoansTestServer(t, loanSvc), "set_loan", map[string]any{
+			"account_id":  float64(3),
+			"kind":        "credit_line",
+			"principal":   "1000",
+			"annual_rate": "12.5",
+			"term_months": float64(12),
+			"payment_day": float64(10),
+			"compounding": "daily",
+			"start_date":  "2026-01-15T00:00:00Z",
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"kind": "credit_line"`)
+		assert.Contains(t, text, `"compounding": "daily"`)
+	})
+
+	t.Run("invalid kind", func(t *testing.T) {
+		result := callTool(t, newLoansTestServer(t, NewMockLoansService(gomock.NewController(t))), "set_loan", map[string]any{
+			"account_id": float64(3),
+			"kind":       "mortgage",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "unsupported kind")
+	})
+
+	t.Run("invalid principal", func(t *testing.T) {
+		result := callTool(t, newLoansTestServer(t, NewMockLoansService(gomock.NewController(t))), "set_loan", map[string]any{
+			"account_id": float64(3),
+			"principal":  "abc",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid principal")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		loanSvc := NewMockLoansService(gomock.NewController(t))
+		loanSvc.EXPECT().SetLoan(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newLoansTestServer(t, loanSvc), "set_loan", map[string]any{
+			"account_id":  float64(3),
+			"principal":   "1000",
+			"annual_rate": "5",
+			"start_date":  "2026-01-15T00:00:00Z",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to set loan")
+	})
+}
+
+func TestServer_HandleGetLoanStatus(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		loanSvc := NewMockLoansService(gomock.NewController(t))
+		loanSvc.EXPECT().GetLoanStatus(gomock.Any(), int32(3), decimal.RequireFromString("250")).
+			Return(&accounts.LoanStatus{
+				Loan:               &database.Loan{ID: 1, AccountID: 3, Kind: database.LoanKindLoan},
+				Currency:           "USD",
+				RemainingPrincipal: decimal.RequireFromString("900"),
+				Repayments: []*accounts.LoanRepayment{
+					{TransactionID: 11, Amount: decimal.NewFromInt(110), Interest: decimal.NewFromInt(10), Principal: decimal.NewFromInt(100)},
+				},
+				Projection: &accounts.PayoffProjection{
+					MonthlyPayment: decimal.NewFromInt(250),
+					PaymentsLeft:   4,
+				},
+			}, nil)
+
+		result := callTool(t, newLoansTestServer(t, loanSvc), "get_loan_status", map[string]any{
+			"account_id":      float64(3),
+			"monthly_payment": "250",
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"remaining_principal": "900"`)
+		assert.Contains(t, text, `"transaction_id": 11`)
+		assert.Contains(t, text, `"payments_left": 4`)
+	})
+
+	t.Run("missing account", func(t *testing.T) {
+		result := callTool(t, newLoansTestServer(t, NewMockLoansService(gomock.NewController(t))), "get_loan_status", map[string]any{})
+
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		loanSvc := NewMockLoansService(gomock.NewController(t))
+		loanSvc.EXPECT().GetLoanStatus(gomock.Any(), int32(3), decimal.Zero).Return(nil, assert.AnError)
+
+		result := callTool(t, newLoansTestServer(t, loanSvc), "get_loan_status", map[string]any{"account_id": float64(3)})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to get loan status")
+	})
+}
+
+func TestServer_HandleGetLoanSchedule(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		loanSvc := NewMockLoansService(gomock.NewController(t))
+		loanSvc.EXPECT().GetAmortizationSchedule(gomock.Any(), int32(3)).Return([]*accounts.AmortizationEntry{
+			{Number: 1, Payment: decimal.RequireFromString("85.61"), RemainingPrincipal: decimal.RequireFromString("922.72")},
+		}, nil)
+
+		result := callTool(t, newLoansTestServer(t, loanSvc), "get_loan_schedule", map[string]any{"account_id": float64(3)})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"payment": "85.61"`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		loanSvc := NewMockLoansService(gomock.NewController(t))
+		loanSvc.EXPECT().GetAmortizationSchedule(gomock.Any(), int32(3)).Return(nil, assert.AnError)
+
+		result := callTool(t, newLoansTestServer(t, loanSvc), "get_loan_schedule", map[string]any{"account_id": float64(3)})
+
+		assert.True(t, result.IsError)
+	})
+}
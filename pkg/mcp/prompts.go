@@ -0,0 +1,214 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const monthLayout = "2006-01"
+
+func (s *Server) registerPrompts() {
+	s.mcpServer.AddPrompt(mcp.NewPrompt(
+		"categorize_uncategorized",
+		mcp.WithPromptDescription("Assign categories to expense and income transactions without a category and propose rules for recurring ones"),
+		mcp.WithArgument("from", mcp.ArgumentDescription("Start date YYYY-MM-DD, inclusive")),
+		mcp.WithArgument("to", mcp.ArgumentDescription("End date YYYY-MM-DD, inclusive")),
+	), s.handleCategorizeUncategorizedPrompt)
+
+	s.mcpServer.AddPrompt(mcp.NewPrompt(
+		"write_rule_from_examples",
+		mcp.WithPromptDescription("Write, dry run and save a Lua rule that handles the given example transactions"),
+		mcp.WithArgument(
+			"transaction_ids",
+			mcp.ArgumentDescription("Comma separated ids of example transactions"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("goal", mcp.ArgumentDescription("What the rule should do, e.g. set category Groceries")),
+	), s.handleWriteRuleFromExamplesPrompt)
+
+	s.mcpServer.AddPrompt(mcp.NewPrompt(
+		"monthly_review",
+		mcp.WithPromptDescription("Review income, spending, balances and data quality for one month"),
+		mcp.WithArgument("month", mcp.ArgumentDescription("Month YYYY-MM, defaults to the previous month")),
+	), s.handleMonthlyReviewPrompt)
+}
+
+func (s *Server) handleCategorizeUncategorizedPrompt(
+	ctx context.Context,
+	request mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	args := request.Params.Arguments
+
+	var dateFilter strings.Builder
+
+	for _, key := range []string{"from", "to"} {
+		val := args[key]
+		if val == "" {
+			continue
+		}
+
+		if _, err := time.Parse(time.DateOnly, val); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", key)
+		}
+
+		op := ">="
+		if key == "to" {
+			op = "<="
+		}
+
+		dateFilter.WriteString(fmt.Sprintf(" AND transaction_date_only %s '%s'", op, val))
+	}
+
+	text := fmt.Sprintf(`Categorize transactions that have no category.
+
+1. Find them with the query tool:
+   SELECT id, transaction_type, title, notes, source_account_id, destination_account_id,
+          destination_amount, destination_currency, transaction_date_only
+   FROM transactions
+   WHERE deleted_at IS NULL AND category_id IS NULL AND transaction_type IN (2, 3)%s
+   ORDER BY transaction_date_only DESC
+   LIMIT 200
+2. Pick a category for every transaction using only the ids from the attached category list.
+   Look at how similar titles were categorized before when unsure, and leave a transaction alone
+   rather than guess.
+3. Show the proposed assignment grouped by category and wait for confirmation.
+4. Apply it with bulk_set_transaction_category, one call per batch.
+5. For titles that repeat, suggest a rule via the write_rule_from_examples prompt instead of
+   categorizing them by hand every month.`, dateFilter.String())
+
+	return mcp.NewGetPromptResult(
+		"Categorize uncategorized transactions",
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+			s.embeddedResourceMessage(ctx, categoriesResourceURI, s.handleCategoriesResource),
+		},
+	), nil
+}
+
+func (s *Server) handleWriteRuleFromExamplesPrompt(
+	ctx context.Context,
+	request mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	args := request.Params.Arguments
+
+	var ids []string
+	for _, raw := range strings.Split(args["transaction_ids"], ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return nil, errors.Newf("invalid transaction id: %s", raw)
+		}
+
+		ids = append(ids, raw)
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("transaction_ids is required")
+	}
+
+	goal := args["goal"]
+	if goal == "" {
+		goal = "infer it from what the examples have in common"
+	}
+
+	text := fmt.Sprintf(`Write a rule from example transactions %s.
+Goal: %s.
+
+1. Load the examples with get_transaction_history or the query tool
+   (WHERE id IN (%s) AND deleted_at IS NULL) and find what they share:
+   title patterns, accounts, amounts, currencies.
+2. Check the attached rule list for an existing rule that already matches them. Read rule://{id}
+   for its script and prefer extending it with update_rule over adding an overlapping rule.
+3. Draft the Lua script. Match titles with string.find(title, "...", 1, true) and avoid
+   conditions that would also catch unrelated transactions.
+4. Run dry_run_rule against every example and at least one transaction that must not match.
+5. Save it with create_rule or update_rule, then record the examples with set_rule_test_cases
+   and confirm run_rule_tests passes.`, strings.Join(ids, ", "), goal, strings.Join(ids, ", "))
+
+	return mcp.NewGetPromptResult(
+		"Write a rule from examples",
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+			s.embeddedResourceMessage(ctx, rulesResourceURI, s.handleRulesResource),
+		},
+	), nil
+}
+
+func (s *Server) handleMonthlyReviewPrompt(
+	ctx context.Context,
+	request mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	month := request.Params.Arguments["month"]
+
+	var start time.Time
+	if month == "" {
+		now := time.Now().UTC()
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	} else {
+		parsed, err := time.Parse(monthLayout, month)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid month")
+		}
+
+		start = parsed
+	}
+
+	from := start.Format(time.DateOnly)
+	to := start.AddDate(0, 1, -1).Format(time.DateOnly)
+
+	text := fmt.Sprintf(`Review my finances for %s (%s to %s). Amounts are in the base currency unless stated.
+
+1. Income and expenses with one query:
+   SELECT SUM(CASE WHEN transaction_type = 2 THEN destination_amount_in_base_currency END) AS income,
+          SUM(CASE WHEN transaction_type = 3 THEN destination_amount_in_base_currency END) AS expenses
+   FROM transactions
+   WHERE deleted_at IS NULL AND transaction_date_only BETWEEN '%s' AND '%s'
+   Compare with the previous month.
+2. Spending by category, largest first, grouped by category_id with names from the
+   context://categories resource. Call out categories that moved more than 20%%.
+3. Count expenses without a category in the month and offer the categorize_uncategorized prompt
+   if there are any.
+4. Net worth at %s with get_net_worth and the change against the start of the month.
+5. Account balances from the attached account list, flagging liabilities that grew.
+6. Check list_jobs for failed jobs since %s.
+
+Finish with a short summary and at most five concrete suggestions.`,
+		start.Format(monthLayout), from, to, from, to, to, from)
+
+	return mcp.NewGetPromptResult(
+		"Monthly review",
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+			s.embeddedResourceMessage(ctx, accountsResourceURI, s.handleAccountsResource),
+		},
+	), nil
+}
+
+// embeddedResourceMessage attaches the current content of a resource to a prompt. Failures are
+// reported as text so the prompt stays usable and the client can read the resource itself.
+func (s *Server) embeddedResourceMessage(
+	ctx context.Context,
+	uri string,
+	handler func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error),
+) mcp.PromptMessage {
+	req := mcp.ReadResourceRequest{}
+	req.Params.URI = uri
+
+	contents, err := handler(ctx, req)
+	if err != nil {
+		return mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(
+			fmt.Sprintf("Read the %s resource for the current data (embedding failed: %v).", uri, err),
+		))
+	}
+
+	return mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(contents[0]))
+}
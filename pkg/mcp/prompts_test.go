@@ -0,0 +1,178 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	rulesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/rules/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+)
+
+func getPrompt(t *testing.T, server *gomcp.Server, name string, args map[string]string) (*mcp.GetPromptResult, *mcp.JSONRPCError) {
+	raw, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "prompts/get",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	require.NoError(t, err)
+
+	msg := server.MCPServer().HandleMessage(context.Background(), raw)
+	if rpcErr, ok := msg.(mcp.JSONRPCError); ok {
+		return nil, &rpcErr
+	}
+
+	resp, ok := msg.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+
+	result, ok := resp.Result.(mcp.GetPromptResult)
+	require.True(t, ok)
+
+	return &result, nil
+}
+
+func promptText(t *testing.T, msg mcp.PromptMessage) string {
+	content, ok := msg.Content.(mcp.TextContent)
+	require.True(t, ok)
+
+	return content.Text
+}
+
+func TestServer_CategorizeUncategorizedPrompt(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		catSvc := NewMockCategoryService(ctrl)
+
+		catSvc.EXPECT().GetAllCategories(gomock.Any()).Return([]*database.Category{{ID: 3, Name: "Groceries"}}, nil)
+
+		server := newResourceServer(t, &gomcp.ServerConfig{CategorySvc: catSvc})
+
+		result, rpcErr := getPrompt(t, server, "categorize_uncategorized", map[string]string{
+			"from": "2026-03-01",
+			"to":   "2026-03-31",
+		})
+		require.Nil(t, rpcErr)
+		require.Len(t, result.Messages, 2)
+
+		text := promptText(t, result.Messages[0])
+		assert.Contains(t, text, "category_id IS NULL")
+		assert.Contains(t, text, "transaction_date_only >= '2026-03-01' AND transaction_date_only <= '2026-03-31'")
+		assert.Contains(t, text, "bulk_set_transaction_category")
+
+		embedded, ok := result.Messages[1].Content.(mcp.EmbeddedResource)
+		require.True(t, ok)
+
+		resource, ok := embedded.Resource.(mcp.TextResourceContents)
+		require.True(t, ok)
+		assert.Equal(t, "context://categories", resource.URI)
+		assert.Contains(t, resource.Text, "Groceries")
+	})
+
+	t.Run("resource failure falls back to text", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		catSvc := NewMockCategoryService(ctrl)
+
+		catSvc.EXPECT().GetAllCategories(gomock.Any()).Return(nil, errors.New("db down"))
+
+		server := newResourceServer(t, &gomcp.ServerConfig{CategorySvc: catSvc})
+
+		result, rpcErr := getPrompt(t, server, "categorize_uncategorized", nil)
+		require.Nil(t, rpcErr)
+		require.Len(t, result.Messages, 2)
+		assert.NotContains(t, promptText(t, result.Messages[0]), "transaction_date_only >=")
+		assert.Contains(t, promptText(t, result.Messages[1]), "context://categories")
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		server := newResourceServer(t, &gomcp.ServerConfig{})
+
+		_, rpcErr := getPrompt(t, server, "categorize_uncategorized", map[string]string{"from": "2026-3-1'; --"})
+		require.NotNil(t, rpcErr)
+		assert.Contains(t, rpcErr.Error.Message, "invalid from")
+	})
+}
+
+func TestServer_WriteRuleFromExamplesPrompt(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rulesSvc := NewMockRulesService(ctrl)
+
+		rulesSvc.EXPECT().ListRules(gomock.Any(), &rulesv1.ListRulesRequest{}).
+			Return(&rulesv1.ListRulesResponse{Rules: []*gomoneypbv1.Rule{{Id: 1, Title: "Existing"}}}, nil)
+
+		server := newResourceServer(t, &gomcp.ServerConfig{RulesSvc: rulesSvc})
+
+		result, rpcErr := getPrompt(t, server, "write_rule_from_examples", map[string]string{
+			"transaction_ids": "10, 11,12",
+			"goal":            "set category Groceries",
+		})
+		require.Nil(t, rpcErr)
+		require.Len(t, result.Messages, 2)
+
+		text := promptText(t, result.Messages[0])
+		assert.Contains(t, text, "WHERE id IN (10, 11, 12)")
+		assert.Contains(t, text, "Goal: set category Groceries.")
+		assert.Contains(t, text, "dry_run_rule")
+
+		embedded, ok := result.Messages[1].Content.(mcp.EmbeddedResource)
+		require.True(t, ok)
+		assert.Equal(t, "context://rules", embedded.Resource.(mcp.TextResourceContents).URI)
+	})
+
+	t.Run("invalid ids", func(t *testing.T) {
+		server := newResourceServer(t, &gomcp.ServerConfig{})
+
+		_, rpcErr := getPrompt(t, server, "write_rule_from_examples", map[string]string{"transaction_ids": "10,abc"})
+		require.NotNil(t, rpcErr)
+		assert.Contains(t, rpcErr.Error.Message, "invalid transaction id: abc")
+	})
+
+	t.Run("missing ids", func(t *testing.T) {
+		server := newResourceServer(t, &gomcp.ServerConfig{})
+
+		_, rpcErr := getPrompt(t, server, "write_rule_from_examples", map[string]string{"transaction_ids": " , "})
+		require.NotNil(t, rpcErr)
+		assert.Contains(t, rpcErr.Error.Message, "transaction_ids is required")
+	})
+}
+
+func TestServer_MonthlyReviewPrompt(t *testing.T) {
+	t.Run("explicit month", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		accountSvc := NewMockAccountsService(ctrl)
+
+		accountSvc.EXPECT().GetAllAccounts(gomock.Any()).Return([]*database.Account{{ID: 1, Name: "Cash"}}, nil)
+
+		server := newResourceServer(t, &gomcp.ServerConfig{AccountSvc: accountSvc})
+
+		result, rpcErr := getPrompt(t, server, "monthly_review", map[string]string{"month": "2026-02"})
+		require.Nil(t, rpcErr)
+		require.Len(t, result.Messages, 2)
+
+		text := promptText(t, result.Messages[0])
+		assert.Contains(t, text, "Review my finances for 2026-02 (2026-02-01 to 2026-02-28)")
+		assert.Contains(t, text, "BETWEEN '2026-02-01' AND '2026-02-28'")
+		assert.Contains(t, text, "more than 20%")
+
+		embedded, ok := result.Messages[1].Content.(mcp.EmbeddedResource)
+		require.True(t, ok)
+		assert.Equal(t, "context://accounts", embedded.Resource.(mcp.TextResourceContents).URI)
+	})
+
+	t.Run("invalid month", func(t *testing.T) {
+		server := newResourceServer(t, &gomcp.ServerConfig{})
+
+		_, rpcErr := getPrompt(t, server, "monthly_review", map[string]string{"month": "February"})
+		require.NotNil(t, rpcErr)
+		assert.Contains(t, rpcErr.Error.Message, "invalid month")
+	})
+}
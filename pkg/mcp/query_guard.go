@@ -0,0 +1,266 @@
+package mcp
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	pgquery "github.com/pganalyze/pg_query_go/v6"
+	"github.com/samber/lo"
+	pgparser "github.com/wasilibs/go-pgquery"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// defaultQueryTables are the tables the query tool may read. Credentials, tokens and
+// system settings (users, service_tokens, jti_revocations, system_configurations,
+// app_configs) are left out on purpose.
+var defaultQueryTables = []string{
+	"accounts",
+	"categories",
+	"currencies",
+	"currency_rate_history",
+	"currency_rate_overrides",
+	"daily_stat",
+	"double_entries",
+	"fx_revaluations",
+	"import_deduplication",
+	"investment_accounts",
+	"investment_trades",
+	"job_runs",
+	"loans",
+	"lua_modules",
+	"rule_revisions",
+	"rule_test_cases",
+	"rules",
+	"schedule_rule_runs",
+	"schedule_rules",
+	"securities",
+	"security_prices",
+	"tags",
+	"transaction_history",
+	"transactions",
+	"information_schema.columns",
+	"information_schema.tables",
+}
+
+// defaultQueryFunctions are side effect free functions the query tool may call.
+var defaultQueryFunctions = []string{
+	// aggregates
+	"count", "sum", "avg", "min", "max", "array_agg", "string_agg", "json_agg", "jsonb_agg",
+	"json_object_agg", "jsonb_object_agg", "bool_and", "bool_or", "every", "stddev", "stddev_pop",
+	"stddev_samp", "variance", "var_pop", "var_samp", "corr", "percentile_cont", "percentile_disc", "mode",
+	// window
+	"row_number", "rank", "dense_rank", "percent_rank", "cume_dist", "ntile", "lag", "lead",
+	"first_value", "last_value", "nth_value",
+	// conditional
+	"coalesce", "nullif", "greatest", "least",
+	// math
+	"abs", "round", "trunc", "floor", "ceil", "ceiling", "sign", "mod", "power", "sqrt", "exp", "ln", "log",
+	"width_bucket",
+	// date and time
+	"now", "date_trunc", "date_part", "date_bin", "extract", "age", "make_date", "make_time",
+	"make_timestamp", "make_interval", "to_char", "to_date", "to_timestamp", "to_number", "justify_days",
+	"justify_interval", "isfinite", "generate_series", "timezone",
+	// strings
+	"lower", "upper", "initcap", "length", "char_length", "trim", "btrim", "ltrim", "rtrim", "substring",
+	"substr", "replace", "concat", "concat_ws", "split_part", "position", "strpos", "left", "right",
+	"lpad", "rpad", "reverse", "repeat", "format", "starts_with", "regexp_replace", "regexp_match",
+	"regexp_matches", "regexp_split_to_array", "string_to_array", "md5", "overlay", "similar_to_escape",
+	// arrays and json
+	"array_length", "array_position", "array_to_string", "array_remove", "array_append", "cardinality",
+	"unnest", "json_build_object", "jsonb_build_object", "json_build_array", "jsonb_build_array",
+	"jsonb_array_elements", "jsonb_array_elements_text", "jsonb_array_length", "jsonb_each",
+	"jsonb_each_text", "jsonb_object_keys", "jsonb_typeof", "jsonb_extract_path_text", "to_json", "to_jsonb",
+	"jsonb_pretty",
+	// operators the parser turns into calls
+	"overlaps",
+}
+
+// lockStrengths are the FOR UPDATE / FOR SHARE variants, all of them take row locks.
+var lockStrengths = map[pgquery.LockClauseStrength]string{
+	pgquery.LockClauseStrength_LCS_FORKEYSHARE:    "FOR SHARE",
+	pgquery.LockClauseStrength_LCS_FORSHARE:       "FOR SHARE",
+	pgquery.LockClauseStrength_LCS_FORNOKEYUPDATE: "FOR UPDATE",
+	pgquery.LockClauseStrength_LCS_FORUPDATE:      "FOR UPDATE",
+}
+
+// QueryGuard validates agent supplied SQL before it is executed in a read only transaction.
+// The query is parsed with the PostgreSQL parser (libpg_query) and every node of the tree is
+// checked: a single SELECT statement, every table reference (FROM, JOIN, TABLE, subqueries)
+// against the table allowlist and every function call against the function allowlist.
+// It is a defence in depth layer; the read only transaction and the optional restricted role
+// are what the database enforces.
+type QueryGuard struct {
+	tables    map[string]struct{}
+	functions map[string]struct{}
+}
+
+func NewQueryGuard(extraTables []string, extraFunctions []string) *QueryGuard {
+	normalize := func(items []string) map[string]struct{} {
+		return lo.SliceToMap(items, func(item string) (string, struct{}) {
+			return strings.ToLower(strings.TrimSpace(item)), struct{}{}
+		})
+	}
+
+	return &QueryGuard{
+		tables:    normalize(append(slices.Clone(defaultQueryTables), extraTables...)),
+		functions: normalize(append(slices.Clone(defaultQueryFunctions), extraFunctions...)),
+	}
+}
+
+// Validate returns a descriptive error when the query is not an allowed read only statement.
+func (g *QueryGuard) Validate(query string) error {
+	tree, err := pgparser.Parse(query)
+	if err != nil {
+		return errors.Wrap(err, "invalid query")
+	}
+
+	switch len(tree.Stmts) {
+	case 0:
+		return errors.New("query is empty")
+	case 1:
+	default:
+		return errors.New("only a single statement is allowed")
+	}
+
+	if tree.Stmts[0].Stmt.GetSelectStmt() == nil {
+		return errors.New("only SELECT queries are allowed")
+	}
+
+	return g.walk(tree.Stmts[0].Stmt.ProtoReflect(), nil)
+}
+
+// isOrdered reports whether the outermost SELECT of a validated query has an ORDER BY.
+func isOrdered(query string) bool {
+	tree, err := pgparser.Parse(query)
+	if err != nil || len(tree.Stmts) != 1 {
+		return false
+	}
+
+	return len(tree.Stmts[0].Stmt.GetSelectStmt().GetSortClause()) > 0
+}
+
+// walk checks msg and everything below it. ctes are the names of common table expressions
+// visible at this point of the query, a table reference without schema may use them.
+func (g *QueryGuard) walk(msg protoreflect.Message, ctes []string) error {
+	switch node := msg.Interface().(type) {
+	case *pgquery.InsertStmt:
+		return errors.New("INSERT is not allowed, the query tool is read only")
+	case *pgquery.UpdateStmt:
+		return errors.New("UPDATE is not allowed, the query tool is read only")
+	case *pgquery.DeleteStmt:
+		return errors.New("DELETE is not allowed, the query tool is read only")
+	case *pgquery.MergeStmt:
+		return errors.New("MERGE is not allowed, the query tool is read only")
+	case *pgquery.SelectStmt:
+		return g.walkSelect(node, ctes)
+	case *pgquery.RangeVar:
+		return g.checkTable(node, ctes)
+	case *pgquery.FuncCall:
+		if err := g.checkFunction(node); err != nil {
+			return err
+		}
+	}
+
+	return g.walkFields(msg, ctes)
+}
+
+// walkSelect scopes the WITH clause: a CTE is visible in the statement, in later CTEs and,
+// for WITH RECURSIVE, in every CTE of the clause. Outside of it the name is a table again.
+func (g *QueryGuard) walkSelect(stmt *pgquery.SelectStmt, ctes []string) error {
+	if stmt.IntoClause != nil {
+		return errors.New("SELECT INTO is not allowed, the query tool is read only")
+	}
+
+	for _, node := range stmt.LockingClause {
+		if name, ok := lockStrengths[node.GetLockingClause().GetStrength()]; ok {
+			return errors.Newf("%s is not allowed, the query tool is read only", name)
+		}
+	}
+
+	visible := slices.Clone(ctes)
+
+	if with := stmt.WithClause; with != nil {
+		names := lo.Map(with.Ctes, func(node *pgquery.Node, _ int) string {
+			return node.GetCommonTableExpr().GetCtename()
+		})
+
+		for i, node := range with.Ctes {
+			scope := append(slices.Clone(ctes), names[:i]...)
+			if with.Recursive {
+				scope = append(slices.Clone(ctes), names...)
+			}
+
+			if err := g.walk(node.ProtoReflect(), scope); err != nil {
+				return err
+			}
+		}
+
+		visible = append(visible, names...)
+	}
+
+	return g.walkFields(stmt.ProtoReflect(), visible, "with_clause")
+}
+
+// walkFields walks all message fields of msg except skip.
+func (g *QueryGuard) walkFields(msg protoreflect.Message, ctes []string, skip ...protoreflect.Name) error {
+	var err error
+
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() != protoreflect.MessageKind || slices.Contains(skip, fd.Name()) {
+			return true
+		}
+
+		if fd.IsList() {
+			for i := 0; i < v.List().Len() && err == nil; i++ {
+				err = g.walk(v.List().Get(i).Message(), ctes)
+			}
+		} else {
+			err = g.walk(v.Message(), ctes)
+		}
+
+		return err == nil
+	})
+
+	return err
+}
+
+func (g *QueryGuard) checkTable(rv *pgquery.RangeVar, ctes []string) error {
+	if rv.Schemaname == "" && slices.Contains(ctes, rv.Relname) {
+		return nil
+	}
+
+	name := rv.Relname
+	if rv.Schemaname != "" {
+		name = rv.Schemaname + "." + name
+	}
+
+	if rv.Catalogname != "" {
+		name = rv.Catalogname + "." + name
+	}
+
+	if _, ok := g.tables[strings.TrimPrefix(name, "public.")]; !ok {
+		return errors.Newf("table %s is not allowed, allowed tables: %s", name, g.allowedTables())
+	}
+
+	return nil
+}
+
+func (g *QueryGuard) checkFunction(call *pgquery.FuncCall) error {
+	name := strings.Join(lo.Map(call.Funcname, func(node *pgquery.Node, _ int) string {
+		return node.GetString_().GetSval()
+	}), ".")
+
+	if _, ok := g.functions[strings.TrimPrefix(name, "pg_catalog.")]; ok {
+		return nil
+	}
+
+	return errors.Newf("function %s is not allowed", name)
+}
+
+func (g *QueryGuard) allowedTables() string {
+	tables := lo.Keys(g.tables)
+	slices.Sort(tables)
+
+	return strings.Join(tables, ", ")
+}
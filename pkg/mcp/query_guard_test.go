@@ -0,0 +1,103 @@
+package mcp_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+)
+
+func TestQueryGuard_Validate_Allowed(t *testing.T) {
+	guard := gomcp.NewQueryGuard(nil, nil)
+
+	cases := []struct {
+		name string
+		sql  string
+	}{
+		{name: "simple select", sql: "SELECT id, name FROM accounts"},
+		{name: "trailing semicolon", sql: "select * from transactions;"},
+		{name: "schema qualified", sql: "SELECT * FROM public.accounts a"},
+		{name: "join", sql: "SELECT t.id, a.name FROM transactions t JOIN accounts a ON a.id = t.source_account_id LEFT JOIN categories c USING (id) WHERE t.id > 10"},
+		{name: "comma join", sql: "SELECT * FROM accounts a, currencies c WHERE a.currency = c.id"},
+		{name: "cte", sql: "WITH monthly AS (SELECT date_trunc('month', transaction_date_only) AS m, sum(destination_amount) AS s FROM transactions GROUP BY 1) SELECT * FROM monthly ORDER BY m"},
+		{name: "recursive cte", sql: "WITH RECURSIVE tree(id) AS (SELECT 1 UNION ALL SELECT id + 1 FROM tree WHERE id < 5) SELECT * FROM tree"},
+		{name: "subquery in from", sql: "SELECT sub.total FROM (SELECT sum(amount) AS total FROM daily_stat) sub"},
+		{name: "exists subquery", sql: "SELECT * FROM accounts a WHERE EXISTS (SELECT 1 FROM transactions t WHERE t.source_account_id = a.id)"},
+		{name: "casts", sql: "SELECT amount::numeric(20, 2), CAST(id AS varchar(10)), '2025-01-01'::date FROM daily_stat"},
+		{name: "extract from", sql: "SELECT extract(year FROM transaction_date_time), substring(title FROM 1 FOR 3), trim(both ' ' FROM title) FROM transactions"},
+		{name: "is distinct from", sql: "SELECT * FROM transactions WHERE category_id IS NOT DISTINCT FROM NULL"},
+		{name: "generate series", sql: "SELECT d::date FROM generate_series('2025-01-01'::date, '2025-02-01'::date, interval '1 day') d"},
+		{name: "window", sql: "SELECT id, row_number() OVER (PARTITION BY account_id ORDER BY date) FROM daily_stat"},
+		{name: "filter", sql: "SELECT count(*) FILTER (WHERE transaction_type = 1) FROM transactions"},
+		{name: "information schema", sql: "SELECT column_name FROM information_schema.columns WHERE table_name = 'transactions'"},
+		{name: "keywords inside literals and comments", sql: "SELECT 'delete; drop table users', $$ insert $$, E'it\\'s' FROM accounts -- update users\n /* DROP /* nested */ */"},
+		{name: "quoted identifiers", sql: `SELECT "name" FROM "accounts"`},
+		{name: "union", sql: "SELECT id FROM accounts UNION SELECT id FROM categories"},
+		{name: "parenthesized", sql: "(SELECT 1)"},
+		{name: "table command", sql: "SELECT * FROM (TABLE accounts) a"},
+		{name: "cte shadows table", sql: "WITH users AS (SELECT id FROM accounts) SELECT * FROM users"},
+		{name: "cte in later cte", sql: "WITH a AS (SELECT 1 AS x), b AS (SELECT x FROM a) SELECT * FROM b"},
+		{name: "at time zone and similar to", sql: "SELECT transaction_date_time AT TIME ZONE 'UTC' FROM transactions WHERE title SIMILAR TO 'a%'"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.NoError(t, guard.Validate(c.sql))
+		})
+	}
+}
+
+func TestQueryGuard_Validate_Rejected(t *testing.T) {
+	guard := gomcp.NewQueryGuard(nil, nil)
+
+	cases := []struct {
+		name          string
+		sql           string
+		expectedError string
+	}{
+		{name: "empty", sql: " ; ", expectedError: "query is empty"},
+		{name: "insert", sql: "INSERT INTO accounts (name) VALUES ('x')", expectedError: "only SELECT queries are allowed"},
+		{name: "comment before statement", sql: "/* hi */ DELETE FROM accounts", expectedError: "only SELECT queries are allowed"},
+		{name: "multiple statements", sql: "SELECT 1; SELECT 2", expectedError: "only a single statement is allowed"},
+		{name: "data modifying cte", sql: "WITH d AS (DELETE FROM accounts RETURNING *) SELECT * FROM d", expectedError: "DELETE is not allowed"},
+		{name: "select into", sql: "SELECT * INTO backup FROM accounts", expectedError: "SELECT INTO is not allowed"},
+		{name: "for update", sql: "SELECT * FROM accounts FOR UPDATE", expectedError: "UPDATE is not allowed"},
+		{name: "for share", sql: "SELECT * FROM accounts FOR KEY SHARE", expectedError: "FOR SHARE is not allowed"},
+		{name: "users table", sql: "SELECT * FROM users", expectedError: "table users is not allowed"},
+		{name: "quoted users table", sql: `SELECT * FROM public."users"`, expectedError: "table public.users is not allowed"},
+		{name: "joined table", sql: "SELECT * FROM accounts a JOIN service_tokens s ON true", expectedError: "table service_tokens is not allowed"},
+		{name: "comma table", sql: "SELECT * FROM accounts, system_configurations", expectedError: "table system_configurations is not allowed"},
+		{name: "subquery table", sql: "SELECT * FROM accounts WHERE id IN (SELECT id FROM app_configs)", expectedError: "table app_configs is not allowed"},
+		{name: "catalog table", sql: "SELECT * FROM pg_catalog.pg_authid", expectedError: "table pg_catalog.pg_authid is not allowed"},
+		{name: "pg_sleep", sql: "SELECT pg_sleep(60)", expectedError: "function pg_sleep is not allowed"},
+		{name: "set_config", sql: "SELECT set_config('role', 'postgres', false)", expectedError: "function set_config is not allowed"},
+		{name: "nextval", sql: "SELECT nextval('accounts_id_seq')", expectedError: "function nextval is not allowed"},
+		{name: "lo_import", sql: "SELECT lo_import('/etc/passwd')", expectedError: "function lo_import is not allowed"},
+		{name: "dblink in from", sql: "SELECT * FROM dblink('host=evil', 'select 1') AS t(x int)", expectedError: "function dblink is not allowed"},
+		{name: "quoted function", sql: `SELECT "pg_read_file"('/etc/passwd')`, expectedError: "function pg_read_file is not allowed"},
+		{name: "unterminated literal", sql: "SELECT 'abc FROM accounts", expectedError: "unterminated quoted string"},
+		{name: "table command", sql: "TABLE users", expectedError: "table users is not allowed"},
+		{name: "table command in from", sql: "SELECT * FROM (TABLE users) t", expectedError: "table users is not allowed"},
+		{name: "table command in sublink", sql: "SELECT * FROM accounts WHERE id IN (TABLE service_tokens)", expectedError: "table service_tokens is not allowed"},
+		{name: "scalar subquery", sql: "SELECT (SELECT login FROM users LIMIT 1) FROM accounts", expectedError: "table users is not allowed"},
+		{name: "cte reads the table it shadows", sql: "WITH users AS (SELECT * FROM users) SELECT * FROM users", expectedError: "table users is not allowed"},
+		{name: "cte out of scope", sql: "SELECT * FROM users, (WITH users AS (SELECT 1) SELECT 1) x", expectedError: "table users is not allowed"},
+		{name: "schema qualified cte name", sql: "WITH users AS (SELECT 1) SELECT * FROM public.users", expectedError: "table public.users is not allowed"},
+		{name: "lateral function", sql: "SELECT * FROM accounts, LATERAL pg_ls_dir('.') f", expectedError: "function pg_ls_dir is not allowed"},
+		{name: "catalog qualified function", sql: "SELECT pg_catalog.pg_sleep(1)", expectedError: "function pg_catalog.pg_sleep is not allowed"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := guard.Validate(c.sql)
+			assert.ErrorContains(t, err, c.expectedError)
+		})
+	}
+}
+
+func TestQueryGuard_Validate_ExtraAllowlist(t *testing.T) {
+	guard := gomcp.NewQueryGuard([]string{"Users"}, []string{"pg_size_pretty"})
+
+	assert.NoError(t, guard.Validate("SELECT pg_size_pretty(1024) FROM users"))
+}
@@ -1,60 +1,322 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
-	"regexp"
+	"hash/fnv"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
 )
 
 const (
 	queryTimeout = 30 * time.Second
 	maxRows      = 999_999
+
+	// queryCancelGrace lets postgres cancel the statement first, its error is clearer than a context deadline
+	queryCancelGrace = 5 * time.Second
+
+	defaultQueryPageSize = 500
+	maxQueryPageSize     = 10_000
+	defaultQueryMaxBytes = 256 << 10
 )
 
-var (
-	forbiddenPatterns = []*regexp.Regexp{
-		regexp.MustCompile(`(?i)^\s*(INSERT|UPDATE|DELETE|DROP|CREATE|ALTER|TRUNCATE|GRANT|REVOKE)`),
-		regexp.MustCompile(`(?i);\s*(INSERT|UPDATE|DELETE|DROP|CREATE|ALTER|TRUNCATE|GRANT|REVOKE)`),
-		regexp.MustCompile(`(?i)INTO\s+OUTFILE`),
-		regexp.MustCompile(`(?i)LOAD\s+DATA`),
-	}
-	selectPattern = regexp.MustCompile(`(?i)^\s*SELECT\b`)
+const (
+	queryFormatJSON    = "json"
+	queryFormatColumns = "columns"
+	queryFormatCSV     = "csv"
 )
 
+type queryColumnOutput struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+type queryOutput struct {
+	Columns    []*queryColumnOutput `json:"columns"`
+	Rows       any                  `json:"rows"`
+	RowCount   int                  `json:"row_count"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+	Notice     string               `json:"notice,omitempty"`
+}
+
+// queryCursor is the position of the next page. It carries a hash of the SQL so a cursor
+// can not be replayed against a different query. The position is an offset: the query runs
+// again for every page, so pages only line up when the order is deterministic and the rows
+// before the offset did not change in between.
+type queryCursor struct {
+	Offset int
+	Hash   uint64
+}
+
+func hashQuery(query string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(query))
+
+	return h.Sum64()
+}
+
+func (c queryCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%x", c.Offset, c.Hash)))
+}
+
+func decodeQueryCursor(raw string, query string) (int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+
+	offsetStr, hashStr, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return 0, errors.New("invalid cursor")
+	}
+
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+
+	hash, err := strconv.ParseUint(hashStr, 16, 64)
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+
+	if hash != hashQuery(query) {
+		return 0, errors.New("cursor belongs to a different query, run the query without cursor first")
+	}
+
+	return offset, nil
+}
+
+// queryPage collects rows of one page in the requested format and stops before the byte budget is exceeded.
+type queryPage struct {
+	format   string
+	columns  []string
+	maxBytes int
+
+	size   int
+	count  int
+	rows   []map[string]any
+	values map[string][]any
+	csvBuf bytes.Buffer
+}
+
+func newQueryPage(format string, columns []string, maxBytes int) *queryPage {
+	p := &queryPage{
+		format:   format,
+		columns:  columns,
+		maxBytes: maxBytes,
+		rows:     []map[string]any{},
+		values:   map[string][]any{},
+	}
+
+	if format == queryFormatCSV {
+		w := csv.NewWriter(&p.csvBuf)
+		_ = w.Write(columns)
+		w.Flush()
+		p.size = p.csvBuf.Len()
+	}
+
+	for _, col := range columns {
+		p.values[col] = []any{}
+	}
+
+	return p
+}
+
+// add appends a row, it returns false when the row does not fit into the byte budget.
+func (p *queryPage) add(values []any) (bool, error) {
+	switch p.format {
+	case queryFormatCSV:
+		record := lo.Map(values, func(v any, _ int) string {
+			return csvValue(v)
+		})
+
+		var line bytes.Buffer
+		w := csv.NewWriter(&line)
+		_ = w.Write(record)
+		w.Flush()
+
+		if p.size+line.Len() > p.maxBytes {
+			return false, nil
+		}
+
+		p.csvBuf.Write(line.Bytes())
+		p.size += line.Len()
+	case queryFormatColumns:
+		rowSize := 0
+		for _, v := range values {
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return false, err
+			}
+
+			rowSize += len(encoded) + 1
+		}
+
+		if p.size+rowSize > p.maxBytes {
+			return false, nil
+		}
+
+		for i, col := range p.columns {
+			p.values[col] = append(p.values[col], values[i])
+		}
+
+		p.size += rowSize
+	default:
+		row := make(map[string]any, len(p.columns))
+		for i, col := range p.columns {
+			row[col] = values[i]
+		}
+
+		encoded, err := json.Marshal(row)
+		if err != nil {
+			return false, err
+		}
+
+		if p.size+len(encoded)+1 > p.maxBytes {
+			return false, nil
+		}
+
+		p.rows = append(p.rows, row)
+		p.size += len(encoded) + 1
+	}
+
+	p.count++
+
+	return true, nil
+}
+
+func (p *queryPage) output() any {
+	switch p.format {
+	case queryFormatCSV:
+		return p.csvBuf.String()
+	case queryFormatColumns:
+		return p.values
+	default:
+		return p.rows
+	}
+}
+
+func csvValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case map[string]any, []any:
+		encoded, _ := json.Marshal(val)
+		return string(encoded)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
 func (s *Server) handleQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	sqlQuery, ok := request.GetArguments()["sql"].(string)
+	args := request.GetArguments()
+
+	sqlQuery, ok := args["sql"].(string)
 	if !ok || sqlQuery == "" {
 		return mcp.NewToolResultError("sql parameter is required"), nil
 	}
 
-	if err := validateQuery(sqlQuery); err != nil {
+	if err := s.queryGuard.Validate(sqlQuery); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	format := queryFormatJSON
+	if val, _ := args["format"].(string); val != "" {
+		format = strings.ToLower(val)
+	}
+
+	if !lo.Contains([]string{queryFormatJSON, queryFormatColumns, queryFormatCSV}, format) {
+		return mcp.NewToolResultError(fmt.Sprintf("unsupported format: %s", format)), nil
+	}
+
+	pageSize := defaultQueryPageSize
+	if val, ok := args["page_size"].(float64); ok {
+		if val < 1 || val > maxQueryPageSize {
+			return mcp.NewToolResultError(fmt.Sprintf("page_size must be between 1 and %d", maxQueryPageSize)), nil
+		}
+
+		pageSize = int(val)
+	}
+
+	offset := 0
+	if val, _ := args["cursor"].(string); val != "" {
+		var err error
+		if offset, err = decodeQueryCursor(val, sqlQuery); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	budget := rowBudget(ctx)
+	limitedByBudget := budget < pageSize
+	pageSize = min(pageSize, budget)
+
+	timeout := lo.CoalesceOrEmpty(s.cfg.QueryStatementTimeout, queryTimeout)
+
+	queryCtx, cancel := context.WithTimeout(ctx, timeout+queryCancelGrace)
 	defer cancel()
 
-	var results []map[string]any
+	tx := s.db.WithContext(queryCtx).Begin(&sql.TxOptions{ReadOnly: true})
+	if tx.Error != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to begin read only transaction: %v", tx.Error)), nil
+	}
+	defer tx.Rollback() // nothing is ever committed
+
+	if err := tx.Exec(fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())).Error; err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to set statement timeout: %v", err)), nil
+	}
+
+	if s.cfg.QueryRole != "" {
+		if err := tx.Exec("SET LOCAL ROLE " + quoteIdentifier(s.cfg.QueryRole)).Error; err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to switch to query role: %v", err)), nil
+		}
+	}
+
+	// one extra row tells whether there is a next page; the newlines keep a trailing line comment
+	// of the agent query from swallowing the closing parenthesis
+	pagedQuery := fmt.Sprintf(
+		"SELECT * FROM (\n%s\n) AS mcp_page LIMIT %d OFFSET %d",
+		strings.TrimRight(strings.TrimSpace(sqlQuery), "; \t\r\n"),
+		pageSize+1,
+		offset,
+	)
 
-	rows, err := s.db.WithContext(queryCtx).Raw(sqlQuery).Rows()
+	rows, err := tx.Raw(pagedQuery).Rows()
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("query error: %v", err)), nil
 	}
 	defer func() { _ = rows.Close() }()
 
-	columns, err := rows.Columns()
+	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("columns error: %v", err)), nil
 	}
 
-	rowCount := 0
+	columns := lo.Map(columnTypes, func(col *sql.ColumnType, _ int) string {
+		return col.Name()
+	})
+
+	maxBytes := lo.CoalesceOrEmpty(s.cfg.QueryMaxResponseBytes, defaultQueryMaxBytes)
+	page := newQueryPage(format, columns, maxBytes)
+
+	hasMore := false
+	overBytes := false
+
 	for rows.Next() {
-		if rowCount >= maxRows {
+		if page.count >= pageSize {
+			hasMore = true
 			break
 		}
 
@@ -68,46 +330,73 @@ func (s *Server) handleQuery(ctx context.Context, request mcp.CallToolRequest) (
 			return mcp.NewToolResultError("error processing query results"), nil
 		}
 
-		row := make(map[string]any)
-		for i, col := range columns {
-			row[col] = convertValue(values[i])
+		for i := range values {
+			values[i] = convertValue(values[i])
+		}
+
+		added, addErr := page.add(values)
+		if addErr != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("json error: %v", addErr)), nil
 		}
 
-		results = append(results, row)
-		rowCount++
+		if !added {
+			if page.count == 0 {
+				return mcp.NewToolResultError(fmt.Sprintf(
+					"row %d alone exceeds the response budget of %d bytes, select fewer or shorter columns",
+					offset+1, maxBytes,
+				)), nil
+			}
+
+			hasMore = true
+			overBytes = true
+			break
+		}
 	}
 
 	if rowsErr := rows.Err(); rowsErr != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("rows error: %v", rowsErr)), nil
 	}
 
-	output, err := json.MarshalIndent(results, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("json error: %v", err)), nil
-	}
+	reportRows(ctx, page.count)
 
-	resultText := string(output)
-	if rowCount >= maxRows {
-		resultText = fmt.Sprintf("(truncated to %d rows)\n%s", maxRows, resultText)
+	out := &queryOutput{
+		Columns: lo.Map(columnTypes, func(col *sql.ColumnType, _ int) *queryColumnOutput {
+			return &queryColumnOutput{
+				Name: col.Name(),
+				Type: strings.ToLower(col.DatabaseTypeName()),
+			}
+		}),
+		Rows:     page.output(),
+		RowCount: page.count,
 	}
 
-	return mcp.NewToolResultText(resultText), nil
-}
+	if hasMore {
+		out.NextCursor = queryCursor{Offset: offset + page.count, Hash: hashQuery(sqlQuery)}.encode()
 
-func validateQuery(sql string) error {
-	sql = strings.TrimSpace(sql)
+		switch {
+		case overBytes:
+			out.Notice = fmt.Sprintf("response truncated to %d rows to stay under %d bytes, pass next_cursor to continue", page.count, maxBytes)
+		case limitedByBudget:
+			out.Notice = fmt.Sprintf("page cut to the remaining row limit of %d rows, pass next_cursor once the limit resets", page.count)
+		default:
+			out.Notice = "more rows available, pass next_cursor to continue"
+		}
 
-	if !selectPattern.MatchString(sql) {
-		return fmt.Errorf("only SELECT queries are allowed")
+		if !isOrdered(sqlQuery) {
+			out.Notice += "; the sql has no ORDER BY, add one on a unique column or later pages may repeat or skip rows"
+		}
 	}
 
-	for _, pattern := range forbiddenPatterns {
-		if pattern.MatchString(sql) {
-			return fmt.Errorf("forbidden SQL operation detected")
-		}
+	output, err := json.Marshal(out)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("json error: %v", err)), nil
 	}
 
-	return nil
+	return mcp.NewToolResultText(string(output)), nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
 }
 
 func convertValue(v any) any {
@@ -0,0 +1,234 @@
+package mcp_test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+type queryResult struct {
+	Columns []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"columns"`
+	Rows       json.RawMessage `json:"rows"`
+	RowCount   int             `json:"row_count"`
+	NextCursor string          `json:"next_cursor"`
+	Notice     string          `json:"notice"`
+}
+
+func runQuery(
+	t *testing.T,
+	maxBytes int,
+	args map[string]any,
+	expectQuery string,
+	rows *sqlmock.Rows,
+) *mcp.CallToolResult {
+	gormDB, mockDB, mock := testingutils.GormMock()
+	defer func() { _ = mockDB.Close() }()
+
+	if expectQuery != "" {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("SET LOCAL statement_timeout = 30000")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(expectQuery)).WillReturnRows(rows)
+		mock.ExpectRollback()
+	}
+
+	server := gomcp.NewServer(&gomcp.ServerConfig{
+		DB:                    gormDB,
+		Docs:                  "test docs",
+		QueryMaxResponseBytes: maxBytes,
+	})
+
+	result := callTool(t, server, "query", args)
+
+	if expectQuery != "" {
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+
+	return result
+}
+
+func decodeQueryResult(t *testing.T, result *mcp.CallToolResult) *queryResult {
+	require.False(t, result.IsError, result.Content[0].(mcp.TextContent).Text)
+
+	var out queryResult
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &out))
+
+	return &out
+}
+
+func categoryRows(count int) *sqlmock.Rows {
+	rows := sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("id").OfType("INT4", int64(0)),
+		sqlmock.NewColumn("name").OfType("TEXT", ""),
+	)
+
+	for i := 1; i <= count; i++ {
+		rows.AddRow(driver.Value(int64(i)), driver.Value(strings.Repeat("x", i)))
+	}
+
+	return rows
+}
+
+func TestServer_HandleQuery_Pagination(t *testing.T) {
+	const sqlText = "SELECT id, name FROM categories ORDER BY id;"
+
+	first := decodeQueryResult(t, runQuery(t, 0,
+		map[string]any{"sql": sqlText, "page_size": float64(2)},
+		"SELECT * FROM (\nSELECT id, name FROM categories ORDER BY id\n) AS mcp_page LIMIT 3 OFFSET 0",
+		categoryRows(3),
+	))
+
+	assert.Equal(t, 2, first.RowCount)
+	assert.JSONEq(t, `[{"id":1,"name":"x"},{"id":2,"name":"xx"}]`, string(first.Rows))
+	require.Len(t, first.Columns, 2)
+	assert.Equal(t, "int4", first.Columns[0].Type)
+	assert.Equal(t, "text", first.Columns[1].Type)
+	require.NotEmpty(t, first.NextCursor)
+	assert.Equal(t, "more rows available, pass next_cursor to continue", first.Notice)
+
+	second := decodeQueryResult(t, runQuery(t, 0,
+		map[string]any{"sql": sqlText, "page_size": float64(2), "cursor": first.NextCursor},
+		"LIMIT 3 OFFSET 2",
+		categoryRows(1),
+	))
+
+	assert.Equal(t, 1, second.RowCount)
+	assert.Empty(t, second.NextCursor)
+	assert.Empty(t, second.Notice)
+}
+
+func TestServer_HandleQuery_Formats(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		out := decodeQueryResult(t, runQuery(t, 0,
+			map[string]any{"sql": "SELECT id, name FROM categories", "format": "csv"},
+			"LIMIT 501 OFFSET 0",
+			sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Food, drinks").AddRow(2, nil),
+		))
+
+		var text string
+		require.NoError(t, json.Unmarshal(out.Rows, &text))
+		assert.Equal(t, "id,name\n1,\"Food, drinks\"\n2,\n", text)
+		assert.Equal(t, 2, out.RowCount)
+	})
+
+	t.Run("columns", func(t *testing.T) {
+		out := decodeQueryResult(t, runQuery(t, 0,
+			map[string]any{"sql": "SELECT id, name FROM categories", "format": "columns"},
+			"LIMIT 501 OFFSET 0",
+			sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Food").AddRow(2, "Rent"),
+		))
+
+		assert.JSONEq(t, `{"id":[1,2],"name":["Food","Rent"]}`, string(out.Rows))
+	})
+}
+
+func TestServer_HandleQuery_ByteBudget(t *testing.T) {
+	t.Run("truncates and continues", func(t *testing.T) {
+		// {"id":1,"name":"x"} is 19 bytes plus separator, the second row does not fit
+		out := decodeQueryResult(t, runQuery(t, 30,
+			map[string]any{"sql": "SELECT id, name FROM categories"},
+			"LIMIT 501 OFFSET 0",
+			categoryRows(3),
+		))
+
+		assert.Equal(t, 1, out.RowCount)
+		assert.Contains(t, out.Notice, "under 30 bytes")
+		assert.Contains(t, out.Notice, "no ORDER BY") // categories are unordered
+		require.NotEmpty(t, out.NextCursor)
+
+		next := decodeQueryResult(t, runQuery(t, 30,
+			map[string]any{"sql": "SELECT id, name FROM categories", "cursor": out.NextCursor},
+			"LIMIT 501 OFFSET 1",
+			categoryRows(1),
+		))
+		assert.Equal(t, 1, next.RowCount)
+	})
+
+	t.Run("single row over budget", func(t *testing.T) {
+		result := runQuery(t, 5,
+			map[string]any{"sql": "SELECT id, name FROM categories"},
+			"LIMIT 501 OFFSET 0",
+			categoryRows(1),
+		)
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "row 1 alone exceeds the response budget of 5 bytes")
+	})
+}
+
+func TestServer_HandleQuery_InvalidPaging(t *testing.T) {
+	cases := []struct {
+		name          string
+		args          map[string]any
+		expectedError string
+	}{
+		{
+			name:          "unsupported format",
+			args:          map[string]any{"sql": "SELECT id FROM tags", "format": "xml"},
+			expectedError: "unsupported format: xml",
+		},
+		{
+			name:          "page size too large",
+			args:          map[string]any{"sql": "SELECT id FROM tags", "page_size": float64(20000)},
+			expectedError: "page_size must be between 1 and 10000",
+		},
+		{
+			name:          "malformed cursor",
+			args:          map[string]any{"sql": "SELECT id FROM tags", "cursor": "!!"},
+			expectedError: "invalid cursor",
+		},
+		{
+			name:          "cursor of another query",
+			args:          map[string]any{"sql": "SELECT id FROM tags", "cursor": "Mjow"}, // "2:0"
+			expectedError: "cursor belongs to a different query",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result := runQuery(t, 0, c.args, "", nil)
+
+			assert.True(t, result.IsError)
+			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expectedError)
+		})
+	}
+}
+
+func TestServer_HandleQuery_RowBudgetNotice(t *testing.T) {
+	gormDB, mockDB, mock := testingutils.GormMock()
+	defer func() { _ = mockDB.Close() }()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SET LOCAL statement_timeout = 30000")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("LIMIT 3 OFFSET 0")).WillReturnRows(categoryRows(3))
+	mock.ExpectRollback()
+
+	server := gomcp.NewServer(&gomcp.ServerConfig{
+		DB:                gormDB,
+		Docs:              "test docs",
+		ClaimsFromContext: testClaims,
+		RateLimit:         &gomcp.RateLimiterConfig{Window: time.Minute, MaxRows: 2},
+	})
+
+	result := handleToolCall(t, server, "query", map[string]any{"sql": "SELECT id, name FROM categories"})
+	out := decodeQueryResult(t, result)
+
+	assert.Equal(t, 2, out.RowCount)
+	assert.Contains(t, out.Notice, "remaining row limit of 2 rows")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+type RateLimiterConfig struct {
+	Window   time.Duration
+	MaxCalls int // 0 disables the call limit
+	MaxRows  int // 0 disables the row limit
+}
+
+// RateLimiter enforces per token call and row budgets over fixed windows.
+// Usage is kept in memory, every replica counts its own calls.
+type RateLimiter struct {
+	cfg       *RateLimiterConfig
+	mut       sync.Mutex
+	usage     map[string]*tokenUsage
+	lastSweep time.Time
+}
+
+type tokenUsage struct {
+	windowStart time.Time
+	calls       int
+	rows        int
+}
+
+func NewRateLimiter(cfg *RateLimiterConfig) *RateLimiter {
+	return &RateLimiter{
+		cfg:   cfg,
+		usage: map[string]*tokenUsage{},
+	}
+}
+
+// Acquire counts a call of the token and returns how many rows it may still receive in the current window.
+func (l *RateLimiter) Acquire(tokenID string) (int, error) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	usage := l.current(tokenID)
+	retryIn := time.Until(usage.windowStart.Add(l.cfg.Window)).Round(time.Second)
+
+	if l.cfg.MaxCalls > 0 && usage.calls >= l.cfg.MaxCalls {
+		return 0, errors.Newf("rate limit exceeded: %d calls per %v, retry in %v", l.cfg.MaxCalls, l.cfg.Window, retryIn)
+	}
+
+	if l.cfg.MaxRows > 0 && usage.rows >= l.cfg.MaxRows {
+		return 0, errors.Newf("rate limit exceeded: %d rows per %v, retry in %v", l.cfg.MaxRows, l.cfg.Window, retryIn)
+	}
+
+	usage.calls++
+
+	if l.cfg.MaxRows == 0 {
+		return maxRows, nil
+	}
+
+	return min(l.cfg.MaxRows-usage.rows, maxRows), nil
+}
+
+// AddRows counts rows returned to the token.
+func (l *RateLimiter) AddRows(tokenID string, rows int) {
+	if rows <= 0 {
+		return
+	}
+
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	l.current(tokenID).rows += rows
+}
+
+func (l *RateLimiter) current(tokenID string) *tokenUsage {
+	now := time.Now()
+
+	l.sweep(now)
+
+	usage, ok := l.usage[tokenID]
+	if !ok || now.Sub(usage.windowStart) >= l.cfg.Window {
+		usage = &tokenUsage{windowStart: now}
+		l.usage[tokenID] = usage
+	}
+
+	return usage
+}
+
+// sweep drops tokens whose window has ended, at most once per window. They would start a new
+// window on their next call anyway, so tokens that stopped calling do not stay in memory.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.cfg.Window {
+		return
+	}
+
+	for tokenID, usage := range l.usage {
+		if now.Sub(usage.windowStart) >= l.cfg.Window {
+			delete(l.usage, tokenID)
+		}
+	}
+
+	l.lastSweep = now
+}
@@ -0,0 +1,90 @@
+package mcp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+)
+
+func TestRateLimiter_Calls(t *testing.T) {
+	limiter := gomcp.NewRateLimiter(&gomcp.RateLimiterConfig{Window: time.Minute, MaxCalls: 2})
+
+	_, err := limiter.Acquire("a")
+	require.NoError(t, err)
+	_, err = limiter.Acquire("a")
+	require.NoError(t, err)
+
+	_, err = limiter.Acquire("a")
+	assert.ErrorContains(t, err, "rate limit exceeded: 2 calls per 1m0s")
+
+	_, err = limiter.Acquire("b")
+	assert.NoError(t, err)
+}
+
+func TestRateLimiter_Rows(t *testing.T) {
+	limiter := gomcp.NewRateLimiter(&gomcp.RateLimiterConfig{Window: time.Minute, MaxRows: 10})
+
+	budget, err := limiter.Acquire("a")
+	require.NoError(t, err)
+	assert.Equal(t, 10, budget)
+
+	limiter.AddRows("a", 7)
+
+	budget, err = limiter.Acquire("a")
+	require.NoError(t, err)
+	assert.Equal(t, 3, budget)
+
+	limiter.AddRows("a", 3)
+
+	_, err = limiter.Acquire("a")
+	assert.ErrorContains(t, err, "rate limit exceeded: 10 rows per 1m0s")
+}
+
+func TestRateLimiter_WindowReset(t *testing.T) {
+	limiter := gomcp.NewRateLimiter(&gomcp.RateLimiterConfig{Window: 50 * time.Millisecond, MaxCalls: 1})
+
+	_, err := limiter.Acquire("a")
+	require.NoError(t, err)
+
+	_, err = limiter.Acquire("a")
+	require.Error(t, err)
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, err = limiter.Acquire("a")
+	assert.NoError(t, err)
+}
+
+func TestRateLimiter_EvictsIdleTokens(t *testing.T) {
+	limiter := gomcp.NewRateLimiter(&gomcp.RateLimiterConfig{Window: 50 * time.Millisecond, MaxCalls: 10})
+
+	for _, token := range []string{"a", "b", "c"} {
+		_, err := limiter.Acquire(token)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, limiter.TrackedTokens())
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, err := limiter.Acquire("d")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, limiter.TrackedTokens())
+}
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	limiter := gomcp.NewRateLimiter(&gomcp.RateLimiterConfig{Window: time.Minute})
+
+	for range 100 {
+		budget, err := limiter.Acquire("a")
+		require.NoError(t, err)
+		assert.Equal(t, 999_999, budget)
+
+		limiter.AddRows("a", 1000)
+	}
+}
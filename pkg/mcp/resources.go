@@ -0,0 +1,276 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	rulesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/rules/v1"
+	tagsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/tags/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+)
+
+const (
+	accountsResourceURI   = "context://accounts"
+	categoriesResourceURI = "context://categories"
+	tagsResourceURI       = "context://tags"
+	currenciesResourceURI = "context://currencies"
+	rulesResourceURI      = "context://rules"
+	ruleResourcePrefix    = "rule://"
+)
+
+type tagOutput struct {
+	ID    int32  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+	Icon  string `json:"icon,omitempty"`
+}
+
+type ruleOutput struct {
+	ID          int32  `json:"id"`
+	URI         string `json:"uri"`
+	Title       string `json:"title"`
+	GroupName   string `json:"group_name,omitempty"`
+	SortOrder   int32  `json:"sort_order"`
+	Enabled     bool   `json:"enabled"`
+	IsFinalRule bool   `json:"is_final_rule"`
+	Interpreter string `json:"interpreter"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+	Script      string `json:"script,omitempty"`
+}
+
+func mapRule(rule *gomoneypbv1.Rule, withScript bool) *ruleOutput {
+	out := &ruleOutput{
+		ID:          rule.Id,
+		URI:         fmt.Sprintf("%s%d", ruleResourcePrefix, rule.Id),
+		Title:       rule.Title,
+		GroupName:   rule.GroupName,
+		SortOrder:   rule.SortOrder,
+		Enabled:     rule.Enabled,
+		IsFinalRule: rule.IsFinalRule,
+		Interpreter: strings.ToLower(strings.TrimPrefix(rule.Interpreter.String(), "RULE_INTERPRETER_TYPE_")),
+	}
+
+	if rule.UpdatedAt != nil {
+		out.UpdatedAt = rule.UpdatedAt.AsTime().UTC().Format(time.RFC3339)
+	}
+
+	if withScript {
+		out.Script = rule.Script
+	}
+
+	return out
+}
+
+func (s *Server) registerResources() {
+	schemaResource := mcp.NewResource(
+		"context://schema",
+		"Database Schema",
+		mcp.WithResourceDescription("Go Money database schema documentation"),
+		mcp.WithMIMEType("text/markdown"),
+	)
+
+	s.mcpServer.AddResource(schemaResource, s.handleSchemaResource)
+
+	s.mcpServer.AddResource(mcp.NewResource(
+		accountsResourceURI,
+		"Accounts",
+		mcp.WithResourceDescription("Current accounts with type, currency and balance in account currency"),
+		mcp.WithMIMEType("application/json"),
+	), s.handleAccountsResource)
+
+	s.mcpServer.AddResource(mcp.NewResource(
+		categoriesResourceURI,
+		"Categories",
+		mcp.WithResourceDescription("All categories ordered by id"),
+		mcp.WithMIMEType("application/json"),
+	), s.handleCategoriesResource)
+
+	s.mcpServer.AddResource(mcp.NewResource(
+		tagsResourceURI,
+		"Tags",
+		mcp.WithResourceDescription("All tags with color and icon"),
+		mcp.WithMIMEType("application/json"),
+	), s.handleTagsResource)
+
+	s.mcpServer.AddResource(mcp.NewResource(
+		currenciesResourceURI,
+		"Currencies",
+		mcp.WithResourceDescription("Active currencies with their rate against the base currency"),
+		mcp.WithMIMEType("application/json"),
+	), s.handleCurrenciesResource)
+
+	s.mcpServer.AddResource(mcp.NewResource(
+		rulesResourceURI,
+		"Rules",
+		mcp.WithResourceDescription("Rules in execution order without scripts. Read rule://{id} for the script of a rule"),
+		mcp.WithMIMEType("application/json"),
+	), s.handleRulesResource)
+
+	s.mcpServer.AddResourceTemplate(mcp.NewResourceTemplate(
+		ruleResourcePrefix+"{id}",
+		"Rule",
+		mcp.WithTemplateDescription("A single rule including its Lua script"),
+		mcp.WithTemplateMIMEType("application/json"),
+	), s.handleRuleResource)
+}
+
+func (s *Server) handleSchemaResource(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      req.Params.URI,
+			MIMEType: "text/markdown",
+			Text:     s.cfg.Docs,
+		},
+	}, nil
+}
+
+func (s *Server) handleAccountsResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	accounts, err := s.cfg.AccountSvc.GetAllAccounts(queryCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list accounts")
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID < accounts[j].ID
+	})
+
+	return jsonResourceContents(req.Params.URI, lo.Map(accounts, func(account *database.Account, _ int) *accountOutput {
+		return mapAccount(account)
+	}))
+}
+
+func (s *Server) handleCategoriesResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	categories, err := s.cfg.CategorySvc.GetAllCategories(queryCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list categories")
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].ID < categories[j].ID
+	})
+
+	return jsonResourceContents(req.Params.URI, lo.Map(categories, func(category *database.Category, _ int) *categoryOutput {
+		return &categoryOutput{
+			ID:   category.ID,
+			Name: category.Name,
+		}
+	}))
+}
+
+func (s *Server) handleTagsResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	resp, err := s.cfg.TagsSvc.ListTags(queryCtx, &tagsv1.ListTagsRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tags")
+	}
+
+	tags := lo.Map(resp.Tags, func(item *tagsv1.ListTagsResponse_TagItem, _ int) *tagOutput {
+		return &tagOutput{
+			ID:    item.Tag.Id,
+			Name:  item.Tag.Name,
+			Color: item.Tag.Color,
+			Icon:  item.Tag.Icon,
+		}
+	})
+
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].ID < tags[j].ID
+	})
+
+	return jsonResourceContents(req.Params.URI, tags)
+}
+
+func (s *Server) handleCurrenciesResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	currencies, err := s.cfg.CatalogSvc.ListCurrencies(queryCtx, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list currencies")
+	}
+
+	return jsonResourceContents(req.Params.URI, lo.Map(currencies, func(record *database.Currency, _ int) *currencyOutput {
+		return mapCurrency(record)
+	}))
+}
+
+func (s *Server) handleRulesResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	resp, err := s.cfg.RulesSvc.ListRules(queryCtx, &rulesv1.ListRulesRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list rules")
+	}
+
+	return jsonResourceContents(req.Params.URI, lo.Map(resp.Rules, func(rule *gomoneypbv1.Rule, _ int) *ruleOutput {
+		return mapRule(rule, false)
+	}))
+}
+
+func (s *Server) handleRuleResource(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	ruleID, err := strconv.ParseInt(strings.TrimPrefix(req.Params.URI, ruleResourcePrefix), 10, 32)
+	if err != nil {
+		return nil, errors.Newf("invalid rule uri: %s", req.Params.URI)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	resp, err := s.cfg.RulesSvc.ListRules(queryCtx, &rulesv1.ListRulesRequest{
+		Ids: []int32{int32(ruleID)},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get rule")
+	}
+
+	if len(resp.Rules) == 0 {
+		return nil, errors.Newf("rule %d not found", ruleID)
+	}
+
+	return jsonResourceContents(req.Params.URI, mapRule(resp.Rules[0], true))
+}
+
+func jsonResourceContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to format resource")
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
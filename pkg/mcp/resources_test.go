@@ -0,0 +1,218 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	rulesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/rules/v1"
+	tagsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/tags/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+// readResource goes through the mcp-go request handling, so templates are matched the way clients see them.
+func readResource(t *testing.T, server *gomcp.Server, uri string) (*mcp.TextResourceContents, *mcp.JSONRPCError) {
+	raw, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "resources/read",
+		"params":  map[string]any{"uri": uri},
+	})
+	require.NoError(t, err)
+
+	msg := server.MCPServer().HandleMessage(context.Background(), raw)
+	if rpcErr, ok := msg.(mcp.JSONRPCError); ok {
+		return nil, &rpcErr
+	}
+
+	resp, ok := msg.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+
+	result, ok := resp.Result.(mcp.ReadResourceResult)
+	require.True(t, ok)
+	require.Len(t, result.Contents, 1)
+
+	contents, ok := result.Contents[0].(mcp.TextResourceContents)
+	require.True(t, ok)
+
+	return &contents, nil
+}
+
+func newResourceServer(t *testing.T, cfg *gomcp.ServerConfig) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	cfg.DB = gormDB
+	cfg.Docs = "test docs"
+
+	return gomcp.NewServer(cfg)
+}
+
+func TestServer_AccountsResource(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		accountSvc := NewMockAccountsService(ctrl)
+
+		accountSvc.EXPECT().GetAllAccounts(gomock.Any()).Return([]*database.Account{
+			{ID: 2, Name: "Card", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_LIABILITY, Currency: "USD", CurrentBalance: decimal.RequireFromString("-40")},
+			{ID: 1, Name: "Cash", Type: gomoneypbv1.AccountType_ACCOUNT_TYPE_ASSET, Currency: "EUR", CurrentBalance: decimal.RequireFromString("100.5")},
+		}, nil)
+
+		server := newResourceServer(t, &gomcp.ServerConfig{AccountSvc: accountSvc})
+
+		contents, rpcErr := readResource(t, server, "context://accounts")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "application/json", contents.MIMEType)
+
+		var out []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(contents.Text), &out))
+		require.Len(t, out, 2)
+		assert.EqualValues(t, 1, out[0]["id"])
+		assert.Equal(t, "asset", out[0]["type"])
+		assert.Equal(t, "100.5", out[0]["current_balance"])
+		assert.Equal(t, "liability", out[1]["type"])
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		accountSvc := NewMockAccountsService(ctrl)
+
+		accountSvc.EXPECT().GetAllAccounts(gomock.Any()).Return(nil, errors.New("db down"))
+
+		server := newResourceServer(t, &gomcp.ServerConfig{AccountSvc: accountSvc})
+
+		_, rpcErr := readResource(t, server, "context://accounts")
+		require.NotNil(t, rpcErr)
+		assert.Contains(t, rpcErr.Error.Message, "db down")
+	})
+}
+
+func TestServer_CategoriesAndTagsResources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	catSvc := NewMockCategoryService(ctrl)
+	tagsSvc := NewMockTagsService(ctrl)
+
+	catSvc.EXPECT().GetAllCategories(gomock.Any()).Return([]*database.Category{
+		{ID: 5, Name: "Travel"},
+		{ID: 3, Name: "Groceries"},
+	}, nil)
+
+	tagsSvc.EXPECT().ListTags(gomock.Any(), gomock.Any()).Return(&tagsv1.ListTagsResponse{
+		Tags: []*tagsv1.ListTagsResponse_TagItem{
+			{Tag: &gomoneypbv1.Tag{Id: 9, Name: "vacation", Color: "#ff0000"}},
+			{Tag: &gomoneypbv1.Tag{Id: 4, Name: "work", Icon: "briefcase"}},
+		},
+	}, nil)
+
+	server := newResourceServer(t, &gomcp.ServerConfig{CategorySvc: catSvc, TagsSvc: tagsSvc})
+
+	contents, rpcErr := readResource(t, server, "context://categories")
+	require.Nil(t, rpcErr)
+	assert.JSONEq(t, `[{"id":3,"name":"Groceries"},{"id":5,"name":"Travel"}]`, contents.Text)
+
+	contents, rpcErr = readResource(t, server, "context://tags")
+	require.Nil(t, rpcErr)
+	assert.JSONEq(t, `[{"id":4,"name":"work","icon":"briefcase"},{"id":9,"name":"vacation","color":"#ff0000"}]`, contents.Text)
+}
+
+func TestServer_CurrenciesResource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	catalogSvc := NewMockCurrencyCatalogService(ctrl)
+
+	catalogSvc.EXPECT().ListCurrencies(gomock.Any(), false).Return([]*database.Currency{
+		{ID: "EUR", Name: "Euro", Symbol: "€", DecimalPlaces: 2, Rate: decimal.RequireFromString("0.92"), IsActive: true},
+	}, nil)
+
+	server := newResourceServer(t, &gomcp.ServerConfig{CatalogSvc: catalogSvc})
+
+	contents, rpcErr := readResource(t, server, "context://currencies")
+	require.Nil(t, rpcErr)
+
+	var out []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(contents.Text), &out))
+	require.Len(t, out, 1)
+	assert.Equal(t, "EUR", out[0]["id"])
+	assert.Equal(t, "0.92", out[0]["rate"])
+}
+
+func TestServer_RuleResources(t *testing.T) {
+	rule := &gomoneypbv1.Rule{
+		Id:          12,
+		Title:       "Groceries",
+		Script:      `tx:categoryID(3)`,
+		Interpreter: gomoneypbv1.RuleInterpreterType_RULE_INTERPRETER_TYPE_LUA,
+		SortOrder:   10,
+		Enabled:     true,
+		GroupName:   "categories",
+	}
+
+	t.Run("list omits scripts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rulesSvc := NewMockRulesService(ctrl)
+
+		rulesSvc.EXPECT().ListRules(gomock.Any(), &rulesv1.ListRulesRequest{}).
+			Return(&rulesv1.ListRulesResponse{Rules: []*gomoneypbv1.Rule{rule}}, nil)
+
+		server := newResourceServer(t, &gomcp.ServerConfig{RulesSvc: rulesSvc})
+
+		contents, rpcErr := readResource(t, server, "context://rules")
+		require.Nil(t, rpcErr)
+
+		var out []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(contents.Text), &out))
+		require.Len(t, out, 1)
+		assert.Equal(t, "rule://12", out[0]["uri"])
+		assert.Equal(t, "lua", out[0]["interpreter"])
+		assert.NotContains(t, out[0], "script")
+	})
+
+	t.Run("single rule includes script", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rulesSvc := NewMockRulesService(ctrl)
+
+		rulesSvc.EXPECT().ListRules(gomock.Any(), &rulesv1.ListRulesRequest{Ids: []int32{12}}).
+			Return(&rulesv1.ListRulesResponse{Rules: []*gomoneypbv1.Rule{rule}}, nil)
+
+		server := newResourceServer(t, &gomcp.ServerConfig{RulesSvc: rulesSvc})
+
+		contents, rpcErr := readResource(t, server, "rule://12")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "rule://12", contents.URI)
+
+		var out map[string]any
+		require.NoError(t, json.Unmarshal([]byte(contents.Text), &out))
+		assert.Equal(t, "tx:categoryID(3)", out["script"])
+		assert.Equal(t, "categories", out["group_name"])
+	})
+
+	t.Run("rule not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		rulesSvc := NewMockRulesService(ctrl)
+
+		rulesSvc.EXPECT().ListRules(gomock.Any(), gomock.Any()).Return(&rulesv1.ListRulesResponse{}, nil)
+
+		server := newResourceServer(t, &gomcp.ServerConfig{RulesSvc: rulesSvc})
+
+		_, rpcErr := readResource(t, server, "rule://99")
+		require.NotNil(t, rpcErr)
+		assert.Contains(t, rpcErr.Error.Message, "rule 99 not found")
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		server := newResourceServer(t, &gomcp.ServerConfig{})
+
+		_, rpcErr := readResource(t, server, "rule://abc")
+		require.NotNil(t, rpcErr)
+		assert.Contains(t, rpcErr.Error.Message, "invalid rule uri")
+	})
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func (s *Server) handleListRuleModules(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	modules, err := s.cfg.RuleModulesSvc.ListModules(queryCtx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list rule modules: %v", err)), nil
+	}
+
+	if len(modules) == 0 {
+		return mcp.NewToolResultText("No rule modules found"), nil
+	}
+
+	type moduleOutput struct {
+		ID          int32  `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Script      string `json:"script"`
+	}
+
+	output := make([]*moduleOutput, 0, len(modules))
+	for _, module := range modules {
+		output = append(output, &moduleOutput{
+			ID:          module.ID,
+			Name:        module.Name,
+			Description: module.Description,
+			Script:      module.Script,
+		})
+	}
+
+	result, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleCreateRuleModule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	module, errResult := parseRuleModuleArgs(request.GetArguments())
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	created, err := s.cfg.RuleModulesSvc.CreateModule(queryCtx, module)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create rule module: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Module %q created with ID %d", created.Name, created.ID)), nil
+}
+
+func (s *Server) handleUpdateRuleModule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	id, ok := args["id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("id parameter is required"), nil
+	}
+
+	module, errResult := parseRuleModuleArgs(args)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	module.ID = int32(id)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	updated, err := s.cfg.RuleModulesSvc.UpdateModule(queryCtx, module)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to update rule module: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Module %q (ID %d) updated", updated.Name, updated.ID)), nil
+}
+
+func (s *Server) handleDeleteRuleModule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	id, ok := request.GetArguments()["id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	deleted, err := s.cfg.RuleModulesSvc.DeleteModule(queryCtx, int32(id))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to delete rule module: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Module %q (ID %d) deleted", deleted.Name, deleted.ID)), nil
+}
+
+func parseRuleModuleArgs(args map[string]any) (*database.LuaModule, *mcp.CallToolResult) {
+	name, ok := args["name"].(string)
+	if !ok || name == "" {
+		return nil, mcp.NewToolResultError("name parameter is required")
+	}
+
+	script, ok := args["script"].(string)
+	if !ok || script == "" {
+		return nil, mcp.NewToolResultError("script parameter is required")
+	}
+
+	description, _ := args["description"].(string)
+
+	return &database.LuaModule{
+		Name:        name,
+		Description: description,
+		Script:      script,
+	}, nil
+}
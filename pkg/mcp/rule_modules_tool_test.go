@@ -0,0 +1,192 @@
+package mcp_test
+
+import (
+	"context"
+	"testing"
+
+	rulesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/rules/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
+)
+
+func newRuleModulesTestServer(t *testing.T, modulesSvc *MockRuleModulesService, dryRunSvc *MockDryRunService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:             gormDB,
+		Docs:           "test docs",
+		RuleModulesSvc: modulesSvc,
+		DryRunSvc:      dryRunSvc,
+	})
+}
+
+func TestServer_HandleListRuleModules(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		modulesSvc := NewMockRuleModulesService(gomock.NewController(t))
+		modulesSvc.EXPECT().ListModules(gomock.Any()).Return([]*database.LuaModule{
+			{ID: 1, Name: "merchants", Script: "return {}"},
+		}, nil)
+
+		result := callTool(t, newRuleModulesTestServer(t, modulesSvc, nil), "list_rule_modules", nil)
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"name": "merchants"`)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		modulesSvc := NewMockRuleModulesService(gomock.NewController(t))
+		modulesSvc.EXPECT().ListModules(gomock.Any()).Return(nil, nil)
+
+		result := callTool(t, newRuleModulesTestServer(t, modulesSvc, nil), "list_rule_modules", nil)
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "No rule modules found")
+	})
+}
+
+func TestServer_HandleCreateRuleModule(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		modulesSvc := NewMockRuleModulesService(gomock.NewController(t))
+		modulesSvc.EXPECT().CreateModule(gomock.Any(), &database.LuaModule{
+			Name:        "merchants",
+			Description: "helpers",
+			Script:      "return {}",
+		}).Return(&database.LuaModule{ID: 4, Name: "merchants"}, nil)
+
+		result := callTool(t, newRuleModulesTestServer(t, modulesSvc, nil), "create_rule_module", map[string]any{
+			"name":        "merchants",
+			"description": "helpers",
+			"script":      "return {}",
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `Module "merchants" created with ID 4`)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			args     map[string]any
+			expected string
+		}{
+			{"missing name", map[string]any{"script": "return {}"}, "name parameter is required"},
+			{"missing script", map[string]any{"name": "merchants"}, "script parameter is required"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				result := callTool(t, newRuleModulesTestServer(t, NewMockRuleModulesService(gomock.NewController(t)), nil), "create_rule_module", c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		modulesSvc := NewMockRuleModulesService(gomock.NewController(t))
+		modulesSvc.EXPECT().CreateModule(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newRuleModulesTestServer(t, modulesSvc, nil), "create_rule_module", map[string]any{
+			"name":   "merchants",
+			"script": "return {}",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to create rule module")
+	})
+}
+
+func TestServer_HandleUpdateRuleModule(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		modulesSvc := NewMockRuleModulesService(gomock.NewController(t))
+		modulesSvc.EXPECT().UpdateModule(gomock.Any(), &database.LuaModule{
+			ID:     4,
+			Name:   "merchants",
+			Script: "return {}",
+		}).Return(&database.LuaModule{ID: 4, Name: "merchants"}, nil)
+
+		result := callTool(t, newRuleModulesTestServer(t, modulesSvc, nil), "update_rule_module", map[string]any{
+			"id":     float64(4),
+			"name":   "merchants",
+			"script": "return {}",
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `Module "merchants" (ID 4) updated`)
+	})
+
+	t.Run("missing id", func(t *testing.T) {
+		result := callTool(t, newRuleModulesTestServer(t, NewMockRuleModulesService(gomock.NewController(t)), nil), "update_rule_module", map[string]any{
+			"name":   "merchants",
+			"script": "return {}",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "id parameter is required")
+	})
+}
+
+func TestServer_HandleDeleteRuleModule(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		modulesSvc := NewMockRuleModulesService(gomock.NewController(t))
+		modulesSvc.EXPECT().DeleteModule(gomock.Any(), int32(4)).Return(&database.LuaModule{ID: 4, Name: "merchants"}, nil)
+
+		result := callTool(t, newRuleModulesTestServer(t, modulesSvc, nil), "delete_rule_module", map[string]any{"id": float64(4)})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `Module "merchants" (ID 4) deleted`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		modulesSvc := NewMockRuleModulesService(gomock.NewController(t))
+		modulesSvc.EXPECT().DeleteModule(gomock.Any(), int32(4)).Return(nil, assert.AnError)
+
+		result := callTool(t, newRuleModulesTestServer(t, modulesSvc, nil), "delete_rule_module", map[string]any{"id": float64(4)})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to delete rule module")
+	})
+}
+
+func TestServer_HandleDryRunRule_Modules(t *testing.T) {
+	t.Run("uncommitted modules are passed to dry run", func(t *testing.T) {
+		dryRunSvc := NewMockDryRunService(gomock.NewController(t))
+		dryRunSvc.EXPECT().DryRunRule(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, req *rulesv1.DryRunRuleRequest) (*rulesv1.DryRunRuleResponse, error) {
+				tx := &database.Transaction{}
+				applied, err := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{}).Run(ctx, req.Rule.Script, tx)
+				assert.NoError(t, err)
+				assert.Equal(t, "draft", tx.Title)
+
+				return &rulesv1.DryRunRuleResponse{RuleApplied: applied}, nil
+			})
+
+		result := callTool(t, newRuleModulesTestServer(t, nil, dryRunSvc), "dry_run_rule", map[string]any{
+			"transaction_id": float64(1),
+			"script":         `tx:title(require("merchants").name)`,
+			"modules":        map[string]any{"merchants": `return { name = "draft" }`},
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"rule_applied": true`)
+	})
+
+	t.Run("invalid module source", func(t *testing.T) {
+		result := callTool(t, newRuleModulesTestServer(t, nil, NewMockDryRunService(gomock.NewController(t))), "dry_run_rule", map[string]any{
+			"transaction_id": float64(1),
+			"script":         "return true",
+			"modules":        map[string]any{"merchants": float64(1)},
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "modules.merchants must be a string")
+	})
+}
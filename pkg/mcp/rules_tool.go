@@ -4,11 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	importv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/import/v1"
 	rulesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/rules/v1"
 	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
 )
 
 // RulesLuaAPIDoc describes the Lua runtime surface available to transaction
@@ -21,10 +26,16 @@ Each rule runs against a cloned transaction; a rule is considered "applied" when
 it mutates any tx field. Scripts must not raise errors — use early ` + "`return`" + ` to exit.
 Rule groups run in sort_order; within a group ` + "`is_final_rule=true`" + ` stops the group
 when the rule mutates the transaction.
+Shared modules from list_rule_modules load with ` + "`local m = require(\"name\")`" + `;
+dry_run_rule accepts uncommitted module sources in ` + "`modules`" + `.
 
 Globals:
   tx       — current transaction (methods below)
   helpers  — utility namespace
+  trigger  — read-only table describing why the rule runs:
+    trigger.event             "created" | "updated" | "imported" | "manual"
+    trigger.importSourceName  e.g. "IMPORT_SOURCE_REVOLUT" (imports only)
+    trigger.actor             {type="user"|"importer"|"scheduler"|"bulk"|"rule", userID, ruleID, detail} or nil
 
 Transaction API (` + "`tx:field()`" + ` = get, ` + "`tx:field(value)`" + ` = set):
 
@@ -50,6 +61,15 @@ Transaction API (` + "`tx:field()`" + ` = get, ` + "`tx:field(value)`" + ` = set
     tx:destinationAmount() / tx:destinationAmount(12.34) / tx:destinationAmount(nil)
     tx:getSourceAmountWithDecimalPlaces(2)       -- rounded to N decimals
     tx:getDestinationAmountWithDecimalPlaces(2)
+    tx:fxSourceAmount()    / tx:fxSourceAmount(10)       -- original foreign amount
+    tx:fxSourceCurrency()  / tx:fxSourceCurrency("EUR")
+    tx:sourceAmountInBaseCurrency()                      -- read-only
+    tx:destinationAmountInBaseCurrency()                 -- read-only
+
+  Extra (importer metadata, string key/value):
+    tx:getExtra("key")           -- value or nil
+    tx:setExtra("key", "value")  -- nil removes the key
+    tx:getAllExtra()             -- Lua table
 
   Tags (tag IDs are ints):
     tx:addTag(tagID)
@@ -66,6 +86,8 @@ Transaction API (` + "`tx:field()`" + ` = get, ` + "`tx:field(value)`" + ` = set
   Date/time:
     tx:transactionDateTimeSetTime(hour, minute)
     tx:transactionDateTimeAddDate(years, months, days)
+    tx:transactionDateTime()        -- unix seconds, use with os.date
+    tx:transactionDateTimeString()  -- RFC3339
 
 Helpers API:
   helpers:getAccountByID(id)
@@ -73,6 +95,20 @@ Helpers API:
     Type, AccountNumber, Iban (and a few more).
   helpers:convertCurrency("FROM", "TO", amount)
     Returns converted number, rounded to target currency decimals.
+  helpers:getAccountByName(name) / helpers:getAccountByIban(iban)
+  helpers:getTagByName(name) / helpers:getCategoryByName(name)
+    Case-insensitive lookup, returns object with .ID/.Name or nil when not found.
+  helpers:regexMatch(pattern, value)  -- bool, RE2 syntax
+  helpers:regexFind(pattern, value)   -- {full, group1, ...} or nil
+
+Schedule API (schedule rules only, ` + "`schedule`" + ` is nil in regular rules):
+  schedule.ruleID / schedule.effectiveDate  -- unix seconds
+  schedule.skip()                           -- do not create the default tx
+  schedule.newTransaction()                 -- extra tx object, same API as tx
+  schedule.findTransactions({fromDate=, toDate=, accountIDs={}, categoryIDs={},
+    transactionTypes={}, limit=100})        -- array of tx objects, changes are saved
+  schedule.balance(accountID[, at])         -- balance at date, default effectiveDate
+  schedule.balanceChange(accountID, from, to) -- balance(to) - balance(from)
 
 Nil-safety: ` + "`tx:title()`" + ` / ` + "`tx:notes()`" + ` can be nil on sparse imports — use
 ` + "`tx:title() or \"\"`" + ` before string.find.
@@ -159,6 +195,19 @@ func (s *Server) handleDryRunRule(ctx context.Context, request mcp.CallToolReque
 
 	queryCtx = database.WithContext(queryCtx, s.db)
 
+	if rawModules, ok := args["modules"].(map[string]any); ok && len(rawModules) > 0 {
+		modules := make(map[string]string, len(rawModules))
+		for name, raw := range rawModules {
+			source, isString := raw.(string)
+			if !isString {
+				return mcp.NewToolResultError(fmt.Sprintf("modules.%s must be a string", name)), nil
+			}
+			modules[name] = source
+		}
+
+		queryCtx = rules.WithModuleOverrides(queryCtx, modules)
+	}
+
 	resp, err := s.cfg.DryRunSvc.DryRunRule(queryCtx, &rulesv1.DryRunRuleRequest{
 		TransactionId: int64(transactionID),
 		Rule: &gomoneypbv1.Rule{
@@ -292,3 +341,317 @@ func (s *Server) handleUpdateRule(ctx context.Context, request mcp.CallToolReque
 
 	return mcp.NewToolResultText(fmt.Sprintf("Rule %d updated", resp.Rule.Id)), nil
 }
+
+func (s *Server) handleListRuleTestCases(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ruleID, ok := request.GetArguments()["rule_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("rule_id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	testCases, err := s.cfg.RulesSvc.ListTestCases(queryCtx, int32(ruleID))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list rule test cases: %v", err)), nil
+	}
+
+	if len(testCases) == 0 {
+		return mcp.NewToolResultText("No test cases found"), nil
+	}
+
+	result, err := json.MarshalIndent(testCases, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format test cases: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleSetRuleTestCases(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	ruleID, ok := args["rule_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("rule_id parameter is required"), nil
+	}
+
+	casesRaw, ok := args["test_cases"].([]any)
+	if !ok {
+		return mcp.NewToolResultError("test_cases parameter is required and must be an array"), nil
+	}
+
+	testCases := make([]*database.RuleTestCase, 0, len(casesRaw))
+	for i, item := range casesRaw {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("test_cases[%d] must be an object", i)), nil
+		}
+
+		name, _ := itemMap["name"].(string)
+		if name == "" {
+			return mcp.NewToolResultError(fmt.Sprintf("test_cases[%d].name is required", i)), nil
+		}
+
+		expected, ok := itemMap["expected"].(map[string]any)
+		if !ok || len(expected) == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("test_cases[%d].expected is required and must be a non-empty object", i)), nil
+		}
+
+		testCase := &database.RuleTestCase{
+			Name:     name,
+			Expected: expected,
+		}
+
+		if input, inputOk := itemMap["input"].(map[string]any); inputOk {
+			testCase.Input = input
+		}
+
+		if txID, txOk := itemMap["transaction_id"].(float64); txOk {
+			id := int64(txID)
+			testCase.TransactionID = &id
+		}
+
+		if testCase.Input == nil && testCase.TransactionID == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("test_cases[%d] requires input or transaction_id", i)), nil
+		}
+
+		testCases = append(testCases, testCase)
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	results, err := s.cfg.RulesSvc.SetTestCases(queryCtx, int32(ruleID), testCases)
+	if err != nil && !errors.Is(err, rules.ErrRuleTestsFailed) {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to set rule test cases: %v", err)), nil
+	}
+
+	formatted, fmtErr := json.MarshalIndent(results, "", "  ")
+	if fmtErr != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", fmtErr)), nil
+	}
+
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("test cases not saved: %v\n%s", err, formatted)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Saved %d test cases for rule %d\n%s", len(testCases), int32(ruleID), formatted)), nil
+}
+
+func (s *Server) handleRunRuleTests(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var ruleIDs []int32
+	if ruleID, ok := request.GetArguments()["rule_id"].(float64); ok {
+		ruleIDs = append(ruleIDs, int32(ruleID))
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	reports, err := s.cfg.RulesSvc.RunTests(queryCtx, ruleIDs)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to run rule tests: %v", err)), nil
+	}
+
+	if len(reports) == 0 {
+		return mcp.NewToolResultText("No rule test cases found"), nil
+	}
+
+	result, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleSetRuleTriggers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	ruleID, ok := args["rule_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("rule_id parameter is required"), nil
+	}
+
+	eventsRaw, _ := args["events"].([]any)
+	events := make([]database.RuleTriggerEvent, 0, len(eventsRaw))
+	for i, raw := range eventsRaw {
+		name, _ := raw.(string)
+		event, found := rules.ParseTriggerEvent(name)
+		if !found {
+			return mcp.NewToolResultError(fmt.Sprintf("events[%d] must be one of created, updated, imported, manual", i)), nil
+		}
+		events = append(events, event)
+	}
+
+	sourcesRaw, _ := args["import_sources"].([]any)
+	sources := make([]importv1.ImportSource, 0, len(sourcesRaw))
+	for i, raw := range sourcesRaw {
+		name, _ := raw.(string)
+		value, found := importv1.ImportSource_value[name]
+		if !found || value == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("import_sources[%d] must be an ImportSource name like IMPORT_SOURCE_REVOLUT", i)), nil
+		}
+		sources = append(sources, importv1.ImportSource(value))
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	rule, err := s.cfg.RulesSvc.SetTriggers(queryCtx, int32(ruleID), events, sources)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to set rule triggers: %v", err)), nil
+	}
+
+	output := struct {
+		RuleID        int32    `json:"rule_id"`
+		Events        []string `json:"events"`
+		ImportSources []string `json:"import_sources"`
+	}{
+		RuleID: rule.ID,
+	}
+
+	for _, e := range rule.TriggerEvents {
+		output.Events = append(output.Events, rules.TriggerEventName(database.RuleTriggerEvent(e)))
+	}
+
+	for _, src := range rule.ImportSources {
+		output.ImportSources = append(output.ImportSources, importv1.ImportSource(src).String())
+	}
+
+	result, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+type ruleRevisionOutput struct {
+	ID           int64     `json:"id"`
+	RuleID       int32     `json:"rule_id"`
+	Title        string    `json:"title"`
+	Script       string    `json:"script"`
+	SortOrder    int32     `json:"sort_order"`
+	Enabled      bool      `json:"enabled"`
+	IsFinalRule  bool      `json:"is_final_rule"`
+	GroupName    string    `json:"group_name,omitempty"`
+	AuthorType   string    `json:"author_type,omitempty"`
+	AuthorUserID *int32    `json:"author_user_id,omitempty"`
+	AuthorDetail *string   `json:"author_detail,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	Current      bool      `json:"current"`
+}
+
+func (s *Server) handleListRuleRevisions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	ruleID, ok := request.GetArguments()["rule_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("rule_id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	revisions, err := s.cfg.RulesSvc.ListRevisions(queryCtx, int32(ruleID))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list rule revisions: %v", err)), nil
+	}
+
+	if len(revisions) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No revisions found for rule %d", int32(ruleID))), nil
+	}
+
+	output := make([]*ruleRevisionOutput, 0, len(revisions))
+	for i, rev := range revisions {
+		item := &ruleRevisionOutput{
+			ID:           rev.ID,
+			RuleID:       rev.RuleID,
+			Title:        rev.Title,
+			Script:       rev.Script,
+			SortOrder:    rev.SortOrder,
+			Enabled:      rev.Enabled,
+			IsFinalRule:  rev.IsFinalRule,
+			GroupName:    rev.GroupName,
+			AuthorUserID: rev.AuthorUserID,
+			AuthorDetail: rev.AuthorDetail,
+			CreatedAt:    rev.CreatedAt,
+			Current:      i == 0,
+		}
+
+		if rev.AuthorType != nil {
+			item.AuthorType = rules.ActorTypeName(*rev.AuthorType)
+		}
+
+		output = append(output, item)
+	}
+
+	result, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleDiffRuleRevisions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	fromID, ok := args["from_revision_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("from_revision_id parameter is required"), nil
+	}
+
+	toID, ok := args["to_revision_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("to_revision_id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	diff, err := s.cfg.RulesSvc.DiffRevisions(queryCtx, int64(fromID), int64(toID))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to diff rule revisions: %v", err)), nil
+	}
+
+	result, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleRestoreRuleRevision(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	revisionID, ok := request.GetArguments()["revision_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("revision_id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	rule, err := s.cfg.RulesSvc.RestoreRevision(queryCtx, int64(revisionID))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to restore rule revision: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf(
+		"Rule %d restored from revision %d, new revision %d",
+		rule.ID, int64(revisionID), lo.FromPtr(rule.RevisionID),
+	)), nil
+}
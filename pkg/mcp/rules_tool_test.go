@@ -4,16 +4,20 @@ import (
 	"context"
 	"testing"
 
+	importv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/import/v1"
 	rulesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/rules/v1"
 	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
 	"github.com/cockroachdb/errors"
 	"github.com/golang/mock/gomock"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ft-t/go-money/pkg/database"
 	gomcp "github.com/ft-t/go-money/pkg/mcp"
 	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
 )
 
 func TestServer_HandleListRules_Success(t *testing.T) {
@@ -602,3 +606,310 @@ func TestServer_HandleUpdateRule_Failure(t *testing.T) {
 		})
 	}
 }
+
+func newRulesTestServer(t *testing.T, rulesSvc *MockRulesService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:       gormDB,
+		Docs:     "test docs",
+		RulesSvc: rulesSvc,
+	})
+}
+
+func callTool(t *testing.T, server *gomcp.Server, name string, args map[string]any) *mcp.CallToolResult {
+	tool := server.MCPServer().GetTool(name)
+	require.NotNil(t, tool)
+
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      name,
+			Arguments: args,
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	return result
+}
+
+func TestServer_HandleSetRuleTestCases(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().SetTestCases(gomock.Any(), int32(3), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ int32, cases []*database.RuleTestCase) ([]*rules.TestCaseResult, error) {
+				require.Len(t, cases, 1)
+				assert.Equal(t, "uber", cases[0].Name)
+				assert.EqualValues(t, 10, *cases[0].TransactionID)
+				assert.Equal(t, "UBER", cases[0].Input["title"])
+
+				return []*rules.TestCaseResult{{Name: "uber", Passed: true}}, nil
+			})
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "set_rule_test_cases", map[string]any{
+			"rule_id": float64(3),
+			"test_cases": []any{
+				map[string]any{
+					"name":           "uber",
+					"transaction_id": float64(10),
+					"input":          map[string]any{"title": "UBER"},
+					"expected":       map[string]any{"category_id": float64(5)},
+				},
+			},
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Saved 1 test cases for rule 3")
+	})
+
+	t.Run("failing cases", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().SetTestCases(gomock.Any(), int32(3), gomock.Any()).
+			Return([]*rules.TestCaseResult{{Name: "uber"}}, errors.Wrap(rules.ErrRuleTestsFailed, "1 of 1 failed: uber"))
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "set_rule_test_cases", map[string]any{
+			"rule_id": float64(3),
+			"test_cases": []any{
+				map[string]any{"name": "uber", "input": map[string]any{}, "expected": map[string]any{"title": "x"}},
+			},
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "test cases not saved")
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			args     map[string]any
+			expected string
+		}{
+			{"missing rule_id", map[string]any{}, "rule_id parameter is required"},
+			{"missing test_cases", map[string]any{"rule_id": float64(1)}, "test_cases parameter is required"},
+			{"missing name", map[string]any{"rule_id": float64(1), "test_cases": []any{map[string]any{}}}, "test_cases[0].name is required"},
+			{"missing expected", map[string]any{"rule_id": float64(1), "test_cases": []any{map[string]any{"name": "a"}}}, "test_cases[0].expected is required"},
+			{"missing input", map[string]any{"rule_id": float64(1), "test_cases": []any{map[string]any{"name": "a", "expected": map[string]any{"title": "x"}}}}, "requires input or transaction_id"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				result := callTool(t, newRulesTestServer(t, NewMockRulesService(gomock.NewController(t))), "set_rule_test_cases", c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+}
+
+func TestServer_HandleRunRuleTests(t *testing.T) {
+	t.Run("all rules", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().RunTests(gomock.Any(), []int32(nil)).
+			Return([]*rules.RuleTestReport{{RuleID: 1, RuleTitle: "uber", Passed: true}}, nil)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "run_rule_tests", map[string]any{})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"rule_title": "uber"`)
+	})
+
+	t.Run("single rule without cases", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().RunTests(gomock.Any(), []int32{2}).Return(nil, nil)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "run_rule_tests", map[string]any{"rule_id": float64(2)})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "No rule test cases found")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().RunTests(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "run_rule_tests", map[string]any{})
+
+		assert.True(t, result.IsError)
+	})
+}
+
+func TestServer_HandleListRuleTestCases(t *testing.T) {
+	rulesSvc := NewMockRulesService(gomock.NewController(t))
+	rulesSvc.EXPECT().ListTestCases(gomock.Any(), int32(4)).
+		Return([]*database.RuleTestCase{{ID: 1, RuleID: 4, Name: "uber"}}, nil)
+
+	result := callTool(t, newRulesTestServer(t, rulesSvc), "list_rule_test_cases", map[string]any{"rule_id": float64(4)})
+
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "uber")
+}
+
+func TestServer_HandleSetRuleTriggers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().SetTriggers(gomock.Any(), int32(3),
+			[]database.RuleTriggerEvent{database.RuleTriggerEventImported},
+			[]importv1.ImportSource{importv1.ImportSource_IMPORT_SOURCE_REVOLUT},
+		).Return(&database.Rule{
+			ID:            3,
+			TriggerEvents: []int32{int32(database.RuleTriggerEventImported)},
+			ImportSources: []int32{int32(importv1.ImportSource_IMPORT_SOURCE_REVOLUT)},
+		}, nil)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "set_rule_triggers", map[string]any{
+			"rule_id":        float64(3),
+			"events":         []any{"imported"},
+			"import_sources": []any{"IMPORT_SOURCE_REVOLUT"},
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"imported"`)
+		assert.Contains(t, text, `"IMPORT_SOURCE_REVOLUT"`)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			args     map[string]any
+			expected string
+		}{
+			{"missing rule_id", map[string]any{}, "rule_id parameter is required"},
+			{"invalid event", map[string]any{"rule_id": float64(1), "events": []any{"deleted"}}, "events[0] must be one of"},
+			{"invalid source", map[string]any{"rule_id": float64(1), "import_sources": []any{"IMPORT_SOURCE_UNSPECIFIED"}}, "import_sources[0] must be"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				result := callTool(t, newRulesTestServer(t, NewMockRulesService(gomock.NewController(t))), "set_rule_triggers", c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().SetTriggers(gomock.Any(), int32(3), gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "set_rule_triggers", map[string]any{"rule_id": float64(3)})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to set rule triggers")
+	})
+}
+
+func TestServer_HandleListRuleRevisions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().ListRevisions(gomock.Any(), int32(3)).Return([]*database.RuleRevision{
+			{ID: 11, RuleID: 3, Title: "v2", AuthorType: lo.ToPtr(database.TransactionHistoryActorTypeUser)},
+			{ID: 10, RuleID: 3, Title: "v1"},
+		}, nil)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "list_rule_revisions", map[string]any{"rule_id": float64(3)})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"author_type": "user"`)
+		assert.Contains(t, text, `"current": true`)
+		assert.Contains(t, text, `"title": "v1"`)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().ListRevisions(gomock.Any(), int32(3)).Return(nil, nil)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "list_rule_revisions", map[string]any{"rule_id": float64(3)})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "No revisions found for rule 3")
+	})
+
+	t.Run("missing rule_id", func(t *testing.T) {
+		result := callTool(t, newRulesTestServer(t, NewMockRulesService(gomock.NewController(t))), "list_rule_revisions", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "rule_id parameter is required")
+	})
+}
+
+func TestServer_HandleDiffRuleRevisions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().DiffRevisions(gomock.Any(), int64(10), int64(11)).Return(&rules.RevisionDiff{
+			RuleID:         3,
+			FromRevisionID: 10,
+			ToRevisionID:   11,
+			Changes:        []*rules.FieldChange{{Field: "title", From: "v1", To: "v2"}},
+			ScriptDiff:     "-a\n+b\n",
+		}, nil)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "diff_rule_revisions", map[string]any{
+			"from_revision_id": float64(10),
+			"to_revision_id":   float64(11),
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"field": "title"`)
+		assert.Contains(t, text, `"script_diff"`)
+	})
+
+	t.Run("missing to_revision_id", func(t *testing.T) {
+		result := callTool(t, newRulesTestServer(t, NewMockRulesService(gomock.NewController(t))), "diff_rule_revisions", map[string]any{
+			"from_revision_id": float64(10),
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "to_revision_id parameter is required")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().DiffRevisions(gomock.Any(), int64(10), int64(11)).Return(nil, assert.AnError)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "diff_rule_revisions", map[string]any{
+			"from_revision_id": float64(10),
+			"to_revision_id":   float64(11),
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to diff rule revisions")
+	})
+}
+
+func TestServer_HandleRestoreRuleRevision(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().RestoreRevision(gomock.Any(), int64(10)).Return(&database.Rule{
+			ID:         3,
+			RevisionID: lo.ToPtr(int64(12)),
+		}, nil)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "restore_rule_revision", map[string]any{"revision_id": float64(10)})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Rule 3 restored from revision 10, new revision 12")
+	})
+
+	t.Run("tests failed", func(t *testing.T) {
+		rulesSvc := NewMockRulesService(gomock.NewController(t))
+		rulesSvc.EXPECT().RestoreRevision(gomock.Any(), int64(10)).Return(nil, rules.ErrRuleTestsFailed)
+
+		result := callTool(t, newRulesTestServer(t, rulesSvc), "restore_rule_revision", map[string]any{"revision_id": float64(10)})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "rule test cases failed")
+	})
+
+	t.Run("missing revision_id", func(t *testing.T) {
+		result := callTool(t, newRulesTestServer(t, NewMockRulesService(gomock.NewController(t))), "restore_rule_revision", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "revision_id parameter is required")
+	})
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+var scheduleRunTriggerNames = map[database.ScheduleRunTrigger]string{
+	database.ScheduleRunTriggerCron:    "cron",
+	database.ScheduleRunTriggerCatchUp: "catch_up",
+	database.ScheduleRunTriggerManual:  "manual",
+}
+
+var scheduleRunStatusNames = map[database.ScheduleRunStatus]string{
+	database.ScheduleRunStatusRunning: "running",
+	database.ScheduleRunStatusSuccess: "success",
+	database.ScheduleRunStatusFailed:  "failed",
+}
+
+type scheduleRunOutput struct {
+	ID             int64      `json:"id"`
+	ScheduleRuleID int32      `json:"schedule_rule_id"`
+	Trigger        string     `json:"trigger"`
+	Status         string     `json:"status"`
+	EffectiveDate  time.Time  `json:"effective_date"`
+	StartedAt      time.Time  `json:"started_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+	Error          *string    `json:"error,omitempty"`
+	TransactionIDs []int64    `json:"transaction_ids,omitempty"`
+}
+
+func mapScheduleRun(run *database.ScheduleRuleRun) *scheduleRunOutput {
+	return &scheduleRunOutput{
+		ID:             run.ID,
+		ScheduleRuleID: run.ScheduleRuleID,
+		Trigger:        scheduleRunTriggerNames[run.Trigger],
+		Status:         scheduleRunStatusNames[run.Status],
+		EffectiveDate:  run.EffectiveDate,
+		StartedAt:      run.StartedAt,
+		FinishedAt:     run.FinishedAt,
+		Error:          run.Error,
+		TransactionIDs: run.TransactionIDs,
+	}
+}
+
+func (s *Server) handleRunScheduleRule(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	ruleID, ok := args["rule_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("rule_id parameter is required"), nil
+	}
+
+	var effectiveDate time.Time
+	if dateStr, _ := args["effective_date"].(string); dateStr != "" {
+		parsed, err := time.Parse(time.RFC3339, dateStr)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid effective_date: %v", err)), nil
+		}
+
+		effectiveDate = parsed
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	run, err := s.cfg.ScheduleSvc.RunNow(queryCtx, int32(ruleID), effectiveDate)
+	if run == nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to run schedule rule: %v", err)), nil
+	}
+
+	result, fmtErr := json.MarshalIndent(mapScheduleRun(run), "", "  ")
+	if fmtErr != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", fmtErr)), nil
+	}
+
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("schedule rule run failed: %v\n%s", err, result)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
+
+func (s *Server) handleListScheduleRuleRuns(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	ruleID, ok := args["rule_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("rule_id parameter is required"), nil
+	}
+
+	limit, _ := args["limit"].(float64)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	runs, err := s.cfg.ScheduleSvc.ListRuns(queryCtx, int32(ruleID), int(limit))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list schedule rule runs: %v", err)), nil
+	}
+
+	if len(runs) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("No runs found for schedule rule %d", int32(ruleID))), nil
+	}
+
+	output := make([]*scheduleRunOutput, 0, len(runs))
+	for _, run := range runs {
+		output = append(output, mapScheduleRun(run))
+	}
+
+	result, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to format result: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(result)), nil
+}
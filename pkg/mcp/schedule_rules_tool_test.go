@@ -0,0 +1,146 @@
+package mcp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+func newScheduleTestServer(t *testing.T, scheduleSvc *MockScheduleRulesService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:          gormDB,
+		Docs:        "test docs",
+		ScheduleSvc: scheduleSvc,
+	})
+}
+
+func TestServer_HandleRunScheduleRule(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		effectiveDate := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		scheduleSvc := NewMockScheduleRulesService(gomock.NewController(t))
+		scheduleSvc.EXPECT().RunNow(gomock.Any(), int32(5), effectiveDate).Return(&database.ScheduleRuleRun{
+			ID:             1,
+			ScheduleRuleID: 5,
+			Trigger:        database.ScheduleRunTriggerManual,
+			Status:         database.ScheduleRunStatusSuccess,
+			EffectiveDate:  effectiveDate,
+			TransactionIDs: []int64{77},
+		}, nil)
+
+		result := callTool(t, newScheduleTestServer(t, scheduleSvc), "run_schedule_rule", map[string]any{
+			"rule_id":        float64(5),
+			"effective_date": "2026-03-01T00:00:00Z",
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"trigger": "manual"`)
+		assert.Contains(t, text, `"status": "success"`)
+		assert.Contains(t, text, "77")
+	})
+
+	t.Run("defaults to now", func(t *testing.T) {
+		scheduleSvc := NewMockScheduleRulesService(gomock.NewController(t))
+		scheduleSvc.EXPECT().RunNow(gomock.Any(), int32(5), time.Time{}).
+			Return(&database.ScheduleRuleRun{ID: 1}, nil)
+
+		result := callTool(t, newScheduleTestServer(t, scheduleSvc), "run_schedule_rule", map[string]any{"rule_id": float64(5)})
+
+		assert.False(t, result.IsError)
+	})
+
+	t.Run("script failure returns recorded run", func(t *testing.T) {
+		scheduleSvc := NewMockScheduleRulesService(gomock.NewController(t))
+		scheduleSvc.EXPECT().RunNow(gomock.Any(), int32(5), gomock.Any()).Return(&database.ScheduleRuleRun{
+			ID:     1,
+			Status: database.ScheduleRunStatusFailed,
+			Error:  lo.ToPtr("boom"),
+		}, assert.AnError)
+
+		result := callTool(t, newScheduleTestServer(t, scheduleSvc), "run_schedule_rule", map[string]any{"rule_id": float64(5)})
+
+		assert.True(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, "schedule rule run failed")
+		assert.Contains(t, text, `"status": "failed"`)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			args     map[string]any
+			expected string
+		}{
+			{"missing rule_id", map[string]any{}, "rule_id parameter is required"},
+			{"invalid date", map[string]any{"rule_id": float64(5), "effective_date": "2026-03-01"}, "invalid effective_date"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				result := callTool(t, newScheduleTestServer(t, NewMockScheduleRulesService(gomock.NewController(t))), "run_schedule_rule", c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+
+	t.Run("rule not found", func(t *testing.T) {
+		scheduleSvc := NewMockScheduleRulesService(gomock.NewController(t))
+		scheduleSvc.EXPECT().RunNow(gomock.Any(), int32(5), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newScheduleTestServer(t, scheduleSvc), "run_schedule_rule", map[string]any{"rule_id": float64(5)})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to run schedule rule")
+	})
+}
+
+func TestServer_HandleListScheduleRuleRuns(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		scheduleSvc := NewMockScheduleRulesService(gomock.NewController(t))
+		scheduleSvc.EXPECT().ListRuns(gomock.Any(), int32(5), 10).Return([]*database.ScheduleRuleRun{
+			{ID: 2, ScheduleRuleID: 5, Trigger: database.ScheduleRunTriggerCatchUp, Status: database.ScheduleRunStatusSuccess},
+		}, nil)
+
+		result := callTool(t, newScheduleTestServer(t, scheduleSvc), "list_schedule_rule_runs", map[string]any{
+			"rule_id": float64(5),
+			"limit":   float64(10),
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"trigger": "catch_up"`)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		scheduleSvc := NewMockScheduleRulesService(gomock.NewController(t))
+		scheduleSvc.EXPECT().ListRuns(gomock.Any(), int32(5), 0).Return(nil, nil)
+
+		result := callTool(t, newScheduleTestServer(t, scheduleSvc), "list_schedule_rule_runs", map[string]any{"rule_id": float64(5)})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "No runs found for schedule rule 5")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		scheduleSvc := NewMockScheduleRulesService(gomock.NewController(t))
+		scheduleSvc.EXPECT().ListRuns(gomock.Any(), int32(5), 0).Return(nil, assert.AnError)
+
+		result := callTool(t, newScheduleTestServer(t, scheduleSvc), "list_schedule_rule_runs", map[string]any{"rule_id": float64(5)})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to list schedule rule runs")
+	})
+}
@@ -4,49 +4,85 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/ft-t/go-money/pkg/auth"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"gorm.io/gorm"
 )
 
 type Server struct {
-	mcpServer  *server.MCPServer
-	httpServer *server.StreamableHTTPServer
-	db         *gorm.DB
-	cfg        *ServerConfig
+	mcpServer   *server.MCPServer
+	httpServer  *server.StreamableHTTPServer
+	db          *gorm.DB
+	cfg         *ServerConfig
+	queryGuard  *QueryGuard
+	rateLimiter *RateLimiter
 }
 
 type ServerConfig struct {
-	DB             *gorm.DB
-	Docs           string
-	CategorySvc    CategoryService
-	RulesSvc       RulesService
-	DryRunSvc      DryRunService
-	TagsSvc        TagsService
-	TransactionSvc TransactionService
-	CurrencySvc    CurrencyConverterService
+	DB              *gorm.DB
+	Docs            string
+	CategorySvc     CategoryService
+	RulesSvc        RulesService
+	RuleModulesSvc  RuleModulesService
+	ScheduleSvc     ScheduleRulesService
+	JobsSvc         JobsService
+	AccountSvc      AccountsService
+	LoanSvc         LoansService
+	InvestmentSvc   InvestmentsService
+	AnalyticsSvc    AnalyticsService
+	DryRunSvc       DryRunService
+	TagsSvc         TagsService
+	TransactionSvc  TransactionService
+	HistorySvc      TransactionHistoryService
+	ImportSvc       ImportService
+	CurrencySvc     CurrencyConverterService
+	CatalogSvc      CurrencyCatalogService
+	RateOverrideSvc RateOverridesService
+	SuggestionSvc   SuggestionsService // optional, adds suggestions to parse_import rows when set
+	EventFeedSvc    EventFeedService
+	WebhookSvc      WebhooksService
+	Location        *time.Location // household timezone, used for "today" defaults
+
+	QueryRole             string        // optional restricted role the query tool switches to with SET LOCAL ROLE
+	QueryStatementTimeout time.Duration // defaults to queryTimeout
+	QueryExtraTables      []string
+	QueryExtraFunctions   []string
+	QueryMaxResponseBytes int // byte budget of one query tool page, defaults to defaultQueryMaxBytes
+
+	AuditSvc          AuditService                             // records every tool call when set
+	ClaimsFromContext func(ctx context.Context) auth.JwtClaims // claims of the token authenticating the request
+	RateLimit         *RateLimiterConfig                       // per-token limits, nil disables them
 }
 
 func NewServer(cfg *ServerConfig) *Server {
-	mcpServer := server.NewMCPServer(
+	s := &Server{
+		db:         cfg.DB,
+		cfg:        cfg,
+		queryGuard: NewQueryGuard(cfg.QueryExtraTables, cfg.QueryExtraFunctions),
+	}
+
+	if cfg.RateLimit != nil {
+		s.rateLimiter = NewRateLimiter(cfg.RateLimit)
+	}
+
+	s.mcpServer = server.NewMCPServer(
 		"go-money",
 		"1.0.0",
 		server.WithToolCapabilities(true),
 		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithToolHandlerMiddleware(s.auditMiddleware), // outermost, so recovered panics are audited too
 		server.WithRecovery(),
 	)
 
-	s := &Server{
-		mcpServer: mcpServer,
-		db:        cfg.DB,
-		cfg:       cfg,
-	}
-
 	s.registerTools()
 	s.registerResources()
+	s.registerPrompts()
 
-	s.httpServer = server.NewStreamableHTTPServer(mcpServer)
+	s.httpServer = server.NewStreamableHTTPServer(s.mcpServer)
 
 	return s
 }
@@ -57,12 +93,246 @@ func (s *Server) registerTools() {
 		mcp.WithDescription(fmt.Sprintf("Run a read-only SQL query against the Go Money database. Schema: %v", s.cfg.Docs)),
 		mcp.WithString(
 			"sql",
-			mcp.Description("The SQL SELECT query to execute"),
+			mcp.Description("The SQL SELECT query to execute. Pages are read by offset, so when paging end it with an ORDER BY on a unique column (e.g. ORDER BY date, id); without one pages may repeat or skip rows"),
 			mcp.Required(),
 		),
+		mcp.WithNumber(
+			"page_size",
+			mcp.Description(fmt.Sprintf("Rows per page, 1-%d, default %d", maxQueryPageSize, defaultQueryPageSize)),
+		),
+		mcp.WithString(
+			"cursor",
+			mcp.Description("next_cursor of the previous page, the sql must be unchanged. The cursor is an offset: rows inserted or deleted before it between calls shift the following pages"),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("json (array of row objects, default), columns (object of column value arrays) or csv (string with header line)"),
+			mcp.Enum(queryFormatJSON, queryFormatColumns, queryFormatCSV),
+		),
 	)
 	s.mcpServer.AddTool(queryTool, s.handleQuery)
 
+	listAccountsTool := mcp.NewTool(
+		"list_accounts",
+		mcp.WithDescription("List accounts with type, currency and current balance in account currency."),
+		mcp.WithArray(
+			"types",
+			mcp.Description("Limit to account types: asset, liability, expense, income, adjustment"),
+		),
+	)
+	s.mcpServer.AddTool(listAccountsTool, s.handleListAccounts)
+
+	getAccountBalanceHistoryTool := mcp.NewTool(
+		"get_account_balance_history",
+		mcp.WithDescription("Get end of day balances of an account from daily_stat, in account currency. Week and month intervals return the last recorded day of every period. Investment accounts also return the market value of holdings."),
+		mcp.WithNumber(
+			"account_id",
+			mcp.Description("The ID of the account"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"from",
+			mcp.Description("First day in YYYY-MM-DD format, defaults to 30 days before to"),
+		),
+		mcp.WithString(
+			"to",
+			mcp.Description("Last day in YYYY-MM-DD format, defaults to today"),
+		),
+		mcp.WithString(
+			"interval",
+			mcp.Description("day (default), week or month"),
+		),
+	)
+	s.mcpServer.AddTool(getAccountBalanceHistoryTool, s.handleGetAccountBalanceHistory)
+
+	listCategoriesTool := mcp.NewTool(
+		"list_categories",
+		mcp.WithDescription("List all categories"),
+	)
+	s.mcpServer.AddTool(listCategoriesTool, s.handleListCategories)
+
+	searchTransactionsTool := mcp.NewTool(
+		"search_transactions",
+		mcp.WithDescription("Search transactions, newest first unless sort is given. All filters are optional and combined with AND. Amounts are in transaction currency. Returns one page of transactions, next_cursor when there are more and the total count of matches on the first page."),
+		mcp.WithString("text", mcp.Description("Search query. Words match title, notes, reference numbers, extra values, category and account names by prefix, \"quoted phrase\" keeps word order. Filters: category:Food,none tag:trip account:\"Revolut EUR\" type:expense ref:INV-1 amount>100 (>, >=, <, <=, :) before:/after:/on:YYYY-MM-DD; prefix a term with - to exclude it")),
+		mcp.WithString("from", mcp.Description("First day in YYYY-MM-DD format, household timezone")),
+		mcp.WithString("to", mcp.Description("Last day in YYYY-MM-DD format, household timezone")),
+		mcp.WithArray("account_ids", mcp.Description("Transactions with any of these accounts as source or destination")),
+		mcp.WithArray("source_account_ids", mcp.Description("Transactions from any of these accounts")),
+		mcp.WithArray("destination_account_ids", mcp.Description("Transactions to any of these accounts")),
+		mcp.WithArray("category_ids", mcp.Description("Transactions in any of these categories")),
+		mcp.WithArray("tag_ids", mcp.Description("Transactions with any of these tags")),
+		mcp.WithArray("types", mcp.Description("Transaction types: expense, income, transfer, adjustment, reversal")),
+		mcp.WithString("amount_from", mcp.Description("Minimum amount as decimal string")),
+		mcp.WithString("amount_to", mcp.Description("Maximum amount as decimal string")),
+		mcp.WithNumber("limit", mcp.Description("Page size, 1-500, default 50")),
+		mcp.WithArray("sort", mcp.Description("Sort keys, each \"field\" or \"field asc|desc\", ascending by default. Fields: date, created_at, amount, base_amount, title, category, account (source account name). Default [\"date desc\"]")),
+		mcp.WithString("cursor", mcp.Description("next_cursor of the previous page, pass the same filters and sort")),
+		mcp.WithBoolean("include_count", mcp.Description("Return total_count of all matches, default true without cursor and false with it")),
+		mcp.WithNumber("skip", mcp.Description("Number of transactions to skip, prefer cursor for deep pages")),
+	)
+	s.mcpServer.AddTool(searchTransactionsTool, s.handleSearchTransactions)
+
+	getTransactionHistoryTool := mcp.NewTool(
+		"get_transaction_history",
+		mcp.WithDescription("Get the audit trail of a transaction: who or what created, updated, deleted it or applied a rule, with the changed fields."),
+		mcp.WithNumber(
+			"transaction_id",
+			mcp.Description("The ID of the transaction"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean(
+			"include_snapshot",
+			mcp.Description("Include the full transaction snapshot of every event, default false"),
+		),
+	)
+	s.mcpServer.AddTool(getTransactionHistoryTool, s.handleGetTransactionHistory)
+
+	listTransactionEventsTool := mcp.NewTool(
+		"list_transaction_events",
+		mcp.WithDescription("Change feed of all transactions: created, updated, deleted and rule_applied events oldest first, after a cursor. Pass next_cursor of the previous call as after_id to resume; wait_seconds turns the call into a long poll that returns as soon as new events arrive."),
+		mcp.WithNumber("after_id", mcp.Description("Cursor, next_cursor of the previous call, default 0 for the oldest")),
+		mcp.WithArray("event_types", mcp.Description("Event types: created, updated, deleted, rule_applied; default all")),
+		mcp.WithNumber("limit", mcp.Description("Number of events, 1-500, default 50")),
+		mcp.WithNumber("wait_seconds", mcp.Description("Wait up to this many seconds for new events when there are none, 0-25, default 0")),
+		mcp.WithBoolean("include_snapshot", mcp.Description("Include the full transaction snapshot of every event, default false")),
+	)
+	s.mcpServer.AddTool(listTransactionEventsTool, s.handleListTransactionEvents)
+
+	debitsCreditsSummaryTool := mcp.NewTool(
+		"debits_credits_summary",
+		mcp.WithDescription("Get count and total of debits and credits per account from the double entry ledger, in base currency."),
+		mcp.WithArray(
+			"account_ids",
+			mcp.Description("Accounts to summarize"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"from",
+			mcp.Description("First day in YYYY-MM-DD format, household timezone"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"to",
+			mcp.Description("Last day in YYYY-MM-DD format, household timezone"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(debitsCreditsSummaryTool, s.handleDebitsCreditsSummary)
+
+	importArgs := []mcp.ToolOption{
+		mcp.WithString(
+			"source",
+			mcp.Description("ImportSource name: IMPORT_SOURCE_FIREFLY, IMPORT_SOURCE_PRIVATE_24, IMPORT_SOURCE_MONOBANK, IMPORT_SOURCE_MBANK, IMPORT_SOURCE_REVOLUT or IMPORT_SOURCE_BNP_PARIBAS_POLSKA"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"files",
+			mcp.Description("Statement files, one string per file"),
+			mcp.Required(),
+		),
+		mcp.WithString("encoding", mcp.Description("base64 (default) or text for CSV files passed as is; xlsx and other binary files must be base64")),
+		mcp.WithBoolean("treat_dates_as_utc", mcp.Description("Firefly only: keep the wall clock time of dates and drop their UTC offset, default false")),
+		mcp.WithBoolean("skip_duplicate_reference_check", mcp.Description("Allow repeated reference numbers inside the files by suffixing them, default false")),
+	}
+
+	parseImportTool := mcp.NewTool(
+		"parse_import",
+		append([]mcp.ToolOption{
+			mcp.WithDescription("Parse bank statement files without saving anything. Returns the transactions that would be created, duplicate_transaction_id for rows that already exist, unparsed rows with the raw data in title and notes and suggested_categories / suggested_tags learned from history for new rows without a category."),
+		}, importArgs...)...,
+	)
+	s.mcpServer.AddTool(parseImportTool, s.handleParseImport)
+
+	commitImportTool := mcp.NewTool(
+		"commit_import",
+		append([]mcp.ToolOption{
+			mcp.WithDescription("Import bank statement files. Duplicates are skipped, rules with the imported trigger run for every new transaction. Call parse_import with the same files first."),
+			mcp.WithBoolean("skip_validation_errors", mcp.Description("Skip rows that fail validation instead of failing the whole import, default false")),
+		}, importArgs...)...,
+	)
+	s.mcpServer.AddTool(commitImportTool, s.handleCommitImport)
+
+	suggestTransactionCategoriesTool := mcp.NewTool(
+		"suggest_transaction_categories",
+		mcp.WithDescription("Suggest categories and tags for saved transactions, learned from already categorized transactions with similar titles, counterparty, amount and weekday. Candidates are ranked with a confidence between 0 and 1; nothing is changed, apply them with bulk_set_transaction_category."),
+		mcp.WithArray("transaction_ids", mcp.Description("Transaction ids, up to 500"), mcp.Required()),
+	)
+	s.mcpServer.AddTool(suggestTransactionCategoriesTool, s.handleSuggestTransactionCategories)
+
+	listMcpAuditLogTool := mcp.NewTool(
+		"list_mcp_audit_log",
+		mcp.WithDescription("List MCP tool calls, newest first: token jti, tool, status, error, returned rows and duration. All filters are optional."),
+		mcp.WithString("token_id", mcp.Description("Only calls made with this token jti")),
+		mcp.WithString("tool", mcp.Description("Only calls of this tool")),
+		mcp.WithArray("statuses", mcp.Description("Statuses: success, error, rejected (refused by the rate limiter)")),
+		mcp.WithString("from", mcp.Description("First day in YYYY-MM-DD format, household timezone")),
+		mcp.WithString("to", mcp.Description("Last day in YYYY-MM-DD format, household timezone")),
+		mcp.WithNumber("limit", mcp.Description("Number of calls, 1-500, default 50")),
+		mcp.WithBoolean("include_arguments", mcp.Description("Include the arguments of every call, default false")),
+	)
+	s.mcpServer.AddTool(listMcpAuditLogTool, s.handleListMcpAuditLog)
+
+	listWebhooksTool := mcp.NewTool(
+		"list_webhooks",
+		mcp.WithDescription("List webhooks receiving transaction events. Secrets are not returned."),
+	)
+	s.mcpServer.AddTool(listWebhooksTool, s.handleListWebhooks)
+
+	createWebhookTool := mcp.NewTool(
+		"create_webhook",
+		mcp.WithDescription("Create a webhook: every new transaction event is POSTed as JSON, signed with HMAC-SHA256 of the secret, and retried with backoff until the receiver answers 2xx. Existing history is not sent. Returns the secret."),
+		mcp.WithString("name", mcp.Description("Display name"), mcp.Required()),
+		mcp.WithString("url", mcp.Description("http or https URL receiving POST requests"), mcp.Required()),
+		mcp.WithString("secret", mcp.Description("Signing secret, at least 16 characters, generated when omitted")),
+		mcp.WithArray("event_types", mcp.Description("Event types: created, updated, deleted, rule_applied; default all")),
+		mcp.WithBoolean("enabled", mcp.Description("Default true")),
+	)
+	s.mcpServer.AddTool(createWebhookTool, s.handleCreateWebhook)
+
+	updateWebhookTool := mcp.NewTool(
+		"update_webhook",
+		mcp.WithDescription("Update a webhook, only the given fields change. Pass an empty event_types array to receive all events."),
+		mcp.WithNumber("id", mcp.Description("Webhook ID"), mcp.Required()),
+		mcp.WithString("name", mcp.Description("Display name")),
+		mcp.WithString("url", mcp.Description("http or https URL receiving POST requests")),
+		mcp.WithString("secret", mcp.Description("New signing secret, at least 16 characters")),
+		mcp.WithArray("event_types", mcp.Description("Event types: created, updated, deleted, rule_applied")),
+		mcp.WithBoolean("enabled", mcp.Description("Disabled webhooks keep their pending deliveries until enabled again")),
+	)
+	s.mcpServer.AddTool(updateWebhookTool, s.handleUpdateWebhook)
+
+	deleteWebhookTool := mcp.NewTool(
+		"delete_webhook",
+		mcp.WithDescription("Delete a webhook, its pending deliveries are failed"),
+		mcp.WithNumber("id", mcp.Description("Webhook ID"), mcp.Required()),
+	)
+	s.mcpServer.AddTool(deleteWebhookTool, s.handleDeleteWebhook)
+
+	pingWebhookTool := mcp.NewTool(
+		"ping_webhook",
+		mcp.WithDescription("Send a signed ping event to a webhook right away and return the delivery with the response code or error. Pings are not retried."),
+		mcp.WithNumber("id", mcp.Description("Webhook ID"), mcp.Required()),
+	)
+	s.mcpServer.AddTool(pingWebhookTool, s.handlePingWebhook)
+
+	listWebhookDeliveriesTool := mcp.NewTool(
+		"list_webhook_deliveries",
+		mcp.WithDescription("Webhook delivery log, newest first: event, status, attempts, response code, error and next retry."),
+		mcp.WithNumber("webhook_id", mcp.Description("Only deliveries of this webhook")),
+		mcp.WithArray("statuses", mcp.Description("Statuses: pending, delivered, failed")),
+		mcp.WithNumber("limit", mcp.Description("Number of deliveries, 1-500, default 50")),
+		mcp.WithBoolean("include_payload", mcp.Description("Include the request body, default false")),
+	)
+	s.mcpServer.AddTool(listWebhookDeliveriesTool, s.handleListWebhookDeliveries)
+
+	retryWebhookDeliveryTool := mcp.NewTool(
+		"retry_webhook_delivery",
+		mcp.WithDescription("Queue a delivery again with a fresh attempt budget, e.g. a failed one after the receiver was fixed"),
+		mcp.WithNumber("id", mcp.Description("Delivery ID"), mcp.Required()),
+	)
+	s.mcpServer.AddTool(retryWebhookDeliveryTool, s.handleRetryWebhookDelivery)
+
 	bulkSetTransactionCategoryTool := mcp.NewTool(
 		"bulk_set_transaction_category",
 		mcp.WithDescription("Set or clear categories for multiple transactions in a single call"),
@@ -124,6 +394,10 @@ func (s *Server) registerTools() {
 			"title",
 			mcp.Description("Title/name for the rule being tested"),
 		),
+		mcp.WithObject(
+			"modules",
+			mcp.Description("Optional uncommitted module sources keyed by name, e.g. {\"merchants\": \"local M = {} ... return M\"}. They override stored modules for this run only."),
+		),
 	)
 	s.mcpServer.AddTool(dryRunRuleTool, s.handleDryRunRule)
 
@@ -196,6 +470,528 @@ func (s *Server) registerTools() {
 	)
 	s.mcpServer.AddTool(updateRuleTool, s.handleUpdateRule)
 
+	listRuleTestCasesTool := mcp.NewTool(
+		"list_rule_test_cases",
+		mcp.WithDescription("List stored test cases of a rule. Each case has an input transaction snapshot and/or transaction_id plus expected snapshot fields after execution."),
+		mcp.WithNumber(
+			"rule_id",
+			mcp.Description("The ID of the rule"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(listRuleTestCasesTool, s.handleListRuleTestCases)
+
+	setRuleTestCasesTool := mcp.NewTool(
+		"set_rule_test_cases",
+		mcp.WithDescription("Replace the stored test cases of a rule. Cases are run against the current script and are only saved when all pass. update_rule rejects scripts that break stored cases. Snapshot keys match transaction_history.snapshot (title, notes, source_amount, source_currency, category_id, tag_ids, extra, transaction_type, ...)."),
+		mcp.WithNumber(
+			"rule_id",
+			mcp.Description("The ID of the rule"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"test_cases",
+			mcp.Description("Array of objects: name (string, required), expected (object, required), input (object, snapshot fields) and/or transaction_id (number, input is applied on top of it). Empty array removes all cases."),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(setRuleTestCasesTool, s.handleSetRuleTestCases)
+
+	runRuleTestsTool := mcp.NewTool(
+		"run_rule_tests",
+		mcp.WithDescription("Run stored rule test cases and return a per-rule report with mismatching fields. Runs every rule with test cases unless rule_id is given."),
+		mcp.WithNumber(
+			"rule_id",
+			mcp.Description("Optional rule ID to limit the run to"),
+		),
+	)
+	s.mcpServer.AddTool(runRuleTestsTool, s.handleRunRuleTests)
+
+	setRuleTriggersTool := mcp.NewTool(
+		"set_rule_triggers",
+		mcp.WithDescription("Set which events run a rule and optionally limit it to import sources. Without events a rule runs on created, updated and imported transactions. Scripts can read the cause via the `trigger` table (trigger.event, trigger.importSourceName, trigger.actor.type)."),
+		mcp.WithNumber(
+			"rule_id",
+			mcp.Description("The ID of the rule"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"events",
+			mcp.Description("Any of: created, updated, imported, manual. Empty array restores the default."),
+		),
+		mcp.WithArray(
+			"import_sources",
+			mcp.Description("ImportSource names, e.g. IMPORT_SOURCE_REVOLUT. When set, the rule only runs for imports from these sources."),
+		),
+	)
+	s.mcpServer.AddTool(setRuleTriggersTool, s.handleSetRuleTriggers)
+
+	listRuleRevisionsTool := mcp.NewTool(
+		"list_rule_revisions",
+		mcp.WithDescription("List revisions of a rule, newest first. Every create, update, trigger change and restore stores an immutable revision with its author."),
+		mcp.WithNumber(
+			"rule_id",
+			mcp.Description("The ID of the rule"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(listRuleRevisionsTool, s.handleListRuleRevisions)
+
+	diffRuleRevisionsTool := mcp.NewTool(
+		"diff_rule_revisions",
+		mcp.WithDescription("Compare two revisions of the same rule. Returns changed fields and a unified diff of the script."),
+		mcp.WithNumber(
+			"from_revision_id",
+			mcp.Description("The older revision ID"),
+			mcp.Required(),
+		),
+		mcp.WithNumber(
+			"to_revision_id",
+			mcp.Description("The newer revision ID"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(diffRuleRevisionsTool, s.handleDiffRuleRevisions)
+
+	restoreRuleRevisionTool := mcp.NewTool(
+		"restore_rule_revision",
+		mcp.WithDescription("Restore a rule to the state stored in a revision. Stored test cases must pass; the restore is recorded as a new revision."),
+		mcp.WithNumber(
+			"revision_id",
+			mcp.Description("The revision ID to restore"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(restoreRuleRevisionTool, s.handleRestoreRuleRevision)
+
+	listRuleModulesTool := mcp.NewTool(
+		"list_rule_modules",
+		mcp.WithDescription("List shared Lua modules that rule scripts load with require(\"name\")."),
+	)
+	s.mcpServer.AddTool(listRuleModulesTool, s.handleListRuleModules)
+
+	createRuleModuleTool := mcp.NewTool(
+		"create_rule_module",
+		mcp.WithDescription("Create a shared Lua module. The script must return the module value, e.g. `local M = {} function M.normalize(s) return s:lower() end return M`. Use dry_run_rule with `modules` to test it first."),
+		mcp.WithString(
+			"name",
+			mcp.Description("Module name used in require(), lowercase identifiers separated by dots"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"script",
+			mcp.Description("Lua source of the module"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"description",
+			mcp.Description("What the module provides"),
+		),
+	)
+	s.mcpServer.AddTool(createRuleModuleTool, s.handleCreateRuleModule)
+
+	updateRuleModuleTool := mcp.NewTool(
+		"update_rule_module",
+		mcp.WithDescription("Replace name, script and description of a shared Lua module. Affects every rule that requires it."),
+		mcp.WithNumber(
+			"id",
+			mcp.Description("The ID of the module"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"name",
+			mcp.Description("Module name used in require()"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"script",
+			mcp.Description("Lua source of the module"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"description",
+			mcp.Description("What the module provides"),
+		),
+	)
+	s.mcpServer.AddTool(updateRuleModuleTool, s.handleUpdateRuleModule)
+
+	deleteRuleModuleTool := mcp.NewTool(
+		"delete_rule_module",
+		mcp.WithDescription("Delete a shared Lua module. Rules that still require it will fail."),
+		mcp.WithNumber(
+			"id",
+			mcp.Description("The ID of the module"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(deleteRuleModuleTool, s.handleDeleteRuleModule)
+
+	runScheduleRuleTool := mcp.NewTool(
+		"run_schedule_rule",
+		mcp.WithDescription("Execute a schedule rule now, as if it fired at effective_date. The run is recorded in schedule_rule_runs; last_run_at is not changed."),
+		mcp.WithNumber(
+			"rule_id",
+			mcp.Description("The ID of the schedule rule"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"effective_date",
+			mcp.Description("RFC3339 date used as the transaction date, defaults to now"),
+		),
+	)
+	s.mcpServer.AddTool(runScheduleRuleTool, s.handleRunScheduleRule)
+
+	listScheduleRuleRunsTool := mcp.NewTool(
+		"list_schedule_rule_runs",
+		mcp.WithDescription("List recent runs of a schedule rule, newest first, with trigger (cron, catch_up, manual), status, error and created transaction IDs."),
+		mcp.WithNumber(
+			"rule_id",
+			mcp.Description("The ID of the schedule rule"),
+			mcp.Required(),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of runs, default 50"),
+		),
+	)
+	s.mcpServer.AddTool(listScheduleRuleRunsTool, s.handleListScheduleRuleRuns)
+
+	listJobsTool := mcp.NewTool(
+		"list_jobs",
+		mcp.WithDescription("List built-in background jobs (exchange rate sync, daily gap fix, loan interest accrual, security prices) with cron schedule, next run and the latest recorded run."),
+	)
+	s.mcpServer.AddTool(listJobsTool, s.handleListJobs)
+
+	runJobTool := mcp.NewTool(
+		"run_job",
+		mcp.WithDescription("Run a built-in background job now on the replica serving the request, regardless of leader election. The run is recorded in job_runs."),
+		mcp.WithString(
+			"name",
+			mcp.Description("Job name as returned by list_jobs, e.g. update_exchange_rates"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(runJobTool, s.handleRunJob)
+
+	setAccountTimezoneTool := mcp.NewTool(
+		"set_account_timezone",
+		mcp.WithDescription("Set the IANA timezone of an account, used to derive transaction_date_only of new transactions on it. Empty timezone falls back to the household TIMEZONE. Existing transactions are not re-dated."),
+		mcp.WithNumber(
+			"account_id",
+			mcp.Description("The ID of the account"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"timezone",
+			mcp.Description("IANA timezone name, e.g. Europe/Kyiv, empty to clear"),
+		),
+	)
+	s.mcpServer.AddTool(setAccountTimezoneTool, s.handleSetAccountTimezone)
+
+	setLoanTool := mcp.NewTool(
+		"set_loan",
+		mcp.WithDescription("Create or replace loan / credit line terms of a liability account. The rate is mirrored to the account liability percent. Interest is accrued on each payment date by a daily job."),
+		mcp.WithNumber(
+			"account_id",
+			mcp.Description("The ID of the liability account"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"kind",
+			mcp.Description("loan (fixed principal) or credit_line (principal drawn by outgoing transactions), default loan"),
+		),
+		mcp.WithString(
+			"principal",
+			mcp.Description("Borrowed amount for loans, limit for credit lines, as decimal string"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"annual_rate",
+			mcp.Description("Annual interest rate in percent, as decimal string"),
+			mcp.Required(),
+		),
+		mcp.WithNumber(
+			"term_months",
+			mcp.Description("Number of monthly payments"),
+			mcp.Required(),
+		),
+		mcp.WithNumber(
+			"payment_day",
+			mcp.Description("Day of month payments are due, 1-28"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"compounding",
+			mcp.Description("monthly or daily, default monthly"),
+		),
+		mcp.WithString(
+			"start_date",
+			mcp.Description("RFC3339 date the loan was taken"),
+			mcp.Required(),
+		),
+		mcp.WithNumber(
+			"interest_account_id",
+			mcp.Description("Expense account for interest accruals, defaults to the default expense account"),
+		),
+		mcp.WithNumber(
+			"interest_category_id",
+			mcp.Description("Category for interest accruals"),
+		),
+	)
+	s.mcpServer.AddTool(setLoanTool, s.handleSetLoan)
+
+	getLoanStatusTool := mcp.NewTool(
+		"get_loan_status",
+		mcp.WithDescription("Get remaining principal, unpaid interest, repayments split into interest and principal, and a payoff projection for a liability account with loan terms."),
+		mcp.WithNumber(
+			"account_id",
+			mcp.Description("The ID of the liability account"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"monthly_payment",
+			mcp.Description("Payment used for the projection, defaults to the annuity payment for the remaining term"),
+		),
+	)
+	s.mcpServer.AddTool(getLoanStatusTool, s.handleGetLoanStatus)
+
+	getLoanScheduleTool := mcp.NewTool(
+		"get_loan_schedule",
+		mcp.WithDescription("Get the original amortization schedule of a loan: due date, payment, interest, principal and remaining principal per payment."),
+		mcp.WithNumber(
+			"account_id",
+			mcp.Description("The ID of the liability account"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(getLoanScheduleTool, s.handleGetLoanSchedule)
+
+	createSecurityTool := mcp.NewTool(
+		"create_security",
+		mcp.WithDescription("Create a security (stock, ETF, fund, bond, crypto or commodity) that can be traded in investment accounts."),
+		mcp.WithString(
+			"symbol",
+			mcp.Description("Ticker or code, stored upper case and unique"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"name",
+			mcp.Description("Display name"),
+		),
+		mcp.WithString(
+			"kind",
+			mcp.Description("stock, etf, fund, bond, crypto or commodity"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"currency",
+			mcp.Description("Currency the security is priced in"),
+			mcp.Required(),
+		),
+		mcp.WithNumber(
+			"quantity_decimals",
+			mcp.Description("Decimal places of quantities, e.g. 8 for crypto, default 0"),
+		),
+	)
+	s.mcpServer.AddTool(createSecurityTool, s.handleCreateSecurity)
+
+	setInvestmentAccountTool := mcp.NewTool(
+		"set_investment_account",
+		mcp.WithDescription("Mark an asset account as an investment account holding securities, or update its settings. Trades are settled with the cash account: buys transfer cash in, sells transfer proceeds out and dividends are income of the cash account."),
+		mcp.WithNumber(
+			"account_id",
+			mcp.Description("The ID of the asset account"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"cost_method",
+			mcp.Description("fifo or average, default fifo"),
+		),
+		mcp.WithNumber(
+			"cash_account_id",
+			mcp.Description("Asset account in the same currency trades are settled with; trades post no transactions when omitted"),
+		),
+		mcp.WithNumber(
+			"dividend_account_id",
+			mcp.Description("Income account dividends come from, defaults to the default income account"),
+		),
+	)
+	s.mcpServer.AddTool(setInvestmentAccountTool, s.handleSetInvestmentAccount)
+
+	recordTradeTool := mcp.NewTool(
+		"record_trade",
+		mcp.WithDescription("Record a buy, sell, dividend or split in an investment account. Holdings are revalued from the trade date. Sells exceeding the held quantity are rejected."),
+		mcp.WithNumber(
+			"account_id",
+			mcp.Description("The ID of the investment account"),
+			mcp.Required(),
+		),
+		mcp.WithNumber(
+			"security_id",
+			mcp.Description("The ID of the security"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"kind",
+			mcp.Description("buy, sell, dividend or split"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"trade_date",
+			mcp.Description("Trade date in YYYY-MM-DD format"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"quantity",
+			mcp.Description("Units bought or sold, or the split ratio (2 for a 2-for-1 split), as decimal string"),
+		),
+		mcp.WithString(
+			"price",
+			mcp.Description("Price per unit in security currency, as decimal string"),
+		),
+		mcp.WithString(
+			"fee",
+			mcp.Description("Fee in account currency, as decimal string"),
+		),
+		mcp.WithString(
+			"amount",
+			mcp.Description("Cash settled in account currency, required for dividends; calculated from quantity, price and fee for buys and sells when omitted"),
+		),
+		mcp.WithString(
+			"note",
+			mcp.Description("Optional note"),
+		),
+	)
+	s.mcpServer.AddTool(recordTradeTool, s.handleRecordTrade)
+
+	deleteTradeTool := mcp.NewTool(
+		"delete_trade",
+		mcp.WithDescription("Delete a trade together with its cash transaction. Rejected when later sells would exceed the remaining quantity."),
+		mcp.WithNumber(
+			"id",
+			mcp.Description("The ID of the trade"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(deleteTradeTool, s.handleDeleteTrade)
+
+	getHoldingsTool := mcp.NewTool(
+		"get_holdings",
+		mcp.WithDescription("Get holdings of an investment account: quantity, open lots, cost basis, latest price, market value, unrealized and realized gains and dividends in account currency."),
+		mcp.WithNumber(
+			"account_id",
+			mcp.Description("The ID of the investment account"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(getHoldingsTool, s.handleGetHoldings)
+
+	importSecurityPricesTool := mcp.NewTool(
+		"import_security_prices",
+		mcp.WithDescription("Import security prices from CSV rows of symbol,date,price (date in YYYY-MM-DD, optional header). Existing prices for the same day are replaced and affected accounts are revalued."),
+		mcp.WithString(
+			"csv",
+			mcp.Description("CSV content"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(importSecurityPricesTool, s.handleImportSecurityPrices)
+
+	getNetWorthTool := mcp.NewTool(
+		"get_net_worth",
+		mcp.WithDescription("Get assets, liabilities and net worth in base currency at the end of a day, with per account values. Investment accounts are valued at the market value of their holdings."),
+		mcp.WithString(
+			"date",
+			mcp.Description("Date in YYYY-MM-DD format, defaults to today"),
+		),
+	)
+	s.mcpServer.AddTool(getNetWorthTool, s.handleGetNetWorth)
+
+	setTransactionExchangeRateTool := mcp.NewTool(
+		"set_transaction_exchange_rate",
+		mcp.WithDescription("Set or lock the exchange rate used to convert transactions to base currency. The rate is source currency units per 1 base currency unit. Locked rates are kept when rates are re-synced; unlocking recalculates base amounts. Returns the resulting rate and base amounts."),
+		mcp.WithArray(
+			"assignments",
+			mcp.Description("Array of objects with transaction_id (required), rate (optional decimal string, manual rate; omit to lock the rate already used) and locked (optional boolean, default true; false unlocks)"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(setTransactionExchangeRateTool, s.handleSetTransactionExchangeRate)
+
+	listRateOverridesTool := mcp.NewTool(
+		"list_rate_overrides",
+		mcp.WithDescription("List user defined exchange rate overrides. Overrides take precedence over synced rates for transactions dated within their range."),
+		mcp.WithString(
+			"currency",
+			mcp.Description("Optional currency code filter"),
+		),
+	)
+	s.mcpServer.AddTool(listRateOverridesTool, s.handleListRateOverrides)
+
+	createRateOverrideTool := mcp.NewTool(
+		"create_rate_override",
+		mcp.WithDescription("Create an exchange rate override for a currency and date range, then recalculate base amounts of unlocked transactions in that range. Ranges of one currency must not overlap."),
+		mcp.WithString(
+			"currency",
+			mcp.Description("Currency code, e.g. PLN"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"rate",
+			mcp.Description("Currency units per 1 base currency unit, as decimal string"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"valid_from",
+			mcp.Description("First transaction date covered, YYYY-MM-DD"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"valid_to",
+			mcp.Description("Last transaction date covered, YYYY-MM-DD; omit for open ended"),
+		),
+		mcp.WithString(
+			"note",
+			mcp.Description("Optional note, e.g. where the rate comes from"),
+		),
+	)
+	s.mcpServer.AddTool(createRateOverrideTool, s.handleCreateRateOverride)
+
+	deleteRateOverrideTool := mcp.NewTool(
+		"delete_rate_override",
+		mcp.WithDescription("Delete an exchange rate override and recalculate base amounts of the transactions it covered"),
+		mcp.WithNumber(
+			"id",
+			mcp.Description("The ID of the override"),
+			mcp.Required(),
+		),
+	)
+	s.mcpServer.AddTool(deleteRateOverrideTool, s.handleDeleteRateOverride)
+
+	getFxGainLossTool := mcp.NewTool(
+		"get_fx_gain_loss",
+		mcp.WithDescription("Report how much of the change in base currency value over a period came from currency movements. Unrealized: per foreign currency asset or liability account, closing value minus opening value minus flows, each valued at the historical rate of its day. Realized: per currency exchange (transfer between currencies, expense with fx amount), received value minus given value at the rates of the transaction day. Amounts are in base currency, positive is a gain."),
+		mcp.WithString(
+			"from",
+			mcp.Description("First day of the period in YYYY-MM-DD format"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"to",
+			mcp.Description("Last day of the period in YYYY-MM-DD format"),
+			mcp.Required(),
+		),
+		mcp.WithArray(
+			"account_ids",
+			mcp.Description("Limit to these accounts; all foreign currency asset and liability accounts when empty"),
+		),
+		mcp.WithBoolean(
+			"post_revaluations",
+			mcp.Description("Store the unrealized result of every account for the period in fx_revaluations, replacing an earlier result for the same end day"),
+		),
+	)
+	s.mcpServer.AddTool(getFxGainLossTool, s.handleGetFxGainLoss)
+
 	listTagsTool := mcp.NewTool(
 		"list_tags",
 		mcp.WithDescription("List all tags"),
@@ -453,27 +1249,40 @@ func (s *Server) registerTools() {
 		mcp.WithString("amount", mcp.Description("Decimal amount as string"), mcp.Required()),
 	)
 	s.mcpServer.AddTool(convertCurrencyTool, s.handleConvertCurrency)
-}
 
-func (s *Server) registerResources() {
-	schemaResource := mcp.NewResource(
-		"context://schema",
-		"Database Schema",
-		mcp.WithResourceDescription("Go Money database schema documentation"),
-		mcp.WithMIMEType("text/markdown"),
+	listCurrenciesTool := mcp.NewTool(
+		"list_currencies",
+		mcp.WithDescription("List currencies with rate vs base currency, decimal places, name, symbol, symbol position, ISO 4217 numeric code and whether it is a user defined pseudo currency (points, miles, gift cards)."),
+		mcp.WithBoolean(
+			"include_inactive",
+			mcp.Description("Include inactive currencies, default false"),
+		),
 	)
+	s.mcpServer.AddTool(listCurrenciesTool, s.handleListCurrencies)
 
-	s.mcpServer.AddResource(schemaResource, s.handleSchemaResource)
-}
+	upsertCurrencyTool := mcp.NewTool(
+		"upsert_currency",
+		mcp.WithDescription("Create a currency or update its metadata. Metadata of known ISO 4217 codes is filled automatically. Pseudo currencies (is_custom) need a non ISO code and an initial rate; sync never touches their rates. Rates of existing currencies are not changed, use set_currency_rate or create_rate_override."),
+		mcp.WithString("id", mcp.Description("Currency code, 2-10 of A-Z, 0-9, _; e.g. EUR, MILES"), mcp.Required()),
+		mcp.WithString("name", mcp.Description("Display name")),
+		mcp.WithString("symbol", mcp.Description("Symbol, e.g. $ or zł; empty formats with the code")),
+		mcp.WithString("symbol_position", mcp.Description("before ($10.00) or after (10.00 zł)")),
+		mcp.WithNumber("numeric_code", mcp.Description("ISO 4217 numeric code")),
+		mcp.WithNumber("decimal_places", mcp.Description("0-8")),
+		mcp.WithBoolean("is_active", mcp.Description("Whether the currency is active")),
+		mcp.WithBoolean("is_custom", mcp.Description("Create as pseudo currency, only used on create")),
+		mcp.WithString("rate", mcp.Description("Initial rate, currency units per 1 base currency unit, as decimal string; only used on create")),
+	)
+	s.mcpServer.AddTool(upsertCurrencyTool, s.handleUpsertCurrency)
 
-func (s *Server) handleSchemaResource(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	return []mcp.ResourceContents{
-		mcp.TextResourceContents{
-			URI:      req.Params.URI,
-			MIMEType: "text/markdown",
-			Text:     s.cfg.Docs,
-		},
-	}, nil
+	setCurrencyRateTool := mcp.NewTool(
+		"set_currency_rate",
+		mcp.WithDescription("Record the rate of a pseudo currency for a day in currency_rate_history. The current rate follows the latest day; when it changes, base amounts of unlocked transactions in the currency are recalculated."),
+		mcp.WithString("currency", mcp.Description("Pseudo currency code"), mcp.Required()),
+		mcp.WithString("rate", mcp.Description("Currency units per 1 base currency unit, as decimal string"), mcp.Required()),
+		mcp.WithString("date", mcp.Description("YYYY-MM-DD the rate applies from, default today")),
+	)
+	s.mcpServer.AddTool(setCurrencyRateTool, s.handleSetCurrencyRate)
 }
 
 func (s *Server) Handler() http.Handler {
@@ -3,6 +3,7 @@ package mcp_test
 import (
 	"context"
 	"database/sql/driver"
+	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -115,6 +116,7 @@ func TestServer_HandleQuery_Success(t *testing.T) {
 	type tc struct {
 		name     string
 		sql      string
+		role     string
 		columns  []string
 		rows     [][]driver.Value
 		expected string
@@ -123,11 +125,19 @@ func TestServer_HandleQuery_Success(t *testing.T) {
 	cases := []tc{
 		{
 			name:     "simple select",
-			sql:      "SELECT id, name FROM users",
+			sql:      "SELECT id, name FROM accounts",
 			columns:  []string{"id", "name"},
 			rows:     [][]driver.Value{{1, "Alice"}, {2, "Bob"}},
 			expected: "Alice",
 		},
+		{
+			name:     "with restricted role",
+			sql:      "SELECT id, name FROM categories",
+			role:     "mcp_reader",
+			columns:  []string{"id", "name"},
+			rows:     [][]driver.Value{{1, "Groceries"}},
+			expected: "Groceries",
+		},
 		{
 			name:     "select with where",
 			sql:      "SELECT balance FROM accounts WHERE id = 1",
@@ -147,7 +157,15 @@ func TestServer_HandleQuery_Success(t *testing.T) {
 			for _, row := range c.rows {
 				mockRows.AddRow(row...)
 			}
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta("SET LOCAL statement_timeout = 30000")).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			if c.role != "" {
+				mock.ExpectExec(regexp.QuoteMeta(`SET LOCAL ROLE "` + c.role + `"`)).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			}
 			mock.ExpectQuery(".*").WillReturnRows(mockRows)
+			mock.ExpectRollback()
 
 			catSvc := NewMockCategoryService(ctrl)
 			rulesSvc := NewMockRulesService(ctrl)
@@ -159,6 +177,7 @@ func TestServer_HandleQuery_Success(t *testing.T) {
 				CategorySvc: catSvc,
 				RulesSvc:    rulesSvc,
 				DryRunSvc:   dryRunSvc,
+				QueryRole:   c.role,
 			})
 
 			mcpServer := server.MCPServer()
@@ -177,6 +196,7 @@ func TestServer_HandleQuery_Success(t *testing.T) {
 			require.NotNil(t, result)
 			assert.False(t, result.IsError)
 			assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -220,9 +240,19 @@ func TestServer_HandleQuery_Failure(t *testing.T) {
 			expectedError: "only SELECT queries are allowed",
 		},
 		{
-			name:          "forbidden pattern with semicolon",
-			sql:           "SELECT * FROM users; DROP TABLE users",
-			expectedError: "forbidden SQL operation detected",
+			name:          "multiple statements",
+			sql:           "SELECT * FROM accounts; DROP TABLE accounts",
+			expectedError: "only a single statement is allowed",
+		},
+		{
+			name:          "table not in allowlist",
+			sql:           "SELECT * FROM users",
+			expectedError: "table users is not allowed",
+		},
+		{
+			name:          "function not in allowlist",
+			sql:           "SELECT pg_sleep(100)",
+			expectedError: "function pg_sleep is not allowed",
 		},
 	}
 
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/suggestions"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+)
+
+type candidateOutput struct {
+	ID         int32   `json:"id"`
+	Confidence float64 `json:"confidence"`
+}
+
+type transactionSuggestionOutput struct {
+	TransactionID int64              `json:"transaction_id"`
+	Categories    []*candidateOutput `json:"categories"`
+	Tags          []*candidateOutput `json:"tags"`
+}
+
+func mapCandidates(candidates []*suggestions.Candidate) []*candidateOutput {
+	return lo.Map(candidates, func(c *suggestions.Candidate, _ int) *candidateOutput {
+		return &candidateOutput{
+			ID:         c.ID,
+			Confidence: c.Confidence,
+		}
+	})
+}
+
+func (s *Server) handleSuggestTransactionCategories(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.cfg.SuggestionSvc == nil {
+		return mcp.NewToolResultError("suggestions are disabled"), nil
+	}
+
+	raw, _ := request.GetArguments()["transaction_ids"].([]any)
+	if len(raw) == 0 {
+		return mcp.NewToolResultError("transaction_ids parameter is required and must be a non-empty array"), nil
+	}
+
+	if len(raw) > maxSearchLimit {
+		return mcp.NewToolResultError(fmt.Sprintf("at most %d transaction_ids are allowed", maxSearchLimit)), nil
+	}
+
+	ids := make([]int64, 0, len(raw))
+	for i, v := range raw {
+		id, ok := v.(float64)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("transaction_ids[%d] must be a number", i)), nil
+		}
+
+		ids = append(ids, int64(id))
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	result, err := s.cfg.SuggestionSvc.SuggestForTransactions(queryCtx, ids)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to suggest categories: %v", err)), nil
+	}
+
+	reportRows(ctx, len(result))
+
+	return toolJSONResult(lo.Map(result, func(item *suggestions.TransactionSuggestion, _ int) *transactionSuggestionOutput {
+		return &transactionSuggestionOutput{
+			TransactionID: item.TransactionID,
+			Categories:    mapCandidates(item.Suggestion.Categories),
+			Tags:          mapCandidates(item.Suggestion.Tags),
+		}
+	}))
+}
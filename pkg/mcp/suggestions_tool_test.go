@@ -0,0 +1,99 @@
+package mcp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/suggestions"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+func newSuggestionsTestServer(t *testing.T, suggestionSvc gomcp.SuggestionsService, importSvc gomcp.ImportService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:            gormDB,
+		Docs:          "test docs",
+		SuggestionSvc: suggestionSvc,
+		ImportSvc:     importSvc,
+	})
+}
+
+func TestServer_HandleSuggestTransactionCategories(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		suggestionSvc := NewMockSuggestionsService(gomock.NewController(t))
+		suggestionSvc.EXPECT().SuggestForTransactions(gomock.Any(), []int64{10, 11}).
+			DoAndReturn(func(_ context.Context, _ []int64) ([]*suggestions.TransactionSuggestion, error) {
+				return []*suggestions.TransactionSuggestion{
+					{
+						TransactionID: 10,
+						Suggestion: &suggestions.Suggestion{
+							Categories: []*suggestions.Candidate{{ID: 3, Confidence: 0.82}},
+							Tags:       []*suggestions.Candidate{{ID: 7, Confidence: 0.5}},
+						},
+					},
+					{
+						TransactionID: 11,
+						Suggestion:    &suggestions.Suggestion{},
+					},
+				}, nil
+			})
+
+		result := callTool(t, newSuggestionsTestServer(t, suggestionSvc, nil), "suggest_transaction_categories", map[string]any{
+			"transaction_ids": []any{float64(10), float64(11)},
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"transaction_id": 10`)
+		assert.Contains(t, text, `"confidence": 0.82`)
+		assert.Contains(t, text, `"transaction_id": 11`)
+		assert.Contains(t, text, `"categories": []`)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			args     map[string]any
+			expected string
+		}{
+			"missing": {args: map[string]any{}, expected: "transaction_ids parameter is required"},
+			"type":    {args: map[string]any{"transaction_ids": []any{"x"}}, expected: "transaction_ids[0] must be a number"},
+			"limit":   {args: map[string]any{"transaction_ids": make([]any, 501)}, expected: "at most 500 transaction_ids"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				server := newSuggestionsTestServer(t, NewMockSuggestionsService(gomock.NewController(t)), nil)
+				result := callTool(t, server, "suggest_transaction_categories", c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		suggestionSvc := NewMockSuggestionsService(gomock.NewController(t))
+		suggestionSvc.EXPECT().SuggestForTransactions(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newSuggestionsTestServer(t, suggestionSvc, nil), "suggest_transaction_categories", map[string]any{
+			"transaction_ids": []any{float64(1)},
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to suggest categories")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		result := callTool(t, newSuggestionsTestServer(t, nil, nil), "suggest_transaction_categories", map[string]any{
+			"transaction_ids": []any{float64(1)},
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "suggestions are disabled")
+	})
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+)
+
+const (
+	maxAuditStringBytes = 1024
+	maxAuditArrayItems  = 50
+)
+
+// auditRedactedArguments never reach the audit log, auditOmittedArguments are file contents that are
+// logged by size only.
+var (
+	auditRedactedArguments = map[string]struct{}{"secret": {}, "password": {}, "token": {}}
+	auditOmittedArguments  = map[string]struct{}{"files": {}, "content": {}}
+)
+
+type callStatsKey struct{}
+
+// callStats collects per call numbers reported by tool handlers.
+type callStats struct {
+	rowBudget int
+	rows      int
+}
+
+// rowBudget returns how many rows the current call may return to the client.
+func rowBudget(ctx context.Context) int {
+	if stats, ok := ctx.Value(callStatsKey{}).(*callStats); ok {
+		return stats.rowBudget
+	}
+
+	return maxRows
+}
+
+// reportRows counts rows returned to the client towards the per-token row limit and the audit log.
+func reportRows(ctx context.Context, rows int) {
+	if stats, ok := ctx.Value(callStatsKey{}).(*callStats); ok {
+		stats.rows += rows
+	}
+}
+
+// auditMiddleware attributes writes of a tool call to the calling token, enforces the per-token limits
+// and records the call in the audit log.
+func (s *Server) auditMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		started := time.Now()
+
+		call := &database.McpToolCall{
+			Tool:      request.Params.Name,
+			Arguments: auditArguments(request.GetArguments()),
+			CreatedAt: started.UTC(),
+		}
+
+		if s.cfg.ClaimsFromContext != nil {
+			claims := s.cfg.ClaimsFromContext(ctx)
+			if claims.RegisteredClaims != nil {
+				call.TokenID = claims.ID
+			}
+
+			call.UserID = claims.UserID
+		}
+
+		if call.UserID != 0 {
+			ctx = history.WithActor(ctx, history.McpActor(call.UserID, call.TokenID))
+		}
+
+		stats := &callStats{rowBudget: maxRows}
+
+		if s.rateLimiter != nil {
+			budget, err := s.rateLimiter.Acquire(call.TokenID)
+			if err != nil {
+				result := mcp.NewToolResultError(err.Error())
+				call.Status = database.McpToolCallStatusRejected
+				s.recordCall(ctx, call, started, result, nil)
+
+				return result, nil
+			}
+
+			stats.rowBudget = budget
+		}
+
+		result, err := next(context.WithValue(ctx, callStatsKey{}, stats), request)
+
+		if s.rateLimiter != nil {
+			s.rateLimiter.AddRows(call.TokenID, stats.rows)
+		}
+
+		call.Rows = int32(stats.rows)
+		s.recordCall(ctx, call, started, result, err)
+
+		return result, err
+	}
+}
+
+func (s *Server) recordCall(
+	ctx context.Context,
+	call *database.McpToolCall,
+	started time.Time,
+	result *mcp.CallToolResult,
+	callErr error,
+) {
+	if s.cfg.AuditSvc == nil {
+		return
+	}
+
+	call.DurationMs = time.Since(started).Milliseconds()
+
+	if callErr != nil {
+		call.Error = lo.ToPtr(callErr.Error())
+	} else if result != nil && result.IsError && len(result.Content) > 0 {
+		if text, ok := result.Content[0].(mcp.TextContent); ok {
+			call.Error = &text.Text
+		}
+	}
+
+	if call.Status == 0 {
+		call.Status = database.McpToolCallStatusSuccess
+		if callErr != nil || (result != nil && result.IsError) {
+			call.Status = database.McpToolCallStatusError
+		}
+	}
+
+	// the audit row must be written even when the client went away
+	auditCtx := database.WithContext(context.WithoutCancel(ctx), s.db)
+
+	if err := s.cfg.AuditSvc.Record(auditCtx, call); err != nil {
+		zerolog.Ctx(ctx).Error().Err(err).Str("tool", call.Tool).Msg("failed to record mcp tool call")
+	}
+}
+
+// auditArguments copies tool arguments for the audit log: secrets are replaced, file contents are
+// replaced by their size, long strings are cut and long arrays keep their first items.
+func auditArguments(args map[string]any) map[string]any {
+	if args == nil {
+		return nil
+	}
+
+	result := make(map[string]any, len(args))
+
+	for key, value := range args {
+		name := strings.ToLower(key)
+
+		switch {
+		case value == nil:
+			result[key] = nil
+		case lo.HasKey(auditRedactedArguments, name):
+			result[key] = "[redacted]"
+		case lo.HasKey(auditOmittedArguments, name):
+			result[key] = omittedValue(value)
+		default:
+			result[key] = auditValue(value)
+		}
+	}
+
+	return result
+}
+
+func auditValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return auditArguments(v)
+	case []any:
+		items := make([]any, 0, min(len(v), maxAuditArrayItems+1))
+		for _, item := range v[:min(len(v), maxAuditArrayItems)] {
+			items = append(items, auditValue(item))
+		}
+
+		if len(v) > maxAuditArrayItems {
+			items = append(items, fmt.Sprintf("[%d more items]", len(v)-maxAuditArrayItems))
+		}
+
+		return items
+	case string:
+		if len(v) <= maxAuditStringBytes {
+			return v
+		}
+
+		return fmt.Sprintf("%s… [%d bytes]", strings.ToValidUTF8(v[:maxAuditStringBytes], ""), len(v))
+	default:
+		return v
+	}
+}
+
+// omittedValue keeps the shape of file arguments: a string becomes its size, an array one size per item.
+func omittedValue(value any) any {
+	switch v := value.(type) {
+	case []any:
+		return lo.Map(v, func(item any, _ int) any { return omittedValue(item) })
+	case string:
+		return fmt.Sprintf("[omitted, %d bytes]", len(v))
+	default:
+		return "[omitted]"
+	}
+}
@@ -0,0 +1,220 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ft-t/go-money/pkg/auth"
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+)
+
+// handleToolCall goes through the mcp-go request handling, so tool middlewares are applied.
+func handleToolCall(t *testing.T, server *gomcp.Server, name string, args map[string]any) *mcp.CallToolResult {
+	raw, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name, "arguments": args},
+	})
+	require.NoError(t, err)
+
+	resp, ok := server.MCPServer().HandleMessage(context.Background(), raw).(mcp.JSONRPCResponse)
+	require.True(t, ok)
+
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+
+	return &result
+}
+
+func testClaims(_ context.Context) auth.JwtClaims {
+	return auth.JwtClaims{
+		RegisteredClaims: &jwt.RegisteredClaims{ID: "token-jti"},
+		UserID:           7,
+	}
+}
+
+func TestServer_AuditMiddleware(t *testing.T) {
+	newServer := func(t *testing.T, cfg *gomcp.ServerConfig) *gomcp.Server {
+		gormDB, mockDB, _ := testingutils.GormMock()
+		t.Cleanup(func() { _ = mockDB.Close() })
+
+		cfg.DB = gormDB
+		cfg.Docs = "test docs"
+		cfg.ClaimsFromContext = testClaims
+
+		return gomcp.NewServer(cfg)
+	}
+
+	t.Run("records call and attributes writes to the token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		txSvc := NewMockTransactionService(ctrl)
+		auditSvc := NewMockAuditService(ctrl)
+
+		txSvc.EXPECT().ListPage(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ *transactions.ListRequest) (*transactions.ListResponse, error) {
+				actor, ok := history.ActorFromContext(ctx)
+				require.True(t, ok)
+				assert.Equal(t, history.McpActor(7, "token-jti"), actor)
+
+				return &transactions.ListResponse{
+					TotalCount:   lo.ToPtr(int64(2)),
+					Transactions: []*gomoneypbv1.Transaction{{Id: 1}, {Id: 2}},
+				}, nil
+			})
+
+		auditSvc.EXPECT().Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, call *database.McpToolCall) error {
+				assert.Equal(t, "token-jti", call.TokenID)
+				assert.EqualValues(t, 7, call.UserID)
+				assert.Equal(t, "search_transactions", call.Tool)
+				assert.Equal(t, "coffee", call.Arguments["text"])
+				assert.Equal(t, database.McpToolCallStatusSuccess, call.Status)
+				assert.Nil(t, call.Error)
+				assert.EqualValues(t, 2, call.Rows)
+				assert.WithinDuration(t, time.Now().UTC(), call.CreatedAt, time.Minute)
+
+				return nil
+			})
+
+		result := handleToolCall(t, newServer(t, &gomcp.ServerConfig{
+			TransactionSvc: txSvc,
+			AuditSvc:       auditSvc,
+		}), "search_transactions", map[string]any{"text": "coffee"})
+
+		assert.False(t, result.IsError)
+	})
+
+	t.Run("records tool errors", func(t *testing.T) {
+		auditSvc := NewMockAuditService(gomock.NewController(t))
+		auditSvc.EXPECT().Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, call *database.McpToolCall) error {
+				assert.Equal(t, database.McpToolCallStatusError, call.Status)
+				require.NotNil(t, call.Error)
+				assert.Contains(t, *call.Error, "sql parameter is required")
+
+				return nil
+			})
+
+		result := handleToolCall(t, newServer(t, &gomcp.ServerConfig{AuditSvc: auditSvc}), "query", map[string]any{})
+
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("redacts and truncates arguments", func(t *testing.T) {
+		auditSvc := NewMockAuditService(gomock.NewController(t))
+		auditSvc.EXPECT().Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, call *database.McpToolCall) error {
+				assert.Equal(t, "[redacted]", call.Arguments["secret"])
+				assert.Equal(t, []any{"[omitted, 4000 bytes]"}, call.Arguments["files"])
+				assert.Equal(t, map[string]any{"password": "[redacted]", "name": "home"}, call.Arguments["nested"])
+
+				note := call.Arguments["note"].(string)
+				assert.True(t, strings.HasPrefix(note, strings.Repeat("n", 1024)))
+				assert.True(t, strings.HasSuffix(note, "… [3000 bytes]"))
+
+				ids := call.Arguments["ids"].([]any)
+				require.Len(t, ids, 51)
+				assert.Equal(t, "[10 more items]", ids[50])
+
+				return nil
+			})
+
+		result := handleToolCall(t, newServer(t, &gomcp.ServerConfig{AuditSvc: auditSvc}), "query", map[string]any{
+			"secret": "0123456789abcdef",
+			"files":  []any{strings.Repeat("Q", 4000)},
+			"nested": map[string]any{"password": "hunter2", "name": "home"},
+			"note":   strings.Repeat("n", 3000),
+			"ids":    lo.ToAnySlice(lo.Range(60)),
+		})
+
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("audit failure does not fail the call", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		categorySvc := NewMockCategoryService(ctrl)
+		auditSvc := NewMockAuditService(ctrl)
+
+		categorySvc.EXPECT().GetAllCategories(gomock.Any()).Return([]*database.Category{{ID: 1, Name: "Food"}}, nil)
+		auditSvc.EXPECT().Record(gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+		result := handleToolCall(t, newServer(t, &gomcp.ServerConfig{
+			CategorySvc: categorySvc,
+			AuditSvc:    auditSvc,
+		}), "list_categories", map[string]any{})
+
+		assert.False(t, result.IsError)
+	})
+
+	t.Run("call limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		categorySvc := NewMockCategoryService(ctrl)
+		auditSvc := NewMockAuditService(ctrl)
+
+		categorySvc.EXPECT().GetAllCategories(gomock.Any()).Return(nil, nil)
+
+		var statuses []database.McpToolCallStatus
+		auditSvc.EXPECT().Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, call *database.McpToolCall) error {
+				statuses = append(statuses, call.Status)
+
+				return nil
+			}).Times(2)
+
+		server := newServer(t, &gomcp.ServerConfig{
+			CategorySvc: categorySvc,
+			AuditSvc:    auditSvc,
+			RateLimit:   &gomcp.RateLimiterConfig{Window: time.Minute, MaxCalls: 1},
+		})
+
+		assert.False(t, handleToolCall(t, server, "list_categories", map[string]any{}).IsError)
+
+		result := handleToolCall(t, server, "list_categories", map[string]any{})
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "rate limit exceeded: 1 calls per 1m0s")
+
+		assert.Equal(t, []database.McpToolCallStatus{
+			database.McpToolCallStatusSuccess,
+			database.McpToolCallStatusRejected,
+		}, statuses)
+	})
+
+	t.Run("row limit caps page size", func(t *testing.T) {
+		txSvc := NewMockTransactionService(gomock.NewController(t))
+		txSvc.EXPECT().ListPage(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *transactions.ListRequest) (*transactions.ListResponse, error) {
+				assert.EqualValues(t, 3, req.Filter.Limit)
+
+				return &transactions.ListResponse{
+					Transactions: []*gomoneypbv1.Transaction{{Id: 1}, {Id: 2}, {Id: 3}},
+				}, nil
+			})
+
+		server := newServer(t, &gomcp.ServerConfig{
+			TransactionSvc: txSvc,
+			RateLimit:      &gomcp.RateLimiterConfig{Window: time.Minute, MaxRows: 3},
+		})
+
+		assert.False(t, handleToolCall(t, server, "search_transactions", map[string]any{}).IsError)
+
+		result := handleToolCall(t, server, "search_transactions", map[string]any{})
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "rate limit exceeded: 3 rows per 1m0s")
+	})
+}
@@ -0,0 +1,359 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+var transactionTypes = map[string]gomoneypbv1.TransactionType{
+	"transfer":   gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+	"income":     gomoneypbv1.TransactionType_TRANSACTION_TYPE_INCOME,
+	"expense":    gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+	"reversal":   gomoneypbv1.TransactionType_TRANSACTION_TYPE_REVERSAL,
+	"adjustment": gomoneypbv1.TransactionType_TRANSACTION_TYPE_ADJUSTMENT,
+}
+
+var transactionTypeNames = lo.Invert(transactionTypes)
+
+var transactionSortFields = map[string]transactions.SortField{
+	"date":        transactions.SortFieldTransactionDate,
+	"created_at":  transactions.SortFieldCreatedAt,
+	"amount":      transactions.SortFieldAmount,
+	"base_amount": transactions.SortFieldBaseAmount,
+	"title":       transactions.SortFieldTitle,
+	"category":    transactions.SortFieldCategory,
+	"account":     transactions.SortFieldAccount,
+}
+
+var historyEventTypeNames = map[database.TransactionHistoryEventType]string{
+	database.TransactionHistoryEventTypeCreated:     "created",
+	database.TransactionHistoryEventTypeUpdated:     "updated",
+	database.TransactionHistoryEventTypeDeleted:     "deleted",
+	database.TransactionHistoryEventTypeRuleApplied: "rule_applied",
+}
+
+var historyActorTypeNames = map[database.TransactionHistoryActorType]string{
+	database.TransactionHistoryActorTypeUser:      "user",
+	database.TransactionHistoryActorTypeRule:      "rule",
+	database.TransactionHistoryActorTypeScheduler: "scheduler",
+	database.TransactionHistoryActorTypeImporter:  "importer",
+	database.TransactionHistoryActorTypeBulk:      "bulk",
+	database.TransactionHistoryActorTypeMcp:       "mcp",
+}
+
+type transactionOutput struct {
+	ID                   int64   `json:"id"`
+	Type                 string  `json:"type"`
+	Title                string  `json:"title"`
+	TransactionDate      string  `json:"transaction_date"`
+	SourceAccountID      int32   `json:"source_account_id,omitempty"`
+	SourceAmount         string  `json:"source_amount,omitempty"`
+	SourceCurrency       string  `json:"source_currency,omitempty"`
+	DestinationAccountID int32   `json:"destination_account_id,omitempty"`
+	DestinationAmount    string  `json:"destination_amount,omitempty"`
+	DestinationCurrency  string  `json:"destination_currency,omitempty"`
+	CategoryID           *int32  `json:"category_id,omitempty"`
+	TagIDs               []int32 `json:"tag_ids,omitempty"`
+	Notes                string  `json:"notes,omitempty"`
+	ReferenceNumber      string  `json:"reference_number,omitempty"`
+}
+
+type searchTransactionsOutput struct {
+	TotalCount   *int64               `json:"total_count,omitempty"`
+	Transactions []*transactionOutput `json:"transactions"`
+	NextCursor   string               `json:"next_cursor,omitempty"`
+}
+
+type transactionHistoryOutput struct {
+	ID                  int64          `json:"id"`
+	TransactionID       int64          `json:"transaction_id,omitempty"` // set by the change feed only
+	EventType           string         `json:"event_type"`
+	ActorType           string         `json:"actor_type"`
+	ActorUserID         *int32         `json:"actor_user_id,omitempty"`
+	ActorRuleID         *int32         `json:"actor_rule_id,omitempty"`
+	ActorRuleRevisionID *int64         `json:"actor_rule_revision_id,omitempty"`
+	ActorExtra          *string        `json:"actor_extra,omitempty"`
+	Diff                map[string]any `json:"diff,omitempty"`
+	Snapshot            map[string]any `json:"snapshot,omitempty"`
+	OccurredAt          string         `json:"occurred_at"`
+}
+
+// parseDateArg parses an optional YYYY-MM-DD argument.
+func parseDateArg(args map[string]any, key string) (*time.Time, error) {
+	val, _ := args[key].(string)
+	if val == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.DateOnly, val)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", key)
+	}
+
+	return &parsed, nil
+}
+
+// dayRange returns the first and the last instant of the calendar days [from, to] in loc.
+func dayRange(from time.Time, to time.Time, loc *time.Location) (time.Time, time.Time) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1).Add(-time.Nanosecond)
+
+	return start, end
+}
+
+func (s *Server) buildSearchTransactionsRequest(args map[string]any) (*transactionsv1.ListTransactionsRequest, error) {
+	req := &transactionsv1.ListTransactionsRequest{
+		Limit: defaultSearchLimit,
+	}
+
+	if limit, ok := args["limit"].(float64); ok {
+		if limit < 1 || limit > maxSearchLimit {
+			return nil, errors.Newf("limit must be between 1 and %d", maxSearchLimit)
+		}
+
+		req.Limit = int32(limit)
+	}
+
+	if skip, ok := args["skip"].(float64); ok && skip > 0 {
+		req.Skip = int32(skip)
+	}
+
+	if text, _ := args["text"].(string); text != "" {
+		req.TextQuery = &text
+	}
+
+	for key, target := range map[string]**string{
+		"amount_from": &req.AmountFrom,
+		"amount_to":   &req.AmountTo,
+	} {
+		val, _ := args[key].(string)
+		if val == "" {
+			continue
+		}
+
+		if _, err := decimal.NewFromString(val); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", key)
+		}
+
+		*target = &val
+	}
+
+	from, err := parseDateArg(args, "from")
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := parseDateArg(args, "to")
+	if err != nil {
+		return nil, err
+	}
+
+	if from != nil {
+		start, _ := dayRange(*from, *from, s.cfg.Location)
+		req.FromDate = timestamppb.New(start)
+	}
+
+	if to != nil {
+		_, end := dayRange(*to, *to, s.cfg.Location)
+		req.ToDate = timestamppb.New(end)
+	}
+
+	for key, target := range map[string]*[]int32{
+		"account_ids":             &req.AnyAccountIds,
+		"source_account_ids":      &req.SourceAccountIds,
+		"destination_account_ids": &req.DestinationAccountIds,
+		"category_ids":            &req.CategoryIds,
+		"tag_ids":                 &req.TagIds,
+	} {
+		ids, parseErr := parseInt32SliceArg(args, key)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+
+		*target = ids
+	}
+
+	if raw, ok := args["types"].([]any); ok {
+		for _, v := range raw {
+			name, _ := v.(string)
+
+			txType, ok := transactionTypes[strings.ToLower(name)]
+			if !ok {
+				return nil, errors.Newf("unsupported transaction type: %v", v)
+			}
+
+			req.TransactionTypes = append(req.TransactionTypes, txType)
+		}
+	}
+
+	return req, nil
+}
+
+// parseTransactionSortArg reads "field" or "field asc|desc" items, ascending by default.
+func parseTransactionSortArg(args map[string]any) ([]*transactions.Sort, error) {
+	raw, ok := args["sort"].([]any)
+	if !ok {
+		return nil, nil
+	}
+
+	result := make([]*transactions.Sort, 0, len(raw))
+	for i, v := range raw {
+		item, _ := v.(string)
+		name, direction, _ := strings.Cut(strings.TrimSpace(strings.ToLower(item)), " ")
+
+		field, found := transactionSortFields[name]
+		if !found {
+			return nil, errors.Newf("sort[%d] must be one of date, created_at, amount, base_amount, title, category, account", i)
+		}
+
+		direction = strings.TrimSpace(direction)
+		if direction != "" && direction != "asc" && direction != "desc" {
+			return nil, errors.Newf("sort[%d] direction must be asc or desc", i)
+		}
+
+		result = append(result, &transactions.Sort{Field: field, Ascending: direction != "desc"})
+	}
+
+	return result, nil
+}
+
+func (s *Server) handleSearchTransactions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	filter, err := s.buildSearchTransactionsRequest(args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	sorts, err := parseTransactionSortArg(args)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	cursor, _ := args["cursor"].(string)
+	if cursor != "" && filter.Skip > 0 {
+		return mcp.NewToolResultError("skip can not be combined with cursor"), nil
+	}
+
+	includeCount := cursor == ""
+	if v, ok := args["include_count"].(bool); ok {
+		includeCount = v
+	}
+
+	filter.Limit = min(filter.Limit, int32(rowBudget(ctx)))
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	resp, err := s.cfg.TransactionSvc.ListPage(queryCtx, &transactions.ListRequest{
+		Filter:       filter,
+		Sort:         sorts,
+		Cursor:       cursor,
+		IncludeCount: includeCount,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to search transactions: %v", err)), nil
+	}
+
+	reportRows(ctx, len(resp.Transactions))
+
+	return toolJSONResult(&searchTransactionsOutput{
+		TotalCount: resp.TotalCount,
+		Transactions: lo.Map(resp.Transactions, func(tx *gomoneypbv1.Transaction, _ int) *transactionOutput {
+			return mapTransactionOutput(tx)
+		}),
+		NextCursor: resp.NextCursor,
+	})
+}
+
+func mapTransactionOutput(tx *gomoneypbv1.Transaction) *transactionOutput {
+	out := &transactionOutput{
+		ID:                   tx.Id,
+		Type:                 transactionTypeNames[tx.Type],
+		Title:                tx.Title,
+		SourceAccountID:      tx.SourceAccountId,
+		SourceAmount:         tx.SourceAmount,
+		SourceCurrency:       tx.SourceCurrency,
+		DestinationAccountID: tx.DestinationAccountId,
+		DestinationAmount:    tx.DestinationAmount,
+		DestinationCurrency:  tx.DestinationCurrency,
+		CategoryID:           tx.CategoryId,
+		TagIDs:               tx.TagIds,
+		Notes:                tx.Notes,
+		ReferenceNumber:      lo.FromPtr(tx.ReferenceNumber),
+	}
+
+	if tx.TransactionDate != nil {
+		out.TransactionDate = tx.TransactionDate.AsTime().Format(time.RFC3339)
+	}
+
+	return out
+}
+
+func (s *Server) handleGetTransactionHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := request.GetArguments()
+
+	transactionID, ok := args["transaction_id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("transaction_id parameter is required"), nil
+	}
+
+	includeSnapshot, _ := args["include_snapshot"].(bool)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	events, err := s.cfg.HistorySvc.List(queryCtx, int64(transactionID))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to get transaction history: %v", err)), nil
+	}
+
+	return toolJSONResult(lo.Map(events, func(event *database.TransactionHistory, _ int) *transactionHistoryOutput {
+		return mapHistoryEvent(event, includeSnapshot)
+	}))
+}
+
+func mapHistoryEvent(event *database.TransactionHistory, includeSnapshot bool) *transactionHistoryOutput {
+	out := &transactionHistoryOutput{
+		ID:                  event.ID,
+		EventType:           historyEventTypeNames[event.EventType],
+		ActorType:           historyActorTypeNames[event.ActorType],
+		ActorUserID:         event.ActorUserID,
+		ActorRuleID:         event.ActorRuleID,
+		ActorRuleRevisionID: event.ActorRuleRevisionID,
+		ActorExtra:          event.ActorExtra,
+		Diff:                event.Diff,
+		OccurredAt:          event.OccurredAt.UTC().Format(time.RFC3339),
+	}
+
+	if includeSnapshot {
+		out.Snapshot = event.Snapshot
+	}
+
+	return out
+}
@@ -0,0 +1,254 @@
+package mcp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions"
+)
+
+func TestServer_HandleSearchTransactions(t *testing.T) {
+	newServer := func(t *testing.T, txSvc *MockTransactionService, loc *time.Location) *gomcp.Server {
+		gormDB, mockDB, _ := testingutils.GormMock()
+		t.Cleanup(func() { _ = mockDB.Close() })
+
+		return gomcp.NewServer(&gomcp.ServerConfig{
+			DB:             gormDB,
+			Docs:           "test docs",
+			TransactionSvc: txSvc,
+			Location:       loc,
+		})
+	}
+
+	t.Run("maps filters", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Kyiv")
+		require.NoError(t, err)
+
+		txSvc := NewMockTransactionService(gomock.NewController(t))
+		txSvc.EXPECT().ListPage(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, page *transactions.ListRequest) (*transactions.ListResponse, error) {
+				req := page.Filter
+				assert.EqualValues(t, 20, req.Limit)
+				assert.EqualValues(t, 40, req.Skip)
+				assert.Equal(t, "grocer", *req.TextQuery)
+				assert.Equal(t, "10", *req.AmountFrom)
+				assert.Nil(t, req.AmountTo)
+				assert.Equal(t, []int32{1, 2}, req.AnyAccountIds)
+				assert.Equal(t, []int32{7}, req.CategoryIds)
+				assert.Equal(t, []gomoneypbv1.TransactionType{gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE}, req.TransactionTypes)
+				assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, loc), req.FromDate.AsTime().In(loc))
+				assert.Equal(t, time.Date(2026, 3, 31, 23, 59, 59, 999999999, loc), req.ToDate.AsTime().In(loc))
+				assert.Empty(t, page.Sort)
+				assert.Empty(t, page.Cursor)
+				assert.True(t, page.IncludeCount)
+
+				return &transactions.ListResponse{
+					TotalCount: lo.ToPtr(int64(41)),
+					Transactions: []*gomoneypbv1.Transaction{
+						{
+							Id:                12,
+							Type:              gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+							Title:             "Grocery store",
+							SourceAccountId:   1,
+							SourceAmount:      "-25.40",
+							SourceCurrency:    "USD",
+							CategoryId:        lo.ToPtr(int32(7)),
+							TransactionDate:   timestamppb.New(time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)),
+							DestinationAmount: "25.40",
+						},
+					},
+				}, nil
+			})
+
+		result := callTool(t, newServer(t, txSvc, loc), "search_transactions", map[string]any{
+			"text":         "grocer",
+			"from":         "2026-03-01",
+			"to":           "2026-03-31",
+			"account_ids":  []any{float64(1), float64(2)},
+			"category_ids": []any{float64(7)},
+			"types":        []any{"expense"},
+			"amount_from":  "10",
+			"limit":        float64(20),
+			"skip":         float64(40),
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"total_count": 41`)
+		assert.Contains(t, text, `"type": "expense"`)
+		assert.Contains(t, text, `"transaction_date": "2026-03-05T10:00:00Z"`)
+		assert.Contains(t, text, `"category_id": 7`)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		txSvc := NewMockTransactionService(gomock.NewController(t))
+		txSvc.EXPECT().ListPage(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, page *transactions.ListRequest) (*transactions.ListResponse, error) {
+				assert.EqualValues(t, 50, page.Filter.Limit)
+				assert.Nil(t, page.Filter.FromDate)
+				assert.Nil(t, page.Filter.TextQuery)
+
+				return &transactions.ListResponse{TotalCount: lo.ToPtr(int64(0))}, nil
+			})
+
+		result := callTool(t, newServer(t, txSvc, nil), "search_transactions", map[string]any{})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"total_count": 0`)
+	})
+
+	t.Run("sort and cursor", func(t *testing.T) {
+		txSvc := NewMockTransactionService(gomock.NewController(t))
+		txSvc.EXPECT().ListPage(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, page *transactions.ListRequest) (*transactions.ListResponse, error) {
+				assert.Equal(t, []*transactions.Sort{
+					{Field: transactions.SortFieldBaseAmount, Ascending: false},
+					{Field: transactions.SortFieldTitle, Ascending: true},
+				}, page.Sort)
+				assert.Equal(t, "page-2", page.Cursor)
+				assert.False(t, page.IncludeCount)
+
+				return &transactions.ListResponse{
+					Transactions: []*gomoneypbv1.Transaction{{Id: 5}},
+					NextCursor:   "page-3",
+				}, nil
+			})
+
+		result := callTool(t, newServer(t, txSvc, nil), "search_transactions", map[string]any{
+			"sort":   []any{"base_amount desc", "Title"},
+			"cursor": "page-2",
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"next_cursor": "page-3"`)
+		assert.NotContains(t, text, "total_count")
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			args     map[string]any
+			expected string
+		}{
+			"limit":  {args: map[string]any{"limit": float64(1000)}, expected: "limit must be between 1 and 500"},
+			"amount": {args: map[string]any{"amount_to": "ten"}, expected: "invalid amount_to"},
+			"date":   {args: map[string]any{"from": "March"}, expected: "invalid from"},
+			"type":   {args: map[string]any{"types": []any{"refund"}}, expected: "unsupported transaction type: refund"},
+			"ids":    {args: map[string]any{"tag_ids": []any{"x"}}, expected: "tag_ids[0] must be a number"},
+			"sort":   {args: map[string]any{"sort": []any{"payee"}}, expected: "sort[0] must be one of"},
+			"order":  {args: map[string]any{"sort": []any{"amount up"}}, expected: "sort[0] direction must be asc or desc"},
+			"cursor": {args: map[string]any{"cursor": "abc", "skip": float64(10)}, expected: "skip can not be combined with cursor"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				result := callTool(t, newServer(t, NewMockTransactionService(gomock.NewController(t)), nil), "search_transactions", c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		txSvc := NewMockTransactionService(gomock.NewController(t))
+		txSvc.EXPECT().ListPage(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newServer(t, txSvc, nil), "search_transactions", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to search transactions")
+	})
+}
+
+func TestServer_HandleGetTransactionHistory(t *testing.T) {
+	newServer := func(t *testing.T, historySvc *MockTransactionHistoryService) *gomcp.Server {
+		gormDB, mockDB, _ := testingutils.GormMock()
+		t.Cleanup(func() { _ = mockDB.Close() })
+
+		return gomcp.NewServer(&gomcp.ServerConfig{
+			DB:         gormDB,
+			Docs:       "test docs",
+			HistorySvc: historySvc,
+		})
+	}
+
+	events := []*database.TransactionHistory{
+		{
+			ID:            1,
+			TransactionID: 9,
+			EventType:     database.TransactionHistoryEventTypeCreated,
+			ActorType:     database.TransactionHistoryActorTypeImporter,
+			Snapshot:      map[string]any{"title": "Coffee"},
+			OccurredAt:    time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:            2,
+			TransactionID: 9,
+			EventType:     database.TransactionHistoryEventTypeRuleApplied,
+			ActorType:     database.TransactionHistoryActorTypeRule,
+			ActorRuleID:   lo.ToPtr(int32(4)),
+			Snapshot:      map[string]any{"title": "Coffee", "category_id": 3},
+			Diff:          map[string]any{"category_id": map[string]any{"from": nil, "to": 3}},
+			OccurredAt:    time.Date(2026, 3, 1, 8, 0, 1, 0, time.UTC),
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		historySvc := NewMockTransactionHistoryService(gomock.NewController(t))
+		historySvc.EXPECT().List(gomock.Any(), int64(9)).Return(events, nil)
+
+		result := callTool(t, newServer(t, historySvc), "get_transaction_history", map[string]any{
+			"transaction_id": float64(9),
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"event_type": "rule_applied"`)
+		assert.Contains(t, text, `"actor_type": "importer"`)
+		assert.Contains(t, text, `"actor_rule_id": 4`)
+		assert.NotContains(t, text, "snapshot")
+	})
+
+	t.Run("with snapshot", func(t *testing.T) {
+		historySvc := NewMockTransactionHistoryService(gomock.NewController(t))
+		historySvc.EXPECT().List(gomock.Any(), int64(9)).Return(events, nil)
+
+		result := callTool(t, newServer(t, historySvc), "get_transaction_history", map[string]any{
+			"transaction_id":   float64(9),
+			"include_snapshot": true,
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"snapshot"`)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		historySvc := NewMockTransactionHistoryService(gomock.NewController(t))
+		historySvc.EXPECT().List(gomock.Any(), int64(9)).Return(nil, assert.AnError)
+
+		result := callTool(t, newServer(t, historySvc), "get_transaction_history", map[string]any{
+			"transaction_id": float64(9),
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to get transaction history")
+	})
+
+	t.Run("transaction_id required", func(t *testing.T) {
+		result := callTool(t, newServer(t, NewMockTransactionHistoryService(gomock.NewController(t))), "get_transaction_history", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "transaction_id parameter is required")
+	})
+}
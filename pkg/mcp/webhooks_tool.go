@@ -0,0 +1,336 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/webhooks"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+)
+
+var webhookDeliveryStatuses = map[string]database.WebhookDeliveryStatus{
+	"pending":   database.WebhookDeliveryStatusPending,
+	"delivered": database.WebhookDeliveryStatusDelivered,
+	"failed":    database.WebhookDeliveryStatusFailed,
+}
+
+var webhookDeliveryStatusNames = lo.Invert(webhookDeliveryStatuses)
+
+type webhookOutput struct {
+	ID         int32    `json:"id"`
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"` // returned on create only
+	EventTypes []string `json:"event_types"`
+	Enabled    bool     `json:"enabled"`
+	LastFeedID int64    `json:"last_feed_id"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type webhookDeliveryOutput struct {
+	ID            int64          `json:"id"`
+	WebhookID     int32          `json:"webhook_id"`
+	HistoryID     int64          `json:"history_id,omitempty"`
+	Event         string         `json:"event"`
+	Status        string         `json:"status"`
+	Attempts      int32          `json:"attempts"`
+	ResponseCode  *int32         `json:"response_code,omitempty"`
+	Error         *string        `json:"error,omitempty"`
+	DurationMs    int64          `json:"duration_ms"`
+	NextAttemptAt *string        `json:"next_attempt_at,omitempty"`
+	LastAttemptAt *string        `json:"last_attempt_at,omitempty"`
+	DeliveredAt   *string        `json:"delivered_at,omitempty"`
+	CreatedAt     string         `json:"created_at"`
+	Payload       map[string]any `json:"payload,omitempty"`
+}
+
+func mapWebhook(hook *database.Webhook) *webhookOutput {
+	return &webhookOutput{
+		ID:   hook.ID,
+		Name: hook.Name,
+		URL:  hook.URL,
+		EventTypes: lo.Map(hook.EventTypes, func(t int32, _ int) string {
+			return historyEventTypeNames[database.TransactionHistoryEventType(t)]
+		}),
+		Enabled:    hook.Enabled,
+		LastFeedID: hook.LastFeedID,
+		CreatedAt:  hook.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+
+	return lo.ToPtr(t.UTC().Format(time.RFC3339))
+}
+
+func mapWebhookDelivery(delivery *database.WebhookDelivery, includePayload bool) *webhookDeliveryOutput {
+	out := &webhookDeliveryOutput{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		HistoryID:     delivery.HistoryID,
+		Event:         webhooks.EventPing,
+		Status:        webhookDeliveryStatusNames[delivery.Status],
+		Attempts:      delivery.Attempts,
+		ResponseCode:  delivery.ResponseCode,
+		Error:         delivery.Error,
+		DurationMs:    delivery.DurationMs,
+		LastAttemptAt: formatOptionalTime(delivery.LastAttemptAt),
+		DeliveredAt:   formatOptionalTime(delivery.DeliveredAt),
+		CreatedAt:     delivery.CreatedAt.UTC().Format(time.RFC3339),
+	}
+
+	if delivery.HistoryID > 0 {
+		out.Event = historyEventTypeNames[delivery.EventType]
+	}
+
+	if delivery.Status == database.WebhookDeliveryStatusPending {
+		out.NextAttemptAt = formatOptionalTime(&delivery.NextAttemptAt)
+	}
+
+	if includePayload {
+		out.Payload = delivery.Payload
+	}
+
+	return out
+}
+
+func (s *Server) handleListWebhooks(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.cfg.WebhookSvc == nil {
+		return mcp.NewToolResultError("webhooks are disabled"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	hooks, err := s.cfg.WebhookSvc.ListWebhooks(queryCtx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list webhooks: %v", err)), nil
+	}
+
+	return toolJSONResult(lo.Map(hooks, func(hook *database.Webhook, _ int) *webhookOutput {
+		return mapWebhook(hook)
+	}))
+}
+
+func (s *Server) handleCreateWebhook(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.cfg.WebhookSvc == nil {
+		return mcp.NewToolResultError("webhooks are disabled"), nil
+	}
+
+	args := request.GetArguments()
+
+	req := &webhooks.CreateWebhookRequest{}
+	req.Name, _ = args["name"].(string)
+	req.URL, _ = args["url"].(string)
+	req.Secret, _ = args["secret"].(string)
+
+	if enabled, ok := args["enabled"].(bool); ok {
+		req.Disabled = !enabled
+	}
+
+	eventTypes, _, err := parseEventTypesArg(args, "event_types")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	req.EventTypes = eventTypes
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	hook, err := s.cfg.WebhookSvc.CreateWebhook(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create webhook: %v", err)), nil
+	}
+
+	out := mapWebhook(hook)
+	out.Secret = hook.Secret
+
+	return toolJSONResult(out)
+}
+
+func (s *Server) handleUpdateWebhook(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.cfg.WebhookSvc == nil {
+		return mcp.NewToolResultError("webhooks are disabled"), nil
+	}
+
+	args := request.GetArguments()
+
+	id, ok := args["id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("id parameter is required"), nil
+	}
+
+	req := &webhooks.UpdateWebhookRequest{ID: int32(id)}
+
+	if v, exists := args["name"].(string); exists {
+		req.Name = &v
+	}
+
+	if v, exists := args["url"].(string); exists {
+		req.URL = &v
+	}
+
+	if v, exists := args["secret"].(string); exists {
+		req.Secret = &v
+	}
+
+	if v, exists := args["enabled"].(bool); exists {
+		req.Enabled = &v
+	}
+
+	eventTypes, exists, err := parseEventTypesArg(args, "event_types")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if exists {
+		req.EventTypes = &eventTypes
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	hook, err := s.cfg.WebhookSvc.UpdateWebhook(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to update webhook: %v", err)), nil
+	}
+
+	return toolJSONResult(mapWebhook(hook))
+}
+
+func (s *Server) handleDeleteWebhook(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.cfg.WebhookSvc == nil {
+		return mcp.NewToolResultError("webhooks are disabled"), nil
+	}
+
+	id, ok := request.GetArguments()["id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	hook, err := s.cfg.WebhookSvc.DeleteWebhook(queryCtx, int32(id))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to delete webhook: %v", err)), nil
+	}
+
+	return toolJSONResult(mapWebhook(hook))
+}
+
+func (s *Server) handlePingWebhook(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.cfg.WebhookSvc == nil {
+		return mcp.NewToolResultError("webhooks are disabled"), nil
+	}
+
+	id, ok := request.GetArguments()["id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	delivery, err := s.cfg.WebhookSvc.Ping(queryCtx, int32(id))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to ping webhook: %v", err)), nil
+	}
+
+	return toolJSONResult(mapWebhookDelivery(delivery, false))
+}
+
+func (s *Server) handleListWebhookDeliveries(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.cfg.WebhookSvc == nil {
+		return mcp.NewToolResultError("webhooks are disabled"), nil
+	}
+
+	args := request.GetArguments()
+
+	req := &webhooks.ListDeliveriesRequest{Limit: defaultSearchLimit}
+
+	webhookID, err := parseOptionalInt32PtrArg(args, "webhook_id")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	req.WebhookID = webhookID
+
+	if v, ok := args["limit"].(float64); ok {
+		req.Limit = int(v)
+		if req.Limit < 1 || req.Limit > maxSearchLimit {
+			return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit)), nil
+		}
+	}
+
+	if raw, ok := args["statuses"].([]any); ok {
+		for i, v := range raw {
+			name, _ := v.(string)
+
+			status, found := webhookDeliveryStatuses[name]
+			if !found {
+				return mcp.NewToolResultError(fmt.Sprintf("statuses[%d] must be one of pending, delivered, failed", i)), nil
+			}
+
+			req.Statuses = append(req.Statuses, status)
+		}
+	}
+
+	includePayload, _ := args["include_payload"].(bool)
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	deliveries, err := s.cfg.WebhookSvc.ListDeliveries(queryCtx, req)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to list webhook deliveries: %v", err)), nil
+	}
+
+	reportRows(ctx, len(deliveries))
+
+	return toolJSONResult(lo.Map(deliveries, func(delivery *database.WebhookDelivery, _ int) *webhookDeliveryOutput {
+		return mapWebhookDelivery(delivery, includePayload)
+	}))
+}
+
+func (s *Server) handleRetryWebhookDelivery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.cfg.WebhookSvc == nil {
+		return mcp.NewToolResultError("webhooks are disabled"), nil
+	}
+
+	id, ok := request.GetArguments()["id"].(float64)
+	if !ok {
+		return mcp.NewToolResultError("id parameter is required"), nil
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	queryCtx = database.WithContext(queryCtx, s.db)
+
+	delivery, err := s.cfg.WebhookSvc.RetryDelivery(queryCtx, int64(id))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to retry webhook delivery: %v", err)), nil
+	}
+
+	return toolJSONResult(mapWebhookDelivery(delivery, false))
+}
@@ -0,0 +1,228 @@
+package mcp_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/database"
+	gomcp "github.com/ft-t/go-money/pkg/mcp"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/webhooks"
+)
+
+func newWebhooksTestServer(t *testing.T, webhookSvc gomcp.WebhooksService) *gomcp.Server {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	return gomcp.NewServer(&gomcp.ServerConfig{
+		DB:         gormDB,
+		Docs:       "test docs",
+		WebhookSvc: webhookSvc,
+	})
+}
+
+func testWebhook() *database.Webhook {
+	return &database.Webhook{
+		ID:         3,
+		Name:       "ledger",
+		URL:        "https://example.com/hook",
+		Secret:     "0123456789abcdef0123",
+		EventTypes: pq.Int32Array{int32(database.TransactionHistoryEventTypeCreated)},
+		Enabled:    true,
+		LastFeedID: 120,
+		CreatedAt:  time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestServer_HandleWebhooks(t *testing.T) {
+	t.Run("list hides secret", func(t *testing.T) {
+		webhookSvc := NewMockWebhooksService(gomock.NewController(t))
+		webhookSvc.EXPECT().ListWebhooks(gomock.Any()).Return([]*database.Webhook{testWebhook()}, nil)
+
+		result := callTool(t, newWebhooksTestServer(t, webhookSvc), "list_webhooks", map[string]any{})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"event_types": [`)
+		assert.Contains(t, text, `"created"`)
+		assert.Contains(t, text, `"last_feed_id": 120`)
+		assert.NotContains(t, text, "secret")
+	})
+
+	t.Run("create returns secret", func(t *testing.T) {
+		webhookSvc := NewMockWebhooksService(gomock.NewController(t))
+		webhookSvc.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *webhooks.CreateWebhookRequest) (*database.Webhook, error) {
+				assert.Equal(t, "ledger", req.Name)
+				assert.Equal(t, "https://example.com/hook", req.URL)
+				assert.True(t, req.Disabled)
+				assert.Equal(t, []database.TransactionHistoryEventType{database.TransactionHistoryEventTypeCreated}, req.EventTypes)
+
+				return testWebhook(), nil
+			})
+
+		result := callTool(t, newWebhooksTestServer(t, webhookSvc), "create_webhook", map[string]any{
+			"name":        "ledger",
+			"url":         "https://example.com/hook",
+			"enabled":     false,
+			"event_types": []any{"created"},
+		})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"secret": "0123456789abcdef0123"`)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		webhookSvc := NewMockWebhooksService(gomock.NewController(t))
+		webhookSvc.EXPECT().UpdateWebhook(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *webhooks.UpdateWebhookRequest) (*database.Webhook, error) {
+				assert.EqualValues(t, 3, req.ID)
+				assert.Nil(t, req.Name)
+				assert.Equal(t, lo.ToPtr(false), req.Enabled)
+				assert.NotNil(t, req.EventTypes)
+				assert.Empty(t, *req.EventTypes)
+
+				return testWebhook(), nil
+			})
+
+		result := callTool(t, newWebhooksTestServer(t, webhookSvc), "update_webhook", map[string]any{
+			"id":          float64(3),
+			"enabled":     false,
+			"event_types": []any{},
+		})
+
+		assert.False(t, result.IsError)
+		assert.NotContains(t, result.Content[0].(mcp.TextContent).Text, "secret")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		webhookSvc := NewMockWebhooksService(gomock.NewController(t))
+		webhookSvc.EXPECT().DeleteWebhook(gomock.Any(), int32(3)).Return(testWebhook(), nil)
+
+		result := callTool(t, newWebhooksTestServer(t, webhookSvc), "delete_webhook", map[string]any{"id": float64(3)})
+
+		assert.False(t, result.IsError)
+	})
+
+	t.Run("ping", func(t *testing.T) {
+		webhookSvc := NewMockWebhooksService(gomock.NewController(t))
+		webhookSvc.EXPECT().Ping(gomock.Any(), int32(3)).Return(&database.WebhookDelivery{
+			ID:           7,
+			WebhookID:    3,
+			Status:       database.WebhookDeliveryStatusFailed,
+			Attempts:     1,
+			ResponseCode: lo.ToPtr(int32(500)),
+			Error:        lo.ToPtr("unexpected status code 500"),
+		}, nil)
+
+		result := callTool(t, newWebhooksTestServer(t, webhookSvc), "ping_webhook", map[string]any{"id": float64(3)})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"event": "ping"`)
+		assert.Contains(t, text, `"status": "failed"`)
+		assert.Contains(t, text, `"response_code": 500`)
+	})
+
+	t.Run("list deliveries", func(t *testing.T) {
+		webhookSvc := NewMockWebhooksService(gomock.NewController(t))
+		webhookSvc.EXPECT().ListDeliveries(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *webhooks.ListDeliveriesRequest) ([]*database.WebhookDelivery, error) {
+				assert.Equal(t, lo.ToPtr(int32(3)), req.WebhookID)
+				assert.Equal(t, []database.WebhookDeliveryStatus{database.WebhookDeliveryStatusPending}, req.Statuses)
+				assert.Equal(t, 50, req.Limit)
+
+				return []*database.WebhookDelivery{
+					{
+						ID:            8,
+						WebhookID:     3,
+						HistoryID:     121,
+						EventType:     database.TransactionHistoryEventTypeUpdated,
+						Payload:       map[string]any{"event": "updated"},
+						Status:        database.WebhookDeliveryStatusPending,
+						Attempts:      2,
+						NextAttemptAt: time.Date(2026, 8, 1, 1, 0, 0, 0, time.UTC),
+					},
+				}, nil
+			})
+
+		result := callTool(t, newWebhooksTestServer(t, webhookSvc), "list_webhook_deliveries", map[string]any{
+			"webhook_id":      float64(3),
+			"statuses":        []any{"pending"},
+			"include_payload": true,
+		})
+
+		assert.False(t, result.IsError)
+		text := result.Content[0].(mcp.TextContent).Text
+		assert.Contains(t, text, `"event": "updated"`)
+		assert.Contains(t, text, `"history_id": 121`)
+		assert.Contains(t, text, `"next_attempt_at": "2026-08-01T01:00:00Z"`)
+		assert.Contains(t, text, `"payload"`)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		webhookSvc := NewMockWebhooksService(gomock.NewController(t))
+		webhookSvc.EXPECT().RetryDelivery(gomock.Any(), int64(8)).Return(&database.WebhookDelivery{
+			ID:        8,
+			WebhookID: 3,
+			HistoryID: 121,
+			EventType: database.TransactionHistoryEventTypeUpdated,
+			Status:    database.WebhookDeliveryStatusPending,
+		}, nil)
+
+		result := callTool(t, newWebhooksTestServer(t, webhookSvc), "retry_webhook_delivery", map[string]any{"id": float64(8)})
+
+		assert.False(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, `"status": "pending"`)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for name, c := range map[string]struct {
+			tool     string
+			args     map[string]any
+			expected string
+		}{
+			"update id":  {tool: "update_webhook", args: map[string]any{}, expected: "id parameter is required"},
+			"delete id":  {tool: "delete_webhook", args: map[string]any{}, expected: "id parameter is required"},
+			"ping id":    {tool: "ping_webhook", args: map[string]any{}, expected: "id parameter is required"},
+			"retry id":   {tool: "retry_webhook_delivery", args: map[string]any{}, expected: "id parameter is required"},
+			"event type": {tool: "create_webhook", args: map[string]any{"name": "a", "url": "https://a", "event_types": []any{"x"}}, expected: "event_types[0] must be one of"},
+			"status":     {tool: "list_webhook_deliveries", args: map[string]any{"statuses": []any{"lost"}}, expected: "statuses[0] must be one of"},
+			"limit":      {tool: "list_webhook_deliveries", args: map[string]any{"limit": float64(0)}, expected: "limit must be between 1 and 500"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				result := callTool(t, newWebhooksTestServer(t, NewMockWebhooksService(gomock.NewController(t))), c.tool, c.args)
+
+				assert.True(t, result.IsError)
+				assert.Contains(t, result.Content[0].(mcp.TextContent).Text, c.expected)
+			})
+		}
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		webhookSvc := NewMockWebhooksService(gomock.NewController(t))
+		webhookSvc.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		result := callTool(t, newWebhooksTestServer(t, webhookSvc), "create_webhook", map[string]any{
+			"name": "ledger",
+			"url":  "https://example.com/hook",
+		})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "failed to create webhook")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		result := callTool(t, newWebhooksTestServer(t, nil), "list_webhooks", map[string]any{})
+
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "webhooks are disabled")
+	})
+}
@@ -0,0 +1,236 @@
+package suggestions
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/samber/lo"
+)
+
+const (
+	titleWeight        = 3.0 // shared by all title tokens
+	counterpartyWeight = 2.0
+	amountWeight       = 1.0
+	weekdayWeight      = 0.5
+
+	minTokenLength = 3
+	maxTitleTokens = 8
+
+	maxCategoryCandidates = 3
+	maxTagCandidates      = 5
+	minCandidateScore     = 0.05
+	minTagConfidence      = 0.3
+)
+
+type feature struct {
+	key    string
+	weight float64
+}
+
+// Tokenize lower cases a title and splits it into words. Short words and words with digits
+// (card numbers, dates, references) are dropped since they rarely repeat.
+func Tokenize(title string) []string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var tokens []string
+	for _, word := range words {
+		if len([]rune(word)) < minTokenLength || strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			continue
+		}
+
+		tokens = append(tokens, word)
+	}
+
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	tokens = lo.Uniq(tokens)
+	if len(tokens) > maxTitleTokens {
+		tokens = tokens[:maxTitleTokens]
+	}
+
+	return tokens
+}
+
+// amountBand groups amounts into half decades: 1-3, 3-10, 10-31, 31-100 and so on.
+func amountBand(in *Input) (int, bool) {
+	amount, _ := in.Amount.Abs().Float64()
+	if amount <= 0 {
+		return 0, false
+	}
+
+	return int(math.Floor(math.Log10(amount) * 2)), true
+}
+
+func counterparty(in *Input) int32 {
+	switch in.TransactionType {
+	case gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE:
+		return in.DestinationAccountID
+	case gomoneypbv1.TransactionType_TRANSACTION_TYPE_INCOME:
+		return in.SourceAccountID
+	default:
+		return 0
+	}
+}
+
+// features are keyed by transaction type, so expense history never votes for income rows.
+func features(in *Input) []feature {
+	prefix := fmt.Sprintf("%d|", in.TransactionType)
+
+	var result []feature
+
+	tokens := Tokenize(in.Title)
+	for _, token := range tokens {
+		result = append(result, feature{key: prefix + "t:" + token, weight: titleWeight / float64(len(tokens))})
+	}
+
+	if id := counterparty(in); id != 0 {
+		result = append(result, feature{key: fmt.Sprintf("%sc:%d", prefix, id), weight: counterpartyWeight})
+	}
+
+	if band, ok := amountBand(in); ok {
+		result = append(result, feature{key: fmt.Sprintf("%sa:%d", prefix, band), weight: amountWeight})
+	}
+
+	if !in.Date.IsZero() {
+		result = append(result, feature{key: fmt.Sprintf("%sw:%d", prefix, in.Date.Weekday()), weight: weekdayWeight})
+	}
+
+	return result
+}
+
+type labelStats struct {
+	featureCount map[string]int
+	labelCount   map[string]map[int32]int
+}
+
+func newLabelStats() *labelStats {
+	return &labelStats{
+		featureCount: map[string]int{},
+		labelCount:   map[string]map[int32]int{},
+	}
+}
+
+func (s *labelStats) add(fs []feature, labels []int32) {
+	for _, f := range fs {
+		s.featureCount[f.key]++
+
+		if s.labelCount[f.key] == nil {
+			s.labelCount[f.key] = map[int32]int{}
+		}
+
+		for _, label := range labels {
+			s.labelCount[f.key][label]++
+		}
+	}
+}
+
+// score is a weighted vote of the features. Every feature votes with the share of its samples
+// that carry the label, the +1 keeps features seen once from voting with full weight. Features
+// never seen in training still count towards the total, so a new title matched only by amount
+// and weekday stays at a low confidence.
+func (s *labelStats) score(fs []feature) []*Candidate {
+	scores := map[int32]float64{}
+	total := 0.0
+
+	for _, f := range fs {
+		total += f.weight
+
+		count := s.featureCount[f.key]
+		if count == 0 {
+			continue
+		}
+
+		for label, labelCount := range s.labelCount[f.key] {
+			scores[label] += f.weight * float64(labelCount) / float64(count+1)
+		}
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	candidates := make([]*Candidate, 0, len(scores))
+	for label, score := range scores {
+		confidence := score / total
+		if confidence < minCandidateScore {
+			continue
+		}
+
+		candidates = append(candidates, &Candidate{
+			ID:         label,
+			Confidence: math.Round(confidence*1000) / 1000,
+		})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	return candidates
+}
+
+// Model holds feature statistics of categorized and tagged transactions.
+type Model struct {
+	categories *labelStats
+	tags       *labelStats
+	Samples    int
+}
+
+// BuildModel learns from transactions that have a category or tags, others are skipped.
+func BuildModel(txs []*database.Transaction) *Model {
+	m := &Model{
+		categories: newLabelStats(),
+		tags:       newLabelStats(),
+	}
+
+	for _, tx := range txs {
+		if tx.CategoryID == nil && len(tx.TagIDs) == 0 {
+			continue
+		}
+
+		fs := features(InputFromTransaction(tx))
+
+		if tx.CategoryID != nil {
+			m.categories.add(fs, []int32{*tx.CategoryID})
+		}
+
+		m.tags.add(fs, lo.Uniq(tx.TagIDs))
+		m.Samples++
+	}
+
+	return m
+}
+
+func (m *Model) Suggest(in *Input) *Suggestion {
+	fs := features(in)
+
+	categories := m.categories.score(fs)
+	if len(categories) > maxCategoryCandidates {
+		categories = categories[:maxCategoryCandidates]
+	}
+
+	tags := lo.Filter(m.tags.score(fs), func(c *Candidate, _ int) bool {
+		return c.Confidence >= minTagConfidence
+	})
+	if len(tags) > maxTagCandidates {
+		tags = tags[:maxTagCandidates]
+	}
+
+	return &Suggestion{
+		Categories: categories,
+		Tags:       tags,
+	}
+}
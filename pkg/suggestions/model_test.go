@@ -0,0 +1,126 @@
+package suggestions_test
+
+import (
+	"testing"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/suggestions"
+)
+
+const (
+	groceries int32 = 3
+	transport int32 = 4
+	salary    int32 = 9
+	work      int32 = 21
+)
+
+func expense(title string, destination int32, amount int64, categoryID *int32, tagIDs ...int32) *database.Transaction {
+	return &database.Transaction{
+		Title:                           title,
+		TransactionType:                 gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+		SourceAccountID:                 1,
+		DestinationAccountID:            destination,
+		DestinationAmountInBaseCurrency: decimal.NewNullDecimal(decimal.NewFromInt(amount)),
+		TransactionDateTime:             time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+		CategoryID:                      categoryID,
+		TagIDs:                          tagIDs,
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		title    string
+		expected []string
+	}{
+		{title: "LIDL Warszawa 1234", expected: []string{"lidl", "warszawa"}},
+		{title: "Card *4411 UBER *TRIP help.uber.com", expected: []string{"card", "uber", "trip", "help", "com"}},
+		{title: "Żabka Z1234 Kraków", expected: []string{"żabka", "kraków"}},
+		{title: "a to 12", expected: nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.title, func(t *testing.T) {
+			assert.Equal(t, c.expected, suggestions.Tokenize(c.title))
+		})
+	}
+}
+
+func TestModel_Suggest(t *testing.T) {
+	cat := func(id int32) *int32 { return &id }
+
+	model := suggestions.BuildModel([]*database.Transaction{
+		expense("LIDL Warszawa 1", 50, 40, cat(groceries)),
+		expense("LIDL Warszawa 2", 50, 55, cat(groceries)),
+		expense("Lidl sp. z o.o.", 50, 35, cat(groceries)),
+		expense("UBER TRIP", 51, 20, cat(transport), work),
+		expense("UBER TRIP", 51, 25, cat(transport), work),
+		expense("Uber BV", 51, 18, nil, work),
+		expense("untouched", 52, 10, nil),
+		{
+			Title:               "LIDL refund",
+			TransactionType:     gomoneypbv1.TransactionType_TRANSACTION_TYPE_INCOME,
+			SourceAccountID:     60,
+			CategoryID:          cat(salary),
+			TransactionDateTime: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+	})
+
+	assert.Equal(t, 7, model.Samples)
+
+	t.Run("title and counterparty agree", func(t *testing.T) {
+		result := model.Suggest(suggestions.InputFromTransaction(expense("LIDL Warszawa 99", 50, 45, nil)))
+
+		require.NotEmpty(t, result.Categories)
+		assert.Equal(t, groceries, result.Categories[0].ID)
+		assert.Greater(t, result.Categories[0].Confidence, 0.6)
+		assert.Empty(t, result.Tags)
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		result := model.Suggest(suggestions.InputFromTransaction(expense("UBER TRIP", 51, 22, nil)))
+
+		require.NotEmpty(t, result.Categories)
+		assert.Equal(t, transport, result.Categories[0].ID)
+		require.Len(t, result.Tags, 1)
+		assert.Equal(t, work, result.Tags[0].ID)
+	})
+
+	t.Run("income history does not vote for expenses", func(t *testing.T) {
+		result := model.Suggest(&suggestions.Input{
+			Title:           "LIDL refund",
+			TransactionType: gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+		})
+
+		for _, c := range result.Categories {
+			assert.NotEqual(t, salary, c.ID)
+		}
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		result := model.Suggest(&suggestions.Input{
+			Title:           "something new",
+			TransactionType: gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+		})
+
+		assert.Empty(t, result.Categories)
+		assert.Empty(t, result.Tags)
+	})
+
+	t.Run("weak evidence has low confidence", func(t *testing.T) {
+		result := model.Suggest(&suggestions.Input{
+			Title:           "new shop",
+			TransactionType: gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+			Amount:          decimal.NewFromInt(40),
+			Date:            time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		})
+
+		require.NotEmpty(t, result.Categories)
+		assert.Less(t, result.Categories[0].Confidence, 0.6)
+	})
+}
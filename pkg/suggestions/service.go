@@ -0,0 +1,116 @@
+package suggestions
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/samber/lo"
+)
+
+const (
+	defaultLookback   = 2 * 365 * 24 * time.Hour
+	defaultMaxSamples = 50_000
+	defaultCacheTTL   = 10 * time.Minute
+)
+
+type ServiceConfig struct {
+	Lookback   time.Duration // only transactions this recent are learned from
+	MaxSamples int           // newest transactions first
+	CacheTTL   time.Duration // how long a built model is reused
+}
+
+// Service suggests categories and tags from the history of already categorized transactions.
+// The model is built in memory from the database and rebuilt once it is older than CacheTTL.
+type Service struct {
+	cfg *ServiceConfig
+
+	mut     sync.Mutex
+	model   *Model
+	builtAt time.Time
+}
+
+func NewService(cfg *ServiceConfig) *Service {
+	return &Service{
+		cfg: cfg,
+	}
+}
+
+func (s *Service) Suggest(ctx context.Context, inputs []*Input) ([]*Suggestion, error) {
+	model, err := s.getModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(inputs, func(in *Input, _ int) *Suggestion {
+		return model.Suggest(in)
+	}), nil
+}
+
+// SuggestForTransactions suggests for saved transactions, ids that do not exist are skipped.
+func (s *Service) SuggestForTransactions(ctx context.Context, ids []int64) ([]*TransactionSuggestion, error) {
+	var txs []*database.Transaction
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).
+		Where("id IN ? AND deleted_at IS NULL", ids).
+		Order("id").
+		Find(&txs).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get transactions")
+	}
+
+	model, err := s.getModel(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(txs, func(tx *database.Transaction, _ int) *TransactionSuggestion {
+		return &TransactionSuggestion{
+			TransactionID: tx.ID,
+			Suggestion:    model.Suggest(InputFromTransaction(tx)),
+		}
+	}), nil
+}
+
+// Invalidate drops the cached model, the next call learns from the current data.
+func (s *Service) Invalidate() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.model = nil
+}
+
+func (s *Service) getModel(ctx context.Context) (*Model, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	ttl := lo.CoalesceOrEmpty(s.cfg.CacheTTL, defaultCacheTTL)
+	if s.model != nil && time.Since(s.builtAt) < ttl {
+		return s.model, nil
+	}
+
+	var txs []*database.Transaction
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).
+		Select("id, title, transaction_type, source_account_id, destination_account_id, source_amount, "+
+			"destination_amount, source_amount_in_base_currency, destination_amount_in_base_currency, "+
+			"transaction_date_time, category_id, tag_ids").
+		Where("deleted_at IS NULL AND transaction_type IN ?", []gomoneypbv1.TransactionType{
+			gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+			gomoneypbv1.TransactionType_TRANSACTION_TYPE_INCOME,
+		}).
+		Where("(category_id IS NOT NULL OR cardinality(tag_ids) > 0)").
+		Where("transaction_date_time >= ?", time.Now().UTC().Add(-lo.CoalesceOrEmpty(s.cfg.Lookback, defaultLookback))).
+		Order("transaction_date_time DESC").
+		Limit(lo.CoalesceOrEmpty(s.cfg.MaxSamples, defaultMaxSamples)).
+		Find(&txs).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to load categorized transactions")
+	}
+
+	s.model = BuildModel(txs)
+	s.builtAt = time.Now()
+
+	return s.model, nil
+}
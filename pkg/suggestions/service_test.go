@@ -0,0 +1,107 @@
+package suggestions_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/suggestions"
+	"github.com/ft-t/go-money/pkg/testingutils"
+)
+
+var gormDB *gorm.DB
+var cfg *configuration.Configuration
+
+func TestMain(m *testing.M) {
+	cfg = configuration.GetConfiguration()
+	gormDB = database.GetDb(database.DbTypeMaster)
+
+	os.Exit(m.Run())
+}
+
+func TestService_Suggest(t *testing.T) {
+	require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	categoryID := groceries
+	now := time.Now().UTC()
+
+	newTx := func(title string, category *int32, date time.Time) *database.Transaction {
+		tx := expense(title, 50, 40, category)
+		tx.TransactionDateTime = date
+		tx.TransactionDateOnly = date
+		tx.Extra = map[string]string{}
+
+		return tx
+	}
+
+	recent := newTx("LIDL Warszawa", &categoryID, now.AddDate(0, 0, -3))
+	old := newTx("ALDI Berlin", &categoryID, now.AddDate(-3, 0, 0))
+	uncategorized := newTx("LIDL Krakow", nil, now.AddDate(0, 0, -1))
+
+	require.NoError(t, gormDB.Create(&[]*database.Transaction{recent, old, uncategorized}).Error)
+
+	svc := suggestions.NewService(&suggestions.ServiceConfig{
+		Lookback: 365 * 24 * time.Hour,
+	})
+
+	t.Run("suggest for input", func(t *testing.T) {
+		result, err := svc.Suggest(context.TODO(), []*suggestions.Input{
+			{
+				Title:                "LIDL Gdansk",
+				TransactionType:      gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+				DestinationAccountID: 50,
+				Amount:               decimal.NewFromInt(42),
+			},
+			{
+				Title:           "ALDI Berlin",
+				TransactionType: gomoneypbv1.TransactionType_TRANSACTION_TYPE_INCOME,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		require.NotEmpty(t, result[0].Categories)
+		assert.Equal(t, groceries, result[0].Categories[0].ID)
+		assert.Empty(t, result[1].Categories) // older than lookback and another type
+	})
+
+	t.Run("suggest for saved transactions", func(t *testing.T) {
+		result, err := svc.SuggestForTransactions(context.TODO(), []int64{uncategorized.ID, 999999})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+
+		assert.Equal(t, uncategorized.ID, result[0].TransactionID)
+		require.NotEmpty(t, result[0].Suggestion.Categories)
+		assert.Equal(t, groceries, result[0].Suggestion.Categories[0].ID)
+	})
+
+	t.Run("model is cached until invalidated", func(t *testing.T) {
+		transport := int32(4)
+		require.NoError(t, gormDB.Create(newTx("LIDL Gdansk", &transport, now)).Error)
+		require.NoError(t, gormDB.Create(newTx("LIDL Gdansk", &transport, now)).Error)
+
+		input := []*suggestions.Input{{
+			Title:           "LIDL Gdansk",
+			TransactionType: gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+		}}
+
+		cached, err := svc.Suggest(context.TODO(), input)
+		require.NoError(t, err)
+		assert.Equal(t, groceries, cached[0].Categories[0].ID)
+
+		svc.Invalidate()
+
+		fresh, err := svc.Suggest(context.TODO(), input)
+		require.NoError(t, err)
+		assert.Equal(t, transport, fresh[0].Categories[0].ID)
+	})
+}
@@ -0,0 +1,89 @@
+package suggestions
+
+import (
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
+)
+
+// Input is a transaction to suggest a category and tags for. It does not have to be saved,
+// import previews pass parsed rows.
+type Input struct {
+	Title                string
+	TransactionType      gomoneypbv1.TransactionType
+	SourceAccountID      int32
+	DestinationAccountID int32
+	Amount               decimal.Decimal // sign is ignored, base currency when known
+	Date                 time.Time
+}
+
+// Candidate is a suggested category or tag id with a confidence between 0 and 1.
+type Candidate struct {
+	ID         int32
+	Confidence float64
+}
+
+// Suggestion holds ranked candidates, the best one first.
+type Suggestion struct {
+	Categories []*Candidate
+	Tags       []*Candidate
+}
+
+type TransactionSuggestion struct {
+	TransactionID int64
+	Suggestion    *Suggestion
+}
+
+func InputFromTransaction(tx *database.Transaction) *Input {
+	amount := decimal.Zero
+
+	for _, val := range []decimal.NullDecimal{
+		tx.DestinationAmountInBaseCurrency,
+		tx.SourceAmountInBaseCurrency,
+		tx.DestinationAmount,
+		tx.SourceAmount,
+	} {
+		if val.Valid && !val.Decimal.IsZero() {
+			amount = val.Decimal.Abs()
+			break
+		}
+	}
+
+	return &Input{
+		Title:                tx.Title,
+		TransactionType:      tx.TransactionType,
+		SourceAccountID:      tx.SourceAccountID,
+		DestinationAccountID: tx.DestinationAccountID,
+		Amount:               amount,
+		Date:                 tx.TransactionDateTime,
+	}
+}
+
+// InputFromProto maps a parsed, not yet saved transaction such as an import preview row.
+func InputFromProto(tx *gomoneypbv1.Transaction) *Input {
+	amount := decimal.Zero
+
+	for _, val := range []string{tx.DestinationAmount, tx.SourceAmount} {
+		parsed, err := decimal.NewFromString(val)
+		if err == nil && !parsed.IsZero() {
+			amount = parsed.Abs()
+			break
+		}
+	}
+
+	in := &Input{
+		Title:                tx.Title,
+		TransactionType:      tx.Type,
+		SourceAccountID:      tx.SourceAccountId,
+		DestinationAccountID: tx.DestinationAccountId,
+		Amount:               amount,
+	}
+
+	if tx.TransactionDate != nil {
+		in.Date = tx.TransactionDate.AsTime()
+	}
+
+	return in
+}
@@ -8,6 +8,10 @@ import (
 
 //go:generate mockgen -destination interfaces_mocks_test.go -package tags_test -source=interfaces.go
 
+type SuggestionsSvc interface {
+	Invalidate()
+}
+
 type Mapper interface {
 	MapTag(ctx context.Context, tag *database.Tag) *gomoneypbv1.Tag
 }
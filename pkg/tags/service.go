@@ -10,14 +10,19 @@ import (
 )
 
 type Service struct {
-	mapper Mapper
+	mapper         Mapper
+	suggestionsSvc SuggestionsSvc
 }
 
+// NewService creates the service, suggestionsSvc is optional and drops the suggestion model when
+// a tag is deleted, so the deleted id is not suggested until the model expires.
 func NewService(
 	mapper Mapper,
+	suggestionsSvc SuggestionsSvc,
 ) *Service {
 	return &Service{
-		mapper: mapper,
+		mapper:         mapper,
+		suggestionsSvc: suggestionsSvc,
 	}
 }
 
@@ -146,6 +151,8 @@ func (s *Service) DeleteTag(ctx context.Context, req *tagsv1.DeleteTagRequest) e
 		return err
 	}
 
+	s.invalidateSuggestions()
+
 	return nil
 }
 
@@ -172,3 +179,11 @@ func (s *Service) UpdateTag(
 		Tag: s.mapper.MapTag(ctx, &existingTag),
 	}, nil
 }
+
+func (s *Service) invalidateSuggestions() {
+	if s.suggestionsSvc == nil {
+		return
+	}
+
+	s.suggestionsSvc.Invalidate()
+}
@@ -31,7 +31,7 @@ func TestCreateAndGet(t *testing.T) {
 
 	mapper := NewMockMapper(gomock.NewController(t))
 
-	srv := tags.NewService(mapper)
+	srv := tags.NewService(mapper, nil)
 
 	mapper.EXPECT().MapTag(gomock.Any(), gomock.Any()).
 		Return(&gomoneypbv1.Tag{})
@@ -59,7 +59,7 @@ func TestCreateDuplicateTagFail(t *testing.T) {
 
 	mapper := NewMockMapper(gomock.NewController(t))
 
-	srv := tags.NewService(mapper)
+	srv := tags.NewService(mapper, nil)
 
 	mapper.EXPECT().MapTag(gomock.Any(), gomock.Any()).
 		Return(&gomoneypbv1.Tag{})
@@ -86,7 +86,7 @@ func TestUpdateTag(t *testing.T) {
 
 		mapper := NewMockMapper(gomock.NewController(t))
 
-		srv := tags.NewService(mapper)
+		srv := tags.NewService(mapper, nil)
 
 		mapper.EXPECT().MapTag(gomock.Any(), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, tag *database.Tag) *gomoneypbv1.Tag {
@@ -124,7 +124,7 @@ func TestUpdateTag(t *testing.T) {
 	t.Run("tag not found", func(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
-		srv := tags.NewService(nil)
+		srv := tags.NewService(nil, nil)
 
 		_, err := srv.UpdateTag(context.TODO(), &tagsv1.UpdateTagRequest{
 			Name:  "some-name",
@@ -138,9 +138,11 @@ func TestUpdateTag(t *testing.T) {
 func TestDeleteTag(t *testing.T) {
 	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
-	mapper := NewMockMapper(gomock.NewController(t))
+	ctrl := gomock.NewController(t)
+	mapper := NewMockMapper(ctrl)
+	suggestionsSvc := NewMockSuggestionsSvc(ctrl)
 
-	srv := tags.NewService(mapper)
+	srv := tags.NewService(mapper, suggestionsSvc)
 
 	mapper.EXPECT().MapTag(gomock.Any(), gomock.Any()).
 		DoAndReturn(func(ctx context.Context, tag *database.Tag) *gomoneypbv1.Tag {
@@ -148,6 +150,7 @@ func TestDeleteTag(t *testing.T) {
 				Id: tag.ID,
 			}
 		})
+	suggestionsSvc.EXPECT().Invalidate()
 
 	resp, err := srv.CreateTag(context.TODO(), &tagsv1.CreateTagRequest{
 		Name:  "some-name",
@@ -172,7 +175,7 @@ func TestListTags(t *testing.T) {
 
 	mapper := NewMockMapper(gomock.NewController(t))
 
-	srv := tags.NewService(mapper)
+	srv := tags.NewService(mapper, nil)
 
 	mapper.EXPECT().MapTag(gomock.Any(), gomock.Any()).
 		DoAndReturn(func(ctx context.Context, tag *database.Tag) *gomoneypbv1.Tag {
@@ -211,7 +214,7 @@ func TestImportTags(t *testing.T) {
 
 	mapper := NewMockMapper(gomock.NewController(t))
 
-	srv := tags.NewService(mapper)
+	srv := tags.NewService(mapper, nil)
 
 	assert.NoError(t, gormDB.Create(&database.Tag{
 		Name:  "tag1",
@@ -251,7 +254,7 @@ func TestImportTags(t *testing.T) {
 
 func TestFind(t *testing.T) {
 	t.Run("fail list", func(t *testing.T) {
-		srv := tags.NewService(nil)
+		srv := tags.NewService(nil, nil)
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -262,7 +265,7 @@ func TestFind(t *testing.T) {
 	})
 
 	t.Run("fail getall", func(t *testing.T) {
-		srv := tags.NewService(nil)
+		srv := tags.NewService(nil, nil)
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -273,7 +276,7 @@ func TestFind(t *testing.T) {
 	})
 
 	t.Run("fail delete", func(t *testing.T) {
-		srv := tags.NewService(nil)
+		srv := tags.NewService(nil, nil)
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -285,7 +288,7 @@ func TestFind(t *testing.T) {
 	})
 
 	t.Run("fail import", func(t *testing.T) {
-		srv := tags.NewService(nil)
+		srv := tags.NewService(nil, nil)
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -304,7 +307,7 @@ func TestFind(t *testing.T) {
 	})
 
 	t.Run("fail create", func(t *testing.T) {
-		srv := tags.NewService(nil)
+		srv := tags.NewService(nil, nil)
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -319,7 +322,7 @@ func TestFind(t *testing.T) {
 	})
 
 	t.Run("fail update", func(t *testing.T) {
-		srv := tags.NewService(nil)
+		srv := tags.NewService(nil, nil)
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
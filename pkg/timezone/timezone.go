@@ -0,0 +1,74 @@
+package timezone
+
+import (
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+)
+
+// Load parses an IANA timezone name such as Europe/Kyiv. An empty name is UTC.
+func Load(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid timezone: %s", name)
+	}
+
+	return loc, nil
+}
+
+// DateOnly returns the calendar date of t in loc as midnight UTC, the form date columns are stored in.
+// A nil loc is UTC.
+func DateOnly(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	y, m, d := t.In(loc).Date()
+
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Today returns the current calendar date in loc as midnight UTC.
+func Today(loc *time.Location) time.Time {
+	return DateOnly(time.Now(), loc)
+}
+
+// AccountLocation returns the timezone of the account, falling back to household
+// when the account has none or it is invalid.
+func AccountLocation(account *database.Account, household *time.Location) *time.Location {
+	if household == nil {
+		household = time.UTC
+	}
+
+	if account == nil || account.Timezone == nil || *account.Timezone == "" {
+		return household
+	}
+
+	loc, err := time.LoadLocation(*account.Timezone)
+	if err != nil {
+		return household
+	}
+
+	return loc
+}
+
+// TransactionLocation returns the timezone a transaction date is resolved in: the source account,
+// then the destination account, then household.
+func TransactionLocation(
+	tx *database.Transaction,
+	accounts map[int32]*database.Account,
+	household *time.Location,
+) *time.Location {
+	for _, accountID := range []int32{tx.SourceAccountID, tx.DestinationAccountID} {
+		if account := accounts[accountID]; account != nil && account.Timezone != nil && *account.Timezone != "" {
+			return AccountLocation(account, household)
+		}
+	}
+
+	return AccountLocation(nil, household)
+}
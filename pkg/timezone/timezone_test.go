@@ -0,0 +1,109 @@
+package timezone_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/timezone"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("empty is utc", func(t *testing.T) {
+		loc, err := timezone.Load("")
+		assert.NoError(t, err)
+		assert.Equal(t, time.UTC, loc)
+	})
+
+	t.Run("iana name", func(t *testing.T) {
+		loc, err := timezone.Load("Europe/Kyiv")
+		assert.NoError(t, err)
+		assert.Equal(t, "Europe/Kyiv", loc.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := timezone.Load("Mars/Olympus")
+		assert.ErrorContains(t, err, "invalid timezone: Mars/Olympus")
+	})
+}
+
+func TestDateOnly(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	instant := time.Date(2026, 3, 1, 21, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), timezone.DateOnly(instant, nil))
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), timezone.DateOnly(instant, time.UTC))
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), timezone.DateOnly(instant, newYork))
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), timezone.DateOnly(instant.Add(-time.Hour), kyiv))
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), timezone.DateOnly(instant.Add(time.Hour), kyiv)) // 23:30 in Kyiv
+}
+
+func TestTransactionLocation(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	accounts := map[int32]*database.Account{
+		1: {ID: 1},
+		2: {ID: 2, Timezone: lo.ToPtr("America/New_York")},
+		3: {ID: 3, Timezone: lo.ToPtr("Asia/Tokyo")},
+		4: {ID: 4, Timezone: lo.ToPtr("invalid")},
+	}
+
+	cases := []struct {
+		name      string
+		tx        *database.Transaction
+		household *time.Location
+		expected  string
+	}{
+		{
+			name:      "household",
+			tx:        &database.Transaction{SourceAccountID: 1},
+			household: kyiv,
+			expected:  "Europe/Kyiv",
+		},
+		{
+			name:     "nil household is utc",
+			tx:       &database.Transaction{SourceAccountID: 1},
+			expected: "UTC",
+		},
+		{
+			name:      "destination account",
+			tx:        &database.Transaction{SourceAccountID: 1, DestinationAccountID: 2},
+			household: kyiv,
+			expected:  "America/New_York",
+		},
+		{
+			name:      "source account wins",
+			tx:        &database.Transaction{SourceAccountID: 3, DestinationAccountID: 2},
+			household: kyiv,
+			expected:  "Asia/Tokyo",
+		},
+		{
+			name:      "invalid account timezone",
+			tx:        &database.Transaction{SourceAccountID: 4},
+			household: kyiv,
+			expected:  "Europe/Kyiv",
+		},
+		{
+			name:      "unknown account",
+			tx:        &database.Transaction{SourceAccountID: 99},
+			household: kyiv,
+			expected:  "Europe/Kyiv",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, timezone.TransactionLocation(c.tx, accounts, c.household).String())
+		})
+	}
+}
@@ -27,6 +27,7 @@ func NewBaseAmountService(
 	}
 }
 
+// RecalculateAmountInBaseCurrencyForAll recalculates every transaction except those with a locked exchange rate.
 func (s *BaseAmountService) RecalculateAmountInBaseCurrencyForAll(
 	ctx context.Context,
 	tx *gorm.DB,
@@ -34,6 +35,9 @@ func (s *BaseAmountService) RecalculateAmountInBaseCurrencyForAll(
 	return s.RecalculateAmountInBaseCurrency(ctx, tx, nil)
 }
 
+// RecalculateAmountInBaseCurrency converts source amounts to base currency and stores the rate used.
+// A locked rate is used as is; otherwise an amount already in base currency (derived rate), a rate
+// override for the transaction date or the synced currencies.rate is used, in that order.
 func (s *BaseAmountService) RecalculateAmountInBaseCurrency(
 	_ context.Context,
 	tx *gorm.DB,
@@ -58,6 +62,8 @@ func (s *BaseAmountService) RecalculateAmountInBaseCurrency(
 		Id                              int64
 		DestinationAmountInBaseCurrency decimal.NullDecimal
 		SourceAmountInBaseCurrency      decimal.NullDecimal
+		ExchangeRate                    decimal.NullDecimal
+		ExchangeRateSource              database.ExchangeRateSource
 	}
 
 	if err := tx.Raw(updateAmountInBaseCurrency,
@@ -76,6 +82,8 @@ func (s *BaseAmountService) RecalculateAmountInBaseCurrency(
 
 		createdTx.DestinationAmountInBaseCurrency = res.DestinationAmountInBaseCurrency
 		createdTx.SourceAmountInBaseCurrency = res.SourceAmountInBaseCurrency
+		createdTx.ExchangeRate = res.ExchangeRate
+		createdTx.ExchangeRateSource = res.ExchangeRateSource
 	}
 
 	return nil
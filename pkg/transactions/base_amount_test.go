@@ -3,6 +3,7 @@ package transactions_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
 	"github.com/cockroachdb/errors"
@@ -514,13 +515,19 @@ func TestBaseAmountService(t *testing.T) {
 
 		gormMock, _, sql := testingutils.GormMock()
 
-		sql.ExpectQuery("with upd as .*").WithArgs(
+		ids := pq.Int64Array{txs[0].ID, txs[2].ID, txs[3].ID}
+		sql.ExpectQuery("with src as .*").WithArgs(
+			ids,
+			ids,
+			ids,
+			"USD",
+			"USD",
+			"USD",
+			"USD",
 			"USD",
 			"USD",
 			"USD",
 			"USD",
-			pq.Int64Array{txs[0].ID, txs[2].ID, txs[3].ID},
-			pq.Int64Array{txs[0].ID, txs[2].ID, txs[3].ID},
 		).WillReturnError(errors.New("db error"))
 		err := svc.RecalculateAmountInBaseCurrency(context.TODO(), gormMock,
 			[]*database.Transaction{
@@ -531,4 +538,109 @@ func TestBaseAmountService(t *testing.T) {
 		)
 		assert.ErrorContains(t, err, "db error")
 	})
+	t.Run("locked, overridden and derived rates", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		rates := []*database.Currency{
+			{
+				ID:            "PLN",
+				Rate:          decimal.NewFromInt(4),
+				DecimalPlaces: 2,
+			},
+			{
+				ID:            baseCurrency,
+				Rate:          decimal.NewFromInt(1),
+				DecimalPlaces: 2,
+			},
+			{
+				ID:            "UAH",
+				Rate:          decimal.NewFromInt(40),
+				DecimalPlaces: 2,
+			},
+		}
+		assert.NoError(t, gormDB.Create(&rates).Error)
+
+		validTo := time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+		assert.NoError(t, gormDB.Create(&database.CurrencyRateOverride{
+			CurrencyID: "PLN",
+			Rate:       decimal.NewFromInt(5),
+			ValidFrom:  time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+			ValidTo:    &validTo,
+		}).Error)
+
+		july := time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)
+		august := time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)
+
+		txs := []*database.Transaction{
+			{ // [0] synced rate
+				TransactionType:     gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+				SourceCurrency:      "PLN",
+				SourceAmount:        decimal.NewNullDecimal(decimal.NewFromInt(-100)),
+				DestinationCurrency: "UAH",
+				DestinationAmount:   decimal.NewNullDecimal(decimal.NewFromInt(1000)),
+				TransactionDateOnly: august,
+				Extra:               map[string]string{},
+			},
+			{ // [1] override for july
+				TransactionType:     gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+				SourceCurrency:      "PLN",
+				SourceAmount:        decimal.NewNullDecimal(decimal.NewFromInt(-100)),
+				DestinationCurrency: "UAH",
+				DestinationAmount:   decimal.NewNullDecimal(decimal.NewFromInt(1000)),
+				TransactionDateOnly: july,
+				Extra:               map[string]string{},
+			},
+			{ // [2] derived from amounts
+				TransactionType:     gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+				SourceCurrency:      "PLN",
+				SourceAmount:        decimal.NewNullDecimal(decimal.NewFromInt(-100)),
+				DestinationCurrency: baseCurrency,
+				DestinationAmount:   decimal.NewNullDecimal(decimal.NewFromInt(32)),
+				TransactionDateOnly: july,
+				Extra:               map[string]string{},
+			},
+			{ // [3] locked manual rate
+				TransactionType:            gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+				SourceCurrency:             "PLN",
+				SourceAmount:               decimal.NewNullDecimal(decimal.NewFromInt(-100)),
+				DestinationCurrency:        "UAH",
+				DestinationAmount:          decimal.NewNullDecimal(decimal.NewFromInt(1000)),
+				TransactionDateOnly:        july,
+				ExchangeRate:               decimal.NewNullDecimal(decimal.NewFromInt(2)),
+				ExchangeRateSource:         database.ExchangeRateSourceManual,
+				ExchangeRateLocked:         true,
+				SourceAmountInBaseCurrency: decimal.NewNullDecimal(decimal.NewFromInt(-7)),
+				Extra:                      map[string]string{},
+			},
+		}
+		assert.NoError(t, gormDB.Create(&txs).Error)
+
+		svc := transactions.NewBaseAmountService(baseCurrency)
+
+		assert.NoError(t, svc.RecalculateAmountInBaseCurrencyForAll(context.TODO(), gormDB))
+
+		var updatedTxs []*database.Transaction
+		assert.NoError(t, gormDB.Order("id asc").Find(&updatedTxs).Error)
+
+		assert.EqualValues(t, "-25", updatedTxs[0].SourceAmountInBaseCurrency.Decimal.String())
+		assert.EqualValues(t, "4", updatedTxs[0].ExchangeRate.Decimal.String())
+		assert.EqualValues(t, database.ExchangeRateSourceSynced, updatedTxs[0].ExchangeRateSource)
+
+		assert.EqualValues(t, "-20", updatedTxs[1].SourceAmountInBaseCurrency.Decimal.String())
+		assert.EqualValues(t, "5", updatedTxs[1].ExchangeRate.Decimal.String())
+		assert.EqualValues(t, database.ExchangeRateSourceOverride, updatedTxs[1].ExchangeRateSource)
+
+		assert.EqualValues(t, "-32", updatedTxs[2].SourceAmountInBaseCurrency.Decimal.String())
+		assert.EqualValues(t, "3.125", updatedTxs[2].ExchangeRate.Decimal.String())
+		assert.EqualValues(t, database.ExchangeRateSourceDerived, updatedTxs[2].ExchangeRateSource)
+
+		// locked transactions are skipped by bulk recalculation
+		assert.EqualValues(t, "-7", updatedTxs[3].SourceAmountInBaseCurrency.Decimal.String())
+
+		// but use the locked rate when recalculated explicitly
+		assert.NoError(t, svc.RecalculateAmountInBaseCurrency(context.TODO(), gormDB, []*database.Transaction{txs[3]}))
+		assert.EqualValues(t, "-50", txs[3].SourceAmountInBaseCurrency.Decimal.String())
+		assert.EqualValues(t, "2", txs[3].ExchangeRate.Decimal.String())
+		assert.EqualValues(t, database.ExchangeRateSourceManual, txs[3].ExchangeRateSource)
+	})
 }
@@ -7,10 +7,11 @@ import (
 )
 
 type Actor struct {
-	Type   database.TransactionHistoryActorType
-	UserID *int32
-	RuleID *int32
-	Detail string
+	Type           database.TransactionHistoryActorType
+	UserID         *int32
+	RuleID         *int32
+	RuleRevisionID *int64
+	Detail         string
 }
 
 type actorCtxKey struct{}
@@ -43,3 +44,17 @@ func BulkActor(userID int32, op string) Actor {
 func RuleActor(ruleID int32) Actor {
 	return Actor{Type: database.TransactionHistoryActorTypeRule, RuleID: &ruleID}
 }
+
+func RuleRevisionActor(ruleID int32, revisionID *int64) Actor {
+	return Actor{Type: database.TransactionHistoryActorTypeRule, RuleID: &ruleID, RuleRevisionID: revisionID}
+}
+
+// JobActor is used for changes made by background jobs that are not tied to a rule.
+func JobActor(name string) Actor {
+	return Actor{Type: database.TransactionHistoryActorTypeScheduler, Detail: name}
+}
+
+// McpActor is used for changes made through MCP tools; the detail holds the jti of the token.
+func McpActor(userID int32, tokenID string) Actor {
+	return Actor{Type: database.TransactionHistoryActorTypeMcp, UserID: &userID, Detail: tokenID}
+}
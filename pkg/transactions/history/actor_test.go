@@ -69,3 +69,18 @@ func TestActorFromContext_Failure_WrongType(t *testing.T) {
 	_, ok := history.ActorFromContext(ctx)
 	assert.False(t, ok)
 }
+
+func TestRuleRevisionActor_Success(t *testing.T) {
+	a := history.RuleRevisionActor(42, lo.ToPtr(int64(7)))
+	assert.Equal(t, database.TransactionHistoryActorTypeRule, a.Type)
+	assert.Equal(t, lo.ToPtr(int32(42)), a.RuleID)
+	assert.Equal(t, lo.ToPtr(int64(7)), a.RuleRevisionID)
+}
+
+func TestMcpActor_Success(t *testing.T) {
+	a := history.McpActor(3, "token-jti")
+	assert.Equal(t, database.TransactionHistoryActorTypeMcp, a.Type)
+	assert.Equal(t, lo.ToPtr(int32(3)), a.UserID)
+	assert.Nil(t, a.RuleID)
+	assert.Equal(t, "token-jti", a.Detail)
+}
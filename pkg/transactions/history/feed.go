@@ -0,0 +1,137 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultFeedLimit           = 100
+	defaultFeedPollInterval    = time.Second
+	defaultFeedSequenceLockKey = 7460514094
+	feedSequenceBatchSize      = 1000
+)
+
+type FeedConfig struct {
+	PollInterval    time.Duration // how often Wait checks for new events
+	SequenceLockKey int64         // advisory lock that lets one replica at a time number events
+}
+
+type FeedRequest struct {
+	AfterID    int64 // cursor, feed_id of the last event seen, 0 for the oldest
+	Limit      int
+	EventTypes []database.TransactionHistoryEventType // empty = all
+}
+
+// Feed reads transaction_history as an ordered change feed with a resumable cursor on feed_id.
+//
+// The row id can not be the cursor: it is taken when a row is inserted, not when its transaction
+// commits, so a long import can commit ids below a cursor that already moved past them. feed_id is
+// given out after commit instead, see sequence.
+type Feed struct {
+	cfg *FeedConfig
+}
+
+func NewFeed(cfg *FeedConfig) *Feed {
+	return &Feed{cfg: cfg}
+}
+
+// List returns events after the cursor, oldest first.
+func (f *Feed) List(ctx context.Context, req *FeedRequest) ([]*database.TransactionHistory, error) {
+	if err := f.sequence(ctx); err != nil {
+		return nil, err
+	}
+
+	query := database.GetDbWithContext(ctx, database.DbTypeReadonly).
+		Where("feed_id > ?", req.AfterID)
+
+	if len(req.EventTypes) > 0 {
+		query = query.Where("event_type IN ?", req.EventTypes)
+	}
+
+	var rows []*database.TransactionHistory
+	if err := query.Order("feed_id").Limit(lo.CoalesceOrEmpty(req.Limit, defaultFeedLimit)).Find(&rows).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return rows, nil
+}
+
+// sequence numbers committed events without a feed_id in id order, continuing after the highest
+// feed_id. Only committed rows are visible to the update, and the advisory lock is held until the
+// numbers are committed, so a reader never sees a feed_id before all lower ones. When another
+// replica holds the lock it is numbering the same rows and this call only reads.
+func (f *Feed) sequence(ctx context.Context) error {
+	return database.GetDbWithContext(ctx, database.DbTypeMaster).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)",
+			lo.CoalesceOrEmpty(f.cfg.SequenceLockKey, defaultFeedSequenceLockKey)).Scan(&locked).Error; err != nil {
+			return errors.Wrap(err, "failed to lock event sequence")
+		}
+
+		if !locked {
+			return nil
+		}
+
+		return errors.Wrap(tx.Exec(`UPDATE transaction_history h SET feed_id = s.base + s.rn
+			FROM (
+				SELECT id,
+					row_number() OVER (ORDER BY id) AS rn,
+					(SELECT coalesce(max(feed_id), 0) FROM transaction_history) AS base
+				FROM transaction_history
+				WHERE feed_id IS NULL
+				ORDER BY id
+				LIMIT ?
+			) s
+			WHERE h.id = s.id`, feedSequenceBatchSize).Error, "failed to sequence events")
+	})
+}
+
+// Wait is a long poll: it returns as soon as there are events after the cursor, or an empty
+// result once timeout passes.
+func (f *Feed) Wait(ctx context.Context, req *FeedRequest, timeout time.Duration) ([]*database.TransactionHistory, error) {
+	deadline := time.Now().Add(timeout)
+	interval := lo.CoalesceOrEmpty(f.cfg.PollInterval, defaultFeedPollInterval)
+
+	for {
+		rows, err := f.List(ctx, req)
+		if err != nil || len(rows) > 0 {
+			return rows, err
+		}
+
+		wait := min(interval, time.Until(deadline))
+		if wait <= 0 {
+			return rows, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// LatestID returns the feed_id of the newest event, a cursor that skips the existing history.
+func (f *Feed) LatestID(ctx context.Context) (int64, error) {
+	if err := f.sequence(ctx); err != nil {
+		return 0, err
+	}
+
+	var id int64
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).
+		Model(&database.TransactionHistory{}).
+		Select("coalesce(max(feed_id), 0)").
+		Scan(&id).Error; err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return id, nil
+}
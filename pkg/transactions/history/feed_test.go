@@ -0,0 +1,111 @@
+package history_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+)
+
+func historyRow(transactionID int64, eventType database.TransactionHistoryEventType) *database.TransactionHistory {
+	return &database.TransactionHistory{
+		TransactionID: transactionID,
+		EventType:     eventType,
+		ActorType:     database.TransactionHistoryActorTypeUser,
+		Snapshot:      map[string]any{},
+		OccurredAt:    time.Now().UTC(),
+	}
+}
+
+func TestFeed(t *testing.T) {
+	require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	rows := []*database.TransactionHistory{
+		historyRow(1, database.TransactionHistoryEventTypeCreated),
+		historyRow(1, database.TransactionHistoryEventTypeUpdated),
+		historyRow(2, database.TransactionHistoryEventTypeCreated),
+	}
+	require.NoError(t, gormDB.Create(rows).Error)
+
+	feed := history.NewFeed(&history.FeedConfig{PollInterval: 10 * time.Millisecond})
+
+	all, err := feed.List(context.TODO(), &history.FeedRequest{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	for i, row := range all {
+		assert.Equal(t, rows[i].ID, row.ID)
+		require.NotNil(t, row.FeedID)
+	}
+
+	t.Run("list after cursor", func(t *testing.T) {
+		events, listErr := feed.List(context.TODO(), &history.FeedRequest{AfterID: *all[0].FeedID, Limit: 10})
+		require.NoError(t, listErr)
+
+		require.Len(t, events, 2)
+		assert.Equal(t, rows[1].ID, events[0].ID)
+		assert.Equal(t, rows[2].ID, events[1].ID)
+	})
+
+	t.Run("event types and limit", func(t *testing.T) {
+		events, listErr := feed.List(context.TODO(), &history.FeedRequest{
+			EventTypes: []database.TransactionHistoryEventType{database.TransactionHistoryEventTypeCreated},
+			Limit:      1,
+		})
+		require.NoError(t, listErr)
+
+		require.Len(t, events, 1)
+		assert.Equal(t, rows[0].ID, events[0].ID)
+	})
+
+	t.Run("late commit is not skipped", func(t *testing.T) {
+		open := gormDB.Begin()
+		t.Cleanup(func() { open.Rollback() })
+
+		late := historyRow(3, database.TransactionHistoryEventTypeCreated)
+		require.NoError(t, open.Create(late).Error)
+
+		early := historyRow(4, database.TransactionHistoryEventTypeCreated)
+		require.NoError(t, gormDB.Create(early).Error)
+		require.Greater(t, early.ID, late.ID)
+
+		events, listErr := feed.List(context.TODO(), &history.FeedRequest{AfterID: *all[2].FeedID})
+		require.NoError(t, listErr)
+		require.Len(t, events, 1)
+		assert.Equal(t, early.ID, events[0].ID)
+
+		require.NoError(t, open.Commit().Error)
+
+		events, listErr = feed.List(context.TODO(), &history.FeedRequest{AfterID: *events[0].FeedID})
+		require.NoError(t, listErr)
+		require.Len(t, events, 1)
+		assert.Equal(t, late.ID, events[0].ID)
+		assert.Greater(t, lo.FromPtr(events[0].FeedID), *all[2].FeedID+1)
+	})
+
+	t.Run("wait times out without events", func(t *testing.T) {
+		latest, latestErr := feed.LatestID(context.TODO())
+		require.NoError(t, latestErr)
+
+		started := time.Now()
+
+		events, waitErr := feed.Wait(context.TODO(), &history.FeedRequest{AfterID: latest}, 50*time.Millisecond)
+		require.NoError(t, waitErr)
+
+		assert.Empty(t, events)
+		assert.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+	})
+
+	t.Run("wait returns existing events", func(t *testing.T) {
+		events, waitErr := feed.Wait(context.TODO(), &history.FeedRequest{}, time.Minute)
+		require.NoError(t, waitErr)
+		assert.Len(t, events, 5)
+	})
+}
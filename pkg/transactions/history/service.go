@@ -41,6 +41,8 @@ func (s *Service) Record(ctx context.Context, tx *gorm.DB, req RecordRequest) er
 		Snapshot:      snap,
 		Diff:          diff,
 		OccurredAt:    time.Now().UTC(),
+
+		ActorRuleRevisionID: req.Actor.RuleRevisionID,
 	}
 	if req.Actor.Detail != "" {
 		row.ActorExtra = lo.ToPtr(req.Actor.Detail)
@@ -2,9 +2,13 @@ package history
 
 import (
 	"encoding/json"
+	"time"
 
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
 	"github.com/cockroachdb/errors"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
 	"github.com/wI2L/jsondiff"
 )
 
@@ -50,6 +54,7 @@ type marshallableTx struct {
 	ReferenceNumber          *string           `json:"reference_number"`
 	InternalReferenceNumbers []string          `json:"internal_reference_numbers"`
 	CategoryID               *int32            `json:"category_id"`
+	LockedExchangeRate       any               `json:"locked_exchange_rate"`
 }
 
 func toMarshallable(tx *database.Transaction) marshallableTx {
@@ -69,6 +74,10 @@ func toMarshallable(tx *database.Transaction) marshallableTx {
 	if tx.DestinationAmountInBaseCurrency.Valid {
 		dstAmtBase = tx.DestinationAmountInBaseCurrency.Decimal.String()
 	}
+	var lockedRate any
+	if tx.ExchangeRateLocked && tx.ExchangeRate.Valid {
+		lockedRate = tx.ExchangeRate.Decimal.String()
+	}
 	var deletedAt any
 	if tx.DeletedAt.Valid {
 		deletedAt = tx.DeletedAt.Time
@@ -100,7 +109,90 @@ func toMarshallable(tx *database.Transaction) marshallableTx {
 		ReferenceNumber:          tx.ReferenceNumber,
 		InternalReferenceNumbers: []string(tx.InternalReferenceNumbers),
 		CategoryID:               tx.CategoryID,
+		LockedExchangeRate:       lockedRate,
+	}
+}
+
+// FromSnapshot is the inverse of Snapshot. Missing keys are left at their zero values.
+func FromSnapshot(snap map[string]any) (*database.Transaction, error) {
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal snapshot")
 	}
+
+	var in snapshotTx
+	if err = json.Unmarshal(raw, &in); err != nil {
+		return nil, errors.Wrap(err, "unmarshal snapshot")
+	}
+
+	tx := &database.Transaction{
+		ID:                       in.ID,
+		SourceAmount:             in.SourceAmount,
+		SourceCurrency:           in.SourceCurrency,
+		FxSourceAmount:           in.FxSourceAmount,
+		FxSourceCurrency:         in.FxSourceCurrency,
+		DestinationAmount:        in.DestinationAmount,
+		DestinationCurrency:      in.DestinationCurrency,
+		SourceAccountID:          in.SourceAccountID,
+		DestinationAccountID:     in.DestinationAccountID,
+		TagIDs:                   pq.Int32Array(in.TagIDs),
+		Notes:                    in.Notes,
+		Extra:                    in.Extra,
+		TransactionType:          gomoneypbv1.TransactionType(in.TransactionType),
+		Flags:                    database.TransactionFlags(in.Flags),
+		VoidedByTransactionID:    in.VoidedByTransactionID,
+		Title:                    in.Title,
+		ReferenceNumber:          in.ReferenceNumber,
+		InternalReferenceNumbers: pq.StringArray(in.InternalReferenceNumbers),
+		CategoryID:               in.CategoryID,
+	}
+
+	if in.LockedExchangeRate.Valid {
+		tx.ExchangeRate = in.LockedExchangeRate
+		tx.ExchangeRateSource = database.ExchangeRateSourceManual
+		tx.ExchangeRateLocked = true
+	}
+
+	if in.TransactionDateTime != nil {
+		tx.TransactionDateTime = *in.TransactionDateTime
+		tx.TransactionDateOnly = *in.TransactionDateTime
+	}
+
+	if in.TransactionDateOnly != "" {
+		dateOnly, parseErr := time.Parse(time.DateOnly, in.TransactionDateOnly)
+		if parseErr != nil {
+			return nil, errors.Wrap(parseErr, "parse transaction_date_only")
+		}
+
+		tx.TransactionDateOnly = dateOnly
+	}
+
+	return tx, nil
+}
+
+type snapshotTx struct {
+	ID                       int64               `json:"id"`
+	SourceAmount             decimal.NullDecimal `json:"source_amount"`
+	SourceCurrency           string              `json:"source_currency"`
+	FxSourceAmount           decimal.NullDecimal `json:"fx_source_amount"`
+	FxSourceCurrency         string              `json:"fx_source_currency"`
+	DestinationAmount        decimal.NullDecimal `json:"destination_amount"`
+	DestinationCurrency      string              `json:"destination_currency"`
+	SourceAccountID          int32               `json:"source_account_id"`
+	DestinationAccountID     int32               `json:"destination_account_id"`
+	TagIDs                   []int32             `json:"tag_ids"`
+	Notes                    string              `json:"notes"`
+	Extra                    map[string]string   `json:"extra"`
+	TransactionDateTime      *time.Time          `json:"transaction_date_time"`
+	TransactionDateOnly      string              `json:"transaction_date_only"`
+	TransactionType          int32               `json:"transaction_type"`
+	Flags                    int64               `json:"flags"`
+	VoidedByTransactionID    *int64              `json:"voided_by_transaction_id"`
+	Title                    string              `json:"title"`
+	ReferenceNumber          *string             `json:"reference_number"`
+	InternalReferenceNumbers []string            `json:"internal_reference_numbers"`
+	CategoryID               *int32              `json:"category_id"`
+	LockedExchangeRate       decimal.NullDecimal `json:"locked_exchange_rate"`
 }
 
 func Diff(prev, curr map[string]any) (map[string]any, error) {
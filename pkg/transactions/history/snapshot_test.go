@@ -7,6 +7,7 @@ import (
 	"github.com/ft-t/go-money/pkg/database"
 	"github.com/ft-t/go-money/pkg/transactions/history"
 	"github.com/lib/pq"
+	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -121,3 +122,72 @@ func TestSnapshot_Success_TransactionDateOnlyFormatStable(t *testing.T) {
 	require.NoError(t, err)
 	assert.Nil(t, diff)
 }
+
+func TestFromSnapshot_RoundTrip(t *testing.T) {
+	dt := time.Date(2025, 6, 7, 8, 9, 10, 0, time.UTC)
+	tx := &database.Transaction{
+		Title:               "coffee",
+		SourceAmount:        decimal.NewNullDecimal(decimal.RequireFromString("-4.50")),
+		SourceCurrency:      "EUR",
+		SourceAccountID:     3,
+		TagIDs:              pq.Int32Array{5},
+		Extra:               map[string]string{"mcc": "5814"},
+		TransactionDateTime: dt,
+		TransactionDateOnly: dt,
+		CategoryID:          lo.ToPtr(int32(9)),
+	}
+
+	snap, err := history.Snapshot(tx)
+	require.NoError(t, err)
+
+	restored, err := history.FromSnapshot(snap)
+	require.NoError(t, err)
+
+	restoredSnap, err := history.Snapshot(restored)
+	require.NoError(t, err)
+
+	assert.Equal(t, snap, restoredSnap)
+	assert.True(t, decimal.RequireFromString("-4.5").Equal(restored.SourceAmount.Decimal))
+}
+
+func TestFromSnapshot_Partial(t *testing.T) {
+	restored, err := history.FromSnapshot(map[string]any{
+		"title":         "uber",
+		"source_amount": 12.5,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "uber", restored.Title)
+	assert.True(t, decimal.NewFromFloat(12.5).Equal(restored.SourceAmount.Decimal))
+	assert.False(t, restored.DestinationAmount.Valid)
+}
+
+func TestFromSnapshot_InvalidDate(t *testing.T) {
+	_, err := history.FromSnapshot(map[string]any{
+		"transaction_date_only": "not-a-date",
+	})
+	assert.ErrorContains(t, err, "parse transaction_date_only")
+}
+
+func TestSnapshot_Success_LockedExchangeRate(t *testing.T) {
+	unlocked, err := history.Snapshot(&database.Transaction{
+		ID:           1,
+		ExchangeRate: decimal.NewNullDecimal(decimal.NewFromInt(4)),
+	})
+	require.NoError(t, err)
+	assert.Nil(t, unlocked["locked_exchange_rate"])
+
+	locked, err := history.Snapshot(&database.Transaction{
+		ID:                 1,
+		ExchangeRate:       decimal.NewNullDecimal(decimal.RequireFromString("4.25")),
+		ExchangeRateLocked: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "4.25", locked["locked_exchange_rate"])
+
+	restored, err := history.FromSnapshot(locked)
+	require.NoError(t, err)
+	assert.True(t, restored.ExchangeRateLocked)
+	assert.Equal(t, "4.25", restored.ExchangeRate.Decimal.String())
+	assert.Equal(t, database.ExchangeRateSourceManual, restored.ExchangeRateSource)
+}
@@ -23,7 +23,7 @@ func TestBasicExpenseWithMultiCurrency(t *testing.T) {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 
 	baseCurrency := NewMockBaseAmountSvc(gomock.NewController(t))
@@ -106,7 +106,7 @@ func TestUpdateTransaction(t *testing.T) {
 
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 
 	baseCurrency := NewMockBaseAmountSvc(gomock.NewController(t))
@@ -324,7 +324,7 @@ func TestUpdateTransaction(t *testing.T) {
 func TestBasicIncome(t *testing.T) {
 	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 
 	mapper.EXPECT().MapTransaction(gomock.Any(), gomock.Any()).
@@ -393,7 +393,7 @@ func TestBasicIncome(t *testing.T) {
 func TestBasicCalc(t *testing.T) {
 	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 
 	mapper.EXPECT().MapTransaction(gomock.Any(), gomock.Any()).
@@ -554,7 +554,7 @@ func TestBasicCalc(t *testing.T) {
 
 func TestBasicCalcWithGap(t *testing.T) {
 	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 
 	baseCurrency := NewMockBaseAmountSvc(gomock.NewController(t))
@@ -662,7 +662,7 @@ func TestBasicCalcWithGap(t *testing.T) {
 
 func TestNoDailyStatOnRecalculate(t *testing.T) {
 	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 
 	baseCurrency := NewMockBaseAmountSvc(gomock.NewController(t))
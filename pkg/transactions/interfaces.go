@@ -82,6 +82,10 @@ type DoubleEntrySvc interface {
 	) error
 }
 
+type SuggestionsSvc interface {
+	Invalidate()
+}
+
 type HistorySvc interface {
 	Record(ctx context.Context, tx *gorm.DB, req history.RecordRequest) error
 }
@@ -0,0 +1,321 @@
+package transactions
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/search"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const defaultListLimit = 50
+
+// sortExpressions are never null, keyset comparisons rely on it.
+var sortExpressions = map[SortField]string{
+	SortFieldTransactionDate: "transactions.transaction_date_time",
+	SortFieldCreatedAt:       "transactions.created_at",
+	SortFieldAmount:          "coalesce(transactions.destination_amount, abs(transactions.source_amount), 0)",
+	SortFieldBaseAmount:      "coalesce(transactions.destination_amount_in_base_currency, abs(transactions.source_amount_in_base_currency), 0)",
+	SortFieldTitle:           "coalesce(transactions.title, '')",
+	SortFieldCategory:        "coalesce((SELECT c.name FROM categories c WHERE c.id = transactions.category_id), '')",
+	SortFieldAccount:         "coalesce((SELECT a.name FROM accounts a WHERE a.id = transactions.source_account_id), '')",
+}
+
+var defaultSort = []*Sort{{Field: SortFieldTransactionDate}}
+
+// listCursor is the sort key of the last row of a page. Order is the sort order it was
+// issued for, so it can not be replayed with a different one.
+type listCursor struct {
+	Order string   `json:"o"`
+	Keys  []string `json:"k"`
+	ID    int64    `json:"id"`
+}
+
+func (c *listCursor) encode() (string, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeListCursor(raw string, order string) (*listCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor listCursor
+	if err = json.Unmarshal(decoded, &cursor); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	if cursor.Order != order {
+		return nil, errors.New("cursor belongs to a different sort order")
+	}
+
+	return &cursor, nil
+}
+
+func sortOrderKey(sorts []*Sort) string {
+	return strings.Join(lo.Map(sorts, func(s *Sort, _ int) string {
+		return fmt.Sprintf("%d:%t", s.Field, s.Ascending)
+	}), ",")
+}
+
+func validateSort(sorts []*Sort) error {
+	seen := map[SortField]bool{}
+
+	for _, s := range sorts {
+		if _, ok := sortExpressions[s.Field]; !ok {
+			return errors.Newf("unsupported sort field %d", s.Field)
+		}
+
+		if seen[s.Field] {
+			return errors.Newf("duplicate sort field %d", s.Field)
+		}
+
+		seen[s.Field] = true
+	}
+
+	return nil
+}
+
+// applySort orders by the sort fields and id as a tie breaker, in the direction of the first field.
+func applySort(query *gorm.DB, sorts []*Sort) *gorm.DB {
+	for _, s := range sorts {
+		query = query.Order(sortExpressions[s.Field] + lo.Ternary(s.Ascending, " ASC", " DESC"))
+	}
+
+	return query.Order("transactions.id" + lo.Ternary(sorts[0].Ascending, " ASC", " DESC"))
+}
+
+// applyCursor keeps rows strictly after the cursor in the sort order. When all fields share a
+// direction it is a row comparison that can use an index, (k1, k2, id) < (v1, v2, last_id);
+// mixed directions expand to k1 > v1 OR (k1 = v1 AND (k2 < v2 OR (k2 = v2 AND id > last_id))).
+func applyCursor(query *gorm.DB, sorts []*Sort, cursor *listCursor) (*gorm.DB, error) {
+	if len(cursor.Keys) != len(sorts) {
+		return nil, errors.New("invalid cursor")
+	}
+
+	ascending := sorts[0].Ascending
+	op := lo.Ternary(ascending, ">", "<")
+
+	if lo.EveryBy(sorts, func(s *Sort) bool { return s.Ascending == ascending }) {
+		columns := lo.Map(sorts, func(s *Sort, _ int) string { return sortExpressions[s.Field] })
+		args := append(lo.ToAnySlice(cursor.Keys), cursor.ID)
+
+		return query.Where(fmt.Sprintf("(%s, transactions.id) %s (%s)",
+			strings.Join(columns, ", "), op, strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")), args...), nil
+	}
+
+	condition := "transactions.id " + op + " ?"
+	args := []any{cursor.ID}
+
+	for i := len(sorts) - 1; i >= 0; i-- {
+		expr := sortExpressions[sorts[i].Field]
+
+		condition = fmt.Sprintf("%s %s ? OR (%s = ? AND (%s))", expr, lo.Ternary(sorts[i].Ascending, ">", "<"), expr, condition)
+		args = append([]any{cursor.Keys[i], cursor.Keys[i]}, args...)
+	}
+
+	return query.Where(condition, args...), nil
+}
+
+// sortKeys reads the sort key values of a row as text, in the form postgres accepts back as parameters.
+func sortKeys(db *gorm.DB, sorts []*Sort, id int64) ([]string, error) {
+	columns := lo.Map(sorts, func(s *Sort, _ int) string {
+		return sortExpressions[s.Field] + "::text"
+	})
+
+	keys := make([]sql.NullString, len(sorts))
+	if err := db.Raw(fmt.Sprintf("SELECT %s FROM transactions WHERE id = ?", strings.Join(columns, ", ")), id).
+		Row().
+		Scan(lo.Map(keys, func(_ sql.NullString, i int) any { return &keys[i] })...); err != nil {
+		return nil, errors.Wrap(err, "failed to read sort keys")
+	}
+
+	return lo.Map(keys, func(k sql.NullString, _ int) string { return k.String }), nil
+}
+
+// applyListFilters adds the request filters, TextQuery is a search query, see the search package,
+// or a title substring when it does not parse.
+func applyListFilters(query *gorm.DB, req *transactionsv1.ListTransactionsRequest, loc *time.Location) (*gorm.DB, error) {
+	query = query.Where("transactions.deleted_at IS NULL")
+
+	if req.AmountFrom != nil {
+		amountFrom, err := decimal.NewFromString(*req.AmountFrom)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid amount_from")
+		}
+
+		query = query.Where("(source_account_id is not null and source_amount >= ?) OR (destination_account_id is not null and destination_amount >= ?)",
+			amountFrom, amountFrom)
+	}
+
+	if req.AmountTo != nil {
+		amountTo, err := decimal.NewFromString(*req.AmountTo)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid amount_to")
+		}
+
+		query = query.Where("(source_account_id is not null and source_amount <= ?) OR (destination_account_id is not null and destination_amount <= ?)",
+			amountTo, amountTo)
+	}
+
+	if req.FromDate != nil {
+		query = query.Where("transaction_date_time >= ?", req.FromDate.AsTime())
+	}
+
+	if req.ToDate != nil {
+		query = query.Where("transaction_date_time <= ?", req.ToDate.AsTime())
+	}
+
+	if req.TextQuery != nil {
+		// a text the search language rejects, e.g. an unterminated quote, is the plain title search
+		// clients used before the language existed
+		if parsed, err := search.Parse(*req.TextQuery); err != nil {
+			query = query.Where("transactions.title ILIKE ?", "%"+*req.TextQuery+"%")
+		} else {
+			query = search.Apply(query, parsed, loc)
+		}
+	}
+
+	if len(req.DestinationAccountIds) > 0 {
+		query = query.Where("destination_account_id IN ?", req.DestinationAccountIds)
+	}
+
+	if len(req.SourceAccountIds) > 0 {
+		query = query.Where("source_account_id IN ?", req.SourceAccountIds)
+	}
+
+	if len(req.AnyAccountIds) > 0 {
+		query = query.Where("source_account_id IN ? OR destination_account_id IN ?", req.AnyAccountIds, req.AnyAccountIds)
+	}
+
+	if len(req.TransactionTypes) > 0 {
+		query = query.Where("transaction_type IN ?", lo.Map(req.TransactionTypes, func(t gomoneypbv1.TransactionType, _ int) int32 {
+			return int32(t)
+		}))
+	}
+
+	if len(req.CategoryIds) > 0 {
+		query = query.Where("category_id IN ?", req.CategoryIds)
+	}
+
+	if len(req.TagIds) > 0 {
+		var tagIds []string
+		for _, tagId := range req.TagIds {
+			tagIds = append(tagIds, fmt.Sprintf("%d", tagId))
+		}
+
+		query = query.Where(fmt.Sprintf("tag_ids && Array[%s]", strings.Join(tagIds, ",")))
+	}
+
+	if len(req.Ids) > 0 {
+		query = query.Where("transactions.id IN ?", req.Ids)
+	}
+
+	return query, nil
+}
+
+// ListPage returns one page of transactions in keyset order. It does not count matches unless
+// asked and, when paged by cursor, stays stable while transactions are inserted.
+func (s *Service) ListPage(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	sorts := lo.Ternary(len(req.Sort) > 0, req.Sort, defaultSort)
+	if err := validateSort(sorts); err != nil {
+		return nil, err
+	}
+
+	filter := lo.Ternary(req.Filter != nil, req.Filter, &transactionsv1.ListTransactionsRequest{})
+
+	limit := int(filter.Limit)
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	db := database.GetDbWithContext(ctx, database.DbTypeReadonly)
+
+	query, err := applyListFilters(db.Model(&database.Transaction{}), filter, s.cfg.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListResponse{}
+
+	if req.IncludeCount {
+		count, countErr := s.count(ctx, filter)
+		if countErr != nil {
+			return nil, countErr
+		}
+
+		resp.TotalCount = &count
+	}
+
+	order := sortOrderKey(sorts)
+
+	if req.Cursor != "" {
+		cursor, cursorErr := decodeListCursor(req.Cursor, order)
+		if cursorErr != nil {
+			return nil, cursorErr
+		}
+
+		if query, err = applyCursor(query, sorts, cursor); err != nil {
+			return nil, err
+		}
+	} else if filter.Skip > 0 {
+		query = query.Offset(int(filter.Skip))
+	}
+
+	var rows []*database.Transaction
+	if err = applySort(query, sorts).Limit(limit + 1).Find(&rows).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if len(rows) > limit {
+		rows = rows[:limit]
+
+		keys, keysErr := sortKeys(db, sorts, rows[limit-1].ID)
+		if keysErr != nil {
+			return nil, keysErr
+		}
+
+		if resp.NextCursor, err = (&listCursor{Order: order, Keys: keys, ID: rows[limit-1].ID}).encode(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, tx := range rows {
+		resp.Transactions = append(resp.Transactions, s.cfg.MapperSvc.MapTransaction(ctx, tx))
+	}
+
+	return resp, nil
+}
+
+// count returns the number of transactions matching the filter.
+func (s *Service) count(ctx context.Context, filter *transactionsv1.ListTransactionsRequest) (int64, error) {
+	query, err := applyListFilters(database.GetDbWithContext(ctx, database.DbTypeReadonly).Model(&database.Transaction{}),
+		filter, s.cfg.Location)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	if err = query.Count(&count).Error; err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return count, nil
+}
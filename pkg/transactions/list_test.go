@@ -0,0 +1,327 @@
+package transactions_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions"
+	"github.com/golang/mock/gomock"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListPage(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	accounts := []*database.Account{
+		{Name: "Wallet", Currency: "USD", Extra: map[string]string{}},
+		{Name: "Bank", Currency: "USD", Extra: map[string]string{}},
+	}
+	require.NoError(t, gormDB.Create(&accounts).Error)
+
+	categories := []*database.Category{{Name: "Food"}, {Name: "Auto"}}
+	require.NoError(t, gormDB.Create(&categories).Error)
+
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	newTx := func(title string, amount int64, at time.Time, accountID int32, categoryID *int32) *database.Transaction {
+		return &database.Transaction{
+			TransactionType:                 gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+			TransactionDateTime:             at,
+			Title:                           title,
+			SourceAccountID:                 accountID,
+			SourceAmount:                    decimal.NewNullDecimal(decimal.NewFromInt(-amount)),
+			SourceCurrency:                  "USD",
+			DestinationAmount:               decimal.NewNullDecimal(decimal.NewFromInt(amount)),
+			DestinationAmountInBaseCurrency: decimal.NewNullDecimal(decimal.NewFromInt(amount * 2)),
+			DestinationCurrency:             "USD",
+			CategoryID:                      categoryID,
+			Extra:                           map[string]string{},
+		}
+	}
+
+	txs := []*database.Transaction{
+		newTx("Coffee", 5, base, accounts[0].ID, &categories[0].ID),
+		newTx("Fuel", 60, base, accounts[1].ID, &categories[1].ID), // same date as Coffee, id breaks the tie
+		newTx("Bakery", 12, base.Add(time.Hour), accounts[0].ID, &categories[0].ID),
+		newTx("Parking", 12, base.Add(2*time.Hour), accounts[1].ID, nil),
+		newTx("Rent", 900, base.Add(3*time.Hour), accounts[1].ID, nil),
+	}
+	require.NoError(t, gormDB.Create(&txs).Error)
+
+	newSrv := func(t *testing.T) *transactions.Service {
+		mapper := NewMockMapperSvc(gomock.NewController(t))
+		mapper.EXPECT().MapTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, tx *database.Transaction) *gomoneypbv1.Transaction {
+				return &gomoneypbv1.Transaction{Id: tx.ID, Title: tx.Title}
+			}).AnyTimes()
+
+		return transactions.NewService(&transactions.ServiceConfig{MapperSvc: mapper})
+	}
+
+	readAll := func(t *testing.T, req *transactions.ListRequest) []string {
+		srv := newSrv(t)
+
+		var titles []string
+		for page := 0; page < 10; page++ {
+			resp, err := srv.ListPage(context.TODO(), req)
+			require.NoError(t, err)
+
+			titles = append(titles, lo.Map(resp.Transactions, func(tx *gomoneypbv1.Transaction, _ int) string {
+				return tx.Title
+			})...)
+
+			if resp.NextCursor == "" {
+				return titles
+			}
+
+			req.Cursor = resp.NextCursor
+			req.IncludeCount = false
+		}
+
+		t.Fatal("too many pages")
+
+		return nil
+	}
+
+	t.Run("default order across pages", func(t *testing.T) {
+		titles := readAll(t, &transactions.ListRequest{
+			Filter: &transactionsv1.ListTransactionsRequest{Limit: 2},
+		})
+
+		assert.Equal(t, []string{"Rent", "Parking", "Bakery", "Fuel", "Coffee"}, titles)
+	})
+
+	t.Run("count and filters", func(t *testing.T) {
+		resp, err := newSrv(t).ListPage(context.TODO(), &transactions.ListRequest{
+			Filter:       &transactionsv1.ListTransactionsRequest{Limit: 1, CategoryIds: []int32{categories[0].ID}},
+			IncludeCount: true,
+		})
+		require.NoError(t, err)
+
+		require.NotNil(t, resp.TotalCount)
+		assert.EqualValues(t, 2, *resp.TotalCount)
+		assert.Len(t, resp.Transactions, 1)
+		assert.NotEmpty(t, resp.NextCursor)
+	})
+
+	t.Run("amount desc with title tie breaker", func(t *testing.T) {
+		titles := readAll(t, &transactions.ListRequest{
+			Filter: &transactionsv1.ListTransactionsRequest{Limit: 2},
+			Sort: []*transactions.Sort{
+				{Field: transactions.SortFieldAmount},
+				{Field: transactions.SortFieldTitle, Ascending: true},
+			},
+		})
+
+		assert.Equal(t, []string{"Rent", "Fuel", "Bakery", "Parking", "Coffee"}, titles)
+	})
+
+	t.Run("base amount asc", func(t *testing.T) {
+		titles := readAll(t, &transactions.ListRequest{
+			Filter: &transactionsv1.ListTransactionsRequest{Limit: 3},
+			Sort:   []*transactions.Sort{{Field: transactions.SortFieldBaseAmount, Ascending: true}},
+		})
+
+		assert.Equal(t, []string{"Coffee", "Bakery", "Parking", "Fuel", "Rent"}, titles)
+	})
+
+	t.Run("category and account names", func(t *testing.T) {
+		titles := readAll(t, &transactions.ListRequest{
+			Filter: &transactionsv1.ListTransactionsRequest{Limit: 2},
+			Sort: []*transactions.Sort{
+				{Field: transactions.SortFieldCategory, Ascending: true},
+				{Field: transactions.SortFieldAccount, Ascending: true},
+				{Field: transactions.SortFieldTitle, Ascending: true},
+			},
+		})
+
+		// no category first, then Auto and Food; Bank before Wallet
+		assert.Equal(t, []string{"Parking", "Rent", "Fuel", "Bakery", "Coffee"}, titles)
+	})
+
+	t.Run("stable with concurrent inserts", func(t *testing.T) {
+		srv := newSrv(t)
+
+		first, err := srv.ListPage(context.TODO(), &transactions.ListRequest{
+			Filter: &transactionsv1.ListTransactionsRequest{Limit: 2},
+		})
+		require.NoError(t, err)
+
+		newer := newTx("Newer", 1, base.Add(24*time.Hour), accounts[0].ID, nil)
+		require.NoError(t, gormDB.Create(newer).Error)
+		t.Cleanup(func() { gormDB.Delete(newer) })
+
+		second, err := srv.ListPage(context.TODO(), &transactions.ListRequest{
+			Filter: &transactionsv1.ListTransactionsRequest{Limit: 2},
+			Cursor: first.NextCursor,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []int64{txs[2].ID, txs[1].ID}, lo.Map(second.Transactions, func(tx *gomoneypbv1.Transaction, _ int) int64 {
+			return tx.Id
+		}))
+	})
+
+	t.Run("cursor of another sort order", func(t *testing.T) {
+		srv := newSrv(t)
+
+		first, err := srv.ListPage(context.TODO(), &transactions.ListRequest{
+			Filter: &transactionsv1.ListTransactionsRequest{Limit: 1},
+		})
+		require.NoError(t, err)
+
+		_, err = srv.ListPage(context.TODO(), &transactions.ListRequest{
+			Filter: &transactionsv1.ListTransactionsRequest{Limit: 1},
+			Sort:   []*transactions.Sort{{Field: transactions.SortFieldTitle}},
+			Cursor: first.NextCursor,
+		})
+		assert.ErrorContains(t, err, "cursor belongs to a different sort order")
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		srv := newSrv(t)
+
+		_, err := srv.ListPage(context.TODO(), &transactions.ListRequest{Cursor: "not a cursor"})
+		assert.ErrorContains(t, err, "invalid cursor")
+
+		_, err = srv.ListPage(context.TODO(), &transactions.ListRequest{
+			Sort: []*transactions.Sort{{Field: 99}},
+		})
+		assert.ErrorContains(t, err, "unsupported sort field 99")
+
+		_, err = srv.ListPage(context.TODO(), &transactions.ListRequest{
+			Sort: []*transactions.Sort{{Field: transactions.SortFieldTitle}, {Field: transactions.SortFieldTitle}},
+		})
+		assert.ErrorContains(t, err, "duplicate sort field")
+	})
+
+	t.Run("search query", func(t *testing.T) {
+		require.NoError(t, gormDB.Model(txs[0]).Updates(map[string]any{
+			"notes": "oat latte",
+			"extra": `{"merchant": "Blue Bottle"}`,
+		}).Error)
+
+		for query, expected := range map[string][]string{
+			"latte":                           {"Coffee"},
+			"bottl":                           {"Coffee"},
+			"ark":                             {"Parking"},
+			"wallet":                          {"Bakery", "Coffee"},
+			"category:food amount>10":         {"Bakery"},
+			"category:none -rent":             {"Parking"},
+			`account:"bank" after:2026-03-01`: {},
+			"on:2026-03-01 type:expense":      {"Rent", "Parking", "Bakery", "Fuel", "Coffee"},
+		} {
+			resp, err := newSrv(t).ListPage(context.TODO(), &transactions.ListRequest{
+				Filter: &transactionsv1.ListTransactionsRequest{Limit: 10, TextQuery: lo.ToPtr(query)},
+			})
+			require.NoError(t, err, query)
+
+			assert.ElementsMatch(t, expected, lo.Map(resp.Transactions, func(tx *gomoneypbv1.Transaction, _ int) string {
+				return tx.Title
+			}), query)
+		}
+	})
+
+	t.Run("text the search language rejects is a title search", func(t *testing.T) {
+		pizza := newTx(`12" pizza, tag: lunch`, 15, base, accounts[0].ID, nil)
+		require.NoError(t, gormDB.Create(pizza).Error)
+		defer func() {
+			require.NoError(t, gormDB.Unscoped().Delete(pizza).Error)
+		}()
+
+		for query, expected := range map[string][]string{
+			`12" pizza`:   {pizza.Title}, // unterminated quote
+			"tag: lunch":  {pizza.Title}, // key without a value
+			"amount>lots": {},
+		} {
+			resp, err := newSrv(t).List(context.TODO(), &transactionsv1.ListTransactionsRequest{
+				Limit:     10,
+				TextQuery: lo.ToPtr(query),
+			})
+			require.NoError(t, err, query)
+
+			assert.Equal(t, expected, lo.Map(resp.Transactions, func(tx *gomoneypbv1.Transaction, _ int) string {
+				return tx.Title
+			}), query)
+		}
+	})
+
+	t.Run("list orders by date", func(t *testing.T) {
+		resp, err := newSrv(t).List(context.TODO(), &transactionsv1.ListTransactionsRequest{
+			Limit: 10,
+			Sort: []*transactionsv1.ListTransactionsRequest_Sort{
+				{Field: transactionsv1.SortField_SORT_FIELD_TRANSACTION_DATE, Ascending: true},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.EqualValues(t, 5, resp.TotalCount)
+		assert.Equal(t, []string{"Coffee", "Fuel", "Bakery", "Parking", "Rent"}, lo.Map(resp.Transactions, func(tx *gomoneypbv1.Transaction, _ int) string {
+			return tx.Title
+		}))
+	})
+
+	t.Run("list sorts by any api field and totals every page", func(t *testing.T) {
+		srv := newSrv(t)
+		list := func(skip int32) *transactionsv1.ListTransactionsResponse {
+			resp, err := srv.List(context.TODO(), &transactionsv1.ListTransactionsRequest{
+				Limit: 2,
+				Skip:  skip,
+				Sort: []*transactionsv1.ListTransactionsRequest_Sort{
+					{Field: transactionsv1.SortField(transactions.SortFieldAmount)},
+				},
+			})
+			require.NoError(t, err)
+
+			return resp
+		}
+
+		first := list(0) // more rows follow, counted
+		assert.EqualValues(t, 5, first.TotalCount)
+		assert.Equal(t, []string{"Rent", "Fuel"}, lo.Map(first.Transactions, func(tx *gomoneypbv1.Transaction, _ int) string {
+			return tx.Title
+		}))
+
+		last := list(4) // last page, total is skip + rows
+		assert.EqualValues(t, 5, last.TotalCount)
+		assert.Equal(t, []string{"Coffee"}, lo.Map(last.Transactions, func(tx *gomoneypbv1.Transaction, _ int) string {
+			return tx.Title
+		}))
+
+		past := list(10) // past the end, counted
+		assert.EqualValues(t, 5, past.TotalCount)
+		assert.Empty(t, past.Transactions)
+	})
+
+	t.Run("null titles across page boundaries", func(t *testing.T) {
+		untitled := []*database.Transaction{
+			newTx("", 1, base, accounts[0].ID, nil),
+			newTx("", 2, base, accounts[0].ID, nil),
+		}
+		require.NoError(t, gormDB.Create(&untitled).Error)
+		t.Cleanup(func() { gormDB.Delete(&untitled) })
+
+		require.NoError(t, gormDB.Model(&database.Transaction{}).
+			Where("id IN ?", []int64{untitled[0].ID, untitled[1].ID}).
+			UpdateColumn("title", nil).Error)
+
+		asc := readAll(t, &transactions.ListRequest{
+			Filter: &transactionsv1.ListTransactionsRequest{Limit: 1},
+			Sort:   []*transactions.Sort{{Field: transactions.SortFieldTitle, Ascending: true}},
+		})
+		assert.Equal(t, []string{"", "", "Bakery", "Coffee", "Fuel", "Parking", "Rent"}, asc)
+
+		desc := readAll(t, &transactions.ListRequest{
+			Filter: &transactionsv1.ListTransactionsRequest{Limit: 1},
+			Sort:   []*transactions.Sort{{Field: transactions.SortFieldTitle}},
+		})
+		assert.Equal(t, []string{"Rent", "Parking", "Fuel", "Coffee", "Bakery", "", ""}, desc)
+	})
+}
@@ -49,6 +49,10 @@ func (s *DryRun) DryRunRule(ctx context.Context, req *rulesv1.DryRunRuleRequest)
 		} // scheduled transaction
 	}
 
+	if _, ok := TriggerFromContext(ctx); !ok {
+		ctx = WithTrigger(ctx, ManualTrigger())
+	}
+
 	finalResp := &rulesv1.DryRunRuleResponse{
 		Before:      s.cfg.MapperSvc.MapTransaction(ctx, tx),
 		After:       nil,
@@ -70,8 +70,15 @@ func (s *Executor) executeInternal(
 		return nil, errors.Wrap(cloneErr, "failed to clone transaction")
 	}
 
+	trigger := resolveTrigger(ctx, inputTx)
+	ctx = WithTrigger(ctx, trigger)
+
 	for _, ruleGroup := range ruleGroups {
 		for _, rule := range ruleGroup.Rules {
+			if !ruleMatchesTrigger(rule, trigger) {
+				continue
+			}
+
 			result, clonedTx, err := s.ProcessSingleRule(ctx, tx, rule)
 			if err != nil { // errors should be handled in lua scripts
 				return nil, err
@@ -84,9 +91,10 @@ func (s *Executor) executeInternal(
 				}
 				if changed {
 					tx.RuleAppliedEvents = append(tx.RuleAppliedEvents, database.RuleAppliedEvent{
-						RuleID: rule.ID,
-						Before: tx,
-						After:  clonedTx,
+						RuleID:         rule.ID,
+						RuleRevisionID: rule.RevisionID,
+						Before:         tx,
+						After:          clonedTx,
 					})
 				}
 				clonedTx.RuleAppliedEvents = tx.RuleAppliedEvents
@@ -2,10 +2,12 @@ package rules
 
 import (
 	"context"
+	"time"
 
 	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
 	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/suggestions"
 	"github.com/ft-t/go-money/pkg/transactions/validation"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
@@ -32,6 +34,23 @@ type AccountsSvc interface {
 		ctx context.Context,
 		accountId int32,
 	) (*database.Account, error)
+	GetAllAccounts(ctx context.Context) ([]*database.Account, error)
+}
+
+type TagsSvc interface {
+	GetAllTags(ctx context.Context) ([]*database.Tag, error)
+}
+
+type CategoriesSvc interface {
+	GetAllCategories(ctx context.Context) ([]*database.Category, error)
+}
+
+type SuggestionsSvc interface {
+	Suggest(ctx context.Context, inputs []*suggestions.Input) ([]*suggestions.Suggestion, error)
+}
+
+type ModulesSvc interface {
+	GetModuleByName(ctx context.Context, name string) (*database.LuaModule, error)
 }
 
 type ExecutorSvc interface {
@@ -52,6 +71,12 @@ type TransactionSvc interface {
 		ctx context.Context,
 		newTx *database.Transaction,
 	) (*transactionsv1.CreateTransactionResponse, error)
+
+	UpsertRawTransactions(
+		ctx context.Context,
+		created []*database.Transaction,
+		updated []*database.Transaction,
+	) ([]*transactionsv1.CreateTransactionResponse, error)
 }
 
 type ValidationSvc interface {
@@ -73,11 +98,21 @@ type CurrencyConverterSvc interface {
 
 type DecimalSvc interface {
 	GetCurrencyDecimals(ctx context.Context, currency string) int32
+	Format(ctx context.Context, amount decimal.Decimal, currency string) string
+}
+
+type TestCaseRunnerSvc interface {
+	Run(
+		ctx context.Context,
+		script string,
+		cases []*database.RuleTestCase,
+	) ([]*TestCaseResult, error)
 }
 
 type SchedulerSvc interface {
 	Reinit(ctx context.Context) error
 	ValidateCronExpression(cronExpression string) error
+	RunNow(ctx context.Context, ruleID int32, effectiveDate time.Time) (*database.ScheduleRuleRun, error)
 }
 
 type AccountSvc interface {
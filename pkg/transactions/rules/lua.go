@@ -2,26 +2,35 @@ package rules
 
 import (
 	"context"
+	"github.com/ft-t/go-money/pkg/configuration"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/hashicorp/golang-lru/v2/expirable"
 	libs "github.com/vadv/gopher-lua-libs"
 	"github.com/yuin/gopher-lua"
 )
 
 type LuaInterpreter struct {
-	cfg *LuaInterpreterConfig
+	cfg      *LuaInterpreterConfig
+	compiled *expirable.LRU[string, *lua.FunctionProto]
 }
 
 type LuaInterpreterConfig struct {
 	AccountsSvc          AccountsSvc
 	DecimalSvc           DecimalSvc
 	CurrencyConverterSvc CurrencyConverterSvc
+	TagsSvc              TagsSvc
+	CategoriesSvc        CategoriesSvc
+	ModulesSvc           ModulesSvc
+	SuggestionsSvc       SuggestionsSvc // optional, backs the suggest helpers
 }
 
 func NewLuaInterpreter(
 	cfg *LuaInterpreterConfig,
 ) *LuaInterpreter {
 	return &LuaInterpreter{
-		cfg: cfg,
+		cfg:      cfg,
+		compiled: expirable.NewLRU[string, *lua.FunctionProto](500, nil, configuration.DefaultCacheTTL*10),
 	}
 }
 
@@ -35,8 +44,18 @@ func (l *LuaInterpreter) registerHelpers(ctx context.Context, state *lua.LState)
 
 	state.SetGlobal(luaHelpers, mt)
 	state.SetField(mt, "__index", state.SetFuncs(state.NewTable(), map[string]lua.LGFunction{
-		"getAccountByID":  helpers.GetAccountById,
-		"convertCurrency": helpers.Convert,
+		"getAccountByID":    helpers.GetAccountById,
+		"getAccountByName":  helpers.GetAccountByName,
+		"getAccountByIban":  helpers.GetAccountByIban,
+		"getTagByName":      helpers.GetTagByName,
+		"getCategoryByName": helpers.GetCategoryByName,
+		"convertCurrency":   helpers.Convert,
+		"formatAmount":      helpers.FormatAmount,
+		"regexMatch":        helpers.RegexMatch,
+		"regexFind":         helpers.RegexFind,
+		"suggestCategory":   helpers.SuggestCategory,
+		"suggestTags":       helpers.SuggestTags,
+		"applySuggestions":  helpers.ApplySuggestions,
 	}))
 
 	ud := state.NewUserData()
@@ -51,7 +70,30 @@ func (l *LuaInterpreter) registerTransaction(state *lua.LState, wrapped *LuaTran
 	mt := state.NewTypeMetatable(luaTransactionType)
 
 	state.SetGlobal(luaTransactionType, mt)
-	state.SetField(mt, "__index", state.SetFuncs(state.NewTable(), map[string]lua.LGFunction{
+	state.SetField(mt, "__index", state.SetFuncs(state.NewTable(), transactionMethods(wrapped)))
+
+	ud := state.NewUserData()
+	ud.Value = wrapped
+	state.SetMetatable(ud, state.GetTypeMetatable(luaTransactionType))
+
+	state.SetGlobal("tx", ud)
+	state.Push(ud)
+}
+
+// newTransactionUserData wraps an additional transaction, methods are bound per wrapper.
+func newTransactionUserData(state *lua.LState, wrapped *LuaTransactionWrapper) *lua.LUserData {
+	mt := state.NewTable()
+	state.SetField(mt, "__index", state.SetFuncs(state.NewTable(), transactionMethods(wrapped)))
+
+	ud := state.NewUserData()
+	ud.Value = wrapped
+	state.SetMetatable(ud, mt)
+
+	return ud
+}
+
+func transactionMethods(wrapped *LuaTransactionWrapper) map[string]lua.LGFunction {
+	return map[string]lua.LGFunction{
 		"title": wrapped.Title,
 
 		"destinationAmount":                     wrapped.DestinationAmount,
@@ -60,32 +102,69 @@ func (l *LuaInterpreter) registerTransaction(state *lua.LState, wrapped *LuaTran
 		"sourceAmount":                     wrapped.SourceAmount,
 		"getSourceAmountWithDecimalPlaces": wrapped.GetSourceAmountWithDecimalPlaces,
 
-		"sourceCurrency":             wrapped.SourceCurrency,
-		"destinationCurrency":        wrapped.DestinationCurrency,
-		"sourceAccountID":            wrapped.SourceAccountID,
-		"categoryID":                 wrapped.CategoryID,
-		"destinationAccountID":       wrapped.DestinationAccountID,
-		"notes":                      wrapped.Notes,
-		"transactionType":            wrapped.TransactionType,
-		"referenceNumber":             wrapped.ReferenceNumber,
-		"getInternalReferenceNumbers": wrapped.GetInternalReferenceNumbers,
-		"addInternalReferenceNumber":  wrapped.AddInternalReferenceNumber,
-		"setInternalReferenceNumbers": wrapped.SetInternalReferenceNumbers,
+		"sourceAmountInBaseCurrency":      wrapped.SourceAmountInBaseCurrency,
+		"destinationAmountInBaseCurrency": wrapped.DestinationAmountInBaseCurrency,
+
+		"fxSourceAmount":   wrapped.FxSourceAmount,
+		"fxSourceCurrency": wrapped.FxSourceCurrency,
+
+		"getExtra":    wrapped.GetExtra,
+		"setExtra":    wrapped.SetExtra,
+		"getAllExtra": wrapped.GetAllExtra,
+
+		"sourceCurrency":                wrapped.SourceCurrency,
+		"destinationCurrency":           wrapped.DestinationCurrency,
+		"sourceAccountID":               wrapped.SourceAccountID,
+		"categoryID":                    wrapped.CategoryID,
+		"destinationAccountID":          wrapped.DestinationAccountID,
+		"notes":                         wrapped.Notes,
+		"transactionType":               wrapped.TransactionType,
+		"referenceNumber":               wrapped.ReferenceNumber,
+		"getInternalReferenceNumbers":   wrapped.GetInternalReferenceNumbers,
+		"addInternalReferenceNumber":    wrapped.AddInternalReferenceNumber,
+		"setInternalReferenceNumbers":   wrapped.SetInternalReferenceNumbers,
 		"removeInternalReferenceNumber": wrapped.RemoveInternalReferenceNumber,
-		"addTag":                      wrapped.AddTag,
-		"removeTag":                  wrapped.RemoveTag,
-		"getTags":                    wrapped.GetTags,
-		"removeAllTags":              wrapped.RemoveAllTags,
-		"transactionDateTimeAddDate": wrapped.TransactionDateTimeAddDate,
-		"transactionDateTimeSetTime": wrapped.TransactionDateTimeSetTime,
-	}))
+		"addTag":                        wrapped.AddTag,
+		"removeTag":                     wrapped.RemoveTag,
+		"getTags":                       wrapped.GetTags,
+		"removeAllTags":                 wrapped.RemoveAllTags,
+		"transactionDateTime":           wrapped.TransactionDateTime,
+		"transactionDateTimeString":     wrapped.TransactionDateTimeString,
+		"transactionDateTimeAddDate":    wrapped.TransactionDateTimeAddDate,
+		"transactionDateTimeSetTime":    wrapped.TransactionDateTimeSetTime,
+	}
+}
 
-	ud := state.NewUserData()
-	ud.Value = wrapped
-	state.SetMetatable(ud, state.GetTypeMetatable(luaTransactionType))
+// registerTrigger exposes a read-only `trigger` table with the event and actor that caused the run.
+func (l *LuaInterpreter) registerTrigger(ctx context.Context, state *lua.LState) {
+	tbl := state.NewTable()
 
-	state.SetGlobal("tx", ud)
-	state.Push(ud)
+	if trigger, ok := TriggerFromContext(ctx); ok {
+		state.SetField(tbl, "event", lua.LString(TriggerEventName(trigger.Event)))
+
+		if trigger.ImportSource != 0 {
+			state.SetField(tbl, "importSource", lua.LNumber(trigger.ImportSource))
+			state.SetField(tbl, "importSourceName", lua.LString(trigger.ImportSource.String()))
+		}
+	}
+
+	if actor, ok := history.ActorFromContext(ctx); ok {
+		actorTbl := state.NewTable()
+		state.SetField(actorTbl, "type", lua.LString(actorTypeNames[actor.Type]))
+		state.SetField(actorTbl, "detail", lua.LString(actor.Detail))
+
+		if actor.UserID != nil {
+			state.SetField(actorTbl, "userID", lua.LNumber(*actor.UserID))
+		}
+
+		if actor.RuleID != nil {
+			state.SetField(actorTbl, "ruleID", lua.LNumber(*actor.RuleID))
+		}
+
+		state.SetField(tbl, "actor", actorTbl)
+	}
+
+	state.SetGlobal("trigger", tbl)
 }
 
 func (l *LuaInterpreter) Run(
@@ -103,8 +182,17 @@ func (l *LuaInterpreter) Run(
 
 	l.registerTransaction(state, wrapped)
 	l.registerHelpers(ctx, state)
+	l.registerTrigger(ctx, state)
+	l.registerModuleLoader(ctx, state)
+	l.registerSchedule(ctx, state)
+
+	proto, err := l.compile("<string>", script)
+	if err != nil {
+		return false, err
+	}
 
-	if err := state.DoString(script); err != nil {
+	state.Push(state.NewFunctionFromProto(proto))
+	if err = state.PCall(0, lua.MultRet, nil); err != nil {
 		return false, err
 	}
 
@@ -2,6 +2,11 @@ package rules
 
 import (
 	"context"
+	"regexp"
+	"strings"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
 	lua "github.com/yuin/gopher-lua"
 	"layeh.com/gopher-luar"
@@ -10,6 +15,11 @@ import (
 type LuaHelpers struct {
 	ctx context.Context
 	cfg *LuaInterpreterConfig
+
+	accounts   []*database.Account
+	tags       []*database.Tag
+	categories []*database.Category
+	regexps    map[string]*regexp.Regexp
 }
 
 func NewLuaHelpers(
@@ -17,8 +27,9 @@ func NewLuaHelpers(
 	cfg *LuaInterpreterConfig,
 ) *LuaHelpers {
 	return &LuaHelpers{
-		ctx: ctx,
-		cfg: cfg,
+		ctx:     ctx,
+		cfg:     cfg,
+		regexps: map[string]*regexp.Regexp{},
 	}
 }
 
@@ -48,6 +59,21 @@ func (h *LuaHelpers) Convert(l *lua.LState) int {
 	return 1
 }
 
+// FormatAmount returns the amount with the currency symbol, e.g. "-12.50 zł".
+func (h *LuaHelpers) FormatAmount(l *lua.LState) int {
+	if l.GetTop() != 3 {
+		l.ArgError(1, "amount and currency expected")
+		return 0
+	}
+
+	amount := l.CheckNumber(2)
+	currency := l.CheckString(3)
+
+	l.Push(lua.LString(h.cfg.DecimalSvc.Format(h.ctx, decimal.NewFromFloat(float64(amount)), currency)))
+
+	return 1
+}
+
 func (h *LuaHelpers) GetAccountById(l *lua.LState) int {
 	if l.GetTop() != 2 {
 		l.ArgError(1, "account ID expected")
@@ -66,3 +92,186 @@ func (h *LuaHelpers) GetAccountById(l *lua.LState) int {
 
 	return 1
 }
+
+func (h *LuaHelpers) GetAccountByName(l *lua.LState) int {
+	if l.GetTop() != 2 {
+		l.ArgError(1, "account name expected")
+		return 0
+	}
+
+	name := l.CheckString(2)
+
+	accounts, err := h.getAccounts()
+	if err != nil {
+		l.RaiseError("failed to get accounts: %v", err)
+		return 0
+	}
+
+	account, ok := lo.Find(accounts, func(acc *database.Account) bool {
+		return strings.EqualFold(acc.Name, name)
+	})
+
+	return h.pushOptional(l, account, ok)
+}
+
+func (h *LuaHelpers) GetAccountByIban(l *lua.LState) int {
+	if l.GetTop() != 2 {
+		l.ArgError(1, "iban expected")
+		return 0
+	}
+
+	iban := normalizeIban(l.CheckString(2))
+
+	accounts, err := h.getAccounts()
+	if err != nil {
+		l.RaiseError("failed to get accounts: %v", err)
+		return 0
+	}
+
+	account, ok := lo.Find(accounts, func(acc *database.Account) bool {
+		return acc.Iban != "" && normalizeIban(acc.Iban) == iban
+	})
+
+	return h.pushOptional(l, account, ok)
+}
+
+func (h *LuaHelpers) GetTagByName(l *lua.LState) int {
+	if l.GetTop() != 2 {
+		l.ArgError(1, "tag name expected")
+		return 0
+	}
+
+	name := l.CheckString(2)
+
+	if h.tags == nil {
+		tags, err := h.cfg.TagsSvc.GetAllTags(h.ctx)
+		if err != nil {
+			l.RaiseError("failed to get tags: %v", err)
+			return 0
+		}
+
+		h.tags = tags
+	}
+
+	tag, ok := lo.Find(h.tags, func(t *database.Tag) bool {
+		return strings.EqualFold(t.Name, name)
+	})
+
+	return h.pushOptional(l, tag, ok)
+}
+
+func (h *LuaHelpers) GetCategoryByName(l *lua.LState) int {
+	if l.GetTop() != 2 {
+		l.ArgError(1, "category name expected")
+		return 0
+	}
+
+	name := l.CheckString(2)
+
+	if h.categories == nil {
+		categories, err := h.cfg.CategoriesSvc.GetAllCategories(h.ctx)
+		if err != nil {
+			l.RaiseError("failed to get categories: %v", err)
+			return 0
+		}
+
+		h.categories = categories
+	}
+
+	category, ok := lo.Find(h.categories, func(c *database.Category) bool {
+		return strings.EqualFold(c.Name, name)
+	})
+
+	return h.pushOptional(l, category, ok)
+}
+
+// RegexMatch reports whether value matches the RE2 pattern.
+func (h *LuaHelpers) RegexMatch(l *lua.LState) int {
+	if l.GetTop() != 3 {
+		l.ArgError(1, "pattern and value expected")
+		return 0
+	}
+
+	re := h.compileRegex(l, l.CheckString(2))
+	if re == nil {
+		return 0
+	}
+
+	l.Push(lua.LBool(re.MatchString(l.CheckString(3))))
+
+	return 1
+}
+
+// RegexFind returns a table with the full match followed by capture groups, or nil when nothing matched.
+func (h *LuaHelpers) RegexFind(l *lua.LState) int {
+	if l.GetTop() != 3 {
+		l.ArgError(1, "pattern and value expected")
+		return 0
+	}
+
+	re := h.compileRegex(l, l.CheckString(2))
+	if re == nil {
+		return 0
+	}
+
+	matches := re.FindStringSubmatch(l.CheckString(3))
+	if matches == nil {
+		l.Push(lua.LNil)
+		return 1
+	}
+
+	tbl := l.NewTable()
+	for _, m := range matches {
+		tbl.Append(lua.LString(m))
+	}
+
+	l.Push(tbl)
+
+	return 1
+}
+
+func (h *LuaHelpers) compileRegex(l *lua.LState, pattern string) *regexp.Regexp {
+	if re, ok := h.regexps[pattern]; ok {
+		return re
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		l.RaiseError("invalid regex: %v", err)
+		return nil
+	}
+
+	h.regexps[pattern] = re
+
+	return re
+}
+
+func (h *LuaHelpers) getAccounts() ([]*database.Account, error) {
+	if h.accounts != nil {
+		return h.accounts, nil
+	}
+
+	accounts, err := h.cfg.AccountsSvc.GetAllAccounts(h.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	h.accounts = accounts
+
+	return accounts, nil
+}
+
+func (h *LuaHelpers) pushOptional(l *lua.LState, val any, found bool) int {
+	if !found {
+		l.Push(lua.LNil)
+		return 1
+	}
+
+	l.Push(luar.New(l, val))
+
+	return 1
+}
+
+func normalizeIban(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+}
@@ -3,6 +3,7 @@ package rules_test
 import (
 	"context"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/suggestions"
 	"github.com/ft-t/go-money/pkg/transactions/rules"
 	"github.com/golang/mock/gomock"
 	"github.com/shopspring/decimal"
@@ -169,3 +170,309 @@ func TestConvertHelpers(t *testing.T) {
 		assert.ErrorContains(t, err, "failed to convert currency")
 	})
 }
+
+func TestFormatAmountHelper(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		decimalSvc := NewMockDecimalSvc(gomock.NewController(t))
+		decimalSvc.EXPECT().Format(gomock.Any(), decimal.NewFromFloat(-12.5), "PLN").Return("-12.50 zł")
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			DecimalSvc: decimalSvc,
+		})
+
+		tx := &database.Transaction{}
+
+		result, err := interpreter.Run(context.TODO(), `
+		tx:notes("paid " .. helpers:formatAmount(-12.5, "PLN"))
+	`, tx)
+		assert.NoError(t, err)
+
+		assert.True(t, result)
+		assert.Equal(t, "paid -12.50 zł", tx.Notes)
+	})
+
+	t.Run("missing arguments", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		result, err := interpreter.Run(context.TODO(), `
+		tx:notes(helpers:formatAmount(10))
+	`, &database.Transaction{})
+		assert.False(t, result)
+		assert.ErrorContains(t, err, "amount and currency expected")
+	})
+}
+
+func TestLookupHelpers(t *testing.T) {
+	t.Run("account by name and iban", func(t *testing.T) {
+		accSvc := NewMockAccountsSvc(gomock.NewController(t))
+		accSvc.EXPECT().GetAllAccounts(gomock.Any()).
+			Return([]*database.Account{
+				{ID: 1, Name: "Cash"},
+				{ID: 2, Name: "Savings", Iban: "PL61 1090 1014 0000 0712 1981 2874"},
+			}, nil) // cached for the whole run
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			AccountsSvc: accSvc,
+		})
+
+		script := `
+		tx:sourceAccountID(helpers:getAccountByName("cash").ID)
+		tx:destinationAccountID(helpers:getAccountByIban("pl61109010140000071219812874").ID)
+		if helpers:getAccountByName("unknown") == nil then
+			tx:notes("not found")
+		end
+	`
+
+		tx := &database.Transaction{}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.EqualValues(t, 1, tx.SourceAccountID)
+		assert.EqualValues(t, 2, tx.DestinationAccountID)
+		assert.Equal(t, "not found", tx.Notes)
+	})
+
+	t.Run("accounts error", func(t *testing.T) {
+		accSvc := NewMockAccountsSvc(gomock.NewController(t))
+		accSvc.EXPECT().GetAllAccounts(gomock.Any()).Return(nil, assert.AnError)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			AccountsSvc: accSvc,
+		})
+
+		result, err := interpreter.Run(context.TODO(), `helpers:getAccountByName("cash")`, &database.Transaction{})
+		assert.ErrorContains(t, err, "failed to get accounts")
+		assert.False(t, result)
+	})
+
+	t.Run("tag and category by name", func(t *testing.T) {
+		tagsSvc := NewMockTagsSvc(gomock.NewController(t))
+		tagsSvc.EXPECT().GetAllTags(gomock.Any()).
+			Return([]*database.Tag{{ID: 7, Name: "Travel"}}, nil)
+
+		categoriesSvc := NewMockCategoriesSvc(gomock.NewController(t))
+		categoriesSvc.EXPECT().GetAllCategories(gomock.Any()).
+			Return([]*database.Category{{ID: 3, Name: "Food"}}, nil)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			TagsSvc:       tagsSvc,
+			CategoriesSvc: categoriesSvc,
+		})
+
+		script := `
+		tx:addTag(helpers:getTagByName("travel").ID)
+		tx:categoryID(helpers:getCategoryByName("FOOD").ID)
+	`
+
+		tx := &database.Transaction{}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.EqualValues(t, []int32{7}, tx.TagIDs)
+		assert.EqualValues(t, 3, *tx.CategoryID)
+	})
+
+	t.Run("tags error", func(t *testing.T) {
+		tagsSvc := NewMockTagsSvc(gomock.NewController(t))
+		tagsSvc.EXPECT().GetAllTags(gomock.Any()).Return(nil, assert.AnError)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			TagsSvc: tagsSvc,
+		})
+
+		result, err := interpreter.Run(context.TODO(), `helpers:getTagByName("x")`, &database.Transaction{})
+		assert.ErrorContains(t, err, "failed to get tags")
+		assert.False(t, result)
+	})
+
+	t.Run("categories error", func(t *testing.T) {
+		categoriesSvc := NewMockCategoriesSvc(gomock.NewController(t))
+		categoriesSvc.EXPECT().GetAllCategories(gomock.Any()).Return(nil, assert.AnError)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			CategoriesSvc: categoriesSvc,
+		})
+
+		result, err := interpreter.Run(context.TODO(), `helpers:getCategoryByName("x")`, &database.Transaction{})
+		assert.ErrorContains(t, err, "failed to get categories")
+		assert.False(t, result)
+	})
+}
+
+func TestRegexHelpers(t *testing.T) {
+	t.Run("match and find", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		script := `
+		if helpers:regexMatch("(?i)^uber\\s+trip", tx:title()) then
+			local m = helpers:regexFind("#(\\d+)", tx:title())
+			tx:referenceNumber(m[2])
+		end
+		if helpers:regexFind("nomatch", tx:title()) == nil then
+			tx:notes("no match")
+		end
+	`
+
+		tx := &database.Transaction{Title: "UBER  trip #12345"}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, "12345", *tx.ReferenceNumber)
+		assert.Equal(t, "no match", tx.Notes)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		result, err := interpreter.Run(context.TODO(), `helpers:regexMatch("(", "x")`, &database.Transaction{})
+		assert.ErrorContains(t, err, "invalid regex")
+		assert.False(t, result)
+	})
+
+	t.Run("missing args", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		result, err := interpreter.Run(context.TODO(), `helpers:regexMatch("(")`, &database.Transaction{})
+		assert.ErrorContains(t, err, "pattern and value expected")
+		assert.False(t, result)
+	})
+}
+
+func TestSuggestionHelpers(t *testing.T) {
+	t.Run("apply above threshold", func(t *testing.T) {
+		suggestionsSvc := NewMockSuggestionsSvc(gomock.NewController(t))
+		suggestionsSvc.EXPECT().Suggest(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, inputs []*suggestions.Input) ([]*suggestions.Suggestion, error) {
+				assert.Len(t, inputs, 1)
+				assert.Equal(t, "UBER TRIP", inputs[0].Title)
+
+				return []*suggestions.Suggestion{{
+					Categories: []*suggestions.Candidate{{ID: 4, Confidence: 0.9}},
+					Tags: []*suggestions.Candidate{
+						{ID: 7, Confidence: 0.8},
+						{ID: 8, Confidence: 0.4},
+					},
+				}}, nil
+			})
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			SuggestionsSvc: suggestionsSvc,
+		})
+
+		tx := &database.Transaction{Title: "UBER TRIP"}
+
+		result, err := interpreter.Run(context.TODO(), `helpers:applySuggestions(tx, 0.75)`, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.EqualValues(t, 4, *tx.CategoryID)
+		assert.EqualValues(t, []int32{7}, tx.TagIDs)
+	})
+
+	t.Run("apply keeps existing category", func(t *testing.T) {
+		suggestionsSvc := NewMockSuggestionsSvc(gomock.NewController(t))
+		suggestionsSvc.EXPECT().Suggest(gomock.Any(), gomock.Any()).
+			Return([]*suggestions.Suggestion{{
+				Categories: []*suggestions.Candidate{{ID: 4, Confidence: 0.9}},
+				Tags:       []*suggestions.Candidate{{ID: 7, Confidence: 0.8}},
+			}}, nil)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			SuggestionsSvc: suggestionsSvc,
+		})
+
+		category := int32(1)
+		tx := &database.Transaction{CategoryID: &category, TagIDs: []int32{7}}
+
+		result, err := interpreter.Run(context.TODO(), `helpers:applySuggestions(tx, 0.5)`, tx)
+		assert.NoError(t, err)
+		assert.False(t, result)
+		assert.EqualValues(t, 1, *tx.CategoryID)
+		assert.EqualValues(t, []int32{7}, tx.TagIDs)
+	})
+
+	t.Run("suggest category and tags", func(t *testing.T) {
+		suggestionsSvc := NewMockSuggestionsSvc(gomock.NewController(t))
+		suggestionsSvc.EXPECT().Suggest(gomock.Any(), gomock.Any()).
+			Return([]*suggestions.Suggestion{{
+				Categories: []*suggestions.Candidate{{ID: 4, Confidence: 0.6}},
+				Tags:       []*suggestions.Candidate{{ID: 7, Confidence: 0.8}},
+			}}, nil).Times(2)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			SuggestionsSvc: suggestionsSvc,
+		})
+
+		script := `
+		local category = helpers:suggestCategory(tx)
+		if category ~= nil and category.confidence > 0.5 then
+			tx:categoryID(category.ID)
+		end
+		for _, tag in ipairs(helpers:suggestTags(tx)) do
+			tx:addTag(tag.ID)
+		end
+	`
+
+		tx := &database.Transaction{}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.EqualValues(t, 4, *tx.CategoryID)
+		assert.EqualValues(t, []int32{7}, tx.TagIDs)
+	})
+
+	t.Run("no category suggested", func(t *testing.T) {
+		suggestionsSvc := NewMockSuggestionsSvc(gomock.NewController(t))
+		suggestionsSvc.EXPECT().Suggest(gomock.Any(), gomock.Any()).
+			Return([]*suggestions.Suggestion{{}}, nil)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			SuggestionsSvc: suggestionsSvc,
+		})
+
+		script := `
+		if helpers:suggestCategory(tx) == nil then
+			tx:notes("no suggestion")
+		end
+	`
+
+		tx := &database.Transaction{}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, "no suggestion", tx.Notes)
+	})
+
+	t.Run("suggestions error", func(t *testing.T) {
+		suggestionsSvc := NewMockSuggestionsSvc(gomock.NewController(t))
+		suggestionsSvc.EXPECT().Suggest(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			SuggestionsSvc: suggestionsSvc,
+		})
+
+		result, err := interpreter.Run(context.TODO(), `helpers:suggestCategory(tx)`, &database.Transaction{})
+		assert.ErrorContains(t, err, "failed to get suggestions")
+		assert.False(t, result)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		result, err := interpreter.Run(context.TODO(), `helpers:suggestTags(tx)`, &database.Transaction{})
+		assert.ErrorContains(t, err, "suggestions are not configured")
+		assert.False(t, result)
+	})
+
+	t.Run("missing threshold", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		result, err := interpreter.Run(context.TODO(), `helpers:applySuggestions(tx)`, &database.Transaction{})
+		assert.ErrorContains(t, err, "transaction and confidence threshold expected")
+		assert.False(t, result)
+	})
+}
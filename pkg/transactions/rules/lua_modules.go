@@ -0,0 +1,112 @@
+package rules
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+type moduleOverridesCtxKey struct{}
+
+// WithModuleOverrides makes require() resolve the given module sources before stored modules.
+// Used by dry runs to test uncommitted module changes.
+func WithModuleOverrides(ctx context.Context, modules map[string]string) context.Context {
+	return context.WithValue(ctx, moduleOverridesCtxKey{}, modules)
+}
+
+func moduleOverridesFromContext(ctx context.Context) map[string]string {
+	modules, _ := ctx.Value(moduleOverridesCtxKey{}).(map[string]string)
+	return modules
+}
+
+// compile returns the compiled script, keyed by chunk name and source hash so edited sources recompile.
+func (l *LuaInterpreter) compile(name string, source string) (*lua.FunctionProto, error) {
+	if l.compiled == nil {
+		return compileLua(name, source)
+	}
+
+	hash := sha256.Sum256([]byte(source))
+	key := name + ":" + hex.EncodeToString(hash[:])
+
+	if proto, ok := l.compiled.Get(key); ok {
+		return proto, nil
+	}
+
+	proto, err := compileLua(name, source)
+	if err != nil {
+		return nil, err
+	}
+
+	l.compiled.Add(key, proto)
+
+	return proto, nil
+}
+
+func compileLua(name string, source string) (*lua.FunctionProto, error) {
+	chunk, err := parse.Parse(strings.NewReader(source), name)
+	if err != nil {
+		return nil, err
+	}
+
+	return lua.Compile(chunk, name)
+}
+
+// registerModuleLoader adds a require() loader for stored modules right after package.preload,
+// so libraries from libs.Preload keep priority and stored modules win over files on disk.
+func (l *LuaInterpreter) registerModuleLoader(ctx context.Context, state *lua.LState) {
+	loaders, ok := state.GetField(state.Get(lua.RegistryIndex), "_LOADERS").(*lua.LTable)
+	if !ok {
+		return
+	}
+
+	loaders.Insert(2, state.NewFunction(func(L *lua.LState) int {
+		name := L.CheckString(1)
+
+		source, found, err := l.lookupModule(ctx, name)
+		if err != nil {
+			L.RaiseError("failed to load module %s: %v", name, err)
+			return 0
+		}
+
+		if !found {
+			L.Push(lua.LString(fmt.Sprintf("no stored module '%s'", name)))
+			return 1
+		}
+
+		proto, err := l.compile(name, source)
+		if err != nil {
+			L.RaiseError("failed to compile module %s: %v", name, err)
+			return 0
+		}
+
+		L.Push(L.NewFunctionFromProto(proto))
+		return 1
+	}))
+}
+
+func (l *LuaInterpreter) lookupModule(ctx context.Context, name string) (string, bool, error) {
+	if source, ok := moduleOverridesFromContext(ctx)[name]; ok {
+		return source, true, nil
+	}
+
+	if l.cfg == nil || l.cfg.ModulesSvc == nil {
+		return "", false, nil
+	}
+
+	module, err := l.cfg.ModulesSvc.GetModuleByName(ctx, name)
+	if err != nil {
+		return "", false, errors.WithStack(err)
+	}
+
+	if module == nil {
+		return "", false, nil
+	}
+
+	return module.Script, true, nil
+}
@@ -0,0 +1,120 @@
+package rules_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const merchantsModule = `
+local M = {}
+function M.normalize(name)
+	return string.lower(name)
+end
+return M
+`
+
+func TestLuaModules(t *testing.T) {
+	t.Run("require stored module", func(t *testing.T) {
+		modulesSvc := NewMockModulesSvc(gomock.NewController(t))
+		modulesSvc.EXPECT().GetModuleByName(gomock.Any(), "merchants").
+			Return(&database.LuaModule{Name: "merchants", Script: merchantsModule}, nil)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			ModulesSvc: modulesSvc,
+		})
+
+		tx := &database.Transaction{Title: "SHOP"}
+		result, err := interpreter.Run(context.TODO(), `
+		local merchants = require("merchants")
+		local again = require("merchants")
+		tx:title(again.normalize(merchants.normalize(tx:title())))
+	`, tx)
+
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, "shop", tx.Title)
+	})
+
+	t.Run("override wins over stored module", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			ModulesSvc: NewMockModulesSvc(gomock.NewController(t)),
+		})
+
+		ctx := rules.WithModuleOverrides(context.TODO(), map[string]string{
+			"merchants": `return { normalize = function(name) return "draft" end }`,
+		})
+
+		tx := &database.Transaction{Title: "SHOP"}
+		_, err := interpreter.Run(ctx, `tx:title(require("merchants").normalize(tx:title()))`, tx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "draft", tx.Title)
+	})
+
+	t.Run("preloaded library keeps priority", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			ModulesSvc: NewMockModulesSvc(gomock.NewController(t)),
+		})
+
+		_, err := interpreter.Run(context.TODO(), `local strings = require("strings")`, &database.Transaction{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("module not found", func(t *testing.T) {
+		modulesSvc := NewMockModulesSvc(gomock.NewController(t))
+		modulesSvc.EXPECT().GetModuleByName(gomock.Any(), "missing").Return(nil, nil)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			ModulesSvc: modulesSvc,
+		})
+
+		_, err := interpreter.Run(context.TODO(), `require("missing")`, &database.Transaction{})
+		assert.ErrorContains(t, err, "no stored module 'missing'")
+	})
+
+	t.Run("module load error", func(t *testing.T) {
+		modulesSvc := NewMockModulesSvc(gomock.NewController(t))
+		modulesSvc.EXPECT().GetModuleByName(gomock.Any(), "merchants").Return(nil, assert.AnError)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{
+			ModulesSvc: modulesSvc,
+		})
+
+		_, err := interpreter.Run(context.TODO(), `require("merchants")`, &database.Transaction{})
+		assert.ErrorContains(t, err, "failed to load module merchants")
+	})
+
+	t.Run("module compile error", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		ctx := rules.WithModuleOverrides(context.TODO(), map[string]string{"broken": "return {"})
+
+		_, err := interpreter.Run(ctx, `require("broken")`, &database.Transaction{})
+		assert.ErrorContains(t, err, "failed to compile module broken")
+	})
+
+	t.Run("cached script runs on fresh state", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		for i := 0; i < 2; i++ {
+			tx := &database.Transaction{}
+			result, err := interpreter.Run(context.TODO(), `tx:title("cached")`, tx)
+
+			assert.NoError(t, err)
+			assert.True(t, result)
+			assert.Equal(t, "cached", tx.Title)
+		}
+	})
+
+	t.Run("script syntax error", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		_, err := interpreter.Run(context.TODO(), `if then`, &database.Transaction{})
+		assert.Error(t, err)
+	})
+}
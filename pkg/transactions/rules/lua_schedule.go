@@ -0,0 +1,227 @@
+package rules
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/timezone"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	lua "github.com/yuin/gopher-lua"
+)
+
+const (
+	scheduleFindDefaultLimit = 100
+	scheduleFindMaxLimit     = 1000
+)
+
+type scheduleSessionCtxKey struct{}
+
+// ScheduleSession collects what a scheduled script emitted, skipped or changed.
+type ScheduleSession struct {
+	RuleID        int32
+	EffectiveDate time.Time
+	Location      *time.Location // household timezone, balances are read for its calendar days, UTC when nil
+	Skipped       bool
+	Created       []*database.Transaction
+
+	loaded []*LuaTransactionWrapper
+}
+
+func WithScheduleSession(ctx context.Context, session *ScheduleSession) context.Context {
+	return context.WithValue(ctx, scheduleSessionCtxKey{}, session)
+}
+
+func ScheduleSessionFromContext(ctx context.Context) (*ScheduleSession, bool) {
+	session, ok := ctx.Value(scheduleSessionCtxKey{}).(*ScheduleSession)
+	return session, ok
+}
+
+// Updated returns existing transactions returned by findTransactions and modified by the script.
+func (s *ScheduleSession) Updated() []*database.Transaction {
+	var updated []*database.Transaction
+
+	seen := map[int64]struct{}{}
+	for _, wrapped := range s.loaded {
+		if !wrapped.modified {
+			continue
+		}
+
+		if _, ok := seen[wrapped.tx.ID]; ok {
+			continue
+		}
+
+		seen[wrapped.tx.ID] = struct{}{}
+		updated = append(updated, wrapped.tx)
+	}
+
+	return updated
+}
+
+// registerSchedule exposes the `schedule` table to scheduled scripts only.
+func (l *LuaInterpreter) registerSchedule(ctx context.Context, state *lua.LState) {
+	session, ok := ScheduleSessionFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	tbl := state.NewTable()
+	state.SetField(tbl, "ruleID", lua.LNumber(session.RuleID))
+	state.SetField(tbl, "effectiveDate", lua.LNumber(session.EffectiveDate.Unix()))
+
+	state.SetFuncs(tbl, map[string]lua.LGFunction{
+		"skip": func(L *lua.LState) int {
+			session.Skipped = true
+			return 0
+		},
+		"newTransaction": func(L *lua.LState) int {
+			newTx := &database.Transaction{
+				Extra:               map[string]string{},
+				TransactionDateTime: session.EffectiveDate,
+				TransactionDateOnly: session.EffectiveDate,
+			}
+			session.Created = append(session.Created, newTx)
+
+			L.Push(newTransactionUserData(L, &LuaTransactionWrapper{tx: newTx}))
+			return 1
+		},
+		"findTransactions": func(L *lua.LState) int {
+			txs, err := findScheduleTransactions(ctx, L.OptTable(1, L.NewTable()))
+			if err != nil {
+				L.RaiseError("failed to find transactions: %v", err)
+				return 0
+			}
+
+			result := L.NewTable()
+			for _, tx := range txs {
+				wrapped := &LuaTransactionWrapper{tx: tx}
+				session.loaded = append(session.loaded, wrapped)
+				result.Append(newTransactionUserData(L, wrapped))
+			}
+
+			L.Push(result)
+			return 1
+		},
+		"balance": func(L *lua.LState) int {
+			accountID := L.CheckInt(1)
+			at := time.Unix(L.OptInt64(2, session.EffectiveDate.Unix()), 0).UTC()
+
+			balance, err := balanceAt(ctx, int32(accountID), at, session.Location)
+			if err != nil {
+				L.RaiseError("failed to get balance: %v", err)
+				return 0
+			}
+
+			L.Push(lua.LNumber(balance.InexactFloat64()))
+			return 1
+		},
+		"balanceChange": func(L *lua.LState) int {
+			accountID := L.CheckInt(1)
+			from := time.Unix(L.CheckInt64(2), 0).UTC()
+			to := time.Unix(L.CheckInt64(3), 0).UTC()
+
+			fromBalance, err := balanceAt(ctx, int32(accountID), from, session.Location)
+			if err != nil {
+				L.RaiseError("failed to get balance change: %v", err)
+				return 0
+			}
+
+			toBalance, err := balanceAt(ctx, int32(accountID), to, session.Location)
+			if err != nil {
+				L.RaiseError("failed to get balance change: %v", err)
+				return 0
+			}
+
+			L.Push(lua.LNumber(toBalance.Sub(fromBalance).InexactFloat64()))
+			return 1
+		},
+	})
+
+	state.SetGlobal("schedule", tbl)
+}
+
+func findScheduleTransactions(ctx context.Context, opts *lua.LTable) ([]*database.Transaction, error) {
+	query := database.GetDbWithContext(ctx, database.DbTypeReadonly).
+		Where("deleted_at IS NULL").
+		Order("transaction_date_time, id")
+
+	if from, ok := opts.RawGetString("fromDate").(lua.LNumber); ok {
+		query = query.Where("transaction_date_time >= ?", time.Unix(int64(from), 0).UTC())
+	}
+
+	if to, ok := opts.RawGetString("toDate").(lua.LNumber); ok {
+		query = query.Where("transaction_date_time <= ?", time.Unix(int64(to), 0).UTC())
+	}
+
+	if ids := luaInt32Array(opts.RawGetString("accountIDs")); len(ids) > 0 {
+		query = query.Where("source_account_id IN ? OR destination_account_id IN ?", ids, ids)
+	}
+
+	if ids := luaInt32Array(opts.RawGetString("categoryIDs")); len(ids) > 0 {
+		query = query.Where("category_id IN ?", ids)
+	}
+
+	if types := luaInt32Array(opts.RawGetString("transactionTypes")); len(types) > 0 {
+		query = query.Where("transaction_type IN ?", types)
+	}
+
+	limit := scheduleFindDefaultLimit
+	if val, ok := opts.RawGetString("limit").(lua.LNumber); ok && val > 0 {
+		limit = min(int(val), scheduleFindMaxLimit)
+	}
+
+	var txs []*database.Transaction
+	if err := query.Limit(limit).Find(&txs).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return txs, nil
+}
+
+// balanceAt returns the end of day balance, daily_stat keeps a running balance per day. Its days
+// are calendar days of the account timezone, household when the account has none, so at is
+// converted the same way before it is compared.
+func balanceAt(ctx context.Context, accountID int32, at time.Time, household *time.Location) (decimal.Decimal, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeReadonly)
+
+	var accounts []*database.Account
+	if err := db.Where("id = ?", accountID).Limit(1).Find(&accounts).Error; err != nil {
+		return decimal.Zero, errors.WithStack(err)
+	}
+
+	day := timezone.DateOnly(at, timezone.AccountLocation(lo.FirstOrEmpty(accounts), household))
+
+	var stats []*database.DailyStat
+
+	if err := db.
+		Where("account_id = ? AND date <= ?", accountID, day.Format(time.DateOnly)).
+		Order("date desc").
+		Limit(1).
+		Find(&stats).Error; err != nil {
+		return decimal.Zero, errors.WithStack(err)
+	}
+
+	if len(stats) == 0 {
+		return decimal.Zero, nil
+	}
+
+	return stats[0].Amount, nil
+}
+
+func luaInt32Array(val lua.LValue) []int32 {
+	tbl, ok := val.(*lua.LTable)
+	if !ok {
+		return nil
+	}
+
+	var result []int32
+	tbl.ForEach(func(_ lua.LValue, item lua.LValue) {
+		if num, isNum := item.(lua.LNumber); isNum {
+			result = append(result, int32(num))
+		}
+	})
+
+	return result
+}
@@ -0,0 +1,133 @@
+package rules_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLuaSchedule(t *testing.T) {
+	effectiveDate := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("not available outside schedule", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		_, err := interpreter.Run(context.TODO(), `assert(schedule == nil)`, &database.Transaction{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("skip and new transactions", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+		session := &rules.ScheduleSession{RuleID: 5, EffectiveDate: effectiveDate}
+
+		_, err := interpreter.Run(rules.WithScheduleSession(context.TODO(), session), `
+			assert(schedule.ruleID == 5)
+			schedule.skip()
+
+			for i = 1, 2 do
+				local t = schedule.newTransaction()
+				t:title("rent " .. i)
+				t:sourceAmount(-100 * i)
+			end
+		`, &database.Transaction{})
+
+		require.NoError(t, err)
+		assert.True(t, session.Skipped)
+		require.Len(t, session.Created, 2)
+		assert.Equal(t, "rent 2", session.Created[1].Title)
+		assert.Equal(t, "-200", session.Created[1].SourceAmount.Decimal.String())
+		assert.Equal(t, effectiveDate, session.Created[0].TransactionDateTime)
+		assert.Empty(t, session.Updated())
+	})
+
+	t.Run("find transactions and balance", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		txs := []*database.Transaction{
+			{
+				Title:               "old",
+				SourceAccountID:     1,
+				TransactionDateTime: effectiveDate.AddDate(0, -2, 0),
+				TransactionDateOnly: effectiveDate.AddDate(0, -2, 0),
+				Extra:               map[string]string{},
+			},
+			{
+				Title:               "in period",
+				SourceAccountID:     1,
+				TransactionDateTime: effectiveDate.AddDate(0, 0, -5),
+				TransactionDateOnly: effectiveDate.AddDate(0, 0, -5),
+				Extra:               map[string]string{},
+			},
+		}
+		require.NoError(t, gormDB.Create(&txs).Error)
+		require.NoError(t, gormDB.Create(&[]*database.DailyStat{
+			{AccountID: 1, Date: effectiveDate.AddDate(0, 0, -10), Amount: decimal.NewFromInt(100)},
+			{AccountID: 1, Date: effectiveDate.AddDate(0, 0, -1), Amount: decimal.NewFromInt(70)},
+			{AccountID: 1, Date: effectiveDate.AddDate(0, 0, 1), Amount: decimal.NewFromInt(77)},
+		}).Error)
+
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+		session := &rules.ScheduleSession{RuleID: 5, EffectiveDate: effectiveDate}
+
+		_, err := interpreter.Run(rules.WithScheduleSession(context.TODO(), session), `
+			local found = schedule.findTransactions({
+				fromDate = schedule.effectiveDate - 30 * 86400,
+				toDate = schedule.effectiveDate,
+				accountIDs = {1},
+			})
+			assert(#found == 1)
+			found[1]:title("updated")
+
+			assert(schedule.balance(1) == 70)
+			assert(schedule.balanceChange(1, schedule.effectiveDate - 2 * 86400, schedule.effectiveDate) == -30)
+		`, &database.Transaction{})
+
+		require.NoError(t, err)
+		updated := session.Updated()
+		require.Len(t, updated, 1)
+		assert.Equal(t, txs[1].ID, updated[0].ID)
+		assert.Equal(t, "updated", updated[0].Title)
+	})
+
+	t.Run("balance on the household day", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		accounts := []*database.Account{
+			{Name: "household", Currency: "UAH", Extra: map[string]string{}},
+			{Name: "new york", Currency: "USD", Extra: map[string]string{}, Timezone: lo.ToPtr("America/New_York")},
+		}
+		require.NoError(t, gormDB.Create(&accounts).Error)
+
+		for _, account := range accounts {
+			require.NoError(t, gormDB.Create(&[]*database.DailyStat{
+				{AccountID: account.ID, Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Amount: decimal.NewFromInt(70)},
+				{AccountID: account.ID, Date: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Amount: decimal.NewFromInt(90)},
+			}).Error)
+		}
+
+		kyiv, err := time.LoadLocation("Europe/Kyiv")
+		require.NoError(t, err)
+
+		// 2026-03-01 22:30 UTC is already March 2nd in Kyiv, still March 1st in New York
+		session := &rules.ScheduleSession{
+			EffectiveDate: time.Date(2026, 3, 1, 22, 30, 0, 0, time.UTC),
+			Location:      kyiv,
+		}
+
+		_, err = rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{}).Run(rules.WithScheduleSession(context.TODO(), session), fmt.Sprintf(`
+			assert(schedule.balance(%d) == 90)
+			assert(schedule.balance(%d) == 70)
+			assert(schedule.balanceChange(%d, schedule.effectiveDate - 3600, schedule.effectiveDate) == 20)
+		`, accounts[0].ID, accounts[1].ID, accounts[0].ID), &database.Transaction{})
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,113 @@
+package rules
+
+import (
+	"github.com/ft-t/go-money/pkg/suggestions"
+	"github.com/samber/lo"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// SuggestCategory returns {ID, confidence} of the best category suggested from history, or nil.
+func (h *LuaHelpers) SuggestCategory(l *lua.LState) int {
+	suggestion := h.suggest(l)
+	if suggestion == nil {
+		return 0
+	}
+
+	if len(suggestion.Categories) == 0 {
+		l.Push(lua.LNil)
+		return 1
+	}
+
+	l.Push(candidateTable(l, suggestion.Categories[0]))
+
+	return 1
+}
+
+// SuggestTags returns an array of {ID, confidence} tables of tags suggested from history.
+func (h *LuaHelpers) SuggestTags(l *lua.LState) int {
+	suggestion := h.suggest(l)
+	if suggestion == nil {
+		return 0
+	}
+
+	tbl := l.NewTable()
+	for _, candidate := range suggestion.Tags {
+		tbl.Append(candidateTable(l, candidate))
+	}
+
+	l.Push(tbl)
+
+	return 1
+}
+
+// ApplySuggestions sets the suggested category when the transaction has none and adds suggested
+// tags, both only at or above the confidence threshold. It returns true when anything changed.
+func (h *LuaHelpers) ApplySuggestions(l *lua.LState) int {
+	if l.GetTop() != 3 {
+		l.ArgError(1, "transaction and confidence threshold expected")
+		return 0
+	}
+
+	threshold := float64(l.CheckNumber(3))
+
+	suggestion := h.suggest(l)
+	if suggestion == nil {
+		return 0
+	}
+
+	wrapped := l.CheckUserData(2).Value.(*LuaTransactionWrapper)
+	changed := false
+
+	if wrapped.tx.CategoryID == nil && len(suggestion.Categories) > 0 && suggestion.Categories[0].Confidence >= threshold {
+		wrapped.tx.CategoryID = lo.ToPtr(suggestion.Categories[0].ID)
+		changed = true
+	}
+
+	for _, candidate := range suggestion.Tags {
+		if candidate.Confidence < threshold || lo.Contains(wrapped.tx.TagIDs, candidate.ID) {
+			continue
+		}
+
+		wrapped.tx.TagIDs = append(wrapped.tx.TagIDs, candidate.ID)
+		changed = true
+	}
+
+	if changed {
+		wrapped.modified = true
+	}
+
+	l.Push(lua.LBool(changed))
+
+	return 1
+}
+
+func (h *LuaHelpers) suggest(l *lua.LState) *suggestions.Suggestion {
+	ud := l.CheckUserData(2)
+
+	wrapped, ok := ud.Value.(*LuaTransactionWrapper)
+	if !ok {
+		l.ArgError(2, "transaction expected")
+		return nil
+	}
+
+	if h.cfg.SuggestionsSvc == nil {
+		l.RaiseError("suggestions are not configured")
+		return nil
+	}
+
+	result, err := h.cfg.SuggestionsSvc.Suggest(h.ctx, []*suggestions.Input{suggestions.InputFromTransaction(wrapped.tx)})
+	if err != nil {
+		l.RaiseError("failed to get suggestions: %v", err)
+		return nil
+	}
+
+	return result[0]
+}
+
+func candidateTable(l *lua.LState, candidate *suggestions.Candidate) *lua.LTable {
+	tbl := l.NewTable()
+	l.SetField(tbl, "ID", lua.LNumber(candidate.ID))
+	l.SetField(tbl, "confidence", lua.LNumber(candidate.Confidence))
+
+	return tbl
+}
@@ -17,6 +17,28 @@ func (w *LuaTransactionWrapper) SourceAmount(l *lua.LState) int {
 	})
 }
 
+func (w *LuaTransactionWrapper) FxSourceAmount(l *lua.LState) int {
+	return w.getSetNullDecimalField(l, w.tx.FxSourceAmount, func(nullDecimal decimal.NullDecimal) {
+		w.tx.FxSourceAmount = nullDecimal
+	})
+}
+
+func (w *LuaTransactionWrapper) FxSourceCurrency(l *lua.LState) int {
+	return w.getSetStringField(l, w.tx.FxSourceCurrency, func(val string) {
+		w.tx.FxSourceCurrency = val
+	})
+}
+
+// SourceAmountInBaseCurrency is read-only, base amounts are recalculated after rules are applied.
+func (w *LuaTransactionWrapper) SourceAmountInBaseCurrency(l *lua.LState) int {
+	return w.getNullDecimalField(l, w.tx.SourceAmountInBaseCurrency)
+}
+
+// DestinationAmountInBaseCurrency is read-only, base amounts are recalculated after rules are applied.
+func (w *LuaTransactionWrapper) DestinationAmountInBaseCurrency(l *lua.LState) int {
+	return w.getNullDecimalField(l, w.tx.DestinationAmountInBaseCurrency)
+}
+
 func (w *LuaTransactionWrapper) GetSourceAmountWithDecimalPlaces(l *lua.LState) int {
 	return w.getAmountWithDecimalPlaces(l, w.tx.SourceAmount)
 }
@@ -61,6 +83,10 @@ func (w *LuaTransactionWrapper) getSetNullDecimalField(l *lua.LState, val decima
 		return 0
 	}
 
+	return w.getNullDecimalField(l, val)
+}
+
+func (w *LuaTransactionWrapper) getNullDecimalField(l *lua.LState, val decimal.NullDecimal) int {
 	if val.Valid {
 		l.Push(lua.LNumber(val.Decimal.InexactFloat64()))
 	} else {
@@ -139,3 +139,48 @@ func TestLuaAmounts(t *testing.T) {
 		assert.False(t, result)
 	})
 }
+
+func TestLuaFxAndBaseAmounts(t *testing.T) {
+	t.Run("fx source amount and currency", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		script := `
+		if tx:fxSourceCurrency() == "EUR" and tx:fxSourceAmount() == 10 then
+			tx:fxSourceAmount(12.5)
+			tx:fxSourceCurrency("USD")
+		end
+	`
+
+		tx := &database.Transaction{
+			FxSourceAmount:   decimal.NewNullDecimal(decimal.NewFromInt(10)),
+			FxSourceCurrency: "EUR",
+		}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, decimal.NewFromFloat(12.5), tx.FxSourceAmount.Decimal)
+		assert.Equal(t, "USD", tx.FxSourceCurrency)
+	})
+
+	t.Run("base currency amounts are read only", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		script := `
+		if tx:sourceAmountInBaseCurrency() == -25 and tx:destinationAmountInBaseCurrency() == nil then
+			tx:notes("matched")
+		end
+		tx:sourceAmountInBaseCurrency(100)
+	`
+
+		tx := &database.Transaction{
+			SourceAmountInBaseCurrency: decimal.NewNullDecimal(decimal.NewFromInt(-25)),
+		}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, "matched", tx.Notes)
+		assert.Equal(t, decimal.NewFromInt(-25), tx.SourceAmountInBaseCurrency.Decimal)
+	})
+}
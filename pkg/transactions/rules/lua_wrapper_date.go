@@ -5,6 +5,17 @@ import (
 	"time"
 )
 
+// TransactionDateTime returns the transaction date time as unix seconds, compatible with os.date.
+func (w *LuaTransactionWrapper) TransactionDateTime(l *lua.LState) int {
+	l.Push(lua.LNumber(w.tx.TransactionDateTime.Unix()))
+	return 1
+}
+
+func (w *LuaTransactionWrapper) TransactionDateTimeString(l *lua.LState) int {
+	l.Push(lua.LString(w.tx.TransactionDateTime.Format(time.RFC3339)))
+	return 1
+}
+
 func (w *LuaTransactionWrapper) TransactionDateTimeAddDate(l *lua.LState) int {
 	if l.GetTop() != 4 { // set
 		l.ArgError(1, "expected 3 arguments")
@@ -103,3 +103,22 @@ func TestSetTime(t *testing.T) {
 		assert.Equal(t, dt, tx.TransactionDateTime)
 	})
 }
+
+func TestTransactionDateTimeRead(t *testing.T) {
+	interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+	script := `
+	if os.date("!%Y-%m-%d", tx:transactionDateTime()) == "2025-03-04" then
+		tx:notes(tx:transactionDateTimeString())
+	end
+`
+
+	tx := &database.Transaction{
+		TransactionDateTime: time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC),
+	}
+
+	result, err := interpreter.Run(context.TODO(), script, tx)
+	assert.NoError(t, err)
+	assert.True(t, result)
+	assert.Equal(t, "2025-03-04T10:30:00Z", tx.Notes)
+}
@@ -0,0 +1,55 @@
+package rules
+
+import (
+	lua "github.com/yuin/gopher-lua"
+)
+
+func (w *LuaTransactionWrapper) GetExtra(l *lua.LState) int {
+	if l.GetTop() != 2 {
+		l.ArgError(1, "key expected")
+		return 0
+	}
+
+	val, ok := w.tx.Extra[l.CheckString(2)]
+	if !ok {
+		l.Push(lua.LNil)
+		return 1
+	}
+
+	l.Push(lua.LString(val))
+	return 1
+}
+
+func (w *LuaTransactionWrapper) SetExtra(l *lua.LState) int {
+	if l.GetTop() != 3 {
+		l.ArgError(1, "key and value expected")
+		return 0
+	}
+
+	key := l.CheckString(2)
+
+	w.modified = true
+
+	if l.Get(3) == lua.LNil {
+		delete(w.tx.Extra, key)
+		return 0
+	}
+
+	if w.tx.Extra == nil {
+		w.tx.Extra = map[string]string{}
+	}
+
+	w.tx.Extra[key] = l.CheckString(3)
+
+	return 0
+}
+
+func (w *LuaTransactionWrapper) GetAllExtra(l *lua.LState) int {
+	tbl := l.NewTable()
+	for k, v := range w.tx.Extra {
+		tbl.RawSetString(k, lua.LString(v))
+	}
+
+	l.Push(tbl)
+	return 1
+}
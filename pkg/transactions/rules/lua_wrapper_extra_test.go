@@ -0,0 +1,89 @@
+package rules_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLuaExtra(t *testing.T) {
+	t.Run("get extra", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		script := `
+		if tx:getExtra("mcc") == "5411" and tx:getExtra("missing") == nil then
+			tx:title("groceries")
+		end
+	`
+
+		tx := &database.Transaction{
+			Extra: map[string]string{"mcc": "5411"},
+		}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, "groceries", tx.Title)
+	})
+
+	t.Run("set extra on nil map", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		script := `tx:setExtra("source", "rule")`
+
+		tx := &database.Transaction{}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, map[string]string{"source": "rule"}, tx.Extra)
+	})
+
+	t.Run("remove extra", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		script := `tx:setExtra("a", nil)`
+
+		tx := &database.Transaction{
+			Extra: map[string]string{"a": "1", "b": "2"},
+		}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, map[string]string{"b": "2"}, tx.Extra)
+	})
+
+	t.Run("set extra invalid args", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		result, err := interpreter.Run(context.TODO(), `tx:setExtra("a")`, &database.Transaction{})
+		assert.ErrorContains(t, err, "key and value expected")
+		assert.False(t, result)
+	})
+
+	t.Run("get all extra", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		script := `
+		local keys = {}
+		for k, v in pairs(tx:getAllExtra()) do
+			table.insert(keys, k .. "=" .. v)
+		end
+		table.sort(keys)
+		tx:notes(table.concat(keys, ","))
+	`
+
+		tx := &database.Transaction{
+			Extra: map[string]string{"b": "2", "a": "1"},
+		}
+
+		result, err := interpreter.Run(context.TODO(), script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, "a=1,b=2", tx.Notes)
+	})
+}
@@ -4,14 +4,32 @@ import (
 	"context"
 	"github.com/cockroachdb/errors"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/timezone"
 	"github.com/ft-t/go-money/pkg/transactions/history"
 	"github.com/go-co-op/gocron/v2"
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"slices"
+	"sync"
 	"time"
 )
 
+const defaultReloadInterval = 30 * time.Second
+
+type CatchUpPolicy string
+
+const (
+	CatchUpPolicyNone   CatchUpPolicy = "none"
+	CatchUpPolicyLatest CatchUpPolicy = "latest"
+	CatchUpPolicyAll    CatchUpPolicy = "all"
+)
+
 type Scheduler struct {
 	cfg       *SchedulerConfig
 	scheduler gocron.Scheduler
+	version   string // ruleSetVersion the running jobs were built from
+	mut       sync.Mutex
 }
 
 type SchedulerConfig struct {
@@ -19,6 +37,11 @@ type SchedulerConfig struct {
 	CronValidationOpts []gocron.SchedulerOption
 	RuleInterpreter    Interpreter
 	TransactionSvc     TransactionSvc
+	CatchUpPolicy      CatchUpPolicy  // runs missed since last_run_at executed by CatchUp, empty = none
+	MaxCatchUpRuns     int            // upper bound per rule for CatchUpPolicyAll, most recent runs are kept
+	Elector            gocron.Elector // when set, cron and catch-up runs happen only on the elected replica
+	Location           *time.Location // household timezone for cron evaluation and transaction dates, UTC when nil
+	ReloadInterval     time.Duration  // how often Watch looks for rules changed through other replicas
 }
 
 func NewScheduler(
@@ -29,16 +52,28 @@ func NewScheduler(
 	}
 }
 
+// Reinit replaces the cron jobs with the enabled rules currently stored. It does not catch up
+// missed runs, see CatchUp.
 func (s *Scheduler) Reinit(ctx context.Context) error {
-	var rules []database.ScheduleRule
+	s.mut.Lock()
+	defer s.mut.Unlock()
 
-	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).
-		Where("enabled = true").
-		Find(&rules).Error; err != nil {
+	version, err := s.ruleSetVersion(ctx)
+	if err != nil {
 		return err
 	}
 
-	sh, err := gocron.NewScheduler(s.cfg.Opts...)
+	rules, err := s.enabledRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := append(slices.Clone(s.cfg.Opts), gocron.WithLocation(s.location()))
+	if s.cfg.Elector != nil {
+		opts = append(opts, gocron.WithDistributedElector(s.cfg.Elector))
+	}
+
+	sh, err := gocron.NewScheduler(opts...)
 	if err != nil {
 		return err
 	}
@@ -64,32 +99,272 @@ func (s *Scheduler) Reinit(ctx context.Context) error {
 	}
 
 	s.scheduler = sh
+	s.version = version
 	sh.Start()
 
 	return nil
 }
 
+// Watch calls Sync every ReloadInterval until the context is cancelled. Rule RPCs only reload
+// the replica serving them, Watch makes the others, the leader included, follow.
+func (s *Scheduler) Watch(ctx context.Context) {
+	ticker := time.NewTicker(lo.CoalesceOrEmpty(s.cfg.ReloadInterval, defaultReloadInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Sync(ctx); err != nil && ctx.Err() == nil {
+				zerolog.Ctx(ctx).Err(err).Msg("failed to sync schedule rules")
+			}
+		}
+	}
+}
+
+// Sync reloads the jobs when schedule_rules changed since the last Reinit and reports whether it did.
+func (s *Scheduler) Sync(ctx context.Context) (bool, error) {
+	version, err := s.ruleSetVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	s.mut.Lock()
+	current := s.version
+	s.mut.Unlock()
+
+	if version == current {
+		return false, nil
+	}
+
+	return true, s.Reinit(ctx)
+}
+
+// ruleSetVersion hashes id, updated_at and deleted_at of every schedule rule. Creating, editing or
+// deleting a rule changes it, runs moving last_run_at do not. Unlike max(updated_at) it does not
+// depend on the clocks of the replicas that made the edits.
+func (s *Scheduler) ruleSetVersion(ctx context.Context) (string, error) {
+	var version string
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).
+		Raw(`select coalesce(md5(string_agg(concat_ws(':', id, updated_at, deleted_at), ',' order by id)), '')
+			from schedule_rules`).
+		Scan(&version).Error; err != nil {
+		return "", errors.Wrap(err, "failed to get schedule rules version")
+	}
+
+	return version, nil
+}
+
 func (s *Scheduler) ExecuteTask(
 	ctx context.Context,
 	rule database.ScheduleRule,
 ) error {
-	timeNow := time.Now().UTC()
+	_, err := s.execute(ctx, rule, time.Now().UTC(), database.ScheduleRunTriggerCron)
+
+	return err
+}
+
+// RunNow executes a schedule rule on demand as if it fired at effectiveDate.
+// Unlike cron and catch-up runs it does not move last_run_at.
+func (s *Scheduler) RunNow(
+	ctx context.Context,
+	ruleID int32,
+	effectiveDate time.Time,
+) (*database.ScheduleRuleRun, error) {
+	var rule database.ScheduleRule
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).
+		Where("id = ?", ruleID).First(&rule).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get schedule rule")
+	}
+
+	if effectiveDate.IsZero() {
+		effectiveDate = time.Now()
+	}
+
+	return s.execute(ctx, rule, effectiveDate.UTC(), database.ScheduleRunTriggerManual)
+}
+
+// execute runs the rule and records the outcome in schedule_rule_runs. The run is returned
+// together with the script error, if any.
+func (s *Scheduler) execute(
+	ctx context.Context,
+	rule database.ScheduleRule,
+	effectiveDate time.Time,
+	trigger database.ScheduleRunTrigger,
+) (*database.ScheduleRuleRun, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	run := &database.ScheduleRuleRun{
+		ScheduleRuleID: rule.ID,
+		Trigger:        trigger,
+		Status:         database.ScheduleRunStatusRunning,
+		EffectiveDate:  effectiveDate,
+		StartedAt:      time.Now().UTC(),
+	}
+
+	if err := db.Create(run).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to create schedule run")
+	}
+
+	txIDs, runErr := s.runScript(ctx, rule, effectiveDate)
+
+	run.FinishedAt = lo.ToPtr(time.Now().UTC())
+	run.TransactionIDs = txIDs
+	run.Status = database.ScheduleRunStatusSuccess
+
+	if runErr != nil {
+		run.Status = database.ScheduleRunStatusFailed
+		run.Error = lo.ToPtr(runErr.Error())
+	}
+
+	if err := db.Save(run).Error; err != nil {
+		return run, errors.CombineErrors(runErr, errors.Wrap(err, "failed to update schedule run"))
+	}
+
+	if trigger != database.ScheduleRunTriggerManual {
+		if err := db.Model(&database.ScheduleRule{}).Where("id = ?", rule.ID).
+			UpdateColumn("last_run_at", effectiveDate).Error; err != nil {
+			return run, errors.CombineErrors(runErr, errors.Wrap(err, "failed to update last_run_at"))
+		}
+	}
+
+	return run, runErr
+}
 
+func (s *Scheduler) runScript(
+	ctx context.Context,
+	rule database.ScheduleRule,
+	effectiveDate time.Time,
+) ([]int64, error) {
 	tx := &database.Transaction{
 		Extra:               map[string]string{},
-		TransactionDateTime: timeNow,
-		TransactionDateOnly: timeNow,
+		TransactionDateTime: effectiveDate,
+		TransactionDateOnly: effectiveDate,
+	}
+
+	session := &ScheduleSession{
+		RuleID:        rule.ID,
+		EffectiveDate: effectiveDate,
+		Location:      s.location(),
 	}
 
-	_, err := s.cfg.RuleInterpreter.Run(ctx, rule.Script, tx)
+	_, err := s.cfg.RuleInterpreter.Run(WithScheduleSession(ctx, session), rule.Script, tx)
 	if err != nil {
-		return errors.Wrapf(err, "failed to run rule script for rule_id: %d", rule.ID)
+		return nil, errors.Wrapf(err, "failed to run rule script for rule_id: %d", rule.ID)
+	}
+
+	created := session.Created
+	if !session.Skipped {
+		created = append([]*database.Transaction{tx}, created...)
+	}
+
+	for _, newTx := range created {
+		newTx.TransactionDateOnly = timezone.DateOnly(newTx.TransactionDateTime, s.location())
 	}
 
 	ctx = history.WithActor(ctx, history.SchedulerActor(rule.ID))
-	_, err = s.cfg.TransactionSvc.CreateRawTransaction(ctx, tx)
+	resp, err := s.cfg.TransactionSvc.UpsertRawTransactions(ctx, created, session.Updated())
+	if err != nil {
+		return nil, err
+	}
 
-	return err
+	var txIDs []int64
+	for _, r := range resp {
+		if r == nil || r.Transaction == nil {
+			continue
+		}
+
+		txIDs = append(txIDs, r.Transaction.Id)
+	}
+
+	return txIDs, nil
+}
+
+// CatchUp executes runs missed since last_run_at while the server was down. It is meant to run
+// once at startup, before Reinit starts the cron jobs, so catch-up and cron runs never overlap.
+// Failures are recorded in the run log and do not block startup.
+func (s *Scheduler) CatchUp(ctx context.Context) error {
+	if s.cfg.Elector != nil {
+		if err := s.cfg.Elector.IsLeader(ctx); err != nil {
+			zerolog.Ctx(ctx).Info().Err(err).Msg("skipping schedule rules catch-up on non-leader replica")
+			return nil
+		}
+	}
+
+	rules, err := s.enabledRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, rule := range rules {
+		for _, effectiveDate := range s.MissedRuns(rule, now) {
+			if _, err := s.execute(ctx, rule, effectiveDate, database.ScheduleRunTriggerCatchUp); err != nil {
+				zerolog.Ctx(ctx).Error().Err(err).
+					Int32("rule_id", rule.ID).
+					Time("effective_date", effectiveDate).
+					Msg("failed to catch up schedule rule run")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Scheduler) enabledRules(ctx context.Context) ([]database.ScheduleRule, error) {
+	var rules []database.ScheduleRule
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).
+		Where("enabled = true").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// MissedRuns returns cron fire times after last_run_at and not after now, filtered by the catch-up policy.
+func (s *Scheduler) MissedRuns(rule database.ScheduleRule, now time.Time) []time.Time {
+	if rule.LastRunAt == nil {
+		return nil
+	}
+
+	var limit int
+	switch s.cfg.CatchUpPolicy {
+	case CatchUpPolicyLatest:
+		limit = 1
+	case CatchUpPolicyAll:
+		limit = max(s.cfg.MaxCatchUpRuns, 1)
+	default:
+		return nil
+	}
+
+	schedule, err := cron.ParseStandard(rule.CronExpression)
+	if err != nil {
+		return nil
+	}
+
+	var missed []time.Time
+	for next := schedule.Next(rule.LastRunAt.In(s.location())); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+		missed = append(missed, next.UTC())
+
+		if len(missed) > limit {
+			missed = missed[1:]
+		}
+	}
+
+	return missed
+}
+
+func (s *Scheduler) location() *time.Location {
+	if s.cfg.Location == nil {
+		return time.UTC
+	}
+
+	return s.cfg.Location
 }
 
 func (s *Scheduler) ValidateCronExpression(cron string) error {
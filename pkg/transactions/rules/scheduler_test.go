@@ -2,9 +2,13 @@ package rules_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	rulesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/rules/v1"
 	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
 	"github.com/ft-t/go-money/pkg/database"
 	"github.com/ft-t/go-money/pkg/testingutils"
 	"github.com/ft-t/go-money/pkg/transactions/history"
@@ -50,7 +54,7 @@ func TestExecuteTask(t *testing.T) {
 		})
 
 		ruleInt.EXPECT().Run(gomock.Any(), "hello world", gomock.Any()).Return(false, nil)
-		txSvc.EXPECT().CreateRawTransaction(gomock.Any(), gomock.Any()).
+		txSvc.EXPECT().UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil, nil)
 
 		err := sh.ExecuteTask(context.TODO(), database.ScheduleRule{
@@ -89,8 +93,8 @@ func TestExecuteTask_SetsSchedulerActor(t *testing.T) {
 
 	ruleInt.EXPECT().Run(gomock.Any(), "script", gomock.Any()).Return(false, nil)
 	txSvc.EXPECT().
-		CreateRawTransaction(gomock.Any(), gomock.Any()).
-		DoAndReturn(func(ctx context.Context, _ *database.Transaction) (*transactionsv1.CreateTransactionResponse, error) {
+		UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ []*database.Transaction, _ []*database.Transaction) ([]*transactionsv1.CreateTransactionResponse, error) {
 			actor, ok := history.ActorFromContext(ctx)
 			require.True(t, ok, "scheduler must set history actor")
 			assert.Equal(t, database.TransactionHistoryActorTypeScheduler, actor.Type)
@@ -102,6 +106,66 @@ func TestExecuteTask_SetsSchedulerActor(t *testing.T) {
 	require.NoError(t, sh.ExecuteTask(context.Background(), database.ScheduleRule{ID: 77, Script: "script"}))
 }
 
+func TestExecuteTask_ScheduleSession(t *testing.T) {
+	t.Run("multiple transactions", func(t *testing.T) {
+		ruleInt := NewMockInterpreter(gomock.NewController(t))
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+
+		sh := rules.NewScheduler(&rules.SchedulerConfig{
+			RuleInterpreter: ruleInt,
+			TransactionSvc:  txSvc,
+		})
+
+		ruleInt.EXPECT().Run(gomock.Any(), "script", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ string, _ *database.Transaction) (bool, error) {
+				session, ok := rules.ScheduleSessionFromContext(ctx)
+				require.True(t, ok)
+				assert.Equal(t, int32(12), session.RuleID)
+
+				session.Created = append(session.Created, &database.Transaction{Title: "extra"})
+				return true, nil
+			})
+		txSvc.EXPECT().UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, created []*database.Transaction, updated []*database.Transaction) ([]*transactionsv1.CreateTransactionResponse, error) {
+				require.Len(t, created, 2)
+				assert.Equal(t, "extra", created[1].Title)
+				assert.Empty(t, updated)
+
+				return []*transactionsv1.CreateTransactionResponse{
+					{Transaction: &gomoneypbv1.Transaction{Id: 1}},
+					{Transaction: &gomoneypbv1.Transaction{Id: 2}},
+				}, nil
+			})
+
+		assert.NoError(t, sh.ExecuteTask(context.TODO(), database.ScheduleRule{ID: 12, Script: "script"}))
+	})
+
+	t.Run("skip default transaction", func(t *testing.T) {
+		ruleInt := NewMockInterpreter(gomock.NewController(t))
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+
+		sh := rules.NewScheduler(&rules.SchedulerConfig{
+			RuleInterpreter: ruleInt,
+			TransactionSvc:  txSvc,
+		})
+
+		ruleInt.EXPECT().Run(gomock.Any(), "script", gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ string, _ *database.Transaction) (bool, error) {
+				session, _ := rules.ScheduleSessionFromContext(ctx)
+				session.Skipped = true
+				return false, nil
+			})
+		txSvc.EXPECT().UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, created []*database.Transaction, updated []*database.Transaction) ([]*transactionsv1.CreateTransactionResponse, error) {
+				assert.Empty(t, created)
+				assert.Empty(t, updated)
+				return nil, nil
+			})
+
+		assert.NoError(t, sh.ExecuteTask(context.TODO(), database.ScheduleRule{ID: 13, Script: "script"}))
+	})
+}
+
 func TestReinit(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
@@ -161,3 +225,407 @@ func TestReinit(t *testing.T) {
 		})
 	})
 }
+
+func TestSync(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	rule := &database.ScheduleRule{
+		Script:         "script",
+		Title:          "daily",
+		CronExpression: "0 0 * * *",
+		Enabled:        true,
+	}
+	assert.NoError(t, gormDB.Create(rule).Error)
+
+	serving := rules.NewScheduler(&rules.SchedulerConfig{})
+	leader := rules.NewScheduler(&rules.SchedulerConfig{})
+
+	require.NoError(t, serving.Reinit(context.TODO()))
+	require.NoError(t, leader.Reinit(context.TODO()))
+
+	reloaded, err := leader.Sync(context.TODO())
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	mapper := NewMockMapperSvc(gomock.NewController(t))
+	mapper.EXPECT().MapScheduleRule(gomock.Any()).Return(&gomoneypbv1.ScheduleRule{}).AnyTimes()
+
+	svc := rules.NewScheduleService(mapper, serving)
+
+	t.Run("edit", func(t *testing.T) {
+		_, err = svc.UpdateRule(context.TODO(), &rulesv1.UpdateScheduleRuleRequest{
+			Rule: &gomoneypbv1.ScheduleRule{
+				Id:             rule.ID,
+				Title:          "daily",
+				Script:         "script",
+				CronExpression: "0 1 * * *",
+				Enabled:        false,
+			},
+		})
+		require.NoError(t, err)
+
+		reloaded, err = serving.Sync(context.TODO())
+		assert.NoError(t, err)
+		assert.False(t, reloaded) // already reloaded by the rpc
+
+		reloaded, err = leader.Sync(context.TODO())
+		assert.NoError(t, err)
+		assert.True(t, reloaded)
+
+		reloaded, err = leader.Sync(context.TODO())
+		assert.NoError(t, err)
+		assert.False(t, reloaded)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err = svc.DeleteRule(context.TODO(), &rulesv1.DeleteScheduleRuleRequest{Id: rule.ID})
+		require.NoError(t, err)
+
+		reloaded, err = leader.Sync(context.TODO())
+		assert.NoError(t, err)
+		assert.True(t, reloaded)
+	})
+
+	t.Run("runs do not reload", func(t *testing.T) {
+		assert.NoError(t, gormDB.Create(&database.ScheduleRule{
+			Script: "script", Title: "other", CronExpression: "0 0 * * *", Enabled: true,
+		}).Error)
+
+		reloaded, err = leader.Sync(context.TODO())
+		assert.NoError(t, err)
+		assert.True(t, reloaded)
+
+		assert.NoError(t, gormDB.Model(&database.ScheduleRule{}).Where("title = ?", "other").
+			UpdateColumn("last_run_at", time.Now().UTC()).Error)
+
+		reloaded, err = leader.Sync(context.TODO())
+		assert.NoError(t, err)
+		assert.False(t, reloaded)
+	})
+}
+
+func TestExecuteTask_RecordsRun(t *testing.T) {
+	t.Run("success updates last_run_at", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		rule := &database.ScheduleRule{Script: "script", Title: "rent", CronExpression: "0 0 1 * *", Enabled: true}
+		assert.NoError(t, gormDB.Create(rule).Error)
+
+		ruleInt := NewMockInterpreter(gomock.NewController(t))
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+
+		ruleInt.EXPECT().Run(gomock.Any(), "script", gomock.Any()).Return(true, nil)
+		txSvc.EXPECT().UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]*transactionsv1.CreateTransactionResponse{
+				{Transaction: &gomoneypbv1.Transaction{Id: 55}},
+			}, nil)
+
+		sh := rules.NewScheduler(&rules.SchedulerConfig{
+			RuleInterpreter: ruleInt,
+			TransactionSvc:  txSvc,
+		})
+
+		assert.NoError(t, sh.ExecuteTask(context.TODO(), *rule))
+
+		var runs []*database.ScheduleRuleRun
+		assert.NoError(t, gormDB.Where("schedule_rule_id = ?", rule.ID).Find(&runs).Error)
+		require.Len(t, runs, 1)
+		assert.Equal(t, database.ScheduleRunStatusSuccess, runs[0].Status)
+		assert.Equal(t, database.ScheduleRunTriggerCron, runs[0].Trigger)
+		assert.EqualValues(t, []int64{55}, runs[0].TransactionIDs)
+		assert.NotNil(t, runs[0].FinishedAt)
+
+		var stored database.ScheduleRule
+		assert.NoError(t, gormDB.First(&stored, rule.ID).Error)
+		assert.NotNil(t, stored.LastRunAt)
+	})
+
+	t.Run("failure is recorded", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		rule := &database.ScheduleRule{Script: "script", Title: "rent", CronExpression: "0 0 1 * *", Enabled: true}
+		assert.NoError(t, gormDB.Create(rule).Error)
+
+		ruleInt := NewMockInterpreter(gomock.NewController(t))
+		ruleInt.EXPECT().Run(gomock.Any(), "script", gomock.Any()).Return(false, assert.AnError)
+
+		sh := rules.NewScheduler(&rules.SchedulerConfig{
+			RuleInterpreter: ruleInt,
+		})
+
+		assert.ErrorIs(t, sh.ExecuteTask(context.TODO(), *rule), assert.AnError)
+
+		var run database.ScheduleRuleRun
+		assert.NoError(t, gormDB.Where("schedule_rule_id = ?", rule.ID).First(&run).Error)
+		assert.Equal(t, database.ScheduleRunStatusFailed, run.Status)
+		require.NotNil(t, run.Error)
+		assert.Contains(t, *run.Error, assert.AnError.Error())
+	})
+}
+
+func TestRunNow(t *testing.T) {
+	t.Run("uses effective date and keeps last_run_at", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		rule := &database.ScheduleRule{Script: "script", Title: "rent", CronExpression: "0 0 1 * *", Enabled: true}
+		assert.NoError(t, gormDB.Create(rule).Error)
+
+		effectiveDate := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		ruleInt := NewMockInterpreter(gomock.NewController(t))
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+
+		ruleInt.EXPECT().Run(gomock.Any(), "script", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, tx *database.Transaction) (bool, error) {
+				assert.Equal(t, effectiveDate, tx.TransactionDateTime)
+				return true, nil
+			})
+		txSvc.EXPECT().UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		sh := rules.NewScheduler(&rules.SchedulerConfig{
+			RuleInterpreter: ruleInt,
+			TransactionSvc:  txSvc,
+		})
+
+		run, err := sh.RunNow(context.TODO(), rule.ID, effectiveDate)
+		assert.NoError(t, err)
+		assert.Equal(t, database.ScheduleRunTriggerManual, run.Trigger)
+		assert.Equal(t, effectiveDate, run.EffectiveDate)
+
+		var stored database.ScheduleRule
+		assert.NoError(t, gormDB.First(&stored, rule.ID).Error)
+		assert.Nil(t, stored.LastRunAt)
+	})
+
+	t.Run("rule not found", func(t *testing.T) {
+		_, err := rules.NewScheduler(&rules.SchedulerConfig{}).RunNow(context.TODO(), 999999, time.Time{})
+		assert.ErrorContains(t, err, "failed to get schedule rule")
+	})
+}
+
+func TestCatchUp(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	lastRun := time.Now().UTC().AddDate(0, 0, -3).Truncate(24 * time.Hour)
+	rule := &database.ScheduleRule{
+		Script:         "script",
+		Title:          "daily",
+		CronExpression: "0 0 * * *",
+		Enabled:        true,
+		LastRunAt:      &lastRun,
+	}
+	assert.NoError(t, gormDB.Create(rule).Error)
+
+	ruleInt := NewMockInterpreter(gomock.NewController(t))
+	txSvc := NewMockTransactionSvc(gomock.NewController(t))
+
+	ruleInt.EXPECT().Run(gomock.Any(), "script", gomock.Any()).Return(true, nil).Times(3)
+	txSvc.EXPECT().UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(3)
+
+	sh := rules.NewScheduler(&rules.SchedulerConfig{
+		RuleInterpreter: ruleInt,
+		TransactionSvc:  txSvc,
+		CatchUpPolicy:   rules.CatchUpPolicyAll,
+		MaxCatchUpRuns:  10,
+	})
+
+	assert.NoError(t, sh.CatchUp(context.TODO()))
+
+	var runs []*database.ScheduleRuleRun
+	assert.NoError(t, gormDB.Where("schedule_rule_id = ?", rule.ID).Order("id").Find(&runs).Error)
+	require.Len(t, runs, 3)
+	assert.Equal(t, database.ScheduleRunTriggerCatchUp, runs[0].Trigger)
+	assert.Equal(t, lastRun.AddDate(0, 0, 1), runs[0].EffectiveDate)
+
+	var stored database.ScheduleRule
+	assert.NoError(t, gormDB.First(&stored, rule.ID).Error)
+	assert.Equal(t, runs[2].EffectiveDate, stored.LastRunAt.UTC())
+
+	// nothing missed any more
+	assert.NoError(t, sh.CatchUp(context.TODO()))
+}
+
+func TestReinit_DoesNotCatchUp(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	lastRun := time.Now().UTC().AddDate(0, 0, -3).Truncate(24 * time.Hour)
+	rule := &database.ScheduleRule{
+		Script:         "script",
+		Title:          "daily",
+		CronExpression: "0 0 * * *",
+		Enabled:        true,
+		LastRunAt:      &lastRun,
+	}
+	assert.NoError(t, gormDB.Create(rule).Error)
+
+	sh := rules.NewScheduler(&rules.SchedulerConfig{
+		RuleInterpreter: NewMockInterpreter(gomock.NewController(t)),
+		TransactionSvc:  NewMockTransactionSvc(gomock.NewController(t)),
+		CatchUpPolicy:   rules.CatchUpPolicyAll,
+		MaxCatchUpRuns:  10,
+	})
+
+	assert.NoError(t, sh.Reinit(context.TODO())) // rule changes reload jobs without running scripts
+
+	var count int64
+	assert.NoError(t, gormDB.Model(&database.ScheduleRuleRun{}).Where("schedule_rule_id = ?", rule.ID).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+type followerElector struct{}
+
+func (followerElector) IsLeader(context.Context) error {
+	return errors.New("not a leader")
+}
+
+func TestCatchUp_SkippedOnFollower(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	lastRun := time.Now().UTC().AddDate(0, 0, -3).Truncate(24 * time.Hour)
+	rule := &database.ScheduleRule{
+		Script:         "script",
+		Title:          "daily",
+		CronExpression: "0 0 * * *",
+		Enabled:        true,
+		LastRunAt:      &lastRun,
+	}
+	assert.NoError(t, gormDB.Create(rule).Error)
+
+	sh := rules.NewScheduler(&rules.SchedulerConfig{
+		RuleInterpreter: NewMockInterpreter(gomock.NewController(t)),
+		TransactionSvc:  NewMockTransactionSvc(gomock.NewController(t)),
+		CatchUpPolicy:   rules.CatchUpPolicyAll,
+		MaxCatchUpRuns:  10,
+		Elector:         followerElector{},
+	})
+
+	assert.NoError(t, sh.CatchUp(context.TODO()))
+
+	var count int64
+	assert.NoError(t, gormDB.Model(&database.ScheduleRuleRun{}).Where("schedule_rule_id = ?", rule.ID).Count(&count).Error)
+	assert.Zero(t, count)
+
+	var stored database.ScheduleRule
+	assert.NoError(t, gormDB.First(&stored, rule.ID).Error)
+	assert.Equal(t, lastRun, stored.LastRunAt.UTC())
+}
+
+func TestMissedRuns(t *testing.T) {
+	lastRun := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+	monthly := database.ScheduleRule{CronExpression: "0 0 1 * *", LastRunAt: &lastRun}
+
+	cases := []struct {
+		name     string
+		policy   rules.CatchUpPolicy
+		maxRuns  int
+		rule     database.ScheduleRule
+		expected []time.Time
+	}{
+		{
+			name:   "all",
+			policy: rules.CatchUpPolicyAll,
+			rule:   monthly,
+			expected: []time.Time{
+				time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			maxRuns: 10,
+		},
+		{
+			name:    "all capped keeps most recent",
+			policy:  rules.CatchUpPolicyAll,
+			maxRuns: 2,
+			rule:    monthly,
+			expected: []time.Time{
+				time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "latest",
+			policy:   rules.CatchUpPolicyLatest,
+			rule:     monthly,
+			expected: []time.Time{time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:   "none",
+			policy: rules.CatchUpPolicyNone,
+			rule:   monthly,
+		},
+		{
+			name:   "never ran",
+			policy: rules.CatchUpPolicyAll,
+			rule:   database.ScheduleRule{CronExpression: "0 0 1 * *"},
+		},
+		{
+			name:   "invalid cron",
+			policy: rules.CatchUpPolicyAll,
+			rule:   database.ScheduleRule{CronExpression: "x", LastRunAt: &lastRun},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			sh := rules.NewScheduler(&rules.SchedulerConfig{
+				CatchUpPolicy:  c.policy,
+				MaxCatchUpRuns: c.maxRuns,
+			})
+
+			assert.Equal(t, c.expected, sh.MissedRuns(c.rule, now))
+		})
+	}
+}
+
+func TestMissedRuns_Location(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	require.NoError(t, err)
+
+	lastRun := time.Date(2026, 2, 28, 22, 0, 0, 0, time.UTC) // 2026-03-01 00:00 in Kyiv
+	now := time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	sh := rules.NewScheduler(&rules.SchedulerConfig{
+		CatchUpPolicy:  rules.CatchUpPolicyAll,
+		MaxCatchUpRuns: 10,
+		Location:       kyiv,
+	})
+
+	assert.Equal(t, []time.Time{
+		time.Date(2026, 3, 31, 21, 0, 0, 0, time.UTC), // 2026-04-01 00:00 in Kyiv, summer time
+	}, sh.MissedRuns(database.ScheduleRule{CronExpression: "0 0 1 * *", LastRunAt: &lastRun}, now))
+}
+
+func TestExecuteTask_LocalDate(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	rule := &database.ScheduleRule{Script: "script", Title: "rent", CronExpression: "0 0 1 * *", Enabled: true}
+	assert.NoError(t, gormDB.Create(rule).Error)
+
+	ruleInt := NewMockInterpreter(gomock.NewController(t))
+	txSvc := NewMockTransactionSvc(gomock.NewController(t))
+
+	effectiveDate := time.Date(2026, 3, 2, 3, 30, 0, 0, time.UTC) // 2026-03-01 22:30 in New York
+
+	ruleInt.EXPECT().Run(gomock.Any(), "script", gomock.Any()).Return(true, nil)
+	txSvc.EXPECT().UpsertRawTransactions(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, created []*database.Transaction, _ []*database.Transaction) ([]*transactionsv1.CreateTransactionResponse, error) {
+			require.Len(t, created, 1)
+			assert.Equal(t, effectiveDate, created[0].TransactionDateTime)
+			assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), created[0].TransactionDateOnly)
+
+			return nil, nil
+		})
+
+	sh := rules.NewScheduler(&rules.SchedulerConfig{
+		RuleInterpreter: ruleInt,
+		TransactionSvc:  txSvc,
+		Location:        newYork,
+	})
+
+	_, err = sh.RunNow(context.TODO(), rule.ID, effectiveDate)
+	assert.NoError(t, err)
+}
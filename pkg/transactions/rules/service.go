@@ -2,22 +2,30 @@ package rules
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	importv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/import/v1"
 	rulesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/rules/v1"
 	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/cockroachdb/errors"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
 )
 
 type Service struct {
-	mapper MapperSvc
+	mapper     MapperSvc
+	testRunner TestCaseRunnerSvc
 }
 
 func NewService(
 	mapper MapperSvc,
+	testRunner TestCaseRunnerSvc,
 ) *Service {
 	return &Service{
-		mapper: mapper,
+		mapper:     mapper,
+		testRunner: testRunner,
 	}
 }
 
@@ -63,7 +71,13 @@ func (s *Service) CreateRule(ctx context.Context, req *rulesv1.CreateRuleRequest
 	newRule.CreatedAt = time.Now().UTC()
 	newRule.UpdatedAt = time.Now().UTC()
 
-	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).Create(newRule).Error; err != nil {
+	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(newRule).Error; err != nil {
+			return err
+		}
+
+		return s.createRevision(ctx, tx, newRule)
+	}); err != nil {
 		return nil, err
 	}
 
@@ -104,7 +118,19 @@ func (s *Service) UpdateRule(ctx context.Context, req *rulesv1.UpdateRuleRequest
 
 	updatedRule.UpdatedAt = time.Now().UTC()
 
-	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).Save(updatedRule).Error; err != nil {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var existing database.Rule
+	if err := db.Where("id = ?", updatedRule.ID).First(&existing).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get rule")
+	}
+
+	// not part of the api model, keep stored values
+	updatedRule.CreatedAt = existing.CreatedAt
+	updatedRule.TriggerEvents = existing.TriggerEvents
+	updatedRule.ImportSources = existing.ImportSources
+
+	if err := s.saveRule(ctx, db, updatedRule); err != nil {
 		return nil, err
 	}
 
@@ -112,3 +138,205 @@ func (s *Service) UpdateRule(ctx context.Context, req *rulesv1.UpdateRuleRequest
 		Rule: s.mapper.MapRule(updatedRule),
 	}, nil
 }
+
+// SetTriggers replaces trigger settings of a rule. Empty events restore the default (created, updated, imported).
+func (s *Service) SetTriggers(
+	ctx context.Context,
+	ruleID int32,
+	events []database.RuleTriggerEvent,
+	importSources []importv1.ImportSource,
+) (*database.Rule, error) {
+	for _, event := range events {
+		if TriggerEventName(event) == "" {
+			return nil, errors.Newf("invalid trigger event: %d", event)
+		}
+	}
+
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var rule database.Rule
+	if err := db.Where("id = ?", ruleID).First(&rule).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get rule")
+	}
+
+	rule.TriggerEvents = lo.Map(events, func(e database.RuleTriggerEvent, _ int) int32 {
+		return int32(e)
+	})
+	rule.ImportSources = lo.Map(importSources, func(src importv1.ImportSource, _ int) int32 {
+		return int32(src)
+	})
+	rule.UpdatedAt = time.Now().UTC()
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&rule).Select("trigger_events", "import_sources", "updated_at").
+			Updates(&rule).Error; err != nil {
+			return errors.Wrap(err, "failed to update rule triggers")
+		}
+
+		return s.createRevision(ctx, tx, &rule)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (s *Service) ListTestCases(ctx context.Context, ruleID int32) ([]*database.RuleTestCase, error) {
+	testCases, err := s.getTestCases(database.GetDbWithContext(ctx, database.DbTypeMaster), []int32{ruleID})
+	if err != nil {
+		return nil, err
+	}
+
+	return testCases[ruleID], nil
+}
+
+// SetTestCases replaces stored test cases of a rule. Cases must pass against the current script.
+func (s *Service) SetTestCases(
+	ctx context.Context,
+	ruleID int32,
+	testCases []*database.RuleTestCase,
+) ([]*TestCaseResult, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var rule database.Rule
+	if err := db.Where("id = ?", ruleID).First(&rule).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get rule")
+	}
+
+	now := time.Now().UTC()
+	for _, testCase := range testCases {
+		testCase.ID = 0
+		testCase.RuleID = ruleID
+		testCase.CreatedAt = now
+		testCase.UpdatedAt = now
+	}
+
+	results, err := s.ensureTestsPass(ctx, rule.Script, testCases)
+	if err != nil {
+		return results, err
+	}
+
+	if err = db.Transaction(func(tx *gorm.DB) error {
+		if err = tx.Where("rule_id = ?", ruleID).Delete(&database.RuleTestCase{}).Error; err != nil {
+			return errors.Wrap(err, "failed to delete existing test cases")
+		}
+
+		if len(testCases) == 0 {
+			return nil
+		}
+
+		return tx.Create(&testCases).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// RunTests executes stored test cases for the given rules, or for every rule when ruleIDs is empty.
+// Rules without test cases are skipped.
+func (s *Service) RunTests(ctx context.Context, ruleIDs []int32) ([]*RuleTestReport, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var rules []*database.Rule
+
+	query := db.Order("sort_order")
+	if len(ruleIDs) > 0 {
+		query = query.Where("id IN ?", ruleIDs)
+	}
+
+	if err := query.Find(&rules).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get rules")
+	}
+
+	testCases, err := s.getTestCases(db, lo.Map(rules, func(r *database.Rule, _ int) int32 {
+		return r.ID
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*RuleTestReport
+	for _, rule := range rules {
+		cases := testCases[rule.ID]
+		if len(cases) == 0 {
+			continue
+		}
+
+		results, runErr := s.testRunner.Run(ctx, rule.Script, cases)
+		if runErr != nil {
+			return nil, errors.Wrapf(runErr, "failed to run tests for rule %d", rule.ID)
+		}
+
+		reports = append(reports, &RuleTestReport{
+			RuleID:    rule.ID,
+			RuleTitle: rule.Title,
+			Passed:    lo.EveryBy(results, func(r *TestCaseResult) bool { return r.Passed }),
+			Results:   results,
+		})
+	}
+
+	return reports, nil
+}
+
+// saveRule runs stored test cases against the rule and persists it together with a new revision.
+func (s *Service) saveRule(ctx context.Context, db *gorm.DB, rule *database.Rule) error {
+	testCases, err := s.getTestCases(db, []int32{rule.ID})
+	if err != nil {
+		return err
+	}
+
+	if _, err = s.ensureTestsPass(ctx, rule.Script, testCases[rule.ID]); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err = tx.Save(rule).Error; err != nil {
+			return err
+		}
+
+		return s.createRevision(ctx, tx, rule)
+	})
+}
+
+func (s *Service) ensureTestsPass(
+	ctx context.Context,
+	script string,
+	testCases []*database.RuleTestCase,
+) ([]*TestCaseResult, error) {
+	if len(testCases) == 0 {
+		return nil, nil
+	}
+
+	results, err := s.testRunner.Run(ctx, script, testCases)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run rule test cases")
+	}
+
+	failed := lo.Filter(results, func(r *TestCaseResult, _ int) bool {
+		return !r.Passed
+	})
+	if len(failed) > 0 {
+		return results, errors.Wrapf(ErrRuleTestsFailed, "%d of %d failed: %s",
+			len(failed), len(results), strings.Join(lo.Map(failed, func(r *TestCaseResult, _ int) string {
+				return r.Name
+			}), ", "))
+	}
+
+	return results, nil
+}
+
+func (s *Service) getTestCases(db *gorm.DB, ruleIDs []int32) (map[int32][]*database.RuleTestCase, error) {
+	if len(ruleIDs) == 0 {
+		return map[int32][]*database.RuleTestCase{}, nil
+	}
+
+	var testCases []*database.RuleTestCase
+	if err := db.Where("rule_id IN ?", ruleIDs).Order("id").Find(&testCases).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get rule test cases")
+	}
+
+	return lo.GroupBy(testCases, func(c *database.RuleTestCase) int32 {
+		return c.RuleID
+	}), nil
+}
@@ -0,0 +1,160 @@
+package rules
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	libs "github.com/vadv/gopher-lua-libs"
+	"github.com/yuin/gopher-lua"
+)
+
+var moduleNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+// preloadedModules are provided by libs.Preload and always win over stored modules in require().
+var preloadedModules = sync.OnceValue(func() map[string]struct{} {
+	state := lua.NewState()
+	defer state.Close()
+	libs.Preload(state)
+
+	names := map[string]struct{}{}
+	preload := state.GetField(state.GetField(state.Get(lua.EnvironIndex), "package"), "preload")
+	if tbl, ok := preload.(*lua.LTable); ok {
+		tbl.ForEach(func(key lua.LValue, _ lua.LValue) {
+			names[key.String()] = struct{}{}
+		})
+	}
+
+	return names
+})
+
+type ModuleService struct {
+	cache *expirable.LRU[string, *database.LuaModule]
+}
+
+func NewModuleService() *ModuleService {
+	return &ModuleService{
+		cache: expirable.NewLRU[string, *database.LuaModule](100, nil, configuration.DefaultCacheTTL),
+	}
+}
+
+func (s *ModuleService) ListModules(ctx context.Context) ([]*database.LuaModule, error) {
+	var modules []*database.LuaModule
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).
+		Order("name").Find(&modules).Error; err != nil {
+		return nil, err
+	}
+
+	return modules, nil
+}
+
+// GetModuleByName returns nil when the module does not exist. Results are cached,
+// writes through this service invalidate the cache.
+func (s *ModuleService) GetModuleByName(ctx context.Context, name string) (*database.LuaModule, error) {
+	if module, ok := s.cache.Get(name); ok {
+		return module, nil
+	}
+
+	var modules []*database.LuaModule
+	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).
+		Where("name = ?", name).Limit(1).Find(&modules).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get lua module")
+	}
+
+	var module *database.LuaModule
+	if len(modules) > 0 {
+		module = modules[0]
+	}
+
+	s.cache.Add(name, module)
+
+	return module, nil
+}
+
+func (s *ModuleService) CreateModule(ctx context.Context, module *database.LuaModule) (*database.LuaModule, error) {
+	if err := s.validate(module); err != nil {
+		return nil, err
+	}
+
+	module.ID = 0
+	module.CreatedAt = time.Now().UTC()
+	module.UpdatedAt = module.CreatedAt
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).Create(module).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to create lua module")
+	}
+
+	s.cache.Purge()
+
+	return module, nil
+}
+
+func (s *ModuleService) UpdateModule(ctx context.Context, module *database.LuaModule) (*database.LuaModule, error) {
+	if err := s.validate(module); err != nil {
+		return nil, err
+	}
+
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var existing database.LuaModule
+	if err := db.Where("id = ?", module.ID).First(&existing).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get lua module")
+	}
+
+	existing.Name = module.Name
+	existing.Description = module.Description
+	existing.Script = module.Script
+	existing.UpdatedAt = time.Now().UTC()
+
+	if err := db.Save(&existing).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to update lua module")
+	}
+
+	s.cache.Purge()
+
+	return &existing, nil
+}
+
+func (s *ModuleService) DeleteModule(ctx context.Context, id int32) (*database.LuaModule, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var module database.LuaModule
+	if err := db.Where("id = ?", id).First(&module).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get lua module")
+	}
+
+	if err := db.Delete(&module).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to delete lua module")
+	}
+
+	s.cache.Purge()
+
+	return &module, nil
+}
+
+func (s *ModuleService) validate(module *database.LuaModule) error {
+	if !moduleNameRegex.MatchString(module.Name) {
+		return errors.Newf("invalid module name %q, expected lowercase identifiers separated by dots", module.Name)
+	}
+
+	if _, ok := preloadedModules()[module.Name]; ok {
+		return errors.Newf("module name %q is reserved by a built-in library", module.Name)
+	}
+
+	if strings.TrimSpace(module.Script) == "" {
+		return errors.New("module script is required")
+	}
+
+	if _, err := compileLua(module.Name, module.Script); err != nil {
+		return errors.Wrap(err, "failed to compile module")
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package rules_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModuleService(t *testing.T) {
+	t.Run("crud", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		svc := rules.NewModuleService()
+
+		missing, err := svc.GetModuleByName(context.TODO(), "merchants")
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+
+		created, err := svc.CreateModule(context.TODO(), &database.LuaModule{
+			Name:   "merchants",
+			Script: merchantsModule,
+		})
+		assert.NoError(t, err)
+		assert.NotZero(t, created.ID)
+
+		found, err := svc.GetModuleByName(context.TODO(), "merchants")
+		assert.NoError(t, err)
+		assert.Equal(t, created.ID, found.ID)
+
+		updated, err := svc.UpdateModule(context.TODO(), &database.LuaModule{
+			ID:          created.ID,
+			Name:        "merchants",
+			Description: "merchant helpers",
+			Script:      "return {}",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "merchant helpers", updated.Description)
+
+		found, err = svc.GetModuleByName(context.TODO(), "merchants")
+		assert.NoError(t, err)
+		assert.Equal(t, "return {}", found.Script)
+
+		modules, err := svc.ListModules(context.TODO())
+		assert.NoError(t, err)
+		assert.Len(t, modules, 1)
+
+		_, err = svc.DeleteModule(context.TODO(), created.ID)
+		assert.NoError(t, err)
+
+		found, err = svc.GetModuleByName(context.TODO(), "merchants")
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+
+		recreated, err := svc.CreateModule(context.TODO(), &database.LuaModule{
+			Name:   "merchants",
+			Script: "return {}",
+		})
+		assert.NoError(t, err)
+		assert.NotEqual(t, created.ID, recreated.ID)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		svc := rules.NewModuleService()
+
+		_, err := svc.CreateModule(context.TODO(), &database.LuaModule{Name: "merchants", Script: "return {}"})
+		assert.NoError(t, err)
+
+		_, err = svc.CreateModule(context.TODO(), &database.LuaModule{Name: "merchants", Script: "return {}"})
+		assert.ErrorContains(t, err, "failed to create lua module")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		cases := []struct {
+			name     string
+			module   *database.LuaModule
+			expected string
+		}{
+			{"invalid name", &database.LuaModule{Name: "Merchants", Script: "return {}"}, "invalid module name"},
+			{"reserved name", &database.LuaModule{Name: "json", Script: "return {}"}, "reserved by a built-in library"},
+			{"empty script", &database.LuaModule{Name: "merchants", Script: " "}, "module script is required"},
+			{"syntax error", &database.LuaModule{Name: "merchants", Script: "return {"}, "failed to compile module"},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				_, err := rules.NewModuleService().CreateModule(context.TODO(), c.module)
+				assert.ErrorContains(t, err, c.expected)
+			})
+		}
+	})
+
+	t.Run("update missing", func(t *testing.T) {
+		_, err := rules.NewModuleService().UpdateModule(context.TODO(), &database.LuaModule{
+			ID:     999999,
+			Name:   "merchants",
+			Script: "return {}",
+		})
+		assert.ErrorContains(t, err, "failed to get lua module")
+	})
+}
@@ -0,0 +1,190 @@
+package rules
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	importv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/import/v1"
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// createRevision stores an immutable copy of rule and points the rule at it.
+func (s *Service) createRevision(ctx context.Context, tx *gorm.DB, rule *database.Rule) error {
+	rev := &database.RuleRevision{
+		RuleID:          rule.ID,
+		Title:           rule.Title,
+		Script:          rule.Script,
+		InterpreterType: rule.InterpreterType,
+		SortOrder:       rule.SortOrder,
+		Enabled:         rule.Enabled,
+		IsFinalRule:     rule.IsFinalRule,
+		GroupName:       rule.GroupName,
+		TriggerEvents:   rule.TriggerEvents,
+		ImportSources:   rule.ImportSources,
+		CreatedAt:       time.Now().UTC(),
+	}
+
+	if actor, ok := history.ActorFromContext(ctx); ok {
+		rev.AuthorType = &actor.Type
+		rev.AuthorUserID = actor.UserID
+
+		if actor.Detail != "" {
+			rev.AuthorDetail = &actor.Detail
+		}
+	}
+
+	if err := tx.Create(rev).Error; err != nil {
+		return errors.Wrap(err, "failed to create rule revision")
+	}
+
+	rule.RevisionID = &rev.ID
+
+	if err := tx.Model(&database.Rule{}).Where("id = ?", rule.ID).
+		UpdateColumn("revision_id", rev.ID).Error; err != nil {
+		return errors.Wrap(err, "failed to update rule revision")
+	}
+
+	return nil
+}
+
+func (s *Service) ListRevisions(ctx context.Context, ruleID int32) ([]*database.RuleRevision, error) {
+	var revisions []*database.RuleRevision
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).
+		Where("rule_id = ?", ruleID).Order("id desc").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// DiffRevisions compares two revisions of the same rule, script changes are returned as a unified diff.
+func (s *Service) DiffRevisions(ctx context.Context, fromID int64, toID int64) (*RevisionDiff, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	from, err := s.getRevision(db, fromID)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := s.getRevision(db, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.RuleID != to.RuleID {
+		return nil, errors.Newf("revisions %d and %d belong to different rules", fromID, toID)
+	}
+
+	diff := &RevisionDiff{
+		RuleID:         to.RuleID,
+		FromRevisionID: from.ID,
+		ToRevisionID:   to.ID,
+	}
+
+	fromFields := revisionFields(from)
+	toFields := revisionFields(to)
+
+	for _, field := range revisionFieldOrder {
+		if !reflect.DeepEqual(fromFields[field], toFields[field]) {
+			diff.Changes = append(diff.Changes, &FieldChange{
+				Field: field,
+				From:  fromFields[field],
+				To:    toFields[field],
+			})
+		}
+	}
+
+	if from.Script != to.Script {
+		diff.ScriptDiff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(from.Script),
+			B:        difflib.SplitLines(to.Script),
+			FromFile: fmt.Sprintf("revision %d", from.ID),
+			ToFile:   fmt.Sprintf("revision %d", to.ID),
+			Context:  3,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to diff scripts")
+		}
+	}
+
+	return diff, nil
+}
+
+// RestoreRevision copies revision state back into the rule. Stored test cases must pass,
+// and the restore itself is recorded as a new revision.
+func (s *Service) RestoreRevision(ctx context.Context, revisionID int64) (*database.Rule, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	rev, err := s.getRevision(db, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rule database.Rule
+	if err = db.Where("id = ?", rev.RuleID).First(&rule).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to get rule")
+	}
+
+	rule.Title = rev.Title
+	rule.Script = rev.Script
+	rule.InterpreterType = rev.InterpreterType
+	rule.SortOrder = rev.SortOrder
+	rule.Enabled = rev.Enabled
+	rule.IsFinalRule = rev.IsFinalRule
+	rule.GroupName = rev.GroupName
+	rule.TriggerEvents = rev.TriggerEvents
+	rule.ImportSources = rev.ImportSources
+	rule.UpdatedAt = time.Now().UTC()
+
+	if err = s.saveRule(ctx, db, &rule); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (s *Service) getRevision(db *gorm.DB, id int64) (*database.RuleRevision, error) {
+	var rev database.RuleRevision
+
+	if err := db.Where("id = ?", id).First(&rev).Error; err != nil {
+		return nil, errors.Wrapf(err, "failed to get rule revision %d", id)
+	}
+
+	return &rev, nil
+}
+
+var revisionFieldOrder = []string{
+	"title",
+	"interpreter_type",
+	"sort_order",
+	"enabled",
+	"is_final_rule",
+	"group_name",
+	"trigger_events",
+	"import_sources",
+}
+
+func revisionFields(rev *database.RuleRevision) map[string]any {
+	return map[string]any{
+		"title":            rev.Title,
+		"interpreter_type": rev.InterpreterType.String(),
+		"sort_order":       rev.SortOrder,
+		"enabled":          rev.Enabled,
+		"is_final_rule":    rev.IsFinalRule,
+		"group_name":       rev.GroupName,
+		"trigger_events": lo.Map(rev.TriggerEvents, func(e int32, _ int) string {
+			return TriggerEventName(database.RuleTriggerEvent(e))
+		}),
+		"import_sources": lo.Map(rev.ImportSources, func(src int32, _ int) string {
+			return importv1.ImportSource(src).String()
+		}),
+	}
+}
@@ -111,15 +111,29 @@ func (s *ScheduleService) UpdateRule(
 	ctx context.Context,
 	req *rulesv1.UpdateScheduleRuleRequest,
 ) (*rulesv1.UpdateScheduleRuleResponse, error) {
-	updatedRule := s.mapRule(req.Rule)
+	if err := s.scheduler.ValidateCronExpression(req.Rule.CronExpression); err != nil {
+		return nil, err
+	}
 
-	updatedRule.UpdatedAt = time.Now().UTC()
+	db := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeMaster))
 
-	if err := s.scheduler.ValidateCronExpression(updatedRule.CronExpression); err != nil {
+	var updatedRule database.ScheduleRule
+	if err := db.Where("id = ?", req.Rule.Id).First(&updatedRule).Error; err != nil {
 		return nil, err
 	}
 
-	if err := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeMaster)).Save(updatedRule).Error; err != nil {
+	// only editable fields are copied, created_at and last_run_at stay as stored so catch-up keeps working
+	edited := s.mapRule(req.Rule)
+
+	updatedRule.Title = edited.Title
+	updatedRule.Script = edited.Script
+	updatedRule.InterpreterType = edited.InterpreterType
+	updatedRule.Enabled = edited.Enabled
+	updatedRule.GroupName = edited.GroupName
+	updatedRule.CronExpression = edited.CronExpression
+	updatedRule.UpdatedAt = time.Now().UTC()
+
+	if err := db.Save(&updatedRule).Error; err != nil {
 		return nil, err
 	}
 
@@ -128,10 +142,42 @@ func (s *ScheduleService) UpdateRule(
 	}
 
 	return &rulesv1.UpdateScheduleRuleResponse{
-		Rule: s.mapper.MapScheduleRule(updatedRule),
+		Rule: s.mapper.MapScheduleRule(&updatedRule),
 	}, nil
 }
 
+// RunNow executes the schedule rule immediately with the given effective date, zero means now.
+func (s *ScheduleService) RunNow(
+	ctx context.Context,
+	ruleID int32,
+	effectiveDate time.Time,
+) (*database.ScheduleRuleRun, error) {
+	return s.scheduler.RunNow(ctx, ruleID, effectiveDate)
+}
+
+// ListRuns returns the latest runs of a schedule rule, newest first.
+func (s *ScheduleService) ListRuns(
+	ctx context.Context,
+	ruleID int32,
+	limit int,
+) ([]*database.ScheduleRuleRun, error) {
+	var runs []*database.ScheduleRuleRun
+
+	if limit <= 0 {
+		limit = 50
+	}
+
+	if err := database.FromContext(ctx, database.GetDbWithContext(ctx, database.DbTypeMaster)).
+		Where("schedule_rule_id = ?", ruleID).
+		Order("id desc").
+		Limit(limit).
+		Find(&runs).Error; err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
 func (s *ScheduleService) mapRule(rule *gomoneypbv1.ScheduleRule) *database.ScheduleRule {
 	mapped := &database.ScheduleRule{
 		ID:              rule.Id,
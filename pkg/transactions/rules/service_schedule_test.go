@@ -10,7 +10,9 @@ import (
 	"github.com/ft-t/go-money/pkg/transactions/rules"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestScheduleService_Create(t *testing.T) {
@@ -165,6 +167,63 @@ func TestScheduleService_UpdateRule(t *testing.T) {
 		assert.Equal(t, "new", updated.Script)
 	})
 
+	t.Run("keeps created_at and last_run_at", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		mapper := NewMockMapperSvc(gomock.NewController(t))
+		scheduler := NewMockSchedulerSvc(gomock.NewController(t))
+
+		mapper.EXPECT().MapScheduleRule(gomock.Any()).Return(&gomoneypbv1.ScheduleRule{})
+		scheduler.EXPECT().ValidateCronExpression(gomock.Any()).Return(nil)
+		scheduler.EXPECT().Reinit(gomock.Any()).Return(nil)
+
+		createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		lastRun := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		rule := &database.ScheduleRule{
+			Title:          "old",
+			Script:         "old",
+			CronExpression: "0 0 1 * *",
+			Enabled:        true,
+			CreatedAt:      createdAt,
+			LastRunAt:      &lastRun,
+		}
+		assert.NoError(t, gormDB.Create(rule).Error)
+
+		svc := rules.NewScheduleService(mapper, scheduler)
+		_, err := svc.UpdateRule(context.TODO(), &rulesv1.UpdateScheduleRuleRequest{
+			Rule: &gomoneypbv1.ScheduleRule{
+				Id:             rule.ID,
+				Title:          "new",
+				Script:         "new",
+				CronExpression: "0 0 2 * *",
+				Enabled:        true,
+			},
+		})
+		assert.NoError(t, err)
+
+		var updated database.ScheduleRule
+		assert.NoError(t, gormDB.First(&updated, rule.ID).Error)
+		assert.Equal(t, "0 0 2 * *", updated.CronExpression)
+		assert.Equal(t, createdAt, updated.CreatedAt.UTC())
+		require.NotNil(t, updated.LastRunAt)
+		assert.Equal(t, lastRun, updated.LastRunAt.UTC())
+		assert.NotEmpty(t, rules.NewScheduler(&rules.SchedulerConfig{CatchUpPolicy: rules.CatchUpPolicyAll}).
+			MissedRuns(updated, time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("rule not found", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		scheduler := NewMockSchedulerSvc(gomock.NewController(t))
+		scheduler.EXPECT().ValidateCronExpression(gomock.Any()).Return(nil)
+
+		svc := rules.NewScheduleService(NewMockMapperSvc(gomock.NewController(t)), scheduler)
+		_, err := svc.UpdateRule(context.TODO(), &rulesv1.UpdateScheduleRuleRequest{
+			Rule: &gomoneypbv1.ScheduleRule{Id: 999999, Title: "new", Script: "new"},
+		})
+		assert.Error(t, err)
+	})
+
 	t.Run("reinit error", func(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
@@ -317,3 +376,42 @@ func TestDelete(t *testing.T) {
 		assert.ErrorContains(t, err, "all expectations were already fulfilled")
 	})
 }
+
+func TestScheduleService_RunNow(t *testing.T) {
+	scheduler := NewMockSchedulerSvc(gomock.NewController(t))
+	effectiveDate := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	scheduler.EXPECT().RunNow(gomock.Any(), int32(5), effectiveDate).
+		Return(&database.ScheduleRuleRun{ID: 1, ScheduleRuleID: 5}, nil)
+
+	run, err := rules.NewScheduleService(nil, scheduler).RunNow(context.TODO(), 5, effectiveDate)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, run.ScheduleRuleID)
+}
+
+func TestScheduleService_ListRuns(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, gormDB.Create(&database.ScheduleRuleRun{
+			ScheduleRuleID: 5,
+			Trigger:        database.ScheduleRunTriggerCron,
+			Status:         database.ScheduleRunStatusSuccess,
+			EffectiveDate:  time.Now().UTC(),
+			StartedAt:      time.Now().UTC(),
+		}).Error)
+	}
+
+	assert.NoError(t, gormDB.Create(&database.ScheduleRuleRun{
+		ScheduleRuleID: 6,
+		Trigger:        database.ScheduleRunTriggerCron,
+		Status:         database.ScheduleRunStatusSuccess,
+		EffectiveDate:  time.Now().UTC(),
+		StartedAt:      time.Now().UTC(),
+	}).Error)
+
+	runs, err := rules.NewScheduleService(nil, nil).ListRuns(context.TODO(), 5, 2)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.Greater(t, runs[0].ID, runs[1].ID)
+}
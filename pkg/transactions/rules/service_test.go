@@ -1,11 +1,13 @@
 package rules_test
 
 import (
+	importv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/import/v1"
 	rulesv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/rules/v1"
 	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
 	"context"
 	"github.com/ft-t/go-money/pkg/database"
 	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions/history"
 	"github.com/ft-t/go-money/pkg/transactions/rules"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -25,7 +27,7 @@ func TestCreateAndDelete(t *testing.T) {
 			}
 		}).Times(2)
 
-	svc := rules.NewService(mapper)
+	svc := rules.NewService(mapper, nil)
 
 	resp, err := svc.CreateRule(context.TODO(), &rulesv1.CreateRuleRequest{
 		Rule: &gomoneypbv1.Rule{
@@ -79,7 +81,7 @@ func TestUpdateRule(t *testing.T) {
 	}
 	assert.NoError(t, gormDB.Create(existing).Error)
 
-	svc := rules.NewService(mapper)
+	svc := rules.NewService(mapper, nil)
 	resp, err := svc.UpdateRule(context.TODO(), &rulesv1.UpdateRuleRequest{
 		Rule: &gomoneypbv1.Rule{
 			Id:     existing.ID,
@@ -109,7 +111,7 @@ func TestListRule(t *testing.T) {
 				}
 			}).Times(3)
 
-		svc := rules.NewService(mapper)
+		svc := rules.NewService(mapper, nil)
 
 		dbRules := []*database.Rule{
 			{ID: 1, Title: "Rule 1"},
@@ -135,7 +137,7 @@ func TestListRule(t *testing.T) {
 				}
 			}).Times(1)
 
-		svc := rules.NewService(mapper)
+		svc := rules.NewService(mapper, nil)
 
 		dbRules := []*database.Rule{
 			{ID: 1, Title: "Rule 1"},
@@ -157,3 +159,316 @@ func TestListRule(t *testing.T) {
 		assert.EqualValues(t, 2, resp.Rules[0].Id)
 	})
 }
+
+func TestUpdateRuleWithTestCases(t *testing.T) {
+	t.Run("rejects regression", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		existing := &database.Rule{Script: "existing-script", Title: "existing-title"}
+		assert.NoError(t, gormDB.Create(existing).Error)
+
+		testCase := &database.RuleTestCase{
+			RuleID:   existing.ID,
+			Name:     "case-1",
+			Input:    map[string]any{"title": "a"},
+			Expected: map[string]any{"title": "b"},
+		}
+		assert.NoError(t, gormDB.Create(testCase).Error)
+
+		runner := NewMockTestCaseRunnerSvc(gomock.NewController(t))
+		runner.EXPECT().Run(gomock.Any(), "updated-script", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, cases []*database.RuleTestCase) ([]*rules.TestCaseResult, error) {
+				assert.Len(t, cases, 1)
+				return []*rules.TestCaseResult{{CaseID: cases[0].ID, Name: cases[0].Name}}, nil
+			})
+
+		svc := rules.NewService(NewMockMapperSvc(gomock.NewController(t)), runner)
+		_, err := svc.UpdateRule(context.TODO(), &rulesv1.UpdateRuleRequest{
+			Rule: &gomoneypbv1.Rule{Id: existing.ID, Title: "updated-title", Script: "updated-script"},
+		})
+		assert.ErrorIs(t, err, rules.ErrRuleTestsFailed)
+		assert.ErrorContains(t, err, "case-1")
+
+		var stored database.Rule
+		assert.NoError(t, gormDB.Find(&stored, existing.ID).Error)
+		assert.Equal(t, "existing-script", stored.Script)
+	})
+
+	t.Run("saves when tests pass", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		existing := &database.Rule{Script: "existing-script", Title: "existing-title"}
+		assert.NoError(t, gormDB.Create(existing).Error)
+		assert.NoError(t, gormDB.Create(&database.RuleTestCase{
+			RuleID:   existing.ID,
+			Name:     "case-1",
+			Expected: map[string]any{"title": "b"},
+		}).Error)
+
+		runner := NewMockTestCaseRunnerSvc(gomock.NewController(t))
+		runner.EXPECT().Run(gomock.Any(), "updated-script", gomock.Any()).
+			Return([]*rules.TestCaseResult{{Name: "case-1", Passed: true}}, nil)
+
+		mapper := NewMockMapperSvc(gomock.NewController(t))
+		mapper.EXPECT().MapRule(gomock.Any()).Return(&gomoneypbv1.Rule{Id: existing.ID})
+
+		svc := rules.NewService(mapper, runner)
+		_, err := svc.UpdateRule(context.TODO(), &rulesv1.UpdateRuleRequest{
+			Rule: &gomoneypbv1.Rule{Id: existing.ID, Title: "updated-title", Script: "updated-script"},
+		})
+		assert.NoError(t, err)
+
+		var stored database.Rule
+		assert.NoError(t, gormDB.Find(&stored, existing.ID).Error)
+		assert.Equal(t, "updated-script", stored.Script)
+	})
+}
+
+func TestSetTestCases(t *testing.T) {
+	t.Run("replaces existing cases", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		existing := &database.Rule{Script: "script", Title: "title"}
+		assert.NoError(t, gormDB.Create(existing).Error)
+		assert.NoError(t, gormDB.Create(&database.RuleTestCase{
+			RuleID:   existing.ID,
+			Name:     "old",
+			Expected: map[string]any{"title": "b"},
+		}).Error)
+
+		runner := NewMockTestCaseRunnerSvc(gomock.NewController(t))
+		runner.EXPECT().Run(gomock.Any(), "script", gomock.Any()).
+			Return([]*rules.TestCaseResult{{Name: "new", Passed: true}}, nil)
+
+		svc := rules.NewService(nil, runner)
+		results, err := svc.SetTestCases(context.TODO(), existing.ID, []*database.RuleTestCase{
+			{Name: "new", Input: map[string]any{"title": "a"}, Expected: map[string]any{"title": "a"}},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+
+		stored, err := svc.ListTestCases(context.TODO(), existing.ID)
+		assert.NoError(t, err)
+		assert.Len(t, stored, 1)
+		assert.Equal(t, "new", stored[0].Name)
+		assert.Equal(t, "a", stored[0].Expected["title"])
+	})
+
+	t.Run("failing cases are not saved", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		existing := &database.Rule{Script: "script", Title: "title"}
+		assert.NoError(t, gormDB.Create(existing).Error)
+
+		runner := NewMockTestCaseRunnerSvc(gomock.NewController(t))
+		runner.EXPECT().Run(gomock.Any(), "script", gomock.Any()).
+			Return([]*rules.TestCaseResult{{Name: "new", Passed: false}}, nil)
+
+		svc := rules.NewService(nil, runner)
+		results, err := svc.SetTestCases(context.TODO(), existing.ID, []*database.RuleTestCase{
+			{Name: "new", Expected: map[string]any{"title": "a"}},
+		})
+		assert.ErrorIs(t, err, rules.ErrRuleTestsFailed)
+		assert.Len(t, results, 1)
+
+		stored, err := svc.ListTestCases(context.TODO(), existing.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, stored)
+	})
+
+	t.Run("rule not found", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		svc := rules.NewService(nil, nil)
+		_, err := svc.SetTestCases(context.TODO(), 999, nil)
+		assert.ErrorContains(t, err, "failed to get rule")
+	})
+}
+
+func TestRunTests(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	dbRules := []*database.Rule{
+		{Title: "with cases", Script: "script-1", SortOrder: 1},
+		{Title: "without cases", Script: "script-2", SortOrder: 2},
+	}
+	assert.NoError(t, gormDB.Create(dbRules).Error)
+	assert.NoError(t, gormDB.Create(&database.RuleTestCase{
+		RuleID:   dbRules[0].ID,
+		Name:     "case",
+		Expected: map[string]any{"title": "b"},
+	}).Error)
+
+	runner := NewMockTestCaseRunnerSvc(gomock.NewController(t))
+	runner.EXPECT().Run(gomock.Any(), "script-1", gomock.Any()).
+		Return([]*rules.TestCaseResult{{Name: "case", Passed: false}}, nil)
+
+	svc := rules.NewService(nil, runner)
+	reports, err := svc.RunTests(context.TODO(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, dbRules[0].ID, reports[0].RuleID)
+	assert.False(t, reports[0].Passed)
+}
+
+func TestSetTriggers(t *testing.T) {
+	t.Run("set and keep on update", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		existing := &database.Rule{Script: "script", Title: "title"}
+		assert.NoError(t, gormDB.Create(existing).Error)
+
+		svc := rules.NewService(nil, nil)
+		updated, err := svc.SetTriggers(context.TODO(), existing.ID,
+			[]database.RuleTriggerEvent{database.RuleTriggerEventImported},
+			[]importv1.ImportSource{importv1.ImportSource_IMPORT_SOURCE_REVOLUT},
+		)
+		assert.NoError(t, err)
+		assert.EqualValues(t, []int32{int32(database.RuleTriggerEventImported)}, updated.TriggerEvents)
+
+		mapper := NewMockMapperSvc(gomock.NewController(t))
+		mapper.EXPECT().MapRule(gomock.Any()).Return(&gomoneypbv1.Rule{Id: existing.ID})
+
+		_, err = rules.NewService(mapper, nil).UpdateRule(context.TODO(), &rulesv1.UpdateRuleRequest{
+			Rule: &gomoneypbv1.Rule{Id: existing.ID, Title: "new-title", Script: "new-script"},
+		})
+		assert.NoError(t, err)
+
+		var stored database.Rule
+		assert.NoError(t, gormDB.Find(&stored, existing.ID).Error)
+		assert.EqualValues(t, []int32{int32(database.RuleTriggerEventImported)}, stored.TriggerEvents)
+		assert.EqualValues(t, []int32{int32(importv1.ImportSource_IMPORT_SOURCE_REVOLUT)}, stored.ImportSources)
+		assert.Equal(t, "new-script", stored.Script)
+	})
+
+	t.Run("invalid event", func(t *testing.T) {
+		svc := rules.NewService(nil, nil)
+		_, err := svc.SetTriggers(context.TODO(), 1, []database.RuleTriggerEvent{99}, nil)
+		assert.ErrorContains(t, err, "invalid trigger event")
+	})
+}
+
+func TestRuleRevisions(t *testing.T) {
+	t.Run("create, update, diff and restore", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		ctx := history.WithActor(context.TODO(), history.UserActor(7))
+
+		mapper := NewMockMapperSvc(gomock.NewController(t))
+		mapper.EXPECT().MapRule(gomock.Any()).Return(&gomoneypbv1.Rule{}).Times(2)
+
+		svc := rules.NewService(mapper, nil)
+
+		created, err := svc.CreateRule(ctx, &rulesv1.CreateRuleRequest{
+			Rule: &gomoneypbv1.Rule{Title: "v1", Script: "a\nb\n"},
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, created)
+
+		var rule database.Rule
+		assert.NoError(t, gormDB.First(&rule).Error)
+		assert.NotNil(t, rule.RevisionID)
+
+		_, err = svc.UpdateRule(ctx, &rulesv1.UpdateRuleRequest{
+			Rule: &gomoneypbv1.Rule{Id: rule.ID, Title: "v2", Script: "a\nc\n", Enabled: true},
+		})
+		assert.NoError(t, err)
+
+		revisions, err := svc.ListRevisions(context.TODO(), rule.ID)
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 2)
+		assert.Equal(t, "v2", revisions[0].Title)
+		assert.Equal(t, "v1", revisions[1].Title)
+		assert.Equal(t, database.TransactionHistoryActorTypeUser, *revisions[1].AuthorType)
+		assert.EqualValues(t, 7, *revisions[1].AuthorUserID)
+
+		diff, err := svc.DiffRevisions(context.TODO(), revisions[1].ID, revisions[0].ID)
+		assert.NoError(t, err)
+		assert.Equal(t, rule.ID, diff.RuleID)
+		assert.Contains(t, diff.ScriptDiff, "-b")
+		assert.Contains(t, diff.ScriptDiff, "+c")
+
+		fields := map[string]*rules.FieldChange{}
+		for _, c := range diff.Changes {
+			fields[c.Field] = c
+		}
+		assert.Len(t, fields, 2)
+		assert.Equal(t, "v1", fields["title"].From)
+		assert.Equal(t, "v2", fields["title"].To)
+		assert.Equal(t, true, fields["enabled"].To)
+
+		restored, err := svc.RestoreRevision(context.TODO(), revisions[1].ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", restored.Title)
+		assert.Equal(t, "a\nb\n", restored.Script)
+
+		var stored database.Rule
+		assert.NoError(t, gormDB.First(&stored, rule.ID).Error)
+		assert.Equal(t, "v1", stored.Title)
+		assert.False(t, stored.Enabled)
+		assert.Equal(t, restored.RevisionID, stored.RevisionID)
+
+		revisions, err = svc.ListRevisions(context.TODO(), rule.ID)
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 3)
+		assert.Equal(t, *stored.RevisionID, revisions[0].ID)
+	})
+
+	t.Run("set triggers creates revision", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		existing := &database.Rule{Script: "script", Title: "title"}
+		assert.NoError(t, gormDB.Create(existing).Error)
+
+		svc := rules.NewService(nil, nil)
+		updated, err := svc.SetTriggers(context.TODO(), existing.ID,
+			[]database.RuleTriggerEvent{database.RuleTriggerEventManual}, nil)
+		assert.NoError(t, err)
+		assert.NotNil(t, updated.RevisionID)
+
+		revisions, err := svc.ListRevisions(context.TODO(), existing.ID)
+		assert.NoError(t, err)
+		assert.Len(t, revisions, 1)
+		assert.EqualValues(t, []int32{int32(database.RuleTriggerEventManual)}, revisions[0].TriggerEvents)
+		assert.Nil(t, revisions[0].AuthorType)
+	})
+
+	t.Run("diff across rules", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		first := &database.RuleRevision{RuleID: 1, Title: "a"}
+		second := &database.RuleRevision{RuleID: 2, Title: "b"}
+		assert.NoError(t, gormDB.Create(first).Error)
+		assert.NoError(t, gormDB.Create(second).Error)
+
+		_, err := rules.NewService(nil, nil).DiffRevisions(context.TODO(), first.ID, second.ID)
+		assert.ErrorContains(t, err, "belong to different rules")
+	})
+
+	t.Run("restore rejected by failing tests", func(t *testing.T) {
+		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		existing := &database.Rule{Script: "new", Title: "title"}
+		assert.NoError(t, gormDB.Create(existing).Error)
+
+		rev := &database.RuleRevision{RuleID: existing.ID, Title: "old", Script: "old"}
+		assert.NoError(t, gormDB.Create(rev).Error)
+		assert.NoError(t, gormDB.Create(&database.RuleTestCase{RuleID: existing.ID, Name: "case"}).Error)
+
+		runner := NewMockTestCaseRunnerSvc(gomock.NewController(t))
+		runner.EXPECT().Run(gomock.Any(), "old", gomock.Any()).
+			Return([]*rules.TestCaseResult{{Name: "case", Passed: false}}, nil)
+
+		_, err := rules.NewService(nil, runner).RestoreRevision(context.TODO(), rev.ID)
+		assert.ErrorIs(t, err, rules.ErrRuleTestsFailed)
+
+		var stored database.Rule
+		assert.NoError(t, gormDB.First(&stored, existing.ID).Error)
+		assert.Equal(t, "new", stored.Script)
+	})
+
+	t.Run("revision not found", func(t *testing.T) {
+		_, err := rules.NewService(nil, nil).RestoreRevision(context.TODO(), 999999)
+		assert.ErrorContains(t, err, "failed to get rule revision")
+	})
+}
@@ -0,0 +1,176 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/shopspring/decimal"
+)
+
+var ErrRuleTestsFailed = errors.New("rule test cases failed")
+
+type TestCaseRunner struct {
+	cfg *TestCaseRunnerConfig
+}
+
+type TestCaseRunnerConfig struct {
+	Executor       ExecutorSvc
+	TransactionSvc TransactionSvc
+}
+
+func NewTestCaseRunner(cfg *TestCaseRunnerConfig) *TestCaseRunner {
+	return &TestCaseRunner{
+		cfg: cfg,
+	}
+}
+
+// Run executes script against every case. Script errors and mismatches fail the case,
+// only infrastructure errors are returned.
+func (r *TestCaseRunner) Run(
+	ctx context.Context,
+	script string,
+	cases []*database.RuleTestCase,
+) ([]*TestCaseResult, error) {
+	results := make([]*TestCaseResult, 0, len(cases))
+
+	for _, testCase := range cases {
+		res, err := r.runSingle(ctx, script, testCase)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to run test case %q", testCase.Name)
+		}
+
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+func (r *TestCaseRunner) runSingle(
+	ctx context.Context,
+	script string,
+	testCase *database.RuleTestCase,
+) (*TestCaseResult, error) {
+	res := &TestCaseResult{
+		CaseID: testCase.ID,
+		Name:   testCase.Name,
+	}
+
+	input, err := r.buildInput(ctx, testCase)
+	if err != nil {
+		return nil, err
+	}
+
+	if input == nil {
+		res.Error = "transaction not found"
+		return res, nil
+	}
+
+	_, updated, ruleErr := r.cfg.Executor.ProcessSingleRule(ctx, input, &database.Rule{
+		ID:     testCase.RuleID,
+		Script: script,
+	})
+	if ruleErr != nil {
+		res.Error = ruleErr.Error()
+		return res, nil
+	}
+
+	actual, err := history.Snapshot(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	for field, expected := range testCase.Expected {
+		if !snapshotValuesEqual(expected, actual[field]) {
+			res.Mismatches = append(res.Mismatches, &FieldMismatch{
+				Field:    field,
+				Expected: expected,
+				Actual:   actual[field],
+			})
+		}
+	}
+
+	res.Passed = len(res.Mismatches) == 0
+
+	return res, nil
+}
+
+// buildInput loads the referenced transaction (if any) and applies Input on top of it.
+func (r *TestCaseRunner) buildInput(
+	ctx context.Context,
+	testCase *database.RuleTestCase,
+) (*database.Transaction, error) {
+	snap := map[string]any{}
+
+	if testCase.TransactionID != nil {
+		txs, err := r.cfg.TransactionSvc.GetTransactionByIDs(ctx, []int64{*testCase.TransactionID})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get transaction")
+		}
+
+		if len(txs) == 0 {
+			return nil, nil
+		}
+
+		snap, err = history.Snapshot(txs[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for k, v := range testCase.Input {
+		snap[k] = v
+	}
+
+	return history.FromSnapshot(snap)
+}
+
+func snapshotValuesEqual(expected, actual any) bool {
+	if reflect.DeepEqual(expected, actual) {
+		return true
+	}
+
+	expectedDec, expectedOk := toDecimal(expected)
+	actualDec, actualOk := toDecimal(actual)
+	if expectedOk && actualOk {
+		return expectedDec.Equal(actualDec)
+	}
+
+	if isEmpty(expected) && isEmpty(actual) {
+		return true
+	}
+
+	expectedRaw, _ := json.Marshal(expected)
+	actualRaw, _ := json.Marshal(actual)
+
+	return string(expectedRaw) == string(actualRaw)
+}
+
+func toDecimal(val any) (decimal.Decimal, bool) {
+	switch v := val.(type) {
+	case float64:
+		return decimal.NewFromFloat(v), true
+	case string:
+		d, err := decimal.NewFromString(v)
+		return d, err == nil
+	}
+
+	return decimal.Decimal{}, false
+}
+
+func isEmpty(val any) bool {
+	if val == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() == 0
+	default:
+		return false
+	}
+}
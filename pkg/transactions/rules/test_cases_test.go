@@ -0,0 +1,132 @@
+package rules_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
+	"github.com/golang/mock/gomock"
+	"github.com/samber/lo"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCaseRunner(txSvc rules.TransactionSvc) *rules.TestCaseRunner {
+	return rules.NewTestCaseRunner(&rules.TestCaseRunnerConfig{
+		Executor:       rules.NewExecutor(rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})),
+		TransactionSvc: txSvc,
+	})
+}
+
+const categorizeUberScript = `
+if string.find(tx:title() or "", "UBER", 1, true) then
+	tx:categoryID(5)
+	tx:addTag(7)
+end
+`
+
+func TestTestCaseRunner(t *testing.T) {
+	t.Run("passing and failing cases", func(t *testing.T) {
+		runner := newTestCaseRunner(nil)
+
+		results, err := runner.Run(context.TODO(), categorizeUberScript, []*database.RuleTestCase{
+			{
+				ID:       1,
+				Name:     "uber",
+				Input:    map[string]any{"title": "UBER TRIP", "source_amount": "-10.5"},
+				Expected: map[string]any{"category_id": float64(5), "tag_ids": []any{float64(7)}, "source_amount": -10.50},
+			},
+			{
+				ID:       2,
+				Name:     "other",
+				Input:    map[string]any{"title": "LIDL"},
+				Expected: map[string]any{"category_id": float64(5)},
+			},
+			{
+				ID:       3,
+				Name:     "untouched",
+				Input:    map[string]any{"title": "LIDL"},
+				Expected: map[string]any{"category_id": nil, "tag_ids": []any{}},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		assert.True(t, results[0].Passed)
+		assert.Empty(t, results[0].Mismatches)
+
+		assert.False(t, results[1].Passed)
+		require.Len(t, results[1].Mismatches, 1)
+		assert.Equal(t, "category_id", results[1].Mismatches[0].Field)
+		assert.Nil(t, results[1].Mismatches[0].Actual)
+
+		assert.True(t, results[2].Passed)
+	})
+
+	t.Run("script error fails case", func(t *testing.T) {
+		runner := newTestCaseRunner(nil)
+
+		results, err := runner.Run(context.TODO(), `error("boom")`, []*database.RuleTestCase{
+			{Name: "any", Input: map[string]any{}, Expected: map[string]any{"title": ""}},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		assert.False(t, results[0].Passed)
+		assert.Contains(t, results[0].Error, "boom")
+	})
+
+	t.Run("input applied on top of existing transaction", func(t *testing.T) {
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+		txSvc.EXPECT().GetTransactionByIDs(gomock.Any(), []int64{42}).
+			Return([]*database.Transaction{
+				{
+					ID:           42,
+					Title:        "LIDL",
+					SourceAmount: decimal.NewNullDecimal(decimal.NewFromInt(-3)),
+				},
+			}, nil)
+
+		runner := newTestCaseRunner(txSvc)
+
+		results, err := runner.Run(context.TODO(), categorizeUberScript, []*database.RuleTestCase{
+			{
+				Name:          "existing",
+				TransactionID: lo.ToPtr(int64(42)),
+				Input:         map[string]any{"title": "UBER EATS"},
+				Expected:      map[string]any{"category_id": 5, "source_amount": "-3"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Passed)
+	})
+
+	t.Run("transaction not found", func(t *testing.T) {
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+		txSvc.EXPECT().GetTransactionByIDs(gomock.Any(), []int64{42}).Return(nil, nil)
+
+		runner := newTestCaseRunner(txSvc)
+
+		results, err := runner.Run(context.TODO(), categorizeUberScript, []*database.RuleTestCase{
+			{Name: "missing", TransactionID: lo.ToPtr(int64(42)), Expected: map[string]any{"title": "x"}},
+		})
+		require.NoError(t, err)
+		assert.False(t, results[0].Passed)
+		assert.Equal(t, "transaction not found", results[0].Error)
+	})
+
+	t.Run("transaction fetch error", func(t *testing.T) {
+		txSvc := NewMockTransactionSvc(gomock.NewController(t))
+		txSvc.EXPECT().GetTransactionByIDs(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		runner := newTestCaseRunner(txSvc)
+
+		_, err := runner.Run(context.TODO(), categorizeUberScript, []*database.RuleTestCase{
+			{Name: "missing", TransactionID: lo.ToPtr(int64(42)), Expected: map[string]any{"title": "x"}},
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,102 @@
+package rules
+
+import (
+	"context"
+
+	importv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/import/v1"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/samber/lo"
+)
+
+var defaultTriggerEvents = []database.RuleTriggerEvent{
+	database.RuleTriggerEventCreated,
+	database.RuleTriggerEventUpdated,
+	database.RuleTriggerEventImported,
+}
+
+var triggerEventNames = map[database.RuleTriggerEvent]string{
+	database.RuleTriggerEventCreated:  "created",
+	database.RuleTriggerEventUpdated:  "updated",
+	database.RuleTriggerEventImported: "imported",
+	database.RuleTriggerEventManual:   "manual",
+}
+
+var actorTypeNames = map[database.TransactionHistoryActorType]string{
+	database.TransactionHistoryActorTypeUser:      "user",
+	database.TransactionHistoryActorTypeRule:      "rule",
+	database.TransactionHistoryActorTypeScheduler: "scheduler",
+	database.TransactionHistoryActorTypeImporter:  "importer",
+	database.TransactionHistoryActorTypeBulk:      "bulk",
+	database.TransactionHistoryActorTypeMcp:       "mcp",
+}
+
+type Trigger struct {
+	Event        database.RuleTriggerEvent
+	ImportSource importv1.ImportSource
+}
+
+type triggerCtxKey struct{}
+
+// WithTrigger overrides the event the executor derives from the transaction itself.
+func WithTrigger(ctx context.Context, t Trigger) context.Context {
+	return context.WithValue(ctx, triggerCtxKey{}, t)
+}
+
+func TriggerFromContext(ctx context.Context) (Trigger, bool) {
+	t, ok := ctx.Value(triggerCtxKey{}).(Trigger)
+	return t, ok
+}
+
+func ImportTrigger(source importv1.ImportSource) Trigger {
+	return Trigger{Event: database.RuleTriggerEventImported, ImportSource: source}
+}
+
+func ManualTrigger() Trigger {
+	return Trigger{Event: database.RuleTriggerEventManual}
+}
+
+func TriggerEventName(event database.RuleTriggerEvent) string {
+	return triggerEventNames[event]
+}
+
+func ActorTypeName(actorType database.TransactionHistoryActorType) string {
+	return actorTypeNames[actorType]
+}
+
+func ParseTriggerEvent(name string) (database.RuleTriggerEvent, bool) {
+	event, ok := lo.FindKey(triggerEventNames, name)
+	return event, ok
+}
+
+// resolveTrigger returns the trigger from context, or derives created/updated from the transaction.
+func resolveTrigger(ctx context.Context, tx *database.Transaction) Trigger {
+	if t, ok := TriggerFromContext(ctx); ok {
+		return t
+	}
+
+	if tx.ID == 0 {
+		return Trigger{Event: database.RuleTriggerEventCreated}
+	}
+
+	return Trigger{Event: database.RuleTriggerEventUpdated}
+}
+
+func ruleMatchesTrigger(rule *database.Rule, trigger Trigger) bool {
+	events := defaultTriggerEvents
+	if len(rule.TriggerEvents) > 0 {
+		events = lo.Map(rule.TriggerEvents, func(e int32, _ int) database.RuleTriggerEvent {
+			return database.RuleTriggerEvent(e)
+		})
+	}
+
+	if !lo.Contains(events, trigger.Event) {
+		return false
+	}
+
+	if len(rule.ImportSources) == 0 {
+		return true
+	}
+
+	return trigger.Event == database.RuleTriggerEventImported &&
+		lo.Contains(rule.ImportSources, int32(trigger.ImportSource))
+}
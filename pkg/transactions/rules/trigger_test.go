@@ -0,0 +1,138 @@
+package rules_test
+
+import (
+	"context"
+	"testing"
+
+	importv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/import/v1"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/ft-t/go-money/pkg/transactions/rules"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggerEventNames(t *testing.T) {
+	for _, name := range []string{"created", "updated", "imported", "manual"} {
+		event, ok := rules.ParseTriggerEvent(name)
+		assert.True(t, ok)
+		assert.Equal(t, name, rules.TriggerEventName(event))
+	}
+
+	_, ok := rules.ParseTriggerEvent("deleted")
+	assert.False(t, ok)
+}
+
+func TestLuaTrigger(t *testing.T) {
+	t.Run("import trigger with actor", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		ctx := rules.WithTrigger(context.TODO(), rules.ImportTrigger(importv1.ImportSource_IMPORT_SOURCE_REVOLUT))
+		ctx = history.WithActor(ctx, history.ImporterActor("revolut"))
+
+		script := `
+		tx:notes(trigger.event .. "|" .. trigger.importSourceName .. "|" .. trigger.actor.type .. "|" .. trigger.actor.detail)
+	`
+
+		tx := &database.Transaction{}
+
+		result, err := interpreter.Run(ctx, script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, "imported|IMPORT_SOURCE_REVOLUT|importer|revolut", tx.Notes)
+	})
+
+	t.Run("user actor", func(t *testing.T) {
+		interpreter := rules.NewLuaInterpreter(&rules.LuaInterpreterConfig{})
+
+		ctx := history.WithActor(context.TODO(), history.UserActor(5))
+
+		script := `
+		if trigger.event == nil and trigger.actor.userID == 5 then
+			tx:notes("user")
+		end
+	`
+
+		tx := &database.Transaction{}
+
+		result, err := interpreter.Run(ctx, script, tx)
+		assert.NoError(t, err)
+		assert.True(t, result)
+		assert.Equal(t, "user", tx.Notes)
+	})
+}
+
+func TestExecutorTriggers(t *testing.T) {
+	type tc struct {
+		name     string
+		ctx      context.Context
+		txID     int64
+		expected []string
+	}
+
+	cases := []tc{
+		{
+			name:     "created",
+			ctx:      context.TODO(),
+			expected: []string{"default", "created-only"},
+		},
+		{
+			name:     "updated",
+			ctx:      context.TODO(),
+			txID:     10,
+			expected: []string{"default"},
+		},
+		{
+			name:     "imported from revolut",
+			ctx:      rules.WithTrigger(context.TODO(), rules.ImportTrigger(importv1.ImportSource_IMPORT_SOURCE_REVOLUT)),
+			expected: []string{"default", "revolut-only"},
+		},
+		{
+			name:     "imported from mbank",
+			ctx:      rules.WithTrigger(context.TODO(), rules.ImportTrigger(importv1.ImportSource_IMPORT_SOURCE_MBANK)),
+			expected: []string{"default"},
+		},
+		{
+			name:     "manual",
+			ctx:      rules.WithTrigger(context.TODO(), rules.ManualTrigger()),
+			expected: []string{"manual-only"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+			dbRules := []*database.Rule{
+				{Script: "default", SortOrder: 1},
+				{Script: "created-only", SortOrder: 2, TriggerEvents: pq.Int32Array{int32(database.RuleTriggerEventCreated)}},
+				{
+					Script:        "revolut-only",
+					SortOrder:     3,
+					TriggerEvents: pq.Int32Array{int32(database.RuleTriggerEventImported)},
+					ImportSources: pq.Int32Array{int32(importv1.ImportSource_IMPORT_SOURCE_REVOLUT)},
+				},
+				{Script: "manual-only", SortOrder: 4, TriggerEvents: pq.Int32Array{int32(database.RuleTriggerEventManual)}},
+			}
+			assert.NoError(t, gormDB.Create(dbRules).Error)
+
+			var executed []string
+
+			interpreter := NewMockInterpreter(gomock.NewController(t))
+			interpreter.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, script string, _ *database.Transaction) (bool, error) {
+					_, ok := rules.TriggerFromContext(ctx)
+					assert.True(t, ok)
+
+					executed = append(executed, script)
+					return false, nil
+				}).AnyTimes()
+
+			_, err := rules.NewExecutor(interpreter).ProcessTransactions(c.ctx, []*database.Transaction{{ID: c.txID}})
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, executed)
+		})
+	}
+}
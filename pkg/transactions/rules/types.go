@@ -6,3 +6,38 @@ type RuleGroup struct {
 	Name  string
 	Rules []*database.Rule
 }
+
+type TestCaseResult struct {
+	CaseID     int32            `json:"case_id"`
+	Name       string           `json:"name"`
+	Passed     bool             `json:"passed"`
+	Error      string           `json:"error,omitempty"`
+	Mismatches []*FieldMismatch `json:"mismatches,omitempty"`
+}
+
+type FieldMismatch struct {
+	Field    string `json:"field"`
+	Expected any    `json:"expected"`
+	Actual   any    `json:"actual"`
+}
+
+type RuleTestReport struct {
+	RuleID    int32             `json:"rule_id"`
+	RuleTitle string            `json:"rule_title"`
+	Passed    bool              `json:"passed"`
+	Results   []*TestCaseResult `json:"results"`
+}
+
+type RevisionDiff struct {
+	RuleID         int32          `json:"rule_id"`
+	FromRevisionID int64          `json:"from_revision_id"`
+	ToRevisionID   int64          `json:"to_revision_id"`
+	Changes        []*FieldChange `json:"changes,omitempty"`
+	ScriptDiff     string         `json:"script_diff,omitempty"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
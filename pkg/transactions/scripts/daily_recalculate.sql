@@ -12,7 +12,7 @@ WITH minDate as (select least(coalesce(
                                                   limit 1)::date, (@startDate)::date),(@startDate)::date) as minDate), -- last fallback to startDate from backend
      date_series AS (SELECT generate_series(
                                     (select * from minDate),
-                                    GREATEST(NOW()::DATE, (select max(transaction_date_only)
+                                    GREATEST((@today)::DATE, (select max(transaction_date_only)
                                                            from transactions
                                                            where (source_account_id = @accountID
                                                               or destination_account_id = @accountID)
@@ -1,29 +1,83 @@
-with upd as (select t.id,
+with src as (select t.id,
+                    t.source_amount,
+                    t.source_currency,
+                    t.transaction_type,
+                    t.fx_source_amount,
+                    t.fx_source_currency,
+                    t.destination_amount,
+                    t.destination_currency,
+                    sourceCurrency.decimal_places,
                     case
-                        when t.source_currency = @baseCurrency then
-                            t.source_amount
-                        when t.transaction_type = 3 and t.fx_source_currency = @baseCurrency and
-                             t.fx_source_amount is not null then
-                            t.fx_source_amount
-                        when t.source_amount is not null and t.destination_amount is not null and
-                             t.destination_currency =
-                             @baseCurrency -- if other side already in base currency, no need to convert
-                            then
-                            t.destination_amount
-                        when t.source_currency != @baseCurrency then
-                            coalesce(nullif(round(t.source_amount / sourceCurrency.rate, sourceCurrency.decimal_places), 0::numeric), 
-                                                                     (t.source_amount / sourceCurrency.rate))
-                        else t.source_amount
-                        end as sourceInBase
+                        when t.exchange_rate_locked and t.exchange_rate is not null and t.exchange_rate != 0
+                            then t.exchange_rate
+                        when override.rate is not null then override.rate
+                        else sourceCurrency.rate
+                        end as rate,
+                    case
+                        when t.exchange_rate_locked and t.exchange_rate is not null and t.exchange_rate != 0
+                            then t.exchange_rate_source
+                        when override.rate is not null then 2 -- override
+                        else 1 -- synced
+                        end as rate_source,
+                    t.exchange_rate_locked and t.exchange_rate is not null and t.exchange_rate != 0 as locked
              from transactions t
                       left join currencies sourceCurrency on sourceCurrency.id = t.source_currency
-                      left join currencies destinationCurrency on destinationCurrency.id = t.destination_currency
+                      left join lateral (select o.rate
+                                         from currency_rate_overrides o
+                                         where o.currency_id = t.source_currency
+                                           and o.deleted_at is null
+                                           and o.valid_from <= t.transaction_date_only
+                                           and (o.valid_to is null or o.valid_to >= t.transaction_date_only)
+                                         order by o.valid_from desc, o.id desc
+                                         limit 1) override on true
              where ((@specificTxIDs)::bigint[] IS NULL
                 OR t.id = ANY ((@specificTxIDs)::bigint[]))
-               and t.deleted_at IS NULL)
+               and ((@specificTxIDs)::bigint[] IS NOT NULL or not t.exchange_rate_locked) -- bulk recalculation keeps locked rates
+               and t.deleted_at IS NULL),
+     upd as (select src.id,
+                    src.source_amount,
+                    case
+                        when src.source_currency = @baseCurrency then
+                            src.source_amount
+                        when src.locked then
+                            coalesce(nullif(round(src.source_amount / src.rate, src.decimal_places), 0::numeric),
+                                     (src.source_amount / src.rate))
+                        when src.transaction_type = 3 and src.fx_source_currency = @baseCurrency and
+                             src.fx_source_amount is not null then
+                            src.fx_source_amount
+                        when src.source_amount is not null and src.destination_amount is not null and
+                             src.destination_currency =
+                             @baseCurrency -- if other side already in base currency, no need to convert
+                            then
+                            src.destination_amount
+                        when src.source_currency != @baseCurrency then
+                            coalesce(nullif(round(src.source_amount / src.rate, src.decimal_places), 0::numeric),
+                                     (src.source_amount / src.rate))
+                        else src.source_amount
+                        end as sourceInBase,
+                    case
+                        when src.locked then src.rate_source
+                        when src.source_currency = @baseCurrency then 3
+                        when src.transaction_type = 3 and src.fx_source_currency = @baseCurrency and
+                             src.fx_source_amount is not null then 3
+                        when src.source_amount is not null and src.destination_amount is not null and
+                             src.destination_currency = @baseCurrency then 3
+                        when src.source_currency != @baseCurrency then src.rate_source
+                        else 0
+                        end as rateSource,
+                    src.rate
+             from src)
 UPDATE transactions
 SET destination_amount_in_base_currency = abs(upd.sourceInBase),
-    source_amount_in_base_currency      = -abs(upd.sourceInBase)
+    source_amount_in_base_currency      = -abs(upd.sourceInBase),
+    exchange_rate                       = case
+                                              when upd.rateSource = 3 then
+                                                  abs(upd.source_amount) / nullif(abs(upd.sourceInBase), 0)
+                                              when upd.rateSource = 0 then null
+                                              else upd.rate
+                                              end,
+    exchange_rate_source                = upd.rateSource
 FROM upd
 WHERE upd.id = transactions.id
-returning transactions.id, destination_amount_in_base_currency, source_amount_in_base_currency
+returning transactions.id, destination_amount_in_base_currency, source_amount_in_base_currency,
+    exchange_rate, exchange_rate_source
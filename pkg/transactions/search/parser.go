@@ -0,0 +1,232 @@
+package search
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shopspring/decimal"
+)
+
+// keys maps key:value prefixes to fields, before, after and on are date comparisons.
+var keys = map[string]Field{
+	"category": FieldCategory,
+	"tag":      FieldTag,
+	"account":  FieldAccount,
+	"type":     FieldType,
+	"ref":      FieldRef,
+	"amount":   FieldAmount,
+	"date":     FieldDate,
+	"before":   FieldDate,
+	"after":    FieldDate,
+	"on":       FieldDate,
+}
+
+var dateKeyOps = map[string]Op{
+	"before": OpLt,
+	"after":  OpGt,
+	"on":     OpEq,
+}
+
+// comparisonOps are checked in order, so two character operators win.
+var comparisonOps = []Op{OpGte, OpLte, OpGt, OpLt, OpEq}
+
+// Parse parses a search query such as
+//
+//	coffee "corner shop" category:Food,Drinks -tag:work amount>=10 account:"Revolut EUR" before:2026-01-01
+//
+// Words that do not start with a known key are searched as text.
+func Parse(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &Query{}
+
+	for _, tok := range tokens {
+		term, termErr := parseToken(tok)
+		if termErr != nil {
+			return nil, termErr
+		}
+
+		if term != nil {
+			query.Terms = append(query.Terms, term)
+		}
+	}
+
+	return query, nil
+}
+
+type token struct {
+	raw     string // text before the first quote, e.g. account: for account:"Revolut EUR"
+	quoted  string
+	hasQuot bool
+	negated bool
+}
+
+func tokenize(input string) ([]*token, error) {
+	var (
+		tokens []*token
+		runes  = []rune(input)
+	)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		tok := &token{}
+
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			tok.negated = true
+			i++
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '"' {
+			i++
+		}
+
+		tok.raw = string(runes[start:i])
+
+		if i < len(runes) && runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+
+			if end == len(runes) {
+				return nil, errors.New("unterminated quote")
+			}
+
+			tok.quoted = string(runes[i+1 : end])
+			tok.hasQuot = true
+			i = end + 1
+		}
+
+		tokens = append(tokens, tok)
+	}
+
+	return tokens, nil
+}
+
+func parseToken(tok *token) (*Term, error) {
+	key, op, value, ok := splitKey(tok.raw)
+	if !ok {
+		text := tok.raw + tok.quoted
+		if strings.TrimSpace(text) == "" {
+			return nil, nil
+		}
+
+		return &Term{
+			Field:   FieldText,
+			Op:      OpEq,
+			Values:  []string{strings.TrimSpace(text)},
+			Phrase:  tok.hasQuot,
+			Negated: tok.negated,
+		}, nil
+	}
+
+	if tok.hasQuot {
+		if value != "" {
+			return nil, errors.Newf("%s: quote the whole value", key)
+		}
+
+		value = tok.quoted
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, errors.Newf("%s: value is required", key)
+	}
+
+	term := &Term{
+		Field:   keys[key],
+		Op:      op,
+		Negated: tok.negated,
+	}
+
+	if dateOp, isDateKey := dateKeyOps[key]; isDateKey && op == OpEq {
+		term.Op = dateOp // before:, after: and on:
+	}
+
+	switch term.Field {
+	case FieldAmount:
+		amount, err := decimal.NewFromString(value)
+		if err != nil {
+			return nil, errors.Newf("amount: invalid number %q", value)
+		}
+
+		term.Amount = amount.Abs()
+		term.Values = []string{value}
+	case FieldDate:
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, errors.Newf("%s: date must be YYYY-MM-DD, got %q", key, value)
+		}
+
+		term.Date = date
+		term.Values = []string{value}
+	case FieldRef:
+		term.Values = []string{value}
+	default:
+		if op != OpEq {
+			return nil, errors.Newf("%s: only %s:value is supported", key, key)
+		}
+
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				term.Values = append(term.Values, v)
+			}
+		}
+
+		if len(term.Values) == 0 {
+			return nil, errors.Newf("%s: value is required", key)
+		}
+
+		if term.Field == FieldType {
+			for _, v := range term.Values {
+				txType, found := transactionTypes[strings.ToLower(v)]
+				if !found {
+					return nil, errors.Newf("type: unknown transaction type %q, use expense, income, transfer, adjustment or reversal", v)
+				}
+
+				term.Types = append(term.Types, txType)
+			}
+		}
+	}
+
+	return term, nil
+}
+
+// splitKey splits key:value and, for amount and date, key>value style comparisons.
+func splitKey(raw string) (string, Op, string, bool) {
+	lower := strings.ToLower(raw)
+
+	for key := range keys {
+		if !strings.HasPrefix(lower, key) {
+			continue
+		}
+
+		rest := raw[len(key):]
+
+		if strings.HasPrefix(rest, ":") {
+			return key, OpEq, rest[1:], true
+		}
+
+		if key != "amount" && key != "date" {
+			continue
+		}
+
+		for _, op := range comparisonOps {
+			if strings.HasPrefix(rest, string(op)) {
+				return key, op, rest[len(op):], true
+			}
+		}
+	}
+
+	return "", "", "", false
+}
@@ -0,0 +1,91 @@
+package search_test
+
+import (
+	"testing"
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ft-t/go-money/pkg/transactions/search"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("full query", func(t *testing.T) {
+		query, err := search.Parse(`coffee "corner shop" category:Food,Drinks -tag:work amount>=10.5 account:"Revolut EUR" before:2026-01-01 type:expense ref:INV-1`)
+		require.NoError(t, err)
+		require.Len(t, query.Terms, 9)
+
+		assert.Equal(t, &search.Term{Field: search.FieldText, Op: search.OpEq, Values: []string{"coffee"}}, query.Terms[0])
+		assert.Equal(t, &search.Term{Field: search.FieldText, Op: search.OpEq, Values: []string{"corner shop"}, Phrase: true}, query.Terms[1])
+		assert.Equal(t, &search.Term{Field: search.FieldCategory, Op: search.OpEq, Values: []string{"Food", "Drinks"}}, query.Terms[2])
+		assert.Equal(t, &search.Term{Field: search.FieldTag, Op: search.OpEq, Values: []string{"work"}, Negated: true}, query.Terms[3])
+
+		assert.Equal(t, search.FieldAmount, query.Terms[4].Field)
+		assert.Equal(t, search.OpGte, query.Terms[4].Op)
+		assert.True(t, decimal.RequireFromString("10.5").Equal(query.Terms[4].Amount))
+
+		assert.Equal(t, []string{"Revolut EUR"}, query.Terms[5].Values)
+
+		assert.Equal(t, search.FieldDate, query.Terms[6].Field)
+		assert.Equal(t, search.OpLt, query.Terms[6].Op)
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), query.Terms[6].Date)
+
+		assert.Equal(t, []gomoneypbv1.TransactionType{gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE}, query.Terms[7].Types)
+		assert.Equal(t, &search.Term{Field: search.FieldRef, Op: search.OpEq, Values: []string{"INV-1"}}, query.Terms[8])
+	})
+
+	t.Run("date keys", func(t *testing.T) {
+		for input, op := range map[string]search.Op{
+			"after:2026-02-01":  search.OpGt,
+			"on:2026-02-01":     search.OpEq,
+			"date:2026-02-01":   search.OpEq,
+			"date<=2026-02-01":  search.OpLte,
+			"BEFORE:2026-02-01": search.OpLt,
+		} {
+			query, err := search.Parse(input)
+			require.NoError(t, err, input)
+			require.Len(t, query.Terms, 1)
+			assert.Equal(t, search.FieldDate, query.Terms[0].Field, input)
+			assert.Equal(t, op, query.Terms[0].Op, input)
+		}
+	})
+
+	t.Run("unknown keys are text", func(t *testing.T) {
+		query, err := search.Parse("https://shop.example 10:30 online")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"https://shop.example"}, query.Terms[0].Values)
+		assert.Equal(t, []string{"10:30"}, query.Terms[1].Values)
+		assert.Equal(t, search.FieldText, query.Terms[2].Field)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		query, err := search.Parse("   ")
+		require.NoError(t, err)
+		assert.Empty(t, query.Terms)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for input, expected := range map[string]string{
+			`account:"Revolut EUR`:   "unterminated quote",
+			"category:":              "category: value is required",
+			"category:,":             "category: value is required",
+			"amount>ten":             `amount: invalid number "ten"`,
+			"before:01.02.2026":      "before: date must be YYYY-MM-DD",
+			"type:refund":            `type: unknown transaction type "refund"`,
+			"tag>trip":               "",
+			`account:x"Revolut EUR"`: "account: quote the whole value",
+		} {
+			_, err := search.Parse(input)
+			if expected == "" {
+				assert.NoError(t, err, input)
+				continue
+			}
+
+			assert.ErrorContains(t, err, expected, input)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+// amountExpression is the amount the transaction list sorts by.
+const amountExpression = "coalesce(transactions.destination_amount, abs(transactions.source_amount), 0)"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Apply adds the conditions of the query to a transactions query. Dates are days in loc.
+func Apply(db *gorm.DB, query *Query, loc *time.Location) *gorm.DB {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	for _, term := range query.Terms {
+		condition, args := termCondition(term, loc)
+
+		if term.Negated {
+			condition = "NOT coalesce((" + condition + "), false)"
+		}
+
+		db = db.Where("("+condition+")", args...)
+	}
+
+	return db
+}
+
+func termCondition(term *Term, loc *time.Location) (string, []any) {
+	switch term.Field {
+	case FieldCategory:
+		names := lo.Reject(lowerValues(term.Values), func(v string, _ int) bool { return v == "none" })
+
+		var parts []string
+		var args []any
+
+		if len(names) > 0 {
+			parts = append(parts, "transactions.category_id IN (SELECT id FROM categories WHERE lower(name) IN ?)")
+			args = append(args, names)
+		}
+
+		if len(names) != len(term.Values) {
+			parts = append(parts, "transactions.category_id IS NULL")
+		}
+
+		return strings.Join(parts, " OR "), args
+	case FieldTag:
+		return "transactions.tag_ids && ARRAY(SELECT id FROM tags WHERE lower(name) IN ?)", []any{lowerValues(term.Values)}
+	case FieldAccount:
+		names := lowerValues(term.Values)
+
+		return "transactions.source_account_id IN (SELECT id FROM accounts WHERE lower(name) IN ?) OR " +
+			"transactions.destination_account_id IN (SELECT id FROM accounts WHERE lower(name) IN ?)", []any{names, names}
+	case FieldType:
+		return "transactions.transaction_type IN ?", []any{lo.Map(term.Types, func(t gomoneypbv1.TransactionType, _ int) int32 {
+			return int32(t)
+		})}
+	case FieldRef:
+		return "lower(transactions.reference_number) = lower(?) OR transactions.internal_reference_numbers @> ARRAY[?]::text[]",
+			[]any{term.Values[0], term.Values[0]}
+	case FieldAmount:
+		return fmt.Sprintf("%s %s ?", amountExpression, term.Op), []any{term.Amount}
+	case FieldDate:
+		return dateCondition(term, loc)
+	default:
+		return textCondition(term)
+	}
+}
+
+// dateCondition compares transaction_date_time, stored in UTC, with the bounds of a day in loc.
+func dateCondition(term *Term, loc *time.Location) (string, []any) {
+	start := time.Date(term.Date.Year(), term.Date.Month(), term.Date.Day(), 0, 0, 0, 0, loc).UTC()
+	next := time.Date(term.Date.Year(), term.Date.Month(), term.Date.Day()+1, 0, 0, 0, 0, loc).UTC()
+
+	switch term.Op {
+	case OpGt:
+		return "transactions.transaction_date_time >= ?", []any{next}
+	case OpGte:
+		return "transactions.transaction_date_time >= ?", []any{start}
+	case OpLt:
+		return "transactions.transaction_date_time < ?", []any{start}
+	case OpLte:
+		return "transactions.transaction_date_time < ?", []any{next}
+	default:
+		return "transactions.transaction_date_time >= ? AND transactions.transaction_date_time < ?", []any{start, next}
+	}
+}
+
+// textCondition matches words by prefix in the search vector (title, notes, references and extra
+// values). The title, category and account names also match as a substring through trigram indexes,
+// which covers parts of words and text the vector splits differently.
+func textCondition(term *Term) (string, []any) {
+	like := "%" + likeEscaper.Replace(term.Values[0]) + "%"
+
+	parts := []string{
+		"transactions.title ILIKE ?",
+		"transactions.category_id IN (SELECT id FROM categories WHERE name ILIKE ?)",
+		"transactions.source_account_id IN (SELECT id FROM accounts WHERE name ILIKE ?)",
+		"transactions.destination_account_id IN (SELECT id FROM accounts WHERE name ILIKE ?)",
+	}
+	args := []any{like, like, like, like}
+
+	if tsQuery := toTsQuery(term.Values[0], term.Phrase); tsQuery != "" {
+		parts = append([]string{"transactions.search_vector @@ to_tsquery('simple', ?)"}, parts...)
+		args = append([]any{tsQuery}, args...)
+	}
+
+	return strings.Join(parts, " OR "), args
+}
+
+// toTsQuery builds a prefix tsquery from the letters and digits of the text, so user input can
+// not inject tsquery operators. Phrase words must follow each other.
+func toTsQuery(text string, phrase bool) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(lo.Map(words, func(w string, _ int) string {
+		return "'" + w + "':*"
+	}), lo.Ternary(phrase, " <-> ", " & "))
+}
+
+func lowerValues(values []string) []string {
+	return lo.Map(values, func(v string, _ int) string { return strings.ToLower(v) })
+}
@@ -0,0 +1,71 @@
+package search_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions/search"
+)
+
+func buildSQL(t *testing.T, input string, loc *time.Location) (string, []any) {
+	gormDB, mockDB, _ := testingutils.GormMock()
+	t.Cleanup(func() { _ = mockDB.Close() })
+
+	query, err := search.Parse(input)
+	require.NoError(t, err)
+
+	stmt := search.Apply(gormDB.Session(&gorm.Session{DryRun: true}).Model(&database.Transaction{}), query, loc).
+		Find(&[]*database.Transaction{}).Statement
+
+	return stmt.SQL.String(), stmt.Vars
+}
+
+func TestApply(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		sql, vars := buildSQL(t, `"corner shop" 50%_off`, nil)
+
+		assert.Contains(t, sql, "transactions.search_vector @@ to_tsquery('simple', $1)")
+		assert.Contains(t, sql, "transactions.title ILIKE $2")
+		assert.Contains(t, sql, "SELECT id FROM categories WHERE name ILIKE $3")
+		assert.Equal(t, "'corner':* <-> 'shop':*", vars[0])
+		assert.Equal(t, "%corner shop%", vars[1])
+		assert.Equal(t, "'50':* & 'off':*", vars[5])
+		assert.Equal(t, `%50\%\_off%`, vars[6])
+	})
+
+	t.Run("punctuation only", func(t *testing.T) {
+		sql, vars := buildSQL(t, "&&", nil)
+
+		assert.NotContains(t, sql, "search_vector")
+		assert.Equal(t, "%&&%", vars[0])
+	})
+
+	t.Run("names and negation", func(t *testing.T) {
+		sql, vars := buildSQL(t, `-category:Food,none tag:Trip account:"Revolut EUR"`, nil)
+
+		assert.Contains(t, sql, "NOT coalesce((transactions.category_id IN (SELECT id FROM categories WHERE lower(name) IN ($1)) OR transactions.category_id IS NULL), false)")
+		assert.Contains(t, sql, "transactions.tag_ids && ARRAY(SELECT id FROM tags WHERE lower(name) IN ($2))")
+		assert.Contains(t, sql, "transactions.source_account_id IN (SELECT id FROM accounts WHERE lower(name) IN ($3))")
+		assert.Equal(t, []any{"food", "trip", "revolut eur", "revolut eur"}, vars)
+	})
+
+	t.Run("amount and dates in household timezone", func(t *testing.T) {
+		loc, err := time.LoadLocation("Europe/Kyiv")
+		require.NoError(t, err)
+
+		sql, vars := buildSQL(t, "amount>100 after:2026-01-31 on:2026-03-01", loc)
+
+		assert.Contains(t, sql, "coalesce(transactions.destination_amount, abs(transactions.source_amount), 0) > $1")
+		assert.Contains(t, sql, "transactions.transaction_date_time >= $2")
+		assert.Contains(t, sql, "transactions.transaction_date_time >= $3 AND transactions.transaction_date_time < $4")
+		assert.Equal(t, time.Date(2026, 1, 31, 22, 0, 0, 0, time.UTC), vars[1])
+		assert.Equal(t, time.Date(2026, 2, 28, 22, 0, 0, 0, time.UTC), vars[2])
+		assert.Equal(t, time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC), vars[3])
+	})
+}
@@ -0,0 +1,56 @@
+package search
+
+import (
+	"time"
+
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+	"github.com/shopspring/decimal"
+)
+
+type Field string
+
+const (
+	FieldText     Field = "text"     // words and phrases
+	FieldCategory Field = "category" // category names, "none" for uncategorized
+	FieldTag      Field = "tag"      // tag names
+	FieldAccount  Field = "account"  // source or destination account names
+	FieldType     Field = "type"
+	FieldRef      Field = "ref" // reference number or internal reference number
+	FieldAmount   Field = "amount"
+	FieldDate     Field = "date" // days in the household timezone
+)
+
+type Op string
+
+const (
+	OpEq  Op = "="
+	OpGt  Op = ">"
+	OpGte Op = ">="
+	OpLt  Op = "<"
+	OpLte Op = "<="
+)
+
+var transactionTypes = map[string]gomoneypbv1.TransactionType{
+	"transfer":   gomoneypbv1.TransactionType_TRANSACTION_TYPE_TRANSFER_BETWEEN_ACCOUNTS,
+	"income":     gomoneypbv1.TransactionType_TRANSACTION_TYPE_INCOME,
+	"expense":    gomoneypbv1.TransactionType_TRANSACTION_TYPE_EXPENSE,
+	"reversal":   gomoneypbv1.TransactionType_TRANSACTION_TYPE_REVERSAL,
+	"adjustment": gomoneypbv1.TransactionType_TRANSACTION_TYPE_ADJUSTMENT,
+}
+
+// Term is a single condition of a query, all terms must match.
+type Term struct {
+	Field   Field
+	Op      Op       // amount and date only, = otherwise
+	Values  []string // any of; a single value for text, ref, amount and date
+	Phrase  bool     // text was quoted, words must follow each other
+	Negated bool
+
+	Types  []gomoneypbv1.TransactionType // parsed values of type
+	Amount decimal.Decimal               // parsed value of amount
+	Date   time.Time                     // parsed value of date
+}
+
+type Query struct {
+	Terms []*Term
+}
@@ -2,7 +2,6 @@ package transactions
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/ft-t/go-money/pkg/boilerplate"
 	"github.com/ft-t/go-money/pkg/configuration"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/timezone"
 	"github.com/ft-t/go-money/pkg/transactions/history"
 	"github.com/ft-t/go-money/pkg/transactions/validation"
 	"github.com/hashicorp/golang-lru/v2/expirable"
@@ -20,7 +20,6 @@ import (
 	"github.com/samber/lo"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 const (
@@ -42,6 +41,8 @@ type ServiceConfig struct {
 	DoubleEntry          DoubleEntrySvc
 	AccountSvc           AccountSvc
 	HistorySvc           HistorySvc
+	SuggestionsSvc       SuggestionsSvc // optional, dropped model after edits of categories and tags
+	Location             *time.Location // household timezone of transaction_date_only, UTC when nil
 }
 
 func NewService(
@@ -100,113 +101,36 @@ func (s *Service) GetTitleSuggestions(
 	}, nil
 }
 
+// List serves the ListTransactions api through ListPage. The api has no cursor fields yet, so it
+// pages with skip and counts only when the page does not tell the total.
 func (s *Service) List(
 	ctx context.Context,
 	req *transactionsv1.ListTransactionsRequest,
 ) (*transactionsv1.ListTransactionsResponse, error) {
-	query := database.GetDbWithContext(ctx, database.DbTypeReadonly).Where("deleted_at IS NULL").Limit(int(req.Limit))
-
-	if req.AmountFrom != nil {
-		amountFrom, err := decimal.NewFromString(*req.AmountFrom)
-		if err != nil {
-			return nil, errors.Wrap(err, "invalid amount_from")
-		}
-
-		query = query.Where("(source_account_id is not null and source_amount >= ?) OR (destination_account_id is not null and destination_amount >= ?)",
-			amountFrom, amountFrom)
-	}
-
-	if req.AmountTo != nil {
-		amountTo, err := decimal.NewFromString(*req.AmountTo)
-		if err != nil {
-			return nil, errors.Wrap(err, "invalid amount_to")
-		}
-
-		query = query.Where("(source_account_id is not null and source_amount <= ?) OR (destination_account_id is not null and destination_amount <= ?)",
-			amountTo, amountTo)
-	}
-
-	if req.FromDate != nil {
-		query = query.Where("transaction_date_time >= ?", req.FromDate.AsTime())
-	}
-
-	if req.ToDate != nil {
-		query = query.Where("transaction_date_time <= ?", req.ToDate.AsTime())
-	}
-
-	if req.TextQuery != nil {
-		query = query.Where("title ILIKE ?", "%"+*req.TextQuery+"%")
-	}
-
-	if len(req.DestinationAccountIds) > 0 {
-		query = query.Where("destination_account_id IN ?", req.DestinationAccountIds)
-	}
-
-	if len(req.SourceAccountIds) > 0 {
-		query = query.Where("source_account_id IN ?", req.SourceAccountIds)
-	}
-
-	if len(req.AnyAccountIds) > 0 {
-		query = query.Where("source_account_id IN ? OR destination_account_id IN ?", req.AnyAccountIds, req.AnyAccountIds)
-	}
-
-	if len(req.TransactionTypes) > 0 {
-		query = query.Where("transaction_type IN ?", lo.Map(req.TransactionTypes, func(t gomoneypbv1.TransactionType, _ int) int32 {
-			return int32(t)
-		}))
-	}
-
-	if len(req.CategoryIds) > 0 {
-		query = query.Where("category_id IN ?", req.CategoryIds)
-	}
-
-	if len(req.TagIds) > 0 {
-		var tagIds []string
-		for _, tagId := range req.TagIds {
-			tagIds = append(tagIds, fmt.Sprintf("%d", tagId))
+	sorts := lo.Map(req.Sort, func(sort *transactionsv1.ListTransactionsRequest_Sort, _ int) *Sort {
+		return &Sort{
+			Field:     lo.CoalesceOrEmpty(SortField(sort.Field), SortFieldTransactionDate),
+			Ascending: sort.Ascending,
 		}
+	})
 
-		query = query.Where(fmt.Sprintf("tag_ids && Array[%s]", strings.Join(tagIds, ",")))
-	}
-
-	if len(req.Ids) > 0 {
-		query = query.Where("id IN ?", req.Ids)
+	page, err := s.ListPage(ctx, &ListRequest{Filter: req, Sort: sorts})
+	if err != nil {
+		return nil, err
 	}
 
-	for _, sort := range req.Sort {
-		switch sort.Field {
-		default:
-			query = query.Order(clause.OrderByColumn{
-				Column: clause.Column{
-					Table: "transactions",
-					Name:  "transaction_date_time",
-				},
-				Desc: !sort.Ascending,
-			})
+	// a page with rows and no next page ends the list, past the end only a count tells the total
+	total := int64(req.Skip) + int64(len(page.Transactions))
+	if page.NextCursor != "" || (len(page.Transactions) == 0 && req.Skip > 0) {
+		if total, err = s.count(ctx, req); err != nil {
+			return nil, err
 		}
 	}
 
-	var transactions []*database.Transaction
-
-	var count int64
-	if err := query.Model(transactions).Count(&count).Error; err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	if err := query.Limit(int(req.Limit)).Offset(int(req.Skip)).Find(&transactions).Error; err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	resp := &transactionsv1.ListTransactionsResponse{
-		Transactions: nil,
-		TotalCount:   count,
-	}
-
-	for _, tx := range transactions {
-		resp.Transactions = append(resp.Transactions, s.cfg.MapperSvc.MapTransaction(ctx, tx))
-	}
-
-	return resp, nil
+	return &transactionsv1.ListTransactionsResponse{
+		Transactions: page.Transactions,
+		TotalCount:   total,
+	}, nil
 }
 
 func (s *Service) CreateBulk(
@@ -274,6 +198,12 @@ func (s *Service) ConvertRequestToTransaction(
 		newTx.ID = originalTx.ID
 		newTx.CreatedAt = originalTx.CreatedAt
 		newTx.UpdatedAt = time.Now().UTC()
+
+		if originalTx.ExchangeRateLocked { // locked rate survives edits
+			newTx.ExchangeRate = originalTx.ExchangeRate
+			newTx.ExchangeRateSource = originalTx.ExchangeRateSource
+			newTx.ExchangeRateLocked = true
+		}
 	}
 
 	if newTx.Extra == nil {
@@ -364,10 +294,24 @@ func (s *Service) CreateBulkInternal(
 		transactionWithRules = modifiedTxs
 	}
 
+	accountMap, err := s.getAccountMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	created := append(transactionWithRules, transactionWithoutRules...)
+
+	for _, newTx := range created { // after rules, as they can move the date or change accounts
+		newTx.TransactionDateOnly = timezone.DateOnly(
+			newTx.TransactionDateTime,
+			timezone.TransactionLocation(newTx, accountMap, s.cfg.Location),
+		)
+	}
+
 	var toCreate []*database.Transaction
 	var toUpdate []*database.Transaction
 
-	for _, newTx := range append(transactionWithRules, transactionWithoutRules...) {
+	for _, newTx := range created {
 		if newTx.ID == 0 {
 			toCreate = append(toCreate, newTx)
 		} else {
@@ -400,9 +344,7 @@ func (s *Service) CreateBulkInternal(
 		s.recordHistory(ctx, tx, newTx, origByID[newTx.ID], database.TransactionHistoryEventTypeUpdated)
 	}
 
-	created := append(transactionWithRules, transactionWithoutRules...)
-
-	return s.FinalizeTransactions(ctx, tx, created, originalTxs, opts)
+	return s.finalizeTransactions(ctx, tx, accountMap, created, originalTxs, opts)
 }
 
 func (s *Service) CreateRawTransaction(
@@ -433,6 +375,77 @@ func (s *Service) CreateRawTransaction(
 	return resp[0], nil
 }
 
+// UpsertRawTransactions creates transactions without ID and saves existing ones in a single db
+// transaction. Rules are not applied, history is recorded with the actor from ctx.
+func (s *Service) UpsertRawTransactions(
+	ctx context.Context,
+	created []*database.Transaction,
+	updated []*database.Transaction,
+) ([]*transactionsv1.CreateTransactionResponse, error) {
+	if len(created) == 0 && len(updated) == 0 {
+		return nil, nil
+	}
+
+	tx := database.GetDbWithContext(ctx, database.DbTypeMaster).Begin()
+	defer tx.Rollback()
+	ctx = database.WithContext(ctx, tx)
+
+	var originalTxs []*database.Transaction
+	if len(updated) > 0 {
+		ids := lo.Map(updated, func(t *database.Transaction, _ int) int64 {
+			return t.ID
+		})
+
+		if err := tx.Where("id IN ? AND deleted_at IS NULL", ids).Find(&originalTxs).Error; err != nil {
+			return nil, errors.Wrap(err, "failed to find existing transactions")
+		}
+
+		if len(originalTxs) != len(lo.Uniq(ids)) {
+			return nil, errors.New("some transactions to update were not found")
+		}
+	}
+
+	for _, newTx := range created {
+		if newTx.Extra == nil {
+			newTx.Extra = map[string]string{}
+		}
+
+		if err := tx.Create(newTx).Error; err != nil {
+			return nil, errors.Wrapf(err, "failed to create transaction: %v", newTx)
+		}
+
+		s.recordHistory(ctx, tx, newTx, nil, database.TransactionHistoryEventTypeCreated)
+	}
+
+	origByID := make(map[int64]*database.Transaction, len(originalTxs))
+	for _, o := range originalTxs {
+		origByID[o.ID] = o
+	}
+
+	for _, updTx := range updated {
+		if err := tx.Save(updTx).Error; err != nil {
+			return nil, errors.Wrapf(err, "failed to update transaction: %d", updTx.ID)
+		}
+
+		s.recordHistory(ctx, tx, updTx, origByID[updTx.ID], database.TransactionHistoryEventTypeUpdated)
+	}
+
+	all := make([]*database.Transaction, 0, len(created)+len(updated))
+	all = append(all, created...)
+	all = append(all, updated...)
+
+	resp, err := s.FinalizeTransactions(ctx, tx, all, originalTxs, UpsertOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return resp, nil
+}
+
 func (s *Service) FinalizeTransactions(
 	ctx context.Context,
 	tx *gorm.DB,
@@ -440,6 +453,15 @@ func (s *Service) FinalizeTransactions(
 	originalTxs []*database.Transaction,
 	opts UpsertOptions,
 ) ([]*transactionsv1.CreateTransactionResponse, error) {
+	accountMap, err := s.getAccountMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.finalizeTransactions(ctx, tx, accountMap, created, originalTxs, opts)
+}
+
+func (s *Service) getAccountMap(ctx context.Context) (map[int32]*database.Account, error) {
 	accounts, err := s.cfg.AccountSvc.GetAllAccounts(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get accounts")
@@ -450,7 +472,18 @@ func (s *Service) FinalizeTransactions(
 		accountMap[acc.ID] = acc
 	}
 
-	if err = s.cfg.ValidationSvc.Validate(ctx, tx, &validation.Request{
+	return accountMap, nil
+}
+
+func (s *Service) finalizeTransactions(
+	ctx context.Context,
+	tx *gorm.DB,
+	accountMap map[int32]*database.Account,
+	created []*database.Transaction,
+	originalTxs []*database.Transaction,
+	opts UpsertOptions,
+) ([]*transactionsv1.CreateTransactionResponse, error) {
+	if err := s.cfg.ValidationSvc.Validate(ctx, tx, &validation.Request{
 		Txs:                    created,
 		Accounts:               accountMap,
 		SkipAccountsValidation: opts.SkipAccountSourceDestValidation,
@@ -458,7 +491,7 @@ func (s *Service) FinalizeTransactions(
 		return nil, errors.Wrap(err, "failed to validate transactions")
 	}
 
-	if err = s.StoreStat(ctx, tx, created, originalTxs, accountMap); err != nil {
+	if err := s.StoreStat(ctx, tx, created, originalTxs, accountMap); err != nil {
 		return nil, errors.Wrap(err, "failed to store statistics")
 	}
 
@@ -548,6 +581,8 @@ func (s *Service) Update(
 		return nil, errors.WithStack(err)
 	}
 
+	s.invalidateSuggestions()
+
 	return &transactionsv1.UpdateTransactionResponse{
 		Transaction: resp[0].Transaction,
 	}, nil
@@ -758,6 +793,8 @@ func (s *Service) BulkSetCategory(
 		return errors.Wrap(err, "failed to commit bulk category update")
 	}
 
+	s.invalidateSuggestions()
+
 	return nil
 }
 
@@ -795,9 +832,71 @@ func (s *Service) BulkSetTags(
 		return errors.Wrap(err, "failed to commit bulk tags update")
 	}
 
+	s.invalidateSuggestions()
+
 	return nil
 }
 
+// SetExchangeRates stores a manual rate on transactions or locks/unlocks the rate they already use.
+// Locked rates are kept by bulk base currency recalculation; unlocking recalculates base amounts.
+func (s *Service) SetExchangeRates(
+	ctx context.Context,
+	assignments []ExchangeRateAssignment,
+) ([]*database.Transaction, error) {
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, 0, len(assignments))
+	for _, a := range assignments {
+		if a.Rate != nil {
+			if !a.Rate.IsPositive() {
+				return nil, errors.Newf("exchange rate for transaction %d must be positive", a.TransactionID)
+			}
+
+			if !a.Locked {
+				return nil, errors.Newf("manual exchange rate for transaction %d must be locked", a.TransactionID)
+			}
+		}
+
+		ids = append(ids, a.TransactionID)
+	}
+
+	var existing []*database.Transaction
+	if err := database.GetDbWithContext(ctx, database.DbTypeMaster).
+		Where("id IN ? AND deleted_at IS NULL", ids).
+		Find(&existing).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to find transactions")
+	}
+
+	byID := make(map[int64]*database.Transaction, len(existing))
+	for _, t := range existing {
+		byID[t.ID] = t
+	}
+
+	updated := make([]*database.Transaction, 0, len(assignments))
+	for _, a := range assignments {
+		t, ok := byID[a.TransactionID]
+		if !ok {
+			return nil, errors.Newf("transaction %d not found", a.TransactionID)
+		}
+
+		if a.Rate != nil {
+			t.ExchangeRate = decimal.NewNullDecimal(*a.Rate)
+			t.ExchangeRateSource = database.ExchangeRateSourceManual
+		}
+
+		t.ExchangeRateLocked = a.Locked
+		updated = append(updated, t)
+	}
+
+	if _, err := s.UpsertRawTransactions(ctx, nil, updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
 func (s *Service) DeleteTransaction(
 	ctx context.Context,
 	req *transactionsv1.DeleteTransactionsRequest,
@@ -830,11 +929,23 @@ func (s *Service) DeleteTransaction(
 		return nil, errors.Wrap(err, "failed to commit transaction")
 	}
 
+	s.invalidateSuggestions()
+
 	return &transactionsv1.DeleteTransactionsResponse{
 		DeletedCount: int32(len(selectedTxs)),
 	}, nil
 }
 
+// invalidateSuggestions drops the suggestion model after a user changed what it learns from.
+// Creates are left to the model TTL, so imports do not rebuild it for every batch.
+func (s *Service) invalidateSuggestions() {
+	if s.cfg.SuggestionsSvc == nil {
+		return
+	}
+
+	s.cfg.SuggestionsSvc.Invalidate()
+}
+
 func (s *Service) recordHistory(
 	ctx context.Context,
 	tx *gorm.DB,
@@ -878,7 +989,7 @@ func (s *Service) recordHistory(
 			Tx:        ruleEvent.After,
 			Previous:  ruleEvent.Before,
 			EventType: database.TransactionHistoryEventTypeRuleApplied,
-			Actor:     history.RuleActor(ruleEvent.RuleID),
+			Actor:     history.RuleRevisionActor(ruleEvent.RuleID, ruleEvent.RuleRevisionID),
 		}); err != nil {
 			zerolog.Ctx(ctx).Error().Err(err).
 				Int64("tx_id", curr.ID).
@@ -910,6 +1021,10 @@ func (s *Service) recordHistoryBulk(
 	}
 
 	bulkActor := history.BulkActor(*actor.UserID, op)
+	if actor.Type == database.TransactionHistoryActorTypeMcp {
+		bulkActor = actor // keep mcp writes distinguishable, the tool call is in the mcp audit log
+	}
+
 	if err := s.cfg.HistorySvc.Record(ctx, tx, history.RecordRequest{
 		Tx:        curr,
 		Previous:  prev,
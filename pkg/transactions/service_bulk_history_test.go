@@ -120,6 +120,28 @@ func TestBulkSetTags_RecordsHistory_WithBulkActor(t *testing.T) {
 	assert.Equal(t, pq.Int32Array{9}, recorded[1].Tx.TagIDs)
 }
 
+func TestBulkSetCategory_RecordsHistory_KeepsMcpActor(t *testing.T) {
+	txs := seedBulkTxs(t, 1)
+
+	historyMock := NewMockHistorySvc(gomock.NewController(t))
+	srv := transactions.NewService(&transactions.ServiceConfig{
+		HistorySvc: historyMock,
+	})
+
+	historyMock.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *gorm.DB, req history.RecordRequest) error {
+			assert.Equal(t, database.TransactionHistoryActorTypeMcp, req.Actor.Type)
+			assert.Equal(t, int32(7), *req.Actor.UserID)
+			assert.Equal(t, "token-jti", req.Actor.Detail)
+			return nil
+		})
+
+	ctx := history.WithActor(context.Background(), history.McpActor(7, "token-jti"))
+	require.NoError(t, srv.BulkSetCategory(ctx, []transactions.CategoryAssignment{
+		{TransactionID: txs[0].ID, CategoryID: lo.ToPtr(int32(42))},
+	}))
+}
+
 func TestBulkSetCategory_NoActor_SkipsHistory_StillSucceeds(t *testing.T) {
 	txs := seedBulkTxs(t, 1)
 
@@ -178,3 +200,26 @@ func TestBulkSetCategory_Empty_NoHistory(t *testing.T) {
 	require.NoError(t, srv.BulkSetCategory(ctx, nil))
 	require.NoError(t, srv.BulkSetCategory(ctx, []transactions.CategoryAssignment{}))
 }
+
+func TestBulkSet_InvalidatesSuggestions(t *testing.T) {
+	txs := seedBulkTxs(t, 1)
+
+	suggestionsMock := NewMockSuggestionsSvc(gomock.NewController(t))
+	srv := transactions.NewService(&transactions.ServiceConfig{
+		SuggestionsSvc: suggestionsMock,
+	})
+
+	suggestionsMock.EXPECT().Invalidate().Times(2)
+
+	require.NoError(t, srv.BulkSetCategory(context.Background(), []transactions.CategoryAssignment{
+		{TransactionID: txs[0].ID, CategoryID: lo.ToPtr(int32(42))},
+	}))
+	require.NoError(t, srv.BulkSetTags(context.Background(), []transactions.TagsAssignment{
+		{TransactionID: txs[0].ID, TagIDs: []int32{1}},
+	}))
+
+	// a failed update keeps the model
+	assert.Error(t, srv.BulkSetCategory(context.Background(), []transactions.CategoryAssignment{
+		{TransactionID: 999999, CategoryID: lo.ToPtr(int32(42))},
+	}))
+}
@@ -28,7 +28,7 @@ func newHistoryTestSvc(
 ) *transactions.Service {
 	t.Helper()
 
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 	baseCurrency := NewMockBaseAmountSvc(gomock.NewController(t))
 	ruleEngine := NewMockRuleSvc(gomock.NewController(t))
@@ -221,7 +221,7 @@ func TestCreate_NoActor_SkipsHistory_StillSucceeds(t *testing.T) {
 func TestCreate_RuleEventsDrained(t *testing.T) {
 	acc := seedAccount(t)
 
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 	baseCurrency := NewMockBaseAmountSvc(gomock.NewController(t))
 	ruleEngine := NewMockRuleSvc(gomock.NewController(t))
@@ -350,7 +350,7 @@ func TestCreateReconciliation(t *testing.T) {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 
 	baseCurrency := NewMockBaseAmountSvc(gomock.NewController(t))
@@ -440,7 +440,7 @@ func TestAndUpdateWithRuleExecuted(t *testing.T) {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 
 	baseCurrency := NewMockBaseAmountSvc(gomock.NewController(t))
@@ -529,7 +529,7 @@ func TestCreateBulk(t *testing.T) {
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
 
-	statsSvc := transactions.NewStatService()
+	statsSvc := transactions.NewStatService(&transactions.StatServiceConfig{})
 	mapper := NewMockMapperSvc(gomock.NewController(t))
 
 	baseCurrency := NewMockBaseAmountSvc(gomock.NewController(t))
@@ -1050,6 +1050,197 @@ func TestCreateRawTransaction(t *testing.T) {
 	})
 }
 
+func TestUpsertRawTransactions(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+	accounts := []*database.Account{
+		{
+			Name:     "Private [UAH]",
+			Currency: "UAH",
+			Extra:    map[string]string{},
+		},
+	}
+	assert.NoError(t, gormDB.Create(&accounts).Error)
+
+	t.Run("success", func(t *testing.T) {
+		existing := &database.Transaction{
+			TransactionType:      gomoneypbv1.TransactionType_TRANSACTION_TYPE_ADJUSTMENT,
+			DestinationAccountID: accounts[0].ID,
+			DestinationCurrency:  accounts[0].Currency,
+			DestinationAmount:    decimal.NewNullDecimal(decimal.NewFromInt(50)),
+			Title:                "before",
+			Extra:                map[string]string{},
+		}
+		assert.NoError(t, gormDB.Create(existing).Error)
+
+		statSvc := NewMockStatsSvc(gomock.NewController(t))
+		baseSvc := NewMockBaseAmountSvc(gomock.NewController(t))
+		mapper := NewMockMapperSvc(gomock.NewController(t))
+		accountSvc := NewMockAccountSvc(gomock.NewController(t))
+		validationSvc := NewMockValidationSvc(gomock.NewController(t))
+		doubleEntry := NewMockDoubleEntrySvc(gomock.NewController(t))
+
+		svc := transactions.NewService(&transactions.ServiceConfig{
+			StatsSvc:          statSvc,
+			BaseAmountService: baseSvc,
+			MapperSvc:         mapper,
+			AccountSvc:        accountSvc,
+			ValidationSvc:     validationSvc,
+			DoubleEntry:       doubleEntry,
+		})
+
+		accountSvc.EXPECT().GetAllAccounts(gomock.Any()).Return(accounts, nil)
+		validationSvc.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		doubleEntry.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil).AnyTimes()
+		baseSvc.EXPECT().RecalculateAmountInBaseCurrency(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+		statSvc.EXPECT().HandleTransactions(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *gorm.DB, txs []*database.Transaction) error {
+				assert.Len(t, txs, 3) // created, updated and original
+				return nil
+			})
+		mapper.EXPECT().MapTransaction(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, transaction *database.Transaction) *gomoneypbv1.Transaction {
+				return &gomoneypbv1.Transaction{Id: transaction.ID}
+			}).Times(2)
+
+		newTx := &database.Transaction{
+			TransactionType:      gomoneypbv1.TransactionType_TRANSACTION_TYPE_ADJUSTMENT,
+			DestinationAccountID: accounts[0].ID,
+			DestinationCurrency:  accounts[0].Currency,
+			DestinationAmount:    decimal.NewNullDecimal(decimal.NewFromInt(100)),
+		}
+		existing.Title = "after"
+
+		resp, err := svc.UpsertRawTransactions(context.TODO(), []*database.Transaction{newTx}, []*database.Transaction{existing})
+		assert.NoError(t, err)
+		assert.Len(t, resp, 2)
+		assert.NotEmpty(t, newTx.ID)
+
+		var stored database.Transaction
+		assert.NoError(t, gormDB.Where("id = ?", existing.ID).First(&stored).Error)
+		assert.Equal(t, "after", stored.Title)
+	})
+
+	t.Run("nothing to do", func(t *testing.T) {
+		svc := transactions.NewService(&transactions.ServiceConfig{})
+
+		resp, err := svc.UpsertRawTransactions(context.TODO(), nil, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("updated not found", func(t *testing.T) {
+		svc := transactions.NewService(&transactions.ServiceConfig{})
+
+		resp, err := svc.UpsertRawTransactions(context.TODO(), nil, []*database.Transaction{{ID: 999999}})
+		assert.ErrorContains(t, err, "some transactions to update were not found")
+		assert.Nil(t, resp)
+	})
+}
+
+func TestSetExchangeRates(t *testing.T) {
+	assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
+	accounts := []*database.Account{
+		{
+			Name:     "Private [PLN]",
+			Currency: "PLN",
+			Extra:    map[string]string{},
+		},
+	}
+	assert.NoError(t, gormDB.Create(&accounts).Error)
+
+	t.Run("success", func(t *testing.T) {
+		existing := &database.Transaction{
+			TransactionType:      gomoneypbv1.TransactionType_TRANSACTION_TYPE_ADJUSTMENT,
+			DestinationAccountID: accounts[0].ID,
+			DestinationCurrency:  accounts[0].Currency,
+			DestinationAmount:    decimal.NewNullDecimal(decimal.NewFromInt(50)),
+			ExchangeRate:         decimal.NewNullDecimal(decimal.NewFromInt(4)),
+			ExchangeRateSource:   database.ExchangeRateSourceSynced,
+			Extra:                map[string]string{},
+		}
+		assert.NoError(t, gormDB.Create(existing).Error)
+
+		statSvc := NewMockStatsSvc(gomock.NewController(t))
+		baseSvc := NewMockBaseAmountSvc(gomock.NewController(t))
+		mapper := NewMockMapperSvc(gomock.NewController(t))
+		accountSvc := NewMockAccountSvc(gomock.NewController(t))
+		validationSvc := NewMockValidationSvc(gomock.NewController(t))
+		doubleEntry := NewMockDoubleEntrySvc(gomock.NewController(t))
+
+		svc := transactions.NewService(&transactions.ServiceConfig{
+			StatsSvc:          statSvc,
+			BaseAmountService: baseSvc,
+			MapperSvc:         mapper,
+			AccountSvc:        accountSvc,
+			ValidationSvc:     validationSvc,
+			DoubleEntry:       doubleEntry,
+		})
+
+		accountSvc.EXPECT().GetAllAccounts(gomock.Any()).Return(accounts, nil)
+		validationSvc.EXPECT().Validate(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		doubleEntry.EXPECT().Record(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		statSvc.EXPECT().HandleTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		baseSvc.EXPECT().RecalculateAmountInBaseCurrency(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *gorm.DB, txs []*database.Transaction) error {
+				assert.Len(t, txs, 1)
+				assert.True(t, txs[0].ExchangeRateLocked)
+				return nil
+			})
+		mapper.EXPECT().MapTransaction(gomock.Any(), gomock.Any()).Return(&gomoneypbv1.Transaction{})
+
+		rate := decimal.RequireFromString("4.25")
+		updated, err := svc.SetExchangeRates(context.TODO(), []transactions.ExchangeRateAssignment{
+			{
+				TransactionID: existing.ID,
+				Rate:          &rate,
+				Locked:        true,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, updated, 1)
+
+		var stored database.Transaction
+		assert.NoError(t, gormDB.Where("id = ?", existing.ID).First(&stored).Error)
+		assert.True(t, stored.ExchangeRateLocked)
+		assert.Equal(t, "4.25", stored.ExchangeRate.Decimal.String())
+		assert.Equal(t, database.ExchangeRateSourceManual, stored.ExchangeRateSource)
+	})
+
+	t.Run("manual rate must be locked", func(t *testing.T) {
+		svc := transactions.NewService(&transactions.ServiceConfig{})
+
+		rate := decimal.NewFromInt(4)
+		updated, err := svc.SetExchangeRates(context.TODO(), []transactions.ExchangeRateAssignment{
+			{TransactionID: 1, Rate: &rate},
+		})
+		assert.ErrorContains(t, err, "must be locked")
+		assert.Nil(t, updated)
+	})
+
+	t.Run("invalid rate", func(t *testing.T) {
+		svc := transactions.NewService(&transactions.ServiceConfig{})
+
+		rate := decimal.Zero
+		updated, err := svc.SetExchangeRates(context.TODO(), []transactions.ExchangeRateAssignment{
+			{TransactionID: 1, Rate: &rate, Locked: true},
+		})
+		assert.ErrorContains(t, err, "must be positive")
+		assert.Nil(t, updated)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := transactions.NewService(&transactions.ServiceConfig{})
+
+		updated, err := svc.SetExchangeRates(context.TODO(), []transactions.ExchangeRateAssignment{
+			{TransactionID: 999999, Locked: true},
+		})
+		assert.ErrorContains(t, err, "transaction 999999 not found")
+		assert.Nil(t, updated)
+	})
+}
+
 func TestDeleteTransaction(t *testing.T) {
 	t.Run("success single", func(t *testing.T) {
 		assert.NoError(t, testingutils.FlushAllTables(cfg.Db))
@@ -8,6 +8,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/timezone"
 	"github.com/hashicorp/golang-lru/v2/expirable"
 	"gorm.io/gorm"
 )
@@ -17,11 +18,17 @@ var dailyRecalculate string
 
 type StatService struct {
 	noGapTillTime *expirable.LRU[string, time.Time]
+	cfg           *StatServiceConfig
 }
 
-func NewStatService() *StatService {
+type StatServiceConfig struct {
+	Location *time.Location // household timezone, daily_stat is generated up to its current date. UTC when nil
+}
+
+func NewStatService(cfg *StatServiceConfig) *StatService {
 	return &StatService{
 		noGapTillTime: expirable.NewLRU[string, time.Time](1000, nil, 10*time.Minute),
+		cfg:           cfg,
 	}
 }
 
@@ -38,12 +45,17 @@ func (s *StatService) BuildImpactedAccounts(newTxs []*database.Transaction) map[
 	impactedAccounts := map[int32]time.Time{} // tx with the lowest date
 
 	for _, newTx := range newTxs {
+		startAt := newTx.TransactionDateTime
+		if !newTx.TransactionDateOnly.IsZero() && newTx.TransactionDateOnly.Before(startAt) {
+			startAt = newTx.TransactionDateOnly // local date can be a day before the utc timestamp
+		}
+
 		for _, accountID := range s.getAccountsForTx(newTx) {
 			if rec, ok := impactedAccounts[accountID]; !ok {
-				impactedAccounts[accountID] = newTx.TransactionDateTime
+				impactedAccounts[accountID] = startAt
 			} else {
-				if rec.After(newTx.TransactionDateTime) {
-					impactedAccounts[accountID] = newTx.TransactionDateTime
+				if rec.After(startAt) {
+					impactedAccounts[accountID] = startAt
 				}
 			}
 		}
@@ -83,5 +95,6 @@ func (s *StatService) CalculateDailyStat(
 	return dbTx.Exec(dailyRecalculate,
 		sql.Named("startDate", req.StartDate),
 		sql.Named("accountID", req.AccountID),
+		sql.Named("today", timezone.Today(s.cfg.Location).Format(time.DateOnly)),
 	).Error
 }
@@ -11,7 +11,7 @@ import (
 )
 
 func TestHandleTransactionFail(t *testing.T) {
-	stat := transactions.NewStatService()
+	stat := transactions.NewStatService(&transactions.StatServiceConfig{})
 
 	ctx, cancel := context.WithCancel(context.TODO())
 	cancel()
@@ -26,7 +26,7 @@ func TestHandleTransactionFail(t *testing.T) {
 
 func TestBuildImpactedAccounts(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		stat := transactions.NewStatService()
+		stat := transactions.NewStatService(&transactions.StatServiceConfig{})
 
 		txs := []*database.Transaction{
 			{
@@ -46,10 +46,28 @@ func TestBuildImpactedAccounts(t *testing.T) {
 		assert.Len(t, impacted, 2)
 		assert.EqualValues(t, txs[1].TransactionDateTime, impacted[int32(1)])
 	})
+
+	t.Run("local date before utc timestamp", func(t *testing.T) {
+		stat := transactions.NewStatService(&transactions.StatServiceConfig{})
+
+		txs := []*database.Transaction{
+			{
+				TransactionDateTime:  time.Date(2026, 3, 2, 3, 30, 0, 0, time.UTC), // 2026-03-01 22:30 in New York
+				TransactionDateOnly:  time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+				SourceAccountID:      1,
+				DestinationAccountID: 2,
+			},
+		}
+
+		impacted := stat.BuildImpactedAccounts(txs)
+
+		assert.Equal(t, txs[0].TransactionDateOnly, impacted[int32(1)])
+		assert.Equal(t, txs[0].TransactionDateOnly, impacted[int32(2)])
+	})
 }
 
 func TestNoData(t *testing.T) {
-	stat := transactions.NewStatService()
+	stat := transactions.NewStatService(&transactions.StatServiceConfig{})
 
 	ctx, cancel := context.WithCancel(context.TODO())
 	cancel()
@@ -3,7 +3,11 @@ package transactions
 import (
 	"time"
 
+	transactionsv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/transactions/v1"
+	gomoneypbv1 "buf.build/gen/go/xskydev/go-money-pb/protocolbuffers/go/gomoneypb/v1"
+
 	"github.com/ft-t/go-money/pkg/database"
+	"github.com/shopspring/decimal"
 )
 
 type FillResponse struct {
@@ -29,3 +33,41 @@ type TagsAssignment struct {
 	TransactionID int64
 	TagIDs        []int32 // nil or empty clears all tags
 }
+
+type ExchangeRateAssignment struct {
+	TransactionID int64
+	Rate          *decimal.Decimal // source currency units per 1 base unit; nil keeps the current rate
+	Locked        bool             // must be true when Rate is set
+}
+
+// SortField values are the values of transactionsv1.SortField: ListTransactions passes the api
+// field through, so new api sort fields must keep these numbers.
+type SortField int32
+
+const (
+	SortFieldTransactionDate SortField = iota + 1
+	SortFieldCreatedAt
+	SortFieldAmount     // destination amount, or absolute source amount when there is none
+	SortFieldBaseAmount // same in base currency
+	SortFieldTitle
+	SortFieldCategory // category name, transactions without category sort as empty name
+	SortFieldAccount  // source account name
+)
+
+type Sort struct {
+	Field     SortField
+	Ascending bool
+}
+
+type ListRequest struct {
+	Filter       *transactionsv1.ListTransactionsRequest // filters, Limit and Skip; Sort is ignored
+	Sort         []*Sort                                 // newest transaction date first when empty
+	Cursor       string                                  // NextCursor of the previous page, Skip is ignored when set
+	IncludeCount bool
+}
+
+type ListResponse struct {
+	Transactions []*gomoneypbv1.Transaction
+	NextCursor   string // empty on the last page
+	TotalCount   *int64 // set when IncludeCount was requested
+}
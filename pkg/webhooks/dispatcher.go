@@ -0,0 +1,328 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/rs/zerolog"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxErrorBodyBytes = 1024
+
+// Run dispatches events until the context is cancelled. Replicas can run it side by side,
+// webhooks and deliveries are claimed with SKIP LOCKED.
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(lo.CoalesceOrEmpty(s.cfg.PollInterval, defaultPollInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Dispatch(ctx); err != nil && ctx.Err() == nil {
+				zerolog.Ctx(ctx).Err(err).Msg("failed to dispatch webhooks")
+			}
+		}
+	}
+}
+
+// Dispatch runs one round: new history events become pending deliveries, then due deliveries are sent.
+func (s *Service) Dispatch(ctx context.Context) error {
+	if err := s.enqueue(ctx); err != nil {
+		return err
+	}
+
+	return s.deliver(ctx)
+}
+
+// Ping sends a ping event to the webhook right away, regardless of whether it is enabled.
+// Pings are logged as deliveries but never retried.
+func (s *Service) Ping(ctx context.Context, id int32) (*database.WebhookDelivery, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	hook, err := s.getWebhook(db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := toPayload(&Event{
+		WebhookID:  hook.ID,
+		Event:      EventPing,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &database.WebhookDelivery{
+		WebhookID:     hook.ID,
+		Payload:       payload,
+		Status:        database.WebhookDeliveryStatusPending,
+		NextAttemptAt: time.Now().UTC(),
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if err = db.Create(delivery).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to create delivery")
+	}
+
+	s.attempt(ctx, hook, delivery, 1)
+
+	if err = db.Save(delivery).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to update delivery")
+	}
+
+	return delivery, nil
+}
+
+func (s *Service) enqueue(ctx context.Context) error {
+	return database.GetDbWithContext(ctx, database.DbTypeMaster).Transaction(func(tx *gorm.DB) error {
+		var hooks []*database.Webhook
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("enabled").
+			Order("id").
+			Find(&hooks).Error; err != nil {
+			return errors.Wrap(err, "failed to lock webhooks")
+		}
+
+		for _, hook := range hooks {
+			rows, err := s.cfg.FeedSvc.List(ctx, &history.FeedRequest{
+				AfterID: hook.LastFeedID,
+				Limit:   lo.CoalesceOrEmpty(s.cfg.BatchSize, defaultBatchSize),
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to read history")
+			}
+
+			if len(rows) == 0 {
+				continue
+			}
+
+			var deliveries []*database.WebhookDelivery
+
+			for _, row := range rows {
+				if len(hook.EventTypes) > 0 && !lo.Contains(hook.EventTypes, int32(row.EventType)) {
+					continue
+				}
+
+				payload, payloadErr := toPayload(newEvent(hook.ID, row))
+				if payloadErr != nil {
+					return payloadErr
+				}
+
+				deliveries = append(deliveries, &database.WebhookDelivery{
+					WebhookID:     hook.ID,
+					HistoryID:     row.ID,
+					EventType:     row.EventType,
+					Payload:       payload,
+					Status:        database.WebhookDeliveryStatusPending,
+					NextAttemptAt: time.Now().UTC(),
+					CreatedAt:     time.Now().UTC(),
+				})
+			}
+
+			if len(deliveries) > 0 {
+				if err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error; err != nil {
+					return errors.Wrap(err, "failed to create deliveries")
+				}
+			}
+
+			// the cursor moves past filtered out events too, so they are not read again
+			if err = tx.Model(hook).Update("last_feed_id", rows[len(rows)-1].FeedID).Error; err != nil {
+				return errors.Wrap(err, "failed to move webhook cursor")
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Service) deliver(ctx context.Context) error {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+	requestTimeout := lo.CoalesceOrEmpty(s.cfg.RequestTimeout, defaultRequestTimeout)
+
+	var deliveries []*database.WebhookDelivery
+
+	// claimed rows are leased by moving next_attempt_at, so other replicas skip them while they are sent
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(`SELECT d.* FROM webhook_deliveries d
+				JOIN webhooks w ON w.id = d.webhook_id AND w.enabled AND w.deleted_at IS NULL
+				WHERE d.status = ? AND d.next_attempt_at <= ?
+				ORDER BY d.id
+				LIMIT ?
+				FOR UPDATE OF d SKIP LOCKED`,
+			database.WebhookDeliveryStatusPending,
+			time.Now().UTC(),
+			lo.CoalesceOrEmpty(s.cfg.BatchSize, defaultBatchSize),
+		).Scan(&deliveries).Error; err != nil {
+			return errors.Wrap(err, "failed to claim deliveries")
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		return errors.Wrap(tx.Model(&database.WebhookDelivery{}).
+			Where("id IN ?", lo.Map(deliveries, func(d *database.WebhookDelivery, _ int) int64 {
+				return d.ID
+			})).
+			Update("next_attempt_at", time.Now().UTC().Add(time.Duration(len(deliveries)+1)*requestTimeout)).Error,
+			"failed to lease deliveries")
+	})
+	if err != nil || len(deliveries) == 0 {
+		return err
+	}
+
+	var hooks []*database.Webhook
+	if err = db.Where("id IN ?", lo.Uniq(lo.Map(deliveries, func(d *database.WebhookDelivery, _ int) int32 {
+		return d.WebhookID
+	}))).Find(&hooks).Error; err != nil {
+		return errors.Wrap(err, "failed to get webhooks")
+	}
+
+	hooksByID := lo.KeyBy(hooks, func(hook *database.Webhook) int32 {
+		return hook.ID
+	})
+
+	for _, delivery := range deliveries {
+		hook, ok := hooksByID[delivery.WebhookID]
+		if !ok {
+			continue // deleted after the claim, the delete failed the delivery
+		}
+
+		s.attempt(ctx, hook, delivery, lo.CoalesceOrEmpty(s.cfg.MaxAttempts, defaultMaxAttempts))
+
+		if err = db.Save(delivery).Error; err != nil {
+			return errors.Wrap(err, "failed to update delivery")
+		}
+	}
+
+	return nil
+}
+
+// attempt sends the delivery once and records the outcome on it, the caller saves it.
+func (s *Service) attempt(ctx context.Context, hook *database.Webhook, delivery *database.WebhookDelivery, maxAttempts int) {
+	started := time.Now()
+
+	code, sendErr := s.send(ctx, hook, delivery)
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = lo.ToPtr(started.UTC())
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	delivery.ResponseCode = nil
+	delivery.Error = nil
+
+	if code != 0 {
+		delivery.ResponseCode = lo.ToPtr(int32(code))
+	}
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = database.WebhookDeliveryStatusDelivered
+		delivery.DeliveredAt = lo.ToPtr(time.Now().UTC())
+	case int(delivery.Attempts) >= maxAttempts:
+		delivery.Status = database.WebhookDeliveryStatusFailed
+		delivery.Error = lo.ToPtr(sendErr.Error())
+	default:
+		delivery.Error = lo.ToPtr(sendErr.Error())
+		delivery.NextAttemptAt = time.Now().UTC().Add(s.backoff(delivery.Attempts))
+	}
+}
+
+func (s *Service) send(ctx context.Context, hook *database.Webhook, delivery *database.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to marshal payload")
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, lo.CoalesceOrEmpty(s.cfg.RequestTimeout, defaultRequestTimeout))
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create request")
+	}
+
+	timestamp := time.Now().Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-money-webhooks")
+	req.Header.Set(HeaderEvent, fmt.Sprint(delivery.Payload["event"]))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, body))
+
+	resp, err := s.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "request failed")
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+
+	return resp.StatusCode, errors.Newf("unexpected status code %d: %s", resp.StatusCode, respBody)
+}
+
+// backoff doubles the delay for every failed attempt: base, 2*base, 4*base, ... up to BackoffMax.
+func (s *Service) backoff(attempts int32) time.Duration {
+	delay := lo.CoalesceOrEmpty(s.cfg.BackoffBase, defaultBackoffBase)
+	maxDelay := lo.CoalesceOrEmpty(s.cfg.BackoffMax, defaultBackoffMax)
+
+	for i := int32(1); i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxDelay)
+}
+
+func newEvent(webhookID int32, row *database.TransactionHistory) *Event {
+	return &Event{
+		WebhookID:           webhookID,
+		Event:               EventTypeNames[row.EventType],
+		HistoryID:           row.ID,
+		TransactionID:       row.TransactionID,
+		ActorType:           actorTypeNames[row.ActorType],
+		ActorUserID:         row.ActorUserID,
+		ActorRuleID:         row.ActorRuleID,
+		ActorRuleRevisionID: row.ActorRuleRevisionID,
+		ActorExtra:          row.ActorExtra,
+		Snapshot:            row.Snapshot,
+		Diff:                row.Diff,
+		OccurredAt:          row.OccurredAt.UTC(),
+	}
+}
+
+func toPayload(event *Event) (map[string]any, error) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal event")
+	}
+
+	var payload map[string]any
+	if err = json.Unmarshal(raw, &payload); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal event")
+	}
+
+	return payload, nil
+}
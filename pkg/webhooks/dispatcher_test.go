@@ -0,0 +1,249 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+	"github.com/ft-t/go-money/pkg/webhooks"
+)
+
+const testSecret = "0123456789abcdef"
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver is a local webhook endpoint answering with the given status codes in order, the last one repeats.
+func receiver(t *testing.T, codes ...int) (*httptest.Server, func() []*receivedRequest) {
+	var mut sync.Mutex
+	var received []*receivedRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mut.Lock()
+		received = append(received, &receivedRequest{header: r.Header.Clone(), body: body})
+		code := codes[min(len(received), len(codes))-1]
+		mut.Unlock()
+
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte("receiver says hi"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []*receivedRequest {
+		mut.Lock()
+		defer mut.Unlock()
+
+		return received
+	}
+}
+
+func createHook(t *testing.T, url string, eventTypes ...int32) *database.Webhook {
+	hook := &database.Webhook{Name: "test", URL: url, Secret: testSecret, EventTypes: eventTypes, Enabled: true}
+	require.NoError(t, gormDB.Create(hook).Error)
+
+	return hook
+}
+
+func historyRows() []*database.TransactionHistory {
+	userID := int32(7)
+
+	return []*database.TransactionHistory{
+		{
+			ID:            11,
+			FeedID:        lo.ToPtr(int64(11)),
+			TransactionID: 100,
+			EventType:     database.TransactionHistoryEventTypeCreated,
+			ActorType:     database.TransactionHistoryActorTypeUser,
+			ActorUserID:   &userID,
+			Snapshot:      map[string]any{"title": "Coffee"},
+			OccurredAt:    time.Date(2026, 8, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:            12,
+			FeedID:        lo.ToPtr(int64(12)),
+			TransactionID: 100,
+			EventType:     database.TransactionHistoryEventTypeUpdated,
+			ActorType:     database.TransactionHistoryActorTypeRule,
+			Snapshot:      map[string]any{"title": "Coffee shop"},
+			Diff:          map[string]any{"ops": []any{}},
+			OccurredAt:    time.Date(2026, 8, 1, 10, 0, 1, 0, time.UTC),
+		},
+	}
+}
+
+func TestService_Dispatch(t *testing.T) {
+	t.Run("delivers signed events", func(t *testing.T) {
+		require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		server, received := receiver(t, http.StatusOK)
+		hook := createHook(t, server.URL)
+
+		feed := NewMockFeedSvc(gomock.NewController(t))
+		feed.EXPECT().List(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, req *history.FeedRequest) ([]*database.TransactionHistory, error) {
+				assert.EqualValues(t, 0, req.AfterID)
+				return historyRows(), nil
+			})
+
+		svc := webhooks.NewService(&webhooks.ServiceConfig{FeedSvc: feed, HTTPClient: http.DefaultClient})
+		require.NoError(t, svc.Dispatch(context.TODO()))
+
+		requests := received()
+		require.Len(t, requests, 2)
+
+		first := requests[0]
+		timestamp, err := strconv.ParseInt(first.header.Get(webhooks.HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, webhooks.Sign(testSecret, timestamp, first.body), first.header.Get(webhooks.HeaderSignature))
+		assert.Equal(t, "created", first.header.Get(webhooks.HeaderEvent))
+		assert.NotEmpty(t, first.header.Get(webhooks.HeaderDelivery))
+
+		var event webhooks.Event
+		require.NoError(t, json.Unmarshal(first.body, &event))
+		assert.Equal(t, hook.ID, event.WebhookID)
+		assert.EqualValues(t, 11, event.HistoryID)
+		assert.EqualValues(t, 100, event.TransactionID)
+		assert.Equal(t, "user", event.ActorType)
+		assert.EqualValues(t, 7, *event.ActorUserID)
+		assert.Equal(t, "Coffee", event.Snapshot["title"])
+
+		var deliveries []*database.WebhookDelivery
+		require.NoError(t, gormDB.Order("id").Find(&deliveries).Error)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, database.WebhookDeliveryStatusDelivered, deliveries[0].Status)
+		assert.EqualValues(t, 200, *deliveries[0].ResponseCode)
+		assert.NotNil(t, deliveries[0].DeliveredAt)
+
+		var stored database.Webhook
+		require.NoError(t, gormDB.First(&stored, hook.ID).Error)
+		assert.EqualValues(t, 12, stored.LastFeedID)
+	})
+
+	t.Run("event type filter moves the cursor", func(t *testing.T) {
+		require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		server, received := receiver(t, http.StatusNoContent)
+		hook := createHook(t, server.URL, int32(database.TransactionHistoryEventTypeUpdated))
+
+		feed := NewMockFeedSvc(gomock.NewController(t))
+		feed.EXPECT().List(gomock.Any(), gomock.Any()).Return(historyRows(), nil)
+
+		svc := webhooks.NewService(&webhooks.ServiceConfig{FeedSvc: feed, HTTPClient: http.DefaultClient})
+		require.NoError(t, svc.Dispatch(context.TODO()))
+
+		requests := received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "updated", requests[0].header.Get(webhooks.HeaderEvent))
+
+		var stored database.Webhook
+		require.NoError(t, gormDB.First(&stored, hook.ID).Error)
+		assert.EqualValues(t, 12, stored.LastFeedID)
+	})
+
+	t.Run("retries with backoff until failed", func(t *testing.T) {
+		require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		server, received := receiver(t, http.StatusInternalServerError)
+		createHook(t, server.URL, int32(database.TransactionHistoryEventTypeCreated))
+
+		feed := NewMockFeedSvc(gomock.NewController(t))
+		feed.EXPECT().List(gomock.Any(), gomock.Any()).Return(historyRows(), nil)
+		feed.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+		svc := webhooks.NewService(&webhooks.ServiceConfig{
+			FeedSvc:     feed,
+			HTTPClient:  http.DefaultClient,
+			MaxAttempts: 2,
+			BackoffBase: time.Minute,
+		})
+		require.NoError(t, svc.Dispatch(context.TODO()))
+
+		var delivery database.WebhookDelivery
+		require.NoError(t, gormDB.First(&delivery).Error)
+		assert.Equal(t, database.WebhookDeliveryStatusPending, delivery.Status)
+		assert.EqualValues(t, 1, delivery.Attempts)
+		assert.EqualValues(t, 500, *delivery.ResponseCode)
+		assert.Contains(t, *delivery.Error, "unexpected status code 500: receiver says hi")
+		assert.WithinDuration(t, time.Now().UTC().Add(time.Minute), delivery.NextAttemptAt, 10*time.Second)
+
+		require.NoError(t, svc.Dispatch(context.TODO())) // not due yet
+		assert.Len(t, received(), 1)
+
+		require.NoError(t, gormDB.Model(&delivery).Update("next_attempt_at", time.Now().UTC().Add(-time.Second)).Error)
+		require.NoError(t, svc.Dispatch(context.TODO()))
+		assert.Len(t, received(), 2)
+
+		require.NoError(t, gormDB.First(&delivery).Error)
+		assert.Equal(t, database.WebhookDeliveryStatusFailed, delivery.Status)
+		assert.EqualValues(t, 2, delivery.Attempts)
+	})
+
+	t.Run("disabled webhook is skipped", func(t *testing.T) {
+		require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		hook := createHook(t, "http://127.0.0.1:1")
+		require.NoError(t, gormDB.Model(hook).Update("enabled", false).Error)
+
+		svc := webhooks.NewService(&webhooks.ServiceConfig{FeedSvc: NewMockFeedSvc(gomock.NewController(t))})
+		require.NoError(t, svc.Dispatch(context.TODO()))
+	})
+}
+
+func TestService_Ping(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		server, received := receiver(t, http.StatusAccepted)
+		hook := createHook(t, server.URL)
+
+		svc := webhooks.NewService(&webhooks.ServiceConfig{HTTPClient: http.DefaultClient})
+
+		delivery, err := svc.Ping(context.TODO(), hook.ID)
+		require.NoError(t, err)
+		assert.Equal(t, database.WebhookDeliveryStatusDelivered, delivery.Status)
+		assert.EqualValues(t, 202, *delivery.ResponseCode)
+
+		requests := received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, webhooks.EventPing, requests[0].header.Get(webhooks.HeaderEvent))
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		hook := createHook(t, "http://127.0.0.1:1")
+
+		svc := webhooks.NewService(&webhooks.ServiceConfig{HTTPClient: http.DefaultClient})
+
+		delivery, err := svc.Ping(context.TODO(), hook.ID)
+		require.NoError(t, err)
+		assert.Equal(t, database.WebhookDeliveryStatusFailed, delivery.Status) // pings are not retried
+		assert.Nil(t, delivery.ResponseCode)
+		assert.Contains(t, *delivery.Error, "request failed")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		_, err := webhooks.NewService(&webhooks.ServiceConfig{}).Ping(context.TODO(), 5)
+		assert.ErrorContains(t, err, "webhook 5 not found")
+	})
+}
@@ -0,0 +1,20 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/transactions/history"
+)
+
+//go:generate mockgen -destination interfaces_mocks_test.go -package webhooks_test -source=interfaces.go
+
+type FeedSvc interface {
+	List(ctx context.Context, req *history.FeedRequest) ([]*database.TransactionHistory, error)
+	LatestID(ctx context.Context) (int64, error)
+}
+
+type httpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
@@ -0,0 +1,256 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultBatchSize      = 100
+	defaultMaxAttempts    = 8
+	defaultBackoffBase    = 30 * time.Second
+	defaultBackoffMax     = time.Hour
+	defaultRequestTimeout = 10 * time.Second
+	defaultPollInterval   = 5 * time.Second
+	defaultListLimit      = 50
+
+	minSecretLength = 16
+)
+
+type ServiceConfig struct {
+	FeedSvc        FeedSvc
+	HTTPClient     httpClient
+	BatchSize      int           // events enqueued per webhook and deliveries sent per round
+	MaxAttempts    int           // a delivery is failed after this many attempts
+	BackoffBase    time.Duration // delay before the first retry, doubled for every next one
+	BackoffMax     time.Duration
+	RequestTimeout time.Duration
+	PollInterval   time.Duration // how often Run looks for new events and due retries
+}
+
+// Service manages webhooks and delivers transaction history events to them.
+type Service struct {
+	cfg *ServiceConfig
+}
+
+func NewService(cfg *ServiceConfig) *Service {
+	return &Service{cfg: cfg}
+}
+
+func (s *Service) ListWebhooks(ctx context.Context) ([]*database.Webhook, error) {
+	var hooks []*database.Webhook
+
+	if err := database.GetDbWithContext(ctx, database.DbTypeReadonly).Order("id").Find(&hooks).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return hooks, nil
+}
+
+// CreateWebhook stores a webhook which receives events recorded from now on, existing history
+// is not sent.
+func (s *Service) CreateWebhook(ctx context.Context, req *CreateWebhookRequest) (*database.Webhook, error) {
+	secret := req.Secret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, errors.Wrap(err, "failed to generate secret")
+		}
+
+		secret = hex.EncodeToString(buf)
+	}
+
+	hook := &database.Webhook{
+		Name:       strings.TrimSpace(req.Name),
+		URL:        strings.TrimSpace(req.URL),
+		Secret:     secret,
+		EventTypes: toInt32Array(req.EventTypes),
+		Enabled:    !req.Disabled,
+	}
+
+	if err := validateWebhook(hook); err != nil {
+		return nil, err
+	}
+
+	lastID, err := s.cfg.FeedSvc.LatestID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest feed id")
+	}
+
+	hook.LastFeedID = lastID
+
+	if err = database.GetDbWithContext(ctx, database.DbTypeMaster).Create(hook).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to create webhook")
+	}
+
+	return hook, nil
+}
+
+func (s *Service) UpdateWebhook(ctx context.Context, req *UpdateWebhookRequest) (*database.Webhook, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	hook, err := s.getWebhook(db, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		hook.Name = strings.TrimSpace(*req.Name)
+	}
+
+	if req.URL != nil {
+		hook.URL = strings.TrimSpace(*req.URL)
+	}
+
+	if req.Secret != nil {
+		hook.Secret = *req.Secret
+	}
+
+	if req.EventTypes != nil {
+		hook.EventTypes = toInt32Array(*req.EventTypes)
+	}
+
+	if req.Enabled != nil {
+		hook.Enabled = *req.Enabled
+	}
+
+	if err = validateWebhook(hook); err != nil {
+		return nil, err
+	}
+
+	if err = db.Save(hook).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to update webhook")
+	}
+
+	return hook, nil
+}
+
+// DeleteWebhook soft deletes the webhook and fails its pending deliveries.
+func (s *Service) DeleteWebhook(ctx context.Context, id int32) (*database.Webhook, error) {
+	var hook *database.Webhook
+
+	err := database.GetDbWithContext(ctx, database.DbTypeMaster).Transaction(func(tx *gorm.DB) error {
+		var err error
+
+		if hook, err = s.getWebhook(tx, id); err != nil {
+			return err
+		}
+
+		if err = tx.Delete(hook).Error; err != nil {
+			return errors.Wrap(err, "failed to delete webhook")
+		}
+
+		return errors.Wrap(tx.Model(&database.WebhookDelivery{}).
+			Where("webhook_id = ? AND status = ?", id, database.WebhookDeliveryStatusPending).
+			Updates(map[string]any{
+				"status": database.WebhookDeliveryStatusFailed,
+				"error":  "webhook deleted",
+			}).Error, "failed to cancel pending deliveries")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hook, nil
+}
+
+// ListDeliveries returns the delivery log, newest first.
+func (s *Service) ListDeliveries(ctx context.Context, req *ListDeliveriesRequest) ([]*database.WebhookDelivery, error) {
+	query := database.GetDbWithContext(ctx, database.DbTypeReadonly).Model(&database.WebhookDelivery{})
+
+	if req.WebhookID != nil {
+		query = query.Where("webhook_id = ?", *req.WebhookID)
+	}
+
+	if len(req.Statuses) > 0 {
+		query = query.Where("status IN ?", req.Statuses)
+	}
+
+	var deliveries []*database.WebhookDelivery
+	if err := query.Order("id DESC").Limit(lo.CoalesceOrEmpty(req.Limit, defaultListLimit)).Find(&deliveries).Error; err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return deliveries, nil
+}
+
+// RetryDelivery queues a delivery again with a fresh attempt budget, e.g. after the receiver was fixed.
+func (s *Service) RetryDelivery(ctx context.Context, id int64) (*database.WebhookDelivery, error) {
+	db := database.GetDbWithContext(ctx, database.DbTypeMaster)
+
+	var delivery database.WebhookDelivery
+	if err := db.Where("id = ?", id).First(&delivery).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Newf("delivery %d not found", id)
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	if _, err := s.getWebhook(db, delivery.WebhookID); err != nil {
+		return nil, err
+	}
+
+	delivery.Status = database.WebhookDeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+
+	if err := db.Save(&delivery).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to update delivery")
+	}
+
+	return &delivery, nil
+}
+
+func (s *Service) getWebhook(db *gorm.DB, id int32) (*database.Webhook, error) {
+	var hook database.Webhook
+
+	if err := db.Where("id = ?", id).First(&hook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.Newf("webhook %d not found", id)
+		}
+
+		return nil, errors.WithStack(err)
+	}
+
+	return &hook, nil
+}
+
+func validateWebhook(hook *database.Webhook) error {
+	if hook.Name == "" {
+		return errors.New("name is required")
+	}
+
+	parsed, err := url.Parse(hook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.Newf("url must be an absolute http or https url: %q", hook.URL)
+	}
+
+	if len(hook.Secret) < minSecretLength {
+		return errors.Newf("secret must be at least %d characters", minSecretLength)
+	}
+
+	for _, eventType := range hook.EventTypes {
+		if _, ok := EventTypeNames[database.TransactionHistoryEventType(eventType)]; !ok {
+			return errors.Newf("unknown event type %d", eventType)
+		}
+	}
+
+	return nil
+}
+
+func toInt32Array(eventTypes []database.TransactionHistoryEventType) pq.Int32Array {
+	return lo.Map(eventTypes, func(t database.TransactionHistoryEventType, _ int) int32 {
+		return int32(t)
+	})
+}
@@ -0,0 +1,170 @@
+package webhooks_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/ft-t/go-money/pkg/configuration"
+	"github.com/ft-t/go-money/pkg/database"
+	"github.com/ft-t/go-money/pkg/testingutils"
+	"github.com/ft-t/go-money/pkg/webhooks"
+)
+
+var (
+	cfg    *configuration.Configuration
+	gormDB *gorm.DB
+)
+
+func TestMain(m *testing.M) {
+	cfg = configuration.GetConfiguration()
+	gormDB = database.GetDb(database.DbTypeMaster)
+	os.Exit(m.Run())
+}
+
+func TestService_CreateWebhook(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+		feed := NewMockFeedSvc(gomock.NewController(t))
+		feed.EXPECT().LatestID(gomock.Any()).Return(int64(42), nil)
+
+		svc := webhooks.NewService(&webhooks.ServiceConfig{FeedSvc: feed})
+
+		hook, err := svc.CreateWebhook(context.TODO(), &webhooks.CreateWebhookRequest{
+			Name:       " Home Assistant ",
+			URL:        "http://127.0.0.1:8123/api/webhook/money",
+			EventTypes: []database.TransactionHistoryEventType{database.TransactionHistoryEventTypeCreated},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Home Assistant", hook.Name)
+		assert.Len(t, hook.Secret, 64) // generated
+		assert.True(t, hook.Enabled)
+		assert.EqualValues(t, 42, hook.LastFeedID) // existing history is not sent
+
+		var stored database.Webhook
+		require.NoError(t, gormDB.First(&stored, hook.ID).Error)
+		assert.EqualValues(t, []int32{1}, stored.EventTypes)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		svc := webhooks.NewService(&webhooks.ServiceConfig{FeedSvc: NewMockFeedSvc(gomock.NewController(t))})
+
+		for name, c := range map[string]struct {
+			req      *webhooks.CreateWebhookRequest
+			expected string
+		}{
+			"name":       {req: &webhooks.CreateWebhookRequest{URL: "https://example.com"}, expected: "name is required"},
+			"url":        {req: &webhooks.CreateWebhookRequest{Name: "a", URL: "ftp://example.com"}, expected: "url must be an absolute http or https url"},
+			"secret":     {req: &webhooks.CreateWebhookRequest{Name: "a", URL: "https://example.com", Secret: "short"}, expected: "secret must be at least 16 characters"},
+			"event type": {req: &webhooks.CreateWebhookRequest{Name: "a", URL: "https://example.com", EventTypes: []database.TransactionHistoryEventType{9}}, expected: "unknown event type 9"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := svc.CreateWebhook(context.TODO(), c.req)
+				assert.ErrorContains(t, err, c.expected)
+			})
+		}
+	})
+}
+
+func TestService_UpdateAndDeleteWebhook(t *testing.T) {
+	require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	hook := &database.Webhook{Name: "a", URL: "https://example.com", Secret: "0123456789abcdef", Enabled: true}
+	require.NoError(t, gormDB.Create(hook).Error)
+
+	pending := &database.WebhookDelivery{
+		WebhookID: hook.ID,
+		HistoryID: 1,
+		Payload:   map[string]any{"event": "created"},
+		Status:    database.WebhookDeliveryStatusPending,
+	}
+	require.NoError(t, gormDB.Create(pending).Error)
+
+	svc := webhooks.NewService(&webhooks.ServiceConfig{})
+
+	t.Run("update", func(t *testing.T) {
+		updated, err := svc.UpdateWebhook(context.TODO(), &webhooks.UpdateWebhookRequest{
+			ID:         hook.ID,
+			URL:        lo.ToPtr("https://example.com/hook"),
+			EventTypes: &[]database.TransactionHistoryEventType{},
+			Enabled:    lo.ToPtr(false),
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "https://example.com/hook", updated.URL)
+		assert.Equal(t, "a", updated.Name)
+		assert.False(t, updated.Enabled)
+	})
+
+	t.Run("update invalid", func(t *testing.T) {
+		_, err := svc.UpdateWebhook(context.TODO(), &webhooks.UpdateWebhookRequest{ID: hook.ID, Secret: lo.ToPtr("x")})
+		assert.ErrorContains(t, err, "secret must be at least 16 characters")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		_, err := svc.DeleteWebhook(context.TODO(), hook.ID)
+		require.NoError(t, err)
+
+		var delivery database.WebhookDelivery
+		require.NoError(t, gormDB.First(&delivery, pending.ID).Error)
+		assert.Equal(t, database.WebhookDeliveryStatusFailed, delivery.Status)
+		assert.Equal(t, "webhook deleted", *delivery.Error)
+
+		hooks, err := svc.ListWebhooks(context.TODO())
+		require.NoError(t, err)
+		assert.Empty(t, hooks)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := svc.DeleteWebhook(context.TODO(), hook.ID)
+		assert.ErrorContains(t, err, "not found")
+	})
+}
+
+func TestService_ListAndRetryDeliveries(t *testing.T) {
+	require.NoError(t, testingutils.FlushAllTables(cfg.Db))
+
+	hook := &database.Webhook{Name: "a", URL: "https://example.com", Secret: "0123456789abcdef", Enabled: true}
+	require.NoError(t, gormDB.Create(hook).Error)
+
+	failed := &database.WebhookDelivery{
+		WebhookID: hook.ID,
+		HistoryID: 1,
+		Payload:   map[string]any{"event": "created"},
+		Status:    database.WebhookDeliveryStatusFailed,
+		Attempts:  8,
+	}
+	delivered := &database.WebhookDelivery{
+		WebhookID: hook.ID,
+		HistoryID: 2,
+		Payload:   map[string]any{"event": "updated"},
+		Status:    database.WebhookDeliveryStatusDelivered,
+	}
+	require.NoError(t, gormDB.Create([]*database.WebhookDelivery{failed, delivered}).Error)
+
+	svc := webhooks.NewService(&webhooks.ServiceConfig{})
+
+	deliveries, err := svc.ListDeliveries(context.TODO(), &webhooks.ListDeliveriesRequest{
+		WebhookID: lo.ToPtr(hook.ID),
+		Statuses:  []database.WebhookDeliveryStatus{database.WebhookDeliveryStatusFailed},
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, failed.ID, deliveries[0].ID)
+
+	retried, err := svc.RetryDelivery(context.TODO(), failed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, database.WebhookDeliveryStatusPending, retried.Status)
+	assert.EqualValues(t, 0, retried.Attempts)
+
+	_, err = svc.RetryDelivery(context.TODO(), 999)
+	assert.ErrorContains(t, err, "delivery 999 not found")
+}
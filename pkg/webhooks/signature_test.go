@@ -0,0 +1,23 @@
+package webhooks_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ft-t/go-money/pkg/webhooks"
+)
+
+func TestSign(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte("0123456789abcdef"))
+	mac.Write([]byte(`1767225600.{"event":"ping"}`))
+
+	signature := webhooks.Sign("0123456789abcdef", 1767225600, []byte(`{"event":"ping"}`))
+
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), signature)
+	assert.NotEqual(t, signature, webhooks.Sign("0123456789abcdef", 1767225601, []byte(`{"event":"ping"}`)))
+	assert.NotEqual(t, signature, webhooks.Sign("another secret!!", 1767225600, []byte(`{"event":"ping"}`)))
+}
@@ -0,0 +1,72 @@
+package webhooks
+
+import (
+	"time"
+
+	"github.com/ft-t/go-money/pkg/database"
+)
+
+const (
+	HeaderSignature = "X-Go-Money-Signature" // sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+	HeaderTimestamp = "X-Go-Money-Timestamp" // unix seconds of the attempt
+	HeaderEvent     = "X-Go-Money-Event"
+	HeaderDelivery  = "X-Go-Money-Delivery"
+
+	EventPing = "ping"
+)
+
+var EventTypeNames = map[database.TransactionHistoryEventType]string{
+	database.TransactionHistoryEventTypeCreated:     "created",
+	database.TransactionHistoryEventTypeUpdated:     "updated",
+	database.TransactionHistoryEventTypeDeleted:     "deleted",
+	database.TransactionHistoryEventTypeRuleApplied: "rule_applied",
+}
+
+var actorTypeNames = map[database.TransactionHistoryActorType]string{
+	database.TransactionHistoryActorTypeUser:      "user",
+	database.TransactionHistoryActorTypeRule:      "rule",
+	database.TransactionHistoryActorTypeScheduler: "scheduler",
+	database.TransactionHistoryActorTypeImporter:  "importer",
+	database.TransactionHistoryActorTypeBulk:      "bulk",
+	database.TransactionHistoryActorTypeMcp:       "mcp",
+}
+
+type CreateWebhookRequest struct {
+	Name       string
+	URL        string
+	Secret     string // generated when empty
+	EventTypes []database.TransactionHistoryEventType
+	Disabled   bool
+}
+
+// UpdateWebhookRequest changes the fields that are set.
+type UpdateWebhookRequest struct {
+	ID         int32
+	Name       *string
+	URL        *string
+	Secret     *string
+	EventTypes *[]database.TransactionHistoryEventType
+	Enabled    *bool
+}
+
+type ListDeliveriesRequest struct {
+	WebhookID *int32
+	Statuses  []database.WebhookDeliveryStatus
+	Limit     int
+}
+
+// Event is the body of a delivery, signed as sent.
+type Event struct {
+	WebhookID           int32          `json:"webhook_id"`
+	Event               string         `json:"event"`
+	HistoryID           int64          `json:"history_id,omitempty"`
+	TransactionID       int64          `json:"transaction_id,omitempty"`
+	ActorType           string         `json:"actor_type,omitempty"`
+	ActorUserID         *int32         `json:"actor_user_id,omitempty"`
+	ActorRuleID         *int32         `json:"actor_rule_id,omitempty"`
+	ActorRuleRevisionID *int64         `json:"actor_rule_revision_id,omitempty"`
+	ActorExtra          *string        `json:"actor_extra,omitempty"`
+	Snapshot            map[string]any `json:"snapshot,omitempty"`
+	Diff                map[string]any `json:"diff,omitempty"`
+	OccurredAt          time.Time      `json:"occurred_at"`
+}